@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/pkg/errors"
 )
@@ -23,6 +25,10 @@ const (
 
 	connectorCertSecretKey = "ca.crt"
 	connectorKeySecretKey  = "ca.key"
+
+	connectorNextCertSecretKey     = "next-ca.crt"
+	connectorNextKeySecretKey      = "next-ca.key"
+	connectorPreviousCertSecretKey = "previous-ca.crt"
 )
 
 type certSetupHandler struct {
@@ -43,6 +49,9 @@ func (csh *certSetupHandler) SetupApplicationConnectorCertificate() error {
 		caKey, caCert, err := csh.validateProvidedCertAndKey()
 		if err == nil {
 			logrus.Infoln("Valid certificate and key provided. Skipping generation.")
+			if csh.options.caRotationEnabled {
+				return csh.introduceProvidedCertAndKey(caKey, caCert)
+			}
 			return csh.populateSecrets(caKey, caCert)
 		}
 
@@ -72,6 +81,40 @@ func (csh *certSetupHandler) SetupApplicationConnectorCertificate() error {
 	return csh.populateSecrets(keyBytes, certBytes)
 }
 
+// introduceProvidedCertAndKey stores the provided certificate and key as the next CA if other CA is already in use.
+// The Connector Service adds the next CA to the trusted certificates and replaces the active CA with it without downtime.
+func (csh *certSetupHandler) introduceProvidedCertAndKey(pemKey, pemCert []byte) error {
+	connectorSecretData, err := csh.secretRepository.Get(csh.options.connectorCertificateSecret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "Failed to get Connector Service secret")
+	}
+
+	if len(connectorSecretData[connectorCertSecretKey]) == 0 {
+		return csh.populateSecrets(pemKey, pemCert)
+	}
+
+	for _, key := range []string{connectorCertSecretKey, connectorNextCertSecretKey, connectorPreviousCertSecretKey} {
+		if bytes.Equal(bytes.TrimSpace(connectorSecretData[key]), bytes.TrimSpace(pemCert)) {
+			logrus.Infof("Provided certificate is already stored in %s key of the Connector Service secret.", key)
+			return nil
+		}
+	}
+
+	logrus.Info("Provided certificate differs from the one in use, storing it as the next CA")
+
+	nextCASecretData := map[string][]byte{
+		connectorNextCertSecretKey: pemCert,
+		connectorNextKeySecretKey:  pemKey,
+	}
+
+	err = csh.secretRepository.Upsert(csh.options.connectorCertificateSecret, nextCASecretData)
+	if err != nil {
+		return errors.Wrap(err, "Failed to update Connector Service secret")
+	}
+
+	return nil
+}
+
 func (csh *certSetupHandler) certificatesExists() (bool, error) {
 	connectorCertsProvided, err := csh.secretRepository.ValuesProvided(csh.options.connectorCertificateSecret, []string{connectorKeySecretKey, connectorCertSecretKey})
 	if err != nil {
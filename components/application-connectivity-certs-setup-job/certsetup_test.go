@@ -239,6 +239,140 @@ func TestCertSetupHandler_SetupApplicationConnectorCertificate(t *testing.T) {
 
 }
 
+func TestCertSetupHandler_SetupApplicationConnectorCertificate_CARotation(t *testing.T) {
+
+	existingCaSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      caSecretNamespacedName.Name,
+			Namespace: caSecretNamespacedName.Namespace,
+		},
+		Data: map[string][]byte{
+			"cacert": []byte("cacert"),
+		},
+	}
+
+	t.Run("should store provided certificate as next CA when other CA is in use", func(t *testing.T) {
+		// given
+		existingConnectorSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      connectorSecretNamespacedName.Name,
+				Namespace: connectorSecretNamespacedName.Namespace,
+			},
+			Data: map[string][]byte{
+				"ca.crt": []byte("cacert"),
+				"ca.key": []byte("key"),
+			},
+		}
+
+		fakeClientSet := fake.NewSimpleClientset(existingCaSecret.DeepCopy(), existingConnectorSecret).CoreV1()
+		secretRepository := NewSecretRepository(func(namespace string) Manager {
+			return fakeClientSet.Secrets(namespace)
+		})
+
+		options := &options{
+			connectorCertificateSecret: connectorSecretNamespacedName,
+			caCertificateSecret:        caSecretNamespacedName,
+			caCertificate:              base64Encode(certificatePem),
+			caKey:                      base64Encode(privateKeyPem),
+			caRotationEnabled:          true,
+		}
+
+		certSetupHandler := NewCertificateSetupHandler(options, secretRepository)
+
+		// when
+		err := certSetupHandler.SetupApplicationConnectorCertificate()
+
+		// then
+		require.NoError(t, err)
+
+		caSecret, err := secretRepository.Get(caSecretNamespacedName)
+		require.NoError(t, err)
+		assert.EqualValues(t, existingCaSecret.Data, caSecret)
+
+		connectorSecret, err := secretRepository.Get(connectorSecretNamespacedName)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("cacert"), connectorSecret["ca.crt"])
+		assert.Equal(t, []byte("key"), connectorSecret["ca.key"])
+		assert.Equal(t, []byte(certificatePem), connectorSecret["next-ca.crt"])
+		assert.Equal(t, []byte(privateKeyPem), connectorSecret["next-ca.key"])
+	})
+
+	t.Run("should not modify secrets when provided certificate is in use", func(t *testing.T) {
+		// given
+		existingConnectorSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      connectorSecretNamespacedName.Name,
+				Namespace: connectorSecretNamespacedName.Namespace,
+			},
+			Data: map[string][]byte{
+				"ca.crt":          []byte("newcacert"),
+				"ca.key":          []byte("newkey"),
+				"previous-ca.crt": []byte(certificatePem),
+				"previous-ca.key": []byte(privateKeyPem),
+			},
+		}
+
+		fakeClientSet := fake.NewSimpleClientset(existingCaSecret.DeepCopy(), existingConnectorSecret).CoreV1()
+		secretRepository := NewSecretRepository(func(namespace string) Manager {
+			return fakeClientSet.Secrets(namespace)
+		})
+
+		options := &options{
+			connectorCertificateSecret: connectorSecretNamespacedName,
+			caCertificateSecret:        caSecretNamespacedName,
+			caCertificate:              base64Encode(certificatePem),
+			caKey:                      base64Encode(privateKeyPem),
+			caRotationEnabled:          true,
+		}
+
+		certSetupHandler := NewCertificateSetupHandler(options, secretRepository)
+
+		// when
+		err := certSetupHandler.SetupApplicationConnectorCertificate()
+
+		// then
+		require.NoError(t, err)
+
+		caSecret, err := secretRepository.Get(caSecretNamespacedName)
+		require.NoError(t, err)
+		assert.EqualValues(t, existingCaSecret.Data, caSecret)
+
+		connectorSecret, err := secretRepository.Get(connectorSecretNamespacedName)
+		require.NoError(t, err)
+		assert.EqualValues(t, existingConnectorSecret.Data, connectorSecret)
+	})
+
+	t.Run("should populate secrets with provided certificate when no CA is in use", func(t *testing.T) {
+		// given
+		secretRepository := fakeRepositoryWithEmptySecrets()
+
+		options := &options{
+			connectorCertificateSecret: connectorSecretNamespacedName,
+			caCertificateSecret:        caSecretNamespacedName,
+			caCertificate:              base64Encode(certificatePem),
+			caKey:                      base64Encode(privateKeyPem),
+			caRotationEnabled:          true,
+		}
+
+		certSetupHandler := NewCertificateSetupHandler(options, secretRepository)
+
+		// when
+		err := certSetupHandler.SetupApplicationConnectorCertificate()
+
+		// then
+		require.NoError(t, err)
+
+		caSecret, err := secretRepository.Get(caSecretNamespacedName)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(certificatePem), caSecret["cacert"])
+
+		connectorSecret, err := secretRepository.Get(connectorSecretNamespacedName)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(certificatePem), connectorSecret["ca.crt"])
+		assert.Empty(t, connectorSecret["next-ca.crt"])
+	})
+}
+
 func TestCertSetupHandler_SetupApplicationConnectorCertificate_GeneratingCertificates(t *testing.T) {
 	validityTime := time.Minute * 60
 
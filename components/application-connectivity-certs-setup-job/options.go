@@ -25,6 +25,8 @@ type options struct {
 	caKey         string
 
 	generatedValidityTime time.Duration
+
+	caRotationEnabled bool
 }
 
 func parseArgs() *options {
@@ -36,6 +38,8 @@ func parseArgs() *options {
 
 	generatedValidityTime := flag.String("generatedValidityTime", "", "Validity time of the generated certificate")
 
+	caRotationEnabled := flag.Bool("caRotationEnabled", false, "Determines whether the provided CA different from the one in use is rotated by the Connector Service")
+
 	flag.Parse()
 
 	validityTime, err := parseDuration(*generatedValidityTime)
@@ -49,15 +53,16 @@ func parseArgs() *options {
 		caCertificate:              *caCertificate,
 		caKey:                      *caKey,
 		generatedValidityTime:      validityTime,
+		caRotationEnabled:          *caRotationEnabled,
 	}
 }
 
 func (o *options) String() string {
 	return fmt.Sprintf("--connectorCertificateSecret=%s --caCertificateSecret=%s "+
-		"--generatedValidityTime=%s "+
+		"--generatedValidityTime=%s --caRotationEnabled=%t "+
 		"CA certificate provided: %t, CA key provided: %t",
 		o.connectorCertificateSecret, o.caCertificateSecret,
-		o.generatedValidityTime.String(),
+		o.generatedValidityTime.String(), o.caRotationEnabled,
 		o.caCertificate != "", o.caKey != "")
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/kyma-project/kyma/common/logging/tracing"
 	"github.com/kyma-project/kyma/components/application-connectivity-validator/internal/controller"
 	"github.com/kyma-project/kyma/components/application-connectivity-validator/internal/externalapi"
+	"github.com/kyma-project/kyma/components/application-connectivity-validator/internal/revocation"
 	"github.com/kyma-project/kyma/components/application-connectivity-validator/internal/validationproxy"
 	"github.com/patrickmn/go-cache"
 )
@@ -58,6 +59,13 @@ func main() {
 		time.Duration(options.cacheCleanupMinutes)*time.Minute,
 	)
 
+	var revocationChecker validationproxy.RevocationChecker
+	if options.revocationCRLURL != "" {
+		crlChecker := revocation.NewCRLChecker(options.revocationCRLURL, options.revocationCACertificatesPath, options.revocationRefreshPeriod, &http.Client{Timeout: 30 * time.Second}, log)
+		go crlChecker.Run()
+		revocationChecker = crlChecker
+	}
+
 	proxyHandler := validationproxy.NewProxyHandler(
 		options.group,
 		options.tenant,
@@ -69,6 +77,7 @@ func main() {
 		options.appRegistryPathPrefix,
 		options.appRegistryHost,
 		idCache,
+		revocationChecker,
 		log)
 
 	tracingMiddleware := tracing.NewTracingMiddleware(proxyHandler.ProxyAppConnectorRequests)
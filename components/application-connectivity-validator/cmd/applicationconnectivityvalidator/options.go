@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"time"
@@ -9,23 +10,26 @@ import (
 )
 
 type args struct {
-	proxyPort                int
-	externalAPIPort          int
-	tenant                   string
-	group                    string
-	eventingPathPrefixV1     string
-	eventingPathPrefixV2     string
-	eventingPublisherHost    string
-	eventingPathPrefixEvents string
-	eventingDestinationPath  string
-	appRegistryPathPrefix    string
-	appRegistryHost          string
-	appName                  string
-	cacheExpirationMinutes   int
-	cacheCleanupMinutes      int
-	kubeConfig               string
-	apiServerURL             string
-	syncPeriod               time.Duration
+	proxyPort                    int
+	externalAPIPort              int
+	tenant                       string
+	group                        string
+	eventingPathPrefixV1         string
+	eventingPathPrefixV2         string
+	eventingPublisherHost        string
+	eventingPathPrefixEvents     string
+	eventingDestinationPath      string
+	appRegistryPathPrefix        string
+	appRegistryHost              string
+	appName                      string
+	cacheExpirationMinutes       int
+	cacheCleanupMinutes          int
+	kubeConfig                   string
+	apiServerURL                 string
+	syncPeriod                   time.Duration
+	revocationCRLURL             string
+	revocationCACertificatesPath string
+	revocationRefreshPeriod      time.Duration
 }
 
 type config struct {
@@ -56,9 +60,16 @@ func parseOptions() (*options, error) {
 	kubeConfig := flag.String("kubeConfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	apiServerURL := flag.String("apiServerURL", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	syncPeriod := flag.Duration("syncPeriod", 120*time.Second, "Sync period in seconds how often controller should periodically reconcile Application resource.")
+	revocationCRLURL := flag.String("revocationCRLURL", "", "URL of the CRL published by the Connector Service. Client certificates revoked on the CRL are rejected. The check is disabled if not specified.")
+	revocationCACertificatesPath := flag.String("revocationCACertificatesPath", "", "Path to the PEM file, or to the directory of PEM files, with the CA certificates of the Connector Service. The CRL must be signed with one of them. Required if revocationCRLURL is specified.")
+	revocationRefreshPeriod := flag.Duration("revocationRefreshPeriod", time.Minute, "How often the CRL is downloaded from the Connector Service.")
 
 	flag.Parse()
 
+	if *revocationCRLURL != "" && *revocationCACertificatesPath == "" {
+		return nil, errors.New("revocationCACertificatesPath is required to verify the CRL downloaded from revocationCRLURL")
+	}
+
 	var c config
 	if err := envconfig.InitWithPrefix(&c, "APP"); err != nil {
 		return nil, err
@@ -66,23 +77,26 @@ func parseOptions() (*options, error) {
 
 	return &options{
 		args: args{
-			proxyPort:                *proxyPort,
-			externalAPIPort:          *externalAPIPort,
-			tenant:                   *tenant,
-			group:                    *group,
-			eventingPathPrefixV1:     *eventingPathPrefixV1,
-			eventingPathPrefixV2:     *eventingPathPrefixV2,
-			eventingPublisherHost:    *eventingPublisherHost,
-			eventingPathPrefixEvents: *eventingPathPrefixEvents,
-			eventingDestinationPath:  *eventingDestinationPath,
-			appRegistryPathPrefix:    *appRegistryPathPrefix,
-			appRegistryHost:          *appRegistryHost,
-			appName:                  *appName,
-			cacheExpirationMinutes:   *cacheExpirationMinutes,
-			cacheCleanupMinutes:      *cacheCleanupMinutes,
-			kubeConfig:               *kubeConfig,
-			apiServerURL:             *apiServerURL,
-			syncPeriod:               *syncPeriod,
+			proxyPort:                    *proxyPort,
+			externalAPIPort:              *externalAPIPort,
+			tenant:                       *tenant,
+			group:                        *group,
+			eventingPathPrefixV1:         *eventingPathPrefixV1,
+			eventingPathPrefixV2:         *eventingPathPrefixV2,
+			eventingPublisherHost:        *eventingPublisherHost,
+			eventingPathPrefixEvents:     *eventingPathPrefixEvents,
+			eventingDestinationPath:      *eventingDestinationPath,
+			appRegistryPathPrefix:        *appRegistryPathPrefix,
+			appRegistryHost:              *appRegistryHost,
+			appName:                      *appName,
+			cacheExpirationMinutes:       *cacheExpirationMinutes,
+			cacheCleanupMinutes:          *cacheCleanupMinutes,
+			kubeConfig:                   *kubeConfig,
+			apiServerURL:                 *apiServerURL,
+			syncPeriod:                   *syncPeriod,
+			revocationCRLURL:             *revocationCRLURL,
+			revocationCACertificatesPath: *revocationCACertificatesPath,
+			revocationRefreshPeriod:      *revocationRefreshPeriod,
 		},
 		config: c,
 	}, nil
@@ -95,6 +109,7 @@ func (o *options) String() string {
 		"--appRegistryPathPrefix=%s --appRegistryHost=%s --appName=%s "+
 		"--cacheExpirationMinutes=%d --cacheCleanupMinutes=%d "+
 		"--kubeConfig=%s --apiServerURL=%s --syncPeriod=%d "+
+		"--revocationCRLURL=%s --revocationCACertificatesPath=%s --revocationRefreshPeriod=%s "+
 		"APP_LOG_FORMAT=%s APP_LOG_LEVEL=%s",
 		o.proxyPort, o.externalAPIPort, o.tenant, o.group,
 		o.eventingPathPrefixV1, o.eventingPathPrefixV2, o.eventingPublisherHost,
@@ -102,5 +117,6 @@ func (o *options) String() string {
 		o.appRegistryPathPrefix, o.appRegistryHost, o.appName,
 		o.cacheExpirationMinutes, o.cacheCleanupMinutes,
 		o.kubeConfig, o.apiServerURL, o.syncPeriod,
+		o.revocationCRLURL, o.revocationCACertificatesPath, o.revocationRefreshPeriod,
 		o.LogFormat, o.LogLevel)
 }
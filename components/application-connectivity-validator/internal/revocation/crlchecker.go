@@ -0,0 +1,199 @@
+package revocation
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma/common/logging/logger"
+)
+
+// maxCRLSize limits the size of the downloaded CRL
+const maxCRLSize = 10 * 1024 * 1024
+
+// CRLChecker checks the certificates against the CRL published by the Connector Service
+type CRLChecker struct {
+	crlURL string
+	// caCertificatesPath is the PEM file with the CA certificates of the Connector Service, the CRL must be signed with one of them
+	caCertificatesPath string
+	refreshPeriod      time.Duration
+	httpClient         *http.Client
+	log                *logger.Logger
+
+	mutex          sync.RWMutex
+	revokedSerials map[string]bool
+	nextUpdate     time.Time
+}
+
+func NewCRLChecker(crlURL, caCertificatesPath string, refreshPeriod time.Duration, httpClient *http.Client, log *logger.Logger) *CRLChecker {
+	return &CRLChecker{
+		crlURL:             crlURL,
+		caCertificatesPath: caCertificatesPath,
+		refreshPeriod:      refreshPeriod,
+		httpClient:         httpClient,
+		log:                log,
+		revokedSerials:     map[string]bool{},
+	}
+}
+
+// IsRevoked returns true if the serial number is on the last successfully downloaded CRL
+func (c *CRLChecker) IsRevoked(serialNumber *big.Int) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.revokedSerials[serialNumber.String()]
+}
+
+// Run downloads the CRL periodically, the previously downloaded CRL is kept if the download fails
+func (c *CRLChecker) Run() {
+	for {
+		if err := c.Refresh(); err != nil {
+			c.log.WithContext().With("crlURL", c.crlURL).Errorf("Failed to refresh revoked certificates: %s", err)
+			c.warnIfStale()
+		}
+
+		time.Sleep(c.refreshPeriod)
+	}
+}
+
+// Refresh downloads the CRL, verifies it's signed with one of the CA certificates and replaces the revoked serial numbers
+func (c *CRLChecker) Refresh() error {
+	caCertificates, err := loadCertificates(c.caCertificatesPath)
+	if err != nil {
+		return fmt.Errorf("failed to load CA certificates: %s", err)
+	}
+
+	response, err := c.httpClient.Get(c.crlURL)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status: %s", response.Status)
+	}
+
+	rawCRL, err := ioutil.ReadAll(io.LimitReader(response.Body, maxCRLSize))
+	if err != nil {
+		return err
+	}
+
+	crl, err := x509.ParseCRL(rawCRL)
+	if err != nil {
+		return err
+	}
+
+	if !isSignedByAny(crl, caCertificates) {
+		return errors.New("CRL is not signed with any of the CA certificates")
+	}
+
+	revokedSerials := make(map[string]bool, len(crl.TBSCertList.RevokedCertificates))
+	for _, revokedCert := range crl.TBSCertList.RevokedCertificates {
+		revokedSerials[revokedCert.SerialNumber.String()] = true
+	}
+
+	c.mutex.Lock()
+	c.revokedSerials = revokedSerials
+	c.nextUpdate = crl.TBSCertList.NextUpdate
+	c.mutex.Unlock()
+
+	c.log.WithContext().With("crlURL", c.crlURL).Debugf("Refreshed revoked certificates, %d certificates revoked", len(revokedSerials))
+	c.warnIfStale()
+
+	return nil
+}
+
+// warnIfStale reports the CRL which should have been replaced by the newer one, the certificates revoked since then aren't rejected
+func (c *CRLChecker) warnIfStale() {
+	c.mutex.RLock()
+	nextUpdate := c.nextUpdate
+	c.mutex.RUnlock()
+
+	if !nextUpdate.IsZero() && time.Now().After(nextUpdate) {
+		c.log.WithContext().With("crlURL", c.crlURL).Warnf("Revoked certificates are stale, the CRL should have been updated at %s", nextUpdate.Format(time.RFC3339))
+	}
+}
+
+func isSignedByAny(crl *pkix.CertificateList, caCertificates []*x509.Certificate) bool {
+	for _, caCertificate := range caCertificates {
+		if caCertificate.CheckCRLSignature(crl) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadCertificates reads the PEM encoded certificates from the file or from all files in the directory, such as the mounted
+// secret, they are read on every refresh to follow the CA rotation
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	rawPEM, err := readFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(rawPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	return certificates, nil
+}
+
+// readFiles returns the content of the file or the concatenated content of the files in the directory, the hidden files
+// like the ones used by Kubernetes to update the mounted secrets are skipped
+func readFiles(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return ioutil.ReadFile(path)
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var content []byte
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+
+		fileContent, err := ioutil.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		content = append(content, fileContent...)
+		content = append(content, '\n')
+	}
+
+	return content, nil
+}
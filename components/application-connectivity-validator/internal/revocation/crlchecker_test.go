@@ -0,0 +1,239 @@
+package revocation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRLChecker(t *testing.T) {
+	log, err := logger.New(logger.TEXT, logger.ERROR)
+	require.NoError(t, err)
+
+	ca := newTestCA(t)
+	caPath := writeCertificates(t, ca)
+
+	t.Run("should return revoked certificates from CRL", func(t *testing.T) {
+		// given
+		crl := createCRL(t, ca, time.Now().Add(time.Hour), big.NewInt(42))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, caPath, time.Minute, server.Client(), log)
+
+		// when
+		err := checker.Refresh()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, checker.IsRevoked(big.NewInt(42)))
+		assert.False(t, checker.IsRevoked(big.NewInt(43)))
+	})
+
+	t.Run("should keep revoked certificates when failed to download CRL", func(t *testing.T) {
+		// given
+		crl := createCRL(t, ca, time.Now().Add(time.Hour), big.NewInt(42))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+
+		checker := NewCRLChecker(server.URL, caPath, time.Minute, server.Client(), log)
+		require.NoError(t, checker.Refresh())
+
+		// when
+		server.Close()
+		err := checker.Refresh()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, checker.IsRevoked(big.NewInt(42)))
+	})
+
+	t.Run("should reject CRL not signed with CA", func(t *testing.T) {
+		// given
+		crl := createCRL(t, ca, time.Now().Add(time.Hour), big.NewInt(42))
+		forgedCRL := createCRL(t, newTestCA(t), time.Now().Add(time.Hour), big.NewInt(43))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, caPath, time.Minute, server.Client(), log)
+		require.NoError(t, checker.Refresh())
+
+		// when
+		crl = forgedCRL
+		err := checker.Refresh()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, checker.IsRevoked(big.NewInt(42)))
+		assert.False(t, checker.IsRevoked(big.NewInt(43)))
+	})
+
+	t.Run("should accept CRL signed with any CA from directory", func(t *testing.T) {
+		// given
+		previousCA := newTestCA(t)
+		dir := t.TempDir()
+		require.NoError(t, os.Rename(writeCertificates(t, ca), filepath.Join(dir, "ca.crt")))
+		require.NoError(t, os.Rename(writeCertificates(t, previousCA), filepath.Join(dir, "previous-ca.crt")))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0700))
+
+		crl := createCRL(t, previousCA, time.Now().Add(time.Hour), big.NewInt(42))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, dir, time.Minute, server.Client(), log)
+
+		// when
+		err := checker.Refresh()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, checker.IsRevoked(big.NewInt(42)))
+	})
+
+	t.Run("should use stale CRL", func(t *testing.T) {
+		// given
+		crl := createCRL(t, ca, time.Now().Add(-time.Minute), big.NewInt(42))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, caPath, time.Minute, server.Client(), log)
+
+		// when
+		err := checker.Refresh()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, checker.IsRevoked(big.NewInt(42)))
+	})
+
+	t.Run("should return error when CA certificates are missing", func(t *testing.T) {
+		// given
+		crl := createCRL(t, ca, time.Now().Add(time.Hour), big.NewInt(42))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, filepath.Join(t.TempDir(), "missing.crt"), time.Minute, server.Client(), log)
+
+		// when
+		err := checker.Refresh()
+
+		// then
+		require.Error(t, err)
+		assert.False(t, checker.IsRevoked(big.NewInt(42)))
+	})
+
+	t.Run("should return error when CRL is not available", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, caPath, time.Minute, server.Client(), log)
+
+		// when
+		err := checker.Refresh()
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should return error when response is not CRL", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not a CRL"))
+		}))
+		defer server.Close()
+
+		checker := NewCRLChecker(server.URL, caPath, time.Minute, server.Client(), log)
+
+		// when
+		err := checker.Refresh()
+
+		// then
+		require.Error(t, err)
+		assert.False(t, checker.IsRevoked(big.NewInt(42)))
+	})
+}
+
+type testCA struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	rawCA, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, key.Public(), key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(rawCA)
+	require.NoError(t, err)
+
+	return testCA{certificate: ca, key: key}
+}
+
+// writeCertificates writes the PEM encoded CA certificates to the file and returns its path
+func writeCertificates(t *testing.T, cas ...testCA) string {
+	var rawPEM []byte
+	for _, ca := range cas {
+		rawPEM = append(rawPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certificate.Raw})...)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, ioutil.WriteFile(path, rawPEM, 0600))
+	return path
+}
+
+func createCRL(t *testing.T, ca testCA, nextUpdate time.Time, serialNumbers ...*big.Int) []byte {
+	var revokedCerts []pkix.RevokedCertificate
+	for _, serialNumber := range serialNumbers {
+		revokedCerts = append(revokedCerts, pkix.RevokedCertificate{SerialNumber: serialNumber, RevocationTime: time.Now()})
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificates: revokedCerts,
+		Number:              big.NewInt(1),
+		ThisUpdate:          nextUpdate.Add(-time.Hour),
+		NextUpdate:          nextUpdate,
+	}, ca.certificate, ca.key)
+	require.NoError(t, err)
+
+	return crl
+}
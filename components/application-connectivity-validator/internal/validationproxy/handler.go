@@ -2,9 +2,13 @@ package validationproxy
 
 import (
 	"context"
+	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -38,6 +42,12 @@ type Cache interface {
 	Set(k string, x interface{}, d time.Duration)
 }
 
+//go:generate mockery --name=RevocationChecker
+type RevocationChecker interface {
+	// IsRevoked returns true if the certificate with the serial number is revoked
+	IsRevoked(serialNumber *big.Int) bool
+}
+
 type proxyHandler struct {
 	group                    string
 	tenant                   string
@@ -54,7 +64,8 @@ type proxyHandler struct {
 
 	log *logger.Logger
 
-	cache Cache
+	cache             Cache
+	revocationChecker RevocationChecker
 }
 
 func NewProxyHandler(
@@ -68,6 +79,7 @@ func NewProxyHandler(
 	appRegistryPathPrefix string,
 	appRegistryHost string,
 	cache Cache,
+	revocationChecker RevocationChecker,
 	log *logger.Logger) *proxyHandler {
 
 	return &proxyHandler{
@@ -84,8 +96,9 @@ func NewProxyHandler(
 		cloudEventsProxy:  createReverseProxy(log, eventingPublisherHost, withRewriteBaseURL(eventingDestinationPath), withEmptyRequestHost, withEmptyXFwdClientCert, withHTTPScheme),
 		appRegistryProxy:  createReverseProxy(log, appRegistryHost, withEmptyRequestHost, withHTTPScheme),
 
-		cache: cache,
-		log:   log,
+		cache:             cache,
+		revocationChecker: revocationChecker,
+		log:               log,
 	}
 }
 
@@ -117,6 +130,11 @@ func (ph *proxyHandler) ProxyAppConnectorRequests(w http.ResponseWriter, r *http
 		return
 	}
 
+	if ph.isCertificateRevoked(certInfoData) {
+		httptools.RespondWithError(ph.log.WithTracing(r.Context()).With("handler", handlerName).With("applicationName", applicationName), w, apperrors.Forbidden("client certificate is revoked"))
+		return
+	}
+
 	reverseProxy, err := ph.mapRequestToProxy(r.URL.Path)
 	if err != nil {
 		httptools.RespondWithError(ph.log.WithTracing(r.Context()).With("handler", handlerName).With("applicationName", applicationName), w, err)
@@ -126,6 +144,21 @@ func (ph *proxyHandler) ProxyAppConnectorRequests(w http.ResponseWriter, r *http
 	reverseProxy.ServeHTTP(w, r)
 }
 
+// isCertificateRevoked checks certificates which Istio passes in the Cert elements of the header, if the revocation check is enabled
+func (ph *proxyHandler) isCertificateRevoked(certInfoData string) bool {
+	if ph.revocationChecker == nil {
+		return false
+	}
+
+	for _, certificate := range extractCertificates(certInfoData) {
+		if ph.revocationChecker.IsRevoked(certificate.SerialNumber) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ph *proxyHandler) getCompassMetadataClientIDs(applicationName string) ([]string, apperrors.AppError) {
 	applicationClientIDs, found := ph.getClientIDsFromCache(applicationName)
 	if !found {
@@ -241,6 +274,34 @@ func extractSubjects(certInfoData string) []string {
 	return subjects
 }
 
+func extractCertificates(certInfoData string) []*x509.Certificate {
+	var certificates []*x509.Certificate
+
+	certRegex := regexp.MustCompile(`Cert="(.*?)"`)
+	certMatches := certRegex.FindAllStringSubmatch(certInfoData, -1)
+
+	for _, certMatch := range certMatches {
+		pemCert, err := url.PathUnescape(get(certMatch, 1))
+		if err != nil {
+			continue
+		}
+
+		block, _ := pem.Decode([]byte(pemCert))
+		if block == nil {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates
+}
+
 func get(array []string, index int) string {
 	if len(array) > index {
 		return array[index]
@@ -2,10 +2,18 @@ package validationproxy
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -19,6 +27,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kyma-project/kyma/components/application-connectivity-validator/internal/validationproxy/mocks"
 	appconnv1alpha1 "github.com/kyma-project/kyma/components/application-operator/pkg/apis/applicationconnector/v1alpha1"
 )
 
@@ -249,6 +258,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 				appRegistryPathPrefix,
 				appRegistryHost,
 				idCache,
+				nil,
 				log)
 
 			t.Run("should proxy eventing V1 request when "+testCase.caseDescription, func(t *testing.T) {
@@ -376,6 +386,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 				appRegistryPathPrefix,
 				appRegistryHost,
 				idCache,
+				nil,
 				log)
 
 			t.Run("should proxy application registry request when "+testCase.caseDescription, func(t *testing.T) {
@@ -428,6 +439,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 				appRegistryPathPrefix,
 				appRegistryHost,
 				idCache,
+				nil,
 				log)
 
 			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/%s/v1/metadata/services", testCase.application.Name), nil)
@@ -470,6 +482,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 			appRegistryPathPrefix,
 			appRegistryHost,
 			idCache,
+			nil,
 			log)
 
 		req, err := http.NewRequest(http.MethodGet, "/path", nil)
@@ -484,6 +497,60 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 	})
 
+	t.Run("should check client certificate revocation", func(t *testing.T) {
+		appRegistryHandler := mux.NewRouter()
+		appRegistryHandler.PathPrefix("/{application}/v1/metadata/services").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		appRegistryServer := httptest.NewServer(appRegistryHandler)
+		appRegistryHost := strings.TrimPrefix(appRegistryServer.URL, "http://")
+
+		certificate := createCertificate(t, big.NewInt(42))
+		certInfoHeader := `Hash=f4cf22fb633d4df500e371daf703d4b4d14a0ea9d69cd631f95f9e6ba840f8ad;` +
+			`Cert="` + url.PathEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}))) + `";` +
+			`Subject="CN=test-application,OU=OrgUnit,O=Organization,L=Waldorf,ST=Waldorf,C=DE";URI=`
+
+		for _, revoked := range []bool{true, false} {
+			// given
+			idCache := cache.New(time.Minute, time.Minute)
+			idCache.Set(applicationName, []string{}, cache.NoExpiration)
+
+			revocationChecker := &mocks.RevocationChecker{}
+			revocationChecker.On("IsRevoked", certificate.SerialNumber).Return(revoked)
+
+			proxyHandler := NewProxyHandler(
+				"",
+				"",
+				eventingPathPrefixV1,
+				eventingPathPrefixV2,
+				"",
+				eventingPathPrefixEvents,
+				eventingDestinationPath,
+				appRegistryPathPrefix,
+				appRegistryHost,
+				idCache,
+				revocationChecker,
+				log)
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/%s/v1/metadata/services", applicationName), nil)
+			require.NoError(t, err)
+			req.Header.Set(CertificateInfoHeader, certInfoHeader)
+			req = mux.SetURLVars(req, map[string]string{"application": applicationName})
+			recorder := httptest.NewRecorder()
+
+			// when
+			proxyHandler.ProxyAppConnectorRequests(recorder, req)
+
+			// then
+			if revoked {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			} else {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			}
+			revocationChecker.AssertExpectations(t)
+		}
+	})
+
 	t.Run("should return 404 when path is invalid", func(t *testing.T) {
 		eventingPublisherHandler := mux.NewRouter()
 		eventingPublisherServer := httptest.NewServer(eventingPublisherHandler)
@@ -514,6 +581,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 			appRegistryPathPrefix,
 			appRegistryHost,
 			idCache,
+			nil,
 			log)
 
 		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/%s/v1/bad/path", applicationMetaName), nil)
@@ -565,6 +633,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 					appRegistryPathPrefix,
 					appRegistryHost,
 					idCache,
+					nil,
 					log)
 				eventTitle := "my-event-1"
 
@@ -616,6 +685,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 					appRegistryPathPrefix,
 					appRegistryHost,
 					idCache,
+					nil,
 					log)
 
 				eventPublisherProxyHandler.PathPrefix("/publish").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -663,6 +733,7 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 					appRegistryPathPrefix,
 					appRegistryHost,
 					idCache,
+					nil,
 					log)
 
 				eventPublisherProxyHandler.Path("/publish").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -699,3 +770,22 @@ func TestProxyHandler_ProxyAppConnectorRequests(t *testing.T) {
 		}
 	})
 }
+
+func createCertificate(t *testing.T, serialNumber *big.Int) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: applicationName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	rawCrt, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	crt, err := x509.ParseCertificate(rawCrt)
+	require.NoError(t, err)
+	return crt
+}
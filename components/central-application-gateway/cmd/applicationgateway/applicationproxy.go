@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/kyma-project/kyma/components/application-operator/pkg/client/clientset/versioned"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf"
 	csrfClient "github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf/client"
 	csrfStrategy "github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf/strategy"
@@ -22,9 +24,15 @@ import (
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/proxy"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/tokencache"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httptools"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/sharedcache"
 	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -65,8 +73,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	internalHandler := newInternalHandler(serviceDefinitionService, options)
-	internalHandlerForCompass := newInternalHandlerForCompass(serviceDefinitionService, options)
+	tokenCache, err := newTokenCache(coreClientset, options)
+	if err != nil {
+		log.Errorf("Unable to create token cache: '%s'", err.Error())
+		os.Exit(1)
+	}
+
+	internalHandler := newInternalHandler(serviceDefinitionService, tokenCache, options)
+	internalHandlerForCompass := newInternalHandlerForCompass(serviceDefinitionService, tokenCache, options)
 	externalHandler := externalapi.NewHandler()
 
 	if options.requestLogging {
@@ -82,18 +96,27 @@ func main() {
 		WriteTimeout: time.Duration(options.requestTimeout) * time.Second,
 	}
 
+	// the proxy removes the ReadTimeout and WriteTimeout of the proxy servers only for the streamed requests, which can
+	// last indefinitely
 	internalSrv := &http.Server{
 		Addr:         ":" + strconv.Itoa(options.proxyPort),
-		Handler:      internalHandler,
+		Handler:      h2c.NewHandler(internalHandler, &http2.Server{}),
 		ReadTimeout:  time.Duration(options.requestTimeout) * time.Second,
 		WriteTimeout: time.Duration(options.requestTimeout) * time.Second,
+		ConnContext:  proxy.ConnContext,
+	}
+
+	metricsSrv := &http.Server{
+		Addr:    ":" + strconv.Itoa(options.metricsPort),
+		Handler: promhttp.Handler(),
 	}
 
 	internalSrvCompass := &http.Server{
 		Addr:         ":" + strconv.Itoa(options.proxyPortCompass),
-		Handler:      internalHandlerForCompass,
+		Handler:      h2c.NewHandler(internalHandlerForCompass, &http2.Server{}),
 		ReadTimeout:  time.Duration(options.requestTimeout) * time.Second,
 		WriteTimeout: time.Duration(options.requestTimeout) * time.Second,
+		ConnContext:  proxy.ConnContext,
 	}
 
 	var g run.Group
@@ -101,6 +124,7 @@ func main() {
 	addHttpServerToRunGroup("external-api", &g, externalSrv)
 	addHttpServerToRunGroup("proxy-kyma-os", &g, internalSrv)
 	addHttpServerToRunGroup("proxy-kyma-mps", &g, internalSrvCompass)
+	addHttpServerToRunGroup("metrics", &g, metricsSrv)
 	addInterruptSignalToRunGroup(&g)
 
 	err = g.Run()
@@ -146,17 +170,17 @@ func addInterruptSignalToRunGroup(g *run.Group) {
 	})
 }
 
-func newInternalHandler(serviceDefinitionService metadata.ServiceDefinitionService, options *options) http.Handler {
-	authStrategyFactory := newAuthenticationStrategyFactory(options.proxyTimeout)
-	csrfCl := newCSRFClient(options.proxyTimeout)
+func newInternalHandler(serviceDefinitionService metadata.ServiceDefinitionService, tokenCache *sharedcache.Cache, options *options) http.Handler {
+	authStrategyFactory := newAuthenticationStrategyFactory(options.proxyTimeout, tokenCache)
+	csrfCl := newCSRFClient(options.proxyTimeout, tokenCache)
 	csrfTokenStrategyFactory := csrfStrategy.NewTokenStrategyFactory(csrfCl)
 
 	return proxy.New(serviceDefinitionService, authStrategyFactory, csrfTokenStrategyFactory, getProxyConfig(options))
 }
 
-func newInternalHandlerForCompass(serviceDefinitionService metadata.ServiceDefinitionService, options *options) http.Handler {
-	authStrategyFactory := newAuthenticationStrategyFactory(options.proxyTimeout)
-	csrfCl := newCSRFClient(options.proxyTimeout)
+func newInternalHandlerForCompass(serviceDefinitionService metadata.ServiceDefinitionService, tokenCache *sharedcache.Cache, options *options) http.Handler {
+	authStrategyFactory := newAuthenticationStrategyFactory(options.proxyTimeout, tokenCache)
+	csrfCl := newCSRFClient(options.proxyTimeout, tokenCache)
 	csrfTokenStrategyFactory := csrfStrategy.NewTokenStrategyFactory(csrfCl)
 
 	return proxy.NewForCompass(serviceDefinitionService, authStrategyFactory, csrfTokenStrategyFactory, getProxyConfig(options))
@@ -167,15 +191,67 @@ func getProxyConfig(options *options) proxy.Config {
 		SkipVerify:    options.skipVerify,
 		ProxyTimeout:  options.proxyTimeout,
 		ProxyCacheTTL: options.proxyCacheTTL,
+		CircuitBreaker: circuitbreaker.Config{
+			ConsecutiveFailures: options.circuitBreakerConsecutiveFailures,
+			ErrorRate:           options.circuitBreakerErrorRate,
+			MinRequests:         options.circuitBreakerMinRequests,
+			Window:              time.Duration(options.circuitBreakerWindow) * time.Second,
+			OpenDuration:        time.Duration(options.circuitBreakerOpenDuration) * time.Second,
+			HalfOpenRequests:    options.circuitBreakerHalfOpenRequests,
+		},
+		Retry: proxy.RetryConfig{
+			MaxRetries:       options.proxyMaxRetries,
+			Backoff:          time.Duration(options.proxyRetryBackoff) * time.Millisecond,
+			MaxBackoff:       time.Duration(options.proxyRetryMaxBackoff) * time.Millisecond,
+			BudgetRatio:      options.proxyRetryBudgetRatio,
+			BudgetMinRetries: options.proxyRetryBudgetMinRetries,
+		},
+		RequestBodySpoolThreshold: options.requestBodySpoolThreshold,
+		RequestBodySpoolDir:       options.requestBodySpoolDir,
+		RequestBodySpoolMaxSize:   options.requestBodySpoolMaxSize,
 	}
 }
 
-func newAuthenticationStrategyFactory(oauthClientTimeout int) authorization.StrategyFactory {
+func newAuthenticationStrategyFactory(oauthClientTimeout int, tokenCache *sharedcache.Cache) authorization.StrategyFactory {
 	return authorization.NewStrategyFactory(authorization.FactoryConfiguration{
 		OAuthClientTimeout: oauthClientTimeout,
+		TokenCache:         tokencache.NewSharedTokenCache(tokenCache),
 	})
 }
 
+// newTokenCache creates the cache of the OAuth and CSRF tokens shared by the handlers and, with the secret backend,
+// by the replicas
+func newTokenCache(coreClientset kubernetes.Interface, options *options) (*sharedcache.Cache, error) {
+	var backend sharedcache.Backend
+	switch options.tokenCacheBackend {
+	case tokenCacheBackendMemory:
+		backend = sharedcache.NewMemoryBackend()
+	case tokenCacheBackendSecret:
+		// the replicas are told apart in the leases by their Pod names
+		identity, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		backend, err = sharedcache.NewSecretBackend(
+			coreClientset.CoreV1().Secrets(options.tokenCacheSecretNamespace),
+			coreClientset.CoordinationV1().Leases(options.tokenCacheSecretNamespace),
+			options.tokenCacheSecretPrefix,
+			identity,
+			wait.NeverStop,
+		)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown token cache backend '%s'", options.tokenCacheBackend)
+	}
+
+	return sharedcache.New(backend, sharedcache.Config{
+		RefreshBefore: time.Duration(options.tokenRefreshBefore) * time.Second,
+		LockTimeout:   time.Duration(options.proxyTimeout) * time.Second,
+	}), nil
+}
+
 func newServiceDefinitionService(k8sConfig *restclient.Config, coreClientset kubernetes.Interface, namespace string) (metadata.ServiceDefinitionService, error) {
 	applicationServiceRepository, apperror := newApplicationRepository(k8sConfig)
 	if apperror != nil {
@@ -206,7 +282,7 @@ func newSecretsRepository(coreClientset kubernetes.Interface, namespace string)
 	return secrets.NewRepository(sei)
 }
 
-func newCSRFClient(timeout int) csrf.Client {
-	cache := csrfClient.NewTokenCache()
+func newCSRFClient(timeout int, tokenCache *sharedcache.Cache) csrf.Client {
+	cache := csrfClient.NewSharedTokenCache(tokenCache)
 	return csrfClient.New(timeout, cache)
 }
@@ -5,8 +5,14 @@ import (
 	"fmt"
 )
 
+const (
+	tokenCacheBackendMemory = "memory"
+	tokenCacheBackendSecret = "secret"
+)
+
 type options struct {
 	externalAPIPort             int
+	metricsPort                 int
 	proxyPort                   int
 	proxyPortCompass            int
 	applicationSecretsNamespace string
@@ -17,10 +23,41 @@ type options struct {
 	proxyCacheTTL               int
 	kubeConfig                  string
 	apiServerURL                string
+	requestBodySpoolThreshold   int64
+	requestBodySpoolDir         string
+	requestBodySpoolMaxSize     int64
+	tokenCacheOptions
+	circuitBreakerOptions
+	retryOptions
+}
+
+type tokenCacheOptions struct {
+	tokenCacheBackend         string
+	tokenCacheSecretPrefix    string
+	tokenCacheSecretNamespace string
+	tokenRefreshBefore        int
+}
+
+type circuitBreakerOptions struct {
+	circuitBreakerConsecutiveFailures int
+	circuitBreakerErrorRate           float64
+	circuitBreakerMinRequests         int
+	circuitBreakerWindow              int
+	circuitBreakerOpenDuration        int
+	circuitBreakerHalfOpenRequests    int
+}
+
+type retryOptions struct {
+	proxyMaxRetries            int
+	proxyRetryBackoff          int
+	proxyRetryMaxBackoff       int
+	proxyRetryBudgetRatio      float64
+	proxyRetryBudgetMinRetries int
 }
 
 func parseArgs() *options {
 	externalAPIPort := flag.Int("externalAPIPort", 8081, "External API port.")
+	metricsPort := flag.Int("metricsPort", 9090, "Port exposing the Prometheus metrics.")
 	proxyPort := flag.Int("proxyPort", 8080, "Proxy port for Kyma OS or MPS bundles with a single API definition")
 	proxyPortCompass := flag.Int("proxyPortCompass", 8082, "Proxy port for Kyma MPS.")
 	applicationSecretsNamespace := flag.String("applicationSecretsNamespace", "kyma-integration", "Namespace where Application secrets used by the Application Gateway exist")
@@ -31,11 +68,30 @@ func parseArgs() *options {
 	proxyCacheTTL := flag.Int("proxyCacheTTL", 120, "TTL, in seconds, for proxy cache of Remote API information")
 	kubeConfig := flag.String("kubeConfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	apiServerURL := flag.String("apiServerURL", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	requestBodySpoolThreshold := flag.Int64("requestBodySpoolThreshold", 1048576, "Size, in bytes, above which the buffered request bodies are stored on disk instead of the memory, 0 keeps all bodies in the memory.")
+	requestBodySpoolDir := flag.String("requestBodySpoolDir", "", "Directory for the request bodies stored on disk, the default directory for temporary files is used if empty.")
+	requestBodySpoolMaxSize := flag.Int64("requestBodySpoolMaxSize", 1073741824, "Total size, in bytes, of the request bodies stored on disk at the same time, the requests exceeding it are rejected, 0 doesn't limit the size.")
+	tokenCacheBackend := flag.String("tokenCacheBackend", tokenCacheBackendMemory, "Backend of the OAuth and CSRF token cache, either memory or secret shared by the replicas.")
+	tokenCacheSecretPrefix := flag.String("tokenCacheSecretPrefix", "central-application-gateway-token-cache", "Prefix of the names of the secrets storing the tokens shared by the replicas.")
+	tokenCacheSecretNamespace := flag.String("tokenCacheSecretNamespace", "central-application-gateway-tokens", "Namespace of the secrets storing the tokens shared by the replicas.")
+	tokenRefreshBefore := flag.Int("tokenRefreshBefore", 30, "Time, in seconds, before the token expiration in which the token is refreshed, 0 disables the early refresh.")
+	circuitBreakerConsecutiveFailures := flag.Int("circuitBreakerConsecutiveFailures", 0, "Number of consecutive failed calls which open the circuit breaker of the API, 0 disables the threshold.")
+	circuitBreakerErrorRate := flag.Float64("circuitBreakerErrorRate", 0, "Ratio of failed calls in the window which opens the circuit breaker of the API, 0 disables the threshold.")
+	circuitBreakerMinRequests := flag.Int("circuitBreakerMinRequests", 20, "Minimal number of calls in the window needed to evaluate the error rate.")
+	circuitBreakerWindow := flag.Int("circuitBreakerWindow", 60, "Window, in seconds, in which the calls are counted for the error rate.")
+	circuitBreakerOpenDuration := flag.Int("circuitBreakerOpenDuration", 30, "Time, in seconds, for which the open circuit breaker rejects calls before probing the API.")
+	circuitBreakerHalfOpenRequests := flag.Int("circuitBreakerHalfOpenRequests", 1, "Number of successful probing calls which close the circuit breaker.")
+	proxyMaxRetries := flag.Int("proxyMaxRetries", 0, "Number of retries of the failed idempotent calls, 0 disables the retries.")
+	proxyRetryBackoff := flag.Int("proxyRetryBackoff", 100, "Base of the jittered exponential backoff between the retries, in milliseconds.")
+	proxyRetryMaxBackoff := flag.Int("proxyRetryMaxBackoff", 2000, "Maximal backoff between the retries, in milliseconds.")
+	proxyRetryBudgetRatio := flag.Float64("proxyRetryBudgetRatio", 0.2, "Ratio of retries to calls allowed per API.")
+	proxyRetryBudgetMinRetries := flag.Int("proxyRetryBudgetMinRetries", 3, "Number of retries per API allowed in 10 seconds regardless of the budget ratio.")
 
 	flag.Parse()
 
 	return &options{
 		externalAPIPort:             *externalAPIPort,
+		metricsPort:                 *metricsPort,
 		proxyPort:                   *proxyPort,
 		proxyPortCompass:            *proxyPortCompass,
 		applicationSecretsNamespace: *applicationSecretsNamespace,
@@ -46,12 +102,44 @@ func parseArgs() *options {
 		proxyCacheTTL:               *proxyCacheTTL,
 		kubeConfig:                  *kubeConfig,
 		apiServerURL:                *apiServerURL,
+		requestBodySpoolThreshold:   *requestBodySpoolThreshold,
+		requestBodySpoolDir:         *requestBodySpoolDir,
+		requestBodySpoolMaxSize:     *requestBodySpoolMaxSize,
+		tokenCacheOptions: tokenCacheOptions{
+			tokenCacheBackend:         *tokenCacheBackend,
+			tokenCacheSecretPrefix:    *tokenCacheSecretPrefix,
+			tokenCacheSecretNamespace: *tokenCacheSecretNamespace,
+			tokenRefreshBefore:        *tokenRefreshBefore,
+		},
+		circuitBreakerOptions: circuitBreakerOptions{
+			circuitBreakerConsecutiveFailures: *circuitBreakerConsecutiveFailures,
+			circuitBreakerErrorRate:           *circuitBreakerErrorRate,
+			circuitBreakerMinRequests:         *circuitBreakerMinRequests,
+			circuitBreakerWindow:              *circuitBreakerWindow,
+			circuitBreakerOpenDuration:        *circuitBreakerOpenDuration,
+			circuitBreakerHalfOpenRequests:    *circuitBreakerHalfOpenRequests,
+		},
+		retryOptions: retryOptions{
+			proxyMaxRetries:            *proxyMaxRetries,
+			proxyRetryBackoff:          *proxyRetryBackoff,
+			proxyRetryMaxBackoff:       *proxyRetryMaxBackoff,
+			proxyRetryBudgetRatio:      *proxyRetryBudgetRatio,
+			proxyRetryBudgetMinRetries: *proxyRetryBudgetMinRetries,
+		},
 	}
 }
 
 func (o *options) String() string {
-	return fmt.Sprintf("--externalAPIPort=%d --proxyPort=%d --proxyPortCompass=%d --applicationSecretsNamespace=%s --requestTimeout=%d --skipVerify=%v --proxyTimeout=%d"+
-		" --requestLogging=%t --proxyCacheTTL=%d --kubeConfig=%s --apiServerURL=%s",
-		o.externalAPIPort, o.proxyPort, o.proxyPortCompass, o.applicationSecretsNamespace, o.requestTimeout, o.skipVerify, o.proxyTimeout,
-		o.requestLogging, o.proxyCacheTTL, o.kubeConfig, o.apiServerURL)
+	return fmt.Sprintf("--externalAPIPort=%d --metricsPort=%d --proxyPort=%d --proxyPortCompass=%d --applicationSecretsNamespace=%s --requestTimeout=%d --skipVerify=%v --proxyTimeout=%d"+
+		" --requestLogging=%t --proxyCacheTTL=%d --kubeConfig=%s --apiServerURL=%s --requestBodySpoolThreshold=%d --requestBodySpoolDir=%s --requestBodySpoolMaxSize=%d"+
+		" --tokenCacheBackend=%s --tokenCacheSecretPrefix=%s --tokenCacheSecretNamespace=%s --tokenRefreshBefore=%d"+
+		" --circuitBreakerConsecutiveFailures=%d --circuitBreakerErrorRate=%v --circuitBreakerMinRequests=%d --circuitBreakerWindow=%d"+
+		" --circuitBreakerOpenDuration=%d --circuitBreakerHalfOpenRequests=%d"+
+		" --proxyMaxRetries=%d --proxyRetryBackoff=%d --proxyRetryMaxBackoff=%d --proxyRetryBudgetRatio=%v --proxyRetryBudgetMinRetries=%d",
+		o.externalAPIPort, o.metricsPort, o.proxyPort, o.proxyPortCompass, o.applicationSecretsNamespace, o.requestTimeout, o.skipVerify, o.proxyTimeout,
+		o.requestLogging, o.proxyCacheTTL, o.kubeConfig, o.apiServerURL, o.requestBodySpoolThreshold, o.requestBodySpoolDir, o.requestBodySpoolMaxSize,
+		o.tokenCacheBackend, o.tokenCacheSecretPrefix, o.tokenCacheSecretNamespace, o.tokenRefreshBefore,
+		o.circuitBreakerConsecutiveFailures, o.circuitBreakerErrorRate, o.circuitBreakerMinRequests, o.circuitBreakerWindow,
+		o.circuitBreakerOpenDuration, o.circuitBreakerHalfOpenRequests,
+		o.proxyMaxRetries, o.proxyRetryBackoff, o.proxyRetryMaxBackoff, o.proxyRetryBudgetRatio, o.proxyRetryBudgetMinRetries)
 }
@@ -0,0 +1,227 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State of the circuit breaker
+type State int
+
+const (
+	// Closed lets all requests through
+	Closed State = iota
+	// HalfOpen lets a limited number of probing requests through
+	HalfOpen
+	// Open rejects all requests
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	case Open:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config holds the thresholds of the circuit breaker
+type Config struct {
+	// ConsecutiveFailures opens the circuit after that many failed requests in a row, 0 disables the threshold
+	ConsecutiveFailures int
+	// ErrorRate opens the circuit when the ratio of the failed requests in the window reaches it, 0 disables the threshold
+	ErrorRate float64
+	// MinRequests is the number of the requests in the window needed to evaluate ErrorRate
+	MinRequests int
+	// Window is the period in which the requests are counted for ErrorRate
+	Window time.Duration
+	// OpenDuration is the time the circuit stays open before the probing requests are let through
+	OpenDuration time.Duration
+	// HalfOpenRequests is the number of the successful probing requests needed to close the circuit
+	HalfOpenRequests int
+}
+
+// Enabled returns true if any of the thresholds is set
+func (c Config) Enabled() bool {
+	return c.ConsecutiveFailures > 0 || c.ErrorRate > 0
+}
+
+// OpenError is returned for the requests rejected by the open circuit
+type OpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open, retry after %s", e.RetryAfter)
+}
+
+// Outcome of the request let through by the circuit breaker
+type Outcome int
+
+const (
+	// Success is the request answered by the API
+	Success Outcome = iota
+	// Failure is the request which shows that the API is failing
+	Failure
+	// Ignored is the request which says nothing about the API, for example the one canceled by the caller. It only
+	// frees its probing slot.
+	Ignored
+)
+
+// DoneFunc records the outcome of the request let through by the circuit breaker
+type DoneFunc func(outcome Outcome)
+
+// Breaker is the circuit breaker of a single API. It's safe for concurrent use.
+type Breaker struct {
+	config        Config
+	onStateChange func(State)
+	now           func() time.Time
+
+	mutex sync.Mutex
+	state State
+	// generation changes with every state transition, so the outcomes of the requests started before are ignored
+	generation          uint64
+	openedAt            time.Time
+	windowStart         time.Time
+	requests            int
+	failures            int
+	consecutiveFailures int
+	probesInFlight      int
+	probeSuccesses      int
+}
+
+// New returns the closed circuit breaker, onStateChange is called on every state transition
+func New(config Config, onStateChange func(State)) *Breaker {
+	if config.HalfOpenRequests < 1 {
+		config.HalfOpenRequests = 1
+	}
+	return &Breaker{
+		config:        config,
+		onStateChange: onStateChange,
+		now:           time.Now,
+	}
+}
+
+// State returns the current state of the circuit breaker
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+// Check returns OpenError if the circuit is open and its open duration hasn't elapsed yet, it doesn't let the
+// request through
+func (b *Breaker) Check() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == Open {
+		if remaining := b.openedAt.Add(b.config.OpenDuration).Sub(b.now()); remaining > 0 {
+			return &OpenError{RetryAfter: remaining}
+		}
+	}
+	return nil
+}
+
+// Allow lets the request through or returns OpenError. The returned DoneFunc must be called with the outcome of the
+// request.
+func (b *Breaker) Allow() (DoneFunc, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.now()
+	if b.state == Open {
+		if remaining := b.openedAt.Add(b.config.OpenDuration).Sub(now); remaining > 0 {
+			return nil, &OpenError{RetryAfter: remaining}
+		}
+		b.setState(HalfOpen, now)
+	}
+	if b.state == HalfOpen {
+		if b.probesInFlight >= b.config.HalfOpenRequests {
+			return nil, &OpenError{RetryAfter: time.Second}
+		}
+		b.probesInFlight++
+	}
+
+	generation := b.generation
+	return func(outcome Outcome) {
+		b.record(generation, outcome)
+	}, nil
+}
+
+func (b *Breaker) record(generation uint64, outcome Outcome) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	now := b.now()
+	if outcome == Ignored {
+		if b.state == HalfOpen {
+			b.probesInFlight--
+		}
+		return
+	}
+	success := outcome == Success
+	switch b.state {
+	case Closed:
+		if now.Sub(b.windowStart) >= b.config.Window {
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+		b.requests++
+		if success {
+			b.consecutiveFailures = 0
+			return
+		}
+		b.failures++
+		b.consecutiveFailures++
+		if b.shouldTrip() {
+			b.setState(Open, now)
+		}
+	case HalfOpen:
+		b.probesInFlight--
+		if !success {
+			b.setState(Open, now)
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.config.HalfOpenRequests {
+			b.setState(Closed, now)
+		}
+	}
+}
+
+func (b *Breaker) shouldTrip() bool {
+	if b.config.ConsecutiveFailures > 0 && b.consecutiveFailures >= b.config.ConsecutiveFailures {
+		return true
+	}
+	return b.config.ErrorRate > 0 && b.requests >= b.config.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.config.ErrorRate
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	b.state = state
+	b.generation++
+	b.openedAt = now
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+	b.consecutiveFailures = 0
+	b.probesInFlight = 0
+	b.probeSuccesses = 0
+
+	if b.onStateChange != nil {
+		b.onStateChange(state)
+	}
+}
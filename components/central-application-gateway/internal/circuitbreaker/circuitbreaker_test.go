@@ -0,0 +1,160 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	config := Config{
+		ConsecutiveFailures: 3,
+		ErrorRate:           0.5,
+		MinRequests:         4,
+		Window:              time.Minute,
+		OpenDuration:        30 * time.Second,
+		HalfOpenRequests:    2,
+	}
+
+	t.Run("should open after consecutive failures", func(t *testing.T) {
+		// given
+		breaker, _, _ := newTestBreaker(Config{ConsecutiveFailures: 3, OpenDuration: 30 * time.Second})
+
+		// when
+		record(t, breaker, false, true, false, false)
+		stateAfterTwoFailures := breaker.State()
+		record(t, breaker, false)
+
+		// then
+		assert.Equal(t, Closed, stateAfterTwoFailures)
+		assert.Equal(t, Open, breaker.State())
+		_, err := breaker.Allow()
+		require.Error(t, err)
+		assert.Equal(t, 30*time.Second, err.(*OpenError).RetryAfter)
+		assert.Error(t, breaker.Check())
+	})
+
+	t.Run("should open when error rate is reached", func(t *testing.T) {
+		// given
+		breaker, _, _ := newTestBreaker(config)
+
+		// when
+		record(t, breaker, true, false, true)
+		stateBelowMinRequests := breaker.State()
+		record(t, breaker, false)
+
+		// then
+		assert.Equal(t, Closed, stateBelowMinRequests)
+		assert.Equal(t, Open, breaker.State())
+	})
+
+	t.Run("should reset error rate in new window", func(t *testing.T) {
+		// given
+		breaker, now, _ := newTestBreaker(config)
+
+		// when
+		record(t, breaker, false, true, false)
+		*now = now.Add(time.Minute)
+		record(t, breaker, false)
+
+		// then
+		assert.Equal(t, Closed, breaker.State())
+	})
+
+	t.Run("should close after successful probes", func(t *testing.T) {
+		// given
+		breaker, now, states := newTestBreaker(config)
+		record(t, breaker, false, false, false)
+
+		// when
+		*now = now.Add(30 * time.Second)
+		require.NoError(t, breaker.Check())
+		first, err := breaker.Allow()
+		require.NoError(t, err)
+		second, err := breaker.Allow()
+		require.NoError(t, err)
+		_, err = breaker.Allow()
+		require.Error(t, err)
+		first(Success)
+		second(Success)
+
+		// then
+		assert.Equal(t, Closed, breaker.State())
+		assert.Equal(t, []State{Open, HalfOpen, Closed}, *states)
+	})
+
+	t.Run("should open again when probe fails", func(t *testing.T) {
+		// given
+		breaker, now, states := newTestBreaker(config)
+		record(t, breaker, false, false, false)
+
+		// when
+		*now = now.Add(30 * time.Second)
+		record(t, breaker, false)
+
+		// then
+		assert.Equal(t, Open, breaker.State())
+		assert.Equal(t, []State{Open, HalfOpen, Open}, *states)
+	})
+
+	t.Run("should ignore outcome of requests started in previous state", func(t *testing.T) {
+		// given
+		breaker, _, _ := newTestBreaker(config)
+		done, err := breaker.Allow()
+		require.NoError(t, err)
+		record(t, breaker, false, false, false)
+
+		// when
+		done(Success)
+
+		// then
+		assert.Equal(t, Open, breaker.State())
+	})
+
+	t.Run("should not count ignored requests", func(t *testing.T) {
+		// given
+		breaker, now, _ := newTestBreaker(config)
+		record(t, breaker, false, false)
+
+		// when
+		done, err := breaker.Allow()
+		require.NoError(t, err)
+		done(Ignored)
+		record(t, breaker, false)
+
+		// then
+		assert.Equal(t, Open, breaker.State())
+
+		// when
+		*now = now.Add(30 * time.Second)
+		probe, err := breaker.Allow()
+		require.NoError(t, err)
+		probe(Ignored)
+		record(t, breaker, true, true)
+
+		// then
+		assert.Equal(t, Closed, breaker.State())
+	})
+}
+
+func newTestBreaker(config Config) (*Breaker, *time.Time, *[]State) {
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	var states []State
+	breaker := New(config, func(state State) { states = append(states, state) })
+	breaker.now = func() time.Time { return now }
+	return breaker, &now, &states
+}
+
+func record(t *testing.T, breaker *Breaker, outcomes ...bool) {
+	for _, success := range outcomes {
+		done, err := breaker.Allow()
+		require.NoError(t, err)
+		if success {
+			done(Success)
+		} else {
+			done(Failure)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httptools"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
 func New(timeoutDuration int, tokenCache TokenCache) csrf.Client {
@@ -34,6 +35,8 @@ type client struct {
 	tokenCache        TokenCache
 	httpClient        *http.Client
 	clientCertificate clientcert.ClientCertificate
+	//Merges the concurrent fetches of the token of the same endpoint into one
+	fetches singleflight.Group
 }
 
 func (c *client) GetTokenEndpointResponse(tokenEndpointURL string, strategy authorization.Strategy) (*csrf.Response, apperrors.AppError) {
@@ -43,15 +46,22 @@ func (c *client) GetTokenEndpointResponse(tokenEndpointURL string, strategy auth
 		return resp, nil
 	}
 
-	log.Infof("CSRF Token not found in cache, fetching (Endpoint: %s)", tokenEndpointURL)
-	tokenResponse, err := c.requestToken(tokenEndpointURL, strategy, c.timeoutDuration)
+	tokenResponse, err, _ := c.fetches.Do(tokenEndpointURL, func() (interface{}, error) {
+		log.Infof("CSRF Token not found in cache, fetching (Endpoint: %s)", tokenEndpointURL)
+		tokenResponse, err := c.requestToken(tokenEndpointURL, strategy, c.timeoutDuration)
+		if err != nil {
+			return nil, err
+		}
+
+		c.tokenCache.Add(tokenEndpointURL, tokenResponse)
+
+		return tokenResponse, nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, err.(apperrors.AppError)
 	}
 
-	c.tokenCache.Add(tokenEndpointURL, tokenResponse)
-
-	return tokenResponse, nil
+	return tokenResponse.(*csrf.Response), nil
 
 }
 
@@ -5,7 +5,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
 
@@ -117,6 +120,40 @@ func TestClient_GetTokenEndpointResponse(t *testing.T) {
 		require.Nil(t, item)
 		assert.False(t, found)
 	})
+
+	t.Run("Should fetch the token from endpoint once for concurrent requests", func(t *testing.T) {
+
+		// given
+		var fetches int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&fetches, 1)
+			time.Sleep(100 * time.Millisecond)
+			w.Header().Add("x-csrf-token", endpointTestToken)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		c := New(timeoutDuration, NewTokenCache())
+
+		// when
+		var wg sync.WaitGroup
+		responses := make([]*csrf.Response, 10)
+		for i := range responses {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				responses[i], _ = c.GetTokenEndpointResponse(srv.URL, strategy)
+			}(i)
+		}
+		wg.Wait()
+
+		// then
+		for _, response := range responses {
+			require.NotNil(t, response)
+			assert.Equal(t, endpointTestToken, response.CSRFToken)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
 }
 
 func TestAddAuthorization(t *testing.T) {
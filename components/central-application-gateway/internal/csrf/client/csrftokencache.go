@@ -1,44 +1,62 @@
 package client
 
 import (
-	"time"
+	"encoding/json"
 
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf"
-
-	cache "github.com/patrickmn/go-cache"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/sharedcache"
+	log "github.com/sirupsen/logrus"
 )
 
-//Cache for CSRF data items
+// Separates the CSRF data items from other items of the shared cache
+const keyPrefix = "csrf/"
+
+// Cache for CSRF data items
 type TokenCache interface {
 	Get(itemID string) (resp *csrf.Response, found bool)
 	Add(itemID string, resp *csrf.Response)
 	Remove(itemID string)
 }
 
-//Creates a new TokenCache instance
+// Creates a new TokenCache instance keeping the items in the memory of the replica
 func NewTokenCache() TokenCache {
+	return NewSharedTokenCache(sharedcache.New(sharedcache.NewMemoryBackend(), sharedcache.Config{}))
+}
+
+// Creates a new TokenCache instance keeping the items in the cache, which can be shared by the gateway replicas
+func NewSharedTokenCache(cache *sharedcache.Cache) TokenCache {
 	return &tokenCache{
-		cache: cache.New(cache.NoExpiration, cache.NoExpiration),
+		cache: cache,
 	}
 }
 
 type tokenCache struct {
-	cache *cache.Cache
+	cache *sharedcache.Cache
 }
 
 func (tc *tokenCache) Get(itemID string) (resp *csrf.Response, found bool) {
-	res, found := tc.cache.Get(itemID)
+	value, found := tc.cache.Get(keyPrefix + itemID)
 	if !found {
 		return nil, false
 	}
 
-	return res.(*csrf.Response), found
+	resp = &csrf.Response{}
+	if err := json.Unmarshal([]byte(value), resp); err != nil {
+		log.Warnf("Failed to decode cached CSRF token of endpoint %s: %s", itemID, err.Error())
+		return nil, false
+	}
+	return resp, true
 }
 
 func (tc *tokenCache) Add(itemID string, resp *csrf.Response) {
-	tc.cache.Set(itemID, resp, time.Duration(-1)*time.Second)
+	value, err := json.Marshal(resp)
+	if err != nil {
+		log.Warnf("Failed to encode CSRF token of endpoint %s: %s", itemID, err.Error())
+		return
+	}
+	tc.cache.Set(keyPrefix+itemID, string(value), 0)
 }
 
 func (tc *tokenCache) Remove(itemID string) {
-	tc.cache.Delete(itemID)
+	tc.cache.Delete(keyPrefix + itemID)
 }
@@ -17,6 +17,9 @@ import (
 const (
 	specAPIType    = "API"
 	specEventsType = "Events"
+
+	// StreamingLabel is the label of the service which enables streaming mode of its APIs
+	StreamingLabel = "gateway.kyma-project.io/streaming"
 )
 
 // Manager contains operations for managing Application CRD
@@ -42,6 +45,8 @@ type ServiceAPI struct {
 	TargetURL                   string
 	Credentials                 *Credentials
 	RequestParametersSecretName string
+	// Streaming is set if the service is labeled with StreamingLabel
+	Streaming bool
 }
 
 type predicateFunc func(service v1alpha1.Service, entry v1alpha1.Entry) bool
@@ -143,6 +148,7 @@ func convert(service v1alpha1.Service, entry v1alpha1.Entry) Service {
 		TargetURL:                   entry.TargetUrl,
 		Credentials:                 convertCredentialsFromK8sType(entry.Credentials),
 		RequestParametersSecretName: entry.RequestParametersSecretName,
+		Streaming:                   service.Labels[StreamingLabel] == "true",
 	}
 
 	return Service{
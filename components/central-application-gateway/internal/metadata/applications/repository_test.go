@@ -154,6 +154,23 @@ func TestGetServices(t *testing.T) {
 
 }
 
+func TestGetStreamingService(t *testing.T) {
+	// given
+	managerMock := &mocks.Manager{}
+	managerMock.On("Get", context.Background(), "production", metav1.GetOptions{}).
+		Return(createApplication("production"), nil)
+
+	repository := applications.NewServiceRepository(managerMock)
+
+	// when
+	service, err := repository.GetByServiceName("production", "products-api")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Products API", service.DisplayName)
+	assert.True(t, service.API.Streaming)
+}
+
 func createApplication(name string) *v1alpha1.Application {
 
 	service1Entry := v1alpha1.Entry{
@@ -192,6 +209,7 @@ func createApplication(name string) *v1alpha1.Application {
 		LongDescription:     "This is Products API",
 		ProviderDisplayName: "SAP Hybris",
 		Tags:                []string{"products"},
+		Labels:              map[string]string{applications.StreamingLabel: "true"},
 		Entries:             []v1alpha1.Entry{service2Entry},
 	}
 
@@ -30,6 +30,8 @@ type API struct {
 	Spec []byte
 	// RequestParameters will be used with request send by the Application Gateway
 	RequestParameters *authorization.RequestParameters
+	// Streaming means the requests to API are forwarded without buffering and without the overall timeout
+	Streaming bool
 }
 
 // Events contains specification for events.
@@ -47,6 +47,7 @@ func NewService(secretsRepository secrets.Repository) Service {
 func (sas defaultService) Read(applicationAPI *applications.ServiceAPI) (*model.API, apperrors.AppError) {
 	api := &model.API{
 		TargetUrl: applicationAPI.TargetURL,
+		Streaming: applicationAPI.Streaming,
 	}
 
 	if applicationAPI.Credentials != nil {
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metadata/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "central_application_gateway"
+
+var apiLabels = []string{"application", "service", "entry"}
+
+var (
+	circuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "circuit_breaker",
+		Name:      "state",
+		Help:      "State of the circuit breaker of the API: 0 is closed, 1 is half-open and 2 is open.",
+	}, apiLabels)
+	circuitBreakerTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "circuit_breaker",
+		Name:      "transitions_total",
+		Help:      "Number of the state transitions of the circuit breaker of the API.",
+	}, append(apiLabels, "state"))
+	circuitBreakerRejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "circuit_breaker",
+		Name:      "rejected_requests_total",
+		Help:      "Number of the requests to the API rejected by its open circuit breaker.",
+	}, apiLabels)
+	proxyRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "retries_total",
+		Help:      "Number of the retried requests to the API.",
+	}, apiLabels)
+	proxyRetryBudgetExhausted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "retry_budget_exhausted_total",
+		Help:      "Number of the failed requests to the API not retried because its retry budget was exhausted.",
+	}, apiLabels)
+)
+
+func init() {
+	prometheus.MustRegister(circuitBreakerState, circuitBreakerTransitions, circuitBreakerRejectedRequests, proxyRetries, proxyRetryBudgetExhausted)
+}
+
+func labels(api model.APIIdentifier) prometheus.Labels {
+	return prometheus.Labels{"application": api.Application, "service": api.Service, "entry": api.Entry}
+}
+
+// SetCircuitBreakerState records the state transition of the circuit breaker of the API
+func SetCircuitBreakerState(api model.APIIdentifier, state circuitbreaker.State) {
+	circuitBreakerState.With(labels(api)).Set(float64(state))
+
+	transitionLabels := labels(api)
+	transitionLabels["state"] = state.String()
+	circuitBreakerTransitions.With(transitionLabels).Inc()
+}
+
+// IncCircuitBreakerRejectedRequests counts the request rejected by the open circuit breaker of the API
+func IncCircuitBreakerRejectedRequests(api model.APIIdentifier) {
+	circuitBreakerRejectedRequests.With(labels(api)).Inc()
+}
+
+// IncRetries counts the retried request to the API
+func IncRetries(api model.APIIdentifier) {
+	proxyRetries.With(labels(api)).Inc()
+}
+
+// IncRetryBudgetExhausted counts the request to the API not retried because of the exhausted retry budget
+func IncRetryBudgetExhausted(api model.APIIdentifier) {
+	proxyRetryBudgetExhausted.With(labels(api)).Inc()
+}
@@ -19,6 +19,8 @@ type CacheEntry struct {
 	Proxy                 *httputil.ReverseProxy
 	AuthorizationStrategy *authorizationStrategyWrapper
 	CSRFTokenStrategy     csrf.TokenStrategy
+	// Streaming means the requests are forwarded without buffering
+	Streaming bool
 }
 
 type authorizationStrategyWrapper struct {
@@ -40,7 +42,7 @@ type Cache interface {
 	// Get returns entry from the cache
 	Get(appName, serviceName, apiName string) (*CacheEntry, bool)
 	// Put adds entry to the cache
-	Put(appName, serviceName, apiName string, reverseProxy *httputil.ReverseProxy, authorizationStrategy authorization.Strategy, csrfTokenStrategy csrf.TokenStrategy, clientCertificate clientcert.ClientCertificate) *CacheEntry
+	Put(appName, serviceName, apiName string, reverseProxy *httputil.ReverseProxy, authorizationStrategy authorization.Strategy, csrfTokenStrategy csrf.TokenStrategy, clientCertificate clientcert.ClientCertificate, streaming bool) *CacheEntry
 }
 
 type cache struct {
@@ -64,9 +66,9 @@ func (p *cache) Get(appName, serviceName, apiName string) (*CacheEntry, bool) {
 	return proxy.(*CacheEntry), found
 }
 
-func (p *cache) Put(appName, serviceName, apiName string, reverseProxy *httputil.ReverseProxy, authorizationStrategy authorization.Strategy, csrfTokenStrategy csrf.TokenStrategy, clientCertificate clientcert.ClientCertificate) *CacheEntry {
+func (p *cache) Put(appName, serviceName, apiName string, reverseProxy *httputil.ReverseProxy, authorizationStrategy authorization.Strategy, csrfTokenStrategy csrf.TokenStrategy, clientCertificate clientcert.ClientCertificate, streaming bool) *CacheEntry {
 	key := appName + serviceName + apiName
-	proxy := &CacheEntry{Proxy: reverseProxy, AuthorizationStrategy: &authorizationStrategyWrapper{authorizationStrategy, reverseProxy, clientCertificate}, CSRFTokenStrategy: csrfTokenStrategy}
+	proxy := &CacheEntry{Proxy: reverseProxy, AuthorizationStrategy: &authorizationStrategyWrapper{authorizationStrategy, reverseProxy, clientCertificate}, CSRFTokenStrategy: csrfTokenStrategy, Streaming: streaming}
 	p.proxyCache.Set(key, proxy, gocache.DefaultExpiration)
 
 	return proxy
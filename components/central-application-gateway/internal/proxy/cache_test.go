@@ -37,7 +37,7 @@ func TestCache(t *testing.T) {
 		url := net.FormatURL("http", "www.example.com", 8080, "")
 		proxy := httputil.NewSingleHostReverseProxy(url)
 
-		cacheEntry := cache.Put("app1", "service1", "api1", proxy, authorizationStrategyMock, csrfTokenStrategy, clientCertificate)
+		cacheEntry := cache.Put("app1", "service1", "api1", proxy, authorizationStrategyMock, csrfTokenStrategy, clientCertificate, false)
 
 		// then
 		require.NotNil(t, cacheEntry)
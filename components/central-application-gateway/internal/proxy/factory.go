@@ -45,8 +45,10 @@ func New(
 
 	return &proxy{
 		cache:                        NewCache(config.ProxyCacheTTL),
+		resilience:                   newResilienceRegistry(config),
 		skipVerify:                   config.SkipVerify,
 		proxyTimeout:                 config.ProxyTimeout,
+		spool:                        newSpool(config.RequestBodySpoolThreshold, config.RequestBodySpoolDir, config.RequestBodySpoolMaxSize),
 		authorizationStrategyFactory: authorizationStrategyFactory,
 		csrfTokenStrategyFactory:     csrfTokenStrategyFactory,
 		extractPathFunc:              pathExtractor,
@@ -84,8 +86,10 @@ func NewForCompass(
 
 	return &proxy{
 		cache:                        NewCache(config.ProxyCacheTTL),
+		resilience:                   newResilienceRegistry(config),
 		skipVerify:                   config.SkipVerify,
 		proxyTimeout:                 config.ProxyTimeout,
+		spool:                        newSpool(config.RequestBodySpoolThreshold, config.RequestBodySpoolDir, config.RequestBodySpoolMaxSize),
 		authorizationStrategyFactory: authorizationStrategyFactory,
 		csrfTokenStrategyFactory:     csrfTokenStrategyFactory,
 		extractPathFunc:              extractFunc,
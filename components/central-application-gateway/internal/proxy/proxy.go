@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf"
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/httperrors"
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metadata/model"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metrics"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
@@ -21,8 +27,10 @@ import (
 
 type proxy struct {
 	cache                        Cache
+	resilience                   *resilienceRegistry
 	skipVerify                   bool
 	proxyTimeout                 int
+	spool                        *spool
 	authorizationStrategyFactory authorization.StrategyFactory
 	csrfTokenStrategyFactory     csrf.TokenStrategyFactory
 	extractPathFunc              pathExtractorFunc
@@ -36,10 +44,19 @@ type APIExtractor interface {
 
 // Config stores Proxy config
 type Config struct {
-	SkipVerify    bool
-	ProxyTimeout  int
-	Application   string
-	ProxyCacheTTL int
+	SkipVerify     bool
+	ProxyTimeout   int
+	Application    string
+	ProxyCacheTTL  int
+	CircuitBreaker circuitbreaker.Config
+	Retry          RetryConfig
+	// RequestBodySpoolThreshold is the size in bytes above which the buffered request bodies are stored in
+	// RequestBodySpoolDir instead of the memory, the bodies are always kept in the memory if it isn't positive
+	RequestBodySpoolThreshold int64
+	RequestBodySpoolDir       string
+	// RequestBodySpoolMaxSize limits the total size in bytes of the bodies stored in RequestBodySpoolDir at the same
+	// time, the requests which would exceed it are rejected, the size isn't limited if it isn't positive
+	RequestBodySpoolMaxSize int64
 }
 
 func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -51,15 +68,45 @@ func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	r.URL.Path = path
 
-	cacheEntry, err := p.getOrCreateCacheEntry(apiIdentifier)
+	resilience := p.resilience.get(apiIdentifier)
+	if resilience.breaker != nil {
+		if err := resilience.breaker.Check(); err != nil {
+			metrics.IncCircuitBreakerRejectedRequests(apiIdentifier)
+			respondCircuitOpen(w, apiIdentifier, err)
+			return
+		}
+	}
+
+	cacheEntry, err := p.getOrCreateCacheEntry(apiIdentifier, resilience)
 	if err != nil {
 		handleErrors(w, err)
 		return
 	}
 
-	newRequest, cancel := p.setRequestTimeout(r)
+	var newRequest *http.Request
+	var cancel context.CancelFunc
+	if cacheEntry.Streaming {
+		newRequest, cancel = p.setStreamingContext(r)
+	} else {
+		newRequest, cancel = p.setRequestTimeout(r)
+	}
 	defer cancel()
 
+	if !cacheEntry.Streaming {
+		body, err := p.spoolRequestBody(newRequest)
+		if errors.Is(err, errSpoolFull) {
+			respondSpoolFull(w)
+			return
+		}
+		if err != nil {
+			handleErrors(w, apperrors.Internal("failed to read request body, %s", err))
+			return
+		}
+		if body != nil {
+			defer body.Close()
+		}
+	}
+
 	err = p.addAuthorization(newRequest, cacheEntry)
 	if err != nil {
 		handleErrors(w, err)
@@ -77,17 +124,17 @@ func (p *proxy) extractPath(path string) (model.APIIdentifier, string, apperrors
 	return apiIdentifier, path, nil
 }
 
-func (p *proxy) getOrCreateCacheEntry(apiIdentifier model.APIIdentifier) (*CacheEntry, apperrors.AppError) {
+func (p *proxy) getOrCreateCacheEntry(apiIdentifier model.APIIdentifier, resilience *apiResilience) (*CacheEntry, apperrors.AppError) {
 	cacheObj, found := p.cache.Get(apiIdentifier.Application, apiIdentifier.Service, apiIdentifier.Entry)
 
 	if found {
 		return cacheObj, nil
 	}
 
-	return p.createCacheEntry(apiIdentifier)
+	return p.createCacheEntry(apiIdentifier, resilience)
 }
 
-func (p *proxy) createCacheEntry(apiIdentifier model.APIIdentifier) (*CacheEntry, apperrors.AppError) {
+func (p *proxy) createCacheEntry(apiIdentifier model.APIIdentifier, resilience *apiResilience) (*CacheEntry, apperrors.AppError) {
 	serviceAPI, err := p.apiExtractor.Get(apiIdentifier)
 	if err != nil {
 		return nil, err
@@ -95,12 +142,12 @@ func (p *proxy) createCacheEntry(apiIdentifier model.APIIdentifier) (*CacheEntry
 	clientCertificate := clientcert.NewClientCertificate(nil)
 	authorizationStrategy := p.newAuthorizationStrategy(serviceAPI.Credentials)
 	csrfTokenStrategy := p.newCSRFTokenStrategy(authorizationStrategy, serviceAPI.Credentials)
-	proxy, err := makeProxy(serviceAPI.TargetUrl, serviceAPI.RequestParameters, apiIdentifier.Service, p.skipVerify, authorizationStrategy, csrfTokenStrategy, clientCertificate, p.proxyTimeout)
+	proxy, err := makeProxy(serviceAPI.TargetUrl, serviceAPI.RequestParameters, apiIdentifier, p.skipVerify, authorizationStrategy, csrfTokenStrategy, clientCertificate, p.proxyTimeout, serviceAPI.Streaming, resilience, p.resilience.retryConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return p.cache.Put(apiIdentifier.Application, apiIdentifier.Service, apiIdentifier.Entry, proxy, authorizationStrategy, csrfTokenStrategy, clientCertificate), nil
+	return p.cache.Put(apiIdentifier.Application, apiIdentifier.Service, apiIdentifier.Entry, proxy, authorizationStrategy, csrfTokenStrategy, clientCertificate, serviceAPI.Streaming), nil
 }
 
 func (p *proxy) newAuthorizationStrategy(credentials *authorization.Credentials) authorization.Strategy {
@@ -125,11 +172,44 @@ func (p *proxy) newCSRFTokenStrategyFromCSRFConfig(authorizationStrategy authori
 
 func (p *proxy) setRequestTimeout(r *http.Request) (*http.Request, context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.proxyTimeout)*time.Second)
+	// the user token is kept in the context as it's removed from the headers by the token exchanging strategies
+	ctx = authorization.WithUserToken(ctx, r.Header.Get(httpconsts.HeaderAccessToken))
 	newRequest := r.WithContext(ctx)
 
 	return newRequest, cancel
 }
 
+// setStreamingContext doesn't limit the duration of the streamed request, which ends when the caller or the target API
+// closes the stream, the transport of the streaming API limits the time of waiting for the response headers instead.
+// The server timeouts are relaxed only for the streamed request.
+func (p *proxy) setStreamingContext(r *http.Request) (*http.Request, context.CancelFunc) {
+	clearConnDeadlines(r.Context())
+	ctx, cancel := context.WithCancel(withStreaming(r.Context()))
+	ctx = authorization.WithUserToken(ctx, r.Header.Get(httpconsts.HeaderAccessToken))
+
+	return r.WithContext(ctx), cancel
+}
+
+// spoolRequestBody buffers the request body so that the request can be repeated, the returned body must be closed
+// after the request is done
+func (p *proxy) spoolRequestBody(r *http.Request) (*spooledBody, error) {
+	if !hasBody(r) {
+		return nil, nil
+	}
+
+	body, err := p.spool.spoolBody(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body.reader()
+	r.GetBody = func() (io.ReadCloser, error) {
+		return body.reader(), nil
+	}
+
+	return body, nil
+}
+
 func (p *proxy) addAuthorization(r *http.Request, cacheEntry *CacheEntry) apperrors.AppError {
 
 	err := cacheEntry.AuthorizationStrategy.AddAuthorization(r)
@@ -141,6 +221,30 @@ func (p *proxy) addAuthorization(r *http.Request, cacheEntry *CacheEntry) apperr
 	return cacheEntry.CSRFTokenStrategy.AddCSRFToken(r)
 }
 
+// requestBodyForRetry returns the function providing the body of the repeated request, the function is nil if
+// the body of the streamed request can't be provided again
+func requestBodyForRetry(r *http.Request) (func() (io.ReadCloser, error), apperrors.AppError) {
+	switch {
+	case !hasBody(r):
+		body := r.Body
+		return func() (io.ReadCloser, error) {
+			return body, nil
+		}, nil
+	case r.GetBody != nil:
+		return r.GetBody, nil
+	case isStreaming(r.Context()):
+		return nil, nil
+	}
+
+	secondRequestBody, err := copyRequestBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return func() (io.ReadCloser, error) {
+		return secondRequestBody, nil
+	}, nil
+}
+
 func copyRequestBody(r *http.Request) (io.ReadCloser, apperrors.AppError) {
 	if r.Body == nil {
 		return nil, nil
@@ -174,6 +278,37 @@ func handleErrors(w http.ResponseWriter, apperr apperrors.AppError) {
 	respondWithBody(w, code, body)
 }
 
+// respondCircuitOpen responds with 503 and the Retry-After header, so the caller backs off the failing API
+func respondCircuitOpen(w http.ResponseWriter, apiIdentifier model.APIIdentifier, err error) {
+	var openErr *circuitbreaker.OpenError
+	retryAfter := time.Second
+	if errors.As(err, &openErr) && openErr.RetryAfter > retryAfter {
+		retryAfter = openErr.RetryAfter
+	}
+
+	w.Header().Set(httpconsts.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	respondWithBody(w, http.StatusServiceUnavailable, httperrors.ErrorResponse{
+		Code:  http.StatusServiceUnavailable,
+		Error: fmt.Sprintf("circuit breaker of the '%s' API is open because the target API is failing", apiPath(apiIdentifier)),
+	})
+}
+
+// respondSpoolFull responds with 503 when the disk space for the request bodies is used up by the concurrent requests
+func respondSpoolFull(w http.ResponseWriter) {
+	w.Header().Set(httpconsts.HeaderRetryAfter, "1")
+	respondWithBody(w, http.StatusServiceUnavailable, httperrors.ErrorResponse{
+		Code:  http.StatusServiceUnavailable,
+		Error: "request body can't be buffered because the space for the request bodies is used up, retry later",
+	})
+}
+
+func apiPath(apiIdentifier model.APIIdentifier) string {
+	if apiIdentifier.Entry == "" {
+		return apiIdentifier.Application + "/" + apiIdentifier.Service
+	}
+	return apiIdentifier.Application + "/" + apiIdentifier.Service + "/" + apiIdentifier.Entry
+}
+
 func respondWithBody(w http.ResponseWriter, code int, body httperrors.ErrorResponse) {
 	w.Header().Set(httpconsts.HeaderContentType, httpconsts.ContentTypeApplicationJson)
 
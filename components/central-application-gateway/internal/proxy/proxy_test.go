@@ -3,11 +3,15 @@ package proxy
 import (
 	"bytes"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf"
 
 	csrfMock "github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf/mocks"
@@ -340,6 +344,118 @@ func TestProxy(t *testing.T) {
 		csrfStrategyMock.AssertExpectations(t)
 	})
 
+	t.Run("should fail fast with Service Unavailable error when circuit breaker is open", func(t *testing.T) {
+		// given
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		authStrategyMock := &authMock.Strategy{}
+		authStrategyMock.
+			On("AddAuthorization", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("SetClientCertificateFunc")).
+			Return(nil).Twice()
+
+		authStrategyFactoryMock := &authMock.StrategyFactory{}
+		authStrategyFactoryMock.On("Create", mock.Anything).Return(authStrategyMock).Once()
+		csrfFactoryMock, csrfStrategyMock := mockCSRFStrategy(authStrategyMock, func(mockCall *mock.Call) { mockCall.Twice() })
+
+		apiExtractorMock := &proxyMocks.APIExtractor{}
+		apiExtractorMock.On("Get", apiIdentifier).Return(&metadatamodel.API{
+			TargetUrl:   ts.URL,
+			Credentials: &authorization.Credentials{},
+		}, nil).Once()
+
+		proxyConfig := createProxyConfig(proxyTimeout)
+		proxyConfig.CircuitBreaker = circuitbreaker.Config{ConsecutiveFailures: 2, OpenDuration: time.Minute}
+		handler := newProxyForTest(apiExtractorMock, authStrategyFactoryMock, csrfFactoryMock, fakePathExtractor, proxyConfig)
+
+		// when
+		var codes []int
+		rr := httptest.NewRecorder()
+		for i := 0; i < 3; i++ {
+			req, err := http.NewRequest(http.MethodGet, "/orders/123", nil)
+			require.NoError(t, err)
+			rr = httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+			codes = append(codes, rr.Code)
+		}
+
+		// then
+		assert.Equal(t, []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusServiceUnavailable}, codes)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, "60", rr.Header().Get(httpconsts.HeaderRetryAfter))
+		assert.JSONEq(t, `{"code":503,"error":"circuit breaker of the 'app/service/entry' API is open because the target API is failing"}`, rr.Body.String())
+
+		apiExtractorMock.AssertExpectations(t)
+		authStrategyFactoryMock.AssertExpectations(t)
+		authStrategyMock.AssertExpectations(t)
+		csrfFactoryMock.AssertExpectations(t)
+		csrfStrategyMock.AssertExpectations(t)
+	})
+
+	t.Run("should repeat request with body spooled to disk when 401 occurred", func(t *testing.T) {
+		// given
+		var bodies []string
+		ts := NewTestServerForRetryTest(http.StatusUnauthorized, func(req *http.Request) {
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+		})
+		defer ts.Close()
+
+		spoolDir, err := ioutil.TempDir("", "spool")
+		require.NoError(t, err)
+		defer os.RemoveAll(spoolDir)
+
+		authStrategyMock := &authMock.Strategy{}
+		authStrategyMock.
+			On("AddAuthorization", mock.Anything, mock.AnythingOfType("SetClientCertificateFunc")).
+			Return(nil).Twice()
+		authStrategyMock.On("Invalidate").Return().Once()
+
+		authStrategyFactoryMock := &authMock.StrategyFactory{}
+		authStrategyFactoryMock.On("Create", mock.Anything).Return(authStrategyMock)
+
+		csrfTokenStrategyMock := &csrfMock.TokenStrategy{}
+		csrfTokenStrategyMock.On("AddCSRFToken", mock.AnythingOfType("*http.Request")).Return(nil).Twice()
+		csrfTokenStrategyMock.On("Invalidate").Return().Once()
+
+		csrfTokenStrategyFactoryMock := &csrfMock.TokenStrategyFactory{}
+		csrfTokenStrategyFactoryMock.On("Create", authStrategyMock, "").Return(csrfTokenStrategyMock)
+
+		apiExtractorMock := &proxyMocks.APIExtractor{}
+		apiExtractorMock.On("Get", apiIdentifier).Return(&metadatamodel.API{
+			TargetUrl:   ts.URL,
+			Credentials: &authorization.Credentials{},
+		}, nil)
+
+		proxyConfig := createProxyConfig(proxyTimeout)
+		proxyConfig.RequestBodySpoolThreshold = 4
+		proxyConfig.RequestBodySpoolDir = spoolDir
+		handler := newProxyForTest(apiExtractorMock, authStrategyFactoryMock, csrfTokenStrategyFactoryMock, fakePathExtractor, proxyConfig)
+
+		req, err := http.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString("large body"))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []string{"large body", "large body"}, bodies)
+		files, err := ioutil.ReadDir(spoolDir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+
+		authStrategyMock.AssertExpectations(t)
+		csrfTokenStrategyMock.AssertExpectations(t)
+	})
+
 	testRetryOnAuthFailure := func(testServerConstructor func(check func(req *http.Request)) *httptest.Server, requestBody io.Reader, expectedStatusCode int, t *testing.T) {
 		// given
 		tsf := testServerConstructor(func(req *http.Request) {
@@ -442,8 +558,10 @@ func newProxyForTest(
 
 	return &proxy{
 		cache:                        NewCache(proxyConfig.ProxyCacheTTL),
+		resilience:                   newResilienceRegistry(proxyConfig),
 		skipVerify:                   proxyConfig.SkipVerify,
 		proxyTimeout:                 proxyConfig.ProxyTimeout,
+		spool:                        newSpool(proxyConfig.RequestBodySpoolThreshold, proxyConfig.RequestBodySpoolDir, proxyConfig.RequestBodySpoolMaxSize),
 		authorizationStrategyFactory: authorizationStrategyFactory,
 		csrfTokenStrategyFactory:     csrfTokenStrategyFactory,
 		extractPathFunc:              pathExtractorFunc,
@@ -0,0 +1,143 @@
+package proxy
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metadata/model"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metrics"
+)
+
+const retryBudgetWindow = 10 * time.Second
+
+// RetryConfig stores the configuration of the retries of the idempotent requests
+type RetryConfig struct {
+	// MaxRetries is the number of the retries of the failed idempotent request, 0 disables the retries
+	MaxRetries int
+	// Backoff is the base of the exponential backoff between the retries, the actual backoff is jittered
+	Backoff time.Duration
+	// MaxBackoff caps the backoff between the retries
+	MaxBackoff time.Duration
+	// BudgetRatio is the ratio of the retries to the requests allowed per API
+	BudgetRatio float64
+	// BudgetMinRetries is the number of the retries per API always allowed in 10 seconds, regardless of BudgetRatio
+	BudgetMinRetries int
+}
+
+// backoff returns the jittered backoff before the retry, it's random between 0 and the exponential backoff
+func (c RetryConfig) backoff(retry int) time.Duration {
+	ceiling := c.Backoff
+	for i := 0; i < retry && ceiling < c.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	if ceiling > c.MaxBackoff {
+		ceiling = c.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// apiResilience holds the state of the API which outlives the proxy cache entries
+type apiResilience struct {
+	api     model.APIIdentifier
+	breaker *circuitbreaker.Breaker
+	budget  *retryBudget
+}
+
+type resilienceRegistry struct {
+	circuitBreakerConfig circuitbreaker.Config
+	retryConfig          RetryConfig
+
+	mutex sync.Mutex
+	apis  map[model.APIIdentifier]*apiResilience
+}
+
+func newResilienceRegistry(config Config) *resilienceRegistry {
+	return &resilienceRegistry{
+		circuitBreakerConfig: config.CircuitBreaker,
+		retryConfig:          config.Retry,
+		apis:                 map[model.APIIdentifier]*apiResilience{},
+	}
+}
+
+func (r *resilienceRegistry) get(api model.APIIdentifier) *apiResilience {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	resilience, ok := r.apis[api]
+	if ok {
+		return resilience
+	}
+
+	resilience = &apiResilience{
+		api:    api,
+		budget: newRetryBudget(r.retryConfig.BudgetRatio, r.retryConfig.BudgetMinRetries),
+	}
+	if r.circuitBreakerConfig.Enabled() {
+		resilience.breaker = circuitbreaker.New(r.circuitBreakerConfig, func(state circuitbreaker.State) {
+			metrics.SetCircuitBreakerState(api, state)
+		})
+		metrics.SetCircuitBreakerState(api, circuitbreaker.Closed)
+	}
+	r.apis[api] = resilience
+
+	return resilience
+}
+
+// retryBudget limits the retries to the ratio of the requests in the window, so the retries don't multiply the load of
+// the failing API
+type retryBudget struct {
+	ratio      float64
+	minRetries int
+	now        func() time.Time
+
+	mutex       sync.Mutex
+	windowStart time.Time
+	requests    int
+	retries     int
+}
+
+func newRetryBudget(ratio float64, minRetries int) *retryBudget {
+	return &retryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		now:        time.Now,
+	}
+}
+
+func (b *retryBudget) recordRequest() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.rotate()
+	b.requests++
+}
+
+// withdraw returns true if the retry fits in the budget
+func (b *retryBudget) withdraw() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.rotate()
+	allowed := int(b.ratio * float64(b.requests))
+	if allowed < b.minRetries {
+		allowed = b.minRetries
+	}
+	if b.retries >= allowed {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+func (b *retryBudget) rotate() {
+	if now := b.now(); now.Sub(b.windowStart) >= retryBudgetWindow {
+		b.windowStart = now
+		b.requests = 0
+		b.retries = 0
+	}
+}
@@ -0,0 +1,155 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+// resilientRoundTripper rejects the requests to the API with the open circuit breaker and retries the failed
+// idempotent requests within the retry budget of the API
+type resilientRoundTripper struct {
+	roundTripper http.RoundTripper
+	resilience   *apiResilience
+	config       RetryConfig
+	sleep        func(req *http.Request, d time.Duration) error
+}
+
+func newResilientRoundTripper(roundTripper http.RoundTripper, resilience *apiResilience, config RetryConfig) *resilientRoundTripper {
+	return &resilientRoundTripper{
+		roundTripper: roundTripper,
+		resilience:   resilience,
+		config:       config,
+		sleep:        sleepWithContext,
+	}
+}
+
+func (p *resilientRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := p.config.MaxRetries > 0 && isIdempotent(req.Method)
+
+	getBody := req.GetBody
+	if retryable && hasBody(req) && getBody == nil {
+		if isStreaming(req.Context()) {
+			// the streamed body can't be sent again
+			retryable = false
+		} else {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			req.Body.Close()
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			getBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+	}
+
+	p.resilience.budget.recordRequest()
+
+	request := req
+	for retry := 0; ; retry++ {
+		resp, err := p.roundTrip(request)
+		if !retryable || retry >= p.config.MaxRetries || !shouldRetryAfterFailure(resp, err) || isOpenCircuit(err) {
+			return resp, err
+		}
+		if !p.resilience.budget.withdraw() {
+			metrics.IncRetryBudgetExhausted(p.resilience.api)
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := p.sleep(req, p.config.backoff(retry)); err != nil {
+			return nil, err
+		}
+
+		log.Infof("Retrying %s request to '%s' after failed attempt %d", req.Method, req.URL.String(), retry+1)
+		metrics.IncRetries(p.resilience.api)
+		request = req.Clone(req.Context())
+		if hasBody(req) {
+			if request.Body, err = getBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// roundTrip sends the request through the circuit breaker, the transport errors and the 502, 503 and 504 responses
+// are its failures
+func (p *resilientRoundTripper) roundTrip(req *http.Request) (*http.Response, error) {
+	breaker := p.resilience.breaker
+	if breaker == nil {
+		return p.roundTripper.RoundTrip(req)
+	}
+
+	done, err := breaker.Allow()
+	if err != nil {
+		metrics.IncCircuitBreakerRejectedRequests(p.resilience.api)
+		return nil, err
+	}
+	resp, err := p.roundTripper.RoundTrip(req)
+	done(circuitBreakerOutcome(req, resp, err))
+
+	return resp, err
+}
+
+// circuitBreakerOutcome counts only the failures of the API or of the connection to it, the requests canceled by the
+// caller and the errors reported by the API itself, such as 500, don't open the circuit
+func circuitBreakerOutcome(req *http.Request, resp *http.Response, err error) circuitbreaker.Outcome {
+	if errors.Is(err, context.Canceled) || req.Context().Err() == context.Canceled {
+		return circuitbreaker.Ignored
+	}
+	if shouldRetryAfterFailure(resp, err) {
+		return circuitbreaker.Failure
+	}
+	return circuitbreaker.Success
+}
+
+func shouldRetryAfterFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func isOpenCircuit(err error) bool {
+	var openErr *circuitbreaker.OpenError
+	return errors.As(err, &openErr)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepWithContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
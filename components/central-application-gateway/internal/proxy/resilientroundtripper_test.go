@@ -0,0 +1,191 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/circuitbreaker"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metadata/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestResilientRoundTripper(t *testing.T) {
+	retryConfig := RetryConfig{
+		MaxRetries:       2,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       10 * time.Millisecond,
+		BudgetRatio:      0.2,
+		BudgetMinRetries: 10,
+	}
+	api := model.APIIdentifier{Application: "app", Service: "service"}
+
+	newRoundTripper := func(config Config, statusCodes ...int) (*resilientRoundTripper, *[]string) {
+		var bodies []string
+		next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body := ""
+			if req.Body != nil {
+				data, _ := ioutil.ReadAll(req.Body)
+				body = string(data)
+			}
+			bodies = append(bodies, body)
+
+			statusCode := statusCodes[len(bodies)-1]
+			if statusCode == 0 {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: statusCode, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		})
+
+		roundTripper := newResilientRoundTripper(next, newResilienceRegistry(config).get(api), config.Retry)
+		roundTripper.sleep = func(req *http.Request, d time.Duration) error { return nil }
+		return roundTripper, &bodies
+	}
+
+	t.Run("should retry idempotent request with body", func(t *testing.T) {
+		// given
+		roundTripper, bodies := newRoundTripper(Config{Retry: retryConfig}, http.StatusServiceUnavailable, 0, http.StatusOK)
+		req, err := http.NewRequest(http.MethodPut, "http://example.com", strings.NewReader("body"))
+		require.NoError(t, err)
+
+		// when
+		resp, err := roundTripper.RoundTrip(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"body", "body", "body"}, *bodies)
+	})
+
+	t.Run("should not retry non-idempotent request", func(t *testing.T) {
+		// given
+		roundTripper, bodies := newRoundTripper(Config{Retry: retryConfig}, http.StatusServiceUnavailable)
+		req, err := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("body"))
+		require.NoError(t, err)
+
+		// when
+		resp, err := roundTripper.RoundTrip(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Len(t, *bodies, 1)
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		// given
+		roundTripper, bodies := newRoundTripper(Config{Retry: retryConfig}, http.StatusNotFound)
+		req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		resp, err := roundTripper.RoundTrip(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Len(t, *bodies, 1)
+	})
+
+	t.Run("should stop retrying when retry budget is exhausted", func(t *testing.T) {
+		// given
+		config := retryConfig
+		config.BudgetMinRetries = 1
+		roundTripper, bodies := newRoundTripper(Config{Retry: config}, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK)
+		req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		resp, err := roundTripper.RoundTrip(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Len(t, *bodies, 2)
+	})
+
+	t.Run("should not open circuit on internal server errors and canceled requests", func(t *testing.T) {
+		// given
+		config := Config{CircuitBreaker: circuitbreaker.Config{ConsecutiveFailures: 2, OpenDuration: time.Minute}}
+		roundTripper, bodies := newRoundTripper(config, http.StatusInternalServerError, http.StatusInternalServerError, 0, 0, http.StatusOK)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		for _, requestContext := range []context.Context{context.Background(), context.Background(), ctx, ctx, context.Background()} {
+			req, err := http.NewRequestWithContext(requestContext, http.MethodGet, "http://example.com", nil)
+			require.NoError(t, err)
+			_, err = roundTripper.RoundTrip(req)
+			assert.False(t, isOpenCircuit(err))
+		}
+
+		// then
+		assert.Len(t, *bodies, 5)
+		assert.Equal(t, circuitbreaker.Closed, roundTripper.resilience.breaker.State())
+	})
+
+	t.Run("should stop retrying when circuit opens", func(t *testing.T) {
+		// given
+		config := Config{
+			Retry:          retryConfig,
+			CircuitBreaker: circuitbreaker.Config{ConsecutiveFailures: 2, OpenDuration: time.Minute},
+		}
+		roundTripper, bodies := newRoundTripper(config, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+		req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		_, err = roundTripper.RoundTrip(req)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, isOpenCircuit(err))
+		assert.Len(t, *bodies, 2)
+	})
+}
+
+func TestRetryConfig_backoff(t *testing.T) {
+	// given
+	config := RetryConfig{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry, ceiling := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		// when
+		backoff := config.backoff(retry)
+
+		// then
+		assert.True(t, backoff >= 0 && backoff <= ceiling, "backoff %s of retry %d exceeds %s", backoff, retry, ceiling)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	// given
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	budget := newRetryBudget(0.5, 1)
+	budget.now = func() time.Time { return now }
+
+	// when
+	for i := 0; i < 4; i++ {
+		budget.recordRequest()
+	}
+	withdrawn := 0
+	for budget.withdraw() {
+		withdrawn++
+	}
+	now = now.Add(retryBudgetWindow)
+	minRetriesInNewWindow := budget.withdraw()
+
+	// then
+	assert.Equal(t, 2, withdrawn)
+	assert.True(t, minRetriesInNewWindow)
+}
@@ -31,21 +31,25 @@ func NewRetryableRoundTripper(roundTripper http.RoundTripper, authorizationStrat
 
 func (p *RetryableRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Handle the case when credentials has been changed or OAuth token has expired
-	secondRequestBody, copyErr := copyRequestBody(req)
-	if copyErr != nil {
-		return nil, copyErr
+	getRetryBody, bodyErr := requestBodyForRetry(req)
+	if bodyErr != nil {
+		return nil, bodyErr
 	}
 	resp, err := p.roundTripper.RoundTrip(req)
 	if err != nil {
 		return nil, err
 	}
-	if !p.shouldRetry(resp) {
+	if !p.shouldRetry(resp) || getRetryBody == nil {
 		return resp, err
 	}
 	if req.Context().Err() != nil {
 		return nil, req.Context().Err()
 	}
-	return p.retry(req, secondRequestBody)
+	retryBody, err := getRetryBody()
+	if err != nil {
+		return nil, err
+	}
+	return p.retry(req, retryBody)
 }
 
 func (p *RetryableRoundTripper) shouldRetry(resp *http.Response) bool {
@@ -65,7 +69,12 @@ func (p *RetryableRoundTripper) retry(req *http.Request, retryBody io.ReadCloser
 
 func (p *RetryableRoundTripper) prepareRequest(req *http.Request) (*http.Request, context.CancelFunc) {
 	req.RequestURI = ""
+	if p.timeout == 0 {
+		// the streamed request isn't limited, it must not be cancelled before its response is read
+		return req.WithContext(req.Context()), func() {}
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.timeout)*time.Second)
+	ctx = authorization.WithUserToken(ctx, authorization.UserToken(req.Context()))
 	return req.WithContext(ctx), cancel
 }
 
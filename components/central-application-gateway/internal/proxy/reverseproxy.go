@@ -5,8 +5,10 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf"
+	"github.com/kyma-project/kyma/components/central-application-gateway/internal/metadata/model"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
@@ -15,10 +17,40 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func makeProxy(targetURL string, requestParameters *authorization.RequestParameters, serviceName string, skipVerify bool, authorizationStrategy authorization.Strategy, csrfTokenStrategy csrf.TokenStrategy, clientCertificate clientcert.ClientCertificate, timeout int) (*httputil.ReverseProxy, apperrors.AppError) {
-	roundTripper := httptools.NewRoundTripper(httptools.WithTLSSkipVerify(skipVerify), httptools.WithGetClientCertificate(clientCertificate.GetClientCertificate))
-	retryableRoundTripper := NewRetryableRoundTripper(roundTripper, authorizationStrategy, csrfTokenStrategy, clientCertificate, timeout)
-	return newProxy(targetURL, requestParameters, serviceName, retryableRoundTripper)
+func makeProxy(targetURL string, requestParameters *authorization.RequestParameters, apiIdentifier model.APIIdentifier, skipVerify bool, authorizationStrategy authorization.Strategy, csrfTokenStrategy csrf.TokenStrategy, clientCertificate clientcert.ClientCertificate, timeout int, streaming bool, resilience *apiResilience, retryConfig RetryConfig) (*httputil.ReverseProxy, apperrors.AppError) {
+	roundTripperOptions := []httptools.RoundTripperOption{
+		httptools.WithTLSSkipVerify(skipVerify),
+		httptools.WithGetClientCertificate(clientCertificate.GetClientCertificate),
+	}
+	retryTimeout := timeout
+	if streaming {
+		roundTripperOptions = append(roundTripperOptions,
+			httptools.WithResponseHeaderTimeout(time.Duration(timeout)*time.Second),
+			httptools.WithH2C())
+		retryTimeout = 0
+	}
+	roundTripper := httptools.NewRoundTripper(roundTripperOptions...)
+	retryableRoundTripper := NewRetryableRoundTripper(roundTripper, authorizationStrategy, csrfTokenStrategy, clientCertificate, retryTimeout)
+	resilientRoundTripper := newResilientRoundTripper(retryableRoundTripper, resilience, retryConfig)
+	reverseProxy, err := newProxy(targetURL, requestParameters, apiIdentifier.Service, resilientRoundTripper)
+	if err != nil {
+		return nil, err
+	}
+
+	if streaming {
+		// the Server-Sent Events and the gRPC messages are flushed to the caller immediately
+		reverseProxy.FlushInterval = -1
+	}
+
+	reverseProxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		if isOpenCircuit(err) {
+			respondCircuitOpen(w, apiIdentifier, err)
+			return
+		}
+		log.Errorf("Proxy call for service '%s' failed: %s", apiIdentifier.Service, err.Error())
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	return reverseProxy, nil
 }
 
 func newProxy(targetURL string, requestParameters *authorization.RequestParameters, serviceName string, transport http.RoundTripper) (*httputil.ReverseProxy, apperrors.AppError) {
@@ -0,0 +1,119 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+)
+
+// errSpoolFull is returned when storing the body would exceed the total size of the bodies stored on disk
+var errSpoolFull = errors.New("request body spool is full")
+
+// spool stores the request bodies bigger than the threshold in temporary files in dir, the total size of the files
+// stored at the same time is limited by maxSize if it's positive
+type spool struct {
+	threshold int64
+	dir       string
+	maxSize   int64
+	// size is the total size of the stored files, it's accessed atomically
+	size int64
+}
+
+func newSpool(threshold int64, dir string, maxSize int64) *spool {
+	return &spool{
+		threshold: threshold,
+		dir:       dir,
+		maxSize:   maxSize,
+	}
+}
+
+// reserve adds n bytes to the total size of the stored files, it fails if the total size would exceed maxSize
+func (s *spool) reserve(n int64) bool {
+	if atomic.AddInt64(&s.size, n) > s.maxSize && s.maxSize > 0 {
+		atomic.AddInt64(&s.size, -n)
+		return false
+	}
+	return true
+}
+
+func (s *spool) release(n int64) {
+	atomic.AddInt64(&s.size, -n)
+}
+
+// spooledBody stores the request body for the retries, the bodies bigger than the threshold are stored in
+// a temporary file instead of the memory
+type spooledBody struct {
+	data  []byte
+	file  *os.File
+	size  int64
+	spool *spool
+}
+
+// spoolBody reads the body, it keeps the whole body in the memory if the threshold isn't positive
+func (s *spool) spoolBody(body io.Reader) (*spooledBody, error) {
+	if s.threshold <= 0 {
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		return &spooledBody{data: data, size: int64(len(data))}, nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, body, s.threshold+1); err != nil {
+		if err != io.EOF {
+			return nil, err
+		}
+		return &spooledBody{data: buf.Bytes(), size: int64(buf.Len())}, nil
+	}
+
+	file, err := ioutil.TempFile(s.dir, "request-body-")
+	if err != nil {
+		return nil, err
+	}
+	spooled := &spooledBody{file: file, spool: s}
+	if _, err = io.Copy(spooledWriter{spooled}, io.MultiReader(&buf, body)); err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	return spooled, nil
+}
+
+// spooledWriter writes the body to the file within the total size of the spool
+type spooledWriter struct {
+	body *spooledBody
+}
+
+func (w spooledWriter) Write(p []byte) (int, error) {
+	if !w.body.spool.reserve(int64(len(p))) {
+		return 0, errSpoolFull
+	}
+	n, err := w.body.file.Write(p)
+	w.body.size += int64(n)
+	w.body.spool.release(int64(len(p) - n))
+	return n, err
+}
+
+// reader returns the new reader of the whole body, the readers of the body spooled to the file can be used concurrently
+func (b *spooledBody) reader() io.ReadCloser {
+	if b.file != nil {
+		return ioutil.NopCloser(io.NewSectionReader(b.file, 0, b.size))
+	}
+	return ioutil.NopCloser(bytes.NewReader(b.data))
+}
+
+// Close removes the temporary file of the body
+func (b *spooledBody) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	b.spool.release(b.size)
+	closeErr := b.file.Close()
+	if err := os.Remove(b.file.Name()); err != nil {
+		return err
+	}
+	return closeErr
+}
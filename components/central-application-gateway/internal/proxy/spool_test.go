@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpoolBody(t *testing.T) {
+	readAll := func(t *testing.T, body *spooledBody) string {
+		data, err := ioutil.ReadAll(body.reader())
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("should keep body below threshold in memory", func(t *testing.T) {
+		// given
+		dir, err := ioutil.TempDir("", "spool")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		// when
+		body, err := newSpool(4, dir, 0).spoolBody(strings.NewReader("body"))
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, body.file)
+		assert.Equal(t, "body", readAll(t, body))
+		assert.Equal(t, "body", readAll(t, body))
+		assertDirEmpty(t, dir)
+		require.NoError(t, body.Close())
+	})
+
+	t.Run("should spool body above threshold to file", func(t *testing.T) {
+		// given
+		dir, err := ioutil.TempDir("", "spool")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		// when
+		body, err := newSpool(4, dir, 0).spoolBody(strings.NewReader("large body"))
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, body.file)
+		assert.Equal(t, "large body", readAll(t, body))
+		assert.Equal(t, "large body", readAll(t, body))
+
+		require.NoError(t, body.Close())
+		assertDirEmpty(t, dir)
+	})
+
+	t.Run("should keep body in memory when threshold isn't set", func(t *testing.T) {
+		// when
+		body, err := newSpool(0, "", 0).spoolBody(strings.NewReader("large body"))
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, body.file)
+		assert.Equal(t, "large body", readAll(t, body))
+	})
+
+	t.Run("should reject body exceeding total size of spool", func(t *testing.T) {
+		// given
+		dir, err := ioutil.TempDir("", "spool")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		spool := newSpool(4, dir, 16)
+		first, err := spool.spoolBody(strings.NewReader("large body"))
+		require.NoError(t, err)
+
+		// when
+		_, err = spool.spoolBody(strings.NewReader("another large body"))
+
+		// then
+		assert.Equal(t, errSpoolFull, err)
+		assertDirContains(t, dir, 1)
+
+		// when
+		require.NoError(t, first.Close())
+		second, err := spool.spoolBody(strings.NewReader("large body"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "large body", readAll(t, second))
+		require.NoError(t, second.Close())
+		assertDirEmpty(t, dir)
+		assert.Equal(t, int64(0), spool.size)
+	})
+}
+
+func assertDirEmpty(t *testing.T, dir string) {
+	assertDirContains(t, dir, 0)
+}
+
+func assertDirContains(t *testing.T, dir string, count int) {
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, count)
+}
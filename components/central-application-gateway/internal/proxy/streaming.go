@@ -0,0 +1,42 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+type streamingKey struct{}
+
+type connKey struct{}
+
+// ConnContext keeps the connection of the request in its context, so the server timeouts can be relaxed for the streamed
+// requests. It's the http.Server.ConnContext of the proxy servers.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// clearConnDeadlines removes the read and write deadlines set on the connection by the server ReadTimeout and
+// WriteTimeout, the server sets them again for the next request on the connection
+func clearConnDeadlines(ctx context.Context) {
+	if conn, ok := ctx.Value(connKey{}).(net.Conn); ok {
+		_ = conn.SetDeadline(time.Time{})
+	}
+}
+
+// withStreaming marks the context of the request forwarded in the streaming mode, its body isn't buffered
+// so the request can't be repeated
+func withStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamingKey{}, true)
+}
+
+func isStreaming(ctx context.Context) bool {
+	streaming, _ := ctx.Value(streamingKey{}).(bool)
+	return streaming
+}
+
+// hasBody checks if the request has the body, which must be provided again to repeat the request
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
+}
@@ -0,0 +1,238 @@
+package proxy
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	csrfMock "github.com/kyma-project/kyma/components/central-application-gateway/internal/csrf/mocks"
+	metadatamodel "github.com/kyma-project/kyma/components/central-application-gateway/internal/metadata/model"
+	proxyMocks "github.com/kyma-project/kyma/components/central-application-gateway/internal/proxy/mocks"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization"
+	authMock "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestStreaming(t *testing.T) {
+	apiIdentifier := metadatamodel.APIIdentifier{
+		Application: "app",
+		Service:     "service",
+		Entry:       "entry",
+	}
+
+	fakePathExtractor := func(path string) (metadatamodel.APIIdentifier, string, apperrors.AppError) {
+		return apiIdentifier, path, nil
+	}
+
+	newStreamingProxy := func(targetURL string, authStrategyMock *authMock.Strategy, proxyTimeout int) (http.Handler, *csrfMock.TokenStrategy) {
+		apiExtractorMock := &proxyMocks.APIExtractor{}
+		apiExtractorMock.On("Get", apiIdentifier).Return(&metadatamodel.API{
+			TargetUrl:   targetURL,
+			Credentials: &authorization.Credentials{},
+			Streaming:   true,
+		}, nil)
+
+		authStrategyFactoryMock := &authMock.StrategyFactory{}
+		authStrategyFactoryMock.On("Create", mock.Anything).Return(authStrategyMock)
+
+		csrfTokenStrategyMock := &csrfMock.TokenStrategy{}
+		csrfTokenStrategyMock.On("AddCSRFToken", mock.AnythingOfType("*http.Request")).
+			Run(func(args mock.Arguments) {
+				args.Get(0).(*http.Request).Header.Set("X-csrf-token", "csrf-token")
+			}).
+			Return(nil)
+
+		csrfTokenStrategyFactoryMock := &csrfMock.TokenStrategyFactory{}
+		csrfTokenStrategyFactoryMock.On("Create", authStrategyMock, "").Return(csrfTokenStrategyMock)
+
+		return newProxyForTest(apiExtractorMock, authStrategyFactoryMock, csrfTokenStrategyFactoryMock, fakePathExtractor, createProxyConfig(proxyTimeout)), csrfTokenStrategyMock
+	}
+
+	authorizingStrategy := func() *authMock.Strategy {
+		authStrategyMock := &authMock.Strategy{}
+		authStrategyMock.
+			On("AddAuthorization", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("SetClientCertificateFunc")).
+			Run(func(args mock.Arguments) {
+				args.Get(0).(*http.Request).Header.Set("Authorization", "Bearer token")
+			}).
+			Return(nil).Once()
+		return authStrategyMock
+	}
+
+	assertAuthorized := func(t *testing.T, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "csrf-token", r.Header.Get("X-csrf-token"))
+	}
+
+	t.Run("should upgrade WebSocket connection", func(t *testing.T) {
+		// given
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assertAuthorized(t, r)
+			conn, brw, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			defer conn.Close()
+
+			fmt.Fprint(brw, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+			require.NoError(t, brw.Flush())
+
+			message, err := brw.ReadString('\n')
+			require.NoError(t, err)
+			fmt.Fprint(brw, "echo "+message)
+			require.NoError(t, brw.Flush())
+		}))
+		defer ts.Close()
+
+		authStrategyMock := authorizingStrategy()
+		handler, csrfTokenStrategyMock := newStreamingProxy(ts.URL, authStrategyMock, 10)
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		conn, err := net.Dial("tcp", proxyServer.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+
+		// when
+		fmt.Fprint(conn, "GET /socket HTTP/1.1\r\nHost: gateway\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		res, err := http.ReadResponse(reader, nil)
+		require.NoError(t, err)
+
+		fmt.Fprint(conn, "hello\n")
+		message, err := reader.ReadString('\n')
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+		assert.Equal(t, "echo hello\n", message)
+		authStrategyMock.AssertExpectations(t)
+		csrfTokenStrategyMock.AssertExpectations(t)
+	})
+
+	t.Run("should stream Server-Sent Events beyond proxy and server timeouts", func(t *testing.T) {
+		// given
+		firstEventRead := make(chan struct{})
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assertAuthorized(t, r)
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: first\n\n")
+			w.(http.Flusher).Flush()
+
+			<-firstEventRead
+			time.Sleep(1500 * time.Millisecond)
+			fmt.Fprint(w, "data: second\n\n")
+		}))
+		defer ts.Close()
+
+		authStrategyMock := authorizingStrategy()
+		handler, _ := newStreamingProxy(ts.URL, authStrategyMock, 1)
+		proxyServer := httptest.NewUnstartedServer(handler)
+		proxyServer.Config.ReadTimeout = time.Second
+		proxyServer.Config.WriteTimeout = time.Second
+		proxyServer.Config.ConnContext = ConnContext
+		proxyServer.Start()
+		defer proxyServer.Close()
+
+		// when
+		res, err := http.Get(proxyServer.URL + "/events")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		reader := bufio.NewReader(res.Body)
+		first, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		close(firstEventRead)
+		rest, err := ioutil.ReadAll(reader)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "data: first\n", first)
+		assert.Equal(t, "\ndata: second\n\n", string(rest))
+		authStrategyMock.AssertExpectations(t)
+	})
+
+	t.Run("should forward gRPC call over h2c with trailers", func(t *testing.T) {
+		// given
+		ts := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assertAuthorized(t, r)
+			assert.Equal(t, 2, r.ProtoMajor)
+			assert.Equal(t, "trailers", r.Header.Get("Te"))
+
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			w.Header().Set("Trailer", "Grpc-Status")
+			w.Header().Set("Content-Type", "application/grpc")
+			w.Write(body)
+			w.Header().Set("Grpc-Status", "0")
+		}), &http2.Server{}))
+		defer ts.Close()
+
+		authStrategyMock := authorizingStrategy()
+		handler, _ := newStreamingProxy(ts.URL, authStrategyMock, 10)
+		proxyServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+		defer proxyServer.Close()
+
+		client := &http.Client{Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		}}
+
+		req, err := http.NewRequest(http.MethodPost, proxyServer.URL+"/orders.Orders/Get", strings.NewReader("message"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/grpc")
+		req.Header.Set("Te", "trailers")
+
+		// when
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "message", string(body))
+		assert.Equal(t, "0", res.Trailer.Get("Grpc-Status"))
+		authStrategyMock.AssertExpectations(t)
+	})
+
+	t.Run("should not repeat streamed upload when 401 occurred", func(t *testing.T) {
+		// given
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer ts.Close()
+
+		authStrategyMock := authorizingStrategy()
+		handler, _ := newStreamingProxy(ts.URL, authStrategyMock, 10)
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		// when
+		res, err := http.Post(proxyServer.URL+"/upload", "application/octet-stream", strings.NewReader("content"))
+		require.NoError(t, err)
+		res.Body.Close()
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Equal(t, 1, calls)
+		authStrategyMock.AssertExpectations(t)
+		authStrategyMock.AssertNotCalled(t, "Invalidate")
+	})
+}
@@ -27,8 +27,12 @@ type StrategyFactory interface {
 type OAuthClient interface {
 	// GetToken obtains OAuth token
 	GetToken(clientID string, clientSecret string, authURL string, headers, queryParameters *map[string][]string) (string, apperrors.AppError)
+	// GetTokenWithGrant obtains OAuth token with the grant described by the request
+	GetTokenWithGrant(request oauth.TokenRequest) (string, apperrors.AppError)
 	// InvalidateTokenCache resets internal token cache
 	InvalidateTokenCache(clientID string)
+	// InvalidateGrantTokenCache resets internal token cache of the tokens obtained with the grant
+	InvalidateGrantTokenCache(grantType, clientID string)
 }
 
 type authorizationStrategyFactory struct {
@@ -40,7 +44,17 @@ func (asf authorizationStrategyFactory) Create(c *Credentials) Strategy {
 	var strategy Strategy
 
 	if c != nil && c.OAuth != nil {
-		strategy = newOAuthStrategy(asf.oauthClient, c.OAuth.ClientID, c.OAuth.ClientSecret, c.OAuth.URL, c.OAuth.RequestParameters)
+		switch {
+		case c.OAuth.GrantType == oauth.GrantTypeSAML2Bearer:
+			// the user token is exchanged, so it mustn't be passed to the target by the external token strategy
+			return newSAMLBearerStrategy(asf.oauthClient, c.OAuth)
+		case c.OAuth.GrantType == oauth.GrantTypeJWTBearer:
+			strategy = newJWTBearerStrategy(asf.oauthClient, c.OAuth)
+		case c.OAuth.GrantType == oauth.GrantTypePassword || c.OAuth.Certificate != nil:
+			strategy = newOAuthGrantStrategy(asf.oauthClient, c.OAuth)
+		default:
+			strategy = newOAuthStrategy(asf.oauthClient, c.OAuth.ClientID, c.OAuth.ClientSecret, c.OAuth.URL, c.OAuth.RequestParameters)
+		}
 	} else if c != nil && c.BasicAuth != nil {
 		strategy = newBasicAuthStrategy(c.BasicAuth.Username, c.BasicAuth.Password)
 	} else if c != nil && c.CertificateGen != nil {
@@ -55,11 +69,16 @@ func (asf authorizationStrategyFactory) Create(c *Credentials) Strategy {
 // FactoryConfiguration holds factory configuration options
 type FactoryConfiguration struct {
 	OAuthClientTimeout int
+	// TokenCache keeps the OAuth tokens, the tokens are kept in the memory if it isn't set
+	TokenCache tokencache.TokenCache
 }
 
 // NewStrategyFactory creates factory for instantiating Strategy implementations
 func NewStrategyFactory(config FactoryConfiguration) StrategyFactory {
-	cache := tokencache.NewTokenCache()
+	cache := config.TokenCache
+	if cache == nil {
+		cache = tokencache.NewTokenCache()
+	}
 	oauthClient := oauth.NewOauthClient(config.OAuthClientTimeout, cache)
 
 	return authorizationStrategyFactory{oauthClient: oauthClient}
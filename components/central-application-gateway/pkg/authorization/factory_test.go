@@ -7,9 +7,11 @@ import (
 
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
 
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
 	oauthMocks "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/mocks"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -145,6 +147,45 @@ func TestStrategyFactory(t *testing.T) {
 		assert.Equal(t, "Bearer external", authHeader)
 	})
 
+	t.Run("should create SAML bearer strategy exchanging external token", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("GetTokenWithGrant", mock.MatchedBy(func(request oauth.TokenRequest) bool {
+			return request.GrantType == oauth.GrantTypeSAML2Bearer && request.Assertion == "external"
+		})).Return("token", nil)
+
+		factory := authorizationStrategyFactory{oauthClient: oauthClientMock}
+		credentials := &Credentials{
+			OAuth: &OAuth{
+				ClientID:     "clientId",
+				ClientSecret: "clientSecret",
+				URL:          "www.example.com/token",
+				GrantType:    oauth.GrantTypeSAML2Bearer,
+			},
+		}
+
+		// when
+		strategy := factory.Create(credentials)
+
+		// then
+		require.NotNil(t, strategy)
+
+		// given
+		requestWithExternalToken, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		requestWithExternalToken.Header.Set(httpconsts.HeaderAccessToken, "Bearer external")
+
+		// when
+		err = strategy.AddAuthorization(requestWithExternalToken, nil)
+
+		// then
+		authHeader := requestWithExternalToken.Header.Get(httpconsts.HeaderAuthorization)
+		assert.Nil(t, err)
+		assert.Equal(t, "Bearer token", authHeader)
+		assert.Empty(t, requestWithExternalToken.Header.Get(httpconsts.HeaderAccessToken))
+	})
+
 	t.Run("should create certificate gen strategy", func(t *testing.T) {
 		// given
 		oauthClientMock := &oauthMocks.Client{}
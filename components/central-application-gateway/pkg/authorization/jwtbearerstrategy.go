@@ -0,0 +1,79 @@
+package authorization
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
+)
+
+const (
+	assertionTTL         = 5 * time.Minute
+	assertionRenewBefore = time.Minute
+)
+
+// jwtBearerStrategy obtains the token with the JWT assertion signed by the Application, the assertion is reused until
+// it's about to expire
+type jwtBearerStrategy struct {
+	oauthClient OAuthClient
+	oauth       *OAuth
+
+	mutex     sync.Mutex
+	assertion string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+func newJWTBearerStrategy(oauthClient OAuthClient, oauth *OAuth) *jwtBearerStrategy {
+	return &jwtBearerStrategy{
+		oauthClient: oauthClient,
+		oauth:       oauth,
+		now:         time.Now,
+	}
+}
+
+func (j *jwtBearerStrategy) AddAuthorization(r *http.Request, _ clientcert.SetClientCertificateFunc) apperrors.AppError {
+	assertion, err := j.getAssertion()
+	if err != nil {
+		return apperrors.Internal("Failed to sign JWT bearer assertion, %s", err.Error())
+	}
+
+	request, appErr := newTokenRequest(j.oauth)
+	if appErr != nil {
+		return appErr
+	}
+	request.Assertion = assertion
+	request.Subject = j.oauth.Subject
+
+	return addToken(r, j.oauthClient, request)
+}
+
+func (j *jwtBearerStrategy) Invalidate() {
+	j.mutex.Lock()
+	j.assertion = ""
+	j.mutex.Unlock()
+
+	j.oauthClient.InvalidateGrantTokenCache(oauth.GrantTypeJWTBearer, j.oauth.ClientID)
+}
+
+func (j *jwtBearerStrategy) getAssertion() (string, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	now := j.now()
+	if j.assertion != "" && now.Add(assertionRenewBefore).Before(j.expiresAt) {
+		return j.assertion, nil
+	}
+
+	assertion, err := oauth.NewJWTAssertion(j.oauth.SigningKey, j.oauth.ClientID, j.oauth.Subject, j.oauth.URL, now, assertionTTL)
+	if err != nil {
+		return "", err
+	}
+	j.assertion = assertion
+	j.expiresAt = now.Add(assertionTTL)
+
+	return assertion, nil
+}
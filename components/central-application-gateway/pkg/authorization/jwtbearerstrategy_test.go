@@ -0,0 +1,98 @@
+package authorization
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
+	oauthMocks "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/mocks"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTBearerStrategy(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	credentials := &OAuth{
+		URL:        "www.example.com/token",
+		ClientID:   "clientId",
+		GrantType:  oauth.GrantTypeJWTBearer,
+		Subject:    "user",
+		SigningKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+	}
+
+	t.Run("should reuse assertion until it's about to expire", func(t *testing.T) {
+		// given
+		var assertions []string
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("GetTokenWithGrant", mock.MatchedBy(func(request oauth.TokenRequest) bool {
+			return request.GrantType == oauth.GrantTypeJWTBearer && request.ClientID == "clientId" && request.Subject == "user"
+		})).Run(func(args mock.Arguments) {
+			assertions = append(assertions, args.Get(0).(oauth.TokenRequest).Assertion)
+		}).Return("token", nil)
+
+		now := time.Now()
+		strategy := newJWTBearerStrategy(oauthClientMock, credentials)
+		strategy.now = func() time.Time { return now }
+
+		// when
+		for _, elapsed := range []time.Duration{0, 3 * time.Minute, 4*time.Minute + 30*time.Second} {
+			now = now.Add(elapsed)
+			request, err := http.NewRequest("GET", "www.example.com", nil)
+			require.NoError(t, err)
+
+			err = strategy.AddAuthorization(request, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer token", request.Header.Get(httpconsts.HeaderAuthorization))
+		}
+
+		// then
+		require.Len(t, assertions, 3)
+		assert.NotEmpty(t, assertions[0])
+		assert.Equal(t, assertions[0], assertions[1])
+		assert.NotEqual(t, assertions[1], assertions[2])
+	})
+
+	t.Run("should fail with invalid signing key", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+
+		strategy := newJWTBearerStrategy(oauthClientMock, &OAuth{ClientID: "clientId", GrantType: oauth.GrantTypeJWTBearer, SigningKey: []byte("invalid")})
+
+		request, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		err = strategy.AddAuthorization(request, nil)
+
+		// then
+		require.Error(t, err)
+		oauthClientMock.AssertNotCalled(t, "GetTokenWithGrant", mock.Anything)
+	})
+
+	t.Run("should invalidate cache of grant", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("InvalidateGrantTokenCache", oauth.GrantTypeJWTBearer, "clientId").Once()
+
+		strategy := newJWTBearerStrategy(oauthClientMock, credentials)
+
+		// when
+		strategy.Invalidate()
+
+		// then
+		oauthClientMock.AssertExpectations(t)
+	})
+}
@@ -6,6 +6,8 @@ import (
 	apperrors "github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
 
 	mock "github.com/stretchr/testify/mock"
+
+	oauth "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
 )
 
 // OAuthClient is an autogenerated mock type for the OAuthClient type
@@ -36,6 +38,34 @@ func (_m *OAuthClient) GetToken(clientID string, clientSecret string, authURL st
 	return r0, r1
 }
 
+// GetTokenWithGrant provides a mock function with given fields: request
+func (_m *OAuthClient) GetTokenWithGrant(request oauth.TokenRequest) (string, apperrors.AppError) {
+	ret := _m.Called(request)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(oauth.TokenRequest) string); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(oauth.TokenRequest) apperrors.AppError); ok {
+		r1 = rf(request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// InvalidateGrantTokenCache provides a mock function with given fields: grantType, clientID
+func (_m *OAuthClient) InvalidateGrantTokenCache(grantType string, clientID string) {
+	_m.Called(grantType, clientID)
+}
+
 // InvalidateTokenCache provides a mock function with given fields: clientID
 func (_m *OAuthClient) InvalidateTokenCache(clientID string) {
 	_m.Called(clientID)
@@ -30,6 +30,20 @@ type OAuth struct {
 	ClientID string
 	// ClientSecret to use for authorization.
 	ClientSecret string
+	// GrantType is the OAuth 2.0 grant, client credentials are used if it's empty.
+	GrantType string
+	// Username of the resource owner for the password grant.
+	Username string
+	// Password of the resource owner for the password grant.
+	Password string
+	// Certificate authenticates the client with mTLS instead of ClientSecret.
+	Certificate []byte
+	// PrivateKey of the Certificate.
+	PrivateKey []byte
+	// Subject of the JWT bearer assertion.
+	Subject string
+	// SigningKey is the PEM encoded private key signing the JWT bearer assertion.
+	SigningKey []byte
 	// RequestParameters will be used with request send by the Application Gateway.
 	RequestParameters *RequestParameters
 }
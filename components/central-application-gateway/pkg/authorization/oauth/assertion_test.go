@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJWTAssertion(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should sign assertion with RSA key", func(t *testing.T) {
+		// given
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		signingKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+		// when
+		assertion, err := NewJWTAssertion(signingKey, "client", "user", "https://token.example.com", now, 5*time.Minute)
+
+		// then
+		require.NoError(t, err)
+		parts := strings.Split(assertion, ".")
+		require.Len(t, parts, 3)
+		assertSegment(t, parts[0], map[string]interface{}{"alg": "RS256", "typ": "JWT"})
+
+		claims := decodeSegment(t, parts[1])
+		assert.Equal(t, "client", claims["iss"])
+		assert.Equal(t, "user", claims["sub"])
+		assert.Equal(t, "https://token.example.com", claims["aud"])
+		assert.Equal(t, float64(now.Unix()), claims["iat"])
+		assert.Equal(t, float64(now.Add(5*time.Minute).Unix()), claims["exp"])
+		assert.NotEmpty(t, claims["jti"])
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+	})
+
+	t.Run("should sign assertion with ECDSA key", func(t *testing.T) {
+		// given
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		signingKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+		// when
+		assertion, err := NewJWTAssertion(signingKey, "client", "user", "https://token.example.com", now, 5*time.Minute)
+
+		// then
+		require.NoError(t, err)
+		parts := strings.Split(assertion, ".")
+		require.Len(t, parts, 3)
+		assertSegment(t, parts[0], map[string]interface{}{"alg": "ES256", "typ": "JWT"})
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		require.Len(t, signature, 64)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], r, s))
+	})
+
+	t.Run("should fail with invalid key", func(t *testing.T) {
+		// when
+		_, err := NewJWTAssertion([]byte("invalid"), "client", "user", "https://token.example.com", now, 5*time.Minute)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func assertSegment(t *testing.T, segment string, expected map[string]interface{}) {
+	assert.Equal(t, expected, decodeSegment(t, segment))
+}
+
+func decodeSegment(t *testing.T, segment string) map[string]interface{} {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	require.NoError(t, err)
+
+	var value map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &value))
+	return value
+}
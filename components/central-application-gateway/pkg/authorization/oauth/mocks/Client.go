@@ -5,6 +5,8 @@ package mocks
 import (
 	apperrors "github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
 	mock "github.com/stretchr/testify/mock"
+
+	oauth "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -35,6 +37,29 @@ func (_m *Client) GetToken(clientID string, clientSecret string, authURL string,
 	return r0, r1
 }
 
+// GetTokenWithGrant provides a mock function with given fields: request
+func (_m *Client) GetTokenWithGrant(request oauth.TokenRequest) (string, apperrors.AppError) {
+	ret := _m.Called(request)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(oauth.TokenRequest) string); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(oauth.TokenRequest) apperrors.AppError); ok {
+		r1 = rf(request)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // InvalidateAndRetry provides a mock function with given fields: clientID, clientSecret, authURL, headers, queryParameters
 func (_m *Client) InvalidateAndRetry(clientID string, clientSecret string, authURL string, headers *map[string][]string, queryParameters *map[string][]string) (string, apperrors.AppError) {
 	ret := _m.Called(clientID, clientSecret, authURL, headers, queryParameters)
@@ -58,6 +83,11 @@ func (_m *Client) InvalidateAndRetry(clientID string, clientSecret string, authU
 	return r0, r1
 }
 
+// InvalidateGrantTokenCache provides a mock function with given fields: grantType, clientID
+func (_m *Client) InvalidateGrantTokenCache(grantType string, clientID string) {
+	_m.Called(grantType, clientID)
+}
+
 // InvalidateTokenCache provides a mock function with given fields: clientID
 func (_m *Client) InvalidateTokenCache(clientID string) {
 	_m.Called(clientID)
@@ -2,8 +2,11 @@ package oauth
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -15,6 +18,7 @@ import (
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/util"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httptools"
+	"golang.org/x/sync/singleflight"
 )
 
 type oauthResponse struct {
@@ -24,15 +28,78 @@ type oauthResponse struct {
 	Scope       string `json:"scope"`
 }
 
+// The OAuth 2.0 grants supported by the Client
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypePassword          = "password"
+	GrantTypeJWTBearer         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	GrantTypeSAML2Bearer       = "urn:ietf:params:oauth:grant-type:saml2-bearer"
+)
+
+// TokenRequest describes the token obtained with one of the supported grants
+type TokenRequest struct {
+	// GrantType is the OAuth 2.0 grant, it's client credentials if it's not set
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	// Username and Password are the resource owner credentials of the password grant
+	Username string
+	Password string
+	// Assertion is the JWT or SAML 2.0 assertion of the bearer grants
+	Assertion string
+	// Subject identifies the owner of the token in the token cache, it's empty for the tokens of the client itself
+	Subject string
+	// Certificate authenticates the client with mTLS instead of the client secret (tls_client_auth)
+	Certificate     *tls.Certificate
+	Headers         *map[string][]string
+	QueryParameters *map[string][]string
+}
+
+func (tr TokenRequest) grantType() string {
+	if tr.GrantType == "" {
+		return GrantTypeClientCredentials
+	}
+	return tr.GrantType
+}
+
+// cacheKey identifies the token in the token cache and the fetches of the same token. The tokens of the same grant and
+// client differ in the authorization server, the client certificate and the subject, so they are all part of the key.
+func (tr TokenRequest) cacheKey() string {
+	hash := sha256.New()
+	hash.Write([]byte(tr.AuthURL))
+	hash.Write([]byte{0})
+	if tr.Certificate != nil && len(tr.Certificate.Certificate) > 0 {
+		fingerprint := sha256.Sum256(tr.Certificate.Certificate[0])
+		hash.Write(fingerprint[:])
+	}
+	hash.Write([]byte{0})
+	hash.Write([]byte(tr.Subject))
+
+	return tokenCacheKey(tr.grantType(), tr.ClientID) + hex.EncodeToString(hash.Sum(nil))
+}
+
+// tokenCacheKey is the prefix of the keys of all tokens obtained by the client with the grant
+func tokenCacheKey(grantType, clientID string) string {
+	return fmt.Sprintf("%s/%s/", grantType, clientID)
+}
+
 type Client interface {
 	GetToken(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) (string, apperrors.AppError)
+	// GetTokenWithGrant obtains the token described by the request, the tokens are cached per grant, client,
+	// authorization server, client certificate and subject
+	GetTokenWithGrant(request TokenRequest) (string, apperrors.AppError)
 	InvalidateAndRetry(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) (string, apperrors.AppError)
 	InvalidateTokenCache(clientID string)
+	// InvalidateGrantTokenCache removes the tokens obtained by the client with the grant for all subjects
+	InvalidateGrantTokenCache(grantType, clientID string)
 }
 
 type client struct {
 	timeoutDuration int
 	tokenCache      tokencache.TokenCache
+	// fetches merges the concurrent fetches of the same token into one
+	fetches singleflight.Group
 }
 
 func NewOauthClient(timeoutDuration int, tokenCache tokencache.TokenCache) Client {
@@ -43,59 +110,99 @@ func NewOauthClient(timeoutDuration int, tokenCache tokencache.TokenCache) Clien
 }
 
 func (c *client) GetToken(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) (string, apperrors.AppError) {
-	token, found := c.tokenCache.Get(clientID)
+	return c.GetTokenWithGrant(clientCredentialsRequest(clientID, clientSecret, authURL, headers, queryParameters))
+}
+
+func (c *client) GetTokenWithGrant(request TokenRequest) (string, apperrors.AppError) {
+	token, found := c.tokenCache.Get(request.cacheKey())
 	if found {
 		return token, nil
 	}
 
-	tokenResponse, err := c.requestToken(clientID, clientSecret, authURL, headers, queryParameters)
-	if err != nil {
-		return "", err
-	}
+	return c.fetchToken(request)
+}
 
-	c.tokenCache.Add(clientID, tokenResponse.AccessToken, tokenResponse.ExpiresIn)
+func (c *client) InvalidateAndRetry(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) (string, apperrors.AppError) {
+	request := clientCredentialsRequest(clientID, clientSecret, authURL, headers, queryParameters)
+	c.tokenCache.Remove(request.cacheKey())
 
-	return tokenResponse.AccessToken, nil
+	return c.fetchToken(request)
 }
 
-func (c *client) InvalidateAndRetry(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) (string, apperrors.AppError) {
-	c.tokenCache.Remove(clientID)
+func (c *client) InvalidateTokenCache(clientID string) {
+	c.tokenCache.RemoveWithPrefix(tokenCacheKey(GrantTypeClientCredentials, clientID))
+}
+
+func (c *client) InvalidateGrantTokenCache(grantType, clientID string) {
+	c.tokenCache.RemoveWithPrefix(tokenCacheKey(grantType, clientID))
+}
+
+// fetchToken requests the token and caches it, the concurrent calls for the same token share a single request
+func (c *client) fetchToken(request TokenRequest) (string, apperrors.AppError) {
+	token, err, _ := c.fetches.Do(request.cacheKey(), func() (interface{}, error) {
+		tokenResponse, err := c.requestToken(request)
+		if err != nil {
+			return nil, err
+		}
 
-	tokenResponse, err := c.requestToken(clientID, clientSecret, authURL, headers, queryParameters)
+		c.tokenCache.Add(request.cacheKey(), tokenResponse.AccessToken, tokenResponse.ExpiresIn)
+
+		return tokenResponse.AccessToken, nil
+	})
 	if err != nil {
-		return "", err
+		return "", err.(apperrors.AppError)
 	}
 
-	c.tokenCache.Add(clientID, tokenResponse.AccessToken, tokenResponse.ExpiresIn)
-
-	return tokenResponse.AccessToken, nil
+	return token.(string), nil
 }
 
-func (c *client) InvalidateTokenCache(clientID string) {
-	c.tokenCache.Remove(clientID)
+func clientCredentialsRequest(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) TokenRequest {
+	return TokenRequest{
+		GrantType:       GrantTypeClientCredentials,
+		ClientID:        clientID,
+		ClientSecret:    clientSecret,
+		AuthURL:         authURL,
+		Headers:         headers,
+		QueryParameters: queryParameters,
+	}
 }
 
-func (c *client) requestToken(clientID, clientSecret, authURL string, headers, queryParameters *map[string][]string) (*oauthResponse, apperrors.AppError) {
+func (c *client) requestToken(request TokenRequest) (*oauthResponse, apperrors.AppError) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if request.Certificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*request.Certificate}
+	}
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig: tlsConfig,
 	}
 	client := &http.Client{Transport: transport}
 
 	form := url.Values{}
-	form.Add("client_id", clientID)
-	form.Add("client_secret", clientSecret)
-	form.Add("grant_type", "client_credentials")
+	form.Add("client_id", request.ClientID)
+	if request.Certificate == nil {
+		form.Add("client_secret", request.ClientSecret)
+	}
+	form.Add("grant_type", request.grantType())
+	switch request.grantType() {
+	case GrantTypePassword:
+		form.Add("username", request.Username)
+		form.Add("password", request.Password)
+	case GrantTypeJWTBearer, GrantTypeSAML2Bearer:
+		form.Add("assertion", request.Assertion)
+	}
 
-	req, err := http.NewRequest(http.MethodPost, authURL, strings.NewReader(form.Encode()))
+	req, err := http.NewRequest(http.MethodPost, request.AuthURL, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, apperrors.Internal("failed to create token request: %s", err.Error())
 	}
 
-	util.AddBasicAuthHeader(req, clientID, clientSecret)
+	if request.Certificate == nil {
+		util.AddBasicAuthHeader(req, request.ClientID, request.ClientSecret)
+	}
 	req.Header.Add(httpconsts.HeaderContentType, httpconsts.ContentTypeApplicationURLEncoded)
 
-	setCustomQueryParameters(req.URL, queryParameters)
-	setCustomHeaders(req.Header, headers)
+	setCustomQueryParameters(req.URL, request.QueryParameters)
+	setCustomHeaders(req.Header, request.Headers)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.timeoutDuration)*time.Second)
 	defer cancel()
@@ -103,17 +210,17 @@ func (c *client) requestToken(clientID, clientSecret, authURL string, headers, q
 
 	response, err := client.Do(requestWithContext)
 	if err != nil {
-		return nil, apperrors.UpstreamServerCallFailed("failed to make a request to '%s': %s", authURL, err.Error())
+		return nil, apperrors.UpstreamServerCallFailed("failed to make a request to '%s': %s", request.AuthURL, err.Error())
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, apperrors.UpstreamServerCallFailed("incorrect response code '%d' while getting token from %s", response.StatusCode, authURL)
+		return nil, apperrors.UpstreamServerCallFailed("incorrect response code '%d' while getting token from %s", response.StatusCode, request.AuthURL)
 	}
 
 	body, err := ioutil.ReadAll(response.Body)
 	defer response.Body.Close()
 	if err != nil {
-		return nil, apperrors.UpstreamServerCallFailed("failed to read token response body from '%s': %s", authURL, err.Error())
+		return nil, apperrors.UpstreamServerCallFailed("failed to read token response body from '%s': %s", request.AuthURL, err.Error())
 	}
 
 	tokenResponse := &oauthResponse{}
@@ -1,15 +1,25 @@
 package oauth
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
 
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/tokencache"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/tokencache/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,7 +29,7 @@ func TestOauthClient_GetToken(t *testing.T) {
 	t.Run("should get token from cache if present", func(t *testing.T) {
 		// given
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Get", "testID").Return("123456789", true)
+		tokenCache.On("Get", clientCredentialsKey("")).Return("123456789", true)
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -46,8 +56,8 @@ func TestOauthClient_GetToken(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Get", "testID").Return("", false)
-		tokenCache.On("Add", "testID", "123456789", 3600).Return()
+		tokenCache.On("Get", clientCredentialsKey(ts.URL)).Return("", false)
+		tokenCache.On("Add", clientCredentialsKey(ts.URL), "123456789", 3600).Return()
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -82,8 +92,8 @@ func TestOauthClient_GetToken(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Get", "testID").Return("", false)
-		tokenCache.On("Add", "testID", "123456789", 3600).Return()
+		tokenCache.On("Get", clientCredentialsKey(ts.URL)).Return("", false)
+		tokenCache.On("Add", clientCredentialsKey(ts.URL), "123456789", 3600).Return()
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -105,7 +115,7 @@ func TestOauthClient_GetToken(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Get", "testID").Return("", false)
+		tokenCache.On("Get", clientCredentialsKey(ts.URL)).Return("", false)
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -129,7 +139,7 @@ func TestOauthClient_GetToken(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Get", "testID").Return("", false)
+		tokenCache.On("Get", clientCredentialsKey(ts.URL)).Return("", false)
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -145,7 +155,7 @@ func TestOauthClient_GetToken(t *testing.T) {
 	t.Run("should fail if OAuth address is incorrect", func(t *testing.T) {
 		// given
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Get", "testID").Return("", false)
+		tokenCache.On("Get", clientCredentialsKey("http://some_no_existent_address.com/token")).Return("", false)
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -157,6 +167,43 @@ func TestOauthClient_GetToken(t *testing.T) {
 		assert.Equal(t, "", token)
 		tokenCache.AssertExpectations(t)
 	})
+
+	t.Run("should fetch token once for concurrent requests", func(t *testing.T) {
+		// given
+		var fetches int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&fetches, 1)
+			time.Sleep(100 * time.Millisecond)
+
+			response := oauthResponse{AccessToken: "123456789", TokenType: "bearer", ExpiresIn: 3600, Scope: "basic"}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(response)
+		}))
+		defer ts.Close()
+
+		oauthClient := NewOauthClient(10, tokencache.NewTokenCache())
+
+		// when
+		var wg sync.WaitGroup
+		tokens := make([]string, 10)
+		errs := make([]error, 10)
+		for i := range tokens {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				tokens[i], errs[i] = oauthClient.GetToken("testID", "testSecret", ts.URL, nil, nil)
+			}(i)
+		}
+		wg.Wait()
+
+		// then
+		for i := range tokens {
+			require.NoError(t, errs[i])
+			assert.Equal(t, "123456789", tokens[i])
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
 }
 
 func TestOauthClient_InvalidateAndRetry(t *testing.T) {
@@ -174,8 +221,8 @@ func TestOauthClient_InvalidateAndRetry(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Remove", "testID")
-		tokenCache.On("Add", "testID", "123456789", 3600).Return()
+		tokenCache.On("Remove", clientCredentialsKey(ts.URL))
+		tokenCache.On("Add", clientCredentialsKey(ts.URL), "123456789", 3600).Return()
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -199,7 +246,7 @@ func TestOauthClient_InvalidateAndRetry(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Remove", "testID")
+		tokenCache.On("Remove", clientCredentialsKey(ts.URL))
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -223,7 +270,7 @@ func TestOauthClient_InvalidateAndRetry(t *testing.T) {
 		defer ts.Close()
 
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Remove", "testID")
+		tokenCache.On("Remove", clientCredentialsKey(ts.URL))
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -239,7 +286,7 @@ func TestOauthClient_InvalidateAndRetry(t *testing.T) {
 	t.Run("should fail if OAuth address is incorrect", func(t *testing.T) {
 		// given
 		tokenCache := mocks.TokenCache{}
-		tokenCache.On("Remove", "testID")
+		tokenCache.On("Remove", clientCredentialsKey("http://some_no_existent_address.com/token"))
 
 		oauthClient := NewOauthClient(10, &tokenCache)
 
@@ -253,6 +300,251 @@ func TestOauthClient_InvalidateAndRetry(t *testing.T) {
 	})
 }
 
+func TestOauthClient_GetTokenWithGrant(t *testing.T) {
+	t.Run("should fetch token with password grant", func(t *testing.T) {
+		// given
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := r.ParseForm()
+			require.NoError(t, err)
+
+			assert.Equal(t, GrantTypePassword, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "testID", r.PostForm.Get("client_id"))
+			assert.Equal(t, "testSecret", r.PostForm.Get("client_secret"))
+			assert.Equal(t, "user", r.PostForm.Get("username"))
+			assert.Equal(t, "pass", r.PostForm.Get("password"))
+
+			response := oauthResponse{AccessToken: "123456789", TokenType: "bearer", ExpiresIn: 3600, Scope: "basic"}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(response)
+		}))
+		defer ts.Close()
+
+		tokenCache := mocks.TokenCache{}
+		key := TokenRequest{GrantType: GrantTypePassword, ClientID: "testID", AuthURL: ts.URL, Subject: "user"}.cacheKey()
+		tokenCache.On("Get", key).Return("", false)
+		tokenCache.On("Add", key, "123456789", 3600).Return()
+
+		oauthClient := NewOauthClient(10, &tokenCache)
+
+		// when
+		token, err := oauthClient.GetTokenWithGrant(TokenRequest{
+			GrantType:    GrantTypePassword,
+			ClientID:     "testID",
+			ClientSecret: "testSecret",
+			AuthURL:      ts.URL,
+			Username:     "user",
+			Password:     "pass",
+			Subject:      "user",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "123456789", token)
+		tokenCache.AssertExpectations(t)
+	})
+
+	t.Run("should cache token of password grant per user", func(t *testing.T) {
+		// given
+		var fetches int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&fetches, 1)
+			err := r.ParseForm()
+			require.NoError(t, err)
+
+			response := oauthResponse{AccessToken: "token-" + r.PostForm.Get("username"), TokenType: "bearer", ExpiresIn: 3600, Scope: "basic"}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(response)
+		}))
+		defer ts.Close()
+
+		oauthClient := NewOauthClient(10, tokencache.NewTokenCache())
+		request := func(username string) TokenRequest {
+			return TokenRequest{
+				GrantType:    GrantTypePassword,
+				ClientID:     "testID",
+				ClientSecret: "testSecret",
+				AuthURL:      ts.URL,
+				Username:     username,
+				Password:     "pass",
+				Subject:      username,
+			}
+		}
+
+		// when
+		var tokens []string
+		for _, username := range []string{"alice", "bob", "alice", "bob"} {
+			token, err := oauthClient.GetTokenWithGrant(request(username))
+			require.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+
+		// then
+		assert.Equal(t, []string{"token-alice", "token-bob", "token-alice", "token-bob"}, tokens)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("should cache token of bearer grant per subject", func(t *testing.T) {
+		// given
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := r.ParseForm()
+			require.NoError(t, err)
+
+			assert.Equal(t, GrantTypeSAML2Bearer, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "assertion", r.PostForm.Get("assertion"))
+
+			response := oauthResponse{AccessToken: "123456789", TokenType: "bearer", ExpiresIn: 3600, Scope: "basic"}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(response)
+		}))
+		defer ts.Close()
+
+		tokenCache := mocks.TokenCache{}
+		key := TokenRequest{GrantType: GrantTypeSAML2Bearer, ClientID: "testID", AuthURL: ts.URL, Subject: "subject"}.cacheKey()
+		tokenCache.On("Get", key).Return("", false)
+		tokenCache.On("Add", key, "123456789", 3600).Return()
+
+		oauthClient := NewOauthClient(10, &tokenCache)
+
+		// when
+		token, err := oauthClient.GetTokenWithGrant(TokenRequest{
+			GrantType:    GrantTypeSAML2Bearer,
+			ClientID:     "testID",
+			ClientSecret: "testSecret",
+			AuthURL:      ts.URL,
+			Assertion:    "assertion",
+			Subject:      "subject",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "123456789", token)
+		tokenCache.AssertExpectations(t)
+	})
+
+	t.Run("should authenticate client with certificate", func(t *testing.T) {
+		// given
+		cert := generateCertificate(t)
+
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := r.ParseForm()
+			require.NoError(t, err)
+
+			require.Len(t, r.TLS.PeerCertificates, 1)
+			assert.Equal(t, "testID", r.TLS.PeerCertificates[0].Subject.CommonName)
+			assert.Equal(t, "testID", r.PostForm.Get("client_id"))
+			assert.Empty(t, r.PostForm.Get("client_secret"))
+			assert.Empty(t, r.Header.Get(httpconsts.HeaderAuthorization))
+
+			response := oauthResponse{AccessToken: "123456789", TokenType: "bearer", ExpiresIn: 3600, Scope: "basic"}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(response)
+		}))
+		ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		ts.StartTLS()
+		defer ts.Close()
+
+		request := TokenRequest{
+			ClientID:    "testID",
+			AuthURL:     ts.URL,
+			Certificate: &cert,
+		}
+
+		tokenCache := mocks.TokenCache{}
+		tokenCache.On("Get", request.cacheKey()).Return("", false)
+		tokenCache.On("Add", request.cacheKey(), "123456789", 3600).Return()
+
+		oauthClient := NewOauthClient(10, &tokenCache)
+
+		// when
+		token, err := oauthClient.GetTokenWithGrant(request)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "123456789", token)
+		tokenCache.AssertExpectations(t)
+	})
+}
+
+func TestOauthClient_InvalidateGrantTokenCache(t *testing.T) {
+	// given
+	tokenCache := mocks.TokenCache{}
+	tokenCache.On("RemoveWithPrefix", GrantTypeSAML2Bearer+"/testID/").Return()
+
+	oauthClient := NewOauthClient(10, &tokenCache)
+
+	// when
+	oauthClient.InvalidateGrantTokenCache(GrantTypeSAML2Bearer, "testID")
+
+	// then
+	tokenCache.AssertExpectations(t)
+}
+
+func TestOauthClient_InvalidateTokenCache(t *testing.T) {
+	// given
+	tokenCache := mocks.TokenCache{}
+	tokenCache.On("RemoveWithPrefix", GrantTypeClientCredentials+"/testID/").Return()
+
+	oauthClient := NewOauthClient(10, &tokenCache)
+
+	// when
+	oauthClient.InvalidateTokenCache("testID")
+
+	// then
+	tokenCache.AssertExpectations(t)
+}
+
+func TestTokenRequest_cacheKey(t *testing.T) {
+	cert := generateCertificate(t)
+	otherCert := generateCertificate(t)
+	request := TokenRequest{
+		GrantType:   GrantTypePassword,
+		ClientID:    "testID",
+		AuthURL:     "https://auth.example.com/token",
+		Subject:     "user",
+		Certificate: &cert,
+	}
+
+	for testName, testData := range map[string]struct {
+		modify func(request *TokenRequest)
+
+		expectedSameKey bool
+	}{
+		"should be the same for the same token": {
+			modify:          func(request *TokenRequest) { request.Password = "other" },
+			expectedSameKey: true,
+		},
+		"should differ for different subjects": {
+			modify: func(request *TokenRequest) { request.Subject = "other" },
+		},
+		"should differ for different authorization servers": {
+			modify: func(request *TokenRequest) { request.AuthURL = "https://other.example.com/token" },
+		},
+		"should differ for different client certificates": {
+			modify: func(request *TokenRequest) { request.Certificate = &otherCert },
+		},
+		"should differ without client certificate": {
+			modify: func(request *TokenRequest) { request.Certificate = nil },
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			other := request
+			testData.modify(&other)
+
+			// when
+			key, otherKey := request.cacheKey(), other.cacheKey()
+
+			// then
+			assert.True(t, strings.HasPrefix(otherKey, tokenCacheKey(GrantTypePassword, "testID")))
+			assert.Equal(t, testData.expectedSameKey, key == otherKey)
+		})
+	}
+}
+
 func checkAccessTokenRequest(t *testing.T, r *http.Request) {
 	err := r.ParseForm()
 	require.NoError(t, err)
@@ -274,3 +566,24 @@ func checkAccessTokenRequestAdditionalRequestParameters(t *testing.T, r *http.Re
 	assert.Equal(t, []string{"queryParameterValue"}, r.URL.Query()["queryParameterKey"])
 	assert.Equal(t, "headerValue", r.Header.Get("headerKey"))
 }
+
+func generateCertificate(t *testing.T) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "testID"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func clientCredentialsKey(authURL string) string {
+	return clientCredentialsRequest("testID", "testSecret", authURL, nil, nil).cacheKey()
+}
@@ -9,25 +9,25 @@ type TokenCache struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: clientID, token, expirationSeconds
-func (_m *TokenCache) Add(clientID string, token string, expirationSeconds int) {
-	_m.Called(clientID, token, expirationSeconds)
+// Add provides a mock function with given fields: key, token, expirationSeconds
+func (_m *TokenCache) Add(key string, token string, expirationSeconds int) {
+	_m.Called(key, token, expirationSeconds)
 }
 
-// Get provides a mock function with given fields: clientID
-func (_m *TokenCache) Get(clientID string) (string, bool) {
-	ret := _m.Called(clientID)
+// Get provides a mock function with given fields: key
+func (_m *TokenCache) Get(key string) (string, bool) {
+	ret := _m.Called(key)
 
 	var r0 string
 	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(clientID)
+		r0 = rf(key)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 bool
 	if rf, ok := ret.Get(1).(func(string) bool); ok {
-		r1 = rf(clientID)
+		r1 = rf(key)
 	} else {
 		r1 = ret.Get(1).(bool)
 	}
@@ -35,7 +35,12 @@ func (_m *TokenCache) Get(clientID string) (string, bool) {
 	return r0, r1
 }
 
-// Remove provides a mock function with given fields: clientID
-func (_m *TokenCache) Remove(clientID string) {
-	_m.Called(clientID)
+// Remove provides a mock function with given fields: key
+func (_m *TokenCache) Remove(key string) {
+	_m.Called(key)
+}
+
+// RemoveWithPrefix provides a mock function with given fields: prefix
+func (_m *TokenCache) RemoveWithPrefix(prefix string) {
+	_m.Called(prefix)
 }
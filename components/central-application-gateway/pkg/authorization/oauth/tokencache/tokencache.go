@@ -3,38 +3,49 @@ package tokencache
 import (
 	"time"
 
-	cache "github.com/patrickmn/go-cache"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/sharedcache"
 )
 
+// keyPrefix separates the OAuth tokens from other items of the shared cache
+const keyPrefix = "oauth/"
+
+// TokenCache keeps the OAuth tokens until they expire, the keys identify the grant, client and subject of the tokens
 type TokenCache interface {
-	Get(clientID string) (token string, found bool)
-	Add(clientID, token string, expirationSeconds int)
-	Remove(clientID string)
+	Get(key string) (token string, found bool)
+	Add(key, token string, expirationSeconds int)
+	Remove(key string)
+	// RemoveWithPrefix removes the tokens with the keys starting with the prefix, e.g. all users' tokens of the client
+	RemoveWithPrefix(prefix string)
 }
 
 type tokenCache struct {
-	cache *cache.Cache
+	cache *sharedcache.Cache
 }
 
+// NewTokenCache creates the cache keeping the tokens in the memory of the replica
 func NewTokenCache() TokenCache {
+	return NewSharedTokenCache(sharedcache.New(sharedcache.NewMemoryBackend(), sharedcache.Config{}))
+}
+
+// NewSharedTokenCache creates the cache keeping the tokens in the cache, which can be shared by the gateway replicas
+func NewSharedTokenCache(cache *sharedcache.Cache) TokenCache {
 	return &tokenCache{
-		cache: cache.New(cache.NoExpiration, cache.NoExpiration),
+		cache: cache,
 	}
 }
 
-func (tc *tokenCache) Get(clientId string) (token string, found bool) {
-	res, found := tc.cache.Get(clientId)
-	if !found {
-		return "", false
-	}
+func (tc *tokenCache) Get(key string) (token string, found bool) {
+	return tc.cache.Get(keyPrefix + key)
+}
 
-	return res.(string), found
+func (tc *tokenCache) Add(key, token string, expirationSeconds int) {
+	tc.cache.Set(keyPrefix+key, token, time.Duration(expirationSeconds-2)*time.Second)
 }
 
-func (tc *tokenCache) Add(clientID, token string, expirationSeconds int) {
-	tc.cache.Set(clientID, token, time.Duration(expirationSeconds-2)*time.Second)
+func (tc *tokenCache) Remove(key string) {
+	tc.cache.Delete(keyPrefix + key)
 }
 
-func (tc *tokenCache) Remove(clientID string) {
-	tc.cache.Delete(clientID)
+func (tc *tokenCache) RemoveWithPrefix(prefix string) {
+	tc.cache.DeleteWithPrefix(keyPrefix + prefix)
 }
@@ -68,3 +68,22 @@ func TestTokenCache(t *testing.T) {
 		assert.Equal(t, "", token)
 	})
 }
+
+func TestTokenCache_RemoveWithPrefix(t *testing.T) {
+	// given
+	tokenCache := NewTokenCache()
+	tokenCache.Add("saml2-bearer/client/user-1", cachedToken, 3600)
+	tokenCache.Add("saml2-bearer/client/user-2", cachedToken, 3600)
+	tokenCache.Add("saml2-bearer/other/user-1", cachedToken, 3600)
+
+	// when
+	tokenCache.RemoveWithPrefix("saml2-bearer/client/")
+
+	// then
+	_, found := tokenCache.Get("saml2-bearer/client/user-1")
+	assert.False(t, found)
+	_, found = tokenCache.Get("saml2-bearer/client/user-2")
+	assert.False(t, found)
+	_, found = tokenCache.Get("saml2-bearer/other/user-1")
+	assert.True(t, found)
+}
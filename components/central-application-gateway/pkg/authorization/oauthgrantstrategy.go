@@ -0,0 +1,86 @@
+package authorization
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
+	log "github.com/sirupsen/logrus"
+)
+
+// oauthGrantStrategy obtains the token with the password grant or with the client credentials of the client
+// authenticated with mTLS
+type oauthGrantStrategy struct {
+	oauthClient OAuthClient
+	oauth       *OAuth
+}
+
+func newOAuthGrantStrategy(oauthClient OAuthClient, oauth *OAuth) oauthGrantStrategy {
+	return oauthGrantStrategy{
+		oauthClient: oauthClient,
+		oauth:       oauth,
+	}
+}
+
+func (o oauthGrantStrategy) AddAuthorization(r *http.Request, _ clientcert.SetClientCertificateFunc) apperrors.AppError {
+	request, err := newTokenRequest(o.oauth)
+	if err != nil {
+		return err
+	}
+	request.Username = o.oauth.Username
+	request.Password = o.oauth.Password
+	if request.GrantType == oauth.GrantTypePassword {
+		request.Subject = o.oauth.Username
+	}
+
+	return addToken(r, o.oauthClient, request)
+}
+
+func (o oauthGrantStrategy) Invalidate() {
+	o.oauthClient.InvalidateGrantTokenCache(grantTypeOrDefault(o.oauth.GrantType), o.oauth.ClientID)
+}
+
+func newTokenRequest(o *OAuth) (oauth.TokenRequest, apperrors.AppError) {
+	headers, queryParameters := o.RequestParameters.unpack()
+	request := oauth.TokenRequest{
+		GrantType:       grantTypeOrDefault(o.GrantType),
+		ClientID:        o.ClientID,
+		ClientSecret:    o.ClientSecret,
+		AuthURL:         o.URL,
+		Headers:         headers,
+		QueryParameters: queryParameters,
+	}
+
+	if o.Certificate != nil {
+		cert, err := tls.X509KeyPair(o.Certificate, o.PrivateKey)
+		if err != nil {
+			return oauth.TokenRequest{}, apperrors.Internal("Failed to prepare certificate, %s", err.Error())
+		}
+		request.Certificate = &cert
+	}
+
+	return request, nil
+}
+
+func addToken(r *http.Request, oauthClient OAuthClient, request oauth.TokenRequest) apperrors.AppError {
+	token, err := oauthClient.GetTokenWithGrant(request)
+	if err != nil {
+		log.Errorf("failed to get token : '%s'", err)
+		return err
+	}
+
+	r.Header.Set(httpconsts.HeaderAuthorization, fmt.Sprintf("Bearer %s", token))
+
+	return nil
+}
+
+func grantTypeOrDefault(grantType string) string {
+	if grantType == "" {
+		return oauth.GrantTypeClientCredentials
+	}
+	return grantType
+}
@@ -0,0 +1,113 @@
+package authorization
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
+	oauthMocks "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/mocks"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthGrantStrategy(t *testing.T) {
+
+	t.Run("should add Authorization header with token of password grant", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("GetTokenWithGrant", oauth.TokenRequest{
+			GrantType:    oauth.GrantTypePassword,
+			ClientID:     "clientId",
+			ClientSecret: "clientSecret",
+			AuthURL:      "www.example.com/token",
+			Username:     "username",
+			Password:     "password",
+			Subject:      "username",
+		}).Return("token", nil).Once()
+
+		strategy := newOAuthGrantStrategy(oauthClientMock, &OAuth{
+			URL:          "www.example.com/token",
+			ClientID:     "clientId",
+			ClientSecret: "clientSecret",
+			GrantType:    oauth.GrantTypePassword,
+			Username:     "username",
+			Password:     "password",
+		})
+
+		request, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		err = strategy.AddAuthorization(request, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token", request.Header.Get(httpconsts.HeaderAuthorization))
+		oauthClientMock.AssertExpectations(t)
+	})
+
+	t.Run("should obtain token with client certificate", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("GetTokenWithGrant", mock.MatchedBy(func(request oauth.TokenRequest) bool {
+			return request.GrantType == oauth.GrantTypeClientCredentials && request.Certificate != nil
+		})).Return("token", nil).Once()
+
+		strategy := newOAuthGrantStrategy(oauthClientMock, &OAuth{
+			URL:         "www.example.com/token",
+			ClientID:    "clientId",
+			Certificate: certificate,
+			PrivateKey:  privateKey,
+		})
+
+		request, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		err = strategy.AddAuthorization(request, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token", request.Header.Get(httpconsts.HeaderAuthorization))
+		oauthClientMock.AssertExpectations(t)
+	})
+
+	t.Run("should fail with invalid client certificate", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+
+		strategy := newOAuthGrantStrategy(oauthClientMock, &OAuth{
+			URL:         "www.example.com/token",
+			ClientID:    "clientId",
+			Certificate: []byte("invalid"),
+			PrivateKey:  privateKey,
+		})
+
+		request, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		err = strategy.AddAuthorization(request, nil)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, "", request.Header.Get(httpconsts.HeaderAuthorization))
+		oauthClientMock.AssertNotCalled(t, "GetTokenWithGrant", mock.Anything)
+	})
+
+	t.Run("should invalidate cache of grant", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("InvalidateGrantTokenCache", oauth.GrantTypePassword, "clientId").Once()
+
+		strategy := newOAuthGrantStrategy(oauthClientMock, &OAuth{ClientID: "clientId", GrantType: oauth.GrantTypePassword})
+
+		// when
+		strategy.Invalidate()
+
+		// then
+		oauthClientMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,54 @@
+package authorization
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
+)
+
+// samlBearerStrategy exchanges the SAML 2.0 assertion of the calling user for the token, the assertion is taken from
+// the request context or from the Access-Token header, which is never passed to the target
+type samlBearerStrategy struct {
+	oauthClient OAuthClient
+	oauth       *OAuth
+}
+
+func newSAMLBearerStrategy(oauthClient OAuthClient, oauth *OAuth) samlBearerStrategy {
+	return samlBearerStrategy{
+		oauthClient: oauthClient,
+		oauth:       oauth,
+	}
+}
+
+func (s samlBearerStrategy) AddAuthorization(r *http.Request, _ clientcert.SetClientCertificateFunc) apperrors.AppError {
+	assertion := UserToken(r.Context())
+	if assertion == "" {
+		assertion = r.Header.Get(httpconsts.HeaderAccessToken)
+	}
+	r.Header.Del(httpconsts.HeaderAccessToken)
+
+	assertion = strings.TrimSpace(strings.TrimPrefix(assertion, "Bearer "))
+	if assertion == "" {
+		return apperrors.WrongInput("SAML bearer assertion of the user is missing, it must be passed in the %s header", httpconsts.HeaderAccessToken)
+	}
+
+	request, err := newTokenRequest(s.oauth)
+	if err != nil {
+		return err
+	}
+	request.Assertion = assertion
+	// the assertion isn't used as the cache key directly, so it's not kept in memory longer than needed
+	request.Subject = fmt.Sprintf("%x", sha256.Sum256([]byte(assertion)))
+
+	return addToken(r, s.oauthClient, request)
+}
+
+func (s samlBearerStrategy) Invalidate() {
+	s.oauthClient.InvalidateGrantTokenCache(oauth.GrantTypeSAML2Bearer, s.oauth.ClientID)
+}
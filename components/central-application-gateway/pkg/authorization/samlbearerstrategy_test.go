@@ -0,0 +1,107 @@
+package authorization
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
+	oauthMocks "github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth/mocks"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSAMLBearerStrategy(t *testing.T) {
+	credentials := &OAuth{
+		URL:          "www.example.com/token",
+		ClientID:     "clientId",
+		ClientSecret: "clientSecret",
+		GrantType:    oauth.GrantTypeSAML2Bearer,
+	}
+	expectedRequest := oauth.TokenRequest{
+		GrantType:    oauth.GrantTypeSAML2Bearer,
+		ClientID:     "clientId",
+		ClientSecret: "clientSecret",
+		AuthURL:      "www.example.com/token",
+		Assertion:    "assertion",
+		Subject:      fmt.Sprintf("%x", sha256.Sum256([]byte("assertion"))),
+	}
+
+	t.Run("should exchange user token from Access-Token header", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("GetTokenWithGrant", expectedRequest).Return("token", nil).Once()
+
+		strategy := newSAMLBearerStrategy(oauthClientMock, credentials)
+
+		request, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+		request.Header.Set(httpconsts.HeaderAccessToken, "Bearer assertion")
+
+		// when
+		err = strategy.AddAuthorization(request, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token", request.Header.Get(httpconsts.HeaderAuthorization))
+		assert.Empty(t, request.Header.Get(httpconsts.HeaderAccessToken))
+		oauthClientMock.AssertExpectations(t)
+	})
+
+	t.Run("should exchange user token from context", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("GetTokenWithGrant", expectedRequest).Return("token", nil).Once()
+
+		strategy := newSAMLBearerStrategy(oauthClientMock, credentials)
+
+		request, err := http.NewRequestWithContext(WithUserToken(context.Background(), "assertion"), "GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		err = strategy.AddAuthorization(request, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token", request.Header.Get(httpconsts.HeaderAuthorization))
+		oauthClientMock.AssertExpectations(t)
+	})
+
+	t.Run("should fail when user token is missing", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+
+		strategy := newSAMLBearerStrategy(oauthClientMock, credentials)
+
+		request, err := http.NewRequest("GET", "www.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		appErr := strategy.AddAuthorization(request, nil)
+
+		// then
+		require.Error(t, appErr)
+		assert.Equal(t, apperrors.CodeWrongInput, appErr.Code())
+		assert.Empty(t, request.Header.Get(httpconsts.HeaderAuthorization))
+		oauthClientMock.AssertNotCalled(t, "GetTokenWithGrant", mock.Anything)
+	})
+
+	t.Run("should invalidate cache of all users", func(t *testing.T) {
+		// given
+		oauthClientMock := &oauthMocks.Client{}
+		oauthClientMock.On("InvalidateGrantTokenCache", oauth.GrantTypeSAML2Bearer, "clientId").Once()
+
+		strategy := newSAMLBearerStrategy(oauthClientMock, credentials)
+
+		// when
+		strategy.Invalidate()
+
+		// then
+		oauthClientMock.AssertExpectations(t)
+	})
+}
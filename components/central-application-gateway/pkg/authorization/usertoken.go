@@ -0,0 +1,19 @@
+package authorization
+
+import "context"
+
+type userTokenKey struct{}
+
+// WithUserToken returns the context carrying the token of the user calling the Application Gateway
+func WithUserToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, userTokenKey{}, token)
+}
+
+// UserToken returns the token of the user calling the Application Gateway, it's empty if the context doesn't carry it
+func UserToken(ctx context.Context) string {
+	token, _ := ctx.Value(userTokenKey{}).(string)
+	return token
+}
@@ -16,9 +16,11 @@ const (
 	HeaderCacheControl         = "cache-control"
 	HeaderCacheControlVal      = "no-cache"
 	HeaderCookie               = "Cookie"
+	HeaderRetryAfter           = "Retry-After"
 )
 
 const (
 	ContentTypeApplicationJson       = "application/json;charset=UTF-8"
 	ContentTypeApplicationURLEncoded = "application/x-www-form-urlencoded"
+	ContentTypeApplicationGRPC       = "application/grpc"
 )
@@ -4,11 +4,16 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/httpconsts"
+	"golang.org/x/net/http2"
 )
 
 type RoundTripper struct {
 	transport *http.Transport
+	h2c       http.RoundTripper
 }
 
 type RoundTripperOption func(*RoundTripper)
@@ -37,6 +42,25 @@ func WithGetClientCertificate(f func(*tls.CertificateRequestInfo) (*tls.Certific
 	}
 }
 
+// WithResponseHeaderTimeout limits the time of waiting for the response headers, the response body is not limited
+func WithResponseHeaderTimeout(timeout time.Duration) RoundTripperOption {
+	return func(rt *RoundTripper) {
+		rt.transport.ResponseHeaderTimeout = timeout
+	}
+}
+
+// WithH2C sends the gRPC requests to the plain HTTP targets over HTTP/2 without TLS (h2c)
+func WithH2C() RoundTripperOption {
+	return func(rt *RoundTripper) {
+		rt.h2c = &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.DialTimeout(network, addr, 30*time.Second)
+			},
+		}
+	}
+}
+
 func NewRoundTripper(options ...RoundTripperOption) *RoundTripper {
 	rt := &RoundTripper{
 		transport: newDefaultTransport(),
@@ -48,9 +72,17 @@ func NewRoundTripper(options ...RoundTripperOption) *RoundTripper {
 }
 
 func (p *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if p.h2c != nil && req.URL.Scheme == "http" && IsGRPCRequest(req) {
+		return p.h2c.RoundTrip(req)
+	}
 	return p.transport.RoundTrip(req)
 }
 
+// IsGRPCRequest checks if the request is the gRPC call, which must be sent over HTTP/2
+func IsGRPCRequest(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get(httpconsts.HeaderContentType), httpconsts.ContentTypeApplicationGRPC)
+}
+
 func newDefaultTransport() *http.Transport {
 	// http.DefaultTransport
 	return &http.Transport{
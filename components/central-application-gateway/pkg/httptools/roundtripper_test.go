@@ -18,6 +18,8 @@ import (
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/clientcert"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func TestRoundTripper(t *testing.T) {
@@ -178,6 +180,46 @@ func TestRoundTripperMTLSMissingCert(t *testing.T) {
 	require.ErrorIs(t, err, clientcert.ErrMissingClientCertificate)
 }
 
+func TestRoundTripperH2C(t *testing.T) {
+	ts := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Header().Set("Proto", r.Proto)
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Grpc-Status", "0")
+	}), &http2.Server{}))
+	defer ts.Close()
+
+	httpClient := &http.Client{
+		Transport: NewRoundTripper(WithH2C()),
+	}
+
+	t.Run("should send gRPC request over h2c", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/grpc+proto")
+
+		res, err := httpClient.Do(req)
+		require.NoError(t, err)
+
+		_, err = ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, "HTTP/2.0", res.Header.Get("Proto"))
+		require.Equal(t, "0", res.Trailer.Get("Grpc-Status"))
+	})
+
+	t.Run("should send other request over HTTP/1.1", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+
+		res, err := httpClient.Do(req)
+		require.NoError(t, err)
+
+		_ = res.Body.Close()
+		require.Equal(t, "HTTP/1.1", res.Header.Get("Proto"))
+	})
+}
+
 func newCert(caCert *tls.Certificate) (*tls.Certificate, error) {
 	certificate := &x509.Certificate{
 		SerialNumber: big.NewInt(mathrand.Int63()),
@@ -3,6 +3,7 @@ package proxyconfig
 import (
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/apperrors"
 	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization"
+	"github.com/kyma-project/kyma/components/central-application-gateway/pkg/authorization/oauth"
 )
 
 //go:generate mockery --name=TargetConfigProvider
@@ -16,11 +17,15 @@ type TargetConfigProvider interface {
 type AuthType string
 
 const (
-	Undefined   AuthType = ""
-	NoAuth      AuthType = "noauth"
-	Oauth       AuthType = "oauth"
-	Basic       AuthType = "basicauth"
-	Certificate AuthType = "certificate"
+	Undefined       AuthType = ""
+	NoAuth          AuthType = "noauth"
+	Oauth           AuthType = "oauth"
+	OauthPassword   AuthType = "oauthpassword"
+	OauthMTLS       AuthType = "oauthmtls"
+	OauthJWTBearer  AuthType = "oauthjwtbearer"
+	OauthSAMLBearer AuthType = "oauthsamlbearer"
+	Basic           AuthType = "basicauth"
+	Certificate     AuthType = "certificate"
 )
 
 // ProxyDestinationConfig is Proxy configuration for specific target
@@ -67,6 +72,97 @@ func (oc OauthConfig) ToCredentials() *authorization.Credentials {
 	}
 }
 
+// OauthPasswordConfig obtains the token with the password grant of the resource owner
+type OauthPasswordConfig struct {
+	ClientId          string                          `json:"clientId"`
+	ClientSecret      string                          `json:"clientSecret"`
+	Username          string                          `json:"username"`
+	Password          string                          `json:"password"`
+	TokenURL          string                          `json:"tokenUrl"`
+	RequestParameters authorization.RequestParameters `json:"requestParameters,omitempty"`
+}
+
+func (oc OauthPasswordConfig) ToCredentials() *authorization.Credentials {
+	return &authorization.Credentials{
+		OAuth: &authorization.OAuth{
+			URL:               oc.TokenURL,
+			ClientID:          oc.ClientId,
+			ClientSecret:      oc.ClientSecret,
+			GrantType:         oauth.GrantTypePassword,
+			Username:          oc.Username,
+			Password:          oc.Password,
+			RequestParameters: &oc.RequestParameters,
+		},
+	}
+}
+
+// OauthMTLSConfig obtains the token with the client credentials, the client is authenticated with the certificate
+type OauthMTLSConfig struct {
+	ClientId          string                          `json:"clientId"`
+	Certificate       []byte                          `json:"certificate"`
+	PrivateKey        []byte                          `json:"privateKey"`
+	TokenURL          string                          `json:"tokenUrl"`
+	RequestParameters authorization.RequestParameters `json:"requestParameters,omitempty"`
+}
+
+func (oc OauthMTLSConfig) ToCredentials() *authorization.Credentials {
+	return &authorization.Credentials{
+		OAuth: &authorization.OAuth{
+			URL:               oc.TokenURL,
+			ClientID:          oc.ClientId,
+			GrantType:         oauth.GrantTypeClientCredentials,
+			Certificate:       oc.Certificate,
+			PrivateKey:        oc.PrivateKey,
+			RequestParameters: &oc.RequestParameters,
+		},
+	}
+}
+
+// OauthJWTBearerConfig obtains the token with the JWT assertion signed with the signing key
+type OauthJWTBearerConfig struct {
+	ClientId          string                          `json:"clientId"`
+	ClientSecret      string                          `json:"clientSecret"`
+	Subject           string                          `json:"subject"`
+	SigningKey        []byte                          `json:"signingKey"`
+	TokenURL          string                          `json:"tokenUrl"`
+	RequestParameters authorization.RequestParameters `json:"requestParameters,omitempty"`
+}
+
+func (oc OauthJWTBearerConfig) ToCredentials() *authorization.Credentials {
+	return &authorization.Credentials{
+		OAuth: &authorization.OAuth{
+			URL:               oc.TokenURL,
+			ClientID:          oc.ClientId,
+			ClientSecret:      oc.ClientSecret,
+			GrantType:         oauth.GrantTypeJWTBearer,
+			Subject:           oc.Subject,
+			SigningKey:        oc.SigningKey,
+			RequestParameters: &oc.RequestParameters,
+		},
+	}
+}
+
+// OauthSAMLBearerConfig exchanges the SAML 2.0 assertion of the calling user, passed in the Access-Token header, for
+// the token
+type OauthSAMLBearerConfig struct {
+	ClientId          string                          `json:"clientId"`
+	ClientSecret      string                          `json:"clientSecret"`
+	TokenURL          string                          `json:"tokenUrl"`
+	RequestParameters authorization.RequestParameters `json:"requestParameters,omitempty"`
+}
+
+func (oc OauthSAMLBearerConfig) ToCredentials() *authorization.Credentials {
+	return &authorization.Credentials{
+		OAuth: &authorization.OAuth{
+			URL:               oc.TokenURL,
+			ClientID:          oc.ClientId,
+			ClientSecret:      oc.ClientSecret,
+			GrantType:         oauth.GrantTypeSAML2Bearer,
+			RequestParameters: &oc.RequestParameters,
+		},
+	}
+}
+
 type BasicAuthConfig struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -0,0 +1,176 @@
+// Package sharedcache contains the cache of the OAuth and CSRF tokens, which can be shared by the gateway replicas
+package sharedcache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Item is the cached value, the zero ExpiresAt means the value doesn't expire
+type Item struct {
+	Value string `json:"value"`
+	// ExpiresAt is the time after which the item isn't returned
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+	// RefreshAt is the time after which one of the callers refreshes the item while the others still get it
+	RefreshAt time.Time `json:"refreshAt,omitempty"`
+}
+
+func (i Item) expired(now time.Time) bool {
+	return !i.ExpiresAt.IsZero() && !now.Before(i.ExpiresAt)
+}
+
+func (i Item) refreshDue(now time.Time) bool {
+	return !i.RefreshAt.IsZero() && !now.Before(i.RefreshAt)
+}
+
+// Backend stores the cached items, the expired items are never returned
+//
+//go:generate mockery --name=Backend
+type Backend interface {
+	Get(key string) (Item, bool, error)
+	Set(key string, item Item) error
+	Delete(key string) error
+	// DeleteWithPrefix deletes the items with the keys starting with the prefix
+	DeleteWithPrefix(prefix string) error
+	// Lock makes the caller the only one, which fetches the item, until it's unlocked or ttl elapses, it reports if
+	// the lock was taken
+	Lock(key string, ttl time.Duration) (bool, error)
+	Unlock(key string) error
+	// Changed returns the channel closed when any of the items is stored or deleted, stop must be called when
+	// the caller doesn't wait for the channel anymore
+	Changed() (changed <-chan struct{}, stop func())
+}
+
+// notifier wakes the callers waiting for the changes of the items
+type notifier struct {
+	mutex   sync.Mutex
+	waiters map[chan struct{}]struct{}
+}
+
+func newNotifier() *notifier {
+	return &notifier{waiters: map[chan struct{}]struct{}{}}
+}
+
+func (n *notifier) subscribe() (<-chan struct{}, func()) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	changed := make(chan struct{})
+	n.waiters[changed] = struct{}{}
+	return changed, func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+
+		delete(n.waiters, changed)
+	}
+}
+
+func (n *notifier) notify() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for changed := range n.waiters {
+		close(changed)
+		delete(n.waiters, changed)
+	}
+}
+
+type memoryBackend struct {
+	mutex    sync.Mutex
+	items    map[string]Item
+	locks    map[string]time.Time
+	notifier *notifier
+	now      func() time.Time
+}
+
+// NewMemoryBackend creates the backend keeping the items in the memory of the replica
+func NewMemoryBackend() Backend {
+	return &memoryBackend{
+		items:    map[string]Item{},
+		locks:    map[string]time.Time{},
+		notifier: newNotifier(),
+		now:      time.Now,
+	}
+}
+
+func (b *memoryBackend) Get(key string) (Item, bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	item, found := b.items[key]
+	if !found || item.expired(b.now()) {
+		return Item{}, false, nil
+	}
+	return item, true, nil
+}
+
+func (b *memoryBackend) Set(key string, item Item) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.purgeExpired()
+	b.items[key] = item
+	b.notifier.notify()
+	return nil
+}
+
+func (b *memoryBackend) Delete(key string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.items, key)
+	b.notifier.notify()
+	return nil
+}
+
+func (b *memoryBackend) DeleteWithPrefix(prefix string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for key := range b.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(b.items, key)
+		}
+	}
+	b.notifier.notify()
+	return nil
+}
+
+func (b *memoryBackend) Lock(key string, ttl time.Duration) (bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.now()
+	if expiresAt, found := b.locks[key]; found && now.Before(expiresAt) {
+		return false, nil
+	}
+	b.locks[key] = now.Add(ttl)
+	return true, nil
+}
+
+func (b *memoryBackend) Unlock(key string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.locks, key)
+	return nil
+}
+
+func (b *memoryBackend) Changed() (<-chan struct{}, func()) {
+	return b.notifier.subscribe()
+}
+
+func (b *memoryBackend) purgeExpired() {
+	now := b.now()
+	for key, item := range b.items {
+		if item.expired(now) {
+			delete(b.items, key)
+		}
+	}
+	for key, expiresAt := range b.locks {
+		if !now.Before(expiresAt) {
+			delete(b.locks, key)
+		}
+	}
+}
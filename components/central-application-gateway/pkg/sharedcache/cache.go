@@ -0,0 +1,171 @@
+package sharedcache
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Config configures the Cache
+type Config struct {
+	// RefreshBefore is the time before the expiration of the item, in which one of the callers refreshes it, it's
+	// limited to the half of the item lifetime, the items aren't refreshed early if it isn't set
+	RefreshBefore time.Duration
+	// LockTimeout is the time for which the caller fetching the item makes the callers of other replicas wait for it,
+	// the callers fetch the missing items independently and the items aren't refreshed early if it isn't set
+	LockTimeout time.Duration
+}
+
+// Cache returns the items from the backend, only one of the callers of all replicas is told to fetch the missing
+// or expiring item, the others wait for the fetched item or get the expiring one meanwhile
+type Cache struct {
+	backend Backend
+	config  Config
+	now     func() time.Time
+	after   func(time.Duration) <-chan time.Time
+
+	mutex sync.Mutex
+	// locks are the keys locked by the replica with their expiration, all callers of the replica fetch the locked
+	// items, so that they can share a single fetch
+	locks map[string]time.Time
+}
+
+// New creates the cache of the items stored in the backend
+func New(backend Backend, config Config) *Cache {
+	return &Cache{
+		backend: backend,
+		config:  config,
+		now:     time.Now,
+		after:   time.After,
+		locks:   map[string]time.Time{},
+	}
+}
+
+// Get returns the value of the item, the caller must fetch the item and Set it if it isn't found
+func (c *Cache) Get(key string) (string, bool) {
+	item, found := c.get(key)
+	if found && !item.refreshDue(c.now()) {
+		return item.Value, true
+	}
+	if c.config.LockTimeout <= 0 {
+		return item.Value, found
+	}
+
+	if found {
+		if c.lockedLocally(key) || !c.lock(key) {
+			return item.Value, true
+		}
+		log.Infof("Refreshing cached item '%s' before it expires", key)
+		return "", false
+	}
+
+	if c.lockedLocally(key) || c.lock(key) {
+		return "", false
+	}
+	return c.wait(key)
+}
+
+// Set stores the item, which expires after ttl, the item doesn't expire if ttl isn't positive
+func (c *Cache) Set(key, value string, ttl time.Duration) {
+	item := Item{Value: value}
+	if ttl > 0 {
+		item.ExpiresAt = c.now().Add(ttl)
+		if refreshBefore := c.config.RefreshBefore; refreshBefore > 0 {
+			if refreshBefore > ttl/2 {
+				refreshBefore = ttl / 2
+			}
+			item.RefreshAt = item.ExpiresAt.Add(-refreshBefore)
+		}
+	}
+
+	if err := c.backend.Set(key, item); err != nil {
+		log.Warnf("Failed to store cached item '%s': %s", key, err.Error())
+	}
+	c.unlock(key)
+}
+
+// Delete removes the item
+func (c *Cache) Delete(key string) {
+	if err := c.backend.Delete(key); err != nil {
+		log.Warnf("Failed to delete cached item '%s': %s", key, err.Error())
+	}
+}
+
+// DeleteWithPrefix removes the items with the keys starting with the prefix
+func (c *Cache) DeleteWithPrefix(prefix string) {
+	if err := c.backend.DeleteWithPrefix(prefix); err != nil {
+		log.Warnf("Failed to delete cached items with prefix '%s': %s", prefix, err.Error())
+	}
+}
+
+func (c *Cache) get(key string) (Item, bool) {
+	item, found, err := c.backend.Get(key)
+	if err != nil {
+		log.Warnf("Failed to get cached item '%s': %s", key, err.Error())
+		return Item{}, false
+	}
+	return item, found
+}
+
+// lock makes the caller the only one, which fetches the item, the caller fetches the item also if the lock fails
+func (c *Cache) lock(key string) bool {
+	expiresAt := c.now().Add(c.config.LockTimeout)
+	locked, err := c.backend.Lock(key, c.config.LockTimeout)
+	if err != nil {
+		log.Warnf("Failed to lock cached item '%s': %s", key, err.Error())
+		return true
+	}
+	if locked {
+		c.mutex.Lock()
+		c.locks[key] = expiresAt
+		c.mutex.Unlock()
+	}
+	return locked
+}
+
+func (c *Cache) lockedLocally(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt, found := c.locks[key]
+	if found && !c.now().Before(expiresAt) {
+		delete(c.locks, key)
+		return false
+	}
+	return found
+}
+
+func (c *Cache) unlock(key string) {
+	c.mutex.Lock()
+	_, found := c.locks[key]
+	delete(c.locks, key)
+	c.mutex.Unlock()
+	if !found {
+		return
+	}
+
+	if err := c.backend.Unlock(key); err != nil {
+		log.Warnf("Failed to unlock cached item '%s': %s", key, err.Error())
+	}
+}
+
+// wait waits for the item fetched by another replica, it's woken by the changes of the items instead of polling the
+// backend, the caller fetches the item if it isn't stored in time
+func (c *Cache) wait(key string) (string, bool) {
+	timeout := c.after(c.config.LockTimeout)
+	for {
+		changed, stop := c.backend.Changed()
+		if item, found := c.get(key); found {
+			stop()
+			return item.Value, true
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+			stop()
+			return "", false
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package sharedcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// After lets the time pass immediately
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Sleep(d)
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return fired
+}
+
+func newTestCache(backend Backend, clock *fakeClock, config Config) *Cache {
+	cache := New(backend, config)
+	cache.now = clock.Now
+	cache.after = clock.After
+	return cache
+}
+
+func newTestMemoryBackend(clock *fakeClock) Backend {
+	backend := NewMemoryBackend().(*memoryBackend)
+	backend.now = clock.Now
+	return backend
+}
+
+func TestCache(t *testing.T) {
+	t.Run("should return stored item until it expires", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		cache := newTestCache(newTestMemoryBackend(clock), clock, Config{})
+
+		// when
+		cache.Set("key", "value", time.Minute)
+
+		// then
+		value, found := cache.Get("key")
+		assert.True(t, found)
+		assert.Equal(t, "value", value)
+
+		clock.Sleep(time.Minute)
+		_, found = cache.Get("key")
+		assert.False(t, found)
+	})
+
+	t.Run("should not expire item without ttl", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		cache := newTestCache(newTestMemoryBackend(clock), clock, Config{})
+
+		// when
+		cache.Set("key", "value", 0)
+		clock.Sleep(24 * time.Hour)
+
+		// then
+		value, found := cache.Get("key")
+		assert.True(t, found)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("should delete items", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		cache := newTestCache(newTestMemoryBackend(clock), clock, Config{})
+		cache.Set("oauth/first", "value", time.Minute)
+		cache.Set("oauth/second", "value", time.Minute)
+		cache.Set("csrf/first", "value", time.Minute)
+
+		// when
+		cache.Delete("csrf/first")
+		cache.DeleteWithPrefix("oauth/")
+
+		// then
+		for _, key := range []string{"oauth/first", "oauth/second", "csrf/first"} {
+			_, found := cache.Get(key)
+			assert.False(t, found, key)
+		}
+	})
+
+	t.Run("should make replicas wait for item fetched by another replica", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		backend := newTestMemoryBackend(clock)
+		config := Config{LockTimeout: 10 * time.Second}
+		fetching := newTestCache(backend, clock, config)
+		waiting := newTestCache(backend, clock, config)
+
+		// when
+		_, found := fetching.Get("key")
+
+		// then
+		assert.False(t, found)
+
+		// when
+		waiting.after = func(time.Duration) <-chan time.Time {
+			return nil
+		}
+		result := make(chan string)
+		go func() {
+			value, _ := waiting.Get("key")
+			result <- value
+		}()
+		time.Sleep(10 * time.Millisecond)
+		fetching.Set("key", "value", time.Minute)
+
+		// then
+		select {
+		case value := <-result:
+			assert.Equal(t, "value", value)
+		case <-time.After(time.Second):
+			t.Fatal("waiting replica wasn't woken by the stored item")
+		}
+	})
+
+	t.Run("should let all callers of replica holding the lock fetch item", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		cache := newTestCache(newTestMemoryBackend(clock), clock, Config{LockTimeout: 10 * time.Second})
+		cache.after = func(time.Duration) <-chan time.Time {
+			t.Fatal("caller of the replica holding the lock must not wait")
+			return nil
+		}
+
+		// when
+		_, firstFound := cache.Get("key")
+		_, secondFound := cache.Get("key")
+
+		// then
+		assert.False(t, firstFound)
+		assert.False(t, secondFound)
+	})
+
+	t.Run("should fetch item when replica holding the lock doesn't store it in time", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		backend := newTestMemoryBackend(clock)
+		config := Config{LockTimeout: time.Second}
+		fetching := newTestCache(backend, clock, config)
+		waiting := newTestCache(backend, clock, config)
+		_, _ = fetching.Get("key")
+		start := clock.now
+
+		// when
+		_, found := waiting.Get("key")
+
+		// then
+		assert.False(t, found)
+		assert.Equal(t, time.Second, clock.now.Sub(start))
+
+		// when
+		_, found = waiting.Get("key")
+
+		// then
+		assert.False(t, found, "expired lock should be taken over")
+	})
+
+	t.Run("should let one caller refresh item before it expires", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		backend := newTestMemoryBackend(clock)
+		config := Config{RefreshBefore: 30 * time.Second, LockTimeout: 10 * time.Second}
+		first := newTestCache(backend, clock, config)
+		second := newTestCache(backend, clock, config)
+		first.Set("key", "old", 2*time.Minute)
+
+		// when
+		clock.Sleep(time.Minute + 31*time.Second)
+		_, refreshing := first.Get("key")
+		secondValue, secondFound := second.Get("key")
+
+		// then
+		assert.False(t, refreshing)
+		assert.True(t, secondFound)
+		assert.Equal(t, "old", secondValue)
+
+		// when
+		first.Set("key", "new", 2*time.Minute)
+
+		// then
+		value, found := second.Get("key")
+		assert.True(t, found)
+		assert.Equal(t, "new", value)
+	})
+
+	t.Run("should limit early refresh to half of item lifetime", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		cache := newTestCache(newTestMemoryBackend(clock), clock, Config{RefreshBefore: time.Minute, LockTimeout: time.Second})
+		cache.Set("key", "value", 40*time.Second)
+
+		// when
+		clock.Sleep(19 * time.Second)
+		_, beforeRefresh := cache.Get("key")
+		clock.Sleep(time.Second)
+		_, afterRefresh := cache.Get("key")
+
+		// then
+		assert.True(t, beforeRefresh)
+		assert.False(t, afterRefresh)
+	})
+
+	t.Run("should not refresh item early without lock timeout", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		cache := newTestCache(newTestMemoryBackend(clock), clock, Config{RefreshBefore: 30 * time.Second})
+		cache.Set("key", "value", time.Minute)
+
+		// when
+		clock.Sleep(45 * time.Second)
+		value, found := cache.Get("key")
+
+		// then
+		assert.True(t, found)
+		assert.Equal(t, "value", value)
+	})
+}
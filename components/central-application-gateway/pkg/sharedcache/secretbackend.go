@@ -0,0 +1,367 @@
+package sharedcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+// SecretManager contains operations for managing the k8s secrets storing the items
+type SecretManager interface {
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*v1.Secret, error)
+	List(ctx context.Context, options metav1.ListOptions) (*v1.SecretList, error)
+	Watch(ctx context.Context, options metav1.ListOptions) (watch.Interface, error)
+	Create(ctx context.Context, secret *v1.Secret, options metav1.CreateOptions) (*v1.Secret, error)
+	Update(ctx context.Context, secret *v1.Secret, options metav1.UpdateOptions) (*v1.Secret, error)
+	Delete(ctx context.Context, name string, options metav1.DeleteOptions) error
+}
+
+// LeaseManager contains operations for managing the k8s leases locking the items
+type LeaseManager interface {
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*coordinationv1.Lease, error)
+	Create(ctx context.Context, lease *coordinationv1.Lease, options metav1.CreateOptions) (*coordinationv1.Lease, error)
+	Update(ctx context.Context, lease *coordinationv1.Lease, options metav1.UpdateOptions) (*coordinationv1.Lease, error)
+	Delete(ctx context.Context, name string, options metav1.DeleteOptions) error
+}
+
+const (
+	// secretLabel marks the secrets of the items, its value is the prefix of the secret names
+	secretLabel = "central-application-gateway/token-cache"
+	// entryDataKey is the key of the secret data holding the item
+	entryDataKey = "entry"
+)
+
+// secretEntry is the item stored in its secret, the name of the secret is derived from the hash of the item key
+type secretEntry struct {
+	Key string `json:"key"`
+	Item
+}
+
+type secretBackend struct {
+	secretManager SecretManager
+	leaseManager  LeaseManager
+	prefix        string
+	identity      string
+	notifier      *notifier
+	now           func() time.Time
+
+	mutex sync.Mutex
+	// snapshot are the entries of the secrets kept up to date by watching them, the items are read only from it
+	snapshot map[string]secretEntry
+	// leases are the resource versions of the leases held by the replica
+	leases map[string]string
+}
+
+// NewSecretBackend creates the backend keeping every item in a separate secret shared by the gateway replicas. The
+// secrets are named with the prefix and labeled, so they are watched and the items are read from the memory of the
+// replica. A secret is written only when its item is stored or deleted, and the secrets of the expired items are
+// deleted when any item is stored. The items are locked with a lease per item held by the identity. The secrets are
+// watched until stop is closed.
+func NewSecretBackend(secretManager SecretManager, leaseManager LeaseManager, prefix, identity string, stop <-chan struct{}) (Backend, error) {
+	backend := &secretBackend{
+		secretManager: secretManager,
+		leaseManager:  leaseManager,
+		prefix:        prefix,
+		identity:      identity,
+		notifier:      newNotifier(),
+		now:           time.Now,
+		snapshot:      map[string]secretEntry{},
+		leases:        map[string]string{},
+	}
+
+	if err := backend.watch(stop); err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+func (b *secretBackend) Get(key string) (Item, bool, error) {
+	b.mutex.Lock()
+	entry, found := b.snapshot[b.objectName(key)]
+	b.mutex.Unlock()
+	if !found || entry.Key != key || entry.expired(b.now()) {
+		return Item{}, false, nil
+	}
+	return entry.Item, true, nil
+}
+
+func (b *secretBackend) Set(key string, item Item) error {
+	value, err := json.Marshal(secretEntry{Key: key, Item: item})
+	if err != nil {
+		return err
+	}
+
+	name := b.objectName(key)
+	secret, err := b.secretManager.Create(context.Background(), b.newSecret(name, value), metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		secret, err = b.replace(name, value)
+	}
+	if err != nil {
+		return err
+	}
+	// the replica reads its own change before the watch delivers it
+	b.setSnapshot(secret)
+
+	return b.deleteExpired()
+}
+
+// replace stores the item in the existing secret, the update is repeated if the secret was updated concurrently
+func (b *secretBackend) replace(name string, value []byte) (*v1.Secret, error) {
+	var updated *v1.Secret
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := b.secretManager.Get(context.Background(), name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			// the conflict makes the update repeated, so the secret deleted by another replica is created again
+			updated, err = b.secretManager.Create(context.Background(), b.newSecret(name, value), metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				return k8serrors.NewConflict(v1.Resource("secrets"), name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		secret.Data = map[string][]byte{entryDataKey: value}
+		updated, err = b.secretManager.Update(context.Background(), secret, metav1.UpdateOptions{})
+		return err
+	})
+	return updated, err
+}
+
+func (b *secretBackend) Delete(key string) error {
+	return b.deleteSecret(b.objectName(key))
+}
+
+// DeleteWithPrefix lists the secrets instead of reading the snapshot, so the items stored by other replicas, which
+// aren't delivered by the watch yet, are deleted too
+func (b *secretBackend) DeleteWithPrefix(prefix string) error {
+	secrets, err := b.secretManager.List(context.Background(), metav1.ListOptions{LabelSelector: b.labelSelector()})
+	if err != nil {
+		return err
+	}
+
+	for i := range secrets.Items {
+		entry, err := decodeEntry(&secrets.Items[i])
+		if err == nil && !strings.HasPrefix(entry.Key, prefix) {
+			continue
+		}
+		if err := b.deleteSecret(secrets.Items[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteExpired deletes the secrets of the expired items, so the secrets don't pile up when the items aren't fetched
+// anymore
+func (b *secretBackend) deleteExpired() error {
+	now := b.now()
+	var expired []string
+	b.mutex.Lock()
+	for name, entry := range b.snapshot {
+		if entry.expired(now) {
+			expired = append(expired, name)
+		}
+	}
+	b.mutex.Unlock()
+
+	for _, name := range expired {
+		if err := b.deleteSecret(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *secretBackend) deleteSecret(name string) error {
+	err := b.secretManager.Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	b.removeSnapshot(name)
+	return nil
+}
+
+func (b *secretBackend) Lock(key string, ttl time.Duration) (bool, error) {
+	now := metav1.NewMicroTime(b.now())
+	seconds := int32(math.Ceil(ttl.Seconds()))
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       &b.identity,
+		LeaseDurationSeconds: &seconds,
+		AcquireTime:          &now,
+		RenewTime:            &now,
+	}
+
+	lease, err := b.leaseManager.Create(context.Background(), &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: b.objectName(key)},
+		Spec:       spec,
+	}, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		lease, err = b.takeOverExpiredLease(key, spec)
+	}
+	if err != nil || lease == nil {
+		return false, err
+	}
+
+	b.mutex.Lock()
+	b.leases[key] = lease.ResourceVersion
+	b.mutex.Unlock()
+	return true, nil
+}
+
+// takeOverExpiredLease takes the lease left by the replica which didn't unlock the item in time, it returns nil if
+// the lease is held or taken by another replica
+func (b *secretBackend) takeOverExpiredLease(key string, spec coordinationv1.LeaseSpec) (*coordinationv1.Lease, error) {
+	lease, err := b.leaseManager.Get(context.Background(), b.objectName(key), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil || !leaseExpired(lease, b.now()) {
+		return nil, err
+	}
+
+	lease.Spec = spec
+	lease, err = b.leaseManager.Update(context.Background(), lease, metav1.UpdateOptions{})
+	if k8serrors.IsConflict(err) {
+		return nil, nil
+	}
+	return lease, err
+}
+
+func (b *secretBackend) Unlock(key string) error {
+	b.mutex.Lock()
+	resourceVersion, found := b.leases[key]
+	delete(b.leases, key)
+	b.mutex.Unlock()
+	if !found {
+		return nil
+	}
+
+	// the precondition keeps the lease taken over by another replica after it expired
+	err := b.leaseManager.Delete(context.Background(), b.objectName(key), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+	})
+	if k8serrors.IsNotFound(err) || k8serrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+func (b *secretBackend) Changed() (<-chan struct{}, func()) {
+	return b.notifier.subscribe()
+}
+
+// objectName is the name of the secret and the lease of the item, the item keys contain characters which aren't
+// allowed in it
+func (b *secretBackend) objectName(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s-%s", b.prefix, hex.EncodeToString(hash[:]))
+}
+
+func (b *secretBackend) newSecret(name string, value []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{secretLabel: b.prefix},
+		},
+		Data: map[string][]byte{entryDataKey: value},
+	}
+}
+
+func (b *secretBackend) labelSelector() string {
+	return labels.SelectorFromSet(labels.Set{secretLabel: b.prefix}).String()
+}
+
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiresAt := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return !now.Before(expiresAt)
+}
+
+// watch keeps the snapshot up to date with the secrets and wakes the callers waiting for the items, it returns when
+// the secrets are read for the first time
+func (b *secretBackend) watch(stop <-chan struct{}) error {
+	labelSelector := b.labelSelector()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector
+			return b.secretManager.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector
+			return b.secretManager.Watch(context.Background(), options)
+		},
+	}
+
+	_, controller := cache.NewInformer(listWatch, &v1.Secret{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			b.setSnapshot(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			b.setSnapshot(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if secret, ok := obj.(*v1.Secret); ok {
+				b.removeSnapshot(secret.Name)
+			}
+		},
+	})
+	go controller.Run(stop)
+
+	if !cache.WaitForCacheSync(stop, controller.HasSynced) {
+		return fmt.Errorf("failed to read the '%s' secrets of the token cache", b.prefix)
+	}
+	return nil
+}
+
+func (b *secretBackend) setSnapshot(obj interface{}) {
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		return
+	}
+	entry, err := decodeEntry(secret)
+	if err != nil {
+		// the secret which can't be read is ignored until it's overwritten or deleted
+		return
+	}
+
+	b.mutex.Lock()
+	b.snapshot[secret.Name] = entry
+	b.mutex.Unlock()
+	b.notifier.notify()
+}
+
+func (b *secretBackend) removeSnapshot(name string) {
+	b.mutex.Lock()
+	delete(b.snapshot, name)
+	b.mutex.Unlock()
+	b.notifier.notify()
+}
+
+func decodeEntry(secret *v1.Secret) (secretEntry, error) {
+	var entry secretEntry
+	err := json.Unmarshal(secret.Data[entryDataKey], &entry)
+	return entry, err
+}
@@ -0,0 +1,249 @@
+package sharedcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	secretPrefix    = "token-cache"
+	secretNamespace = "central-application-gateway-tokens"
+)
+
+func newTestSecretBackend(t *testing.T, clientset kubernetes.Interface, clock *fakeClock, identity string) Backend {
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+
+	backend, err := NewSecretBackend(clientset.CoreV1().Secrets(secretNamespace), clientset.CoordinationV1().Leases(secretNamespace), secretPrefix, identity, stop)
+	require.NoError(t, err)
+	backend.(*secretBackend).now = clock.Now
+	return backend
+}
+
+func assertEventuallyFound(t *testing.T, backend Backend, key, expectedValue string) {
+	assert.Eventually(t, func() bool {
+		item, found, err := backend.Get(key)
+		return err == nil && found && item.Value == expectedValue
+	}, time.Second, 10*time.Millisecond, key)
+}
+
+func TestSecretBackend(t *testing.T) {
+	t.Run("should share items between replicas", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+
+		// when
+		err := first.Set("oauth/key", Item{Value: "token", ExpiresAt: clock.now.Add(time.Minute)})
+
+		// then
+		require.NoError(t, err)
+		assertEventuallyFound(t, second, "oauth/key", "token")
+
+		secrets, err := clientset.CoreV1().Secrets(secretNamespace).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, secrets.Items, 1)
+		assert.Equal(t, first.(*secretBackend).objectName("oauth/key"), secrets.Items[0].Name)
+		assert.Equal(t, secretPrefix, secrets.Items[0].Labels[secretLabel])
+	})
+
+	t.Run("should overwrite item stored by another replica", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+		require.NoError(t, first.Set("key", Item{Value: "old"}))
+
+		// when
+		err := second.Set("key", Item{Value: "new"})
+
+		// then
+		require.NoError(t, err)
+		assertEventuallyFound(t, first, "key", "new")
+		assertEventuallyFound(t, second, "key", "new")
+	})
+
+	t.Run("should delete secrets of expired items when storing item", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+		require.NoError(t, first.Set("expiring", Item{Value: "token", ExpiresAt: clock.now.Add(time.Minute)}))
+		require.NoError(t, first.Set("lasting", Item{Value: "token"}))
+		assertEventuallyFound(t, second, "expiring", "token")
+
+		// when
+		clock.Sleep(time.Minute)
+		err := second.Set("other", Item{Value: "token"})
+
+		// then
+		require.NoError(t, err)
+		secrets, err := clientset.CoreV1().Secrets(secretNamespace).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, secret := range secrets.Items {
+			names = append(names, secret.Name)
+		}
+		backend := second.(*secretBackend)
+		assert.ElementsMatch(t, []string{backend.objectName("lasting"), backend.objectName("other")}, names)
+	})
+
+	t.Run("should read items without calling API server", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		backend := newTestSecretBackend(t, clientset, clock, "first")
+		require.NoError(t, backend.Set("key", Item{Value: "token"}))
+		clientset.ClearActions()
+
+		// when
+		for i := 0; i < 10; i++ {
+			_, found, err := backend.Get("key")
+			require.NoError(t, err)
+			assert.True(t, found)
+		}
+
+		// then
+		assert.Empty(t, clientset.Actions())
+	})
+
+	t.Run("should not return expired item", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		backend := newTestSecretBackend(t, fake.NewSimpleClientset(), clock, "first")
+		require.NoError(t, backend.Set("key", Item{Value: "token", ExpiresAt: clock.now.Add(time.Minute)}))
+
+		// when
+		clock.Sleep(time.Minute)
+		_, found, err := backend.Get("key")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should wake waiting callers when item is stored by another replica", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+		changed, stop := second.Changed()
+		defer stop()
+
+		// when
+		require.NoError(t, first.Set("key", Item{Value: "token"}))
+
+		// then
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+			t.Fatal("waiting caller wasn't woken")
+		}
+		assertEventuallyFound(t, second, "key", "token")
+	})
+
+	t.Run("should lock item for one replica until it expires", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+
+		// when
+		firstLocked, err := first.Lock("key", time.Second)
+		require.NoError(t, err)
+		secondLocked, err := second.Lock("key", time.Second)
+		require.NoError(t, err)
+		otherKeyLocked, err := second.Lock("other", time.Second)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, firstLocked)
+		assert.False(t, secondLocked)
+		assert.True(t, otherKeyLocked)
+
+		// when
+		clock.Sleep(time.Second)
+		secondLocked, err = second.Lock("key", time.Second)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, secondLocked, "expired lease should be taken over")
+	})
+
+	t.Run("should unlock item", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+		locked, err := first.Lock("key", time.Minute)
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		// when
+		require.NoError(t, first.Unlock("key"))
+		locked, err = second.Lock("key", time.Minute)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, locked)
+	})
+
+	t.Run("should delete items", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+		for _, key := range []string{"oauth/first", "oauth/second", "csrf/first", "csrf/second"} {
+			require.NoError(t, first.Set(key, Item{Value: "token"}))
+		}
+		assertEventuallyFound(t, second, "csrf/second", "token")
+
+		// when
+		require.NoError(t, second.Delete("csrf/first"))
+		require.NoError(t, second.DeleteWithPrefix("oauth/"))
+
+		// then
+		assert.Eventually(t, func() bool {
+			for _, key := range []string{"oauth/first", "oauth/second", "csrf/first"} {
+				if _, found, _ := first.Get(key); found {
+					return false
+				}
+			}
+			return true
+		}, time.Second, 10*time.Millisecond)
+		_, found, err := first.Get("csrf/second")
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("should keep items of replicas storing them concurrently", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Now()}
+		clientset := fake.NewSimpleClientset()
+		first := newTestSecretBackend(t, clientset, clock, "first")
+		second := newTestSecretBackend(t, clientset, clock, "second")
+
+		// when
+		require.NoError(t, first.Set("first", Item{Value: "first"}))
+		require.NoError(t, second.Set("second", Item{Value: "second"}))
+
+		// then
+		assertEventuallyFound(t, first, "second", "second")
+		assertEventuallyFound(t, second, "first", "first")
+	})
+}
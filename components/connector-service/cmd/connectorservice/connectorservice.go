@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strconv"
 	"sync"
+	"time"
 
+	"github.com/kyma-project/kyma/components/connector-service/internal/carotation"
 	loggingMiddlewares "github.com/kyma-project/kyma/components/connector-service/internal/logging/middlewares"
 	"github.com/kyma-project/kyma/components/connector-service/internal/monitoring"
 	"github.com/kyma-project/kyma/components/connector-service/internal/tokens"
@@ -27,7 +29,7 @@ func main() {
 	env := parseEnv()
 	log.Infof("Environment variables: %s", env)
 
-	tokenCache := tokencache.NewTokenCache()
+	tokenCache := newTokenCache(options)
 	tokenGenerator := tokens.NewTokenGenerator(options.tokenLength)
 	tokenManager := tokens.NewTokenManager(tokenCache)
 	tokenCreatorProvider := tokens.NewTokenCreatorProvider(tokenCache, tokenGenerator.NewToken)
@@ -41,6 +43,8 @@ func main() {
 		globalMiddlewares = append(globalMiddlewares, loggingMiddlewares.NewRequestLoggingMiddleware().Middleware)
 	}
 
+	startCARotation(options)
+
 	handlers := createAPIHandlers(tokenManager, tokenCreatorProvider, options, env, globalMiddlewares)
 
 	externalSrv := &http.Server{
@@ -72,3 +76,49 @@ func main() {
 
 	wg.Wait()
 }
+
+const (
+	tokenGarbageCollectionPeriod   = time.Minute
+	caRotationReconciliationPeriod = time.Minute
+)
+
+func newTokenCache(opts *options) tokencache.TokenCache {
+	if opts.tokenCacheBackend != tokenCacheBackendSecret {
+		return tokencache.NewTokenCache()
+	}
+
+	// the in-memory token cache isn't a fallback, the tokens issued by one replica would be rejected by the others
+	coreClientSet, appErr := newCoreClientSet()
+	if appErr != nil {
+		log.Fatalf("Failed to initialize Kubernetes client for token cache: %s", appErr.Error())
+	}
+
+	tokenCache := tokencache.NewSecretTokenCache(coreClientSet.CoreV1().Secrets(opts.tokenCacheNamespace))
+	go tokenCache.RunGarbageCollector(tokenGarbageCollectionPeriod)
+
+	return tokenCache
+}
+
+func startCARotation(opts *options) {
+	if opts.caTrustBundleSecretName.Name == "" {
+		return
+	}
+
+	coreClientSet, appErr := newCoreClientSet()
+	if appErr != nil {
+		log.Errorf("Failed to initialize Kubernetes client for CA rotation: %s", appErr.Error())
+		return
+	}
+
+	controller := carotation.NewController(func(namespace string) carotation.SecretsManager {
+		return coreClientSet.CoreV1().Secrets(namespace)
+	}, coreClientSet.CoreV1().ConfigMaps(opts.namespace), carotation.Config{
+		CASecretName:               opts.caSecretName,
+		TrustBundleSecretName:      opts.caTrustBundleSecretName,
+		StatusConfigMapName:        opts.caRotationStatusConfigMapName,
+		PropagationTime:            opts.caRotationPropagationTime,
+		MaxCertificateValidityTime: maxCertificateValidityTime(opts),
+	})
+
+	go controller.Run(caRotationReconciliationPeriod)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/kyma-project/kyma/components/connector-service/internal/externalapi"
 	"github.com/kyma-project/kyma/components/connector-service/internal/externalapi/middlewares"
 	"github.com/kyma-project/kyma/components/connector-service/internal/internalapi"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
 	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 	certificateMiddlewares "github.com/kyma-project/kyma/components/connector-service/internal/revocation/middlewares"
 	"github.com/kyma-project/kyma/components/connector-service/internal/secrets"
@@ -28,6 +29,9 @@ const (
 	runtimeCSRInfoFmt = "https://%s/v1/runtimes/signingRequests/info"
 	AppURLFormat      = "https://%s/v1/applications"
 	RuntimeURLFormat  = "https://%s/v1/runtimes"
+
+	certificateInventoryGarbageCollectionPeriod = time.Hour
+	certificateExpiryMetricsPeriod              = time.Minute
 )
 
 type Handlers struct {
@@ -47,8 +51,9 @@ func createAPIHandlers(tokenManager tokens.Manager, tokenCreatorProvider tokens.
 		}
 	}
 
-	revokedCertsRepo := newRevokedCertsRepository(coreClientSet, opts.namespace, opts.revocationConfigMapName)
+	revokedCertsRepo := newRevokedCertsRepository(coreClientSet, opts.namespace, opts.revocationConfigMapName, maxCertificateValidityTime(opts))
 	secretsRepository := newSecretsRepository(coreClientSet)
+	certificateInventory := newCertificateInventory(coreClientSet, opts)
 
 	subjectValues := certificates.CSRSubject{
 		Country:            env.country,
@@ -61,13 +66,13 @@ func createAPIHandlers(tokenManager tokens.Manager, tokenCreatorProvider tokens.
 	contextExtractor := clientcontext.NewContextExtractor(subjectValues)
 
 	return Handlers{
-		internalAPI: newInternalHandler(tokenCreatorProvider, opts, globalMiddlewares, revokedCertsRepo, contextExtractor),
-		externalAPI: newExternalHandler(tokenManager, tokenCreatorProvider, opts, env, globalMiddlewares, secretsRepository, revokedCertsRepo, contextExtractor),
+		internalAPI: newInternalHandler(tokenCreatorProvider, opts, globalMiddlewares, revokedCertsRepo, certificateInventory, contextExtractor),
+		externalAPI: newExternalHandler(tokenManager, tokenCreatorProvider, opts, env, globalMiddlewares, secretsRepository, revokedCertsRepo, certificateInventory, contextExtractor),
 	}
 }
 
 func newExternalHandler(tokenManager tokens.Manager, tokenCreatorProvider tokens.TokenCreatorProvider, opts *options, env *environment, globalMiddlewares []mux.MiddlewareFunc,
-	secretsRepository secrets.Repository, revocationListRepository revocation.RevocationListRepository, contextExtractor *clientcontext.ContextExtractor) http.Handler {
+	secretsRepository secrets.Repository, revocationListRepository revocation.RevocationListRepository, certificateInventory inventory.Repository, contextExtractor *clientcontext.ContextExtractor) http.Handler {
 
 	lookupEnabled := clientcontext.LookupEnabledType(opts.lookupEnabled)
 
@@ -75,7 +80,7 @@ func newExternalHandler(tokenManager tokens.Manager, tokenCreatorProvider tokens
 
 	headerParser := certificates.NewHeaderParser(env.country, env.province, env.locality, env.organization, env.organizationalUnit, opts.central)
 
-	appCertificateService := certificates.NewCertificateService(secretsRepository, certificates.NewCertificateUtility(opts.appCertificateValidityTime), opts.caSecretName, opts.rootCACertificateSecretName)
+	appCertificateService := certificates.NewCertificateService(secretsRepository, certificates.NewCertificateUtility(opts.appCertificateValidityTime, opts.keyPolicy), opts.caSecretName, opts.rootCACertificateSecretName)
 
 	appTokenResolverMiddleware := middlewares.NewTokenResolverMiddleware(tokenManager, clientcontext.NewApplicationContextExtender)
 	clusterTokenResolverMiddleware := middlewares.NewTokenResolverMiddleware(tokenManager, clientcontext.NewClusterContextExtender)
@@ -104,12 +109,17 @@ func newExternalHandler(tokenManager tokens.Manager, tokenCreatorProvider tokens
 		CertService:                 appCertificateService,
 		RevokedCertsRepo:            revocationListRepository,
 		HeaderParser:                headerParser,
+		KeyAlgorithms:               opts.keyPolicy.KeyAlgorithms(),
+		CertificateInventory:        certificateInventory,
 	}
 
 	handlerBuilder.WithApps(appHandlerConfig)
 
+	revocationStatusService := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(opts.appCertificateValidityTime, opts.keyPolicy), opts.caSecretName, revocationListRepository, certificateInventory)
+	handlerBuilder.WithRevocationStatus(revocationStatusService)
+
 	if opts.central {
-		runtimeCertificateService := certificates.NewCertificateService(secretsRepository, certificates.NewCertificateUtility(opts.runtimeCertificateValidityTime), opts.caSecretName, opts.rootCACertificateSecretName)
+		runtimeCertificateService := certificates.NewCertificateService(secretsRepository, certificates.NewCertificateUtility(opts.runtimeCertificateValidityTime, opts.keyPolicy), opts.caSecretName, opts.rootCACertificateSecretName)
 		runtimeTokenTTLMinutes := time.Duration(opts.runtimeTokenExpirationMinutes) * time.Minute
 
 		runtimeHandlerConfig := externalapi.Config{
@@ -121,6 +131,8 @@ func newExternalHandler(tokenManager tokens.Manager, tokenCreatorProvider tokens
 			CertService:                 runtimeCertificateService,
 			RevokedCertsRepo:            revocationListRepository,
 			HeaderParser:                headerParser,
+			KeyAlgorithms:               opts.keyPolicy.KeyAlgorithms(),
+			CertificateInventory:        certificateInventory,
 		}
 
 		handlerBuilder.WithRuntimes(runtimeHandlerConfig)
@@ -130,7 +142,7 @@ func newExternalHandler(tokenManager tokens.Manager, tokenCreatorProvider tokens
 }
 
 func newInternalHandler(tokenManagerProvider tokens.TokenCreatorProvider, opts *options, globalMiddlewares []mux.MiddlewareFunc,
-	revocationListRepository revocation.RevocationListRepository, contextExtractor *clientcontext.ContextExtractor) http.Handler {
+	revocationListRepository revocation.RevocationListRepository, certificateInventory inventory.Repository, contextExtractor *clientcontext.ContextExtractor) http.Handler {
 
 	clusterCtxEnabled := clientcontext.CtxEnabledType(opts.central)
 	clusterContextStrategy := clientcontext.NewClusterContextStrategy(clusterCtxEnabled)
@@ -140,10 +152,11 @@ func newInternalHandler(tokenManagerProvider tokens.TokenCreatorProvider, opts *
 
 	appTokenTTLMinutes := time.Duration(opts.appTokenExpirationMinutes) * time.Minute
 	appHandlerConfig := internalapi.Config{
-		TokenManager:     tokenManagerProvider.WithTTL(appTokenTTLMinutes),
-		CSRInfoURL:       fmt.Sprintf(appCSRInfoFmt, opts.connectorServiceHost),
-		ContextExtractor: contextExtractor.CreateApplicationClientContextService,
-		RevokedCertsRepo: revocationListRepository,
+		TokenManager:         tokenManagerProvider.WithTTL(appTokenTTLMinutes),
+		CSRInfoURL:           fmt.Sprintf(appCSRInfoFmt, opts.connectorServiceHost),
+		ContextExtractor:     contextExtractor.CreateApplicationClientContextService,
+		RevokedCertsRepo:     revocationListRepository,
+		CertificateInventory: certificateInventory,
 	}
 
 	handlerBuilder := internalapi.NewHandlerBuilder(internalapi.FunctionalMiddlewares{
@@ -160,6 +173,7 @@ func newInternalHandler(tokenManagerProvider tokens.TokenCreatorProvider, opts *
 			CSRInfoURL:              fmt.Sprintf(runtimeCSRInfoFmt, opts.connectorServiceHost),
 			ContextExtractor:        contextExtractor.CreateClusterClientContextService,
 			RevokedRuntimeCertsRepo: revocationListRepository,
+			CertificateInventory:    certificateInventory,
 		}
 
 		handlerBuilder.WithRuntimes(runtimeHandlerConfig)
@@ -176,10 +190,34 @@ func newSecretsRepository(coreClientSet *kubernetes.Clientset) secrets.Repositor
 	})
 }
 
-func newRevokedCertsRepository(coreClientSet *kubernetes.Clientset, namespace, revocationSecretName string) revocation.RevocationListRepository {
+func newRevokedCertsRepository(coreClientSet *kubernetes.Clientset, namespace, revocationSecretName string, defaultExpiration time.Duration) revocation.RevocationListRepository {
 	cmi := coreClientSet.CoreV1().ConfigMaps(namespace)
 
-	return revocation.NewRepository(cmi, revocationSecretName)
+	return revocation.NewRepository(cmi, revocationSecretName, defaultExpiration)
+}
+
+// newCertificateInventory creates the inventory of issued certificates, removes the expired records and exposes the expiry metrics in the background
+func newCertificateInventory(coreClientSet *kubernetes.Clientset, opts *options) inventory.Repository {
+	certificateInventory := inventory.NewRepository(coreClientSet.CoreV1().ConfigMaps(opts.namespace))
+	go certificateInventory.RunGarbageCollector(certificateInventoryGarbageCollectionPeriod)
+
+	expiryMetrics, appErr := inventory.NewExpiryMetrics(certificateInventory, opts.certificateExpiryWarningTime)
+	if appErr != nil {
+		log.Errorf("Error while setting up certificate expiry metrics: %s", appErr)
+	} else {
+		go expiryMetrics.Run(certificateExpiryMetricsPeriod)
+	}
+
+	return certificateInventory
+}
+
+// maxCertificateValidityTime is the longest time for which the revoked certificate, which expiry is not known, may still be valid
+func maxCertificateValidityTime(opts *options) time.Duration {
+	if opts.central && opts.runtimeCertificateValidityTime > opts.appCertificateValidityTime {
+		return opts.runtimeCertificateValidityTime
+	}
+
+	return opts.appCertificateValidityTime
 }
 
 func newCoreClientSet() (*kubernetes.Clientset, apperrors.AppError) {
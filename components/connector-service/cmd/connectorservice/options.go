@@ -10,12 +10,19 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	defaultCertificateValidityTime = 90 * 24 * time.Hour
 	defaultNamespace               = "default"
+
+	defaultCARotationPropagationTime = 5 * time.Minute
+	defaultCertificateExpiryWarning  = 14 * 24 * time.Hour
+
+	tokenCacheBackendMemory = "memory"
+	tokenCacheBackendSecret = "secret"
 )
 
 type options struct {
@@ -40,6 +47,13 @@ type options struct {
 	revocationConfigMapName        string
 	lookupEnabled                  bool
 	lookupConfigMapPath            string
+	keyPolicy                      certificates.KeyPolicy
+	tokenCacheBackend              string
+	tokenCacheNamespace            string
+	caTrustBundleSecretName        types.NamespacedName
+	caRotationStatusConfigMapName  string
+	caRotationPropagationTime      time.Duration
+	certificateExpiryWarningTime   time.Duration
 }
 
 type environment struct {
@@ -72,6 +86,15 @@ func parseArgs() *options {
 	revocationConfigMapName := flag.String("revocationConfigMapName", "revocations-config", "Name of the config map containing revoked certificates")
 	lookupEnabled := flag.Bool("lookupEnabled", false, "Determines whether connector should make a call to get gateway endpoint")
 	lookupConfigMapPath := flag.String("lookupConfigMapPath", "/etc/config/config.json", "Path in the pod where Config Map for cluster lookup is stored")
+	allowedKeyAlgorithms := flag.String("allowedKeyAlgorithms", "rsa,ecdsa", "Comma-separated list of key algorithms accepted in CSRs, in the order of preference. Supported values are rsa, ecdsa and ed25519.")
+	minRSAKeySize := flag.Int("minRSAKeySize", certificates.DefaultMinRSAKeySize, "Minimal size of RSA keys accepted in CSRs, expressed in bits.")
+	minECDSAKeySize := flag.Int("minECDSAKeySize", certificates.DefaultMinECDSAKeySize, "Minimal size of ECDSA keys accepted in CSRs, expressed in bits.")
+	caTrustBundleSecretName := flag.String("caTrustBundleSecretName", "", "Namespace/name of the secret with CA certificates trusted by the Istio gateway. If set, the CA is rotated when the next CA is put into the caSecretName secret.")
+	caRotationStatusConfigMapName := flag.String("caRotationStatusConfigMapName", "ca-rotation-status", "Name of the config map containing the CA rotation status")
+	caRotationPropagationTime := flag.String("caRotationPropagationTime", "5m", "Time for which the next CA is trusted before it is used for signing certificates.")
+	certificateExpiryWarningTime := flag.String("certificateExpiryWarningTime", "14d", "Time before the expiry from which the issued certificates are reported as expiring in the metrics.")
+	tokenCacheBackend := flag.String("tokenCacheBackend", tokenCacheBackendMemory, "Storage of the one-time tokens. Supported values are memory and secret, which shares tokens between the replicas.")
+	tokenCacheNamespace := flag.String("tokenCacheNamespace", "connector-service-tokens", "Namespace dedicated to the secrets of the one-time tokens stored with the secret token cache backend.")
 
 	flag.Parse()
 
@@ -85,6 +108,29 @@ func parseArgs() *options {
 		logrus.Infof("Failed to parse certificate validity time for applications: %s, using default value.", err)
 	}
 
+	keyPolicy, err := certificates.ParseKeyPolicy(*allowedKeyAlgorithms, *minRSAKeySize, *minECDSAKeySize)
+	if err != nil {
+		logrus.Infof("Failed to parse allowed key algorithms: %s, using default value.", err)
+		keyPolicy = certificates.DefaultKeyPolicy()
+	}
+
+	propagationTime, err := parseDuration(*caRotationPropagationTime)
+	if err != nil {
+		logrus.Infof("Failed to parse CA rotation propagation time: %s, using default value.", err)
+		propagationTime = defaultCARotationPropagationTime
+	}
+
+	expiryWarningTime, err := parseDuration(*certificateExpiryWarningTime)
+	if err != nil {
+		logrus.Infof("Failed to parse certificate expiry warning time: %s, using default value.", err)
+		expiryWarningTime = defaultCertificateExpiryWarning
+	}
+
+	// falling back to the memory backend would make the tokens issued by one replica unusable on the others
+	if err := validateTokenCacheBackend(*tokenCacheBackend); err != nil {
+		logrus.Fatalf("Invalid token cache backend: %s.", err)
+	}
+
 	return &options{
 		appName:                        *appName,
 		externalAPIPort:                *externalAPIPort,
@@ -107,6 +153,13 @@ func parseArgs() *options {
 		revocationConfigMapName:        *revocationConfigMapName,
 		lookupEnabled:                  *lookupEnabled,
 		lookupConfigMapPath:            *lookupConfigMapPath,
+		keyPolicy:                      keyPolicy,
+		tokenCacheBackend:              *tokenCacheBackend,
+		tokenCacheNamespace:            *tokenCacheNamespace,
+		caTrustBundleSecretName:        parseNamespacedName(*caTrustBundleSecretName),
+		caRotationStatusConfigMapName:  *caRotationStatusConfigMapName,
+		caRotationPropagationTime:      propagationTime,
+		certificateExpiryWarningTime:   expiryWarningTime,
 	}
 }
 
@@ -115,12 +168,16 @@ func (o *options) String() string {
 		"--appTokenExpirationMinutes=%d --runtimeTokenExpirationMinutes=%d --caSecretName=%s --rootCACertificateSecretName=%s --requestLogging=%t "+
 		"--connectorServiceHost=%s --certificateProtectedHost=%s --gatewayBaseURL=%s "+
 		"--appsInfoURL=%s --runtimesInfoURL=%s --central=%t --appCertificateValidityTime=%s --runtimeCertificateValidityTime=%s "+
-		"--revocationConfigMapName=%s --lookupEnabled=%t --lookupConfigMapPath=%s",
+		"--revocationConfigMapName=%s --lookupEnabled=%t --lookupConfigMapPath=%s "+
+		"--allowedKeyAlgorithms=%s --minRSAKeySize=%d --minECDSAKeySize=%d --tokenCacheBackend=%s --tokenCacheNamespace=%s "+
+		"--caTrustBundleSecretName=%s --caRotationStatusConfigMapName=%s --caRotationPropagationTime=%s --certificateExpiryWarningTime=%s",
 		o.appName, o.externalAPIPort, o.internalAPIPort, o.namespace, o.tokenLength,
 		o.appTokenExpirationMinutes, o.runtimeTokenExpirationMinutes, o.caSecretName, o.rootCACertificateSecretName, o.requestLogging,
 		o.connectorServiceHost, o.certificateProtectedHost, o.gatewayBaseURL,
 		o.appsInfoURL, o.runtimesInfoURL, o.central, o.appCertificateValidityTime, o.runtimeCertificateValidityTime,
-		o.revocationConfigMapName, o.lookupEnabled, o.lookupConfigMapPath)
+		o.revocationConfigMapName, o.lookupEnabled, o.lookupConfigMapPath,
+		strings.Join(o.keyPolicy.Algorithms, ","), o.keyPolicy.MinRSAKeySize, o.keyPolicy.MinECDSAKeySize, o.tokenCacheBackend, o.tokenCacheNamespace,
+		o.caTrustBundleSecretName, o.caRotationStatusConfigMapName, o.caRotationPropagationTime, o.certificateExpiryWarningTime)
 }
 
 func parseEnv() *environment {
@@ -155,6 +212,14 @@ func parseDuration(durationString string) (time.Duration, error) {
 	return time.Duration(timeLength) * unitsMap[timeUnit], nil
 }
 
+func validateTokenCacheBackend(backend string) error {
+	if backend != tokenCacheBackendMemory && backend != tokenCacheBackendSecret {
+		return fmt.Errorf("unsupported value %s, supported values are %s and %s", backend, tokenCacheBackendMemory, tokenCacheBackendSecret)
+	}
+
+	return nil
+}
+
 func parseNamespacedName(value string) types.NamespacedName {
 	parts := strings.Split(value, string(types.Separator))
 
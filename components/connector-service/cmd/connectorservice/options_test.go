@@ -34,6 +34,26 @@ func TestOptions_ParseEnv(t *testing.T) {
 	})
 }
 
+func TestOptions_ValidateTokenCacheBackend(t *testing.T) {
+	t.Run("should accept supported backends", func(t *testing.T) {
+		for _, backend := range []string{tokenCacheBackendMemory, tokenCacheBackendSecret} {
+			//when
+			err := validateTokenCacheBackend(backend)
+
+			//then
+			assert.NoError(t, err, backend)
+		}
+	})
+
+	t.Run("should reject unknown backend", func(t *testing.T) {
+		//when
+		err := validateTokenCacheBackend("configmap")
+
+		//then
+		assert.Error(t, err)
+	})
+}
+
 func TestOptions_ParseDuration(t *testing.T) {
 	t.Run("should parse proper duration string", func(t *testing.T) {
 		//given
@@ -0,0 +1,386 @@
+package carotation
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	caCertificateSecretKey         = "ca.crt"
+	caKeySecretKey                 = "ca.key"
+	nextCACertificateSecretKey     = "next-ca.crt"
+	nextCAKeySecretKey             = "next-ca.key"
+	previousCACertificateSecretKey = "previous-ca.crt"
+	previousCAKeySecretKey         = "previous-ca.key"
+
+	trustBundleSecretKey = "cacert"
+)
+
+type SecretsManagerConstructor func(namespace string) SecretsManager
+
+type SecretsManager interface {
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*v1.Secret, error)
+	Update(ctx context.Context, secret *v1.Secret, options metav1.UpdateOptions) (*v1.Secret, error)
+}
+
+type Config struct {
+	// CASecretName is the secret with the active CA, the next CA to be introduced and the previous CA being retired
+	CASecretName types.NamespacedName
+	// TrustBundleSecretName is the secret with the CA certificates trusted by the Istio gateway
+	TrustBundleSecretName types.NamespacedName
+	StatusConfigMapName   string
+	// PropagationTime is the time for which the next CA is trusted before it is used for signing
+	PropagationTime time.Duration
+	// MaxCertificateValidityTime is the longest validity time of the issued certificates
+	MaxCertificateValidityTime time.Duration
+}
+
+type Controller interface {
+	// Reconcile moves the CA rotation to the next phase when it is due and updates the status
+	Reconcile() error
+}
+
+type controller struct {
+	ctx                       context.Context
+	secretsManagerConstructor SecretsManagerConstructor
+	configMapManager          ConfigMapManager
+	config                    Config
+}
+
+// NewController creates the controller rotating the CA without downtime. The rotation starts when the next CA is put into the CA secret.
+// The next CA is added to the trust bundle and, after the propagation time, replaces the active CA. The replaced CA stays in the trust bundle
+// until all certificates it issued expire. Every replica may run the controller, the secrets are updated with optimistic concurrency.
+func NewController(secretsManagerConstructor SecretsManagerConstructor, configMapManager ConfigMapManager, config Config) *controller {
+	return &controller{
+		ctx:                       context.Background(),
+		secretsManagerConstructor: secretsManagerConstructor,
+		configMapManager:          configMapManager,
+		config:                    config,
+	}
+}
+
+// Run reconciles the CA rotation every period, it never returns
+func (c *controller) Run(period time.Duration) {
+	for {
+		if err := c.Reconcile(); err != nil {
+			log.Errorf("Failed to reconcile CA rotation: %s", err)
+		}
+
+		time.Sleep(period)
+	}
+}
+
+func (c *controller) Reconcile() error {
+	now := time.Now()
+
+	configMap, status, err := c.getStatus()
+	if err != nil {
+		return fmt.Errorf("failed to read CA rotation status: %s", err)
+	}
+
+	caSecret, err := c.secretsManagerConstructor(c.config.CASecretName.Namespace).Get(c.ctx, c.config.CASecretName.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s secret: %s", c.config.CASecretName, err)
+	}
+
+	active, err := parseCertificate(caSecret.Data[caCertificateSecretKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse active CA certificate: %s", err)
+	}
+
+	next, err := parseOptionalCertificate(caSecret.Data[nextCACertificateSecretKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse next CA certificate: %s", err)
+	}
+
+	previous, err := parseOptionalCertificate(caSecret.Data[previousCACertificateSecretKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse previous CA certificate: %s", err)
+	}
+
+	var newStatus Status
+	switch {
+	case previous != nil:
+		newStatus, err = c.retire(now, caSecret, status, active, previous, next)
+	case next != nil:
+		newStatus, err = c.introduce(now, caSecret, status, active, next)
+	default:
+		newStatus, err = c.stabilize(now, status, active)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.saveStatus(configMap, newStatus)
+}
+
+// introduce adds the next CA to the trust bundle and makes it active after the propagation time
+func (c *controller) introduce(now time.Time, caSecret *v1.Secret, status Status, active, next *x509.Certificate) (Status, error) {
+	if fingerprint(next) == fingerprint(active) {
+		delete(caSecret.Data, nextCACertificateSecretKey)
+		delete(caSecret.Data, nextCAKeySecretKey)
+
+		if err := c.updateCASecret(caSecret); err != nil {
+			return Status{}, err
+		}
+
+		return c.stabilize(now, status, active)
+	}
+
+	if _, keyPairErr := tls.X509KeyPair(caSecret.Data[nextCACertificateSecretKey], caSecret.Data[nextCAKeySecretKey]); keyPairErr != nil {
+		newStatus, err := c.stabilize(now, status, active)
+		if err != nil {
+			return Status{}, err
+		}
+
+		newStatus.NextCA = newCAInfo(next)
+		newStatus.Message = fmt.Sprintf("Next CA is not introduced, certificate or key is invalid: %s", keyPairErr)
+		return newStatus, nil
+	}
+
+	if err := c.updateTrustBundle([]*x509.Certificate{active, next}, nil); err != nil {
+		return Status{}, err
+	}
+
+	newStatus := Status{
+		Phase:          PhaseIntroducing,
+		PhaseStartedAt: status.PhaseStartedAt,
+		ActiveCA:       newCAInfo(active),
+		NextCA:         newCAInfo(next),
+	}
+	if status.Phase != PhaseIntroducing || status.NextCA.Fingerprint != newStatus.NextCA.Fingerprint || status.PhaseStartedAt.IsZero() {
+		newStatus.PhaseStartedAt = now
+	}
+
+	activationTime := newStatus.PhaseStartedAt.Add(c.config.PropagationTime)
+	if now.Before(activationTime) {
+		newStatus.Message = fmt.Sprintf("Next CA is trusted and is used for signing after %s", formatTime(activationTime))
+		return newStatus, nil
+	}
+
+	caSecret.Data[previousCACertificateSecretKey] = caSecret.Data[caCertificateSecretKey]
+	caSecret.Data[previousCAKeySecretKey] = caSecret.Data[caKeySecretKey]
+	caSecret.Data[caCertificateSecretKey] = caSecret.Data[nextCACertificateSecretKey]
+	caSecret.Data[caKeySecretKey] = caSecret.Data[nextCAKeySecretKey]
+	delete(caSecret.Data, nextCACertificateSecretKey)
+	delete(caSecret.Data, nextCAKeySecretKey)
+
+	if err := c.updateCASecret(caSecret); err != nil {
+		return Status{}, err
+	}
+
+	log.Infof("CA %s replaced with CA %s", fingerprint(active), fingerprint(next))
+
+	removalTime := c.removalTime(now, active)
+
+	return Status{
+		Phase:                 PhaseRetiring,
+		PhaseStartedAt:        now,
+		ActiveCA:              newCAInfo(next),
+		PreviousCA:            newCAInfo(active),
+		PreviousCARemovalTime: removalTime,
+		Message:               fmt.Sprintf("Previous CA is trusted until %s", formatTime(removalTime)),
+	}, nil
+}
+
+// retire keeps the previous CA in the trust bundle until the certificates it issued expire
+func (c *controller) retire(now time.Time, caSecret *v1.Secret, status Status, active, previous, next *x509.Certificate) (Status, error) {
+	if err := c.updateTrustBundle([]*x509.Certificate{active, previous}, nil); err != nil {
+		return Status{}, err
+	}
+
+	newStatus := Status{
+		Phase:                 PhaseRetiring,
+		PhaseStartedAt:        status.PhaseStartedAt,
+		ActiveCA:              newCAInfo(active),
+		NextCA:                newCAInfo(next),
+		PreviousCA:            newCAInfo(previous),
+		PreviousCARemovalTime: status.PreviousCARemovalTime,
+	}
+	if status.Phase != PhaseRetiring || status.PreviousCA.Fingerprint != newStatus.PreviousCA.Fingerprint || status.PreviousCARemovalTime.IsZero() {
+		// the status is missing or outdated, so the previous CA might have issued certificates until now
+		newStatus.PhaseStartedAt = now
+		newStatus.PreviousCARemovalTime = c.removalTime(now, previous)
+	}
+
+	if now.Before(newStatus.PreviousCARemovalTime) {
+		newStatus.Message = fmt.Sprintf("Previous CA is trusted until %s", formatTime(newStatus.PreviousCARemovalTime))
+		if next != nil {
+			newStatus.Message += ", next CA is introduced afterwards"
+		}
+		return newStatus, nil
+	}
+
+	// the trust bundle is updated first, so that the removal is repeated if updating the CA secret fails
+	if fingerprint(previous) != fingerprint(active) {
+		if err := c.updateTrustBundle(nil, []*x509.Certificate{previous}); err != nil {
+			return Status{}, err
+		}
+	}
+
+	delete(caSecret.Data, previousCACertificateSecretKey)
+	delete(caSecret.Data, previousCAKeySecretKey)
+
+	if err := c.updateCASecret(caSecret); err != nil {
+		return Status{}, err
+	}
+
+	log.Infof("CA %s removed", fingerprint(previous))
+
+	return Status{
+		Phase:          PhaseStable,
+		PhaseStartedAt: now,
+		ActiveCA:       newCAInfo(active),
+		NextCA:         newCAInfo(next),
+	}, nil
+}
+
+func (c *controller) stabilize(now time.Time, status Status, active *x509.Certificate) (Status, error) {
+	if err := c.updateTrustBundle([]*x509.Certificate{active}, nil); err != nil {
+		return Status{}, err
+	}
+
+	newStatus := Status{
+		Phase:          PhaseStable,
+		PhaseStartedAt: status.PhaseStartedAt,
+		ActiveCA:       newCAInfo(active),
+	}
+	if status.Phase != PhaseStable || status.PhaseStartedAt.IsZero() {
+		newStatus.PhaseStartedAt = now
+	}
+
+	return newStatus, nil
+}
+
+// removalTime is the expiry of the last certificate which the CA might have issued
+func (c *controller) removalTime(now time.Time, ca *x509.Certificate) time.Time {
+	removalTime := now.Add(c.config.MaxCertificateValidityTime)
+	if ca.NotAfter.Before(removalTime) {
+		return ca.NotAfter
+	}
+
+	return removalTime
+}
+
+// updateCASecret fails with conflict if other replica modified the secret in the meantime, the reconciliation is repeated then
+func (c *controller) updateCASecret(caSecret *v1.Secret) error {
+	_, err := c.secretsManagerConstructor(c.config.CASecretName.Namespace).Update(c.ctx, caSecret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update %s secret: %s", c.config.CASecretName, err)
+	}
+
+	return nil
+}
+
+func (c *controller) updateTrustBundle(add, remove []*x509.Certificate) error {
+	secretsManager := c.secretsManagerConstructor(c.config.TrustBundleSecretName.Namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret, err := secretsManager.Get(c.ctx, c.config.TrustBundleSecretName.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		bundle := secret.Data[trustBundleSecretKey]
+		updated := bundle
+		for _, certificate := range add {
+			updated = addCertificate(updated, certificate)
+		}
+		for _, certificate := range remove {
+			updated = removeCertificate(updated, certificate)
+		}
+
+		if bytes.Equal(updated, bundle) {
+			return nil
+		}
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[trustBundleSecretKey] = updated
+
+		_, err = secretsManager.Update(c.ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s trust bundle: %s", c.config.TrustBundleSecretName, err)
+	}
+
+	return nil
+}
+
+func addCertificate(bundle []byte, certificate *x509.Certificate) []byte {
+	for _, block := range decodeBundle(bundle) {
+		if bytes.Equal(block.Bytes, certificate.Raw) {
+			return bundle
+		}
+	}
+
+	updated := append([]byte{}, bundle...)
+	if len(updated) > 0 && updated[len(updated)-1] != '\n' {
+		updated = append(updated, '\n')
+	}
+
+	return append(updated, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})...)
+}
+
+func removeCertificate(bundle []byte, certificate *x509.Certificate) []byte {
+	blocks := decodeBundle(bundle)
+
+	var updated []byte
+	removed := false
+	for _, block := range blocks {
+		if bytes.Equal(block.Bytes, certificate.Raw) {
+			removed = true
+			continue
+		}
+		updated = append(updated, pem.EncodeToMemory(block)...)
+	}
+
+	if !removed {
+		return bundle
+	}
+
+	return updated
+}
+
+func decodeBundle(bundle []byte) []*pem.Block {
+	var blocks []*pem.Block
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return blocks
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+func parseCertificate(encodedCertificate []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(encodedCertificate)
+	if block == nil {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseOptionalCertificate(encodedCertificate []byte) (*x509.Certificate, error) {
+	if len(encodedCertificate) == 0 {
+		return nil, nil
+	}
+
+	return parseCertificate(encodedCertificate)
+}
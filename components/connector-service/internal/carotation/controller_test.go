@@ -0,0 +1,271 @@
+package carotation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	integrationNamespace = "kyma-integration"
+	istioNamespace       = "istio-system"
+	statusConfigMapName  = "ca-rotation-status"
+)
+
+var (
+	caSecretName          = types.NamespacedName{Namespace: integrationNamespace, Name: "connector-service-app-ca"}
+	trustBundleSecretName = types.NamespacedName{Namespace: istioNamespace, Name: "app-connector-certs"}
+)
+
+func TestController_Reconcile(t *testing.T) {
+
+	t.Run("should add active CA to trust bundle", func(t *testing.T) {
+		// given
+		activeCrt, activeKey := createCA(t, time.Hour)
+		clientset := prepareClientset(map[string][]byte{caCertificateSecretKey: activeCrt, caKeySecretKey: activeKey}, nil)
+
+		controller := newTestController(clientset, time.Minute, time.Hour)
+
+		// when
+		err := controller.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{activeCrt}, trustBundle(t, clientset))
+
+		status := readStatus(t, clientset)
+		assert.Equal(t, PhaseStable, status.Phase)
+		assert.Equal(t, fingerprintOf(t, activeCrt), status.ActiveCA.Fingerprint)
+	})
+
+	t.Run("should trust next CA without using it for signing during propagation time", func(t *testing.T) {
+		// given
+		activeCrt, activeKey := createCA(t, time.Hour)
+		nextCrt, nextKey := createCA(t, time.Hour)
+		clientset := prepareClientset(map[string][]byte{
+			caCertificateSecretKey:     activeCrt,
+			caKeySecretKey:             activeKey,
+			nextCACertificateSecretKey: nextCrt,
+			nextCAKeySecretKey:         nextKey,
+		}, activeCrt)
+
+		controller := newTestController(clientset, time.Minute, time.Hour)
+
+		// when
+		err := controller.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{activeCrt, nextCrt}, trustBundle(t, clientset))
+		assert.Equal(t, activeCrt, caSecretData(t, clientset)[caCertificateSecretKey])
+
+		status := readStatus(t, clientset)
+		assert.Equal(t, PhaseIntroducing, status.Phase)
+		assert.Equal(t, fingerprintOf(t, nextCrt), status.NextCA.Fingerprint)
+	})
+
+	t.Run("should sign with next CA after propagation time and keep previous CA trusted", func(t *testing.T) {
+		// given
+		activeCrt, activeKey := createCA(t, time.Hour)
+		nextCrt, nextKey := createCA(t, time.Hour)
+		clientset := prepareClientset(map[string][]byte{
+			caCertificateSecretKey:     activeCrt,
+			caKeySecretKey:             activeKey,
+			nextCACertificateSecretKey: nextCrt,
+			nextCAKeySecretKey:         nextKey,
+		}, activeCrt)
+
+		controller := newTestController(clientset, 0, time.Minute)
+
+		// when
+		err := controller.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{activeCrt, nextCrt}, trustBundle(t, clientset))
+
+		secretData := caSecretData(t, clientset)
+		assert.Equal(t, nextCrt, secretData[caCertificateSecretKey])
+		assert.Equal(t, nextKey, secretData[caKeySecretKey])
+		assert.Equal(t, activeCrt, secretData[previousCACertificateSecretKey])
+		assert.Empty(t, secretData[nextCACertificateSecretKey])
+
+		status := readStatus(t, clientset)
+		assert.Equal(t, PhaseRetiring, status.Phase)
+		assert.Equal(t, fingerprintOf(t, activeCrt), status.PreviousCA.Fingerprint)
+		assert.True(t, status.PreviousCARemovalTime.After(time.Now()))
+
+		// when
+		err = controller.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, PhaseRetiring, readStatus(t, clientset).Phase)
+		assert.Equal(t, [][]byte{activeCrt, nextCrt}, trustBundle(t, clientset))
+	})
+
+	t.Run("should remove previous CA when certificates it issued expired", func(t *testing.T) {
+		// given
+		rootCrt, _ := createCA(t, time.Hour)
+		activeCrt, activeKey := createCA(t, time.Hour)
+		previousCrt, previousKey := createCA(t, time.Hour)
+		clientset := prepareClientset(map[string][]byte{
+			caCertificateSecretKey:         activeCrt,
+			caKeySecretKey:                 activeKey,
+			previousCACertificateSecretKey: previousCrt,
+			previousCAKeySecretKey:         previousKey,
+		}, append(append(rootCrt, previousCrt...), activeCrt...))
+
+		controller := newTestController(clientset, 0, 0)
+
+		// when
+		err := controller.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{rootCrt, activeCrt}, trustBundle(t, clientset))
+
+		secretData := caSecretData(t, clientset)
+		assert.Equal(t, activeCrt, secretData[caCertificateSecretKey])
+		assert.Empty(t, secretData[previousCACertificateSecretKey])
+		assert.Empty(t, secretData[previousCAKeySecretKey])
+
+		assert.Equal(t, PhaseStable, readStatus(t, clientset).Phase)
+	})
+
+	t.Run("should not introduce next CA with invalid key", func(t *testing.T) {
+		// given
+		activeCrt, activeKey := createCA(t, time.Hour)
+		nextCrt, _ := createCA(t, time.Hour)
+		clientset := prepareClientset(map[string][]byte{
+			caCertificateSecretKey:     activeCrt,
+			caKeySecretKey:             activeKey,
+			nextCACertificateSecretKey: nextCrt,
+			nextCAKeySecretKey:         activeKey,
+		}, activeCrt)
+
+		controller := newTestController(clientset, 0, time.Hour)
+
+		// when
+		err := controller.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{activeCrt}, trustBundle(t, clientset))
+		assert.Equal(t, activeCrt, caSecretData(t, clientset)[caCertificateSecretKey])
+
+		status := readStatus(t, clientset)
+		assert.Equal(t, PhaseStable, status.Phase)
+		assert.NotEmpty(t, status.Message)
+	})
+
+	t.Run("should return error when CA secret does not exist", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+
+		controller := newTestController(clientset, 0, time.Hour)
+
+		// when
+		err := controller.Reconcile()
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func newTestController(clientset kubernetes.Interface, propagationTime, maxCertificateValidityTime time.Duration) *controller {
+	return NewController(func(namespace string) SecretsManager {
+		return clientset.CoreV1().Secrets(namespace)
+	}, clientset.CoreV1().ConfigMaps(integrationNamespace), Config{
+		CASecretName:               caSecretName,
+		TrustBundleSecretName:      trustBundleSecretName,
+		StatusConfigMapName:        statusConfigMapName,
+		PropagationTime:            propagationTime,
+		MaxCertificateValidityTime: maxCertificateValidityTime,
+	})
+}
+
+func prepareClientset(caSecretData map[string][]byte, trustBundle []byte) *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: caSecretName.Name, Namespace: caSecretName.Namespace},
+			Data:       caSecretData,
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: trustBundleSecretName.Name, Namespace: trustBundleSecretName.Namespace},
+			Data:       map[string][]byte{trustBundleSecretKey: trustBundle},
+		},
+	)
+}
+
+func caSecretData(t *testing.T, clientset *fake.Clientset) map[string][]byte {
+	secret, err := clientset.CoreV1().Secrets(caSecretName.Namespace).Get(context.Background(), caSecretName.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return secret.Data
+}
+
+func trustBundle(t *testing.T, clientset *fake.Clientset) [][]byte {
+	secret, err := clientset.CoreV1().Secrets(trustBundleSecretName.Namespace).Get(context.Background(), trustBundleSecretName.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	var certificates [][]byte
+	for _, block := range decodeBundle(secret.Data[trustBundleSecretKey]) {
+		certificates = append(certificates, pem.EncodeToMemory(block))
+	}
+
+	return certificates
+}
+
+func readStatus(t *testing.T, clientset *fake.Clientset) Status {
+	configMap, err := clientset.CoreV1().ConfigMaps(integrationNamespace).Get(context.Background(), statusConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return decodeStatus(configMap.Data)
+}
+
+func fingerprintOf(t *testing.T, encodedCrt []byte) string {
+	certificate, err := parseCertificate(encodedCrt)
+	require.NoError(t, err)
+	return fingerprint(certificate)
+}
+
+func createCA(t *testing.T, validity time.Duration) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: "Kyma"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	rawCrt, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rawCrt}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey})
+}
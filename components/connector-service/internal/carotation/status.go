@@ -0,0 +1,175 @@
+package carotation
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Phase string
+
+const (
+	// PhaseStable means that only the active CA is trusted and used for signing
+	PhaseStable Phase = "Stable"
+	// PhaseIntroducing means that the next CA is trusted, but the active CA is still used for signing
+	PhaseIntroducing Phase = "Introducing"
+	// PhaseRetiring means that the certificates are signed with the new CA, and the previous CA is trusted until the certificates it issued expire
+	PhaseRetiring Phase = "Retiring"
+)
+
+const (
+	phaseKey                 = "phase"
+	phaseStartedAtKey        = "phaseStartedAt"
+	activeCAFingerprintKey   = "activeCAFingerprint"
+	activeCAExpiresAtKey     = "activeCAExpiresAt"
+	nextCAFingerprintKey     = "nextCAFingerprint"
+	nextCAExpiresAtKey       = "nextCAExpiresAt"
+	previousCAFingerprintKey = "previousCAFingerprint"
+	previousCARemovalTimeKey = "previousCARemovalTime"
+	messageKey               = "message"
+)
+
+type ConfigMapManager interface {
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*v1.ConfigMap, error)
+	Create(ctx context.Context, configMap *v1.ConfigMap, options metav1.CreateOptions) (*v1.ConfigMap, error)
+	Update(ctx context.Context, configMap *v1.ConfigMap, options metav1.UpdateOptions) (*v1.ConfigMap, error)
+}
+
+// CAInfo identifies the CA certificate by the SHA-256 fingerprint
+type CAInfo struct {
+	Fingerprint string
+	ExpiresAt   time.Time
+}
+
+// Status is the state of the CA rotation stored in the config map
+type Status struct {
+	Phase          Phase
+	PhaseStartedAt time.Time
+	ActiveCA       CAInfo
+	NextCA         CAInfo
+	PreviousCA     CAInfo
+	// PreviousCARemovalTime is the time after which no unexpired certificate issued by the previous CA exists
+	PreviousCARemovalTime time.Time
+	Message               string
+}
+
+func newCAInfo(certificate *x509.Certificate) CAInfo {
+	if certificate == nil {
+		return CAInfo{}
+	}
+
+	return CAInfo{
+		Fingerprint: fingerprint(certificate),
+		ExpiresAt:   certificate.NotAfter.UTC(),
+	}
+}
+
+func fingerprint(certificate *x509.Certificate) string {
+	hash := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(hash[:])
+}
+
+func (c *controller) getStatus() (*v1.ConfigMap, Status, error) {
+	configMap, err := c.configMapManager.Get(c.ctx, c.config.StatusConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, Status{}, nil
+		}
+		return nil, Status{}, err
+	}
+
+	return configMap, decodeStatus(configMap.Data), nil
+}
+
+// saveStatus creates the config map if it does not exist, the update fails with conflict if other replica modified it in the meantime
+func (c *controller) saveStatus(configMap *v1.ConfigMap, status Status) error {
+	data := encodeStatus(status)
+
+	if configMap == nil {
+		_, err := c.configMapManager.Create(c.ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: c.config.StatusConfigMapName},
+			Data:       data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+
+	if equalData(configMap.Data, data) {
+		return nil
+	}
+
+	configMap.Data = data
+	_, err := c.configMapManager.Update(c.ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+func encodeStatus(status Status) map[string]string {
+	data := map[string]string{
+		phaseKey:               string(status.Phase),
+		phaseStartedAtKey:      formatTime(status.PhaseStartedAt),
+		activeCAFingerprintKey: status.ActiveCA.Fingerprint,
+		activeCAExpiresAtKey:   formatTime(status.ActiveCA.ExpiresAt),
+	}
+
+	if status.NextCA.Fingerprint != "" {
+		data[nextCAFingerprintKey] = status.NextCA.Fingerprint
+		data[nextCAExpiresAtKey] = formatTime(status.NextCA.ExpiresAt)
+	}
+	if status.PreviousCA.Fingerprint != "" {
+		data[previousCAFingerprintKey] = status.PreviousCA.Fingerprint
+		data[previousCARemovalTimeKey] = formatTime(status.PreviousCARemovalTime)
+	}
+	if status.Message != "" {
+		data[messageKey] = status.Message
+	}
+
+	return data
+}
+
+func decodeStatus(data map[string]string) Status {
+	return Status{
+		Phase:                 Phase(data[phaseKey]),
+		PhaseStartedAt:        parseTime(data[phaseStartedAtKey]),
+		ActiveCA:              CAInfo{Fingerprint: data[activeCAFingerprintKey], ExpiresAt: parseTime(data[activeCAExpiresAtKey])},
+		NextCA:                CAInfo{Fingerprint: data[nextCAFingerprintKey], ExpiresAt: parseTime(data[nextCAExpiresAtKey])},
+		PreviousCA:            CAInfo{Fingerprint: data[previousCAFingerprintKey]},
+		PreviousCARemovalTime: parseTime(data[previousCARemovalTimeKey]),
+		Message:               data[messageKey],
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+func equalData(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		if other, found := b[key]; !found || other != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,8 +1,8 @@
 package certificates
 
 import (
+	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"math/big"
@@ -11,22 +11,30 @@ import (
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
 )
 
+// serial numbers identify revoked certificates in the CRL and in OCSP responses so they must be unique
+var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// LegacySerialNumber was the serial number of all certificates issued before the serial numbers became unique
+var LegacySerialNumber = big.NewInt(2)
+
 type CertificateUtility interface {
 	LoadCert(encodedData []byte) (*x509.Certificate, apperrors.AppError)
-	LoadKey(encodedData []byte) (*rsa.PrivateKey, apperrors.AppError)
+	LoadKey(encodedData []byte) (crypto.Signer, apperrors.AppError)
 	LoadCSR(encodedData []byte) (*x509.CertificateRequest, apperrors.AppError)
 	CheckCSRValues(csr *x509.CertificateRequest, subject CSRSubject) apperrors.AppError
-	SignCSR(caCrt *x509.Certificate, csr *x509.CertificateRequest, caKey *rsa.PrivateKey) ([]byte, apperrors.AppError)
+	SignCSR(caCrt *x509.Certificate, csr *x509.CertificateRequest, caKey crypto.Signer) ([]byte, apperrors.AppError)
 	AddCertificateHeaderAndFooter(crtRaw []byte) []byte
 }
 
 type certificateUtility struct {
 	certificateValidityTime time.Duration
+	keyPolicy               KeyPolicy
 }
 
-func NewCertificateUtility(certificateValidityTime time.Duration) CertificateUtility {
+func NewCertificateUtility(certificateValidityTime time.Duration, keyPolicy KeyPolicy) CertificateUtility {
 	return &certificateUtility{
 		certificateValidityTime: certificateValidityTime,
+		keyPolicy:               keyPolicy,
 	}
 }
 
@@ -45,7 +53,7 @@ func (cu *certificateUtility) LoadCert(encodedData []byte) (*x509.Certificate, a
 	return caCRT, nil
 }
 
-func (cu *certificateUtility) LoadKey(encodedData []byte) (*rsa.PrivateKey, apperrors.AppError) {
+func (cu *certificateUtility) LoadKey(encodedData []byte) (crypto.Signer, apperrors.AppError) {
 
 	pemBlock, _ := pem.Decode(encodedData)
 	if pemBlock == nil {
@@ -56,12 +64,21 @@ func (cu *certificateUtility) LoadKey(encodedData []byte) (*rsa.PrivateKey, appe
 		return caPrivateKey, nil
 	}
 
+	if caPrivateKey, err := x509.ParseECPrivateKey(pemBlock.Bytes); err == nil {
+		return caPrivateKey, nil
+	}
+
 	caPrivateKey, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
 	if err != nil {
 		return nil, apperrors.Internal("Error while parsing private key: %s", err)
 	}
 
-	return caPrivateKey.(*rsa.PrivateKey), nil
+	signer, ok := caPrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, apperrors.Internal("Error while parsing private key: unsupported key type %T", caPrivateKey)
+	}
+
+	return signer, nil
 }
 
 func (cu *certificateUtility) LoadCSR(encodedData []byte) (*x509.CertificateRequest, apperrors.AppError) {
@@ -118,11 +135,17 @@ func (cu *certificateUtility) CheckCSRValues(csr *x509.CertificateRequest, subje
 	} else if csr.Subject.Province[0] != subject.Province {
 		return apperrors.WrongInput("CSR: Invalid province provided.")
 	}
-	return nil
+
+	return cu.keyPolicy.CheckPublicKey(csr.PublicKey)
 }
 
-func (cu *certificateUtility) SignCSR(caCrt *x509.Certificate, csr *x509.CertificateRequest, caKey *rsa.PrivateKey) ([]byte, apperrors.AppError) {
-	clientCRTTemplate := cu.prepareCRTTemplate(csr)
+func (cu *certificateUtility) SignCSR(caCrt *x509.Certificate, csr *x509.CertificateRequest, caKey crypto.Signer) ([]byte, apperrors.AppError) {
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, apperrors.Internal("Error while generating certificate serial number: %s", err)
+	}
+
+	clientCRTTemplate := cu.prepareCRTTemplate(csr, serialNumber)
 
 	clientCrtRaw, err := x509.CreateCertificate(rand.Reader, &clientCRTTemplate, caCrt, csr.PublicKey, caKey)
 	if err != nil {
@@ -132,11 +155,10 @@ func (cu *certificateUtility) SignCSR(caCrt *x509.Certificate, csr *x509.Certifi
 	return clientCrtRaw, nil
 }
 
-func (cu *certificateUtility) prepareCRTTemplate(csr *x509.CertificateRequest) x509.Certificate {
+func (cu *certificateUtility) prepareCRTTemplate(csr *x509.CertificateRequest, serialNumber *big.Int) x509.Certificate {
+	// the signature algorithm is chosen from the CA key, which doesn't have to be of the same type as the CSR key
 	return x509.Certificate{
-		SignatureAlgorithm: csr.SignatureAlgorithm,
-
-		SerialNumber: big.NewInt(2),
+		SerialNumber: serialNumber,
 		Subject:      csr.Subject,
 		NotBefore:    time.Now(),
 		NotAfter:     time.Now().Add(cu.certificateValidityTime),
@@ -1,10 +1,16 @@
 package certificates
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"math/big"
 	"testing"
 	"time"
 
@@ -228,7 +234,7 @@ func TestCertificateUtility_LoadCert(t *testing.T) {
 
 	t.Run("should load cert", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadCert(encodedCert)
@@ -247,7 +253,7 @@ func TestCertificateUtility_LoadCert(t *testing.T) {
 
 	t.Run("should fail decoding cert", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadCert([]byte("invalid data"))
@@ -260,7 +266,7 @@ func TestCertificateUtility_LoadCert(t *testing.T) {
 
 	t.Run("should fail parsing cert", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadCert(encodedInvalidCert)
@@ -276,7 +282,7 @@ func TestCertificateUtility_LoadKey(t *testing.T) {
 
 	t.Run("should load RSA key", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		key, err := certificateUtility.LoadKey(encodedRSAKey)
@@ -288,7 +294,7 @@ func TestCertificateUtility_LoadKey(t *testing.T) {
 
 	t.Run("should load key", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		key, err := certificateUtility.LoadKey(encodedKey)
@@ -298,9 +304,43 @@ func TestCertificateUtility_LoadKey(t *testing.T) {
 		assert.NotNil(t, key)
 	})
 
+	t.Run("should load ECDSA key", func(t *testing.T) {
+		// given
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
+
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		rawKey, err := x509.MarshalECPrivateKey(ecdsaKey)
+		require.NoError(t, err)
+
+		// when
+		key, apperr := certificateUtility.LoadKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}))
+
+		// then
+		require.NoError(t, apperr)
+		assert.Equal(t, ecdsaKey.Public(), key.Public())
+	})
+
+	t.Run("should load Ed25519 key", func(t *testing.T) {
+		// given
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
+
+		_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		rawKey, err := x509.MarshalPKCS8PrivateKey(ed25519Key)
+		require.NoError(t, err)
+
+		// when
+		key, apperr := certificateUtility.LoadKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rawKey}))
+
+		// then
+		require.NoError(t, apperr)
+		assert.Equal(t, ed25519Key.Public(), key.Public())
+	})
+
 	t.Run("should fail decoding key", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadKey([]byte("invalid data"))
@@ -313,7 +353,7 @@ func TestCertificateUtility_LoadKey(t *testing.T) {
 
 	t.Run("should fail parsing key", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadKey(encodedInvalidKey)
@@ -329,7 +369,7 @@ func TestCertificateUtility_LoadCSR(t *testing.T) {
 
 	t.Run("should load CSR", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		key, err := certificateUtility.LoadCSR([]byte(CSR))
@@ -341,7 +381,7 @@ func TestCertificateUtility_LoadCSR(t *testing.T) {
 
 	t.Run("should fail decoding CSR", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadCSR([]byte("aW52YWxpZCBkYXRh"))
@@ -354,7 +394,7 @@ func TestCertificateUtility_LoadCSR(t *testing.T) {
 
 	t.Run("should fail parsing CSR", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		crt, err := certificateUtility.LoadCSR([]byte(invalidCSR))
@@ -368,7 +408,11 @@ func TestCertificateUtility_LoadCSR(t *testing.T) {
 
 func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
 	csr := &x509.CertificateRequest{
+		PublicKey: key.Public(),
 		Subject: pkix.Name{
 			CommonName:         "cname",
 			Country:            []string{"country"},
@@ -390,7 +434,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "province",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -399,6 +443,34 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should fail when key algorithm is not allowed", func(t *testing.T) {
+		// given
+		csrSubject := CSRSubject{
+			CommonName:         "cname",
+			Country:            "country",
+			Organization:       "organization",
+			OrganizationalUnit: "organizationalUnit",
+			Locality:           "locality",
+			Province:           "province",
+		}
+
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		ed25519CSR := *csr
+		ed25519CSR.PublicKey = publicKey
+
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
+
+		// when
+		apperr := certificateUtility.CheckCSRValues(&ed25519CSR, csrSubject)
+
+		// then
+		require.Error(t, apperr)
+		assert.Equal(t, apperrors.CodeWrongInput, apperr.Code())
+		assert.Contains(t, apperr.Error(), "CSR: Ed25519 keys are not allowed.")
+	})
+
 	t.Run("should fail when subject country is nil", func(t *testing.T) {
 		// given
 		csrSubject := CSRSubject{
@@ -411,7 +483,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			},
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -433,7 +505,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "province",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -455,7 +527,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "province",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -478,7 +550,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "province",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -500,7 +572,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "province",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -522,7 +594,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "province",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -544,7 +616,7 @@ func TestCertificateUtility_CheckCSRValues(t *testing.T) {
 			Province:           "invalidProvince",
 		}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		err := certificateUtility.CheckCSRValues(csr, csrSubject)
@@ -560,7 +632,7 @@ func TestCertificateUtility_SignCSR(t *testing.T) {
 
 	t.Run("should sign client certificate", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 		caCrt, csr, key := prepareCrtAndKey(certificateUtility)
 
 		// when
@@ -577,13 +649,81 @@ func TestCertificateUtility_SignCSR(t *testing.T) {
 		assert.Equal(t, validityTime, certificateValidityTime)
 	})
 
+	t.Run("should sign client certificate with ECDSA key using RSA CA key", func(t *testing.T) {
+		// given
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
+		caCrt, _, caKey := prepareCrtAndKey(certificateUtility)
+
+		clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		csr := createCSR(t, clientKey)
+
+		// when
+		rawClientCRT, apperr := certificateUtility.SignCSR(caCrt, csr, caKey)
+
+		// then
+		require.NoError(t, apperr)
+
+		decodedCrt, err := x509.ParseCertificate(rawClientCRT)
+		require.NoError(t, err)
+		assert.Equal(t, x509.SHA256WithRSA, decodedCrt.SignatureAlgorithm)
+		assert.Equal(t, clientKey.Public(), decodedCrt.PublicKey)
+		assert.NoError(t, decodedCrt.CheckSignatureFrom(caCrt))
+	})
+
+	t.Run("should sign client certificate with Ed25519 key using ECDSA CA key", func(t *testing.T) {
+		// given
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
+
+		caKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		caCrt := createCACert(t, caKey)
+
+		_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		csr := createCSR(t, clientKey)
+
+		// when
+		rawClientCRT, apperr := certificateUtility.SignCSR(caCrt, csr, caKey)
+
+		// then
+		require.NoError(t, apperr)
+
+		decodedCrt, err := x509.ParseCertificate(rawClientCRT)
+		require.NoError(t, err)
+		assert.Equal(t, x509.ECDSAWithSHA384, decodedCrt.SignatureAlgorithm)
+		assert.Equal(t, clientKey.Public(), decodedCrt.PublicKey)
+		assert.NoError(t, decodedCrt.CheckSignatureFrom(caCrt))
+	})
+
+	t.Run("should sign client certificates with unique serial numbers", func(t *testing.T) {
+		// given
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
+		caCrt, csr, key := prepareCrtAndKey(certificateUtility)
+
+		// when
+		firstRawCRT, apperr := certificateUtility.SignCSR(caCrt, csr, key)
+		require.NoError(t, apperr)
+		secondRawCRT, apperr := certificateUtility.SignCSR(caCrt, csr, key)
+		require.NoError(t, apperr)
+
+		// then
+		firstCrt, err := x509.ParseCertificate(firstRawCRT)
+		require.NoError(t, err)
+		secondCrt, err := x509.ParseCertificate(secondRawCRT)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, firstCrt.SerialNumber.Sign())
+		assert.NotEqual(t, firstCrt.SerialNumber, secondCrt.SerialNumber)
+	})
+
 	t.Run("should return when failed to create certificate", func(t *testing.T) {
 		// given
 		caCrt := &x509.Certificate{}
 		csr := &x509.CertificateRequest{}
 		key := &rsa.PrivateKey{}
 
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 
 		// when
 		rawClientCRT, err := certificateUtility.SignCSR(caCrt, csr, key)
@@ -600,7 +740,7 @@ func TestCertificateUtility_AddCertificateHeaderAndFooter(t *testing.T) {
 
 	t.Run("should add certificate header and footer", func(t *testing.T) {
 		// given
-		certificateUtility := NewCertificateUtility(validityTime)
+		certificateUtility := NewCertificateUtility(validityTime, DefaultKeyPolicy())
 		certificate, apperr := certificateUtility.LoadCert([]byte(cert))
 		require.NoError(t, apperr)
 
@@ -630,9 +770,37 @@ func calculateValidityTime(certificate *x509.Certificate) time.Duration {
 	return difference
 }
 
-func prepareCrtAndKey(certificateUtility CertificateUtility) (*x509.Certificate, *x509.CertificateRequest, *rsa.PrivateKey) {
+func prepareCrtAndKey(certificateUtility CertificateUtility) (*x509.Certificate, *x509.CertificateRequest, crypto.Signer) {
 	caCrt, _ := certificateUtility.LoadCert(encodedCert)
 	csr, _ := certificateUtility.LoadCSR([]byte(CSR))
 	key, _ := certificateUtility.LoadKey(encodedKey)
 	return caCrt, csr, key
 }
+
+func createCACert(t *testing.T, key crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	rawCrt, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	crt, err := x509.ParseCertificate(rawCrt)
+	require.NoError(t, err)
+	return crt
+}
+
+func createCSR(t *testing.T, key crypto.Signer) *x509.CertificateRequest {
+	rawCSR, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "client"}}, key)
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(rawCSR)
+	require.NoError(t, err)
+	return csr
+}
@@ -1,7 +1,12 @@
 package certificates
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"net/http"
+	"net/url"
 	"regexp"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
@@ -28,6 +33,8 @@ type headerParser struct {
 type CertInfo struct {
 	Hash    string
 	Subject string
+	// Certificate is set only if the header contains the Cert element for the Hash
+	Certificate *x509.Certificate
 }
 
 func NewHeaderParser(country, province, locality, organization, unit string, central bool) HeaderParser {
@@ -67,7 +74,45 @@ func (hp *headerParser) ParseCertificateHeader(r http.Request) (CertInfo, apperr
 
 	certInfos := createCertInfos(subjects, hashes)
 
-	return hp.getCertInfoWithMatchingSubject(certInfos)
+	certInfo, appError := hp.getCertInfoWithMatchingSubject(certInfos)
+	if appError != nil {
+		return CertInfo{}, appError
+	}
+
+	certRegex := regexp.MustCompile(`Cert="(.*?)"`)
+
+	certInfo.Certificate = findCertificate(extractFromHeader(certHeader, certRegex), certInfo.Hash)
+
+	return certInfo, nil
+}
+
+// findCertificate returns the URL encoded PEM certificate which SHA-256 hash of the DER encoding is equal to the hash
+func findCertificate(encodedCerts []string, hash string) *x509.Certificate {
+	for _, encodedCert := range encodedCerts {
+		pemCert, err := url.PathUnescape(encodedCert)
+		if err != nil {
+			continue
+		}
+
+		block, _ := pem.Decode([]byte(pemCert))
+		if block == nil {
+			continue
+		}
+
+		sum := sha256.Sum256(block.Bytes)
+		if hex.EncodeToString(sum[:]) != hash {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		return certificate
+	}
+
+	return nil
 }
 
 func extractFromHeader(certHeader string, regex *regexp.Regexp) []string {
@@ -1,7 +1,14 @@
 package certificates
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -71,4 +78,52 @@ func TestParseCertHeader(t *testing.T) {
 
 		require.Error(t, e)
 	})
+
+	t.Run("Should return certificate from Cert element matching the hash", func(t *testing.T) {
+		//given
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		certificate := createCACert(t, key)
+
+		sum := sha256.Sum256(certificate.Raw)
+		hash := hex.EncodeToString(sum[:])
+		encodedCert := url.PathEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})))
+
+		r, _ := http.NewRequest("GET", "", nil)
+		r.Header.Set(ClientCertHeader, "By=spiffe://cluster.local/ns/kyma-integration/sa/default;Hash="+hash+";Cert=\""+encodedCert+"\";"+
+			"Subject=\"CN=test-application,OU=OrgUnit,O=organization,L=Waldorf,ST=Waldorf,C=DE\";URI=")
+
+		hp := NewHeaderParser("DE", "Waldorf", "Waldorf", "organization", "OrgUnit", false)
+
+		//when
+		certInfo, e := hp.ParseCertificateHeader(*r)
+
+		//then
+		require.NoError(t, e)
+		assert.Equal(t, hash, certInfo.Hash)
+		require.NotNil(t, certInfo.Certificate)
+		assert.Equal(t, certificate.SerialNumber, certInfo.Certificate.SerialNumber)
+	})
+
+	t.Run("Should not return certificate from Cert element not matching the hash", func(t *testing.T) {
+		//given
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		certificate := createCACert(t, key)
+
+		encodedCert := url.PathEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})))
+
+		r, _ := http.NewRequest("GET", "", nil)
+		r.Header.Set(ClientCertHeader, "Hash=f4cf22fb633d4df500e371daf703d4b4d14a0ea9d69cd631f95f9e6ba840f8ad;Cert=\""+encodedCert+"\";"+
+			"Subject=\"CN=test-application,OU=OrgUnit,O=organization,L=Waldorf,ST=Waldorf,C=DE\";URI=")
+
+		hp := NewHeaderParser("DE", "Waldorf", "Waldorf", "organization", "OrgUnit", false)
+
+		//when
+		certInfo, e := hp.ParseCertificateHeader(*r)
+
+		//then
+		require.NoError(t, e)
+		assert.Nil(t, certInfo.Certificate)
+	})
 }
@@ -0,0 +1,145 @@
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+)
+
+const (
+	KeyAlgorithmRSA     = "rsa"
+	KeyAlgorithmECDSA   = "ecdsa"
+	KeyAlgorithmEd25519 = "ed25519"
+
+	DefaultMinRSAKeySize   = 2048
+	DefaultMinECDSAKeySize = 256
+)
+
+var ecdsaCurves = []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()}
+
+// KeyPolicy defines the key algorithms and the minimal key sizes accepted in CSRs
+type KeyPolicy struct {
+	Algorithms      []string
+	MinRSAKeySize   int
+	MinECDSAKeySize int
+}
+
+// DefaultKeyPolicy accepts RSA keys of at least 2048 bits and ECDSA keys on the P-256, P-384 and P-521 curves
+func DefaultKeyPolicy() KeyPolicy {
+	return KeyPolicy{
+		Algorithms:      []string{KeyAlgorithmRSA, KeyAlgorithmECDSA},
+		MinRSAKeySize:   DefaultMinRSAKeySize,
+		MinECDSAKeySize: DefaultMinECDSAKeySize,
+	}
+}
+
+// ParseKeyPolicy creates the policy from the comma-separated list of the key algorithms
+func ParseKeyPolicy(algorithms string, minRSAKeySize, minECDSAKeySize int) (KeyPolicy, error) {
+	policy := KeyPolicy{
+		MinRSAKeySize:   minRSAKeySize,
+		MinECDSAKeySize: minECDSAKeySize,
+	}
+
+	for _, algorithm := range strings.Split(algorithms, ",") {
+		algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+		switch algorithm {
+		case "":
+			continue
+		case KeyAlgorithmRSA, KeyAlgorithmECDSA, KeyAlgorithmEd25519:
+			policy.Algorithms = append(policy.Algorithms, algorithm)
+		default:
+			return KeyPolicy{}, fmt.Errorf("unsupported key algorithm provided: %s", algorithm)
+		}
+	}
+
+	if len(policy.Algorithms) == 0 {
+		return KeyPolicy{}, fmt.Errorf("no key algorithm provided")
+	}
+	if policy.allows(KeyAlgorithmECDSA) && len(policy.ecdsaCurves()) == 0 {
+		return KeyPolicy{}, fmt.Errorf("no ECDSA curve with at least %d bits supported", minECDSAKeySize)
+	}
+
+	return policy, nil
+}
+
+// KeyAlgorithms returns the accepted key types in the order of the preference, for example rsa2048 or ecdsa-p256
+func (p KeyPolicy) KeyAlgorithms() []string {
+	var keyAlgorithms []string
+
+	for _, algorithm := range p.Algorithms {
+		switch algorithm {
+		case KeyAlgorithmRSA:
+			keyAlgorithms = append(keyAlgorithms, fmt.Sprintf("rsa%d", p.MinRSAKeySize))
+		case KeyAlgorithmECDSA:
+			for _, curve := range p.ecdsaCurves() {
+				keyAlgorithms = append(keyAlgorithms, "ecdsa-"+strings.ToLower(strings.ReplaceAll(curve.Params().Name, "-", "")))
+			}
+		case KeyAlgorithmEd25519:
+			keyAlgorithms = append(keyAlgorithms, KeyAlgorithmEd25519)
+		}
+	}
+
+	return keyAlgorithms
+}
+
+// CheckPublicKey verifies that the algorithm and the size of the key are accepted
+func (p KeyPolicy) CheckPublicKey(publicKey crypto.PublicKey) apperrors.AppError {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if !p.allows(KeyAlgorithmRSA) {
+			return apperrors.WrongInput("CSR: RSA keys are not allowed.")
+		}
+		if size := key.N.BitLen(); size < p.MinRSAKeySize {
+			return apperrors.WrongInput("CSR: RSA key size %d is smaller than the required %d.", size, p.MinRSAKeySize)
+		}
+	case *ecdsa.PublicKey:
+		if !p.allows(KeyAlgorithmECDSA) {
+			return apperrors.WrongInput("CSR: ECDSA keys are not allowed.")
+		}
+		if !p.allowsCurve(key.Curve) {
+			return apperrors.WrongInput("CSR: ECDSA curve %s is not allowed.", key.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+		if !p.allows(KeyAlgorithmEd25519) {
+			return apperrors.WrongInput("CSR: Ed25519 keys are not allowed.")
+		}
+	default:
+		return apperrors.WrongInput("CSR: Unsupported key algorithm.")
+	}
+
+	return nil
+}
+
+func (p KeyPolicy) allows(algorithm string) bool {
+	for _, allowed := range p.Algorithms {
+		if allowed == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+func (p KeyPolicy) allowsCurve(curve elliptic.Curve) bool {
+	for _, allowed := range p.ecdsaCurves() {
+		if allowed == curve {
+			return true
+		}
+	}
+	return false
+}
+
+func (p KeyPolicy) ecdsaCurves() []elliptic.Curve {
+	var curves []elliptic.Curve
+	for _, curve := range ecdsaCurves {
+		if curve.Params().BitSize >= p.MinECDSAKeySize {
+			curves = append(curves, curve)
+		}
+	}
+	return curves
+}
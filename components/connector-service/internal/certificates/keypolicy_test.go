@@ -0,0 +1,178 @@
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyPolicy(t *testing.T) {
+
+	t.Run("should parse key policy", func(t *testing.T) {
+		// when
+		policy, err := ParseKeyPolicy(" ECDSA, rsa,ed25519", 3072, 384)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, KeyPolicy{
+			Algorithms:      []string{KeyAlgorithmECDSA, KeyAlgorithmRSA, KeyAlgorithmEd25519},
+			MinRSAKeySize:   3072,
+			MinECDSAKeySize: 384,
+		}, policy)
+	})
+
+	t.Run("should fail on unsupported key algorithm", func(t *testing.T) {
+		// when
+		_, err := ParseKeyPolicy("rsa,dsa", 2048, 256)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, "unsupported key algorithm provided: dsa", err.Error())
+	})
+
+	t.Run("should fail when no key algorithm provided", func(t *testing.T) {
+		// when
+		_, err := ParseKeyPolicy(" , ", 2048, 256)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should fail when no ECDSA curve is big enough", func(t *testing.T) {
+		// when
+		_, err := ParseKeyPolicy("ecdsa", 2048, 1024)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestKeyPolicy_KeyAlgorithms(t *testing.T) {
+
+	t.Run("should return default key algorithms", func(t *testing.T) {
+		// when
+		keyAlgorithms := DefaultKeyPolicy().KeyAlgorithms()
+
+		// then
+		assert.Equal(t, []string{"rsa2048", "ecdsa-p256", "ecdsa-p384", "ecdsa-p521"}, keyAlgorithms)
+	})
+
+	t.Run("should return key algorithms in order of preference", func(t *testing.T) {
+		// given
+		policy := KeyPolicy{
+			Algorithms:      []string{KeyAlgorithmEd25519, KeyAlgorithmECDSA, KeyAlgorithmRSA},
+			MinRSAKeySize:   4096,
+			MinECDSAKeySize: 384,
+		}
+
+		// when
+		keyAlgorithms := policy.KeyAlgorithms()
+
+		// then
+		assert.Equal(t, []string{"ed25519", "ecdsa-p384", "ecdsa-p521", "rsa4096"}, keyAlgorithms)
+	})
+}
+
+func TestKeyPolicy_CheckPublicKey(t *testing.T) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	ed25519Key, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	allAlgorithms := []string{KeyAlgorithmRSA, KeyAlgorithmECDSA, KeyAlgorithmEd25519}
+
+	testCases := []struct {
+		name          string
+		policy        KeyPolicy
+		publicKey     crypto.PublicKey
+		expectedError string
+	}{
+		{
+			name:      "should accept RSA key",
+			policy:    KeyPolicy{Algorithms: allAlgorithms, MinRSAKeySize: 1024, MinECDSAKeySize: 256},
+			publicKey: rsaKey.Public(),
+		},
+		{
+			name:          "should reject too small RSA key",
+			policy:        KeyPolicy{Algorithms: allAlgorithms, MinRSAKeySize: 2048, MinECDSAKeySize: 256},
+			publicKey:     rsaKey.Public(),
+			expectedError: "CSR: RSA key size 1024 is smaller than the required 2048.",
+		},
+		{
+			name:          "should reject RSA key if not allowed",
+			policy:        KeyPolicy{Algorithms: []string{KeyAlgorithmECDSA}, MinRSAKeySize: 1024, MinECDSAKeySize: 256},
+			publicKey:     rsaKey.Public(),
+			expectedError: "CSR: RSA keys are not allowed.",
+		},
+		{
+			name:      "should accept ECDSA key",
+			policy:    KeyPolicy{Algorithms: allAlgorithms, MinRSAKeySize: 2048, MinECDSAKeySize: 256},
+			publicKey: p256Key.Public(),
+		},
+		{
+			name:          "should reject ECDSA key on too small curve",
+			policy:        KeyPolicy{Algorithms: allAlgorithms, MinRSAKeySize: 2048, MinECDSAKeySize: 384},
+			publicKey:     p256Key.Public(),
+			expectedError: "CSR: ECDSA curve P-256 is not allowed.",
+		},
+		{
+			name:      "should accept ECDSA key on big enough curve",
+			policy:    KeyPolicy{Algorithms: allAlgorithms, MinRSAKeySize: 2048, MinECDSAKeySize: 384},
+			publicKey: p384Key.Public(),
+		},
+		{
+			name:          "should reject ECDSA key if not allowed",
+			policy:        KeyPolicy{Algorithms: []string{KeyAlgorithmRSA}, MinRSAKeySize: 2048, MinECDSAKeySize: 256},
+			publicKey:     p256Key.Public(),
+			expectedError: "CSR: ECDSA keys are not allowed.",
+		},
+		{
+			name:      "should accept Ed25519 key",
+			policy:    KeyPolicy{Algorithms: allAlgorithms, MinRSAKeySize: 2048, MinECDSAKeySize: 256},
+			publicKey: ed25519Key,
+		},
+		{
+			name:          "should reject Ed25519 key if not allowed",
+			policy:        DefaultKeyPolicy(),
+			publicKey:     ed25519Key,
+			expectedError: "CSR: Ed25519 keys are not allowed.",
+		},
+		{
+			name:          "should reject missing key",
+			policy:        DefaultKeyPolicy(),
+			expectedError: "CSR: Unsupported key algorithm.",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// when
+			err := testCase.policy.CheckPublicKey(testCase.publicKey)
+
+			// then
+			if testCase.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, apperrors.CodeWrongInput, err.Code())
+			assert.Equal(t, testCase.expectedError, err.Error())
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
+	"github.com/kyma-project/kyma/components/connector-service/internal/secrets"
+	"golang.org/x/crypto/ocsp"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RevocationStatusValidity is the time after which the clients should fetch the CRL or the OCSP response again
+const RevocationStatusValidity = time.Hour
+
+type RevocationStatusService interface {
+	// CRL returns the DER encoded certificate revocation list signed with the CA stored in secret
+	CRL() ([]byte, apperrors.AppError)
+	// PreviousCRL returns the DER encoded certificate revocation list signed with the previous CA during the CA rotation,
+	// so that the certificates it issued can be checked against the CRL of their issuer
+	PreviousCRL() ([]byte, apperrors.AppError)
+	// OCSPResponse takes DER encoded OCSP request and returns DER encoded OCSP response signed with the CA stored in secret,
+	// the status is unknown if the certificate might have been revoked only by the hash
+	OCSPResponse(rawRequest []byte) ([]byte, apperrors.AppError)
+}
+
+type issuer struct {
+	certificate *x509.Certificate
+	key         crypto.Signer
+}
+
+type revocationStatusService struct {
+	ctx               context.Context
+	secretsRepository secrets.Repository
+	certUtil          CertificateUtility
+	caSecretName      types.NamespacedName
+	revocationList    revocation.RevocationListRepository
+	inventory         inventory.Repository
+}
+
+func NewRevocationStatusService(secretRepository secrets.Repository, certUtil CertificateUtility, caSecretName types.NamespacedName,
+	revocationList revocation.RevocationListRepository, certificateInventory inventory.Repository) RevocationStatusService {
+	return &revocationStatusService{
+		ctx:               context.Background(),
+		secretsRepository: secretRepository,
+		certUtil:          certUtil,
+		caSecretName:      caSecretName,
+		revocationList:    revocationList,
+		inventory:         certificateInventory,
+	}
+}
+
+func (svc *revocationStatusService) CRL() ([]byte, apperrors.AppError) {
+	issuers, appErr := svc.loadIssuers()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return svc.createCRL(issuers[0])
+}
+
+func (svc *revocationStatusService) PreviousCRL() ([]byte, apperrors.AppError) {
+	issuers, appErr := svc.loadIssuers()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if len(issuers) < 2 {
+		return nil, apperrors.NotFound("There is no previous CA, the CA is not being rotated.")
+	}
+
+	return svc.createCRL(issuers[1])
+}
+
+// createCRL lists all revoked certificates regardless of the issuer, as the serial numbers are unique across the CAs
+func (svc *revocationStatusService) createCRL(issuer issuer) ([]byte, apperrors.AppError) {
+	entries, err := svc.revocationList.List(svc.ctx)
+	if err != nil {
+		return nil, apperrors.Internal("Failed to read revoked certificates: %s.", err)
+	}
+
+	var revokedCerts []pkix.RevokedCertificate
+	for _, entry := range entries {
+		// certificates revoked only by the hash can't be published
+		if entry.SerialNumber == nil {
+			continue
+		}
+
+		revokedCerts = append(revokedCerts, pkix.RevokedCertificate{
+			SerialNumber:   entry.SerialNumber,
+			RevocationTime: entry.RevokedAt,
+		})
+	}
+
+	now := time.Now()
+	template := &x509.RevocationList{
+		RevokedCertificates: revokedCerts,
+		Number:              big.NewInt(now.Unix()),
+		ThisUpdate:          now,
+		NextUpdate:          now.Add(RevocationStatusValidity),
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, template, issuer.certificate, issuer.key)
+	if err != nil {
+		return nil, apperrors.Internal("Failed to create CRL: %s.", err)
+	}
+
+	return crl, nil
+}
+
+func (svc *revocationStatusService) OCSPResponse(rawRequest []byte) ([]byte, apperrors.AppError) {
+	request, err := ocsp.ParseRequest(rawRequest)
+	if err != nil {
+		return ocsp.MalformedRequestErrorResponse, nil
+	}
+
+	issuers, appErr := svc.loadIssuers()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var caCrt *x509.Certificate
+	var caKey crypto.Signer
+	for _, issuer := range issuers {
+		if isIssuedBy(request, issuer.certificate) {
+			caCrt, caKey = issuer.certificate, issuer.key
+			break
+		}
+	}
+
+	if caCrt == nil {
+		return ocsp.UnauthorizedErrorResponse, nil
+	}
+
+	entries, err := svc.revocationList.List(svc.ctx)
+	if err != nil {
+		return nil, apperrors.Internal("Failed to read revoked certificates: %s.", err)
+	}
+
+	now := time.Now()
+	template := ocsp.Response{
+		SerialNumber: request.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(RevocationStatusValidity),
+	}
+
+	revokedEntry, status, appErr := svc.revocationStatus(request.SerialNumber, entries)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	template.Status = status
+	if status == ocsp.Revoked {
+		template.RevokedAt = revokedEntry.RevokedAt
+		template.RevocationReason = ocsp.Unspecified
+	}
+
+	response, err := ocsp.CreateResponse(caCrt, caCrt, template, caKey)
+	if err != nil {
+		return nil, apperrors.Internal("Failed to create OCSP response: %s.", err)
+	}
+
+	return response, nil
+}
+
+// revocationStatus finds the entry of the certificate with the serial number. The entries of the certificates revoked only
+// by the hash are matched with the fingerprint recorded in the inventory, the status of the certificate which might match
+// such entry but isn't recorded, including all certificates with the legacy serial number, is unknown.
+func (svc *revocationStatusService) revocationStatus(serialNumber *big.Int, entries []revocation.Entry) (revocation.Entry, int, apperrors.AppError) {
+	hashOnlyEntries := map[string]revocation.Entry{}
+	for _, entry := range entries {
+		if entry.SerialNumber == nil {
+			hashOnlyEntries[entry.Hash] = entry
+			continue
+		}
+
+		if entry.SerialNumber.Cmp(serialNumber) == 0 {
+			return entry, ocsp.Revoked, nil
+		}
+	}
+
+	if len(hashOnlyEntries) == 0 {
+		return revocation.Entry{}, ocsp.Good, nil
+	}
+	if serialNumber.Cmp(LegacySerialNumber) == 0 {
+		return revocation.Entry{}, ocsp.Unknown, nil
+	}
+
+	record, found, err := svc.inventory.Get(svc.ctx, serialNumber)
+	if err != nil {
+		return revocation.Entry{}, 0, apperrors.Internal("Failed to read certificate from inventory: %s.", err)
+	}
+	if !found {
+		return revocation.Entry{}, ocsp.Unknown, nil
+	}
+
+	if entry, revoked := hashOnlyEntries[record.Fingerprint]; revoked {
+		return entry, ocsp.Revoked, nil
+	}
+
+	return revocation.Entry{}, ocsp.Good, nil
+}
+
+// loadIssuers returns the active CA and, during the CA rotation, the previous CA which issued certificates that are still valid
+func (svc *revocationStatusService) loadIssuers() ([]issuer, apperrors.AppError) {
+	secretData, err := svc.secretsRepository.Get(svc.ctx, svc.caSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := svc.loadIssuer(secretData[caCertificateSecretKey], secretData[caKeySecretKey])
+	if err != nil {
+		return nil, err
+	}
+
+	issuers := []issuer{active}
+
+	if len(secretData[previousCACertificateSecretKey]) != 0 {
+		previous, err := svc.loadIssuer(secretData[previousCACertificateSecretKey], secretData[previousCAKeySecretKey])
+		if err != nil {
+			return nil, err
+		}
+
+		issuers = append(issuers, previous)
+	}
+
+	return issuers, nil
+}
+
+func (svc *revocationStatusService) loadIssuer(encodedCrt, encodedKey []byte) (issuer, apperrors.AppError) {
+	caCrt, err := svc.certUtil.LoadCert(encodedCrt)
+	if err != nil {
+		return issuer{}, err
+	}
+
+	caKey, err := svc.certUtil.LoadKey(encodedKey)
+	if err != nil {
+		return issuer{}, err
+	}
+
+	return issuer{certificate: caCrt, key: caKey}, nil
+}
+
+// isIssuedBy compares the hashes of the issuer name and key from the OCSP request with the CA certificate
+func isIssuedBy(request *ocsp.Request, caCrt *x509.Certificate) bool {
+	if !request.HashAlgorithm.Available() {
+		return false
+	}
+
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(caCrt.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return false
+	}
+
+	nameHash := request.HashAlgorithm.New()
+	nameHash.Write(caCrt.RawSubject)
+
+	keyHash := request.HashAlgorithm.New()
+	keyHash.Write(publicKeyInfo.PublicKey.RightAlign())
+
+	return bytes.Equal(nameHash.Sum(nil), request.IssuerNameHash) && bytes.Equal(keyHash.Sum(nil), request.IssuerKeyHash)
+}
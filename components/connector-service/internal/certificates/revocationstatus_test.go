@@ -0,0 +1,357 @@
+package certificates_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	inventoryMocks "github.com/kyma-project/kyma/components/connector-service/internal/inventory/mocks"
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
+	revocationMocks "github.com/kyma-project/kyma/components/connector-service/internal/revocation/mocks"
+	secretsMock "github.com/kyma-project/kyma/components/connector-service/internal/secrets/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestRevocationStatusService_CRL(t *testing.T) {
+
+	t.Run("should return CRL with revoked certificates signed with CA", func(t *testing.T) {
+		// given
+		caCert, caSecretData := prepareCA(t)
+		revokedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+		secretsRepository := &secretsMock.Repository{}
+		secretsRepository.On("Get", testContext, authNamespacedName).Return(caSecretData, nil)
+
+		revocationList := &revocationMocks.RevocationListRepository{}
+		revocationList.On("List", testContext).Return([]revocation.Entry{
+			{Hash: "revoked", SerialNumber: big.NewInt(42), RevokedAt: revokedAt, ExpiresAt: time.Now().Add(time.Hour)},
+			{Hash: "revokedByHash", RevokedAt: revokedAt, ExpiresAt: time.Now().Add(time.Hour)},
+		}, nil)
+
+		service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, &inventoryMocks.Repository{})
+
+		// when
+		rawCRL, apperr := service.CRL()
+
+		// then
+		require.NoError(t, apperr)
+
+		crl, err := x509.ParseCRL(rawCRL)
+		require.NoError(t, err)
+		require.NoError(t, caCert.CheckCRLSignature(crl))
+
+		revokedCerts := crl.TBSCertList.RevokedCertificates
+		require.Len(t, revokedCerts, 1)
+		assert.Equal(t, big.NewInt(42), revokedCerts[0].SerialNumber)
+		assert.True(t, revokedAt.Equal(revokedCerts[0].RevocationTime))
+		assert.True(t, crl.TBSCertList.NextUpdate.After(time.Now()))
+	})
+
+	t.Run("should return error when failed to read revoked certificates", func(t *testing.T) {
+		// given
+		_, caSecretData := prepareCA(t)
+
+		secretsRepository := &secretsMock.Repository{}
+		secretsRepository.On("Get", testContext, authNamespacedName).Return(caSecretData, nil)
+
+		revocationList := &revocationMocks.RevocationListRepository{}
+		revocationList.On("List", testContext).Return(nil, errors.New("some error"))
+
+		service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, &inventoryMocks.Repository{})
+
+		// when
+		rawCRL, apperr := service.CRL()
+
+		// then
+		require.Error(t, apperr)
+		assert.Equal(t, apperrors.CodeInternal, apperr.Code())
+		assert.Nil(t, rawCRL)
+	})
+
+	t.Run("should return error when failed to read CA secret", func(t *testing.T) {
+		// given
+		secretsRepository := &secretsMock.Repository{}
+		secretsRepository.On("Get", testContext, authNamespacedName).Return(nil, apperrors.NotFound("secret not found"))
+
+		revocationList := &revocationMocks.RevocationListRepository{}
+
+		service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, &inventoryMocks.Repository{})
+
+		// when
+		_, apperr := service.CRL()
+
+		// then
+		require.Error(t, apperr)
+		assert.Equal(t, apperrors.CodeNotFound, apperr.Code())
+	})
+}
+
+func TestRevocationStatusService_PreviousCRL(t *testing.T) {
+
+	t.Run("should return CRL signed with previous CA during CA rotation", func(t *testing.T) {
+		// given
+		_, caSecretData := prepareCA(t)
+		previousCACert, previousCASecretData := prepareCA(t)
+
+		secretsRepository := &secretsMock.Repository{}
+		secretsRepository.On("Get", testContext, authNamespacedName).Return(map[string][]byte{
+			"ca.crt":          caSecretData["ca.crt"],
+			"ca.key":          caSecretData["ca.key"],
+			"previous-ca.crt": previousCASecretData["ca.crt"],
+			"previous-ca.key": previousCASecretData["ca.key"],
+		}, nil)
+
+		revocationList := &revocationMocks.RevocationListRepository{}
+		revocationList.On("List", testContext).Return([]revocation.Entry{
+			{Hash: "revoked", SerialNumber: big.NewInt(42), RevokedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
+		}, nil)
+
+		service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, &inventoryMocks.Repository{})
+
+		// when
+		rawCRL, apperr := service.PreviousCRL()
+
+		// then
+		require.NoError(t, apperr)
+
+		crl, err := x509.ParseCRL(rawCRL)
+		require.NoError(t, err)
+		require.NoError(t, previousCACert.CheckCRLSignature(crl))
+
+		revokedCerts := crl.TBSCertList.RevokedCertificates
+		require.Len(t, revokedCerts, 1)
+		assert.Equal(t, big.NewInt(42), revokedCerts[0].SerialNumber)
+	})
+
+	t.Run("should return not found error when CA is not rotated", func(t *testing.T) {
+		// given
+		_, caSecretData := prepareCA(t)
+
+		secretsRepository := &secretsMock.Repository{}
+		secretsRepository.On("Get", testContext, authNamespacedName).Return(caSecretData, nil)
+
+		service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, &revocationMocks.RevocationListRepository{}, &inventoryMocks.Repository{})
+
+		// when
+		rawCRL, apperr := service.PreviousCRL()
+
+		// then
+		require.Error(t, apperr)
+		assert.Equal(t, apperrors.CodeNotFound, apperr.Code())
+		assert.Nil(t, rawCRL)
+	})
+}
+
+func TestRevocationStatusService_OCSPResponse(t *testing.T) {
+
+	caCert, caSecretData := prepareCA(t)
+	revokedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	secretsRepository := &secretsMock.Repository{}
+	secretsRepository.On("Get", testContext, authNamespacedName).Return(caSecretData, nil)
+
+	revocationList := &revocationMocks.RevocationListRepository{}
+	revocationList.On("List", testContext).Return([]revocation.Entry{
+		{Hash: "revoked", SerialNumber: big.NewInt(42), RevokedAt: revokedAt, ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+
+	service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, &inventoryMocks.Repository{})
+
+	t.Run("should return revoked status", func(t *testing.T) {
+		// given
+		request := createOCSPRequest(t, caCert, big.NewInt(42))
+
+		// when
+		rawResponse, apperr := service.OCSPResponse(request)
+
+		// then
+		require.NoError(t, apperr)
+
+		response, err := ocsp.ParseResponse(rawResponse, caCert)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Revoked, response.Status)
+		assert.Equal(t, big.NewInt(42), response.SerialNumber)
+		assert.True(t, revokedAt.Equal(response.RevokedAt))
+	})
+
+	t.Run("should return good status", func(t *testing.T) {
+		// given
+		request := createOCSPRequest(t, caCert, big.NewInt(43))
+
+		// when
+		rawResponse, apperr := service.OCSPResponse(request)
+
+		// then
+		require.NoError(t, apperr)
+
+		response, err := ocsp.ParseResponse(rawResponse, caCert)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Good, response.Status)
+		assert.Equal(t, big.NewInt(43), response.SerialNumber)
+	})
+
+	t.Run("should return unauthorized response for certificate of other issuer", func(t *testing.T) {
+		// given
+		otherCACert, _ := prepareCA(t)
+		request := createOCSPRequest(t, otherCACert, big.NewInt(42))
+
+		// when
+		rawResponse, apperr := service.OCSPResponse(request)
+
+		// then
+		require.NoError(t, apperr)
+		assert.Equal(t, ocsp.UnauthorizedErrorResponse, rawResponse)
+	})
+
+	t.Run("should return status signed with previous CA during CA rotation", func(t *testing.T) {
+		// given
+		previousCACert, previousCASecretData := prepareCA(t)
+
+		rotationSecretData := map[string][]byte{
+			"ca.crt":          caSecretData["ca.crt"],
+			"ca.key":          caSecretData["ca.key"],
+			"previous-ca.crt": previousCASecretData["ca.crt"],
+			"previous-ca.key": previousCASecretData["ca.key"],
+		}
+
+		rotationSecretsRepository := &secretsMock.Repository{}
+		rotationSecretsRepository.On("Get", testContext, authNamespacedName).Return(rotationSecretData, nil)
+
+		rotationService := certificates.NewRevocationStatusService(rotationSecretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, &inventoryMocks.Repository{})
+
+		request := createOCSPRequest(t, previousCACert, big.NewInt(42))
+
+		// when
+		rawResponse, apperr := rotationService.OCSPResponse(request)
+
+		// then
+		require.NoError(t, apperr)
+
+		response, err := ocsp.ParseResponse(rawResponse, previousCACert)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Revoked, response.Status)
+	})
+
+	t.Run("should return malformed request response", func(t *testing.T) {
+		// when
+		rawResponse, apperr := service.OCSPResponse([]byte("not a request"))
+
+		// then
+		require.NoError(t, apperr)
+		assert.Equal(t, ocsp.MalformedRequestErrorResponse, rawResponse)
+	})
+}
+
+func TestRevocationStatusService_OCSPResponse_RevokedByHash(t *testing.T) {
+
+	caCert, caSecretData := prepareCA(t)
+	revokedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	secretsRepository := &secretsMock.Repository{}
+	secretsRepository.On("Get", testContext, authNamespacedName).Return(caSecretData, nil)
+
+	revocationList := &revocationMocks.RevocationListRepository{}
+	revocationList.On("List", testContext).Return([]revocation.Entry{
+		{Hash: "revokedByHash", RevokedAt: revokedAt, ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+
+	certificateInventory := &inventoryMocks.Repository{}
+	certificateInventory.On("Get", testContext, big.NewInt(42)).Return(inventory.Record{SerialNumber: big.NewInt(42), Fingerprint: "revokedByHash"}, true, nil)
+	certificateInventory.On("Get", testContext, big.NewInt(43)).Return(inventory.Record{SerialNumber: big.NewInt(43), Fingerprint: "other"}, true, nil)
+	certificateInventory.On("Get", testContext, big.NewInt(44)).Return(inventory.Record{}, false, nil)
+	certificateInventory.On("Get", testContext, big.NewInt(45)).Return(inventory.Record{}, false, errors.New("some error"))
+
+	service := certificates.NewRevocationStatusService(secretsRepository, certificates.NewCertificateUtility(time.Hour, certificates.DefaultKeyPolicy()), authNamespacedName, revocationList, certificateInventory)
+
+	testCases := []struct {
+		description    string
+		serialNumber   *big.Int
+		expectedStatus int
+	}{
+		{description: "should return revoked status of certificate recorded with revoked hash", serialNumber: big.NewInt(42), expectedStatus: ocsp.Revoked},
+		{description: "should return good status of certificate recorded with other hash", serialNumber: big.NewInt(43), expectedStatus: ocsp.Good},
+		{description: "should return unknown status of certificate not recorded", serialNumber: big.NewInt(44), expectedStatus: ocsp.Unknown},
+		{description: "should return unknown status of certificate with legacy serial number", serialNumber: certificates.LegacySerialNumber, expectedStatus: ocsp.Unknown},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			request := createOCSPRequest(t, caCert, testCase.serialNumber)
+
+			// when
+			rawResponse, apperr := service.OCSPResponse(request)
+
+			// then
+			require.NoError(t, apperr)
+
+			response, err := ocsp.ParseResponse(rawResponse, caCert)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, response.Status)
+			if testCase.expectedStatus == ocsp.Revoked {
+				assert.True(t, revokedAt.Equal(response.RevokedAt))
+			}
+		})
+	}
+
+	t.Run("should return error when failed to read inventory", func(t *testing.T) {
+		// given
+		request := createOCSPRequest(t, caCert, big.NewInt(45))
+
+		// when
+		rawResponse, apperr := service.OCSPResponse(request)
+
+		// then
+		require.Error(t, apperr)
+		assert.Equal(t, apperrors.CodeInternal, apperr.Code())
+		assert.Nil(t, rawResponse)
+	})
+}
+
+func prepareCA(t *testing.T) (*x509.Certificate, map[string][]byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	rawCrt, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	crt, err := x509.ParseCertificate(rawCrt)
+	require.NoError(t, err)
+
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return crt, map[string][]byte{
+		"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rawCrt}),
+		"ca.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}),
+	}
+}
+
+func createOCSPRequest(t *testing.T, issuer *x509.Certificate, serialNumber *big.Int) []byte {
+	request, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: serialNumber}, issuer, &ocsp.RequestOptions{Hash: crypto.SHA256})
+	require.NoError(t, err)
+	return request
+}
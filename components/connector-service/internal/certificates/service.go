@@ -15,6 +15,9 @@ const (
 	caCertificateSecretKey     = "ca.crt"
 	caKeySecretKey             = "ca.key"
 	rootCACertificateSecretKey = "cacert"
+
+	previousCACertificateSecretKey = "previous-ca.crt"
+	previousCAKeySecretKey         = "previous-ca.key"
 )
 
 type Service interface {
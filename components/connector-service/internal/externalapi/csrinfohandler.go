@@ -22,15 +22,17 @@ type csrInfoHandler struct {
 	getInfoURL               string
 	baseURL                  string
 	csrSubject               certificates.CSRSubject
+	keyAlgorithms            []string
 }
 
-func NewCSRInfoHandler(tokenManager tokens.Creator, connectorClientExtractor clientcontext.ConnectorClientExtractor, getInfoURL string, baseURL string) CSRInfoHandler {
+func NewCSRInfoHandler(tokenManager tokens.Creator, connectorClientExtractor clientcontext.ConnectorClientExtractor, getInfoURL string, baseURL string, keyAlgorithms []string) CSRInfoHandler {
 
 	return &csrInfoHandler{
 		tokenManager:             tokenManager,
 		connectorClientExtractor: connectorClientExtractor,
 		getInfoURL:               getInfoURL,
 		baseURL:                  baseURL,
+		keyAlgorithms:            keyAlgorithms,
 	}
 }
 
@@ -51,7 +53,7 @@ func (ih *csrInfoHandler) GetCSRInfo(w http.ResponseWriter, r *http.Request) {
 
 	csrURL := ih.makeCSRURLs(newToken)
 
-	certInfo := makeCertInfo(clientContextService.GetSubject().ToString())
+	certInfo := makeCertInfo(clientContextService.GetSubject().ToString(), ih.keyAlgorithms)
 
 	httphelpers.RespondWithBody(w, http.StatusOK, csrInfoResponse{CsrURL: csrURL, API: apiURLs, CertificateInfo: certInfo})
 }
@@ -71,10 +73,18 @@ func (ih *csrInfoHandler) makeApiURLs(clientContextService clientcontext.ClientC
 	}
 }
 
-func makeCertInfo(subject string) certInfo {
+// makeCertInfo advertises the accepted key types, the preferred one is also returned as the key algorithm for the clients
+// which support only one
+func makeCertInfo(subject string, keyAlgorithms []string) certInfo {
+	keyAlgorithm := ""
+	if len(keyAlgorithms) > 0 {
+		keyAlgorithm = keyAlgorithms[0]
+	}
+
 	return certInfo{
-		Subject:      subject,
-		Extensions:   "",
-		KeyAlgorithm: "rsa2048",
+		Subject:       subject,
+		Extensions:    "",
+		KeyAlgorithm:  keyAlgorithm,
+		KeyAlgorithms: keyAlgorithms,
 	}
 }
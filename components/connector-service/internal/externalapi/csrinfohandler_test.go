@@ -34,6 +34,8 @@ var (
 	}
 
 	strSubject = "O=Org,OU=OrgUnit,L=Gliwice,ST=Province,C=PL,CN=CommonName"
+
+	keyAlgorithms = []string{"rsa2048", "ecdsa-p256", "ecdsa-p384", "ecdsa-p521"}
 )
 
 type dummyClientContextService struct{}
@@ -89,9 +91,10 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 	expectedSignUrl := fmt.Sprintf("%s/certificates?token=%s", baseURL, newToken)
 
 	expectedCertInfo := certInfo{
-		Subject:      strSubject,
-		Extensions:   "",
-		KeyAlgorithm: "rsa2048",
+		Subject:       strSubject,
+		Extensions:    "",
+		KeyAlgorithm:  "rsa2048",
+		KeyAlgorithms: keyAlgorithms,
 	}
 
 	t.Run("should successfully get csr info", func(t *testing.T) {
@@ -104,7 +107,7 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 		tokenCreator := &tokenMocks.Creator{}
 		tokenCreator.On("Save", dummyClientContextService).Return(newToken, nil)
 
-		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL)
+		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL, keyAlgorithms)
 
 		req, err := http.NewRequest(http.MethodPost, urlApps, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -146,7 +149,7 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 		tokenCreator := &tokenMocks.Creator{}
 		tokenCreator.On("Save", dummyClientContextServiceWithEmptyURLs).Return(newToken, nil)
 
-		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL)
+		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL, keyAlgorithms)
 
 		req, err := http.NewRequest(http.MethodPost, urlApps, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -180,7 +183,7 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 		tokenCreator := &tokenMocks.Creator{}
 		tokenCreator.On("Save", dummyClientContextService).Return(newToken, nil)
 
-		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, predefinedGetInfoURL, baseURL)
+		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, predefinedGetInfoURL, baseURL, keyAlgorithms)
 
 		req, err := http.NewRequest(http.MethodPost, urlApps, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -209,7 +212,7 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 			return nil, apperrors.Internal("error")
 		}
 
-		infoHandler := NewCSRInfoHandler(tokenCreator, errorExtractor, infoURL, baseURL)
+		infoHandler := NewCSRInfoHandler(tokenCreator, errorExtractor, infoURL, baseURL, keyAlgorithms)
 
 		req, err := http.NewRequest(http.MethodPost, urlApps, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -235,7 +238,7 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 		tokenCreator := &tokenMocks.Creator{}
 		tokenCreator.On("Save", dummyClientContextService).Return("", apperrors.Internal("error"))
 
-		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL)
+		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL, keyAlgorithms)
 
 		req, err := http.NewRequest(http.MethodPost, urlApps, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -279,7 +282,7 @@ func TestCSRInfoHandler_GetCSRInfo(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, urlApps, nil)
 		require.NoError(t, err)
 
-		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL)
+		infoHandler := NewCSRInfoHandler(tokenCreator, clientContextService, infoURL, baseURL, keyAlgorithms)
 
 		rr := httptest.NewRecorder()
 
@@ -1,12 +1,15 @@
 package externalapi
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 
 	loggingMiddlewares "github.com/kyma-project/kyma/components/connector-service/internal/logging/middlewares"
 	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
 
@@ -26,6 +29,8 @@ type Config struct {
 	CertService                 certificates.Service
 	RevokedCertsRepo            revocation.RevocationListRepository
 	HeaderParser                certificates.HeaderParser
+	KeyAlgorithms               []string
+	CertificateInventory        inventory.Repository
 }
 
 type FunctionalMiddlewares struct {
@@ -73,11 +78,11 @@ func NewHandlerBuilder(funcMiddlwares FunctionalMiddlewares, globalMiddlewares [
 }
 
 func (hb *handlerBuilder) WithApps(appHandlerCfg Config) {
-	applicationInfoHandler := NewCSRInfoHandler(appHandlerCfg.TokenCreator, appHandlerCfg.ContextExtractor, appHandlerCfg.ManagementInfoURL, appHandlerCfg.ConnectorServiceBaseURL)
-	applicationRenewalHandler := NewSignatureHandler(appHandlerCfg.CertService, appHandlerCfg.ContextExtractor)
-	applicationSignatureHandler := NewSignatureHandler(appHandlerCfg.CertService, appHandlerCfg.ContextExtractor)
-	applicationManagementInfoHandler := NewManagementInfoHandler(appHandlerCfg.ContextExtractor, appHandlerCfg.CertificateProtectedBaseURL)
-	applicationRevocationHandler := NewRevocationHandler(appHandlerCfg.RevokedCertsRepo, appHandlerCfg.HeaderParser)
+	applicationInfoHandler := NewCSRInfoHandler(appHandlerCfg.TokenCreator, appHandlerCfg.ContextExtractor, appHandlerCfg.ManagementInfoURL, appHandlerCfg.ConnectorServiceBaseURL, appHandlerCfg.KeyAlgorithms)
+	applicationRenewalHandler := NewSignatureHandler(appHandlerCfg.CertService, appHandlerCfg.ContextExtractor, appHandlerCfg.CertificateInventory, certificateRequestor(appHandlerCfg.HeaderParser))
+	applicationSignatureHandler := NewSignatureHandler(appHandlerCfg.CertService, appHandlerCfg.ContextExtractor, appHandlerCfg.CertificateInventory, tokenRequestor)
+	applicationManagementInfoHandler := NewManagementInfoHandler(appHandlerCfg.ContextExtractor, appHandlerCfg.CertificateProtectedBaseURL, appHandlerCfg.KeyAlgorithms)
+	applicationRevocationHandler := NewRevocationHandler(appHandlerCfg.RevokedCertsRepo, appHandlerCfg.HeaderParser, appHandlerCfg.CertificateInventory)
 
 	csrApplicationRouter := hb.router.PathPrefix("/v1/applications/signingRequests").Subrouter()
 	csrApplicationRouter.HandleFunc("/info", applicationInfoHandler.GetCSRInfo).Methods(http.MethodGet)
@@ -120,11 +125,11 @@ func (hb *handlerBuilder) WithApps(appHandlerCfg Config) {
 }
 
 func (hb *handlerBuilder) WithRuntimes(runtimeHandlerCfg Config) {
-	runtimeInfoHandler := NewCSRInfoHandler(runtimeHandlerCfg.TokenCreator, runtimeHandlerCfg.ContextExtractor, runtimeHandlerCfg.ManagementInfoURL, runtimeHandlerCfg.ConnectorServiceBaseURL)
-	runtimeRenewalHandler := NewSignatureHandler(runtimeHandlerCfg.CertService, runtimeHandlerCfg.ContextExtractor)
-	runtimeSignatureHandler := NewSignatureHandler(runtimeHandlerCfg.CertService, runtimeHandlerCfg.ContextExtractor)
-	runtimeManagementInfoHandler := NewManagementInfoHandler(runtimeHandlerCfg.ContextExtractor, runtimeHandlerCfg.CertificateProtectedBaseURL)
-	runtimeRevocationHandler := NewRevocationHandler(runtimeHandlerCfg.RevokedCertsRepo, runtimeHandlerCfg.HeaderParser)
+	runtimeInfoHandler := NewCSRInfoHandler(runtimeHandlerCfg.TokenCreator, runtimeHandlerCfg.ContextExtractor, runtimeHandlerCfg.ManagementInfoURL, runtimeHandlerCfg.ConnectorServiceBaseURL, runtimeHandlerCfg.KeyAlgorithms)
+	runtimeRenewalHandler := NewSignatureHandler(runtimeHandlerCfg.CertService, runtimeHandlerCfg.ContextExtractor, runtimeHandlerCfg.CertificateInventory, certificateRequestor(runtimeHandlerCfg.HeaderParser))
+	runtimeSignatureHandler := NewSignatureHandler(runtimeHandlerCfg.CertService, runtimeHandlerCfg.ContextExtractor, runtimeHandlerCfg.CertificateInventory, tokenRequestor)
+	runtimeManagementInfoHandler := NewManagementInfoHandler(runtimeHandlerCfg.ContextExtractor, runtimeHandlerCfg.CertificateProtectedBaseURL, runtimeHandlerCfg.KeyAlgorithms)
+	runtimeRevocationHandler := NewRevocationHandler(runtimeHandlerCfg.RevokedCertsRepo, runtimeHandlerCfg.HeaderParser, runtimeHandlerCfg.CertificateInventory)
 
 	csrRuntimesRouter := hb.router.PathPrefix("/v1/runtimes/signingRequests").Subrouter()
 	csrRuntimesRouter.HandleFunc("/info", runtimeInfoHandler.GetCSRInfo).Methods(http.MethodGet)
@@ -165,6 +170,36 @@ func (hb *handlerBuilder) WithRuntimes(runtimeHandlerCfg Config) {
 
 }
 
+// WithRevocationStatus publishes the CRL and the OCSP responder for all certificates signed by the service
+func (hb *handlerBuilder) WithRevocationStatus(revocationStatusService certificates.RevocationStatusService) {
+	revocationStatusHandler := NewRevocationStatusHandler(revocationStatusService)
+
+	revocationStatusRouter := hb.router.PathPrefix("/v1/certificates").Subrouter()
+	revocationStatusRouter.HandleFunc("/crl", revocationStatusHandler.GetCRL).Methods(http.MethodGet)
+	revocationStatusRouter.HandleFunc("/crl/previous", revocationStatusHandler.GetPreviousCRL).Methods(http.MethodGet)
+	revocationStatusRouter.HandleFunc("/ocsp", revocationStatusHandler.PostOCSPResponse).Methods(http.MethodPost)
+	revocationStatusRouter.HandleFunc("/ocsp/{request:.+}", revocationStatusHandler.GetOCSPResponse).Methods(http.MethodGet)
+}
+
+// tokenRequestor identifies the clients which requested the certificate by the SHA-256 hash of the one-time token, the
+// same hash names the Secret of the token
+func tokenRequestor(r *http.Request) string {
+	hash := sha256.Sum256([]byte(r.URL.Query().Get("token")))
+	return "token:" + hex.EncodeToString(hash[:])
+}
+
+// certificateRequestor identifies the clients which renewed the certificate by the hash of the client certificate
+func certificateRequestor(headerParser certificates.HeaderParser) RequestorResolver {
+	return func(r *http.Request) string {
+		certInfo, err := headerParser.ParseCertificateHeader(*r)
+		if err != nil {
+			return "certificate"
+		}
+
+		return inventory.RenewalRequestor(certInfo.Hash)
+	}
+}
+
 func (hb *handlerBuilder) createRenewalAuditLogMiddleware(contextExtractor clientcontext.ConnectorClientExtractor) mux.MiddlewareFunc {
 	return loggingMiddlewares.NewAuditLoggingMiddleware(contextExtractor, loggingMiddlewares.AuditLogMessages{
 		StartingOperationMsg:   "Starting certificate renewal.",
@@ -16,12 +16,14 @@ const (
 type managementInfoHandler struct {
 	connectorClientExtractor    clientcontext.ConnectorClientExtractor
 	certificateProtectedBaseURL string
+	keyAlgorithms               []string
 }
 
-func NewManagementInfoHandler(connectorClientExtractor clientcontext.ConnectorClientExtractor, certProtectedBaseURL string) *managementInfoHandler {
+func NewManagementInfoHandler(connectorClientExtractor clientcontext.ConnectorClientExtractor, certProtectedBaseURL string, keyAlgorithms []string) *managementInfoHandler {
 	return &managementInfoHandler{
 		connectorClientExtractor:    connectorClientExtractor,
 		certificateProtectedBaseURL: certProtectedBaseURL,
+		keyAlgorithms:               keyAlgorithms,
 	}
 }
 
@@ -34,7 +36,7 @@ func (ih *managementInfoHandler) GetManagementInfo(w http.ResponseWriter, r *htt
 
 	urls := ih.buildURLs(clientContextService)
 
-	certInfo := makeCertInfo(clientContextService.GetSubject().ToString())
+	certInfo := makeCertInfo(clientContextService.GetSubject().ToString(), ih.keyAlgorithms)
 
 	httphelpers.RespondWithBody(w, http.StatusOK, mgmtInfoReponse{URLs: urls, ClientIdentity: clientContextService.ClientContext(), CertificateInfo: certInfo})
 }
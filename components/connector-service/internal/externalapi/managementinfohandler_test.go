@@ -61,7 +61,7 @@ func TestManagementInfoHandler_GetManagementInfo(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/v1/applications/management/info", nil)
 		require.NoError(t, err)
 
-		infoHandler := NewManagementInfoHandler(connectorClientExtractor, protectedBaseURL)
+		infoHandler := NewManagementInfoHandler(connectorClientExtractor, protectedBaseURL, keyAlgorithms)
 
 		rr := httptest.NewRecorder()
 
@@ -91,6 +91,7 @@ func TestManagementInfoHandler_GetManagementInfo(t *testing.T) {
 		assert.Equal(t, strSubject, certificateInfo.Subject)
 		assert.Equal(t, expectedExtensions, certificateInfo.Extensions)
 		assert.Equal(t, expectedKeyAlgorithm, certificateInfo.KeyAlgorithm)
+		assert.Equal(t, keyAlgorithms, certificateInfo.KeyAlgorithms)
 	})
 
 	t.Run("should successfully get management info response for runtime", func(t *testing.T) {
@@ -107,7 +108,7 @@ func TestManagementInfoHandler_GetManagementInfo(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/v1/runtimes/management/info", nil)
 		require.NoError(t, err)
 
-		infoHandler := NewManagementInfoHandler(connectorClientExtractor, protectedBaseURL)
+		infoHandler := NewManagementInfoHandler(connectorClientExtractor, protectedBaseURL, keyAlgorithms)
 
 		rr := httptest.NewRecorder()
 
@@ -134,6 +135,7 @@ func TestManagementInfoHandler_GetManagementInfo(t *testing.T) {
 		assert.Equal(t, strSubject, certificateInfo.Subject)
 		assert.Equal(t, expectedExtensions, certificateInfo.Extensions)
 		assert.Equal(t, expectedKeyAlgorithm, certificateInfo.KeyAlgorithm)
+		assert.Equal(t, keyAlgorithms, certificateInfo.KeyAlgorithms)
 	})
 
 	t.Run("should return 500 when failed to extract context", func(t *testing.T) {
@@ -145,7 +147,7 @@ func TestManagementInfoHandler_GetManagementInfo(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/v1/applications/management/info", nil)
 		require.NoError(t, err)
 
-		infoHandler := NewManagementInfoHandler(clientContextService, protectedBaseURL)
+		infoHandler := NewManagementInfoHandler(clientContextService, protectedBaseURL, keyAlgorithms)
 
 		rr := httptest.NewRecorder()
 
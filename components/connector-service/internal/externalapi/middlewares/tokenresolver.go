@@ -51,6 +51,8 @@ func (cc *tokenResolverMiddleware) Middleware(handler http.Handler) http.Handler
 
 		if writerWithStatus.IsSuccessful() {
 			cc.tokenManager.Delete(token)
+		} else {
+			cc.tokenManager.Release(token)
 		}
 	})
 }
@@ -63,6 +63,34 @@ func TestTokenResolver_Middleware(t *testing.T) {
 		tokenManager.AssertExpectations(t)
 	})
 
+	t.Run("should release token when request failed", func(t *testing.T) {
+		// given
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		tokenManager := &mocks.Manager{}
+		tokenManager.On("Resolve", token, dummyExtenderObject).
+			Return(nil)
+		tokenManager.On("Release", token).Return(nil)
+
+		req, err := http.NewRequest("GET", "/?token="+token, nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		middleware := NewTokenResolverMiddleware(tokenManager, dummyExtender)
+
+		// when
+		resultHandler := middleware.Middleware(handler)
+		resultHandler.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		tokenManager.AssertExpectations(t)
+		tokenManager.AssertNotCalled(t, "Delete", token)
+	})
+
 	t.Run("should return 403 when there is no token sent", func(t *testing.T) {
 		// given
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -40,9 +40,10 @@ type api struct {
 }
 
 type certInfo struct {
-	Subject      string `json:"subject"`
-	Extensions   string `json:"extensions"`
-	KeyAlgorithm string `json:"key-algorithm"`
+	Subject       string   `json:"subject"`
+	Extensions    string   `json:"extensions"`
+	KeyAlgorithm  string   `json:"key-algorithm"`
+	KeyAlgorithms []string `json:"key-algorithms"`
 }
 
 func toCertResponse(encodedChain certificates.EncodedCertificateChain) certResponse {
@@ -7,32 +7,36 @@ import (
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
 	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
 	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
 	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
+	log "github.com/sirupsen/logrus"
 )
 
 type revocationHandler struct {
-	ctx            context.Context
-	revocationList revocation.RevocationListRepository
-	headerParser   certificates.HeaderParser
+	ctx                  context.Context
+	revocationList       revocation.RevocationListRepository
+	headerParser         certificates.HeaderParser
+	certificateInventory inventory.Repository
 }
 
-func NewRevocationHandler(revocationList revocation.RevocationListRepository, headerParser certificates.HeaderParser) *revocationHandler {
+func NewRevocationHandler(revocationList revocation.RevocationListRepository, headerParser certificates.HeaderParser, certificateInventory inventory.Repository) *revocationHandler {
 	return &revocationHandler{
-		ctx:            context.Background(),
-		revocationList: revocationList,
-		headerParser:   headerParser,
+		ctx:                  context.Background(),
+		revocationList:       revocationList,
+		headerParser:         headerParser,
+		certificateInventory: certificateInventory,
 	}
 }
 
 func (handler revocationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
 
-	hash, appError := handler.getCertificateHash(r)
+	entry, appError := handler.getRevocationEntry(r)
 	if appError != nil {
 		httphelpers.RespondWithErrorAndLog(w, appError)
 		return
 	}
 
-	appError = handler.addToRevocationList(hash)
+	appError = handler.addToRevocationList(entry)
 	if appError != nil {
 		httphelpers.RespondWithErrorAndLog(w, appError)
 		return
@@ -41,17 +45,46 @@ func (handler revocationHandler) Revoke(w http.ResponseWriter, r *http.Request)
 	httphelpers.Respond(w, http.StatusCreated)
 }
 
-func (handler revocationHandler) getCertificateHash(r *http.Request) (string, apperrors.AppError) {
+func (handler revocationHandler) getRevocationEntry(r *http.Request) (revocation.Entry, apperrors.AppError) {
 	certInfo, appError := handler.headerParser.ParseCertificateHeader(*r)
 	if appError != nil {
-		return "", appError
+		return revocation.Entry{}, appError
 	}
 
-	return certInfo.Hash, nil
+	entry := revocation.Entry{Hash: certInfo.Hash}
+	if certInfo.Certificate == nil {
+		return handler.resolveFromInventory(entry), nil
+	}
+
+	entry.ExpiresAt = certInfo.Certificate.NotAfter
+
+	// the legacy serial number is shared by many certificates so they can be revoked only by the hash
+	if certInfo.Certificate.SerialNumber.Cmp(certificates.LegacySerialNumber) != 0 {
+		entry.SerialNumber = certInfo.Certificate.SerialNumber
+	}
+
+	return entry, nil
+}
+
+// resolveFromInventory looks up the serial number of the certificate known only by the hash, so that it can be published
+// in the CRL. The certificate which isn't recorded in the inventory is revoked only by the hash.
+func (handler revocationHandler) resolveFromInventory(entry revocation.Entry) revocation.Entry {
+	record, found, err := handler.certificateInventory.GetByFingerprint(handler.ctx, entry.Hash)
+	if err != nil {
+		log.Errorf("Failed to read certificate with hash %s from inventory, revoking it only by the hash: %s", entry.Hash, err)
+		return entry
+	}
+	if !found {
+		return entry
+	}
+
+	entry.SerialNumber = record.SerialNumber
+	entry.ExpiresAt = record.ExpiresAt
+	return entry
 }
 
-func (handler revocationHandler) addToRevocationList(hash string) apperrors.AppError {
-	err := handler.revocationList.Insert(handler.ctx, hash)
+func (handler revocationHandler) addToRevocationList(entry revocation.Entry) apperrors.AppError {
+	err := handler.revocationList.Insert(handler.ctx, entry)
 	if err != nil {
 		return apperrors.Internal("Unable to mark certificate as revoked: %s.", err)
 	}
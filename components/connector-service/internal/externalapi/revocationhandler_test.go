@@ -2,15 +2,21 @@ package externalapi
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
 	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
 	certmocks "github.com/kyma-project/kyma/components/connector-service/internal/certificates/mocks"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	inventoryMocks "github.com/kyma-project/kyma/components/connector-service/internal/inventory/mocks"
 
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 	"github.com/kyma-project/kyma/components/connector-service/internal/revocation/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,12 +34,91 @@ func TestRevocationHandler(t *testing.T) {
 
 	headerParser := &certmocks.HeaderParser{}
 
+	certificateInventory := &inventoryMocks.Repository{}
+	certificateInventory.On("GetByFingerprint", testContext, hash).Return(inventory.Record{}, false, nil)
+
 	t.Run("should revoke certificate and return http code 201", func(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
-		revocationListRepository.On("Insert", testContext, hash).Return(nil)
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash}).Return(nil)
+
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
+
+		rr := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, nil)
+		req.Header.Set(certificates.ClientCertHeader, testCertHeader)
+		headerParser.On("ParseCertificateHeader", *req).Return(certInfo, nil)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		revocationListRepository.AssertExpectations(t)
+	})
+
+	t.Run("should revoke certificate with serial number and expiry from header", func(t *testing.T) {
+		//given
+		certificate := &x509.Certificate{SerialNumber: big.NewInt(42), NotAfter: time.Now().Add(time.Hour)}
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash, SerialNumber: certificate.SerialNumber, ExpiresAt: certificate.NotAfter}).Return(nil)
+
+		headerParser := &certmocks.HeaderParser{}
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
+
+		rr := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, nil)
+		req.Header.Set(certificates.ClientCertHeader, testCertHeader)
+		headerParser.On("ParseCertificateHeader", *req).Return(certificates.CertInfo{Hash: hash, Certificate: certificate}, nil)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		revocationListRepository.AssertExpectations(t)
+	})
+
+	t.Run("should revoke certificate with serial number and expiry from inventory", func(t *testing.T) {
+		//given
+		record := inventory.Record{SerialNumber: big.NewInt(42), Fingerprint: hash, ExpiresAt: time.Now().Add(time.Hour)}
+
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("GetByFingerprint", testContext, hash).Return(record, true, nil)
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash, SerialNumber: record.SerialNumber, ExpiresAt: record.ExpiresAt}).Return(nil)
+
+		headerParser := &certmocks.HeaderParser{}
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
+
+		rr := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, nil)
+		req.Header.Set(certificates.ClientCertHeader, testCertHeader)
+		headerParser.On("ParseCertificateHeader", *req).Return(certInfo, nil)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		revocationListRepository.AssertExpectations(t)
+	})
+
+	t.Run("should revoke certificate by hash when failed to read inventory", func(t *testing.T) {
+		//given
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("GetByFingerprint", testContext, hash).Return(inventory.Record{}, false, errors.New("error"))
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash}).Return(nil)
 
-		handler := NewRevocationHandler(revocationListRepository, headerParser)
+		headerParser := &certmocks.HeaderParser{}
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -49,12 +134,36 @@ func TestRevocationHandler(t *testing.T) {
 		revocationListRepository.AssertExpectations(t)
 	})
 
+	t.Run("should revoke certificate with legacy serial number only by hash", func(t *testing.T) {
+		//given
+		certificate := &x509.Certificate{SerialNumber: big.NewInt(2), NotAfter: time.Now().Add(time.Hour)}
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash, ExpiresAt: certificate.NotAfter}).Return(nil)
+
+		headerParser := &certmocks.HeaderParser{}
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
+
+		rr := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, nil)
+		req.Header.Set(certificates.ClientCertHeader, testCertHeader)
+		headerParser.On("ParseCertificateHeader", *req).Return(certificates.CertInfo{Hash: hash, Certificate: certificate}, nil)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		revocationListRepository.AssertExpectations(t)
+	})
+
 	t.Run("should return http code 201 when certificate already revoked", func(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
-		revocationListRepository.On("Insert", testContext, hash).Return(nil)
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash}).Return(nil)
 
-		handler := NewRevocationHandler(revocationListRepository, headerParser)
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -83,7 +192,7 @@ func TestRevocationHandler(t *testing.T) {
 
 		revocationListRepository := &mocks.RevocationListRepository{}
 
-		handler := NewRevocationHandler(revocationListRepository, headerParser)
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -97,15 +206,15 @@ func TestRevocationHandler(t *testing.T) {
 
 		//then
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
-		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"))
+		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("revocation.Entry"))
 	})
 
 	t.Run("should return http code 500 when certificate revocation not persisted", func(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
-		revocationListRepository.On("Insert", testContext, hash).Return(errors.New("Error"))
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hash}).Return(errors.New("Error"))
 
-		handler := NewRevocationHandler(revocationListRepository, headerParser)
+		handler := NewRevocationHandler(revocationListRepository, headerParser, certificateInventory)
 
 		rr := httptest.NewRecorder()
 
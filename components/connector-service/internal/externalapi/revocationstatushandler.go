@@ -0,0 +1,94 @@
+package externalapi
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
+	"github.com/kyma-project/kyma/components/connector-service/internal/httpconsts"
+	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
+)
+
+// maxOCSPRequestSize limits the body of the OCSP request, which contains only the hashes and the serial number
+const maxOCSPRequestSize = 10 * 1024
+
+type revocationStatusHandler struct {
+	revocationStatusService certificates.RevocationStatusService
+}
+
+func NewRevocationStatusHandler(revocationStatusService certificates.RevocationStatusService) *revocationStatusHandler {
+	return &revocationStatusHandler{
+		revocationStatusService: revocationStatusService,
+	}
+}
+
+func (handler revocationStatusHandler) GetCRL(w http.ResponseWriter, r *http.Request) {
+	crl, appError := handler.revocationStatusService.CRL()
+	if appError != nil {
+		httphelpers.RespondWithErrorAndLog(w, appError)
+		return
+	}
+
+	respondWithDER(w, httpconsts.ContentTypeApplicationPkixCRL, crl)
+}
+
+// GetPreviousCRL returns the CRL of the previous CA during the CA rotation
+func (handler revocationStatusHandler) GetPreviousCRL(w http.ResponseWriter, r *http.Request) {
+	crl, appError := handler.revocationStatusService.PreviousCRL()
+	if appError != nil {
+		httphelpers.RespondWithErrorAndLog(w, appError)
+		return
+	}
+
+	respondWithDER(w, httpconsts.ContentTypeApplicationPkixCRL, crl)
+}
+
+// GetOCSPResponse handles the base64 encoded OCSP request sent in the URL path as described in RFC 6960 Appendix A.1
+func (handler revocationStatusHandler) GetOCSPResponse(w http.ResponseWriter, r *http.Request) {
+	encodedRequest, err := url.PathUnescape(mux.Vars(r)["request"])
+	if err != nil {
+		httphelpers.RespondWithErrorAndLog(w, apperrors.BadRequest("Invalid OCSP request encoding: %s.", err))
+		return
+	}
+
+	rawRequest, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedRequest))
+	if err != nil {
+		httphelpers.RespondWithErrorAndLog(w, apperrors.BadRequest("Invalid OCSP request encoding: %s.", err))
+		return
+	}
+
+	handler.respondWithOCSPResponse(w, rawRequest)
+}
+
+func (handler revocationStatusHandler) PostOCSPResponse(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	rawRequest, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxOCSPRequestSize))
+	if err != nil {
+		httphelpers.RespondWithErrorAndLog(w, apperrors.BadRequest("Error while reading request body: %s.", err))
+		return
+	}
+
+	handler.respondWithOCSPResponse(w, rawRequest)
+}
+
+func (handler revocationStatusHandler) respondWithOCSPResponse(w http.ResponseWriter, rawRequest []byte) {
+	response, appError := handler.revocationStatusService.OCSPResponse(rawRequest)
+	if appError != nil {
+		httphelpers.RespondWithErrorAndLog(w, appError)
+		return
+	}
+
+	respondWithDER(w, httpconsts.ContentTypeApplicationOCSPResponse, response)
+}
+
+func respondWithDER(w http.ResponseWriter, contentType string, body []byte) {
+	w.Header().Set(httpconsts.HeaderContentType, contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,165 @@
+package externalapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/kyma-project/kyma/components/connector-service/internal/certificates/mocks"
+	"github.com/kyma-project/kyma/components/connector-service/internal/httpconsts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationStatusHandler_GetCRL(t *testing.T) {
+
+	urlCRL := "/v1/certificates/crl"
+
+	t.Run("should return CRL", func(t *testing.T) {
+		// given
+		crl := []byte("crl")
+		revocationStatusService := &mocks.RevocationStatusService{}
+		revocationStatusService.On("CRL").Return(crl, nil)
+
+		handler := NewRevocationStatusHandler(revocationStatusService)
+
+		req := httptest.NewRequest(http.MethodGet, urlCRL, nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.GetCRL(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, httpconsts.ContentTypeApplicationPkixCRL, rr.Header().Get(httpconsts.HeaderContentType))
+		assert.Equal(t, crl, rr.Body.Bytes())
+	})
+
+	t.Run("should return http code 500 when failed to create CRL", func(t *testing.T) {
+		// given
+		revocationStatusService := &mocks.RevocationStatusService{}
+		revocationStatusService.On("CRL").Return(nil, apperrors.Internal("error"))
+
+		handler := NewRevocationStatusHandler(revocationStatusService)
+
+		req := httptest.NewRequest(http.MethodGet, urlCRL, nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.GetCRL(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestRevocationStatusHandler_GetPreviousCRL(t *testing.T) {
+
+	urlPreviousCRL := "/v1/certificates/crl/previous"
+
+	t.Run("should return CRL of previous CA", func(t *testing.T) {
+		// given
+		crl := []byte("crl")
+		revocationStatusService := &mocks.RevocationStatusService{}
+		revocationStatusService.On("PreviousCRL").Return(crl, nil)
+
+		handler := NewRevocationStatusHandler(revocationStatusService)
+
+		req := httptest.NewRequest(http.MethodGet, urlPreviousCRL, nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.GetPreviousCRL(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, httpconsts.ContentTypeApplicationPkixCRL, rr.Header().Get(httpconsts.HeaderContentType))
+		assert.Equal(t, crl, rr.Body.Bytes())
+	})
+
+	t.Run("should return http code 404 when CA is not rotated", func(t *testing.T) {
+		// given
+		revocationStatusService := &mocks.RevocationStatusService{}
+		revocationStatusService.On("PreviousCRL").Return(nil, apperrors.NotFound("no previous CA"))
+
+		handler := NewRevocationStatusHandler(revocationStatusService)
+
+		req := httptest.NewRequest(http.MethodGet, urlPreviousCRL, nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.GetPreviousCRL(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestRevocationStatusHandler_OCSPResponse(t *testing.T) {
+
+	urlOCSP := "/v1/certificates/ocsp"
+	ocspRequest := []byte{0x30, 0xfb, 0xff}
+	ocspResponse := []byte("response")
+
+	t.Run("should return OCSP response for request sent in body", func(t *testing.T) {
+		// given
+		revocationStatusService := &mocks.RevocationStatusService{}
+		revocationStatusService.On("OCSPResponse", ocspRequest).Return(ocspResponse, nil)
+
+		handler := NewRevocationStatusHandler(revocationStatusService)
+
+		req := httptest.NewRequest(http.MethodPost, urlOCSP, bytes.NewReader(ocspRequest))
+		req.Header.Set(httpconsts.HeaderContentType, httpconsts.ContentTypeApplicationOCSPRequest)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.PostOCSPResponse(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, httpconsts.ContentTypeApplicationOCSPResponse, rr.Header().Get(httpconsts.HeaderContentType))
+		assert.Equal(t, ocspResponse, rr.Body.Bytes())
+	})
+
+	t.Run("should return OCSP response for request sent in path", func(t *testing.T) {
+		// given
+		revocationStatusService := &mocks.RevocationStatusService{}
+		revocationStatusService.On("OCSPResponse", ocspRequest).Return(ocspResponse, nil)
+
+		router := mux.NewRouter()
+		router.HandleFunc(urlOCSP+"/{request:.+}", NewRevocationStatusHandler(revocationStatusService).GetOCSPResponse)
+
+		encodedRequest := url.PathEscape(base64.StdEncoding.EncodeToString(ocspRequest))
+		req := httptest.NewRequest(http.MethodGet, urlOCSP+"/"+encodedRequest, nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, ocspResponse, rr.Body.Bytes())
+	})
+
+	t.Run("should return http code 400 when request in path is not base64 encoded", func(t *testing.T) {
+		// given
+		revocationStatusService := &mocks.RevocationStatusService{}
+
+		router := mux.NewRouter()
+		router.HandleFunc(urlOCSP+"/{request:.+}", NewRevocationStatusHandler(revocationStatusService).GetOCSPResponse)
+
+		req := httptest.NewRequest(http.MethodGet, urlOCSP+"/not-base64", nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		revocationStatusService.AssertNotCalled(t, "OCSPResponse")
+	})
+}
@@ -1,12 +1,19 @@
 package externalapi
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/clientcontext"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
 
@@ -14,15 +21,23 @@ import (
 	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
 )
 
+// RequestorResolver identifies the client requesting the certificate, the identity is recorded in the certificate inventory
+type RequestorResolver func(r *http.Request) string
+
 type signatureHandler struct {
 	connectorClientExtractor clientcontext.ConnectorClientExtractor
 	certificateService       certificates.Service
+	certificateInventory     inventory.Repository
+	requestorResolver        RequestorResolver
 }
 
-func NewSignatureHandler(certificateService certificates.Service, connectorClientExtractor clientcontext.ConnectorClientExtractor) SignatureHandler {
+func NewSignatureHandler(certificateService certificates.Service, connectorClientExtractor clientcontext.ConnectorClientExtractor,
+	certificateInventory inventory.Repository, requestorResolver RequestorResolver) SignatureHandler {
 	return &signatureHandler{
 		connectorClientExtractor: connectorClientExtractor,
 		certificateService:       certificateService,
+		certificateInventory:     certificateInventory,
+		requestorResolver:        requestorResolver,
 	}
 }
 
@@ -51,9 +66,64 @@ func (sh *signatureHandler) SignCSR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the certificate is already issued, so the failure to record it does not fail the request
+	recordErr := sh.recordCertificate(r, clientContextService.ClientContext(), encodedCertificatesChain.ClientCertificate)
+	if recordErr != nil {
+		clientContextService.ClientContext().GetLogger().Errorf("Failed to record issued certificate in the inventory: %s", recordErr)
+	}
+
 	httphelpers.RespondWithBody(w, http.StatusCreated, toCertResponse(encodedCertificatesChain))
 }
 
+func (sh *signatureHandler) recordCertificate(r *http.Request, clientContext clientcontext.ClientContextService, encodedCertificate string) error {
+	certificate, err := parseEncodedCertificate(encodedCertificate)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(certificate.Raw)
+	application, clusterContext := ownerOf(clientContext)
+
+	return sh.certificateInventory.Insert(r.Context(), inventory.Record{
+		SerialNumber: certificate.SerialNumber,
+		Fingerprint:  hex.EncodeToString(hash[:]),
+		Subject:      certificate.Subject.String(),
+		Application:  application,
+		Group:        clusterContext.Group,
+		Tenant:       clusterContext.Tenant,
+		IssuedAt:     time.Now().UTC(),
+		ExpiresAt:    certificate.NotAfter.UTC(),
+		RequestedBy:  sh.requestorResolver(r),
+	})
+}
+
+func parseEncodedCertificate(encodedCertificate string) (*x509.Certificate, error) {
+	pemCertificate, err := base64.StdEncoding.DecodeString(encodedCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(pemCertificate)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block of the certificate")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func ownerOf(clientContext clientcontext.ClientContextService) (string, clientcontext.ClusterContext) {
+	switch ctx := clientContext.(type) {
+	case clientcontext.ExtendedApplicationContext:
+		return ctx.Application, ctx.ClusterContext
+	case clientcontext.ApplicationContext:
+		return ctx.Application, ctx.ClusterContext
+	case clientcontext.ClusterContext:
+		return "", ctx
+	default:
+		return "", clientcontext.ClusterContext{}
+	}
+}
+
 func readCertRequest(r *http.Request) (*certRequest, apperrors.AppError) {
 	b, err := ioutil.ReadAll(r.Body)
 	if err != nil {
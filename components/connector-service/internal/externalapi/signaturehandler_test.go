@@ -3,13 +3,29 @@ package externalapi
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	inventoryMocks "github.com/kyma-project/kyma/components/connector-service/internal/inventory/mocks"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/certificates"
 
@@ -41,7 +57,7 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 		// given
 		certChainBase64 := "certChainBase64"
 		caCertificate := "caCertificate"
-		clientCertificate := "clientCertificate"
+		clientCertificate, certificate := createEncodedCertificate(t)
 
 		encodedChain := certificates.EncodedCertificateChain{
 			CertificateChain:  certChainBase64,
@@ -52,12 +68,29 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 		certService := &certMock.Service{}
 		certService.On("SignCSR", decodedCSR, subject).Return(encodedChain, nil)
 
-		dummyClientContext := dummyClientContextService{}
+		appContext := clientcontext.ApplicationContext{
+			Application:    appName,
+			ClusterContext: clientcontext.ClusterContext{Group: "group", Tenant: "tenant"},
+		}
 		connectorClientExtractor := func(ctx context.Context) (clientcontext.ClientCertContextService, apperrors.AppError) {
-			return dummyClientCertCtx{dummyClientContext}, nil
+			return dummyClientCertCtx{appContext}, nil
 		}
 
-		signatureHandler := NewSignatureHandler(certService, connectorClientExtractor)
+		hash := sha256.Sum256(certificate.Raw)
+		tokenHash := sha256.Sum256([]byte(token))
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("Insert", mock.Anything, mock.MatchedBy(func(record inventory.Record) bool {
+			return record.SerialNumber.Cmp(certificate.SerialNumber) == 0 &&
+				record.Fingerprint == hex.EncodeToString(hash[:]) &&
+				record.Subject == certificate.Subject.String() &&
+				record.Application == appName &&
+				record.Group == "group" &&
+				record.Tenant == "tenant" &&
+				record.ExpiresAt.Equal(certificate.NotAfter) &&
+				record.RequestedBy == "token:"+hex.EncodeToString(tokenHash[:])
+		})).Return(nil)
+
+		signatureHandler := NewSignatureHandler(certService, connectorClientExtractor, certificateInventory, tokenRequestor)
 
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -76,6 +109,36 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 
 		assert.Equal(t, certChainBase64, certResponse.CRTChain)
 		assert.Equal(t, http.StatusCreated, rr.Code)
+		certificateInventory.AssertExpectations(t)
+	})
+
+	t.Run("should sign client certificate when failed to record it in inventory", func(t *testing.T) {
+		// given
+		clientCertificate, _ := createEncodedCertificate(t)
+
+		certService := &certMock.Service{}
+		certService.On("SignCSR", decodedCSR, subject).Return(certificates.EncodedCertificateChain{ClientCertificate: clientCertificate}, nil)
+
+		dummyClientContext := dummyClientContextService{}
+		connectorClientExtractor := func(ctx context.Context) (clientcontext.ClientCertContextService, apperrors.AppError) {
+			return dummyClientCertCtx{dummyClientContext}, nil
+		}
+
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("Insert", mock.Anything, mock.AnythingOfType("inventory.Record")).Return(errors.New("error"))
+
+		signatureHandler := NewSignatureHandler(certService, connectorClientExtractor, certificateInventory, tokenRequestor)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tokenRequestRaw))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		// when
+		signatureHandler.SignCSR(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		certificateInventory.AssertExpectations(t)
 	})
 
 	t.Run("should return 500 when failed to extract client context", func(t *testing.T) {
@@ -84,7 +147,7 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 			return nil, apperrors.Internal("error")
 		}
 
-		signatureHandler := NewSignatureHandler(nil, errorExtractor)
+		signatureHandler := NewSignatureHandler(nil, errorExtractor, nil, tokenRequestor)
 
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -107,7 +170,7 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 			return dummyClientCertCtx{dummyClientContext}, nil
 		}
 
-		signatureHandler := NewSignatureHandler(nil, connectorClientExtractor)
+		signatureHandler := NewSignatureHandler(nil, connectorClientExtractor, nil, tokenRequestor)
 
 		incorrectBody := []byte("incorrectBody")
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(incorrectBody))
@@ -131,7 +194,7 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 			return dummyClientCertCtx{dummyClientContext}, nil
 		}
 
-		signatureHandler := NewSignatureHandler(nil, connectorClientExtractor)
+		signatureHandler := NewSignatureHandler(nil, connectorClientExtractor, nil, tokenRequestor)
 
 		incorrectBase64Body := compact([]byte("{\"csr\":\"not base 64\"}"))
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(incorrectBase64Body))
@@ -158,7 +221,7 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 			return dummyClientCertCtx{dummyClientContext}, nil
 		}
 
-		signatureHandler := NewSignatureHandler(certService, connectorClientExtractor)
+		signatureHandler := NewSignatureHandler(certService, connectorClientExtractor, nil, tokenRequestor)
 
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tokenRequestRaw))
 		require.NoError(t, err)
@@ -175,6 +238,28 @@ func TestSignatureHandler_SignCSR(t *testing.T) {
 	})
 }
 
+func createEncodedCertificate(t *testing.T) (string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0xabc),
+		Subject:      pkix.Name{CommonName: appName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+
+	rawCrt, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(rawCrt)
+	require.NoError(t, err)
+
+	pemCrt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rawCrt})
+
+	return base64.StdEncoding.EncodeToString(pemCrt), certificate
+}
+
 func readErrorResponse(t *testing.T, body io.Reader) httperrors.ErrorResponse {
 	responseBody, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
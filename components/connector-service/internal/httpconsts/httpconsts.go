@@ -5,5 +5,8 @@ const (
 )
 
 const (
-	ContentTypeApplicationJson = "application/json;charset=UTF-8"
+	ContentTypeApplicationJson         = "application/json;charset=UTF-8"
+	ContentTypeApplicationPkixCRL      = "application/pkix-crl"
+	ContentTypeApplicationOCSPRequest  = "application/ocsp-request"
+	ContentTypeApplicationOCSPResponse = "application/ocsp-response"
 )
@@ -0,0 +1,118 @@
+package internalapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
+)
+
+type certificateRecord struct {
+	SerialNumber string    `json:"serialNumber"`
+	Fingerprint  string    `json:"fingerprint"`
+	Subject      string    `json:"subject"`
+	Application  string    `json:"application,omitempty"`
+	Group        string    `json:"group,omitempty"`
+	Tenant       string    `json:"tenant,omitempty"`
+	IssuedAt     time.Time `json:"issuedAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	RequestedBy  string    `json:"requestedBy"`
+	Revoked      bool      `json:"revoked"`
+}
+
+type certificatesHandler struct {
+	ctx                  context.Context
+	certificateInventory inventory.Repository
+	revocationList       revocation.RevocationListRepository
+	runtimes             bool
+}
+
+// NewCertificatesHandler creates the handler listing the certificates issued for applications, or for runtimes if runtimes is set
+func NewCertificatesHandler(ctx context.Context, certificateInventory inventory.Repository, revocationListRepository revocation.RevocationListRepository, runtimes bool) *certificatesHandler {
+	return &certificatesHandler{
+		ctx:                  ctx,
+		certificateInventory: certificateInventory,
+		revocationList:       revocationListRepository,
+		runtimes:             runtimes,
+	}
+}
+
+func (handler certificatesHandler) List(w http.ResponseWriter, request *http.Request) {
+	filter, appError := handler.readFilter(request)
+	if appError != nil {
+		httphelpers.RespondWithErrorAndLog(w, appError)
+		return
+	}
+
+	records, err := handler.certificateInventory.List(handler.ctx, filter)
+	if err != nil {
+		httphelpers.RespondWithErrorAndLog(w, apperrors.Internal("Failed to list issued certificates: %s.", err))
+		return
+	}
+
+	revokedEntries, err := handler.revocationList.List(handler.ctx)
+	if err != nil {
+		httphelpers.RespondWithErrorAndLog(w, apperrors.Internal("Failed to read revocation list: %s.", err))
+		return
+	}
+
+	revoked := make(map[string]bool, len(revokedEntries))
+	for _, entry := range revokedEntries {
+		revoked[entry.Hash] = true
+	}
+
+	certificates := make([]certificateRecord, 0, len(records))
+	for _, record := range records {
+		certificates = append(certificates, toCertificateRecord(record, revoked[record.Fingerprint]))
+	}
+
+	httphelpers.RespondWithBody(w, http.StatusOK, certificates)
+}
+
+func (handler certificatesHandler) readFilter(request *http.Request) (inventory.Filter, apperrors.AppError) {
+	query := request.URL.Query()
+
+	filter := inventory.Filter{
+		Runtimes: handler.runtimes,
+		Group:    query.Get("group"),
+		Tenant:   query.Get("tenant"),
+	}
+
+	if !handler.runtimes {
+		filter.Application = query.Get("application")
+	}
+
+	if expiresBefore := query.Get("expiresBefore"); expiresBefore != "" {
+		t, err := time.Parse(time.RFC3339, expiresBefore)
+		if err != nil {
+			return inventory.Filter{}, apperrors.BadRequest("Invalid expiresBefore value, RFC 3339 time expected: %s.", err)
+		}
+		filter.ExpiresBefore = t
+	}
+
+	return filter, nil
+}
+
+func toCertificateRecord(record inventory.Record, revoked bool) certificateRecord {
+	serialNumber := ""
+	if record.SerialNumber != nil {
+		serialNumber = record.SerialNumber.Text(16)
+	}
+
+	return certificateRecord{
+		SerialNumber: serialNumber,
+		Fingerprint:  record.Fingerprint,
+		Subject:      record.Subject,
+		Application:  record.Application,
+		Group:        record.Group,
+		Tenant:       record.Tenant,
+		IssuedAt:     record.IssuedAt,
+		ExpiresAt:    record.ExpiresAt,
+		RequestedBy:  record.RequestedBy,
+		Revoked:      revoked,
+	}
+}
@@ -0,0 +1,116 @@
+package internalapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	inventoryMocks "github.com/kyma-project/kyma/components/connector-service/internal/inventory/mocks"
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificatesHandler_List(t *testing.T) {
+
+	testContext := context.Background()
+	revokedHash := "f21139ef2b82d02ee73a56c5c73c053fbafa3480a0b35459cba276b0667c57fc"
+	validHash := "3f5ba1e0ac3ab7a94f8da1ee6a30f4c4e0abde1dd5c2c1cb4a4da0a7ef73e2d4"
+
+	t.Run("should list certificates matching filter", func(t *testing.T) {
+		//given
+		expiresBefore := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		expiresAt := time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("List", testContext, inventory.Filter{Application: "app", Tenant: "tenant", ExpiresBefore: expiresBefore}).Return([]inventory.Record{
+			{SerialNumber: big.NewInt(0xabc), Fingerprint: revokedHash, Application: "app", Tenant: "tenant", ExpiresAt: expiresAt, RequestedBy: "token"},
+			{SerialNumber: big.NewInt(0xdef), Fingerprint: validHash, Application: "app", Tenant: "tenant", ExpiresAt: expiresAt, RequestedBy: "certificate:" + revokedHash},
+		}, nil)
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("List", testContext).Return([]revocation.Entry{{Hash: revokedHash}}, nil)
+
+		handler := NewCertificatesHandler(testContext, certificateInventory, revocationListRepository, false)
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/applications/certificates?application=app&tenant=tenant&expiresBefore=2030-01-01T00:00:00Z", nil)
+
+		//when
+		handler.List(rr, req)
+
+		//then
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var certificates []certificateRecord
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&certificates))
+		require.Len(t, certificates, 2)
+
+		assert.Equal(t, "abc", certificates[0].SerialNumber)
+		assert.True(t, certificates[0].Revoked)
+		assert.Equal(t, "def", certificates[1].SerialNumber)
+		assert.False(t, certificates[1].Revoked)
+		assert.Equal(t, "certificate:"+revokedHash, certificates[1].RequestedBy)
+		certificateInventory.AssertExpectations(t)
+	})
+
+	t.Run("should list runtime certificates ignoring application", func(t *testing.T) {
+		//given
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("List", testContext, inventory.Filter{Runtimes: true, Group: "group"}).Return([]inventory.Record{}, nil)
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("List", testContext).Return([]revocation.Entry{}, nil)
+
+		handler := NewCertificatesHandler(testContext, certificateInventory, revocationListRepository, true)
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/runtimes/certificates?group=group&application=app", nil)
+
+		//when
+		handler.List(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, "[]", rr.Body.String())
+		certificateInventory.AssertExpectations(t)
+	})
+
+	t.Run("should return http code 400 when expiry time is invalid", func(t *testing.T) {
+		//given
+		handler := NewCertificatesHandler(testContext, &inventoryMocks.Repository{}, &mocks.RevocationListRepository{}, false)
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/applications/certificates?expiresBefore=tomorrow", nil)
+
+		//when
+		handler.List(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return http code 500 when failed to list certificates", func(t *testing.T) {
+		//given
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("List", testContext, inventory.Filter{}).Return(nil, errors.New("error"))
+
+		handler := NewCertificatesHandler(testContext, certificateInventory, &mocks.RevocationListRepository{}, false)
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/applications/certificates", nil)
+
+		//when
+		handler.List(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
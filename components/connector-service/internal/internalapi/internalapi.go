@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/kyma-project/kyma/components/connector-service/internal/errorhandler"
 	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
 	"github.com/kyma-project/kyma/components/connector-service/internal/tokens"
 )
 
@@ -20,6 +21,7 @@ type Config struct {
 	ContextExtractor        clientcontext.ConnectorClientExtractor
 	RevokedCertsRepo        revocation.RevocationListRepository
 	RevokedRuntimeCertsRepo revocation.RevocationListRepository
+	CertificateInventory    inventory.Repository
 }
 
 type FunctionalMiddlewares struct {
@@ -53,7 +55,8 @@ func NewHandlerBuilder(functionalMiddlewares FunctionalMiddlewares, globalMiddle
 
 func (hb *handlerBuilder) WithApps(appCfg Config) {
 	appTokenHandler := NewTokenHandler(appCfg.TokenManager, appCfg.CSRInfoURL, appCfg.ContextExtractor)
-	appRevocationHandler := NewRevocationHandler(hb.ctx, appCfg.RevokedCertsRepo)
+	appRevocationHandler := NewRevocationHandler(hb.ctx, appCfg.RevokedCertsRepo, appCfg.CertificateInventory)
+	appCertificatesHandler := NewCertificatesHandler(hb.ctx, appCfg.CertificateInventory, appCfg.RevokedCertsRepo, false)
 
 	applicationTokenRouter := hb.router.PathPrefix("/v1/applications").Subrouter()
 	httphelpers.WithMiddlewares(applicationTokenRouter, hb.functionalMiddlewares.ApplicationCtxMiddleware)
@@ -61,11 +64,15 @@ func (hb *handlerBuilder) WithApps(appCfg Config) {
 
 	applicationRevocationRouter := hb.router.Path("/v1/applications/certificates/revocations").Subrouter()
 	applicationRevocationRouter.HandleFunc("", appRevocationHandler.Revoke).Methods(http.MethodPost)
+
+	applicationCertificatesRouter := hb.router.Path("/v1/applications/certificates").Subrouter()
+	applicationCertificatesRouter.HandleFunc("", appCertificatesHandler.List).Methods(http.MethodGet)
 }
 
 func (hb *handlerBuilder) WithRuntimes(runtimeCfg Config) {
 	runtimeTokenHandler := NewTokenHandler(runtimeCfg.TokenManager, runtimeCfg.CSRInfoURL, runtimeCfg.ContextExtractor)
-	runtimeRevocationHandler := NewRevocationHandler(hb.ctx, runtimeCfg.RevokedRuntimeCertsRepo)
+	runtimeRevocationHandler := NewRevocationHandler(hb.ctx, runtimeCfg.RevokedRuntimeCertsRepo, runtimeCfg.CertificateInventory)
+	runtimeCertificatesHandler := NewCertificatesHandler(hb.ctx, runtimeCfg.CertificateInventory, runtimeCfg.RevokedRuntimeCertsRepo, true)
 
 	clusterTokenRouter := hb.router.PathPrefix("/v1/runtimes").Subrouter()
 	httphelpers.WithMiddlewares(clusterTokenRouter, hb.functionalMiddlewares.RuntimeCtxMiddleware)
@@ -73,6 +80,9 @@ func (hb *handlerBuilder) WithRuntimes(runtimeCfg Config) {
 
 	runtimeRevocationRouter := hb.router.Path("/v1/runtimes/certificates/revocations").Subrouter()
 	runtimeRevocationRouter.HandleFunc("", runtimeRevocationHandler.Revoke).Methods(http.MethodPost)
+
+	runtimeCertificatesRouter := hb.router.Path("/v1/runtimes/certificates").Subrouter()
+	runtimeCertificatesRouter.HandleFunc("", runtimeCertificatesHandler.List).Methods(http.MethodGet)
 }
 
 func (hb *handlerBuilder) GetHandler() http.Handler {
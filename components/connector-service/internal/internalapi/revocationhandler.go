@@ -4,27 +4,33 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
 	"github.com/kyma-project/kyma/components/connector-service/internal/httphelpers"
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
 	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 	"github.com/sirupsen/logrus"
 )
 
 type revocationBody struct {
 	Hash string
+	// SerialNumber is the hexadecimal serial number of the certificate recorded in the certificate inventory
+	SerialNumber string `json:",omitempty"`
 }
 
 type revocationHandler struct {
-	ctx            context.Context
-	revocationList revocation.RevocationListRepository
+	ctx                  context.Context
+	revocationList       revocation.RevocationListRepository
+	certificateInventory inventory.Repository
 }
 
-func NewRevocationHandler(ctx context.Context, revocationListRepository revocation.RevocationListRepository) *revocationHandler {
+func NewRevocationHandler(ctx context.Context, revocationListRepository revocation.RevocationListRepository, certificateInventory inventory.Repository) *revocationHandler {
 	return &revocationHandler{
-		ctx:            ctx,
-		revocationList: revocationListRepository,
+		ctx:                  ctx,
+		revocationList:       revocationListRepository,
+		certificateInventory: certificateInventory,
 	}
 }
 
@@ -35,7 +41,13 @@ func (handler revocationHandler) Revoke(w http.ResponseWriter, request *http.Req
 		return
 	}
 
-	appError = handler.addToRevocationList(rb.Hash)
+	entry, appError := handler.revocationEntry(rb)
+	if appError != nil {
+		httphelpers.RespondWithErrorAndLog(w, appError)
+		return
+	}
+
+	appError = handler.addToRevocationList(entry)
 	if appError != nil {
 		httphelpers.RespondWithErrorAndLog(w, appError)
 		return
@@ -58,17 +70,55 @@ func (handler revocationHandler) readBody(request *http.Request) (*revocationBod
 		return nil, apperrors.BadRequest("Error while unmarshalling request body: %s.", err)
 	}
 
-	if rb.Hash == "" {
+	if rb.Hash == "" && rb.SerialNumber == "" {
 		return nil, apperrors.BadRequest("Error while unmarshalling request body: certificate hash value not provided.")
 	}
 
 	return &rb, nil
 }
 
-func (handler revocationHandler) addToRevocationList(hash string) apperrors.AppError {
-	err := handler.revocationList.Insert(handler.ctx, hash)
+// revocationEntry looks up the certificate in the inventory by the serial number or the hash, the certificate identified
+// by the hash which isn't recorded in the inventory is revoked only by the hash
+func (handler revocationHandler) revocationEntry(rb *revocationBody) (revocation.Entry, apperrors.AppError) {
+	if rb.SerialNumber == "" {
+		record, found, err := handler.certificateInventory.GetByFingerprint(handler.ctx, rb.Hash)
+		if err != nil {
+			return revocation.Entry{}, apperrors.Internal("Failed to read certificate with hash %s from inventory: %s.", rb.Hash, err)
+		}
+		if !found {
+			return revocation.Entry{Hash: rb.Hash}, nil
+		}
+
+		return revocation.Entry{Hash: rb.Hash, SerialNumber: record.SerialNumber, ExpiresAt: record.ExpiresAt}, nil
+	}
+
+	serialNumber, ok := new(big.Int).SetString(rb.SerialNumber, 16)
+	if !ok {
+		return revocation.Entry{}, apperrors.BadRequest("Invalid serial number: %s, hexadecimal value expected.", rb.SerialNumber)
+	}
+
+	record, found, err := handler.certificateInventory.Get(handler.ctx, serialNumber)
+	if err != nil {
+		return revocation.Entry{}, apperrors.Internal("Failed to read certificate with serial number %s from inventory: %s.", rb.SerialNumber, err)
+	}
+	if !found {
+		return revocation.Entry{}, apperrors.NotFound("Certificate with serial number %s not found or already expired.", rb.SerialNumber)
+	}
+	if rb.Hash != "" && rb.Hash != record.Fingerprint {
+		return revocation.Entry{}, apperrors.BadRequest("Certificate hash does not match the certificate with serial number %s.", rb.SerialNumber)
+	}
+
+	return revocation.Entry{
+		Hash:         record.Fingerprint,
+		SerialNumber: record.SerialNumber,
+		ExpiresAt:    record.ExpiresAt,
+	}, nil
+}
+
+func (handler revocationHandler) addToRevocationList(entry revocation.Entry) apperrors.AppError {
+	err := handler.revocationList.Insert(handler.ctx, entry)
 
-	logrus.Warningf("Adding certificate with hash: %s to revocation list.", hash)
+	logrus.Warningf("Adding certificate with hash: %s to revocation list.", entry.Hash)
 	if err != nil {
 		return apperrors.Internal("Unable to mark certificate as revoked: %s.", err)
 	}
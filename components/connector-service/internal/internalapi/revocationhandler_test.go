@@ -6,10 +6,15 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/kyma-project/kyma/components/connector-service/internal/inventory"
+	inventoryMocks "github.com/kyma-project/kyma/components/connector-service/internal/inventory/mocks"
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 	"github.com/kyma-project/kyma/components/connector-service/internal/revocation/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,12 +27,15 @@ func TestRevocationHandler(t *testing.T) {
 	urlRevocation := "/v1/applications/certificates/revocations"
 	hashedTestCert := "f21139ef2b82d02ee73a56c5c73c053fbafa3480a0b35459cba276b0667c57fc"
 
+	unrecordedInventory := &inventoryMocks.Repository{}
+	unrecordedInventory.On("GetByFingerprint", testContext, hashedTestCert).Return(inventory.Record{}, false, nil)
+
 	t.Run("should revoke certificate and return http code 201", func(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
-		revocationListRepository.On("Insert", testContext, hashedTestCert).Return(nil)
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hashedTestCert}).Return(nil)
 
-		handler := NewRevocationHandler(testContext, revocationListRepository)
+		handler := NewRevocationHandler(testContext, revocationListRepository, unrecordedInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -51,9 +59,9 @@ func TestRevocationHandler(t *testing.T) {
 	t.Run("should return http code 201 when certificate already revoked", func(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
-		revocationListRepository.On("Insert", testContext, hashedTestCert).Return(nil)
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hashedTestCert}).Return(nil)
 
-		handler := NewRevocationHandler(testContext, revocationListRepository)
+		handler := NewRevocationHandler(testContext, revocationListRepository, unrecordedInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -87,7 +95,7 @@ func TestRevocationHandler(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
 
-		handler := NewRevocationHandler(testContext, revocationListRepository)
+		handler := NewRevocationHandler(testContext, revocationListRepository, unrecordedInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -98,7 +106,7 @@ func TestRevocationHandler(t *testing.T) {
 
 		//then
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
-		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"))
+		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("revocation.Entry"))
 	})
 
 	t.Run("should return http code 400 when failed to unmarshall", func(t *testing.T) {
@@ -106,7 +114,7 @@ func TestRevocationHandler(t *testing.T) {
 
 		revocationListRepository := &mocks.RevocationListRepository{}
 
-		handler := NewRevocationHandler(testContext, revocationListRepository)
+		handler := NewRevocationHandler(testContext, revocationListRepository, unrecordedInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -126,15 +134,15 @@ func TestRevocationHandler(t *testing.T) {
 
 		//then
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
-		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"))
+		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("revocation.Entry"))
 	})
 
 	t.Run("should return http code 500 when certificate revocation not persisted", func(t *testing.T) {
 		//given
 		revocationListRepository := &mocks.RevocationListRepository{}
-		revocationListRepository.On("Insert", testContext, hashedTestCert).Return(errors.New("Error"))
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hashedTestCert}).Return(errors.New("Error"))
 
-		handler := NewRevocationHandler(testContext, revocationListRepository)
+		handler := NewRevocationHandler(testContext, revocationListRepository, unrecordedInventory)
 
 		rr := httptest.NewRecorder()
 
@@ -152,6 +160,130 @@ func TestRevocationHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 		revocationListRepository.AssertExpectations(t)
 	})
+
+	t.Run("should revoke certificate identified by serial number", func(t *testing.T) {
+		//given
+		serialNumber := big.NewInt(0xabc)
+		expiresAt := time.Now().Add(time.Hour)
+
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("Get", testContext, serialNumber).Return(inventory.Record{
+			SerialNumber: serialNumber,
+			Fingerprint:  hashedTestCert,
+			ExpiresAt:    expiresAt,
+		}, true, nil)
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hashedTestCert, SerialNumber: serialNumber, ExpiresAt: expiresAt}).Return(nil)
+
+		handler := NewRevocationHandler(testContext, revocationListRepository, certificateInventory)
+
+		rr := httptest.NewRecorder()
+
+		body, err := marshall(revocationBody{SerialNumber: "abc"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, body)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		revocationListRepository.AssertExpectations(t)
+		certificateInventory.AssertExpectations(t)
+	})
+
+	t.Run("should revoke certificate identified by hash with serial number from inventory", func(t *testing.T) {
+		//given
+		record := inventory.Record{SerialNumber: big.NewInt(0xabc), Fingerprint: hashedTestCert, ExpiresAt: time.Now().Add(time.Hour)}
+
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("GetByFingerprint", testContext, hashedTestCert).Return(record, true, nil)
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+		revocationListRepository.On("Insert", testContext, revocation.Entry{Hash: hashedTestCert, SerialNumber: record.SerialNumber, ExpiresAt: record.ExpiresAt}).Return(nil)
+
+		handler := NewRevocationHandler(testContext, revocationListRepository, certificateInventory)
+
+		body, err := marshall(revocationBody{Hash: hashedTestCert})
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, body)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		revocationListRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return http code 500 when failed to read inventory", func(t *testing.T) {
+		//given
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("GetByFingerprint", testContext, hashedTestCert).Return(inventory.Record{}, false, errors.New("error"))
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+
+		handler := NewRevocationHandler(testContext, revocationListRepository, certificateInventory)
+
+		body, err := marshall(revocationBody{Hash: hashedTestCert})
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, body)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		revocationListRepository.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return http code 404 when certificate with serial number not recorded", func(t *testing.T) {
+		//given
+		certificateInventory := &inventoryMocks.Repository{}
+		certificateInventory.On("Get", testContext, big.NewInt(0xabc)).Return(inventory.Record{}, false, nil)
+
+		revocationListRepository := &mocks.RevocationListRepository{}
+
+		handler := NewRevocationHandler(testContext, revocationListRepository, certificateInventory)
+
+		rr := httptest.NewRecorder()
+
+		body, err := marshall(revocationBody{SerialNumber: "abc"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, body)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("revocation.Entry"))
+	})
+
+	t.Run("should return http code 400 when serial number is not hexadecimal", func(t *testing.T) {
+		//given
+		revocationListRepository := &mocks.RevocationListRepository{}
+
+		handler := NewRevocationHandler(testContext, revocationListRepository, &inventoryMocks.Repository{})
+
+		rr := httptest.NewRecorder()
+
+		body, err := marshall(revocationBody{SerialNumber: "not hex"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, urlRevocation, body)
+
+		//when
+		handler.Revoke(rr, req)
+
+		//then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		revocationListRepository.AssertNotCalled(t, "Insert", mock.AnythingOfType("context.Context"), mock.AnythingOfType("revocation.Entry"))
+	})
 }
 
 func marshall(body interface{}) (io.Reader, error) {
@@ -0,0 +1,113 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var ownerLabels = []string{"application", "group", "tenant"}
+
+type owner struct {
+	application string
+	group       string
+	tenant      string
+}
+
+type expiryMetrics struct {
+	repository     Repository
+	warningTime    time.Duration
+	valid          *prometheus.GaugeVec
+	expiring       *prometheus.GaugeVec
+	earliestExpiry *prometheus.GaugeVec
+}
+
+// NewExpiryMetrics registers the gauges describing the issued certificates which are not expired.
+// Certificates expiring within the warningTime are reported as expiring.
+func NewExpiryMetrics(repository Repository, warningTime time.Duration) (*expiryMetrics, apperrors.AppError) {
+	metrics := &expiryMetrics{
+		repository:  repository,
+		warningTime: warningTime,
+		valid: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "connector_service_certificates_valid",
+			Help: "Number of the issued certificates which are not expired.",
+		}, ownerLabels),
+		expiring: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "connector_service_certificates_expiring",
+			Help: "Number of the issued certificates which expire within the warning time and were not renewed.",
+		}, ownerLabels),
+		earliestExpiry: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "connector_service_certificates_earliest_expiry_timestamp_seconds",
+			Help: "Expiry of the first issued certificate to expire which was not renewed, expressed as Unix time.",
+		}, ownerLabels),
+	}
+
+	for _, vector := range []*prometheus.GaugeVec{metrics.valid, metrics.expiring, metrics.earliestExpiry} {
+		if err := prometheus.Register(vector); err != nil {
+			return nil, apperrors.Internal("Failed to register certificate expiry metrics: %s", err.Error())
+		}
+	}
+
+	return metrics, nil
+}
+
+// Update sets the gauges to the state of the certificates issued for applications and runtimes. The certificates
+// which were renewed are not reported as expiring, as the clients already use their successors.
+func (m *expiryMetrics) Update(ctx context.Context) error {
+	records, err := m.repository.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	renewed := renewedFingerprints(records)
+
+	valid := map[owner]int{}
+	expiring := map[owner]int{}
+	earliestExpiry := map[owner]time.Time{}
+
+	warningThreshold := time.Now().Add(m.warningTime)
+	for _, record := range records {
+		recordOwner := owner{application: record.Application, group: record.Group, tenant: record.Tenant}
+
+		valid[recordOwner]++
+		if renewed[record.Fingerprint] {
+			continue
+		}
+		if record.ExpiresAt.Before(warningThreshold) {
+			expiring[recordOwner]++
+		}
+		if expiresAt, found := earliestExpiry[recordOwner]; !found || record.ExpiresAt.Before(expiresAt) {
+			earliestExpiry[recordOwner] = record.ExpiresAt
+		}
+	}
+
+	m.valid.Reset()
+	m.expiring.Reset()
+	m.earliestExpiry.Reset()
+
+	for recordOwner, count := range valid {
+		labels := []string{recordOwner.application, recordOwner.group, recordOwner.tenant}
+
+		m.valid.WithLabelValues(labels...).Set(float64(count))
+		m.expiring.WithLabelValues(labels...).Set(float64(expiring[recordOwner]))
+		if expiresAt, found := earliestExpiry[recordOwner]; found {
+			m.earliestExpiry.WithLabelValues(labels...).Set(float64(expiresAt.Unix()))
+		}
+	}
+
+	return nil
+}
+
+// Run updates the gauges every period, it never returns
+func (m *expiryMetrics) Run(period time.Duration) {
+	for {
+		if err := m.Update(context.Background()); err != nil {
+			log.Errorf("Failed to update certificate expiry metrics: %s", err)
+		}
+
+		time.Sleep(period)
+	}
+}
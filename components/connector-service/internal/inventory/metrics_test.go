@@ -0,0 +1,76 @@
+package inventory
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExpiryMetrics_Update(t *testing.T) {
+	ctx := context.Background()
+
+	metrics, err := NewExpiryMetrics(nil, 24*time.Hour)
+	require.NoError(t, err)
+
+	t.Run("should report valid and expiring certificates", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		metrics.repository = NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		expiringRecord := newRecord(big.NewInt(1), "app", time.Hour)
+		for _, record := range []Record{expiringRecord, newRecord(big.NewInt(2), "app", 48*time.Hour), newRecord(big.NewInt(3), "", 48*time.Hour)} {
+			require.NoError(t, metrics.repository.Insert(ctx, record))
+		}
+
+		// when
+		updateErr := metrics.Update(ctx)
+
+		// then
+		require.NoError(t, updateErr)
+
+		assert.Equal(t, float64(2), gaugeValue(t, metrics.valid, "app", "group", "tenant"))
+		assert.Equal(t, float64(1), gaugeValue(t, metrics.expiring, "app", "group", "tenant"))
+		assert.Equal(t, float64(expiringRecord.ExpiresAt.Unix()), gaugeValue(t, metrics.earliestExpiry, "app", "group", "tenant"))
+
+		assert.Equal(t, float64(1), gaugeValue(t, metrics.valid, "", "group", "tenant"))
+		assert.Equal(t, float64(0), gaugeValue(t, metrics.expiring, "", "group", "tenant"))
+	})
+
+	t.Run("should not report renewed certificates as expiring", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		metrics.repository = NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		renewedRecord := newRecord(big.NewInt(1), "app", time.Hour)
+		renewalRecord := newRecord(big.NewInt(2), "app", 48*time.Hour)
+		renewalRecord.Fingerprint = strings.Repeat("a", len(renewedRecord.Fingerprint))
+		renewalRecord.RequestedBy = RenewalRequestor(renewedRecord.Fingerprint)
+		for _, record := range []Record{renewedRecord, renewalRecord} {
+			require.NoError(t, metrics.repository.Insert(ctx, record))
+		}
+
+		// when
+		updateErr := metrics.Update(ctx)
+
+		// then
+		require.NoError(t, updateErr)
+
+		assert.Equal(t, float64(2), gaugeValue(t, metrics.valid, "app", "group", "tenant"))
+		assert.Equal(t, float64(0), gaugeValue(t, metrics.expiring, "app", "group", "tenant"))
+		assert.Equal(t, float64(renewalRecord.ExpiresAt.Unix()), gaugeValue(t, metrics.earliestExpiry, "app", "group", "tenant"))
+	})
+}
+
+func gaugeValue(t *testing.T, vector *prometheus.GaugeVec, labels ...string) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, vector.WithLabelValues(labels...).Write(metric))
+	return metric.GetGauge().GetValue()
+}
@@ -0,0 +1,315 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	certificateConfigMapNamePrefix = "connector-certificate-"
+	certificateConfigMapLabel      = "connector-service/certificate"
+	// fingerprintLabel indexes the records by the fingerprint, it holds only its prefix as the label values are limited to 63 characters
+	fingerprintLabel       = "connector-service/fingerprint"
+	fingerprintLabelLength = 32
+
+	serialNumberKey = "serialNumber"
+	fingerprintKey  = "fingerprint"
+	subjectKey      = "subject"
+	applicationKey  = "application"
+	groupKey        = "group"
+	tenantKey       = "tenant"
+	issuedAtKey     = "issuedAt"
+	expiresAtKey    = "expiresAt"
+	requestedByKey  = "requestedBy"
+
+	renewalRequestorPrefix = "certificate:"
+)
+
+// Record describes the certificate issued by the Connector Service
+type Record struct {
+	SerialNumber *big.Int
+	// Fingerprint is the SHA-256 hash of the DER encoded certificate, the same as used in the revocation list
+	Fingerprint string
+	Subject     string
+	// Application is empty for the certificates issued for runtimes
+	Application string
+	Group       string
+	Tenant      string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+	// RequestedBy identifies the client which requested the certificate, either the hash of the one-time token or the fingerprint of the renewed certificate
+	RequestedBy string
+}
+
+// Filter selects the records, empty fields match all records
+type Filter struct {
+	// Runtimes selects the certificates issued for runtimes instead of applications
+	Runtimes    bool
+	Application string
+	Group       string
+	Tenant      string
+	// ExpiresBefore selects the certificates which expire before the time
+	ExpiresBefore time.Time
+}
+
+type Repository interface {
+	Insert(ctx context.Context, record Record) error
+	Get(ctx context.Context, serialNumber *big.Int) (Record, bool, error)
+	// GetByFingerprint returns the record of the certificate with the SHA-256 hash of the DER encoded certificate
+	GetByFingerprint(ctx context.Context, fingerprint string) (Record, bool, error)
+	// List returns records of the certificates which are not expired, sorted by the expiry
+	List(ctx context.Context, filter Filter) ([]Record, error)
+	// ListAll returns records of the certificates issued for applications and runtimes which are not expired, sorted by the expiry
+	ListAll(ctx context.Context) ([]Record, error)
+}
+
+type ConfigMapManager interface {
+	Create(ctx context.Context, configMap *v1.ConfigMap, options metav1.CreateOptions) (*v1.ConfigMap, error)
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*v1.ConfigMap, error)
+	Delete(ctx context.Context, name string, options metav1.DeleteOptions) error
+	List(ctx context.Context, options metav1.ListOptions) (*v1.ConfigMapList, error)
+}
+
+type repository struct {
+	configMapManager ConfigMapManager
+}
+
+// NewRepository creates the repository storing every issued certificate in a separate config map, so that the records
+// are shared between the replicas. Config maps of the expired certificates are removed by the garbage collector started with RunGarbageCollector.
+func NewRepository(configMapManager ConfigMapManager) *repository {
+	return &repository{
+		configMapManager: configMapManager,
+	}
+}
+
+func (r *repository) Insert(ctx context.Context, record Record) error {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: configMapName(record.SerialNumber),
+			Labels: map[string]string{
+				certificateConfigMapLabel: "true",
+				fingerprintLabel:          fingerprintLabelValue(record.Fingerprint),
+			},
+		},
+		Data: encode(record),
+	}
+
+	_, err := r.configMapManager.Create(ctx, configMap, metav1.CreateOptions{})
+	return err
+}
+
+func (r *repository) Get(ctx context.Context, serialNumber *big.Int) (Record, bool, error) {
+	configMap, err := r.configMapManager.Get(ctx, configMapName(serialNumber), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return Record{}, false, nil
+		}
+		return Record{}, false, err
+	}
+
+	record := decode(configMap.Data)
+	if !record.ExpiresAt.After(time.Now()) {
+		return Record{}, false, nil
+	}
+
+	return record, true, nil
+}
+
+func (r *repository) GetByFingerprint(ctx context.Context, fingerprint string) (Record, bool, error) {
+	selector := fmt.Sprintf("%s=true,%s=%s", certificateConfigMapLabel, fingerprintLabel, fingerprintLabelValue(fingerprint))
+	configMaps, err := r.configMapManager.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	now := time.Now()
+	for _, configMap := range configMaps.Items {
+		record := decode(configMap.Data)
+		if record.Fingerprint == fingerprint && record.ExpiresAt.After(now) {
+			return record, true, nil
+		}
+	}
+
+	return Record{}, false, nil
+}
+
+func (r *repository) List(ctx context.Context, filter Filter) ([]Record, error) {
+	records, err := r.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]Record, 0, len(records))
+	for _, record := range records {
+		if filter.matches(record) {
+			filtered = append(filtered, record)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *repository) ListAll(ctx context.Context) ([]Record, error) {
+	configMaps, err := r.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	records := make([]Record, 0, len(configMaps))
+	for _, configMap := range configMaps {
+		record := decode(configMap.Data)
+		if record.ExpiresAt.After(now) {
+			records = append(records, record)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ExpiresAt.Before(records[j].ExpiresAt)
+	})
+
+	return records, nil
+}
+
+// RemoveExpired deletes the config maps of the expired certificates
+func (r *repository) RemoveExpired(ctx context.Context) error {
+	configMaps, err := r.list(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, configMap := range configMaps {
+		if decode(configMap.Data).ExpiresAt.After(now) {
+			continue
+		}
+
+		err := r.configMapManager.Delete(ctx, configMap.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunGarbageCollector removes the records of the expired certificates every period, it never returns
+func (r *repository) RunGarbageCollector(period time.Duration) {
+	for {
+		if err := r.RemoveExpired(context.Background()); err != nil {
+			log.Errorf("Failed to remove records of expired certificates: %s", err)
+		}
+
+		time.Sleep(period)
+	}
+}
+
+func (r *repository) list(ctx context.Context) ([]v1.ConfigMap, error) {
+	configMaps, err := r.configMapManager.List(ctx, metav1.ListOptions{LabelSelector: certificateConfigMapLabel + "=true"})
+	if err != nil {
+		return nil, err
+	}
+
+	return configMaps.Items, nil
+}
+
+func (f Filter) matches(record Record) bool {
+	if f.Runtimes != (record.Application == "") {
+		return false
+	}
+	if f.Application != "" && f.Application != record.Application {
+		return false
+	}
+	if f.Group != "" && f.Group != record.Group {
+		return false
+	}
+	if f.Tenant != "" && f.Tenant != record.Tenant {
+		return false
+	}
+	if !f.ExpiresBefore.IsZero() && !record.ExpiresAt.Before(f.ExpiresBefore) {
+		return false
+	}
+
+	return true
+}
+
+// RenewalRequestor identifies the client which renewed the certificate with the fingerprint
+func RenewalRequestor(fingerprint string) string {
+	return renewalRequestorPrefix + fingerprint
+}
+
+// renewedFingerprints returns the fingerprints of the certificates renewed by the records
+func renewedFingerprints(records []Record) map[string]bool {
+	renewed := map[string]bool{}
+	for _, record := range records {
+		if strings.HasPrefix(record.RequestedBy, renewalRequestorPrefix) {
+			renewed[strings.TrimPrefix(record.RequestedBy, renewalRequestorPrefix)] = true
+		}
+	}
+
+	return renewed
+}
+
+func configMapName(serialNumber *big.Int) string {
+	return certificateConfigMapNamePrefix + serialNumber.Text(16)
+}
+
+func fingerprintLabelValue(fingerprint string) string {
+	if len(fingerprint) > fingerprintLabelLength {
+		return fingerprint[:fingerprintLabelLength]
+	}
+
+	return fingerprint
+}
+
+func encode(record Record) map[string]string {
+	return map[string]string{
+		serialNumberKey: record.SerialNumber.Text(16),
+		fingerprintKey:  record.Fingerprint,
+		subjectKey:      record.Subject,
+		applicationKey:  record.Application,
+		groupKey:        record.Group,
+		tenantKey:       record.Tenant,
+		issuedAtKey:     record.IssuedAt.UTC().Format(time.RFC3339),
+		expiresAtKey:    record.ExpiresAt.UTC().Format(time.RFC3339),
+		requestedByKey:  record.RequestedBy,
+	}
+}
+
+func decode(data map[string]string) Record {
+	serialNumber, ok := new(big.Int).SetString(data[serialNumberKey], 16)
+	if !ok {
+		serialNumber = nil
+	}
+
+	return Record{
+		SerialNumber: serialNumber,
+		Fingerprint:  data[fingerprintKey],
+		Subject:      data[subjectKey],
+		Application:  data[applicationKey],
+		Group:        data[groupKey],
+		Tenant:       data[tenantKey],
+		IssuedAt:     parseTime(data[issuedAtKey]),
+		ExpiresAt:    parseTime(data[expiresAtKey]),
+		RequestedBy:  data[requestedByKey],
+	}
+}
+
+// parseTime returns zero time for malformed values, so that such records are treated as expired
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
@@ -0,0 +1,177 @@
+package inventory
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const namespace = "kyma-integration"
+
+func TestRepository(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("should insert and get record", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		repository := NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		record := newRecord(big.NewInt(0xabc), "app", time.Hour)
+
+		// when
+		err := repository.Insert(ctx, record)
+		require.NoError(t, err)
+
+		stored, found, err := repository.Get(ctx, big.NewInt(0xabc))
+
+		// then
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, record, stored)
+
+		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, "connector-certificate-abc", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "true", configMap.Labels[certificateConfigMapLabel])
+	})
+
+	t.Run("should not get unknown or expired record", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		repository := NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		require.NoError(t, repository.Insert(ctx, newRecord(big.NewInt(1), "app", -time.Hour)))
+
+		for _, serialNumber := range []int64{1, 2} {
+			// when
+			_, found, err := repository.Get(ctx, big.NewInt(serialNumber))
+
+			// then
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+	})
+
+	t.Run("should get record by fingerprint", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		repository := NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		record := newRecord(big.NewInt(1), "app", time.Hour)
+		// the other fingerprint has the same prefix which is stored in the label
+		otherRecord := newRecord(big.NewInt(2), "app", time.Hour)
+		otherRecord.Fingerprint = record.Fingerprint[:fingerprintLabelLength] + strings.Repeat("0", len(record.Fingerprint)-fingerprintLabelLength)
+		expiredRecord := newRecord(big.NewInt(3), "app", -time.Hour)
+		expiredRecord.Fingerprint = strings.Repeat("e", len(record.Fingerprint))
+
+		for _, r := range []Record{record, otherRecord, expiredRecord} {
+			require.NoError(t, repository.Insert(ctx, r))
+		}
+
+		// when
+		stored, found, err := repository.GetByFingerprint(ctx, record.Fingerprint)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, record, stored)
+
+		for _, fingerprint := range []string{expiredRecord.Fingerprint, strings.Repeat("a", len(record.Fingerprint))} {
+			// when
+			_, found, err := repository.GetByFingerprint(ctx, fingerprint)
+
+			// then
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+	})
+
+	t.Run("should list records matching filter sorted by expiry", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		repository := NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		laterAppRecord := newRecord(big.NewInt(1), "app", 2*time.Hour)
+		appRecord := newRecord(big.NewInt(2), "app", time.Hour)
+		otherAppRecord := newRecord(big.NewInt(3), "other-app", 90*time.Minute)
+		expiredAppRecord := newRecord(big.NewInt(4), "app", -time.Hour)
+		runtimeRecord := newRecord(big.NewInt(5), "", 30*time.Minute)
+
+		for _, record := range []Record{laterAppRecord, appRecord, otherAppRecord, expiredAppRecord, runtimeRecord} {
+			require.NoError(t, repository.Insert(ctx, record))
+		}
+
+		testCases := []struct {
+			filter   Filter
+			expected []Record
+		}{
+			{filter: Filter{}, expected: []Record{appRecord, otherAppRecord, laterAppRecord}},
+			{filter: Filter{Application: "app"}, expected: []Record{appRecord, laterAppRecord}},
+			{filter: Filter{ExpiresBefore: time.Now().Add(100 * time.Minute)}, expected: []Record{appRecord, otherAppRecord}},
+			{filter: Filter{Tenant: "other-tenant"}, expected: []Record{}},
+			{filter: Filter{Runtimes: true}, expected: []Record{runtimeRecord}},
+		}
+
+		for _, testCase := range testCases {
+			// when
+			records, err := repository.List(ctx, testCase.filter)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, records)
+		}
+
+		// when
+		records, err := repository.ListAll(ctx)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Record{runtimeRecord, appRecord, otherAppRecord, laterAppRecord}, records)
+	})
+
+	t.Run("should remove records of expired certificates", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "malformed", Namespace: namespace, Labels: map[string]string{certificateConfigMapLabel: "true"}},
+		})
+		repository := NewRepository(clientset.CoreV1().ConfigMaps(namespace))
+
+		require.NoError(t, repository.Insert(ctx, newRecord(big.NewInt(1), "app", time.Hour)))
+		require.NoError(t, repository.Insert(ctx, newRecord(big.NewInt(2), "app", -time.Hour)))
+
+		// when
+		err := repository.RemoveExpired(ctx)
+
+		// then
+		require.NoError(t, err)
+
+		configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, configMaps.Items, 1)
+		assert.Equal(t, "connector-certificate-1", configMaps.Items[0].Name)
+	})
+}
+
+func newRecord(serialNumber *big.Int, application string, validity time.Duration) Record {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	return Record{
+		SerialNumber: serialNumber,
+		Fingerprint:  "f21139ef2b82d02ee73a56c5c73c053fbafa3480a0b35459cba276b0667c57fc",
+		Subject:      "CN=" + application + ",OU=OrgUnit,O=Organization,L=Waldorf,ST=Waldorf,C=DE",
+		Application:  application,
+		Group:        "group",
+		Tenant:       "tenant",
+		IssuedAt:     now,
+		ExpiresAt:    now.Add(validity),
+		RequestedBy:  "token:8d969eef6ecad3c29a3a629280e686cf0c3f5d5a86aff3ca12020c923adc6c92",
+	}
+}
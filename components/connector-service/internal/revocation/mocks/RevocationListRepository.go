@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	revocation "github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 )
 
 // RevocationListRepository is an autogenerated mock type for the RevocationListRepository type
@@ -34,16 +36,39 @@ func (_m *RevocationListRepository) Contains(ctx context.Context, hash string) (
 	return r0, r1
 }
 
-// Insert provides a mock function with given fields: ctx, hash
-func (_m *RevocationListRepository) Insert(ctx context.Context, hash string) error {
-	ret := _m.Called(ctx, hash)
+// Insert provides a mock function with given fields: ctx, entry
+func (_m *RevocationListRepository) Insert(ctx context.Context, entry revocation.Entry) error {
+	ret := _m.Called(ctx, entry)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, hash)
+	if rf, ok := ret.Get(0).(func(context.Context, revocation.Entry) error); ok {
+		r0 = rf(ctx, entry)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// List provides a mock function with given fields: ctx
+func (_m *RevocationListRepository) List(ctx context.Context) ([]revocation.Entry, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []revocation.Entry
+	if rf, ok := ret.Get(0).(func(context.Context) []revocation.Entry); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]revocation.Entry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -2,6 +2,9 @@ package revocation
 
 import (
 	"context"
+	"encoding/json"
+	"math/big"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,44 +16,81 @@ type Manager interface {
 	Update(ctx context.Context, configmap *v1.ConfigMap, options metav1.UpdateOptions) (*v1.ConfigMap, error)
 }
 
+// Entry describes the revoked certificate
+type Entry struct {
+	// Hash is the SHA-256 hash of the DER encoded certificate
+	Hash string
+	// SerialNumber is nil if the revoked certificate is known only by its hash
+	SerialNumber *big.Int
+	RevokedAt    time.Time
+	// ExpiresAt is the expiry of the revoked certificate, the entry is removed after it
+	ExpiresAt time.Time
+}
+
 type RevocationListRepository interface {
-	Insert(ctx context.Context, hash string) error
+	// Insert marks the certificate as revoked and removes the entries of the expired certificates
+	Insert(ctx context.Context, entry Entry) error
 	Contains(ctx context.Context, hash string) (bool, error)
+	// List returns entries of the revoked certificates which are not expired
+	List(ctx context.Context) ([]Entry, error)
+}
+
+type storedEntry struct {
+	SerialNumber string    `json:"serialNumber,omitempty"`
+	RevokedAt    time.Time `json:"revokedAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
 }
 
 type revocationListRepository struct {
 	configListManager Manager
 	configMapName     string
+	defaultExpiration time.Duration
 }
 
-func NewRepository(configListManager Manager, configMapName string) RevocationListRepository {
+// NewRepository creates the repository storing revoked certificates in the config map.
+// Entries without the expiry, including those created by older versions, are kept for the defaultExpiration,
+// which should not be shorter than the validity time of the issued certificates.
+func NewRepository(configListManager Manager, configMapName string, defaultExpiration time.Duration) RevocationListRepository {
 	return &revocationListRepository{
 		configListManager: configListManager,
 		configMapName:     configMapName,
+		defaultExpiration: defaultExpiration,
 	}
 }
 
-func (r *revocationListRepository) Insert(ctx context.Context, hash string) error {
-	configMap, err := r.configListManager.Get(ctx, r.configMapName, metav1.GetOptions{})
-	if err != nil {
-		return err
+func (r *revocationListRepository) Insert(ctx context.Context, entry Entry) error {
+	now := time.Now()
+	if entry.RevokedAt.IsZero() {
+		entry.RevokedAt = now
 	}
-
-	revokedCerts := configMap.Data
-	if revokedCerts == nil {
-		revokedCerts = map[string]string{}
+	if entry.ExpiresAt.IsZero() {
+		entry.ExpiresAt = now.Add(r.defaultExpiration)
 	}
-	revokedCerts[hash] = hash
 
-	updatedConfigMap := configMap
-	updatedConfigMap.Data = revokedCerts
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		configMap, err := r.configListManager.Get(ctx, r.configMapName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		revokedCerts := map[string]string{}
+		for hash, value := range configMap.Data {
+			stored := r.decode(hash, value)
+			if stored.ExpiresAt.After(now) {
+				revokedCerts[hash] = encode(stored)
+			}
+		}
 
-	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		_, err = r.configListManager.Update(ctx, updatedConfigMap, metav1.UpdateOptions{})
+		// the certificate revoked before keeps its revocation time unless the entry adds the serial number
+		if stored, found := configMap.Data[entry.Hash]; !found || (entry.SerialNumber != nil && r.decode(entry.Hash, stored).SerialNumber == "") {
+			revokedCerts[entry.Hash] = encode(toStoredEntry(entry))
+		}
+
+		configMap.Data = revokedCerts
+
+		_, err = r.configListManager.Update(ctx, configMap, metav1.UpdateOptions{})
 		return err
 	})
-
-	return err
 }
 
 func (r *revocationListRepository) Contains(ctx context.Context, hash string) (bool, error) {
@@ -66,3 +106,62 @@ func (r *revocationListRepository) Contains(ctx context.Context, hash string) (b
 
 	return found, nil
 }
+
+func (r *revocationListRepository) List(ctx context.Context) ([]Entry, error) {
+	configMap, err := r.configListManager.Get(ctx, r.configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]Entry, 0, len(configMap.Data))
+	for hash, value := range configMap.Data {
+		entry := toEntry(hash, r.decode(hash, value))
+		if entry.ExpiresAt.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// decode reads the stored entry, values written by older versions contain only the hash and get the default expiration
+func (r *revocationListRepository) decode(hash, value string) storedEntry {
+	var stored storedEntry
+	if value == hash || json.Unmarshal([]byte(value), &stored) != nil || stored.ExpiresAt.IsZero() {
+		now := time.Now()
+		return storedEntry{RevokedAt: now, ExpiresAt: now.Add(r.defaultExpiration)}
+	}
+
+	return stored
+}
+
+func encode(stored storedEntry) string {
+	value, _ := json.Marshal(stored)
+	return string(value)
+}
+
+func toStoredEntry(entry Entry) storedEntry {
+	stored := storedEntry{
+		RevokedAt: entry.RevokedAt.UTC(),
+		ExpiresAt: entry.ExpiresAt.UTC(),
+	}
+	if entry.SerialNumber != nil {
+		stored.SerialNumber = entry.SerialNumber.Text(16)
+	}
+
+	return stored
+}
+
+func toEntry(hash string, stored storedEntry) Entry {
+	entry := Entry{
+		Hash:      hash,
+		RevokedAt: stored.RevokedAt,
+		ExpiresAt: stored.ExpiresAt,
+	}
+	if serialNumber, ok := new(big.Int).SetString(stored.SerialNumber, 16); ok {
+		entry.SerialNumber = serialNumber
+	}
+
+	return entry
+}
@@ -1,10 +1,14 @@
-package revocation
+package revocation_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/kyma-project/kyma/components/connector-service/internal/revocation"
 	k8sclientMocks "github.com/kyma-project/kyma/components/connector-service/internal/revocation/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,6 +18,14 @@ import (
 
 var testContext = context.Background()
 
+const defaultExpiration = 90 * 24 * time.Hour
+
+type storedEntry struct {
+	SerialNumber string    `json:"serialNumber"`
+	RevokedAt    time.Time `json:"revokedAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
 func TestRevocationListRepository(t *testing.T) {
 
 	configMapName := "revokedCertificates"
@@ -29,7 +41,7 @@ func TestRevocationListRepository(t *testing.T) {
 				Data: nil,
 			}, nil)
 
-		repository := NewRepository(configListManagerMock, configMapName)
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
 
 		// when
 		isPresent, err := repository.Contains(testContext, someHash)
@@ -43,6 +55,7 @@ func TestRevocationListRepository(t *testing.T) {
 	t.Run("should insert value to the list", func(t *testing.T) {
 		// given
 		someHash := "someHash"
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
 		configListManagerMock := &k8sclientMocks.Manager{}
 
 		configListManagerMock.On("Get", testContext, configMapName, mock.AnythingOfType("v1.GetOptions")).Return(
@@ -50,22 +63,115 @@ func TestRevocationListRepository(t *testing.T) {
 				Data: nil,
 			}, nil)
 
-		configListManagerMock.On("Update", testContext, &v1.ConfigMap{
-			Data: map[string]string{
-				someHash: someHash,
-			}}, mock.AnythingOfType("v1.UpdateOptions")).Return(&v1.ConfigMap{
-			Data: map[string]string{
-				someHash: someHash,
-			}}, nil)
+		var updated *v1.ConfigMap
+		configListManagerMock.On("Update", testContext, mock.AnythingOfType("*v1.ConfigMap"), mock.AnythingOfType("v1.UpdateOptions")).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*v1.ConfigMap) }).
+			Return(&v1.ConfigMap{}, nil)
 
-		repository := NewRepository(configListManagerMock, configMapName)
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
 
 		// when
-		err := repository.Insert(context.Background(), someHash)
+		err := repository.Insert(context.Background(), revocation.Entry{Hash: someHash, SerialNumber: big.NewInt(255), ExpiresAt: expiresAt})
 		require.NoError(t, err)
 
 		// then
 		configListManagerMock.AssertExpectations(t)
+		require.Contains(t, updated.Data, someHash)
+
+		stored := decode(t, updated.Data[someHash])
+		assert.Equal(t, "ff", stored.SerialNumber)
+		assert.Equal(t, expiresAt, stored.ExpiresAt)
+		assert.False(t, stored.RevokedAt.IsZero())
+	})
+
+	t.Run("should remove expired entries and keep entries created by older versions when inserting value", func(t *testing.T) {
+		// given
+		configListManagerMock := &k8sclientMocks.Manager{}
+
+		configListManagerMock.On("Get", testContext, configMapName, mock.AnythingOfType("v1.GetOptions")).Return(
+			&v1.ConfigMap{
+				Data: map[string]string{
+					"expiredHash": encode(t, storedEntry{SerialNumber: "1", ExpiresAt: time.Now().Add(-time.Minute)}),
+					"validHash":   encode(t, storedEntry{SerialNumber: "2", ExpiresAt: time.Now().Add(time.Hour)}),
+					"legacyHash":  "legacyHash",
+				},
+			}, nil)
+
+		var updated *v1.ConfigMap
+		configListManagerMock.On("Update", testContext, mock.AnythingOfType("*v1.ConfigMap"), mock.AnythingOfType("v1.UpdateOptions")).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*v1.ConfigMap) }).
+			Return(&v1.ConfigMap{}, nil)
+
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
+
+		// when
+		err := repository.Insert(testContext, revocation.Entry{Hash: "newHash"})
+		require.NoError(t, err)
+
+		// then
+		assert.Len(t, updated.Data, 3)
+		assert.NotContains(t, updated.Data, "expiredHash")
+		assert.Contains(t, updated.Data, "validHash")
+
+		legacy := decode(t, updated.Data["legacyHash"])
+		assert.WithinDuration(t, time.Now().Add(defaultExpiration), legacy.ExpiresAt, time.Minute)
+
+		inserted := decode(t, updated.Data["newHash"])
+		assert.Empty(t, inserted.SerialNumber)
+		assert.WithinDuration(t, time.Now().Add(defaultExpiration), inserted.ExpiresAt, time.Minute)
+	})
+
+	t.Run("should keep revocation time of already revoked certificate", func(t *testing.T) {
+		// given
+		revokedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		configListManagerMock := &k8sclientMocks.Manager{}
+
+		configListManagerMock.On("Get", testContext, configMapName, mock.AnythingOfType("v1.GetOptions")).Return(
+			&v1.ConfigMap{
+				Data: map[string]string{
+					"someHash": encode(t, storedEntry{SerialNumber: "1", RevokedAt: revokedAt, ExpiresAt: time.Now().Add(time.Hour)}),
+				},
+			}, nil)
+
+		var updated *v1.ConfigMap
+		configListManagerMock.On("Update", testContext, mock.AnythingOfType("*v1.ConfigMap"), mock.AnythingOfType("v1.UpdateOptions")).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*v1.ConfigMap) }).
+			Return(&v1.ConfigMap{}, nil)
+
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
+
+		// when
+		err := repository.Insert(testContext, revocation.Entry{Hash: "someHash", SerialNumber: big.NewInt(1)})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, revokedAt, decode(t, updated.Data["someHash"]).RevokedAt)
+	})
+
+	t.Run("should list entries which are not expired", func(t *testing.T) {
+		// given
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		configListManagerMock := &k8sclientMocks.Manager{}
+
+		configListManagerMock.On("Get", testContext, configMapName, mock.AnythingOfType("v1.GetOptions")).Return(
+			&v1.ConfigMap{
+				Data: map[string]string{
+					"expiredHash": encode(t, storedEntry{SerialNumber: "1", ExpiresAt: time.Now().Add(-time.Minute)}),
+					"validHash":   encode(t, storedEntry{SerialNumber: "a", ExpiresAt: expiresAt}),
+				},
+			}, nil)
+
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
+
+		// when
+		entries, err := repository.List(testContext)
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, entries, 1)
+		assert.Equal(t, "validHash", entries[0].Hash)
+		assert.Equal(t, big.NewInt(10), entries[0].SerialNumber)
+		assert.Equal(t, expiresAt, entries[0].ExpiresAt)
 	})
 
 	t.Run("should return error when failed to get config map", func(t *testing.T) {
@@ -75,15 +181,18 @@ func TestRevocationListRepository(t *testing.T) {
 
 		configListManagerMock.On("Get", testContext, configMapName, mock.AnythingOfType("v1.GetOptions")).Return(nil, errors.New("some error"))
 
-		repository := NewRepository(configListManagerMock, configMapName)
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
 
 		// when
-		err := repository.Insert(testContext, someHash)
+		err := repository.Insert(testContext, revocation.Entry{Hash: someHash})
 		require.Error(t, err)
 
 		_, err = repository.Contains(testContext, someHash)
 		require.Error(t, err)
 
+		_, err = repository.List(testContext)
+		require.Error(t, err)
+
 		// then
 		configListManagerMock.AssertExpectations(t)
 	})
@@ -98,18 +207,27 @@ func TestRevocationListRepository(t *testing.T) {
 				Data: nil,
 			}, nil)
 
-		configListManagerMock.On("Update", testContext, &v1.ConfigMap{
-			Data: map[string]string{
-				someHash: someHash,
-			}}, mock.AnythingOfType("v1.UpdateOptions")).Return(nil, errors.New("some error"))
+		configListManagerMock.On("Update", testContext, mock.AnythingOfType("*v1.ConfigMap"), mock.AnythingOfType("v1.UpdateOptions")).Return(nil, errors.New("some error"))
 
-		repository := NewRepository(configListManagerMock, configMapName)
+		repository := revocation.NewRepository(configListManagerMock, configMapName, defaultExpiration)
 
 		// when
-		err := repository.Insert(testContext, someHash)
+		err := repository.Insert(testContext, revocation.Entry{Hash: someHash})
 		require.Error(t, err)
 
 		// then
 		configListManagerMock.AssertExpectations(t)
 	})
 }
+
+func encode(t *testing.T, entry storedEntry) string {
+	value, err := json.Marshal(entry)
+	require.NoError(t, err)
+	return string(value)
+}
+
+func decode(t *testing.T, value string) storedEntry {
+	var entry storedEntry
+	require.NoError(t, json.Unmarshal([]byte(value), &entry))
+	return entry
+}
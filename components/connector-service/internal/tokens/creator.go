@@ -43,7 +43,10 @@ func (svc *tokenCreator) Save(tokenData interface{}) (string, apperrors.AppError
 		return "", apperrors.Internal("Failed to generate token, %s", err.Error())
 	}
 
-	svc.store.Put(token, string(jsonData), svc.tokenTTL)
+	err = svc.store.Put(token, string(jsonData), svc.tokenTTL)
+	if err != nil {
+		return "", apperrors.Internal("Failed to store token, %s", err.Error())
+	}
 
 	return token, nil
 }
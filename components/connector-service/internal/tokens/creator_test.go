@@ -33,7 +33,7 @@ func TestManager_Save(t *testing.T) {
 	t.Run("should trigger Put method on token store", func(t *testing.T) {
 
 		tokenCache := &mocks.TokenCache{}
-		tokenCache.On("Put", token, mock.AnythingOfType("string"), tokenTTL)
+		tokenCache.On("Put", token, mock.AnythingOfType("string"), tokenTTL).Return(nil)
 		tokenGenerator := func() (string, apperrors.AppError) { return token, nil }
 
 		tokenCreator := NewTokenCreator(tokenTTL, tokenCache, tokenGenerator)
@@ -60,4 +60,17 @@ func TestManager_Save(t *testing.T) {
 
 		require.Error(t, err)
 	})
+
+	t.Run("should return error when failed to store token", func(t *testing.T) {
+		tokenCache := &mocks.TokenCache{}
+		tokenCache.On("Put", token, mock.AnythingOfType("string"), tokenTTL).Return(errors.New("error"))
+		tokenGenerator := func() (string, apperrors.AppError) { return token, nil }
+
+		tokenCreator := NewTokenCreator(tokenTTL, tokenCache, tokenGenerator)
+
+		_, err := tokenCreator.Save(serializable)
+
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeInternal, err.Code())
+	})
 }
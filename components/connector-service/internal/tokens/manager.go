@@ -5,10 +5,14 @@ import (
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
 	"github.com/kyma-project/kyma/components/connector-service/internal/tokens/tokencache"
+	log "github.com/sirupsen/logrus"
 )
 
 type Manager interface {
+	// Resolve redeems the token, it can't be resolved again until it is released
 	Resolve(token string, destination interface{}) apperrors.AppError
+	// Release makes the resolved token available again, e.g. when the request using it failed
+	Release(token string)
 	Delete(token string)
 }
 
@@ -23,19 +27,31 @@ func NewTokenManager(store tokencache.TokenCache) *tokenManager {
 }
 
 func (svc *tokenManager) Resolve(token string, destination interface{}) apperrors.AppError {
-	encodedParams, found := svc.store.Get(token)
+	encodedParams, found, err := svc.store.Redeem(token)
+	if err != nil {
+		return apperrors.Internal("Failed to redeem token, %s", err.Error())
+	}
 	if !found {
 		return apperrors.NotFound("Token not found")
 	}
 
-	err := json.Unmarshal([]byte(encodedParams), destination)
+	err = json.Unmarshal([]byte(encodedParams), destination)
 	if err != nil {
+		svc.Delete(token)
 		return apperrors.Internal("Failed to unmarshal token params, %s", err.Error())
 	}
 
 	return nil
 }
 
+func (svc *tokenManager) Release(token string) {
+	if err := svc.store.Release(token); err != nil {
+		log.Errorf("Failed to release token: %s", err)
+	}
+}
+
 func (svc *tokenManager) Delete(token string) {
-	svc.store.Delete(token)
+	if err := svc.store.Delete(token); err != nil {
+		log.Errorf("Failed to delete token: %s", err)
+	}
 }
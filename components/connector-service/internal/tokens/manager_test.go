@@ -3,6 +3,7 @@ package tokens
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/kyma-project/kyma/components/connector-service/internal/apperrors"
@@ -24,7 +25,7 @@ func TestTokenService_Resolve(t *testing.T) {
 		dummyData := data{Data: dummyString}
 
 		tokenCache := &mocks.TokenCache{}
-		tokenCache.On("Get", token).Return(encodedData, true)
+		tokenCache.On("Redeem", token).Return(encodedData, true, nil)
 
 		var destination data
 
@@ -41,7 +42,7 @@ func TestTokenService_Resolve(t *testing.T) {
 	t.Run("should return error when token not found", func(t *testing.T) {
 		// given
 		tokenCache := &mocks.TokenCache{}
-		tokenCache.On("Get", token).Return("", false)
+		tokenCache.On("Redeem", token).Return("", false, nil)
 
 		var destination data
 
@@ -54,6 +55,59 @@ func TestTokenService_Resolve(t *testing.T) {
 		require.Error(t, err)
 		assert.Equal(t, apperrors.CodeNotFound, err.Code())
 	})
+
+	t.Run("should return error when failed to redeem token", func(t *testing.T) {
+		// given
+		tokenCache := &mocks.TokenCache{}
+		tokenCache.On("Redeem", token).Return("", false, errors.New("error"))
+
+		var destination data
+
+		tokenManager := NewTokenManager(tokenCache)
+
+		// when
+		err := tokenManager.Resolve(token, &destination)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeInternal, err.Code())
+	})
+
+	t.Run("should delete token when failed to unmarshal token params", func(t *testing.T) {
+		// given
+		tokenCache := &mocks.TokenCache{}
+		tokenCache.On("Redeem", token).Return("not json", true, nil)
+		tokenCache.On("Delete", token).Return(nil)
+
+		var destination data
+
+		tokenManager := NewTokenManager(tokenCache)
+
+		// when
+		err := tokenManager.Resolve(token, &destination)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeInternal, err.Code())
+		tokenCache.AssertExpectations(t)
+	})
+}
+
+func TestTokenService_Release(t *testing.T) {
+
+	t.Run("should release token", func(t *testing.T) {
+		// given
+		tokenCache := &mocks.TokenCache{}
+		tokenCache.On("Release", token).Return(nil)
+
+		tokenManager := NewTokenManager(tokenCache)
+
+		// when
+		tokenManager.Release(token)
+
+		// then
+		tokenCache.AssertExpectations(t)
+	})
 }
 
 func compact(src []byte) []byte {
@@ -16,6 +16,11 @@ func (_m *Manager) Delete(token string) {
 	_m.Called(token)
 }
 
+// Release provides a mock function with given fields: token
+func (_m *Manager) Release(token string) {
+	_m.Called(token)
+}
+
 // Resolve provides a mock function with given fields: token, destination
 func (_m *Manager) Resolve(token string, destination interface{}) apperrors.AppError {
 	ret := _m.Called(token, destination)
@@ -14,12 +14,35 @@ type TokenCache struct {
 }
 
 // Delete provides a mock function with given fields: token
-func (_m *TokenCache) Delete(token string) {
-	_m.Called(token)
+func (_m *TokenCache) Delete(token string) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
-// Get provides a mock function with given fields: token
-func (_m *TokenCache) Get(token string) (string, bool) {
+// Put provides a mock function with given fields: token, data, ttl
+func (_m *TokenCache) Put(token string, data string, ttl time.Duration) error {
+	ret := _m.Called(token, data, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) error); ok {
+		r0 = rf(token, data, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Redeem provides a mock function with given fields: token
+func (_m *TokenCache) Redeem(token string) (string, bool, error) {
 	ret := _m.Called(token)
 
 	var r0 string
@@ -36,10 +59,26 @@ func (_m *TokenCache) Get(token string) (string, bool) {
 		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(token)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// Put provides a mock function with given fields: token, data, ttl
-func (_m *TokenCache) Put(token string, data string, ttl time.Duration) {
-	_m.Called(token, data, ttl)
+// Release provides a mock function with given fields: token
+func (_m *TokenCache) Release(token string) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
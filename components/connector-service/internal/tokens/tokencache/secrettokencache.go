@@ -0,0 +1,171 @@
+package tokencache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	tokenSecretNamePrefix = "connector-token-"
+	tokenSecretLabel      = "connector-service/token"
+
+	tokenDataKey      = "data"
+	tokenExpiresAtKey = "expiresAt"
+	tokenRedeemedKey  = "redeemed"
+)
+
+type SecretsManager interface {
+	Create(ctx context.Context, secret *v1.Secret, options metav1.CreateOptions) (*v1.Secret, error)
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*v1.Secret, error)
+	Update(ctx context.Context, secret *v1.Secret, options metav1.UpdateOptions) (*v1.Secret, error)
+	Delete(ctx context.Context, name string, options metav1.DeleteOptions) error
+	List(ctx context.Context, options metav1.ListOptions) (*v1.SecretList, error)
+}
+
+type secretTokenCache struct {
+	ctx            context.Context
+	secretsManager SecretsManager
+}
+
+// NewSecretTokenCache creates the token cache storing every token in a separate secret, so that the tokens are shared
+// between the replicas and survive restarts. The token is redeemed with an update guarded by the resource version of the secret.
+// Secrets of the expired tokens are removed by the garbage collector started with RunGarbageCollector.
+func NewSecretTokenCache(secretsManager SecretsManager) *secretTokenCache {
+	return &secretTokenCache{
+		ctx:            context.Background(),
+		secretsManager: secretsManager,
+	}
+}
+
+func (c *secretTokenCache) Put(token string, data string, ttl time.Duration) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   secretName(token),
+			Labels: map[string]string{tokenSecretLabel: "true"},
+		},
+		Data: map[string][]byte{
+			tokenDataKey:      []byte(data),
+			tokenExpiresAtKey: []byte(time.Now().Add(ttl).UTC().Format(time.RFC3339)),
+			tokenRedeemedKey:  []byte("false"),
+		},
+	}
+
+	_, err := c.secretsManager.Create(c.ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+func (c *secretTokenCache) Redeem(token string) (string, bool, error) {
+	var data string
+	redeemed := false
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret, err := c.secretsManager.Get(c.ctx, secretName(token), metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if isExpired(secret, time.Now()) || string(secret.Data[tokenRedeemedKey]) == "true" {
+			return nil
+		}
+
+		secret.Data[tokenRedeemedKey] = []byte("true")
+
+		// the update fails with conflict if other replica modified the secret in the meantime
+		_, err = c.secretsManager.Update(c.ctx, secret, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+
+		data = string(secret.Data[tokenDataKey])
+		redeemed = true
+
+		return nil
+	})
+
+	return data, redeemed, err
+}
+
+func (c *secretTokenCache) Release(token string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret, err := c.secretsManager.Get(c.ctx, secretName(token), metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if isExpired(secret, time.Now()) {
+			return nil
+		}
+
+		secret.Data[tokenRedeemedKey] = []byte("false")
+
+		_, err = c.secretsManager.Update(c.ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (c *secretTokenCache) Delete(token string) error {
+	err := c.secretsManager.Delete(c.ctx, secretName(token), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveExpired deletes the secrets of the expired tokens, including the tokens which were redeemed but never deleted
+func (c *secretTokenCache) RemoveExpired() error {
+	secrets, err := c.secretsManager.List(c.ctx, metav1.ListOptions{LabelSelector: tokenSecretLabel + "=true"})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, secret := range secrets.Items {
+		if !isExpired(&secret, now) {
+			continue
+		}
+
+		err := c.secretsManager.Delete(c.ctx, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunGarbageCollector removes the expired tokens every period, it never returns
+func (c *secretTokenCache) RunGarbageCollector(period time.Duration) {
+	for {
+		if err := c.RemoveExpired(); err != nil {
+			log.Errorf("Failed to remove expired tokens: %s", err)
+		}
+
+		time.Sleep(period)
+	}
+}
+
+// secretName is derived from the hash of the token, so that the token is not exposed in the name
+func secretName(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return tokenSecretNamePrefix + hex.EncodeToString(hash[:])
+}
+
+func isExpired(secret *v1.Secret, now time.Time) bool {
+	expiresAt, err := time.Parse(time.RFC3339, string(secret.Data[tokenExpiresAtKey]))
+	return err != nil || !expiresAt.After(now)
+}
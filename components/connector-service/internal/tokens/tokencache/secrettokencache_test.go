@@ -0,0 +1,242 @@
+package tokencache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma/components/connector-service/internal/tokens/tokencache/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSecretTokenCache_Put(t *testing.T) {
+
+	t.Run("should create secret with token data", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Create", context.Background(), mock.MatchedBy(func(secret *v1.Secret) bool {
+			return secret.Name == secretName(token) &&
+				secret.Labels[tokenSecretLabel] == "true" &&
+				string(secret.Data[tokenDataKey]) == tokenData &&
+				string(secret.Data[tokenRedeemedKey]) == "false" &&
+				!isExpired(secret, time.Now())
+		}), metav1.CreateOptions{}).Return(&v1.Secret{}, nil)
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		err := cache.Put(token, tokenData, time.Minute)
+
+		// then
+		require.NoError(t, err)
+		secretsManager.AssertExpectations(t)
+	})
+
+	t.Run("should return error when failed to create secret", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Create", context.Background(), mock.Anything, metav1.CreateOptions{}).Return(nil, errors.New("error"))
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		err := cache.Put(token, tokenData, time.Minute)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestSecretTokenCache_Redeem(t *testing.T) {
+
+	t.Run("should mark token as redeemed", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(tokenSecret(time.Minute, false), nil)
+		secretsManager.On("Update", context.Background(), mock.MatchedBy(func(secret *v1.Secret) bool {
+			return string(secret.Data[tokenRedeemedKey]) == "true"
+		}), metav1.UpdateOptions{}).Return(&v1.Secret{}, nil)
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		data, found, err := cache.Redeem(token)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, tokenData, data)
+		secretsManager.AssertExpectations(t)
+	})
+
+	t.Run("should not redeem token redeemed by other replica in the meantime", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(tokenSecret(time.Minute, false), nil).Once()
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(tokenSecret(time.Minute, true), nil).Once()
+		secretsManager.On("Update", context.Background(), mock.Anything, metav1.UpdateOptions{}).
+			Return(nil, k8serrors.NewConflict(schema.GroupResource{Resource: "secrets"}, secretName(token), errors.New("conflict"))).Once()
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		_, found, err := cache.Redeem(token)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, found)
+		secretsManager.AssertExpectations(t)
+	})
+
+	t.Run("should not redeem redeemed token", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(tokenSecret(time.Minute, true), nil)
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		_, found, err := cache.Redeem(token)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, found)
+		secretsManager.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not redeem expired token", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(tokenSecret(-time.Minute, false), nil)
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		_, found, err := cache.Redeem(token)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should not redeem token which does not exist", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).
+			Return(nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, secretName(token)))
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		_, found, err := cache.Redeem(token)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should return error when failed to get secret", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(nil, errors.New("error"))
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		_, _, err := cache.Redeem(token)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestSecretTokenCache_Release(t *testing.T) {
+
+	t.Run("should mark token as not redeemed", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Get", context.Background(), secretName(token), metav1.GetOptions{}).Return(tokenSecret(time.Minute, true), nil)
+		secretsManager.On("Update", context.Background(), mock.MatchedBy(func(secret *v1.Secret) bool {
+			return string(secret.Data[tokenRedeemedKey]) == "false"
+		}), metav1.UpdateOptions{}).Return(&v1.Secret{}, nil)
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		err := cache.Release(token)
+
+		// then
+		require.NoError(t, err)
+		secretsManager.AssertExpectations(t)
+	})
+}
+
+func TestSecretTokenCache_Delete(t *testing.T) {
+
+	t.Run("should ignore token which does not exist", func(t *testing.T) {
+		// given
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("Delete", context.Background(), secretName(token), metav1.DeleteOptions{}).
+			Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, secretName(token)))
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		err := cache.Delete(token)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestSecretTokenCache_RemoveExpired(t *testing.T) {
+
+	t.Run("should delete secrets of expired tokens", func(t *testing.T) {
+		// given
+		expired := tokenSecret(-time.Minute, true)
+		expired.Name = "expired"
+		valid := tokenSecret(time.Minute, false)
+		valid.Name = "valid"
+
+		secretsManager := &mocks.SecretsManager{}
+		secretsManager.On("List", context.Background(), metav1.ListOptions{LabelSelector: "connector-service/token=true"}).
+			Return(&v1.SecretList{Items: []v1.Secret{*expired, *valid}}, nil)
+		secretsManager.On("Delete", context.Background(), "expired", metav1.DeleteOptions{}).Return(nil)
+
+		cache := NewSecretTokenCache(secretsManager)
+
+		// when
+		err := cache.RemoveExpired()
+
+		// then
+		require.NoError(t, err)
+		secretsManager.AssertExpectations(t)
+		secretsManager.AssertNotCalled(t, "Delete", context.Background(), "valid", metav1.DeleteOptions{})
+	})
+}
+
+func tokenSecret(ttl time.Duration, redeemed bool) *v1.Secret {
+	redeemedValue := "false"
+	if redeemed {
+		redeemedValue = "true"
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   secretName(token),
+			Labels: map[string]string{tokenSecretLabel: "true"},
+		},
+		Data: map[string][]byte{
+			tokenDataKey:      []byte(tokenData),
+			tokenExpiresAtKey: []byte(time.Now().Add(ttl).UTC().Format(time.RFC3339)),
+			tokenRedeemedKey:  []byte(redeemedValue),
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package tokencache
 
 import (
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -9,34 +10,68 @@ import (
 const defaultTTLMinutes = 5
 
 type TokenCache interface {
-	Put(token string, data string, ttl time.Duration)
-	Get(token string) (string, bool)
-	Delete(token string)
+	Put(token string, data string, ttl time.Duration) error
+	// Redeem atomically marks the token as used and returns its data. Only one of the concurrent callers redeems the token,
+	// it can't be redeemed again until it is released.
+	Redeem(token string) (string, bool, error)
+	// Release makes the redeemed token available again
+	Release(token string) error
+	Delete(token string) error
+}
+
+type entry struct {
+	data     string
+	redeemed bool
 }
 
 type tokenCache struct {
 	tokenCache *cache.Cache
+	mutex      sync.Mutex
 }
 
+// NewTokenCache creates the in-memory token cache, tokens stored in it can be redeemed only by the same replica
 func NewTokenCache() TokenCache {
 	return &tokenCache{
 		tokenCache: cache.New(time.Duration(defaultTTLMinutes)*time.Minute, 1*time.Minute),
 	}
 }
 
-func (c *tokenCache) Put(token string, data string, ttl time.Duration) {
-	c.tokenCache.Set(token, data, ttl)
+func (c *tokenCache) Put(token string, data string, ttl time.Duration) error {
+	c.tokenCache.Set(token, &entry{data: data}, ttl)
+	return nil
 }
 
-func (c *tokenCache) Get(token string) (string, bool) {
-	data, found := c.tokenCache.Get(token)
+func (c *tokenCache) Redeem(token string) (string, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	value, found := c.tokenCache.Get(token)
 	if !found {
-		return "", found
+		return "", false, nil
+	}
+
+	stored := value.(*entry)
+	if stored.redeemed {
+		return "", false, nil
+	}
+
+	stored.redeemed = true
+
+	return stored.data, true, nil
+}
+
+func (c *tokenCache) Release(token string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if value, found := c.tokenCache.Get(token); found {
+		value.(*entry).redeemed = false
 	}
 
-	return data.(string), found
+	return nil
 }
 
-func (c *tokenCache) Delete(token string) {
+func (c *tokenCache) Delete(token string) error {
 	c.tokenCache.Delete(token)
+	return nil
 }
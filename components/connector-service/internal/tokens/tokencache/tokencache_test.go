@@ -0,0 +1,82 @@
+package tokencache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token     = "token"
+	tokenData = "data"
+)
+
+func TestTokenCache(t *testing.T) {
+
+	t.Run("should redeem token only once until it is released", func(t *testing.T) {
+		// given
+		cache := NewTokenCache()
+		require.NoError(t, cache.Put(token, tokenData, time.Minute))
+
+		// when
+		data, found, err := cache.Redeem(token)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, tokenData, data)
+
+		_, found, err = cache.Redeem(token)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		require.NoError(t, cache.Release(token))
+
+		data, found, err = cache.Redeem(token)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, tokenData, data)
+	})
+
+	t.Run("should not redeem deleted token", func(t *testing.T) {
+		// given
+		cache := NewTokenCache()
+		require.NoError(t, cache.Put(token, tokenData, time.Minute))
+
+		// when
+		require.NoError(t, cache.Delete(token))
+
+		// then
+		_, found, err := cache.Redeem(token)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should redeem token by one of concurrent callers", func(t *testing.T) {
+		// given
+		cache := NewTokenCache()
+		require.NoError(t, cache.Put(token, tokenData, time.Minute))
+
+		var redeemed int32
+		wg := &sync.WaitGroup{}
+
+		// when
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, found, _ := cache.Redeem(token); found {
+					atomic.AddInt32(&redeemed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Equal(t, int32(1), redeemed)
+	})
+}
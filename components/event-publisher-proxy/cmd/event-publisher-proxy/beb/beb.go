@@ -13,6 +13,7 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/oauth"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/signals"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
@@ -82,6 +83,15 @@ func (c *Commander) Start() error {
 		applicationLister,
 	)
 
+	// configure schema validator
+	schemaValidationMode, err := schema.ParseMode(c.envCfg.SchemaValidationMode)
+	if err != nil {
+		c.logger.Errorf("Invalid schema validation configuration with error: %s", err)
+		return err
+	}
+	schemaRegistry := schema.NewRegistry(applicationLister, c.envCfg.EventTypePrefix, c.envCfg.SchemaCacheTTL, c.logger)
+	schemaValidator := schema.NewValidator(schemaValidationMode, schemaRegistry, c.metricsCollector, c.logger)
+
 	// Configure Subscription Lister
 	subDynamicSharedInfFactory := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
 	subLister := subDynamicSharedInfFactory.ForResource(subscribed.GVR).Lister()
@@ -97,7 +107,7 @@ func (c *Commander) Start() error {
 
 	// start handler which blocks until it receives a shutdown signal
 	if err := beb.NewHandler(messageReceiver, messageSender, c.envCfg.RequestTimeout, legacyTransformer, c.opts,
-		subscribedProcessor, c.logger, c.metricsCollector).Start(ctx); err != nil {
+		subscribedProcessor, schemaValidator, c.logger, c.metricsCollector).Start(ctx); err != nil {
 		c.logger.Errorf("Start handler failed with error: %s", err)
 		return err
 	}
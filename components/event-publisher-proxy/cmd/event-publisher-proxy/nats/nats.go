@@ -13,6 +13,7 @@ import (
 	pkgnats "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/nats"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/signals"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
@@ -70,7 +71,10 @@ func (c *Commander) Start() error {
 	defer bc.Connection.Close()
 
 	// configure message sender
-	messageSenderToNats := sender.NewNatsMessageSender(ctx, bc, c.logger)
+	var messageSenderToNats sender.GenericSender = sender.NewNatsMessageSender(ctx, bc, c.logger)
+	if c.envCfg.JetStreamEnabled {
+		messageSenderToNats = sender.NewJetStreamMessageSender(ctx, bc, c.envCfg.JSStreamName, c.logger)
+	}
 
 	// cluster config
 	k8sConfig := config.GetConfigOrDie()
@@ -86,6 +90,15 @@ func (c *Commander) Start() error {
 		applicationLister,
 	)
 
+	// configure schema validator
+	schemaValidationMode, err := schema.ParseMode(c.envCfg.SchemaValidationMode)
+	if err != nil {
+		c.logger.Errorf("Invalid schema validation configuration with error: %s", err)
+		return err
+	}
+	schemaRegistry := schema.NewRegistry(applicationLister, c.envCfg.ToConfig().EventTypePrefix, c.envCfg.SchemaCacheTTL, c.logger)
+	schemaValidator := schema.NewValidator(schemaValidationMode, schemaRegistry, c.metricsCollector, c.logger)
+
 	// configure Subscription Lister
 	subDynamicSharedInfFactory := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
 	subLister := subDynamicSharedInfFactory.ForResource(subscribed.GVR).Lister()
@@ -102,7 +115,7 @@ func (c *Commander) Start() error {
 
 	// start handler which blocks until it receives a shutdown signal
 	if err := nats.NewHandler(messageReceiver, messageSenderToNats, c.envCfg.RequestTimeout, legacyTransformer, c.opts,
-		subscribedProcessor, c.logger, c.metricsCollector).Start(ctx); err != nil {
+		subscribedProcessor, schemaValidator, c.logger, c.metricsCollector).Start(ctx); err != nil {
 		c.logger.Errorf("Start handler failed with error: %s", err)
 		return err
 	}
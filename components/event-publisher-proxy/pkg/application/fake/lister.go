@@ -12,9 +12,13 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application"
 )
 
-func NewListerOrDie(ctx context.Context, app *applicationv1alpha1.Application) *application.Lister {
+func NewListerOrDie(ctx context.Context, apps ...*applicationv1alpha1.Application) *application.Lister {
 	scheme := setupSchemeOrDie()
-	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme, app)
+	objects := make([]runtime.Object, 0, len(apps))
+	for _, app := range apps {
+		objects = append(objects, app)
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme, objects...)
 	return application.NewLister(ctx, dynamicClient)
 }
 
@@ -7,6 +7,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -36,7 +37,28 @@ func (l Lister) Get(name string) (*applicationv1alpha1.Application, error) {
 	if err != nil {
 		return nil, err
 	}
+	return toApplication(object)
+}
+
+func (l Lister) List() ([]*applicationv1alpha1.Application, error) {
+	objects, err := l.lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	applications := make([]*applicationv1alpha1.Application, 0, len(objects))
+	for _, object := range objects {
+		application, err := toApplication(object)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+
+	return applications, nil
+}
 
+func toApplication(object runtime.Object) (*applicationv1alpha1.Application, error) {
 	applicationUnstructured, ok := object.(*unstructured.Unstructured)
 	if !ok {
 		return nil, errors.New("failed to convert runtime object to unstructured")
@@ -24,6 +24,8 @@ type BebConfig struct {
 	// EventTypePrefix is the prefix of each event as per the eventing specification
 	// It follows the eventType format: <eventTypePrefix>.<appName>.<event-name>.<version>
 	EventTypePrefix string `envconfig:"EVENT_TYPE_PREFIX" default:""`
+
+	SchemaValidationConfig
 }
 
 // ConfigureTransport receives an HTTP transport and configure its max idle connection properties.
@@ -35,6 +37,8 @@ func (c *BebConfig) ConfigureTransport(transport *http.Transport) {
 // String implements the fmt.Stringer interface
 func (c *BebConfig) String() string {
 	return fmt.Sprintf("BebConfig{ Port: %v; TokenEndPoint: %v; EmsPublishURL: %v; "+
-		"MaxIdleConns: %v; MaxIdleConnsPerHost: %v; RequestTimeout: %v; BEBNamespace: %v; EventTypePrefix: %v }",
-		c.Port, c.TokenEndpoint, c.EmsPublishURL, c.MaxIdleConns, c.MaxIdleConnsPerHost, c.RequestTimeout, c.BEBNamespace, c.EventTypePrefix)
+		"MaxIdleConns: %v; MaxIdleConnsPerHost: %v; RequestTimeout: %v; BEBNamespace: %v; EventTypePrefix: %v; "+
+		"SchemaValidationMode: %v; SchemaCacheTTL: %v }",
+		c.Port, c.TokenEndpoint, c.EmsPublishURL, c.MaxIdleConns, c.MaxIdleConnsPerHost, c.RequestTimeout, c.BEBNamespace, c.EventTypePrefix,
+		c.SchemaValidationMode, c.SchemaCacheTTL)
 }
@@ -22,6 +22,13 @@ type NatsConfig struct {
 	// LegacyEventTypePrefix is the prefix of each event as per the eventing specification, used for legacy events
 	// It follows the eventType format: <LegacyEventTypePrefix>.<appName>.<event-name>.<version>
 	LegacyEventTypePrefix string `envconfig:"LEGACY_EVENT_TYPE_PREFIX" default:"kyma"`
+
+	// JetStreamEnabled publishes the events to the JetStream stream and waits for the server acknowledgement
+	JetStreamEnabled bool `envconfig:"ENABLE_JETSTREAM_BACKEND" default:"false"`
+	// JSStreamName is the name of the JetStream stream which persists the events
+	JSStreamName string `envconfig:"JS_STREAM_NAME" default:"kyma"`
+
+	SchemaValidationConfig
 }
 
 // ToConfig converts to a default BEB BebConfig
@@ -0,0 +1,12 @@
+package env
+
+import "time"
+
+// SchemaValidationConfig represents the environment config for validating the events against the schemas
+// of their event types which are registered by the applications.
+type SchemaValidationConfig struct {
+	// SchemaValidationMode is one of: disabled, warn or enforce
+	SchemaValidationMode string `envconfig:"SCHEMA_VALIDATION_MODE" default:"disabled"`
+	// SchemaCacheTTL is the duration the event schemas of an application are cached for
+	SchemaCacheTTL time.Duration `envconfig:"SCHEMA_CACHE_TTL" default:"5m"`
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cev2client "github.com/cloudevents/sdk-go/v2/client"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
+)
+
+// BatchSender sends a single event of a batch and returns the response status code, dispatch time and body.
+type BatchSender func(ctx context.Context, event *cev2event.Event) (int, time.Duration, []byte)
+
+// BatchEventResult is the publish result of a single event of a batch.
+type BatchEventResult struct {
+	ID       string `json:"id,omitempty"`
+	Status   int    `json:"status"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// BatchResponse is the response body of a batch publish request, the results are in the order of the batch events.
+type BatchResponse struct {
+	Accepted int                `json:"accepted"`
+	Rejected int                `json:"rejected"`
+	Results  []BatchEventResult `json:"results"`
+}
+
+// StatusCode returns http.StatusOK if all the batch events were accepted, otherwise http.StatusMultiStatus.
+func (r *BatchResponse) StatusCode() int {
+	if r.Rejected > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
+// IsABatchRequest returns true if the given request has the CE JSON batch content type.
+func IsABatchRequest(request *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get(cev2http.ContentType))
+	if err != nil {
+		return false
+	}
+	return mediaType == cev2event.ApplicationCloudEventsBatchJSON
+}
+
+// ReadBatch reads the events of a CE JSON batch request, the events are not parsed so that each one of them
+// can be rejected on its own. The request body is limited to maxRequestSize bytes and to maxEvents events,
+// a zero maxEvents does not limit the number of events.
+func ReadBatch(writer http.ResponseWriter, request *http.Request, maxRequestSize int64, maxEvents int) ([]json.RawMessage, error) {
+	decoder := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxRequestSize))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("failed to parse batch request body: not a JSON array")
+	}
+	var events []json.RawMessage
+	for decoder.More() {
+		if maxEvents > 0 && len(events) == maxEvents {
+			return nil, fmt.Errorf("batch request contains more than %d events", maxEvents)
+		}
+		var event json.RawMessage
+		if err := decoder.Decode(&event); err != nil {
+			return nil, errors.Wrap(err, "failed to parse batch request body")
+		}
+		events = append(events, event)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, errors.Wrap(err, "failed to parse batch request body")
+	}
+	if len(events) == 0 {
+		return nil, errors.New("batch request does not contain any event")
+	}
+	return events, nil
+}
+
+// PublishBatch sends the given batch events one by one using the given sender and returns the result of each one.
+// The events which are not valid as per the CE spec are rejected without being sent.
+func PublishBatch(ctx context.Context, events []json.RawMessage, send BatchSender) *BatchResponse {
+	response := &BatchResponse{Results: make([]BatchEventResult, 0, len(events))}
+	for _, data := range events {
+		result := publishBatchEvent(ctx, data, send)
+		if result.Accepted {
+			response.Accepted++
+		} else {
+			response.Rejected++
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response
+}
+
+func publishBatchEvent(ctx context.Context, data json.RawMessage, send BatchSender) BatchEventResult {
+	event := cev2event.New()
+	if err := json.Unmarshal(data, &event); err != nil {
+		return BatchEventResult{Status: http.StatusBadRequest, Reason: err.Error()}
+	}
+	if err := event.Validate(); err != nil {
+		return BatchEventResult{ID: event.ID(), Status: http.StatusBadRequest, Reason: err.Error()}
+	}
+
+	statusCode, _, respBody := send(ctx, &event)
+	result := BatchEventResult{ID: event.ID(), Status: statusCode}
+	if statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices {
+		result.Accepted = true
+		return result
+	}
+	result.Reason = string(respBody)
+	if len(result.Reason) == 0 {
+		result.Reason = http.StatusText(statusCode)
+	}
+	return result
+}
+
+// BatchPublisher publishes the events of CE JSON batch requests, it is shared by the BEB and the NATS handlers.
+type BatchPublisher struct {
+	// Send dispatches a single event to the messaging server
+	Send BatchSender
+	// Prepare adds the context of the batch request to each of its events, it is optional
+	Prepare func(request *http.Request, event *cev2event.Event)
+	// Defaulter sets default values to the batch events
+	Defaulter cev2client.EventDefaulter
+	// SchemaValidator validates the events against the schemas of their event types, nil if it is disabled
+	SchemaValidator *schema.Validator
+	// RequestTimeout timeout for the dispatch of each event
+	RequestTimeout time.Duration
+	// MaxRequestSize limits the size of the batch request body in bytes
+	MaxRequestSize int64
+	// MaxEvents limits the number of events per batch request, zero does not limit it
+	MaxEvents int
+	Logger    *logrus.Logger
+	Collector *metrics.Collector
+}
+
+// Publish sends the events of the batch request one by one and writes back the result of each event.
+func (p *BatchPublisher) Publish(writer http.ResponseWriter, request *http.Request) {
+	events, err := ReadBatch(writer, request, p.MaxRequestSize, p.MaxEvents)
+	if err != nil {
+		p.Logger.Warnf("Failed to read events from batch request with error: %s", err)
+		p.writeResponse(writer, http.StatusBadRequest, []byte(err.Error()))
+		return
+	}
+	p.Collector.RecordBatchSize(len(events))
+
+	send := func(ctx context.Context, event *cev2event.Event) (int, time.Duration, []byte) {
+		if p.Prepare != nil {
+			p.Prepare(request, event)
+		}
+		return p.sendEvent(ctx, event)
+	}
+	ctx := binding.WithForceBinary(request.Context())
+	response := PublishBatch(ctx, events, send)
+	p.Collector.RecordBatchEvents(response.Accepted, response.Rejected)
+
+	respBody, err := json.Marshal(response)
+	if err != nil {
+		p.Logger.Errorf("Failed to marshal batch response with error: %s", err)
+		p.writeResponse(writer, http.StatusInternalServerError, []byte(err.Error()))
+		return
+	}
+	writer.Header().Set(cev2http.ContentType, "application/json")
+	p.writeResponse(writer, response.StatusCode(), respBody)
+
+	p.Logger.WithFields(
+		logrus.Fields{
+			"size":     len(events),
+			"accepted": response.Accepted,
+			"rejected": response.Rejected,
+		}).Info("Batch dispatched")
+}
+
+// sendEvent dispatches a single event of a batch, each event has its own request timeout.
+func (p *BatchPublisher) sendEvent(ctx context.Context, event *cev2event.Event) (int, time.Duration, []byte) {
+	ctx, cancel := context.WithTimeout(ctx, p.RequestTimeout)
+	defer cancel()
+
+	if validationErr := p.SchemaValidator.Validate(ctx, event); validationErr != nil {
+		p.Logger.Warnf("Event id:[%s] is invalid as per the event type schema with error: %s", event.ID(), validationErr)
+		return http.StatusBadRequest, 0, []byte(validationErr.Error())
+	}
+
+	if p.Defaulter != nil {
+		*event = p.Defaulter(ctx, *event)
+	}
+	p.Logger.Infof("Event received id:[%s]", event.ID())
+
+	statusCode, dispatchTime, respBody := p.Send(ctx, event)
+
+	p.Logger.WithFields(
+		logrus.Fields{
+			"id":           event.ID(),
+			"source":       event.Source(),
+			"type":         event.Type(),
+			"statusCode":   statusCode,
+			"duration":     dispatchTime,
+			"responseBody": string(respBody),
+		}).Info("Event dispatched")
+	return statusCode, dispatchTime, respBody
+}
+
+func (p *BatchPublisher) writeResponse(writer http.ResponseWriter, statusCode int, respBody []byte) {
+	writer.WriteHeader(statusCode)
+
+	if respBody == nil {
+		return
+	}
+	if _, err := writer.Write(respBody); err != nil {
+		p.Logger.Errorf("Failed to write response body with error: %s", err)
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+
+	testingutils "github.com/kyma-project/kyma/components/event-publisher-proxy/testing"
+)
+
+func TestIsABatchRequest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		want        bool
+	}{
+		{name: "batch content type", contentType: "application/cloudevents-batch+json", want: true},
+		{name: "batch content type with charset", contentType: "application/cloudevents-batch+json; charset=utf-8", want: true},
+		{name: "structured content type", contentType: "application/cloudevents+json", want: false},
+		{name: "no content type", contentType: "", want: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, PublishEndpoint, nil)
+			if err != nil {
+				t.Fatalf("failed to create request with error: %v", err)
+			}
+			request.Header.Set("Content-Type", tc.contentType)
+			assert.Equal(t, tc.want, IsABatchRequest(request))
+		})
+	}
+}
+
+func TestReadBatch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		maxRequestSize int64
+		maxEvents      int
+		wantEvents     int
+		wantErr        string
+	}{
+		{name: "batch within the limits", body: `[{"id":"1"},{"id":"2"}]`, maxRequestSize: 1024, maxEvents: 2, wantEvents: 2},
+		{name: "batch without event count limit", body: `[{"id":"1"},{"id":"2"}]`, maxRequestSize: 1024, wantEvents: 2},
+		{name: "too many events", body: `[{"id":"1"},{"id":"2"},{"id":"3"}]`, maxRequestSize: 1024, maxEvents: 2, wantErr: "more than 2 events"},
+		{name: "too large body", body: `[{"id":"` + strings.Repeat("1", 100) + `"}]`, maxRequestSize: 64, maxEvents: 2, wantErr: "too large"},
+		{name: "empty batch", body: `[]`, maxRequestSize: 1024, maxEvents: 2, wantErr: "does not contain any event"},
+		{name: "not an array", body: `{"id":"1"}`, maxRequestSize: 1024, maxEvents: 2, wantErr: "not a JSON array"},
+		{name: "truncated array", body: `[{"id":"1"}`, maxRequestSize: 1024, maxEvents: 2, wantErr: "failed to parse"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, PublishEndpoint, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatalf("failed to create request with error: %v", err)
+			}
+			events, err := ReadBatch(httptest.NewRecorder(), request, tc.maxRequestSize, tc.maxEvents)
+			if tc.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, events, tc.wantEvents)
+		})
+	}
+}
+
+func TestPublishBatch(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, PublishEndpoint, bytes.NewBufferString(testingutils.BatchCloudEventPayloadWithInvalidEvent))
+	if err != nil {
+		t.Fatalf("failed to create request with error: %v", err)
+	}
+	events, err := ReadBatch(httptest.NewRecorder(), request, 65536, 10)
+	if err != nil {
+		t.Fatalf("failed to read batch with error: %v", err)
+	}
+	assert.Len(t, events, 2)
+
+	// the sender rejects the events after the first one
+	sent := 0
+	send := func(ctx context.Context, event *cev2event.Event) (int, time.Duration, []byte) {
+		sent++
+		if sent > 1 {
+			return http.StatusBadGateway, time.Millisecond, []byte("nats: connection closed")
+		}
+		return http.StatusNoContent, time.Millisecond, nil
+	}
+
+	// the invalid event is rejected without being sent
+	response := PublishBatch(context.Background(), events, send)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 1, response.Accepted)
+	assert.Equal(t, 1, response.Rejected)
+	assert.Equal(t, http.StatusMultiStatus, response.StatusCode())
+	assert.Equal(t, BatchEventResult{ID: testingutils.EventID, Status: http.StatusNoContent, Accepted: true}, response.Results[0])
+	assert.Equal(t, testingutils.EventID, response.Results[1].ID)
+	assert.Equal(t, http.StatusBadRequest, response.Results[1].Status)
+	assert.Contains(t, response.Results[1].Reason, "source")
+
+	// the rejection reason of the sender is returned
+	response = PublishBatch(context.Background(), events[:1], send)
+	assert.Equal(t, http.StatusMultiStatus, response.StatusCode())
+	assert.Equal(t, BatchEventResult{ID: testingutils.EventID, Status: http.StatusBadGateway, Reason: "nats: connection closed"}, response.Results[0])
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
 )
@@ -58,6 +59,8 @@ type Handler struct {
 	Logger *logrus.Logger
 	// Options configures HTTP server
 	Options *options.Options
+	// SchemaValidator validates the events against the schemas of their event types, nil if it is disabled
+	SchemaValidator *schema.Validator
 	// collector collects metrics
 	collector *metrics.Collector
 }
@@ -65,13 +68,14 @@ type Handler struct {
 // NewHandler returns a new HTTP Handler instance.
 func NewHandler(receiver *receiver.HttpMessageReceiver, sender *sender.BebMessageSender, requestTimeout time.Duration,
 	legacyTransformer *legacy.Transformer, opts *options.Options, subscribedProcessor *subscribed.Processor,
-	logger *logrus.Logger, collector *metrics.Collector) *Handler {
+	schemaValidator *schema.Validator, logger *logrus.Logger, collector *metrics.Collector) *Handler {
 	return &Handler{
 		Receiver:            receiver,
 		Sender:              sender,
 		RequestTimeout:      requestTimeout,
 		LegacyTransformer:   legacyTransformer,
 		SubscribedProcessor: subscribedProcessor,
+		SchemaValidator:     schemaValidator,
 		Logger:              logger,
 		Options:             opts,
 		collector:           collector,
@@ -100,6 +104,10 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	// Process /publish endpoint
 	// Gets a CE and sends it to BEB
 	if handler.IsARequestWithCE(uri) {
+		if handler.IsABatchRequest(request) {
+			h.batchPublisher().Publish(writer, request)
+			return
+		}
 		h.publishCloudEvents(writer, request)
 		return
 	}
@@ -131,6 +139,13 @@ func (h *Handler) publishLegacyEventsAsCE(writer http.ResponseWriter, request *h
 
 	ctx, cancel := context.WithTimeout(request.Context(), h.RequestTimeout)
 	defer cancel()
+
+	if validationErr := h.SchemaValidator.Validate(ctx, event); validationErr != nil {
+		h.Logger.Warnf("Request is invalid as per the event type schema with error: %s", validationErr)
+		h.LegacyTransformer.TransformsSchemaViolationToLegacyResponse(writer, validationErr)
+		return
+	}
+
 	h.receive(ctx, event)
 	statusCode, dispatchTime, respBody := h.send(ctx, event)
 	// Change response as per old error codes
@@ -167,6 +182,13 @@ func (h *Handler) publishCloudEvents(writer http.ResponseWriter, request *http.R
 		return
 	}
 
+	if validationErr := h.SchemaValidator.Validate(ctx, event); validationErr != nil {
+		h.Logger.Warnf("Request is invalid as per the event type schema with error: %s", validationErr)
+		writer.Header().Set(cev2http.ContentType, "application/json")
+		h.writeResponse(writer, http.StatusBadRequest, handler.SchemaViolationResponseBody(validationErr))
+		return
+	}
+
 	if request.Header.Get(cev2http.ContentType) == cev2event.ApplicationCloudEventsJSON {
 		ctx = binding.WithForceStructured(ctx)
 	} else {
@@ -188,6 +210,20 @@ func (h *Handler) publishCloudEvents(writer http.ResponseWriter, request *http.R
 		}).Info("Event dispatched")
 }
 
+// batchPublisher returns the publisher of the CE JSON batch requests, the events are sent by the handler one by one.
+func (h *Handler) batchPublisher() *handler.BatchPublisher {
+	return &handler.BatchPublisher{
+		Send:            h.send,
+		Defaulter:       h.Defaulter,
+		SchemaValidator: h.SchemaValidator,
+		RequestTimeout:  h.RequestTimeout,
+		MaxRequestSize:  h.Options.MaxRequestSize,
+		MaxEvents:       h.Options.MaxBatchEvents,
+		Logger:          h.Logger,
+		Collector:       h.collector,
+	}
+}
+
 // writeResponse writes the HTTP response given the status code and response body.
 func (h *Handler) writeResponse(writer http.ResponseWriter, statusCode int, respBody []byte) {
 	writer.WriteHeader(statusCode)
@@ -88,6 +88,8 @@ func TestHandlerForCloudEvents(t *testing.T) {
 				}
 			})
 		}
+		metricstest.EnsureMetricBatchSize(t, collector)
+		metricstest.EnsureMetricBatchEvents(t, collector)
 	}
 
 	// make sure not to change the cloudevent, even if its event-type contains none-alphanumeric characters
@@ -462,7 +464,7 @@ func setupTestResources(t *testing.T, port, maxRequestSize int, applicationName,
 	}
 
 	collector := metrics.NewCollector()
-	msgHandler := NewHandler(msgReceiver, msgSender, cfg.RequestTimeout, legacyTransformer, opts, subscribedProcessor, logrus.New(), collector)
+	msgHandler := NewHandler(msgReceiver, msgSender, cfg.RequestTimeout, legacyTransformer, opts, subscribedProcessor, nil, logrus.New(), collector)
 	go func() {
 		if err := msgHandler.Start(ctx); err != nil {
 			t.Errorf("failed to start handler with error: %v", err)
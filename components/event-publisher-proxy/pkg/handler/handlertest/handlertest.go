@@ -202,6 +202,35 @@ var (
 			},
 			WantStatusCode: http.StatusNoContent,
 		},
+		// batch cloudevents
+		{
+			Name: "Batch CloudEvents are valid",
+			ProvideMessage: func() (string, http.Header) {
+				return testingutils.BatchCloudEventPayload, testingutils.GetBatchMessageHeaders()
+			},
+			WantStatusCode: http.StatusOK,
+		},
+		{
+			Name: "Batch CloudEvents with an invalid event",
+			ProvideMessage: func() (string, http.Header) {
+				return testingutils.BatchCloudEventPayloadWithInvalidEvent, testingutils.GetBatchMessageHeaders()
+			},
+			WantStatusCode: http.StatusMultiStatus,
+		},
+		{
+			Name: "Batch CloudEvents without events",
+			ProvideMessage: func() (string, http.Header) {
+				return "[]", testingutils.GetBatchMessageHeaders()
+			},
+			WantStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name: "Batch CloudEvents which is not a list",
+			ProvideMessage: func() (string, http.Header) {
+				return testingutils.StructuredCloudEventPayload, testingutils.GetBatchMessageHeaders()
+			},
+			WantStatusCode: http.StatusBadRequest,
+		},
 	}
 
 	TestCasesForLegacyEvents = []struct {
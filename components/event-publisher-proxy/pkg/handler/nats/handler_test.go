@@ -17,7 +17,9 @@ import (
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
 	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
@@ -32,6 +34,7 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/tracing"
 	testingutils "github.com/kyma-project/kyma/components/event-publisher-proxy/testing"
 	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
 )
@@ -109,7 +112,7 @@ func (test *Test) setupResources(t *testing.T, subscription *eventingv1alpha1.Su
 
 	// start handler which blocks until it receives a shutdown signal
 	opts := &options.Options{MaxRequestSize: 65536}
-	natsHandler := NewHandler(messageReceiver, msgSender, test.natsConfig.RequestTimeout, legacyTransformer, opts, subscribedProcessor, test.logger, test.collector)
+	natsHandler := NewHandler(messageReceiver, msgSender, test.natsConfig.RequestTimeout, legacyTransformer, opts, subscribedProcessor, nil, test.logger, test.collector)
 	assert.NotNil(t, natsHandler)
 	go func() {
 		if err := natsHandler.Start(ctx); err != nil {
@@ -178,6 +181,8 @@ func TestNatsHandlerForCloudEvents(t *testing.T) {
 				}
 			})
 		}
+		metricstest.EnsureMetricBatchSize(t, test.collector)
+		metricstest.EnsureMetricBatchEvents(t, test.collector)
 	}
 
 	// make sure not to change the cloudevent, even if its event-type contains none-alphanumeric characters
@@ -241,6 +246,56 @@ func TestNatsHandlerForLegacyEvents(t *testing.T) {
 	exec(t, testingutils.ApplicationNameNotClean, testingutils.CloudEventType)
 }
 
+func TestNatsHandlerForTracePropagation(t *testing.T) {
+	test.logger.Info("TestNatsHandlerForTracePropagation started")
+
+	// setup test environment
+	publishEndpoint := fmt.Sprintf("http://localhost:%d/publish", test.natsConfig.Port)
+	subscription := testingutils.NewSubscription(testingutils.SubscriptionWithFilter(testingutils.MessagingNamespace, testingutils.CloudEventTypeNotClean))
+	cancel := test.setupResources(t, subscription, testingutils.ApplicationName)
+	defer cancel()
+
+	// connect to nats
+	bc := pkgnats.NewBackendConnection(test.natsUrl, true, 3, time.Second)
+	err := bc.Connect()
+	assert.Nil(t, err)
+	assert.NotNil(t, bc.Connection)
+
+	// receive the events published to NATS, the event type is not used by the other tests which are still subscribed
+	eventType := "sap.kyma.custom.tracing.order.created.v1"
+	events := make(chan cev2event.Event, 1)
+	testingutils.SubscribeToEventOrFail(t, bc.Connection, eventType, func(msg *nats.Msg) {
+		event := cev2event.New(cev2event.CloudEventsVersionV1)
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			t.Errorf("failed to unmarshal message with error: %v", err)
+		}
+		events <- event
+	})
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	headers := testingutils.GetBinaryMessageHeaders()
+	headers.Set(testingutils.CeTypeHeader, eventType)
+	headers.Add("Content-Type", "application/json")
+	headers.Add("traceparent", traceParent)
+	resp, err := testingutils.SendEvent(publishEndpoint, `{"key":"value"}`, headers)
+	if err != nil {
+		t.Fatalf("Failed to send event with error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if !testingutils.Is2XX(resp.StatusCode) {
+		t.Fatalf("Test failed, want status code 2XX but got:%d", resp.StatusCode)
+	}
+
+	// the trace context of the request is carried by the event across NATS
+	select {
+	case event := <-events:
+		assert.Equal(t, traceParent, event.Extensions()["traceparent"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", event.Extensions()[tracing.B3Extension])
+	case <-time.After(5 * time.Second):
+		t.Fatal("Test failed, the event was not published to NATS")
+	}
+}
+
 func TestNatsHandlerForSubscribedEndpoint(t *testing.T) {
 	test.logger.Info("TestNatsHandlerForSubscribedEndpoint started")
 
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
+)
+
+// SchemaViolationResponseBody returns the JSON response body for an event whose data does not match the schema
+// of its event type.
+func SchemaViolationResponseBody(err *schema.ValidationError) []byte {
+	body, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		return []byte(err.Error())
+	}
+	return body
+}
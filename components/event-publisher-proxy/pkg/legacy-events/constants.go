@@ -12,6 +12,7 @@ const (
 	ErrorMessageRequestBodyTooLarge = "Request body too large"
 	ErrorMessageMissingField        = "Missing field"
 	ErrorMessageInvalidField        = "Invalid field"
+	ErrorMessageSchemaViolation     = "Event data does not match the event type schema"
 )
 
 // Error type definition
@@ -21,6 +22,7 @@ const (
 	ErrorTypeMissingField        = "missing_field"
 	ErrorTypeValidationViolation = "validation_violation"
 	ErrorTypeInvalidField        = "invalid_field"
+	ErrorTypeSchemaViolation     = "schema_violation"
 )
 
 // Field definition
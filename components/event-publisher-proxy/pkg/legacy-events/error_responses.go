@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy-events/api"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
 )
 
 // An ErrorResponse represents an error with a status code and an error message
@@ -61,6 +62,20 @@ func ErrorResponseMissingFieldData() (response *api.PublishEventResponses) {
 	return CreateMissingFieldError(FieldData)
 }
 
+// ErrorResponseSchemaViolation returns an error of type PublishEventResponses for event data which does not match the schema of the event type
+func ErrorResponseSchemaViolation(err *schema.ValidationError) (response *api.PublishEventResponses) {
+	details := make([]api.ErrorDetail, 0, len(err.Violations))
+	for _, violation := range err.Violations {
+		field := FieldData
+		if len(violation.Field) > 0 {
+			field = FieldData + "." + violation.Field
+		}
+		details = append(details, api.ErrorDetail{Field: field, Type: ErrorTypeSchemaViolation, Message: violation.Message})
+	}
+	apiError := api.Error{Status: http.StatusBadRequest, Type: ErrorTypeValidationViolation, Message: ErrorMessageSchemaViolation, MoreInfo: err.EventType, Details: details}
+	return &api.PublishEventResponses{Ok: nil, Error: &apiError}
+}
+
 // ErrorResponse returns an error of type PublishEventResponses with the given status and error
 func ErrorResponse(status int, err error) *api.PublishEventResponses {
 	return &api.PublishEventResponses{Error: &api.Error{Status: status, Message: err.Error()}}
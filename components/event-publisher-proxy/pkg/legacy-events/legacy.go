@@ -14,6 +14,7 @@ import (
 
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application"
 	apiv1 "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy-events/api"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
 )
 
 var (
@@ -144,6 +145,12 @@ func (t Transformer) TransformsCEResponseToLegacyResponse(writer http.ResponseWr
 	return
 }
 
+// TransformsSchemaViolationToLegacyResponse writes the legacy error response for an event whose data does not match
+// the schema of its event type.
+func (t Transformer) TransformsSchemaViolationToLegacyResponse(writer http.ResponseWriter, err *schema.ValidationError) {
+	writeJSONResponse(writer, ErrorResponseSchemaViolation(err))
+}
+
 // convertPublishRequestToCloudEvent converts the given publish request to a CloudEvent.
 func (t Transformer) convertPublishRequestToCloudEvent(appName string, publishRequest *apiv1.PublishEventParametersV1) (*cev2event.Event, error) {
 	if !application.IsCleanName(appName) {
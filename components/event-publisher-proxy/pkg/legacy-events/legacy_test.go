@@ -1,10 +1,15 @@
 package legacy
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	legacyapi "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy-events/api"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/schema"
 	. "github.com/kyma-project/kyma/components/event-publisher-proxy/testing"
 )
 
@@ -94,3 +99,36 @@ func TestCombineEventTypeSegments(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformsSchemaViolationToLegacyResponse(t *testing.T) {
+	legacyTransformer := NewTransformer(MessagingNamespace, MessagingEventTypePrefix, nil)
+	validationErr := &schema.ValidationError{
+		EventType:  CloudEventType,
+		Message:    "event data does not match the schema of the event type",
+		Violations: []schema.Violation{{Message: "orderCode is required"}, {Field: "amount", Message: "Invalid type"}},
+	}
+
+	writer := httptest.NewRecorder()
+	legacyTransformer.TransformsSchemaViolationToLegacyResponse(writer, validationErr)
+	if writer.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status code, want: %d, got: %d", http.StatusBadRequest, writer.Code)
+	}
+
+	gotError := &legacyapi.Error{}
+	if err := json.NewDecoder(writer.Body).Decode(gotError); err != nil {
+		t.Fatalf("failed to decode response body with error: %v", err)
+	}
+	wantError := &legacyapi.Error{
+		Status:   http.StatusBadRequest,
+		Type:     ErrorTypeValidationViolation,
+		Message:  ErrorMessageSchemaViolation,
+		MoreInfo: CloudEventType,
+		Details: []legacyapi.ErrorDetail{
+			{Field: FieldData, Type: ErrorTypeSchemaViolation, Message: "orderCode is required"},
+			{Field: FieldData + ".amount", Type: ErrorTypeSchemaViolation, Message: "Invalid type"},
+		},
+	}
+	if !reflect.DeepEqual(wantError, gotError) {
+		t.Errorf("invalid response body, want: %+v, got: %+v", wantError, gotError)
+	}
+}
@@ -11,16 +11,33 @@ const (
 	Errors = "event_publish_to_messaging_server_errors_total"
 	// Latency name of the latency metric
 	Latency = "event_publish_to_messaging_server_latency"
+	// BatchSize name of the batch size metric
+	BatchSize = "event_publish_batch_size"
+	// BatchEvents name of the batch events metric
+	BatchEvents = "event_publish_batch_events_total"
+	// SchemaValidations name of the schema validations metric
+	SchemaValidations = "event_publish_schema_validations_total"
 	// errorsHelp help for the errors metric
 	errorsHelp = "The total number of errors while sending Events to the messaging server"
 	// latencyHelp help for the latency metric
 	latencyHelp = "The duration of sending Events to the messaging server"
+	// batchSizeHelp help for the batch size metric
+	batchSizeHelp = "The number of Events per batch publish request"
+	// batchEventsHelp help for the batch events metric
+	batchEventsHelp = "The total number of Events of batch publish requests which were accepted or rejected"
+	// schemaValidationsHelp help for the schema validations metric
+	schemaValidationsHelp = "The total number of Events validated against the schema of their event type"
 )
 
 // Collector implements the prometheus.Collector interface
 type Collector struct {
-	errors  *prometheus.CounterVec
-	latency *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	batchSize *prometheus.HistogramVec
+	// batchEvents is labeled with the publish result
+	batchEvents *prometheus.CounterVec
+	// schemaValidations is labeled with the event type and the validation result
+	schemaValidations *prometheus.CounterVec
 }
 
 // NewCollector a new instance of Collector
@@ -40,6 +57,28 @@ func NewCollector() *Collector {
 			},
 			[]string{},
 		),
+		batchSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    BatchSize,
+				Help:    batchSizeHelp,
+				Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+			},
+			[]string{},
+		),
+		batchEvents: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: BatchEvents,
+				Help: batchEventsHelp,
+			},
+			[]string{"result"},
+		),
+		schemaValidations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: SchemaValidations,
+				Help: schemaValidationsHelp,
+			},
+			[]string{"event_type", "result"},
+		),
 	}
 }
 
@@ -47,12 +86,18 @@ func NewCollector() *Collector {
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.errors.Describe(ch)
 	c.latency.Describe(ch)
+	c.batchSize.Describe(ch)
+	c.batchEvents.Describe(ch)
+	c.schemaValidations.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface Collect method
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.errors.Collect(ch)
 	c.latency.Collect(ch)
+	c.batchSize.Collect(ch)
+	c.batchEvents.Collect(ch)
+	c.schemaValidations.Collect(ch)
 }
 
 // RecordError records an error metric
@@ -64,3 +109,19 @@ func (c *Collector) RecordError() {
 func (c *Collector) RecordLatency(duration time.Duration) {
 	c.latency.WithLabelValues().Observe(duration.Seconds())
 }
+
+// RecordBatchSize records a batch size metric
+func (c *Collector) RecordBatchSize(size int) {
+	c.batchSize.WithLabelValues().Observe(float64(size))
+}
+
+// RecordBatchEvents records the number of accepted and rejected events of a batch
+func (c *Collector) RecordBatchEvents(accepted, rejected int) {
+	c.batchEvents.WithLabelValues("accepted").Add(float64(accepted))
+	c.batchEvents.WithLabelValues("rejected").Add(float64(rejected))
+}
+
+// RecordSchemaValidation records a schema validation metric
+func (c *Collector) RecordSchemaValidation(eventType, result string) {
+	c.schemaValidations.WithLabelValues(eventType, result).Inc()
+}
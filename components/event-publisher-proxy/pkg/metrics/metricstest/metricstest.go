@@ -18,6 +18,16 @@ func EnsureMetricLatency(t *testing.T, collector *metrics.Collector) {
 	ensureMetricCount(t, collector, metrics.Latency, 1)
 }
 
+// EnsureMetricBatchSize ensures metric batch size exists
+func EnsureMetricBatchSize(t *testing.T, collector *metrics.Collector) {
+	ensureMetricCount(t, collector, metrics.BatchSize, 1)
+}
+
+// EnsureMetricBatchEvents ensures metric batch events exists for the accepted and rejected events
+func EnsureMetricBatchEvents(t *testing.T, collector *metrics.Collector) {
+	ensureMetricCount(t, collector, metrics.BatchEvents, 2)
+}
+
 func ensureMetricCount(t *testing.T, collector *metrics.Collector, metric string, expectedCount int) {
 	if count := testutil.CollectAndCount(collector, metric); count != expectedCount {
 		t.Fatalf("invalid count for metric:%s, want:%d, got:%d", metric, expectedCount, count)
@@ -4,17 +4,20 @@ import "flag"
 
 type Options struct {
 	MaxRequestSize int64
+	MaxBatchEvents int
 	MetricsAddress string
 }
 
 func ParseArgs() *Options {
 	maxRequestSize := flag.Int64("max-request-size", 65536, "The maximum request size in bytes.")
+	maxBatchEvents := flag.Int("max-batch-events", 100, "The maximum number of events in a batch request.")
 	metricsAddress := flag.String("metrics-addr", ":9090", "The address the metric endpoint binds to.")
 
 	flag.Parse()
 
 	return &Options{
 		MaxRequestSize: *maxRequestSize,
+		MaxBatchEvents: *maxBatchEvents,
 		MetricsAddress: *metricsAddress,
 	}
 }
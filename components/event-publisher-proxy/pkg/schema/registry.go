@@ -0,0 +1,290 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/sync/singleflight"
+	"sigs.k8s.io/yaml"
+
+	applicationv1alpha1 "github.com/kyma-project/kyma/components/application-operator/pkg/apis/applicationconnector/v1alpha1"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application"
+)
+
+const (
+	// eventsEntryType is the type of the application service entries which declare events
+	eventsEntryType = "Events"
+	// specRequestTimeout is the timeout for fetching the API spec of an application service entry
+	specRequestTimeout = 10 * time.Second
+	// catalogLoadTimeout is the timeout for fetching all the API specs of an application
+	catalogLoadTimeout = 30 * time.Second
+	// eventTypeSegments is the number of segments of an event type without its prefix in the form of:
+	// <app>.<businessObject>.<operation>.<version>
+	eventTypeSegments = 4
+)
+
+// Registry resolves the JSON schemas of the event types from the AsyncAPI specs registered by the applications.
+// The schemas of an application are cached for the configured TTL.
+type Registry struct {
+	applicationLister *application.Lister
+	client            *http.Client
+	eventTypePrefix   string
+	ttl               time.Duration
+	logger            *logrus.Logger
+
+	// mutex guards the catalogs, it is not held while a catalog is loaded
+	mutex    sync.Mutex
+	catalogs map[string]*catalog
+	// loads makes sure the specs of an application are fetched once at a time, independent of the requests
+	// which wait for them
+	loads singleflight.Group
+}
+
+// catalog holds the schemas of the events of an application by event name and version.
+type catalog struct {
+	schemas map[string]*gojsonschema.Schema
+	err     error
+	expiry  time.Time
+}
+
+// NewRegistry returns a new Registry instance.
+func NewRegistry(applicationLister *application.Lister, eventTypePrefix string, ttl time.Duration, logger *logrus.Logger) *Registry {
+	return &Registry{
+		applicationLister: applicationLister,
+		client:            &http.Client{Timeout: specRequestTimeout},
+		eventTypePrefix:   eventTypePrefix,
+		ttl:               ttl,
+		logger:            logger,
+		catalogs:          make(map[string]*catalog),
+	}
+}
+
+// Get returns the JSON schema of the given event type, it returns nil if the application of the event type
+// did not register a schema for it.
+func (r *Registry) Get(ctx context.Context, eventType string) (*gojsonschema.Schema, error) {
+	appName, eventKey, ok := r.parseEventType(eventType)
+	if !ok {
+		return nil, nil
+	}
+
+	c := r.getCatalog(appName)
+	if c == nil {
+		result := r.loads.DoChan(appName, func() (interface{}, error) {
+			return r.loadCatalog(appName), nil
+		})
+		select {
+		case res := <-result:
+			c = res.Val.(*catalog)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.schemas[eventKey], nil
+}
+
+// getCatalog returns the cached catalog of the given application, nil if it is not cached or expired.
+func (r *Registry) getCatalog(appName string) *catalog {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c, ok := r.catalogs[appName]
+	if !ok || time.Now().After(c.expiry) {
+		return nil
+	}
+	return c
+}
+
+// parseEventType returns the application name and the event name and version of the given event type which is in
+// the form of: <eventTypePrefix>.<app>.<businessObject>.<operation>.<version>
+func (r *Registry) parseEventType(eventType string) (string, string, bool) {
+	if len(r.eventTypePrefix) > 0 {
+		if !strings.HasPrefix(eventType, r.eventTypePrefix+".") {
+			return "", "", false
+		}
+		eventType = strings.TrimPrefix(eventType, r.eventTypePrefix+".")
+	}
+	segments := strings.Split(eventType, ".")
+	if len(segments) != eventTypeSegments {
+		return "", "", false
+	}
+	return segments[0], strings.Join(segments[1:], "."), true
+}
+
+// loadCatalog fetches the specs of the events registered by the application with the given clean name and caches
+// them. The catalogs which failed due to the load timeout are not cached, so that the next request retries them.
+func (r *Registry) loadCatalog(appName string) *catalog {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogLoadTimeout)
+	defer cancel()
+
+	c := r.fetchCatalog(ctx, appName)
+	if errors.Is(c.err, context.DeadlineExceeded) {
+		return c
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.catalogs[appName] = c
+	return c
+}
+
+// fetchCatalog fetches the specs of the events registered by the application with the given clean name.
+func (r *Registry) fetchCatalog(ctx context.Context, appName string) *catalog {
+	c := &catalog{schemas: make(map[string]*gojsonschema.Schema), expiry: time.Now().Add(r.ttl)}
+
+	app, err := r.getApplication(appName)
+	if err != nil {
+		c.err = errors.Wrapf(err, "failed to get application %s", appName)
+		return c
+	}
+	if app == nil {
+		return c
+	}
+
+	for _, service := range app.Spec.Services {
+		for _, entry := range service.Entries {
+			if entry.Type != eventsEntryType || len(entry.SpecificationUrl) == 0 {
+				continue
+			}
+			schemas, err := r.fetchSchemas(ctx, entry.SpecificationUrl)
+			if err != nil {
+				c.err = errors.Wrapf(err, "failed to load the events spec of application %s service %s", appName, service.Name)
+				return c
+			}
+			for key, schema := range schemas {
+				c.schemas[key] = schema
+			}
+		}
+	}
+
+	r.logger.WithFields(logrus.Fields{"application": appName, "schemas": len(c.schemas)}).Info("Event schemas loaded")
+	return c
+}
+
+// getApplication returns the application whose clean type or name matches the given name, nil if there is none.
+func (r *Registry) getApplication(name string) (*applicationv1alpha1.Application, error) {
+	applications, err := r.applicationLister.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range applications {
+		if application.GetCleanTypeOrName(app) == name {
+			return app, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *Registry) fetchSchemas(ctx context.Context, url string) (map[string]*gojsonschema.Schema, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status code %d for %s", response.StatusCode, url)
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseSpec(data)
+}
+
+// parseSpec returns the JSON schemas of the event payloads declared in the given AsyncAPI spec in JSON or YAML format
+// by event name and version. Both the AsyncAPI 1.x topics and the AsyncAPI 2.x channels are supported.
+func parseSpec(data []byte) (map[string]*gojsonschema.Schema, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse AsyncAPI spec")
+	}
+	spec := make(map[string]interface{})
+	if err := json.Unmarshal(jsonData, &spec); err != nil {
+		return nil, errors.Wrap(err, "failed to parse AsyncAPI spec")
+	}
+
+	payloads := make(map[string]map[string]interface{})
+	for name, channel := range asMap(spec["channels"]) {
+		if payload := lookupPayload(channel, "message", "payload"); payload != nil {
+			payloads[name] = payload
+		}
+	}
+	for name, topic := range asMap(spec["topics"]) {
+		if payload := lookupPayload(topic, "payload"); payload != nil {
+			payloads[name] = payload
+		}
+	}
+
+	schemas := make(map[string]*gojsonschema.Schema, len(payloads))
+	for name, payload := range payloads {
+		key, ok := eventKey(name)
+		if !ok {
+			continue
+		}
+		// keep the spec components next to the payload so that its local references can be resolved
+		document := make(map[string]interface{}, len(payload)+1)
+		for k, v := range payload {
+			document[k] = v
+		}
+		if components, ok := spec["components"]; ok {
+			if _, ok := document["components"]; !ok {
+				document["components"] = components
+			}
+		}
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(document))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid payload schema of event %s", name)
+		}
+		schemas[key] = schema
+	}
+	return schemas, nil
+}
+
+// lookupPayload returns the payload schema of the given channel or topic which is declared by either its subscribe or
+// publish operation at the given path.
+func lookupPayload(channel interface{}, path ...string) map[string]interface{} {
+	for _, operation := range []string{"subscribe", "publish"} {
+		value := asMap(channel)[operation]
+		for _, key := range path {
+			value = asMap(value)[key]
+		}
+		if payload := asMap(value); payload != nil {
+			return payload
+		}
+	}
+	return nil
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+// eventKey returns the event name and version of the given AsyncAPI channel or topic name in the same form as the
+// event types, e.g. "Account.Order.Created.v1" becomes "AccountOrder.Created.v1".
+func eventKey(name string) (string, bool) {
+	segments := strings.Split(name, ".")
+	if len(segments) < 3 {
+		return "", false
+	}
+	version := segments[len(segments)-1]
+	businessObject := strings.Join(segments[:len(segments)-2], "")
+	operation := segments[len(segments)-2]
+	return fmt.Sprintf("%s.%s.%s", businessObject, operation, version), true
+}
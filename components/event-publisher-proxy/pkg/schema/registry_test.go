@@ -0,0 +1,247 @@
+package schema
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	applicationv1alpha1 "github.com/kyma-project/kyma/components/application-operator/pkg/apis/applicationconnector/v1alpha1"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application/applicationtest"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application/fake"
+)
+
+const (
+	eventTypePrefix = "sap.kyma.custom"
+
+	// asyncAPIv2Spec declares the order created event with a payload which references the spec components
+	asyncAPIv2Spec = `{
+  "asyncapi": "2.0.0",
+  "info": {"title": "orders", "version": "1.0.0"},
+  "channels": {
+    "order.created.v1": {
+      "subscribe": {
+        "message": {
+          "payload": {"$ref": "#/components/schemas/Order"}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Order": {
+        "type": "object",
+        "required": ["orderCode"],
+        "properties": {
+          "orderCode": {"type": "string"},
+          "amount": {"type": "number", "minimum": 0}
+        }
+      }
+    }
+  }
+}`
+
+	// asyncAPIv1Spec declares the customer created event with a multi-segment business object in YAML format
+	asyncAPIv1Spec = `
+asyncapi: "1.0.0"
+info:
+  title: customers
+  version: "1.0.0"
+topics:
+  account.customer.created.v1:
+    subscribe:
+      payload:
+        type: object
+        required:
+          - customerId
+        properties:
+          customerId:
+            type: string
+`
+)
+
+func TestParseSpec(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{name: "AsyncAPI 2.x channels", spec: asyncAPIv2Spec, wantKeys: []string{"order.created.v1"}},
+		{name: "AsyncAPI 1.x topics", spec: asyncAPIv1Spec, wantKeys: []string{"accountcustomer.created.v1"}},
+		{name: "spec without events", spec: `{"asyncapi": "2.0.0", "channels": {}}`, wantKeys: []string{}},
+		{name: "invalid spec", spec: `[`, wantErr: true},
+		{name: "invalid payload schema", spec: `{"channels": {"order.created.v1": {"publish": {"message": {"payload": {"type": 1}}}}}}`, wantErr: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			schemas, err := parseSpec([]byte(tc.spec))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			keys := make([]string, 0, len(schemas))
+			for key := range schemas {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tc.wantKeys, keys)
+		})
+	}
+}
+
+func TestRegistryGet(t *testing.T) {
+	var requests int32
+	server := newSpecServer(t, &requests)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := NewRegistry(newApplicationLister(ctx, server.URL), eventTypePrefix, time.Minute, logrus.New())
+
+	testCases := []struct {
+		name       string
+		eventType  string
+		wantSchema bool
+		wantErr    bool
+	}{
+		{name: "event declared by the application", eventType: "sap.kyma.custom.commerce.order.created.v1", wantSchema: true},
+		{name: "event with multi-segment business object", eventType: "sap.kyma.custom.commerce.accountcustomer.created.v1", wantSchema: true},
+		{name: "event not declared by the application", eventType: "sap.kyma.custom.commerce.order.deleted.v1", wantSchema: false},
+		{name: "event of an unknown application", eventType: "sap.kyma.custom.unknown.order.created.v1", wantSchema: false},
+		{name: "event type with another prefix", eventType: "com.example.commerce.order.created.v1", wantSchema: false},
+		{name: "event type with missing segments", eventType: "sap.kyma.custom.order.created.v1", wantSchema: false},
+		{name: "event of an application with an unavailable spec", eventType: "sap.kyma.custom.broken.order.created.v1", wantErr: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			schema, err := registry.Get(ctx, tc.eventType)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantSchema, schema != nil)
+		})
+	}
+
+	// the specs of each application are fetched once while they are cached
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestRegistryCacheExpiry(t *testing.T) {
+	var requests int32
+	server := newSpecServer(t, &requests)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := NewRegistry(newApplicationLister(ctx, server.URL), eventTypePrefix, time.Millisecond, logrus.New())
+
+	for i := 0; i < 2; i++ {
+		schema, err := registry.Get(ctx, "sap.kyma.custom.commerce.order.created.v1")
+		assert.NoError(t, err)
+		assert.NotNil(t, schema)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// the specs are fetched again once the cache expired
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+}
+
+func TestRegistryGetCancelled(t *testing.T) {
+	var requests int32
+	server := newSpecServer(t, &requests)
+	defer server.Close()
+
+	// the specs are served once they are released
+	release := make(chan struct{})
+	gate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer gate.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := NewRegistry(newApplicationLister(ctx, gate.URL), eventTypePrefix, time.Minute, logrus.New())
+	eventType := "sap.kyma.custom.commerce.order.created.v1"
+
+	// the request which gives up waiting does not cancel the fetch
+	requestCtx, cancelRequest := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelRequest()
+	_, err := registry.Get(requestCtx, eventType)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the concurrent requests wait for the same fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			schema, err := registry.Get(ctx, eventType)
+			assert.NoError(t, err)
+			assert.NotNil(t, schema)
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+// newSpecServer serves the AsyncAPI specs and counts the requests.
+func newSpecServer(t *testing.T, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		var spec string
+		switch r.URL.Path {
+		case "/orders":
+			spec = asyncAPIv2Spec
+		case "/customers":
+			spec = asyncAPIv1Spec
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, err := w.Write([]byte(spec)); err != nil {
+			t.Errorf("failed to write spec with error: %v", err)
+		}
+	}))
+}
+
+// newApplicationLister returns a lister with the commerce application whose events are declared by the specs
+// served at the given URL and the broken application whose spec is not available.
+func newApplicationLister(ctx context.Context, specURL string) *application.Lister {
+	commerce := applicationtest.NewApplication("commerce-app", map[string]string{"application-type": "commerce"})
+	commerce.Spec.Services = []applicationv1alpha1.Service{
+		{
+			Name: "orders",
+			Entries: []applicationv1alpha1.Entry{
+				{Type: "API", TargetUrl: "http://commerce", SpecificationUrl: specURL + "/api"},
+				{Type: "Events", SpecificationUrl: specURL + "/orders"},
+			},
+		},
+		{
+			Name:    "customers",
+			Entries: []applicationv1alpha1.Entry{{Type: "Events", SpecificationUrl: specURL + "/customers"}},
+		},
+	}
+	broken := applicationtest.NewApplication("broken", nil)
+	broken.Spec.Services = []applicationv1alpha1.Service{
+		{
+			Name:    "orders",
+			Entries: []applicationv1alpha1.Entry{{Type: "Events", SpecificationUrl: specURL + "/missing"}},
+		},
+	}
+	return fake.NewListerOrDie(ctx, commerce, broken)
+}
@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
+)
+
+// Mode configures how the events are validated against the schemas of their event types.
+type Mode string
+
+const (
+	// ModeDisabled does not validate the events
+	ModeDisabled Mode = "disabled"
+	// ModeWarn validates the events and records the results, the events which do not match their schema are published
+	ModeWarn Mode = "warn"
+	// ModeEnforce validates the events and rejects the ones which do not match their schema
+	ModeEnforce Mode = "enforce"
+)
+
+// Results of the schema validation recorded in the metrics
+const (
+	ResultValid   = "valid"
+	ResultInvalid = "invalid"
+	ResultUnknown = "unknown"
+	ResultError   = "error"
+)
+
+const (
+	// violationMessage is the message of the errors returned for the events which do not match their schema
+	violationMessage = "event data does not match the schema of the event type"
+	// rootField is the field used by the JSON schema validation for the violations of the whole event data
+	rootField = "(root)"
+	// unknownEventType is the metric label of the event types without a resolved schema, the event type is sent by the
+	// clients, so only the event types with a schema are recorded to keep the number of metric series bounded
+	unknownEventType = "unknown"
+)
+
+// ParseMode returns the Mode of the given value.
+func ParseMode(mode string) (Mode, error) {
+	switch m := Mode(strings.ToLower(mode)); m {
+	case ModeDisabled, ModeWarn, ModeEnforce:
+		return m, nil
+	case "":
+		return ModeDisabled, nil
+	default:
+		return "", fmt.Errorf("invalid schema validation mode %q, must be one of: %s, %s, %s", mode, ModeDisabled, ModeWarn, ModeEnforce)
+	}
+}
+
+// Violation is a mismatch between the event data and the schema of the event type.
+type Violation struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned for the events which do not match the schema of their event type.
+type ValidationError struct {
+	EventType  string      `json:"eventType"`
+	Message    string      `json:"message"`
+	Violations []Violation `json:"violations"`
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if len(v.Field) == 0 {
+			violations = append(violations, v.Message)
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	return fmt.Sprintf("%s %s: %s", e.Message, e.EventType, strings.Join(violations, "; "))
+}
+
+// Validator validates the data of the events against the schemas of their event types.
+type Validator struct {
+	mode      Mode
+	registry  *Registry
+	collector *metrics.Collector
+	logger    *logrus.Logger
+}
+
+// NewValidator returns a new Validator instance, it returns nil if the given mode is ModeDisabled.
+func NewValidator(mode Mode, registry *Registry, collector *metrics.Collector, logger *logrus.Logger) *Validator {
+	if mode == ModeDisabled {
+		return nil
+	}
+	return &Validator{mode: mode, registry: registry, collector: collector, logger: logger}
+}
+
+// Validate validates the data of the given event against the schema of its event type. It returns a ValidationError
+// only if the event does not match its schema and the validator is in ModeEnforce. The events are not validated if
+// the validator is nil or the schema of their event type cannot be resolved.
+func (v *Validator) Validate(ctx context.Context, event *cev2event.Event) *ValidationError {
+	if v == nil {
+		return nil
+	}
+
+	schema, err := v.registry.Get(ctx, event.Type())
+	if err != nil {
+		v.logger.Warnf("Failed to resolve the schema of event type %s with error: %s", event.Type(), err)
+		v.collector.RecordSchemaValidation(unknownEventType, ResultError)
+		return nil
+	}
+	if schema == nil {
+		v.collector.RecordSchemaValidation(unknownEventType, ResultUnknown)
+		return nil
+	}
+
+	violations, err := validateData(schema, event)
+	if err != nil {
+		v.logger.Warnf("Failed to validate event id:[%s] with error: %s", event.ID(), err)
+		v.collector.RecordSchemaValidation(event.Type(), ResultError)
+		return nil
+	}
+	if len(violations) == 0 {
+		v.collector.RecordSchemaValidation(event.Type(), ResultValid)
+		return nil
+	}
+
+	v.collector.RecordSchemaValidation(event.Type(), ResultInvalid)
+	validationErr := &ValidationError{EventType: event.Type(), Message: violationMessage, Violations: violations}
+	if v.mode != ModeEnforce {
+		v.logger.Warnf("Event id:[%s] is invalid: %s", event.ID(), validationErr)
+		return nil
+	}
+	return validationErr
+}
+
+func validateData(schema *gojsonschema.Schema, event *cev2event.Event) ([]Violation, error) {
+	if !isJSON(event.DataContentType()) {
+		return []Violation{{Message: fmt.Sprintf("data content type %s is not JSON", event.DataContentType())}}, nil
+	}
+
+	data := event.Data()
+	if len(data) == 0 {
+		data = []byte("null")
+	}
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]Violation, 0, len(result.Errors()))
+	for _, resultErr := range result.Errors() {
+		field := resultErr.Field()
+		if field == rootField {
+			field = ""
+		}
+		violations = append(violations, Violation{Field: field, Message: resultErr.Description()})
+	}
+	return violations, nil
+}
+
+// isJSON returns true if the given data content type is JSON, the data of the events without a content type is JSON.
+func isJSON(contentType string) bool {
+	if len(contentType) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
+)
+
+func TestParseMode(t *testing.T) {
+	testCases := []struct {
+		mode    string
+		want    Mode
+		wantErr bool
+	}{
+		{mode: "", want: ModeDisabled},
+		{mode: "disabled", want: ModeDisabled},
+		{mode: "warn", want: ModeWarn},
+		{mode: "Enforce", want: ModeEnforce},
+		{mode: "reject", wantErr: true},
+	}
+	for _, tc := range testCases {
+		mode, err := ParseMode(tc.mode)
+		if tc.wantErr {
+			assert.Error(t, err, tc.mode)
+			continue
+		}
+		assert.NoError(t, err, tc.mode)
+		assert.Equal(t, tc.want, mode, tc.mode)
+	}
+}
+
+func TestValidatorValidate(t *testing.T) {
+	var requests int32
+	server := newSpecServer(t, &requests)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := NewRegistry(newApplicationLister(ctx, server.URL), eventTypePrefix, time.Minute, logrus.New())
+
+	testCases := []struct {
+		name           string
+		eventType      string
+		contentType    string
+		data           string
+		wantViolations []Violation
+	}{
+		{
+			name:      "valid event",
+			eventType: "sap.kyma.custom.commerce.order.created.v1",
+			data:      `{"orderCode": "123", "amount": 10}`,
+		},
+		{
+			name:      "event with missing and invalid fields",
+			eventType: "sap.kyma.custom.commerce.order.created.v1",
+			data:      `{"amount": -1}`,
+			wantViolations: []Violation{
+				{Message: "orderCode is required"},
+				{Field: "amount", Message: "Must be greater than or equal to 0"},
+			},
+		},
+		{
+			name:           "event with non JSON data",
+			eventType:      "sap.kyma.custom.commerce.order.created.v1",
+			contentType:    "text/plain",
+			data:           `123`,
+			wantViolations: []Violation{{Message: "data content type text/plain is not JSON"}},
+		},
+		{
+			name:      "event without schema",
+			eventType: "sap.kyma.custom.commerce.order.deleted.v1",
+			data:      `{"amount": -1}`,
+		},
+		{
+			name:      "another event without schema",
+			eventType: "sap.kyma.custom.commerce.order.updated.v1",
+			data:      `{}`,
+		},
+		{
+			name:      "event whose schema cannot be resolved",
+			eventType: "sap.kyma.custom.broken.order.created.v1",
+			data:      `{"amount": -1}`,
+		},
+	}
+
+	for _, mode := range []Mode{ModeWarn, ModeEnforce} {
+		collector := metrics.NewCollector()
+		validator := NewValidator(mode, registry, collector, logrus.New())
+		for _, tc := range testCases {
+			event := newEvent(t, tc.eventType, tc.contentType, tc.data)
+			validationErr := validator.Validate(ctx, event)
+			if mode == ModeWarn || len(tc.wantViolations) == 0 {
+				assert.Nil(t, validationErr, "%s in mode %s", tc.name, mode)
+				continue
+			}
+			if assert.NotNil(t, validationErr, "%s in mode %s", tc.name, mode) {
+				assert.Equal(t, tc.eventType, validationErr.EventType)
+				assert.ElementsMatch(t, tc.wantViolations, validationErr.Violations, tc.name)
+			}
+		}
+		// the results are recorded in both modes by event type and result, the event types without schema share a series
+		assert.Equal(t, 4, testutil.CollectAndCount(collector, metrics.SchemaValidations), mode)
+	}
+}
+
+func TestDisabledValidator(t *testing.T) {
+	validator := NewValidator(ModeDisabled, nil, metrics.NewCollector(), logrus.New())
+	assert.Nil(t, validator)
+
+	event := newEvent(t, "sap.kyma.custom.commerce.order.created.v1", "", `{}`)
+	assert.Nil(t, validator.Validate(context.Background(), event))
+}
+
+func TestValidationErrorError(t *testing.T) {
+	err := &ValidationError{
+		EventType:  "sap.kyma.custom.commerce.order.created.v1",
+		Message:    violationMessage,
+		Violations: []Violation{{Message: "orderCode is required"}, {Field: "amount", Message: "Invalid type"}},
+	}
+	assert.Equal(t, "event data does not match the schema of the event type sap.kyma.custom.commerce.order.created.v1: "+
+		"orderCode is required; amount: Invalid type", err.Error())
+}
+
+func newEvent(t *testing.T, eventType, contentType, data string) *cev2event.Event {
+	if len(contentType) == 0 {
+		contentType = cev2event.ApplicationJSON
+	}
+	event := cev2event.New()
+	event.SetID("id")
+	event.SetSource("source")
+	event.SetType(eventType)
+	if err := event.SetData(contentType, []byte(data)); err != nil {
+		t.Fatalf("failed to set event data with error: %v", err)
+	}
+	return &event
+}
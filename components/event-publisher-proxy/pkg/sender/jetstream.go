@@ -0,0 +1,64 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+
+	pkgnats "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/nats"
+)
+
+// compile time check
+var _ GenericSender = &JetStreamMessageSender{}
+
+// JetStreamMessageSender is responsible for persisting messages in a NATS JetStream stream.
+type JetStreamMessageSender struct {
+	ctx               context.Context
+	logger            *logrus.Logger
+	backendConnection *pkgnats.BackendConnection
+	streamName        string
+}
+
+// NewJetStreamMessageSender returns a new JetStreamMessageSender instance with the given nats connection and stream name.
+func NewJetStreamMessageSender(ctx context.Context, bc *pkgnats.BackendConnection, streamName string, logger *logrus.Logger) *JetStreamMessageSender {
+	return &JetStreamMessageSender{ctx: ctx, backendConnection: bc, streamName: streamName, logger: logger}
+}
+
+// Send persists the given Cloud Event in the JetStream stream and returns the response status.
+// The event is accepted only after the NATS server acknowledged that it is stored in the stream.
+func (s *JetStreamMessageSender) Send(ctx context.Context, event *cev2event.Event) (int, error) {
+	s.logger.Infof("Sending event to NATS JetStream, id:[%s]", event.ID())
+
+	jsCtx, err := s.backendConnection.Connection.JetStream()
+	if err != nil {
+		s.logger.Errorf("Failed to create JetStream context, %s", err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Errorf("Failed to marshal event, %s", err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	// The same Nats subject used by Nats subscription, the event id is used to detect duplicates
+	msg := nats.NewMsg(event.Type())
+	msg.Data = data
+	if _, err := jsCtx.PublishMsg(msg, nats.Context(ctx), nats.ExpectStream(s.streamName), nats.MsgId(event.ID())); err != nil {
+		s.logger.Errorf("Failed to send: %s", err.Error())
+		if s.backendConnection.Connection.IsClosed() {
+			s.logger.Info("Reconnect...")
+			if err := s.backendConnection.Reconnect(); err != nil {
+				s.logger.Errorf("Failed to reconnect: %s", err.Error())
+			}
+		}
+		return http.StatusBadGateway, err
+	}
+
+	s.logger.Infof("sent id:[%s], persisted in stream:[%s]", event.ID(), s.streamName)
+	return http.StatusNoContent, nil
+}
@@ -0,0 +1,104 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	pkgnats "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/nats"
+	testingutils "github.com/kyma-project/kyma/components/event-publisher-proxy/testing"
+)
+
+const testStreamName = "kyma"
+
+func TestJetStreamSendCloudEvent(t *testing.T) {
+	logger := logrus.New()
+	logger.Info("TestJetStreamSender started")
+
+	// Start Nats server with JetStream enabled
+	storeDir, err := ioutil.TempDir("", "jetstream")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(storeDir) }()
+	natsServer := testingutils.StartJetStreamNatsServer(storeDir)
+	assert.NotNil(t, natsServer)
+	defer natsServer.Shutdown()
+
+	// connect to nats
+	bc := pkgnats.NewBackendConnection(natsServer.ClientURL(), true, 10, time.Second)
+	err = bc.Connect()
+	assert.Nil(t, err)
+	assert.NotNil(t, bc.Connection)
+
+	// create message sender
+	ctx := context.Background()
+	sender := NewJetStreamMessageSender(ctx, bc, testStreamName, logger)
+
+	// create cloudevent
+	ce := testingutils.StructuredCloudEventPayloadWithCleanEventType
+	event := cloudevents.NewEvent()
+	event.SetType(testingutils.CloudEventType)
+	err = json.Unmarshal([]byte(ce), &event)
+	assert.Nil(t, err)
+
+	// send cloudevent, the message is not accepted cause the stream does not exist yet
+	status, err := sender.Send(ctx, &event)
+	assert.NotNil(t, err)
+	assert.Equal(t, status, http.StatusBadGateway)
+
+	// create the stream
+	jsCtx, err := bc.Connection.JetStream()
+	assert.Nil(t, err)
+	_, err = jsCtx.AddStream(&nats.StreamConfig{
+		Name:     testStreamName,
+		Subjects: []string{testingutils.CloudEventType},
+		Storage:  nats.MemoryStorage,
+	})
+	assert.Nil(t, err)
+
+	// send cloudevent
+	status, err = sender.Send(ctx, &event)
+	assert.Nil(t, err)
+	assert.Equal(t, status, http.StatusNoContent)
+
+	// send the same cloudevent again, the duplicate is not persisted
+	status, err = sender.Send(ctx, &event)
+	assert.Nil(t, err)
+	assert.Equal(t, status, http.StatusNoContent)
+
+	info, err := jsCtx.StreamInfo(testStreamName)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), info.State.Msgs)
+
+	// the persisted message is the structured cloudevent
+	msg, err := jsCtx.GetMsg(testStreamName, info.State.FirstSeq)
+	assert.Nil(t, err)
+	assert.Equal(t, testingutils.CloudEventType, msg.Subject)
+	persisted := cloudevents.NewEvent()
+	err = json.Unmarshal(msg.Data, &persisted)
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf(`"%s"`, testingutils.CloudEventData), string(persisted.Data()))
+	assert.Equal(t, event.ID(), persisted.ID())
+
+	// close connection
+	bc.Connection.Close()
+
+	// send the cloudevent again, the message is not persisted cause the connection was closed
+	status, err = sender.Send(ctx, &event)
+	assert.NotNil(t, err)
+	assert.Equal(t, status, http.StatusBadGateway)
+
+	// send the cloudevent again, the reconnection should work
+	status, err = sender.Send(ctx, &event)
+	assert.Nil(t, err)
+	assert.Equal(t, status, http.StatusNoContent)
+}
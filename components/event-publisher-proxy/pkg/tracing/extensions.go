@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2extensions "github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opencensus.io/trace"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/tracing/propagation/tracecontextb3"
+)
+
+const (
+	// B3Extension is the CE extension attribute which carries the trace context in the B3 single header format:
+	// {TraceId}-{SpanId}-{SamplingState}
+	B3Extension = "b3"
+)
+
+// AddTracingContextToCEExtensions adds the trace context of the given request to the given event as the W3C traceparent
+// and tracestate and the B3 CE extension attributes, so that the trace is continued by the subscribers of the event.
+// The event is not changed if the request does not carry a trace context or the event already carries one.
+func AddTracingContextToCEExtensions(request *http.Request, event *cev2event.Event) {
+	if _, ok := cev2extensions.GetDistributedTracingExtension(*event); ok {
+		return
+	}
+	sc, ok := tracecontextb3.TraceContextEgress.SpanContextFromRequest(request)
+	if !ok {
+		return
+	}
+	cev2extensions.FromSpanContext(sc).AddTracingAttributes(event)
+	event.SetExtension(B3Extension, b3Value(sc))
+}
+
+// b3Value returns the B3 single header value of the given span context.
+func b3Value(sc trace.SpanContext) string {
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	return hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + sampled
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"net/http"
+	"testing"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	b3          = "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"
+)
+
+func TestAddTracingContextToCEExtensions(t *testing.T) {
+	testCases := []struct {
+		name            string
+		headers         map[string]string
+		extensions      map[string]string
+		wantTraceParent string
+		wantTraceState  string
+		wantB3          string
+	}{
+		{
+			name:            "W3C trace context headers",
+			headers:         map[string]string{"traceparent": traceParent, "tracestate": "vendor=value"},
+			wantTraceParent: traceParent,
+			wantTraceState:  "vendor=value",
+			wantB3:          b3,
+		},
+		{
+			name: "B3 headers",
+			headers: map[string]string{
+				"X-B3-TraceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+				"X-B3-SpanId":  "00f067aa0ba902b7",
+				"X-B3-Sampled": "1",
+			},
+			wantTraceParent: traceParent,
+			wantB3:          b3,
+		},
+		{
+			name:    "no tracing headers",
+			headers: map[string]string{},
+		},
+		{
+			name:            "event with trace context",
+			headers:         map[string]string{"traceparent": traceParent},
+			extensions:      map[string]string{"traceparent": "00-80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-01"},
+			wantTraceParent: "00-80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-01",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+			if err != nil {
+				t.Fatalf("failed to create request with error: %v", err)
+			}
+			for name, value := range tc.headers {
+				request.Header.Set(name, value)
+			}
+			event := cev2event.New()
+			for name, value := range tc.extensions {
+				event.SetExtension(name, value)
+			}
+
+			AddTracingContextToCEExtensions(request, &event)
+
+			assertExtension(t, &event, "traceparent", tc.wantTraceParent)
+			assertExtension(t, &event, "tracestate", tc.wantTraceState)
+			assertExtension(t, &event, B3Extension, tc.wantB3)
+		})
+	}
+}
+
+func assertExtension(t *testing.T, event *cev2event.Event, name, want string) {
+	value, ok := event.Extensions()[name]
+	if len(want) == 0 {
+		assert.False(t, ok, "unexpected extension %s", name)
+		return
+	}
+	assert.Equal(t, want, value, name)
+}
@@ -71,6 +71,10 @@ const (
            "datacontenttype":"` + CloudEventDataContentType + `"
         }`
 
+	BatchCloudEventPayload = `[` + StructuredCloudEventPayload + `,` + StructuredCloudEventPayload + `]`
+
+	BatchCloudEventPayloadWithInvalidEvent = `[` + StructuredCloudEventPayload + `,` + StructuredCloudEventPayloadWithoutSource + `]`
+
 	ValidLegacyEventPayloadWithEventId = `{
             "event-id": "` + EventID + `",
             "event-type":"` + LegacyEventType + `",
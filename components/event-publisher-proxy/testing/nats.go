@@ -18,6 +18,15 @@ func StartNatsServer() *server.Server {
 	return test.RunServer(&opts)
 }
 
+// StartJetStreamNatsServer starts a NATS server with JetStream enabled which stores the streams in the given directory.
+func StartJetStreamNatsServer(storeDir string) *server.Server {
+	opts := test.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = storeDir
+	return test.RunServer(&opts)
+}
+
 func SubscribeToEventOrFail(t *testing.T, connection *nats.Conn, eventType string, validator nats.MsgHandler) {
 	if _, err := connection.Subscribe(eventType, validator); err != nil {
 		t.Fatalf("Failed to subscribe to event with error: %v", err)
@@ -62,6 +62,10 @@ func GetStructuredMessageHeaders() http.Header {
 	return http.Header{"Content-Type": []string{"application/cloudevents+json"}}
 }
 
+func GetBatchMessageHeaders() http.Header {
+	return http.Header{"Content-Type": []string{"application/cloudevents-batch+json"}}
+}
+
 func GetBinaryMessageHeaders() http.Header {
 	headers := make(http.Header)
 	headers.Add(CeIDHeader, EventID)
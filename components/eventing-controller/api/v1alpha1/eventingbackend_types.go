@@ -32,6 +32,10 @@ type EventingBackendStatus struct {
 	// +optional
 	PublisherProxyReady *bool `json:"publisherProxyReady"`
 
+	// Specifies whether the JetStream stream persisting the events is ready, set only for NATS with JetStream
+	// +optional
+	NATSStreamReady *bool `json:"natsStreamReady,omitempty"`
+
 	// The name of the secret containing BEB access tokens, required only for BEB
 	// +optional
 	BebSecretName string `json:"bebSecretName"`
@@ -51,6 +51,38 @@ const (
 	ProtocolSettingsContentModeStructured string = "STRUCTURED"
 )
 
+const (
+	BackoffTypeExponential string = "exponential"
+	BackoffTypeLinear      string = "linear"
+)
+
+// RetryPolicy defines how often and how fast the dispatch of an event to the sink is retried
+type RetryPolicy struct {
+	// MaxAttempts defines the maximum number of dispatch attempts of an event including the first one
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts *int `json:"maxAttempts,omitempty"`
+
+	// BackoffType defines how the delay between two dispatch attempts grows, either exponential or linear
+	// +optional
+	// +kubebuilder:validation:Enum=exponential;linear
+	BackoffType *string `json:"backoffType,omitempty"`
+
+	// MaxBackoff defines the upper bound of the delay between two dispatch attempts, e.g. 30s
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+const (
+	// FilterTypeExact matches the attributes which are equal to the filter value
+	FilterTypeExact string = "exact"
+	// FilterTypePrefix matches the attributes which start with the filter value
+	FilterTypePrefix string = "prefix"
+	// FilterTypeWildcard matches the event types against a pattern where "*" matches a single segment
+	// and a trailing ">" matches one or more segments, only supported for the event type by NATS
+	FilterTypeWildcard string = "wildcard"
+)
+
 // Filter defines the CE filter element
 type Filter struct {
 	// Type defines the type of the filter
@@ -71,6 +103,11 @@ type BebFilter struct {
 
 	// EventType defines the type of CE filter
 	EventType *Filter `json:"eventType"`
+
+	// Extensions defines the CE extension attribute filters, the property is the name of the extension attribute.
+	// Only supported by NATS
+	// +optional
+	Extensions []*Filter `json:"extensions,omitempty"`
 }
 
 func (bf *BebFilter) hash() (uint64, error) {
@@ -123,6 +160,24 @@ type SubscriptionSpec struct {
 
 	// Filter defines the list of filters
 	Filter *BebFilters `json:"filter"`
+
+	// RetryPolicy defines how the dispatch of an event to the sink is retried
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// DeadLetterSink defines the endpoint which receives the events whose dispatch failed after all retries
+	// +optional
+	DeadLetterSink string `json:"deadLetterSink,omitempty"`
+
+	// MaxInFlight defines the maximum number of events which are dispatched to the sink concurrently
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxInFlight *int `json:"maxInFlight,omitempty"`
+
+	// MaxRatePerSecond defines the maximum number of events which are dispatched to the sink per second
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRatePerSecond *int `json:"maxRatePerSecond,omitempty"`
 }
 
 type EmsSubscriptionStatus struct {
@@ -180,6 +235,19 @@ type SubscriptionStatus struct {
 	// EmsSubscriptionStatus defines the status of Subscription in BEB
 	// +optional
 	EmsSubscriptionStatus EmsSubscriptionStatus `json:"emsSubscriptionStatus,omitempty"`
+
+	// DispatchStatus defines the status of the event dispatch to the sink in NATS on the leader replica
+	// +optional
+	DispatchStatus *DispatchStatus `json:"dispatchStatus,omitempty"`
+}
+
+// DispatchStatus defines the status of the event dispatch of a Subscription in NATS
+type DispatchStatus struct {
+	// PendingMessages defines the number of events which were received from NATS but not dispatched yet
+	PendingMessages int64 `json:"pendingMessages"`
+
+	// SlowConsumer defines if NATS dropped events because the dispatch could not keep up with them
+	SlowConsumer bool `json:"slowConsumer"`
 }
 
 // +kubebuilder:object:root=true
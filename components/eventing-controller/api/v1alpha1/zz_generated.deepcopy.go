@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -21,6 +22,17 @@ func (in *BebFilter) DeepCopyInto(out *BebFilter) {
 		*out = new(Filter)
 		**out = **in
 	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]*Filter, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Filter)
+				**out = **in
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BebFilter.
@@ -75,6 +87,21 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchStatus) DeepCopyInto(out *DispatchStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchStatus.
+func (in *DispatchStatus) DeepCopy() *DispatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmsSubscriptionStatus) DeepCopyInto(out *EmsSubscriptionStatus) {
 	*out = *in
@@ -182,6 +209,11 @@ func (in *EventingBackendStatus) DeepCopyInto(out *EventingBackendStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.NATSStreamReady != nil {
+		in, out := &in.NATSStreamReady, &out.NATSStreamReady
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingBackendStatus.
@@ -244,6 +276,36 @@ func (in *ProtocolSettings) DeepCopy() *ProtocolSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int)
+		**out = **in
+	}
+	if in.BackoffType != nil {
+		in, out := &in.BackoffType, &out.BackoffType
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
@@ -316,6 +378,21 @@ func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 		*out = new(BebFilters)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxInFlight != nil {
+		in, out := &in.MaxInFlight, &out.MaxInFlight
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxRatePerSecond != nil {
+		in, out := &in.MaxRatePerSecond, &out.MaxRatePerSecond
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
@@ -339,6 +416,11 @@ func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 		}
 	}
 	out.EmsSubscriptionStatus = in.EmsSubscriptionStatus
+	if in.DispatchStatus != nil {
+		in, out := &in.DispatchStatus, &out.DispatchStatus
+		*out = new(DispatchStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
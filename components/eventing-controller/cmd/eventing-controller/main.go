@@ -18,6 +18,10 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/reconciler/backend"
 )
 
+const (
+	leaderElectionID = "eventing-controller-leader-election"
+)
+
 func main() {
 	setupLogger := ctrl.Log.WithName("setup")
 
@@ -60,6 +64,8 @@ func main() {
 		HealthProbeBindAddress: opts.ProbeAddr,
 		Port:                   9443,
 		SyncPeriod:             &opts.ReconcilePeriod, // CHECK Only used in BEB so far.
+		LeaderElection:         opts.LeaderElect,
+		LeaderElectionID:       leaderElectionID,
 	})
 	if err != nil {
 		setupLogger.Error(err, "start manager failed")
@@ -67,7 +73,7 @@ func main() {
 	}
 
 	// Instantiate and initialize all the subscription commanders.
-	natsCommander := nats.NewCommander(restCfg, opts.MetricsAddr, opts.MaxReconnects, opts.ReconnectWait, ctrLogger)
+	natsCommander := nats.NewCommander(restCfg, opts.MetricsAddr, opts.MaxReconnects, opts.ReconnectWait, opts.LeaderElect, ctrLogger)
 	if err := natsCommander.Init(mgr); err != nil {
 		setupLogger.Error(err, "initialize NATS commander failed")
 		os.Exit(1)
@@ -17,6 +17,7 @@ const (
 	argNameProbeAddr       = "health-probe-bind-addr"
 	argNameReadyEndpoint   = "ready-check-endpoint"
 	argNameHealthEndpoint  = "health-check-endpoint"
+	argNameLeaderElect     = "leader-elect"
 
 	// env
 	envNameLogFormat = "APP_LOG_FORMAT"
@@ -38,6 +39,7 @@ type Args struct {
 	ProbeAddr       string
 	ReadyEndpoint   string
 	HealthEndpoint  string
+	LeaderElect     bool
 }
 
 // Env represents the controller environment variables.
@@ -60,6 +62,7 @@ func (o *Options) Parse() error {
 	flag.StringVar(&o.ProbeAddr, argNameProbeAddr, ":8081", "The TCP address that the controller should bind to for serving health probes.")
 	flag.StringVar(&o.ReadyEndpoint, argNameReadyEndpoint, "readyz", "The endpoint of the readiness probe.")
 	flag.StringVar(&o.HealthEndpoint, argNameHealthEndpoint, "healthz", "The endpoint of the health probe.")
+	flag.BoolVar(&o.LeaderElect, argNameLeaderElect, false, "Enable leader election to run several replicas, only the leader reconciles while all of them dispatch (NATS).")
 	flag.Parse()
 
 	if err := envconfig.Process("", &o.Env); err != nil {
@@ -71,7 +74,7 @@ func (o *Options) Parse() error {
 
 // String implements the fmt.Stringer interface.
 func (o Options) String() string {
-	return fmt.Sprintf("--%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v %s=%v %s=%v",
+	return fmt.Sprintf("--%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v %s=%v %s=%v",
 		argNameMaxReconnects, o.MaxReconnects,
 		argNameMetricsAddr, o.MetricsAddr,
 		argNameReconnectWait, o.ReconnectWait,
@@ -79,6 +82,7 @@ func (o Options) String() string {
 		argNameProbeAddr, o.ProbeAddr,
 		argNameReadyEndpoint, o.ReadyEndpoint,
 		argNameHealthEndpoint, o.HealthEndpoint,
+		argNameLeaderElect, o.LeaderElect,
 		envNameLogFormat, o.LogFormat,
 		envNameLogLevel, o.LogLevel,
 	)
@@ -19,3 +19,9 @@ type Commander interface {
 	// Stop tells the commander instance to shutdown and clean-up.
 	Stop() error
 }
+
+// StreamStatusReporter is implemented by the commanders whose backend persists the events in a stream.
+type StreamStatusReporter interface {
+	// StreamReady returns nil if the backend does not use a stream, otherwise whether the stream is healthy.
+	StreamReady() *bool
+}
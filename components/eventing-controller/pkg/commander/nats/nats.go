@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
 	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
@@ -23,7 +24,9 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/commander"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tracing"
 	subscription "github.com/kyma-project/kyma/components/eventing-controller/reconciler/subscription-nats"
+	"github.com/kyma-project/kyma/components/eventing-controller/utils"
 )
 
 const (
@@ -41,6 +44,9 @@ func AddToScheme(scheme *runtime.Scheme) error {
 	return nil
 }
 
+// compile time check
+var _ commander.StreamStatusReporter = &Commander{}
+
 // Commander implements the Commander interface.
 type Commander struct {
 	cancel      context.CancelFunc
@@ -50,16 +56,19 @@ type Commander struct {
 	mgr         manager.Manager
 	backend     handlers.MessagingBackend
 	logger      *logger.Logger
+	// leaderElection is true if several replicas are running, the ones which are not the leader dispatch only
+	leaderElection bool
 }
 
 // NewCommander creates the Commander for BEB and initializes it as far as it
 // does not depend on non-common options.
-func NewCommander(restCfg *rest.Config, metricsAddr string, maxReconnects int, reconnectWait time.Duration, logger *logger.Logger) *Commander {
+func NewCommander(restCfg *rest.Config, metricsAddr string, maxReconnects int, reconnectWait time.Duration, leaderElection bool, logger *logger.Logger) *Commander {
 	return &Commander{
-		envCfg:      env.GetNatsConfig(maxReconnects, reconnectWait), // TODO Harmonization.
-		restCfg:     restCfg,
-		metricsAddr: metricsAddr,
-		logger:      logger,
+		envCfg:         env.GetNatsConfig(maxReconnects, reconnectWait), // TODO Harmonization.
+		restCfg:        restCfg,
+		metricsAddr:    metricsAddr,
+		leaderElection: leaderElection,
+		logger:         logger,
 	}
 }
 
@@ -69,6 +78,27 @@ func (c *Commander) Init(mgr manager.Manager) error {
 		return fmt.Errorf("env var URL must be a non-empty value")
 	}
 	c.mgr = mgr
+
+	// the spans of the event dispatch are exported by all the replicas
+	if len(c.envCfg.TracingOTLPEndpoint) > 0 {
+		exporter, err := tracing.NewExporter(c.envCfg.TracingOTLPEndpoint, c.envCfg.TracingServiceName, c.logger)
+		if err != nil {
+			return fmt.Errorf("unable to create the tracing exporter: %v", err)
+		}
+		if err := mgr.Add(exporter); err != nil {
+			return fmt.Errorf("unable to setup the tracing exporter: %v", err)
+		}
+		trace.RegisterExporter(exporter)
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(c.envCfg.TracingSampleRatio)})
+	}
+
+	// the replicas which are not the leader dispatch the events of the subscriptions reconciled by the leader
+	if c.leaderElection {
+		dispatcher := subscription.NewDispatcher(mgr, c.restCfg, c.envCfg, c.logger)
+		if err := mgr.Add(dispatcher); err != nil {
+			return fmt.Errorf("unable to setup the NATS subscription dispatcher: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -95,6 +125,22 @@ func (c *Commander) Start(_ commander.Params) error {
 	return nil
 }
 
+// StreamReady implements the StreamStatusReporter interface.
+func (c *Commander) StreamReady() *bool {
+	if !c.envCfg.JetStreamEnabled {
+		return nil
+	}
+	natsBackend, ok := c.backend.(*handlers.Nats)
+	if !ok {
+		return utils.BoolPtr(false)
+	}
+	ready, err := natsBackend.StreamReady()
+	if err != nil {
+		c.namedLogger().Errorw("check JetStream stream failed", "stream", c.envCfg.JSStreamName, "error", err)
+	}
+	return utils.BoolPtr(ready)
+}
+
 // Stop implements the Commander interface and stops the commander.
 func (c *Commander) Stop() error {
 	c.cancel()
@@ -70,7 +70,7 @@ func NewBEBPublisherDeployment(publisherConfig env.PublisherConfig) *appsv1.Depl
 							Name:            PublisherName,
 							Image:           publisherConfig.Image,
 							Ports:           getContainerPorts(),
-							Env:             getBEBEnvVars(),
+							Env:             getBEBEnvVars(publisherConfig),
 							LivenessProbe:   getLivenessProbe(),
 							ReadinessProbe:  getReadinessProbe(),
 							ImagePullPolicy: getImagePullPolicy(publisherConfig.ImagePullPolicy),
@@ -117,7 +117,7 @@ func NewNATSPublisherDeployment(publisherConfig env.PublisherConfig) *appsv1.Dep
 							Name:            PublisherName,
 							Image:           publisherConfig.Image,
 							Ports:           getContainerPorts(),
-							Env:             getNATSEnvVars(),
+							Env:             getNATSEnvVars(publisherConfig),
 							LivenessProbe:   getLivenessProbe(),
 							ReadinessProbe:  getReadinessProbe(),
 							ImagePullPolicy: getImagePullPolicy(publisherConfig.ImagePullPolicy),
@@ -201,7 +201,7 @@ func getContainerPorts() []v1.ContainerPort {
 	}
 }
 
-func getBEBEnvVars() []v1.EnvVar {
+func getBEBEnvVars(publisherConfig env.PublisherConfig) []v1.EnvVar {
 	return []v1.EnvVar{
 		{Name: "BACKEND", Value: "beb"},
 		{Name: "PORT", Value: strconv.Itoa(int(publisherPortNum))},
@@ -251,10 +251,12 @@ func getBEBEnvVars() []v1.EnvVar {
 			Name:  "BEB_NAMESPACE",
 			Value: fmt.Sprintf("%s$(BEB_NAMESPACE_VALUE)", bebNamespacePrefix),
 		},
+		{Name: "SCHEMA_VALIDATION_MODE", Value: publisherConfig.SchemaValidationMode},
+		{Name: "SCHEMA_CACHE_TTL", Value: publisherConfig.SchemaCacheTTL},
 	}
 }
 
-func getNATSEnvVars() []v1.EnvVar {
+func getNATSEnvVars(publisherConfig env.PublisherConfig) []v1.EnvVar {
 	return []v1.EnvVar{
 		{Name: "BACKEND", Value: "nats"},
 		{Name: "PORT", Value: strconv.Itoa(int(publisherPortNum))},
@@ -263,6 +265,10 @@ func getNATSEnvVars() []v1.EnvVar {
 		{Name: "LEGACY_NAMESPACE", Value: "kyma"},
 		{Name: "LEGACY_EVENT_TYPE_PREFIX", Value: "sap.kyma.custom"},
 		{Name: "EVENT_TYPE_PREFIX", Value: "sap.kyma.custom"},
+		{Name: "ENABLE_JETSTREAM_BACKEND", Value: strconv.FormatBool(publisherConfig.JetStreamEnabled)},
+		{Name: "JS_STREAM_NAME", Value: publisherConfig.JSStreamName},
+		{Name: "SCHEMA_VALIDATION_MODE", Value: publisherConfig.SchemaValidationMode},
+		{Name: "SCHEMA_CACHE_TTL", Value: publisherConfig.SchemaCacheTTL},
 	}
 }
 
@@ -25,6 +25,16 @@ type PublisherConfig struct {
 	RequestsMemory  string `envconfig:"PUBLISHER_REQUESTS_MEMORY" default:"64Mi"`
 	LimitsCPU       string `envconfig:"PUBLISHER_LIMITS_CPU" default:"100m"`
 	LimitsMemory    string `envconfig:"PUBLISHER_LIMITS_MEMORY" default:"128Mi"`
+
+	// JetStream config shared with the NATS subscription controller
+	JetStreamEnabled bool   `envconfig:"ENABLE_JETSTREAM_BACKEND" default:"false"`
+	JSStreamName     string `envconfig:"JS_STREAM_NAME" default:"kyma"`
+
+	// SchemaValidationMode validates the published events against the schemas registered by the applications,
+	// it is one of: disabled, warn or enforce
+	SchemaValidationMode string `envconfig:"PUBLISHER_SCHEMA_VALIDATION_MODE" default:"disabled"`
+	// SchemaCacheTTL is the duration the event schemas of an application are cached for
+	SchemaCacheTTL string `envconfig:"PUBLISHER_SCHEMA_CACHE_TTL" default:"5m"`
 }
 
 func GetBackendConfig() BackendConfig {
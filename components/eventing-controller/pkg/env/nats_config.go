@@ -23,6 +23,22 @@ type NatsConfig struct {
 	MaxConnsPerHost     int           `envconfig:"MAX_CONNS_PER_HOST" default:"50"`
 	MaxIdleConnsPerHost int           `envconfig:"MAX_IDLE_CONNS_PER_HOST" default:"50"`
 	IdleConnTimeout     time.Duration `envconfig:"IDLE_CONN_TIMEOUT" default:"10s"`
+
+	// JetStream config, events are persisted in a stream and dispatched by durable consumers if enabled
+	JetStreamEnabled        bool          `envconfig:"ENABLE_JETSTREAM_BACKEND" default:"false"`
+	JSStreamName            string        `envconfig:"JS_STREAM_NAME" default:"kyma"`
+	JSStreamStorageType     string        `envconfig:"JS_STREAM_STORAGE_TYPE" default:"file"`
+	JSStreamReplicas        int           `envconfig:"JS_STREAM_REPLICAS" default:"1"`
+	JSStreamRetentionPolicy string        `envconfig:"JS_STREAM_RETENTION_POLICY" default:"interest"`
+	JSStreamMaxMessages     int64         `envconfig:"JS_STREAM_MAX_MSGS" default:"-1"`
+	JSStreamMaxBytes        int64         `envconfig:"JS_STREAM_MAX_BYTES" default:"-1"`
+	JSConsumerAckWait       time.Duration `envconfig:"JS_CONSUMER_ACK_WAIT" default:"30s"`
+	JSConsumerMaxDeliver    int           `envconfig:"JS_CONSUMER_MAX_DELIVER" default:"5"`
+
+	// Tracing config, the spans of the event dispatch are exported to the OTLP/HTTP collector if an endpoint is set
+	TracingOTLPEndpoint string  `envconfig:"TRACING_OTLP_ENDPOINT" default:""`
+	TracingServiceName  string  `envconfig:"TRACING_SERVICE_NAME" default:"eventing-controller"`
+	TracingSampleRatio  float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"0.1"`
 }
 
 func GetNatsConfig(maxReconnects int, reconnectWait time.Duration) NatsConfig {
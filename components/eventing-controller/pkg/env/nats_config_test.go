@@ -16,12 +16,20 @@ func Test_GetNatsConfig(t *testing.T) {
 	idleConnTimeout := time.Second * 40
 
 	envs := map[string]string{
-		"NATS_URL":                "NATS_URL",
-		"EVENT_TYPE_PREFIX":       "EVENT_TYPE_PREFIX",
-		"MAX_IDLE_CONNS":          fmt.Sprintf("%d", maxIdleConns),
-		"MAX_CONNS_PER_HOST":      fmt.Sprintf("%d", maxConnsPerHost),
-		"MAX_IDLE_CONNS_PER_HOST": fmt.Sprintf("%d", maxIdleConnsPerHost),
-		"IDLE_CONN_TIMEOUT":       fmt.Sprintf("%v", idleConnTimeout),
+		"NATS_URL":                 "NATS_URL",
+		"EVENT_TYPE_PREFIX":        "EVENT_TYPE_PREFIX",
+		"MAX_IDLE_CONNS":           fmt.Sprintf("%d", maxIdleConns),
+		"MAX_CONNS_PER_HOST":       fmt.Sprintf("%d", maxConnsPerHost),
+		"MAX_IDLE_CONNS_PER_HOST":  fmt.Sprintf("%d", maxIdleConnsPerHost),
+		"IDLE_CONN_TIMEOUT":        fmt.Sprintf("%v", idleConnTimeout),
+		"ENABLE_JETSTREAM_BACKEND": "true",
+		"JS_STREAM_NAME":           "JS_STREAM_NAME",
+		"JS_STREAM_STORAGE_TYPE":   "memory",
+		"JS_STREAM_REPLICAS":       "3",
+		"JS_CONSUMER_ACK_WAIT":     "10s",
+		"JS_CONSUMER_MAX_DELIVER":  "7",
+		"TRACING_OTLP_ENDPOINT":    "http://otel-collector:4318",
+		"TRACING_SAMPLE_RATIO":     "0.5",
 	}
 
 	g := NewGomegaWithT(t)
@@ -50,4 +58,18 @@ func Test_GetNatsConfig(t *testing.T) {
 	g.Expect(config.MaxConnsPerHost).To(Equal(maxConnsPerHost))
 	g.Expect(config.MaxIdleConnsPerHost).To(Equal(maxIdleConnsPerHost))
 	g.Expect(config.IdleConnTimeout).To(Equal(idleConnTimeout))
+
+	g.Expect(config.JetStreamEnabled).To(BeTrue())
+	g.Expect(config.JSStreamName).To(Equal(envs["JS_STREAM_NAME"]))
+	g.Expect(config.JSStreamStorageType).To(Equal(envs["JS_STREAM_STORAGE_TYPE"]))
+	g.Expect(config.JSStreamReplicas).To(Equal(3))
+	g.Expect(config.JSStreamRetentionPolicy).To(Equal("interest"))
+	g.Expect(config.JSStreamMaxMessages).To(Equal(int64(-1)))
+	g.Expect(config.JSStreamMaxBytes).To(Equal(int64(-1)))
+	g.Expect(config.JSConsumerAckWait).To(Equal(10 * time.Second))
+	g.Expect(config.JSConsumerMaxDeliver).To(Equal(7))
+
+	g.Expect(config.TracingOTLPEndpoint).To(Equal(envs["TRACING_OTLP_ENDPOINT"]))
+	g.Expect(config.TracingServiceName).To(Equal("eventing-controller"))
+	g.Expect(config.TracingSampleRatio).To(Equal(0.5))
 }
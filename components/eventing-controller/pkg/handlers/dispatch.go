@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tracing"
+)
+
+// CE extension attributes added to the events forwarded to the dead-letter sink.
+// note: CE extension attribute names must consist of lower-case letters and digits only
+const (
+	DeadLetterReasonExtension   = "deadletterreason"
+	DeadLetterStatusExtension   = "deadletterstatus"
+	DeadLetterAttemptsExtension = "deadletterattempts"
+)
+
+// retryPolicy is the resolved retry policy of a subscription.
+type retryPolicy struct {
+	maxAttempts int
+	backoffType string
+	maxBackoff  time.Duration
+}
+
+const (
+	// defaultMaxInFlight keeps the events of a subscription in order unless the subscription allows more
+	defaultMaxInFlight = 1
+	// defaultMaxBackoff caps the delay between two dispatch attempts unless the subscription sets its own cap
+	defaultMaxBackoff = 10 * period
+)
+
+// dispatchConfig holds everything needed to dispatch the events of a subscription.
+type dispatchConfig struct {
+	sink             string
+	deadLetterSink   string
+	retryPolicy      retryPolicy
+	filter           *subjectFilter
+	maxInFlight      int
+	maxRatePerSecond int
+	// ackWait is the ack wait of the JetStream consumer, it is zero for core NATS
+	ackWait time.Duration
+}
+
+// newDispatchConfig returns the dispatch config of the given subscription filter, the retry policy fields which are
+// not set in the subscription are defaulted to the given max attempts and an exponential backoff capped at defaultMaxBackoff.
+// The events are dispatched one at a time without rate limit unless the subscription sets the dispatch limits.
+func newDispatchConfig(sub *eventingv1alpha1.Subscription, defaultMaxAttempts int, filter *subjectFilter) dispatchConfig {
+	policy := retryPolicy{
+		maxAttempts: defaultMaxAttempts,
+		backoffType: eventingv1alpha1.BackoffTypeExponential,
+		maxBackoff:  defaultMaxBackoff,
+	}
+	if sub.Spec.RetryPolicy != nil {
+		if sub.Spec.RetryPolicy.MaxAttempts != nil {
+			policy.maxAttempts = *sub.Spec.RetryPolicy.MaxAttempts
+		}
+		if sub.Spec.RetryPolicy.BackoffType != nil {
+			policy.backoffType = *sub.Spec.RetryPolicy.BackoffType
+		}
+		if sub.Spec.RetryPolicy.MaxBackoff != nil {
+			policy.maxBackoff = sub.Spec.RetryPolicy.MaxBackoff.Duration
+		}
+	}
+	config := dispatchConfig{
+		sink:           sub.Spec.Sink,
+		deadLetterSink: sub.Spec.DeadLetterSink,
+		retryPolicy:    policy,
+		filter:         filter,
+		maxInFlight:    defaultMaxInFlight,
+	}
+	if sub.Spec.MaxInFlight != nil {
+		config.maxInFlight = *sub.Spec.MaxInFlight
+	}
+	// a zero rate means the dispatch is not rate limited
+	if sub.Spec.MaxRatePerSecond != nil {
+		config.maxRatePerSecond = *sub.Spec.MaxRatePerSecond
+	}
+	return config
+}
+
+// backoff returns the delay to wait after the given failed attempt before the next attempt.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := period * time.Duration(attempt)
+	if p.backoffType != eventingv1alpha1.BackoffTypeLinear {
+		delay = period
+		// double the delay per attempt and stop before it overflows
+		for i := 1; i < attempt && delay <= math.MaxInt64/2; i++ {
+			delay *= 2
+		}
+	}
+	if p.maxBackoff > 0 && delay > p.maxBackoff {
+		return p.maxBackoff
+	}
+	return delay
+}
+
+// sendToDeadLetterSink forwards an event whose dispatch failed to the dead-letter sink of the subscription if any.
+// The failure reason, the last HTTP status and the number of attempts are added as CE extension attributes.
+func (n *Nats) sendToDeadLetterSink(ce *cev2event.Event, config dispatchConfig, attempts int, result cev2protocol.Result) {
+	if len(config.deadLetterSink) == 0 {
+		return
+	}
+
+	deadLetter := ce.Clone()
+	deadLetter.SetExtension(DeadLetterAttemptsExtension, attempts)
+	if result != nil {
+		deadLetter.SetExtension(DeadLetterReasonExtension, result.Error())
+		var httpResult *cev2http.Result
+		if cev2.ResultAs(result, &httpResult) {
+			deadLetter.SetExtension(DeadLetterStatusExtension, httpResult.StatusCode)
+		}
+	}
+
+	ctx, span := tracing.StartDispatchSpan(context.Background(), tracing.DeadLetterSpanName, ce, config.deadLetterSink, attempts)
+	defer span.End()
+
+	deadLetterResult := n.client.Send(cev2.ContextWithTarget(ctx, config.deadLetterSink), deadLetter)
+	tracing.SetSpanStatus(span, deadLetterResult)
+	if !cev2.IsACK(deadLetterResult) {
+		n.namedLogger().Errorw("forward event to dead-letter sink failed", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "deadLetterSink", config.deadLetterSink, "error", deadLetterResult)
+		return
+	}
+
+	n.namedLogger().Infow("event forwarded to dead-letter sink", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "deadLetterSink", config.deadLetterSink, "attempts", attempts)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestDeadLetterSink(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5225
+
+	natsServer := eventingtesting.RunNatsServerOnPort(natsPort)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	natsClient := NewNats(env.NatsConfig{
+		Url:           natsServer.ClientURL(),
+		MaxReconnects: 2,
+		ReconnectWait: time.Second,
+	}, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the sink rejects all the events
+	var attempts int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer sink.Close()
+
+	// the dead-letter sink records the failure details of the events it receives
+	deadLetters := make(chan http.Header, 1)
+	deadLetterSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLetters <- r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer deadLetterSink.Close()
+
+	maxAttempts := 3
+	backoffType := eventingv1alpha1.BackoffTypeLinear
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	sub.Spec.DeadLetterSink = deadLetterSink.URL
+	sub.Spec.RetryPolicy = &eventingv1alpha1.RetryPolicy{
+		MaxAttempts: &maxAttempts,
+		BackoffType: &backoffType,
+		MaxBackoff:  &metav1.Duration{Duration: 10 * time.Millisecond},
+	}
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	g.Expect(SendEventToNATS(natsClient, "sampledata")).Should(Succeed())
+
+	var header http.Header
+	g.Eventually(deadLetters, 5*time.Second).Should(Receive(&header))
+	g.Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(maxAttempts))
+	g.Expect(header.Get("ce-id")).To(Equal("id"))
+	g.Expect(header.Get("ce-" + DeadLetterAttemptsExtension)).To(Equal(fmt.Sprint(maxAttempts)))
+	g.Expect(header.Get("ce-" + DeadLetterStatusExtension)).To(Equal(fmt.Sprint(http.StatusServiceUnavailable)))
+	g.Expect(header.Get("ce-" + DeadLetterReasonExtension)).NotTo(BeEmpty())
+}
+
+func TestDispatchTracePropagation(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5230
+
+	natsServer := eventingtesting.RunNatsServerOnPort(natsPort)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	natsClient := NewNats(env.NatsConfig{
+		Url:           natsServer.ClientURL(),
+		MaxReconnects: 2,
+		ReconnectWait: time.Second,
+	}, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	received := make(chan http.Header, 1)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer sink.Close()
+
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	// the event carries the trace context of the publisher as CE extension attribute
+	traceID, spanID := "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	event := fmt.Sprintf(`{"data":"sampledata","datacontenttype":"application/json","id":"id","source":"%s","specversion":"1.0","type":"%s","traceparent":"00-%s-%s-01"}`,
+		eventingtesting.EventSource, eventingtesting.OrderCreatedEventType, traceID, spanID)
+	g.Expect(natsClient.connection.Publish(eventingtesting.OrderCreatedEventType, []byte(event))).Should(Succeed())
+
+	// the sink continues the trace of the publisher in both the CE extension and the tracing headers
+	var header http.Header
+	g.Eventually(received, 5*time.Second).Should(Receive(&header))
+	g.Expect(header.Get("ce-traceparent")).To(HavePrefix("00-" + traceID + "-"))
+	g.Expect(header.Get("ce-traceparent")).NotTo(ContainSubstring(spanID))
+	g.Expect(header.Get("traceparent")).To(HavePrefix("00-" + traceID + "-"))
+	g.Expect(header.Get("X-B3-TraceId")).To(Equal(traceID))
+	g.Expect(header.Get("X-B3-Sampled")).To(Equal("1"))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	g := NewWithT(t)
+
+	sub := eventingtesting.NewSubscription("sub", "foo")
+
+	// defaults to a capped exponential backoff
+	config := newDispatchConfig(sub, maxTries, nil)
+	g.Expect(config.retryPolicy.maxAttempts).To(Equal(maxTries))
+	g.Expect(config.retryPolicy.backoff(1)).To(Equal(period))
+	g.Expect(config.retryPolicy.backoff(3)).To(Equal(4 * period))
+	g.Expect(config.retryPolicy.backoff(1000)).To(BeNumerically(">", 0))
+	g.Expect(config.retryPolicy.backoff(1000)).To(Equal(defaultMaxBackoff))
+
+	backoffType := eventingv1alpha1.BackoffTypeLinear
+	sub.Spec.RetryPolicy = &eventingv1alpha1.RetryPolicy{
+		BackoffType: &backoffType,
+		MaxBackoff:  &metav1.Duration{Duration: 3 * period},
+	}
+	config = newDispatchConfig(sub, maxTries, nil)
+	g.Expect(config.retryPolicy.backoff(2)).To(Equal(2 * period))
+	g.Expect(config.retryPolicy.backoff(5)).To(Equal(3 * period))
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2types "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+)
+
+const (
+	// natsSingleTokenWildcard matches a single segment of a NATS subject
+	natsSingleTokenWildcard = "*"
+	// natsMultiTokenWildcard matches one or more trailing segments of a NATS subject
+	natsMultiTokenWildcard = ">"
+)
+
+var (
+	// ErrUnsupportedFilter is returned for the subscription filters which cannot be applied by the NATS backend.
+	ErrUnsupportedFilter = errors.New("unsupported filter")
+
+	// validPatternSegment matches the literal segments of the prefix and wildcard event-type filters,
+	// these filters are not cleaned hence their segments must be clean already.
+	validPatternSegment = regexp.MustCompile("^[a-zA-Z0-9]+$")
+
+	// validExtensionName matches the CE extension attribute names as per the CE spec.
+	validExtensionName = regexp.MustCompile("^[a-z0-9]+$")
+)
+
+// eventMatcher matches the CE attributes of a subscription filter which are not covered by its NATS subject.
+type eventMatcher struct {
+	source     *eventingv1alpha1.Filter
+	extensions []*eventingv1alpha1.Filter
+}
+
+// subjectFilter holds the filters of a Kyma subscription which share the same NATS subject.
+type subjectFilter struct {
+	subject  string
+	matchers []eventMatcher
+}
+
+// match returns true if the given event matches any of the filters of the subject.
+func (f *subjectFilter) match(ce *cev2event.Event) bool {
+	if f == nil {
+		return true
+	}
+	for _, m := range f.matchers {
+		if m.match(ce) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m eventMatcher) match(ce *cev2event.Event) bool {
+	if m.source != nil && !matchAttribute(m.source, ce.Source()) {
+		return false
+	}
+	for _, extension := range m.extensions {
+		value, ok := ce.Extensions()[extension.Property]
+		if !ok {
+			return false
+		}
+		// the extension values are compared in their canonical string format
+		s, err := cev2types.Format(value)
+		if err != nil || !matchAttribute(extension, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAttribute(filter *eventingv1alpha1.Filter, value string) bool {
+	if filter.Type == eventingv1alpha1.FilterTypePrefix {
+		return strings.HasPrefix(value, filter.Value)
+	}
+	return value == filter.Value
+}
+
+// createSubjectFilters translates the given subscription filters to NATS subjects, the filters which map
+// to the same subject are merged so that there is one NATS subscription per subject.
+func createSubjectFilters(filters []*eventingv1alpha1.BebFilter, cleaner eventtype.Cleaner) ([]*subjectFilter, error) {
+	var result []*subjectFilter
+	bySubject := make(map[string]*subjectFilter, len(filters))
+	for _, filter := range filters {
+		subject, err := createSubject(filter, cleaner)
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := createEventMatcher(filter)
+		if err != nil {
+			return nil, err
+		}
+		sf, ok := bySubject[subject]
+		if !ok {
+			sf = &subjectFilter{subject: subject}
+			bySubject[subject] = sf
+			result = append(result, sf)
+		}
+		sf.matchers = append(sf.matchers, matcher)
+	}
+	return result, nil
+}
+
+func createEventMatcher(filter *eventingv1alpha1.BebFilter) (eventMatcher, error) {
+	matcher := eventMatcher{}
+	// an empty source matches the events of all the sources
+	if filter.EventSource != nil && len(strings.TrimSpace(filter.EventSource.Value)) > 0 {
+		if err := validateAttributeFilterType(filter.EventSource); err != nil {
+			return matcher, err
+		}
+		matcher.source = filter.EventSource
+	}
+	for _, extension := range filter.Extensions {
+		if extension == nil {
+			continue
+		}
+		if !validExtensionName.MatchString(extension.Property) {
+			return matcher, errors.Wrapf(ErrUnsupportedFilter, "invalid extension attribute name [%s]", extension.Property)
+		}
+		if err := validateAttributeFilterType(extension); err != nil {
+			return matcher, err
+		}
+		matcher.extensions = append(matcher.extensions, extension)
+	}
+	return matcher, nil
+}
+
+func validateAttributeFilterType(filter *eventingv1alpha1.Filter) error {
+	switch filter.Type {
+	case "", eventingv1alpha1.FilterTypeExact, eventingv1alpha1.FilterTypePrefix:
+		return nil
+	}
+	return errors.Wrapf(ErrUnsupportedFilter, "filter type [%s] is not supported for property [%s]", filter.Type, filter.Property)
+}
+
+func createSubject(filter *eventingv1alpha1.BebFilter, cleaner eventtype.Cleaner) (string, error) {
+	if filter.EventType == nil {
+		return "", nats.ErrBadSubject
+	}
+	eventType := strings.TrimSpace(filter.EventType.Value)
+	if len(eventType) == 0 {
+		return "", nats.ErrBadSubject
+	}
+
+	switch filter.EventType.Type {
+	case "", eventingv1alpha1.FilterTypeExact:
+		// clean the application name segment in the event-type from none-alphanumeric characters
+		// return it as a NATS subject
+		return cleaner.Clean(eventType)
+	case eventingv1alpha1.FilterTypePrefix:
+		// the prefix is mapped to a subject which matches any trailing segments, hence it must end with a complete
+		// segment to match the same event types as the string prefix of the BEB backend
+		if !strings.HasSuffix(eventType, ".") {
+			return "", errors.Wrapf(ErrUnsupportedFilter, "prefix event type [%s] must end with a dot", eventType)
+		}
+		eventType = strings.TrimSuffix(eventType, ".")
+		if err := validatePattern(eventType, false); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.%s", eventType, natsMultiTokenWildcard), nil
+	case eventingv1alpha1.FilterTypeWildcard:
+		if err := validatePattern(eventType, true); err != nil {
+			return "", err
+		}
+		return eventType, nil
+	}
+	return "", errors.Wrapf(ErrUnsupportedFilter, "filter type [%s] is not supported for the event type", filter.EventType.Type)
+}
+
+// validatePattern makes sure the given event-type pattern is a valid NATS subject.
+func validatePattern(pattern string, allowWildcards bool) error {
+	segments := strings.Split(pattern, ".")
+	for i, segment := range segments {
+		if allowWildcards {
+			if segment == natsSingleTokenWildcard {
+				continue
+			}
+			if segment == natsMultiTokenWildcard && i == len(segments)-1 {
+				continue
+			}
+		}
+		if !validPatternSegment.MatchString(segment) {
+			return errors.Wrapf(ErrUnsupportedFilter, "invalid event type segment [%s] in [%s]", segment, pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/gomega"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestCreateSubject(t *testing.T) {
+	cleaner := eventtype.CleanerFunc(func(et string) (string, error) { return et, nil })
+
+	testCases := []struct {
+		name            string
+		eventType       *eventingv1alpha1.Filter
+		wantSubject     string
+		wantErr         error
+		wantUnsupported bool
+	}{
+		{
+			name:      "missing event type",
+			eventType: nil,
+			wantErr:   nats.ErrBadSubject,
+		},
+		{
+			name:      "empty event type",
+			eventType: &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeExact, Value: " "},
+			wantErr:   nats.ErrBadSubject,
+		},
+		{
+			name:        "exact event type",
+			eventType:   &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeExact, Value: eventingtesting.OrderCreatedEventType},
+			wantSubject: eventingtesting.OrderCreatedEventType,
+		},
+		{
+			name:        "event type without filter type",
+			eventType:   &eventingv1alpha1.Filter{Value: eventingtesting.OrderCreatedEventType},
+			wantSubject: eventingtesting.OrderCreatedEventType,
+		},
+		{
+			name:        "prefix event type",
+			eventType:   &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypePrefix, Value: "sap.kyma.app1."},
+			wantSubject: "sap.kyma.app1.>",
+		},
+		{
+			name:        "wildcard event type",
+			eventType:   &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeWildcard, Value: "sap.kyma.*.order.created.v1"},
+			wantSubject: "sap.kyma.*.order.created.v1",
+		},
+		{
+			name:        "trailing wildcard event type",
+			eventType:   &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeWildcard, Value: "sap.kyma.app1.>"},
+			wantSubject: "sap.kyma.app1.>",
+		},
+		{
+			name:            "prefix event type which ends within a segment",
+			eventType:       &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypePrefix, Value: "sap.kyma.app1"},
+			wantUnsupported: true,
+		},
+		{
+			name:            "wildcard in prefix event type",
+			eventType:       &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypePrefix, Value: "sap.*.app1."},
+			wantUnsupported: true,
+		},
+		{
+			name:            "multi-token wildcard which is not trailing",
+			eventType:       &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeWildcard, Value: "sap.>.created.v1"},
+			wantUnsupported: true,
+		},
+		{
+			name:            "unsupported filter type",
+			eventType:       &eventingv1alpha1.Filter{Type: "regex", Value: "sap.kyma.app1.*"},
+			wantUnsupported: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			subject, err := createSubject(&eventingv1alpha1.BebFilter{EventType: tc.eventType}, cleaner)
+			switch {
+			case tc.wantUnsupported:
+				g.Expect(errors.Is(err, ErrUnsupportedFilter)).To(BeTrue())
+			case tc.wantErr != nil:
+				g.Expect(err).To(Equal(tc.wantErr))
+			default:
+				g.Expect(err).ShouldNot(HaveOccurred())
+				g.Expect(subject).To(Equal(tc.wantSubject))
+			}
+		})
+	}
+}
+
+func TestCreateSubjectFilters(t *testing.T) {
+	g := NewWithT(t)
+
+	cleaner := eventtype.CleanerFunc(func(et string) (string, error) { return et, nil })
+	eventType := &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeExact, Value: eventingtesting.OrderCreatedEventType}
+
+	// the filters which share the same event type are merged
+	filters := []*eventingv1alpha1.BebFilter{
+		{EventSource: &eventingv1alpha1.Filter{Value: "app1"}, EventType: eventType},
+		{EventSource: &eventingv1alpha1.Filter{Value: "app2"}, EventType: eventType},
+		{EventType: &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypePrefix, Value: eventingtesting.EventTypePrefix + "."}},
+	}
+	subjectFilters, err := createSubjectFilters(filters, cleaner)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(subjectFilters).To(HaveLen(2))
+	g.Expect(subjectFilters[0].subject).To(Equal(eventingtesting.OrderCreatedEventType))
+	g.Expect(subjectFilters[0].matchers).To(HaveLen(2))
+	g.Expect(subjectFilters[1].subject).To(Equal(eventingtesting.EventTypePrefix + ".>"))
+
+	// the extension attribute names must be valid CE attribute names
+	filters = []*eventingv1alpha1.BebFilter{
+		{
+			EventType:  eventType,
+			Extensions: []*eventingv1alpha1.Filter{{Property: "Tenant-ID", Value: "foo"}},
+		},
+	}
+	_, err = createSubjectFilters(filters, cleaner)
+	g.Expect(errors.Is(err, ErrUnsupportedFilter)).To(BeTrue())
+
+	// the wildcard type is only supported for event types
+	filters = []*eventingv1alpha1.BebFilter{
+		{
+			EventSource: &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeWildcard, Value: "app*"},
+			EventType:   eventType,
+		},
+	}
+	_, err = createSubjectFilters(filters, cleaner)
+	g.Expect(errors.Is(err, ErrUnsupportedFilter)).To(BeTrue())
+}
+
+func TestSubjectFilterMatch(t *testing.T) {
+	newEvent := func(source string, extensions map[string]interface{}) *cev2event.Event {
+		ce := cev2event.New(cev2event.CloudEventsVersionV1)
+		ce.SetID("id")
+		ce.SetType(eventingtesting.OrderCreatedEventType)
+		ce.SetSource(source)
+		for name, value := range extensions {
+			ce.SetExtension(name, value)
+		}
+		return &ce
+	}
+
+	sf := &subjectFilter{
+		subject: eventingtesting.OrderCreatedEventType,
+		matchers: []eventMatcher{
+			{
+				source: &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeExact, Value: "app1"},
+			},
+			{
+				source: &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypePrefix, Value: "/default/"},
+				extensions: []*eventingv1alpha1.Filter{
+					{Type: eventingv1alpha1.FilterTypeExact, Property: "tenant", Value: "t1"},
+					{Type: eventingv1alpha1.FilterTypePrefix, Property: "priority", Value: "1"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		event     *cev2event.Event
+		wantMatch bool
+	}{
+		{
+			name:      "exact source",
+			event:     newEvent("app1", nil),
+			wantMatch: true,
+		},
+		{
+			name:      "other source",
+			event:     newEvent("app2", nil),
+			wantMatch: false,
+		},
+		{
+			name:      "prefix source with matching extensions",
+			event:     newEvent("/default/kyma/id", map[string]interface{}{"tenant": "t1", "priority": 10}),
+			wantMatch: true,
+		},
+		{
+			name:      "prefix source with other extension value",
+			event:     newEvent("/default/kyma/id", map[string]interface{}{"tenant": "t2", "priority": 10}),
+			wantMatch: false,
+		},
+		{
+			name:      "prefix source with missing extension",
+			event:     newEvent("/default/kyma/id", map[string]interface{}{"tenant": "t1"}),
+			wantMatch: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(sf.match(tc.event)).To(Equal(tc.wantMatch))
+		})
+	}
+}
+
+func TestSubscriptionWithSourceFilter(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5228
+
+	natsServer := eventingtesting.RunNatsServerOnPort(natsPort)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	natsClient := NewNats(env.NatsConfig{
+		Url:           natsServer.ClientURL(),
+		MaxReconnects: 2,
+		ReconnectWait: time.Second,
+	}, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the sink records the sources of the events it receives
+	sources := make(chan string, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sources <- r.Header.Get("ce-source")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer sink.Close()
+
+	// subscribe to all the events with the prefix from the test source only
+	sub := eventingtesting.NewSubscription("sub", "foo")
+	sub.Spec.Sink = sink.URL
+	sub.Spec.Filter = &eventingv1alpha1.BebFilters{
+		Filters: []*eventingv1alpha1.BebFilter{
+			{
+				EventSource: &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypeExact, Property: "source", Value: eventingtesting.EventSource},
+				EventType:   &eventingv1alpha1.Filter{Type: eventingv1alpha1.FilterTypePrefix, Property: "type", Value: eventingtesting.EventTypePrefix + "."},
+			},
+		},
+	}
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	eventTime := time.Now().Format(time.RFC3339)
+	eventType := eventingtesting.OrderCreatedEventType
+	otherEvent := NewNatsMessagePayload("sampledata", "id1", "/other/source", eventTime, eventType)
+	g.Expect(natsClient.connection.Publish(eventType, []byte(otherEvent))).Should(Succeed())
+	g.Expect(SendEventToNATS(natsClient, "sampledata")).Should(Succeed())
+
+	// only the event from the subscribed source is dispatched
+	g.Eventually(sources, 5*time.Second).Should(Receive(Equal(eventingtesting.EventSource)))
+	g.Consistently(sources, time.Second).ShouldNot(Receive())
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tracing"
+)
+
+const (
+	JetStreamStorageTypeFile   = "file"
+	JetStreamStorageTypeMemory = "memory"
+
+	JetStreamRetentionPolicyLimits   = "limits"
+	JetStreamRetentionPolicyInterest = "interest"
+
+	// streamNotFoundErrMessage is the error description returned by the NATS server for a missing stream, nats.go
+	// v1.11.0 returns it as an untyped error since it has no nats.ErrStreamNotFound yet
+	streamNotFoundErrMessage = "stream not found"
+	// consumerNotFoundErrMessage is the error description returned by the NATS server for a missing consumer
+	consumerNotFoundErrMessage = "consumer not found"
+	// jetStreamBackoffMarginDivisor keeps a tenth of the ack wait between the end of the backoff and the redelivery
+	jetStreamBackoffMarginDivisor = 10
+)
+
+// initJetStream creates the JetStream context and makes sure the stream used to persist the events exists.
+func (n *Nats) initJetStream() error {
+	jsCtx, err := n.connection.JetStream()
+	if err != nil {
+		return errors.Wrapf(err, "create JetStream context failed")
+	}
+	n.jsCtx = jsCtx
+
+	desiredConfig, err := getStreamConfig(n.config)
+	if err != nil {
+		return err
+	}
+
+	info, err := n.jsCtx.StreamInfo(desiredConfig.Name)
+	if err != nil {
+		if !isStreamNotFound(err) {
+			return errors.Wrapf(err, "get JetStream stream info failed")
+		}
+		if _, err := n.jsCtx.AddStream(desiredConfig); err != nil {
+			return errors.Wrapf(err, "create JetStream stream failed")
+		}
+		n.namedLogger().Infow("JetStream stream created", "stream", desiredConfig.Name)
+		return nil
+	}
+
+	if err := validateStreamConfigChange(&info.Config, desiredConfig); err != nil {
+		return err
+	}
+	if !isStreamConfigEqual(&info.Config, desiredConfig) {
+		if _, err := n.jsCtx.UpdateStream(desiredConfig); err != nil {
+			return errors.Wrapf(err, "update JetStream stream failed")
+		}
+		n.namedLogger().Infow("JetStream stream updated", "stream", desiredConfig.Name)
+	}
+	return nil
+}
+
+// StreamReady returns true if the JetStream stream which persists the events exists and has a leader.
+func (n *Nats) StreamReady() (bool, error) {
+	if n.jsCtx == nil {
+		return false, errors.New("JetStream is not initialized")
+	}
+	info, err := n.jsCtx.StreamInfo(n.config.JSStreamName)
+	if err != nil {
+		return false, errors.Wrapf(err, "get JetStream stream info failed")
+	}
+	if info.Cluster != nil && len(info.Cluster.Leader) == 0 {
+		return false, nil
+	}
+	return true, nil
+}
+
+// syncJetStreamSubscription makes sure there is one durable JetStream consumer per filter of the given subscription.
+// Consumers which are still valid are kept as they are, so that no event is lost while reconciling.
+func (n *Nats) syncJetStreamSubscription(sub *eventingv1alpha1.Subscription, filters []*subjectFilter, log *zap.SugaredLogger) error {
+	if n.connection.Status() != nats.CONNECTED {
+		if err := n.Initialize(env.Config{}); err != nil {
+			log.Errorw("reset NATS connection failed", "status", n.connection.Stats(), "error", err)
+			return err
+		}
+	}
+
+	desiredKeys := make(map[string]struct{}, len(filters))
+	for _, filter := range filters {
+		// the consumer gives up after JS_CONSUMER_MAX_DELIVER deliveries, hence the subscription can only lower it
+		config := newDispatchConfig(sub, n.config.JSConsumerMaxDeliver, filter)
+		if config.retryPolicy.maxAttempts > n.config.JSConsumerMaxDeliver {
+			config.retryPolicy.maxAttempts = n.config.JSConsumerMaxDeliver
+		}
+		config.ackWait = getJetStreamAckWait(n.config.JSConsumerAckWait, config, len(filters))
+
+		subject := filter.subject
+		key := createKey(sub, subject)
+		desiredKeys[key] = struct{}{}
+
+		if existing, ok := n.subscriptions[key]; ok {
+			if existing.IsValid() && reflect.DeepEqual(n.dispatchConfigs[key], config) {
+				continue
+			}
+			// draining keeps the durable consumer, the new subscription is going to attach to it
+			if existing.IsValid() {
+				if err := existing.Drain(); err != nil {
+					log.Errorw("drain JetStream subscription failed", "subject", subject, "error", err)
+					return err
+				}
+			}
+		}
+
+		deliverPolicy, err := n.syncJetStreamConsumer(createDurableName(key), config, log)
+		if err != nil {
+			log.Errorw("sync JetStream consumer failed", "subject", subject, "error", err)
+			return err
+		}
+
+		pool := n.getWorkerPool(sub, config)
+		jsSub, err := n.jsCtx.QueueSubscribe(subject, createQueueGroup(sub), pool.wrap(n.getJetStreamCallback(config)),
+			nats.Durable(createDurableName(key)),
+			nats.ManualAck(),
+			nats.AckExplicit(),
+			deliverPolicy,
+			nats.AckWait(config.ackWait),
+			nats.MaxAckPending(config.maxInFlight),
+			nats.MaxDeliver(n.config.JSConsumerMaxDeliver),
+		)
+		if err != nil {
+			log.Errorw("create JetStream subscription failed", "subject", subject, "error", err)
+			return err
+		}
+		n.storeSubscription(key, sub, jsSub)
+		n.dispatchConfigs[key] = config
+		pool.track(jsSub)
+	}
+
+	// delete the consumers of the filters which were removed from the subscription
+	prefix := createKeyPrefix(sub) + "."
+	for key, jsSub := range n.subscriptions {
+		if _, ok := desiredKeys[key]; ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if jsSub.IsValid() {
+			// the consumer may have been deleted by another replica already
+			if err := jsSub.Unsubscribe(); err != nil && !isConsumerNotFound(err) {
+				log.Errorw("unsubscribe JetStream subscription failed", "key", key, "error", err)
+				return err
+			}
+		}
+		n.removeSubscription(key)
+	}
+
+	return nil
+}
+
+// syncJetStreamConsumer returns the deliver policy of the durable consumer with the given name. The consumer config
+// can't be updated in place and the subscription attaches to an existing consumer as it is, hence a consumer whose
+// ack wait or max ack pending differs from the given config is deleted and recreated from its ack floor. The messages
+// acknowledged out of order after the ack floor are dispatched again.
+func (n *Nats) syncJetStreamConsumer(durableName string, config dispatchConfig, log *zap.SugaredLogger) (nats.SubOpt, error) {
+	info, err := n.jsCtx.ConsumerInfo(n.config.JSStreamName, durableName)
+	if err != nil {
+		if isConsumerNotFound(err) {
+			return nats.DeliverNew(), nil
+		}
+		return nil, errors.Wrapf(err, "get JetStream consumer info failed")
+	}
+	if info.Config.AckWait == config.ackWait && info.Config.MaxAckPending == config.maxInFlight {
+		return nats.DeliverNew(), nil
+	}
+
+	if err := n.jsCtx.DeleteConsumer(n.config.JSStreamName, durableName); err != nil && !isConsumerNotFound(err) {
+		return nil, errors.Wrapf(err, "delete JetStream consumer failed")
+	}
+	log.Infow("JetStream consumer recreated with new dispatch limits", "consumer", durableName,
+		"ackWait", config.ackWait, "maxAckPending", config.maxInFlight, "startSequence", info.AckFloor.Stream+1)
+	return nats.StartSequence(info.AckFloor.Stream + 1), nil
+}
+
+// getJetStreamAckWait returns the ack wait of the consumers of a subscription with the given number of filters.
+// The consumers share the worker pool of the subscription and each of them has at most maxInFlight messages pending,
+// hence a message waits for the dispatch of the messages of the other consumers and for the rate limit of all the
+// pending messages before it is dispatched within the given ack wait.
+func getJetStreamAckWait(ackWait time.Duration, config dispatchConfig, consumers int) time.Duration {
+	wait := ackWait * time.Duration(consumers)
+	if config.maxRatePerSecond > 0 {
+		pending := consumers * config.maxInFlight
+		wait += time.Duration(pending) * time.Second / time.Duration(config.maxRatePerSecond)
+	}
+	return wait
+}
+
+// getJetStreamCallback returns a handler which acknowledges a message only after the sink accepted the event.
+// The backoff between two attempts is the consumer ack wait, once the attempts are exhausted the event is
+// forwarded to the dead-letter sink and terminated.
+func (n *Nats) getJetStreamCallback(config dispatchConfig) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		ce, err := convertMsgToCE(msg)
+		if err != nil {
+			n.namedLogger().Errorw("convert JetStream message to CE failed", "error", err)
+			// the message can never be dispatched, hence it should not be redelivered
+			if err := msg.Term(); err != nil {
+				n.namedLogger().Errorw("terminate JetStream message failed", "error", err)
+			}
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), n.config.JSConsumerAckWait)
+		defer cancel()
+
+		// the NATS subject covers the event type only, the events filtered out are acknowledged without dispatch
+		if !config.filter.match(ce) {
+			n.namedLogger().Debugw("event filtered out", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink)
+			if err := msg.Ack(); err != nil {
+				n.namedLogger().Errorw("acknowledge JetStream message failed", "id", ce.ID(), "error", err)
+			}
+			return
+		}
+
+		// the message is not acknowledged if the dispatch failed, so that JetStream redelivers it after the ack wait
+		if result := n.sendJetStreamMessage(ctx, msg, ce, config.sink); !cev2.IsACK(result) {
+			n.namedLogger().Errorw("event dispatch failed", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink, "error", result)
+			n.handleJetStreamDispatchFailure(msg, ce, config, result)
+			return
+		}
+
+		if err := msg.Ack(); err != nil {
+			n.namedLogger().Errorw("acknowledge JetStream message failed", "id", ce.ID(), "error", err)
+			return
+		}
+
+		n.namedLogger().Infow("event dispatched", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink)
+	}
+}
+
+// sendJetStreamMessage sends the event of the given message to the given sink within a span which continues the
+// trace carried by the event, each delivery of the message is a dispatch attempt.
+func (n *Nats) sendJetStreamMessage(ctx context.Context, msg *nats.Msg, ce *cev2event.Event, sink string) cev2protocol.Result {
+	attempt := 1
+	if metadata, err := msg.Metadata(); err == nil {
+		attempt = int(metadata.NumDelivered)
+	}
+	ctx, span := tracing.StartDispatchSpan(ctx, tracing.DispatchSpanName, ce, sink, attempt)
+	defer span.End()
+
+	result := n.client.Send(cev2.ContextWithTarget(ctx, sink), *ce)
+	tracing.SetSpanStatus(span, result)
+	return result
+}
+
+// handleJetStreamDispatchFailure forwards the event to the dead-letter sink and terminates the message
+// if it was delivered as often as the retry policy allows, otherwise the message is negatively acknowledged
+// once the backoff of the retry policy elapsed. JetStream redelivers an unacknowledged message after the ack wait,
+// hence the ack wait is restarted and the backoff ends before it, so that the redelivery is driven by the retry policy.
+func (n *Nats) handleJetStreamDispatchFailure(msg *nats.Msg, ce *cev2event.Event, config dispatchConfig, result cev2protocol.Result) {
+	metadata, err := msg.Metadata()
+	if err != nil {
+		n.namedLogger().Errorw("get JetStream message metadata failed", "id", ce.ID(), "error", err)
+		return
+	}
+	if metadata.NumDelivered < uint64(config.retryPolicy.maxAttempts) {
+		if err := msg.InProgress(); err != nil {
+			// JetStream redelivers the message once the ack wait elapsed
+			n.namedLogger().Errorw("restart JetStream message ack wait failed", "id", ce.ID(), "error", err)
+			return
+		}
+		time.AfterFunc(getJetStreamBackoff(config, int(metadata.NumDelivered)), func() {
+			if err := msg.Nak(); err != nil {
+				n.namedLogger().Errorw("negatively acknowledge JetStream message failed", "id", ce.ID(), "error", err)
+			}
+		})
+		return
+	}
+
+	n.sendToDeadLetterSink(ce, config, int(metadata.NumDelivered), result)
+	if err := msg.Term(); err != nil {
+		n.namedLogger().Errorw("terminate JetStream message failed", "id", ce.ID(), "error", err)
+	}
+}
+
+// getJetStreamBackoff returns the backoff of the retry policy after the given delivery, it is capped below the ack wait
+// of the consumer, so that the message is negatively acknowledged before JetStream redelivers it.
+func getJetStreamBackoff(config dispatchConfig, delivered int) time.Duration {
+	backoff := config.retryPolicy.backoff(delivered)
+	if limit := config.ackWait - config.ackWait/jetStreamBackoffMarginDivisor; backoff > limit {
+		return limit
+	}
+	return backoff
+}
+
+// getStreamConfig returns the JetStream stream config for the given NATS config.
+// The stream captures all the event types which start with the configured event type prefix.
+func getStreamConfig(config env.NatsConfig) (*nats.StreamConfig, error) {
+	storage, err := toJetStreamStorageType(config.JSStreamStorageType)
+	if err != nil {
+		return nil, err
+	}
+	retention, err := toJetStreamRetentionPolicy(config.JSStreamRetentionPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return &nats.StreamConfig{
+		Name:      config.JSStreamName,
+		Subjects:  []string{fmt.Sprintf("%s.>", config.EventTypePrefix)},
+		Storage:   storage,
+		Replicas:  config.JSStreamReplicas,
+		Retention: retention,
+		MaxMsgs:   config.JSStreamMaxMessages,
+		MaxBytes:  config.JSStreamMaxBytes,
+	}, nil
+}
+
+// validateStreamConfigChange returns an error if the storage type or the retention policy of the stream changed, they
+// can't be updated in place and recreating the stream would lose the persisted events, hence the change is left to
+// the operator.
+func validateStreamConfigChange(current, desired *nats.StreamConfig) error {
+	if current.Storage != desired.Storage || current.Retention != desired.Retention {
+		return errors.Errorf("JetStream stream %s has the storage type %s and the retention policy %s which can't be changed to %s and %s in place, "+
+			"delete the stream to apply the new configuration", current.Name, current.Storage, current.Retention, desired.Storage, desired.Retention)
+	}
+	return nil
+}
+
+// isStreamConfigEqual compares the stream config fields which can be updated in place.
+func isStreamConfigEqual(current, desired *nats.StreamConfig) bool {
+	if len(current.Subjects) != len(desired.Subjects) {
+		return false
+	}
+	for i := range current.Subjects {
+		if current.Subjects[i] != desired.Subjects[i] {
+			return false
+		}
+	}
+	return current.Replicas == desired.Replicas &&
+		current.MaxMsgs == desired.MaxMsgs &&
+		current.MaxBytes == desired.MaxBytes
+}
+
+func toJetStreamStorageType(storageType string) (nats.StorageType, error) {
+	switch storageType {
+	case JetStreamStorageTypeFile:
+		return nats.FileStorage, nil
+	case JetStreamStorageTypeMemory:
+		return nats.MemoryStorage, nil
+	}
+	return nats.FileStorage, errors.Errorf("invalid JetStream storage type: %s", storageType)
+}
+
+func toJetStreamRetentionPolicy(retentionPolicy string) (nats.RetentionPolicy, error) {
+	switch retentionPolicy {
+	case JetStreamRetentionPolicyLimits:
+		return nats.LimitsPolicy, nil
+	case JetStreamRetentionPolicyInterest:
+		return nats.InterestPolicy, nil
+	}
+	return nats.LimitsPolicy, errors.Errorf("invalid JetStream retention policy: %s", retentionPolicy)
+}
+
+// createDurableName returns a JetStream consumer name for the given subscription key.
+// Consumer names must not contain dots, hence the key is hashed.
+func createDurableName(key string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(key)))[:32]
+}
+
+// isConsumerNotFound returns true if the given error is returned by the NATS server for a missing consumer.
+func isStreamNotFound(err error) bool {
+	return strings.Contains(err.Error(), streamNotFoundErrMessage)
+}
+
+func isConsumerNotFound(err error) bool {
+	return strings.Contains(err.Error(), consumerNotFoundErrMessage)
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/gomega"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestJetStreamSubscription(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5224
+	subscriberPort := 8080
+	subscriberReceiveURL := fmt.Sprintf("http://127.0.0.1:%d/store", subscriberPort)
+	subscriberCheckURL := fmt.Sprintf("http://127.0.0.1:%d/check", subscriberPort)
+
+	// Start NATS server with JetStream enabled
+	storeDir, err := ioutil.TempDir("", "jetstream")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer func() { _ = os.RemoveAll(storeDir) }()
+	natsServer := eventingtesting.RunJetStreamNatsServerOnPort(natsPort, storeDir)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	config := newJetStreamConfig(natsServer.ClientURL())
+	natsClient := NewNats(config, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the stream should be created on initialization
+	ready, err := natsClient.StreamReady()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(ready).To(BeTrue())
+
+	// the storage type of the existing stream can't be changed
+	fileConfig := config
+	fileConfig.JSStreamStorageType = JetStreamStorageTypeFile
+	g.Expect(NewNats(fileConfig, defaultLogger).Initialize(env.Config{})).ShouldNot(Succeed())
+
+	// Create a new subscriber
+	subscriber := eventingtesting.NewSubscriber(fmt.Sprintf(":%d", subscriberPort))
+	subscriber.Start()
+	defer subscriber.Shutdown()
+	g.Expect(subscriber.CheckEvent("", subscriberCheckURL)).Should(Succeed())
+
+	// Create a subscription
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = subscriberReceiveURL
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(natsClient.subscriptions).To(HaveLen(1))
+
+	// a second sync should keep the same durable consumer
+	var natsSubBefore = natsClient.subscriptions[createKey(sub, eventingtesting.OrderCreatedEventType)]
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(natsClient.subscriptions[createKey(sub, eventingtesting.OrderCreatedEventType)]).To(BeIdenticalTo(natsSubBefore))
+
+	data := "sampledata"
+	g.Expect(SendEventToNATS(natsClient, data)).Should(Succeed())
+	g.Expect(subscriber.CheckEvent(fmt.Sprintf("\"%s\"", data), subscriberCheckURL)).Should(Succeed())
+
+	// Simulate a dispatcher outage, the events published meanwhile must be persisted in the stream
+	natsClient.connection.Close()
+
+	publisher := NewNats(config, defaultLogger)
+	g.Expect(publisher.Initialize(env.Config{})).Should(Succeed())
+	missedData := "misseddata"
+	g.Expect(SendEventToNATS(publisher, missedData)).Should(Succeed())
+
+	// The restarted dispatcher attaches to the durable consumer and receives the missed event
+	restartedClient := NewNats(config, defaultLogger)
+	g.Expect(restartedClient.Initialize(env.Config{})).Should(Succeed())
+	_, err = restartedClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(subscriber.CheckEvent(fmt.Sprintf("\"%s\"", missedData), subscriberCheckURL)).Should(Succeed())
+
+	// Deleting the subscription deletes its durable consumer
+	g.Expect(restartedClient.DeleteSubscription(sub)).Should(Succeed())
+	g.Expect(restartedClient.subscriptions).To(BeEmpty())
+	info, err := restartedClient.jsCtx.StreamInfo(config.JSStreamName)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(info.State.Consumers).To(BeZero())
+}
+
+func TestJetStreamDeadLetterSink(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5226
+
+	storeDir, err := ioutil.TempDir("", "jetstream")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer func() { _ = os.RemoveAll(storeDir) }()
+	natsServer := eventingtesting.RunJetStreamNatsServerOnPort(natsPort, storeDir)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	natsClient := NewNats(newJetStreamConfig(natsServer.ClientURL()), defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the sink rejects all the events
+	var attempts int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer sink.Close()
+
+	deadLetters := make(chan http.Header, 1)
+	deadLetterSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLetters <- r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer deadLetterSink.Close()
+
+	// the retry policy of the subscription lowers the max deliveries of the consumer
+	maxAttempts := 2
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	sub.Spec.DeadLetterSink = deadLetterSink.URL
+	sub.Spec.RetryPolicy = &eventingv1alpha1.RetryPolicy{MaxAttempts: &maxAttempts}
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	g.Expect(SendEventToNATS(natsClient, "sampledata")).Should(Succeed())
+
+	var header http.Header
+	g.Eventually(deadLetters, 10*time.Second).Should(Receive(&header))
+	g.Expect(header.Get("ce-" + DeadLetterAttemptsExtension)).To(Equal(fmt.Sprint(maxAttempts)))
+	g.Expect(header.Get("ce-" + DeadLetterStatusExtension)).To(Equal(fmt.Sprint(http.StatusInternalServerError)))
+
+	// the terminated message is not redelivered
+	g.Consistently(func() int32 { return atomic.LoadInt32(&attempts) }, 2*time.Second).Should(BeEquivalentTo(maxAttempts))
+}
+
+func TestJetStreamConsumerLimits(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5227
+
+	storeDir, err := ioutil.TempDir("", "jetstream")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer func() { _ = os.RemoveAll(storeDir) }()
+	natsServer := eventingtesting.RunJetStreamNatsServerOnPort(natsPort, storeDir)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	// the dispatch must not time out while the sink holds the events
+	config := newJetStreamConfig(natsServer.ClientURL())
+	config.JSConsumerAckWait = 10 * time.Second
+	natsClient := NewNats(config, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the sink holds the events until the test ends
+	var received int32
+	release := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer sink.Close()
+	defer close(release)
+
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	durableName := createDurableName(createKey(sub, eventingtesting.OrderCreatedEventType))
+	info, err := natsClient.jsCtx.ConsumerInfo(config.JSStreamName, durableName)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(info.Config.MaxAckPending).To(Equal(defaultMaxInFlight))
+	g.Expect(info.Config.AckWait).To(Equal(config.JSConsumerAckWait))
+
+	// raising the dispatch limits recreates the consumer, the ack wait covers the rate limit of the pending events
+	maxInFlight, maxRatePerSecond := 4, 2
+	sub.Spec.MaxInFlight = &maxInFlight
+	sub.Spec.MaxRatePerSecond = &maxRatePerSecond
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	info, err = natsClient.jsCtx.ConsumerInfo(config.JSStreamName, durableName)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(info.Config.MaxAckPending).To(Equal(maxInFlight))
+	g.Expect(info.Config.AckWait).To(Equal(config.JSConsumerAckWait + 2*time.Second))
+
+	// JetStream does not push more events than the dispatch limit while the sink is busy
+	for i := 0; i < 2*maxInFlight; i++ {
+		g.Expect(SendEventToNATS(natsClient, "sampledata")).Should(Succeed())
+	}
+	g.Eventually(func() int32 { return atomic.LoadInt32(&received) }, 5*time.Second).Should(BeEquivalentTo(maxInFlight))
+	g.Consistently(func() int32 { return atomic.LoadInt32(&received) }, time.Second).Should(BeEquivalentTo(maxInFlight))
+
+	info, err = natsClient.jsCtx.ConsumerInfo(config.JSStreamName, durableName)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(info.NumAckPending).To(Equal(maxInFlight))
+	g.Expect(info.NumPending).To(BeEquivalentTo(maxInFlight))
+}
+
+func TestGetJetStreamAckWait(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(getJetStreamAckWait(30*time.Second, dispatchConfig{maxInFlight: 10}, 1)).To(Equal(30 * time.Second))
+	g.Expect(getJetStreamAckWait(30*time.Second, dispatchConfig{maxInFlight: 10}, 2)).To(Equal(time.Minute))
+	g.Expect(getJetStreamAckWait(30*time.Second, dispatchConfig{maxInFlight: 10, maxRatePerSecond: 4}, 2)).To(Equal(time.Minute + 5*time.Second))
+}
+
+func TestGetJetStreamBackoff(t *testing.T) {
+	g := NewWithT(t)
+
+	config := dispatchConfig{
+		retryPolicy: retryPolicy{backoffType: eventingv1alpha1.BackoffTypeLinear, maxBackoff: 10 * time.Second},
+		ackWait:     30 * time.Second,
+	}
+	g.Expect(getJetStreamBackoff(config, 1)).To(Equal(10 * time.Second))
+
+	// the backoff ends before JetStream redelivers the message
+	config.retryPolicy.maxBackoff = time.Hour
+	g.Expect(getJetStreamBackoff(config, 1)).To(Equal(27 * time.Second))
+}
+
+func TestGetStreamConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config := newJetStreamConfig("")
+	streamConfig, err := getStreamConfig(config)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(streamConfig.Name).To(Equal(config.JSStreamName))
+	g.Expect(streamConfig.Subjects).To(ConsistOf(eventingtesting.EventTypePrefix + ".>"))
+
+	config.JSStreamStorageType = "disk"
+	_, err = getStreamConfig(config)
+	g.Expect(err).Should(HaveOccurred())
+
+	config = newJetStreamConfig("")
+	config.JSStreamRetentionPolicy = "workqueue"
+	_, err = getStreamConfig(config)
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestValidateStreamConfigChange(t *testing.T) {
+	g := NewWithT(t)
+
+	current, err := getStreamConfig(newJetStreamConfig(""))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	desired := *current
+	desired.MaxMsgs = 100
+	g.Expect(validateStreamConfigChange(current, &desired)).Should(Succeed())
+	g.Expect(isStreamConfigEqual(current, &desired)).To(BeFalse())
+
+	desired = *current
+	desired.Storage = nats.FileStorage
+	g.Expect(validateStreamConfigChange(current, &desired)).ShouldNot(Succeed())
+
+	desired = *current
+	desired.Retention = nats.LimitsPolicy
+	g.Expect(validateStreamConfigChange(current, &desired)).ShouldNot(Succeed())
+}
+
+func newJetStreamConfig(url string) env.NatsConfig {
+	return env.NatsConfig{
+		Url:                     url,
+		MaxReconnects:           2,
+		ReconnectWait:           time.Second,
+		EventTypePrefix:         eventingtesting.EventTypePrefix,
+		JetStreamEnabled:        true,
+		JSStreamName:            "kyma",
+		JSStreamStorageType:     JetStreamStorageTypeMemory,
+		JSStreamReplicas:        1,
+		JSStreamRetentionPolicy: JetStreamRetentionPolicyInterest,
+		JSStreamMaxMessages:     -1,
+		JSStreamMaxBytes:        -1,
+		JSConsumerAckWait:       time.Second,
+		JSConsumerMaxDeliver:    5,
+	}
+}
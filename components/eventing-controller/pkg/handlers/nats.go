@@ -6,20 +6,25 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
+	"go.opencensus.io/plugin/ochttp"
 	"go.uber.org/zap"
 
 	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tracing"
 	"github.com/kyma-project/kyma/components/eventing-controller/utils"
 )
 
@@ -37,11 +42,26 @@ type Nats struct {
 	logger        *logger.Logger
 	client        cev2.Client
 	connection    *nats.Conn
+	jsCtx         nats.JetStreamContext
 	subscriptions map[string]*nats.Subscription
+	// subscriptionNames keeps the Kyma subscription each NATS subscription belongs to
+	subscriptionNames map[string]types.NamespacedName
+	// dispatchConfigs keeps the dispatch config each NATS subscription was created with, it is only used in JetStream mode
+	dispatchConfigs map[string]dispatchConfig
+	// pools keeps the worker pool of each Kyma subscription, poolsMutex guards it since the stats are read concurrently
+	pools      map[types.NamespacedName]*workerPool
+	poolsMutex sync.Mutex
 }
 
 func NewNats(config env.NatsConfig, logger *logger.Logger) *Nats {
-	return &Nats{config: config, logger: logger, subscriptions: make(map[string]*nats.Subscription)}
+	return &Nats{
+		config:            config,
+		logger:            logger,
+		subscriptions:     make(map[string]*nats.Subscription),
+		subscriptionNames: make(map[string]types.NamespacedName),
+		dispatchConfigs:   make(map[string]dispatchConfig),
+		pools:             make(map[types.NamespacedName]*workerPool),
+	}
 }
 
 // Initialize creates a connection to NATS.
@@ -55,6 +75,14 @@ func (n *Nats) Initialize(env.Config) (err error) {
 		if n.connection.Status() != nats.CONNECTED {
 			return errors.Errorf("connect to NATS failed status: %v", n.connection.Status())
 		}
+		// the JetStream context is bound to the connection, hence it must be renewed with it
+		n.jsCtx = nil
+	}
+
+	if n.config.JetStreamEnabled && n.jsCtx == nil {
+		if err := n.initJetStream(); err != nil {
+			return err
+		}
 	}
 
 	if n.client != nil {
@@ -75,7 +103,9 @@ func newCloudeventClient(config env.NatsConfig) (cev2.Client, error) {
 		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
 		IdleConnTimeout:     config.IdleConnTimeout,
 	}
-	protocol, err := cev2.NewHTTP(cev2.WithRoundTripper(transport))
+	// the trace context of each request is sent to the sink in both the W3C TraceContext and the B3 headers
+	tracingTransport := &ochttp.Transport{Base: transport, Propagation: &tracing.HTTPFormat{}}
+	protocol, err := cev2.NewHTTP(cev2.WithRoundTripper(tracingTransport))
 	if err != nil {
 		return nil, err
 	}
@@ -97,14 +127,18 @@ func (n *Nats) SyncSubscription(sub *eventingv1alpha1.Subscription, cleaner even
 	// Format logger
 	log := utils.LoggerWithSubscription(n.namedLogger(), sub)
 
-	// Create subscriptions in NATS
-	for _, filter := range filters {
-		subject, err := createSubject(filter, cleaner)
-		if err != nil {
-			log.Errorw("create NATS subject failed", "error", err)
-			return false, err
-		}
+	subjectFilters, err := createSubjectFilters(filters, cleaner)
+	if err != nil {
+		log.Errorw("create NATS subject failed", "error", err)
+		return false, err
+	}
 
+	if n.config.JetStreamEnabled {
+		return false, n.syncJetStreamSubscription(sub, subjectFilters, log)
+	}
+
+	// Create subscriptions in NATS
+	for _, filter := range subjectFilters {
 		if n.connection.Status() != nats.CONNECTED {
 			if err := n.Initialize(env.Config{}); err != nil {
 				log.Errorw("reset NATS connection failed", "status", n.connection.Stats(), "error", err)
@@ -112,12 +146,15 @@ func (n *Nats) SyncSubscription(sub *eventingv1alpha1.Subscription, cleaner even
 			}
 		}
 
-		callback := n.getCallback(sub.Spec.Sink)
-		if natsSub, err := n.connection.Subscribe(subject, callback); err != nil {
+		config := newDispatchConfig(sub, maxTries, filter)
+		pool := n.getWorkerPool(sub, config)
+		callback := pool.wrap(n.getCallback(config, pool))
+		if natsSub, err := n.connection.QueueSubscribe(filter.subject, createQueueGroup(sub), callback); err != nil {
 			log.Errorw("create NATS subscription failed", "error", err)
 			return false, err
 		} else {
-			n.subscriptions[createKey(sub, subject)] = natsSub
+			n.storeSubscription(createKey(sub, filter.subject), sub, natsSub)
+			pool.track(natsSub)
 		}
 	}
 
@@ -146,15 +183,55 @@ func (n *Nats) DeleteSubscription(subscription *eventingv1alpha1.Subscription) e
 				}
 			}
 			if sub.IsValid() {
-				if err := sub.Unsubscribe(); err != nil {
+				// the JetStream consumer may have been deleted by another replica already
+				if err := sub.Unsubscribe(); err != nil && !isConsumerNotFound(err) {
 					log.Errorw("unsubscribe failed", "error", err)
 					return errors.Wrapf(err, "unsubscribe failed")
 				}
 			}
-			delete(n.subscriptions, key)
+			n.removeSubscription(key)
 			log.Infow("unsubscribe succeeded")
 		}
 	}
+	n.removeWorkerPools(matchSubscription(subscription))
+	return nil
+}
+
+// ReleaseSubscription stops dispatching the events of the given Kyma subscription on this replica.
+// Unlike DeleteSubscription it keeps the JetStream consumers, which are shared with the other replicas.
+func (n *Nats) ReleaseSubscription(subscription *eventingv1alpha1.Subscription) error {
+	prefix := createKeyPrefix(subscription) + "."
+	if err := n.releaseSubscriptions(func(key string) bool { return strings.HasPrefix(key, prefix) }); err != nil {
+		return err
+	}
+	n.removeWorkerPools(matchSubscription(subscription))
+	return nil
+}
+
+// ReleaseAllSubscriptions stops dispatching the events of all the Kyma subscriptions on this replica.
+func (n *Nats) ReleaseAllSubscriptions() error {
+	if err := n.releaseSubscriptions(func(string) bool { return true }); err != nil {
+		return err
+	}
+	n.removeWorkerPools(func(types.NamespacedName) bool { return true })
+	return nil
+}
+
+func (n *Nats) releaseSubscriptions(match func(key string) bool) error {
+	for key, sub := range n.subscriptions {
+		if !match(key) {
+			continue
+		}
+		// draining lets the messages in flight be dispatched and does not delete durable consumers
+		if sub.IsValid() {
+			if err := sub.Drain(); err != nil {
+				n.namedLogger().Errorw("drain NATS subscription failed", "key", key, "error", err)
+				return errors.Wrapf(err, "drain failed")
+			}
+		}
+		n.removeSubscription(key)
+		n.namedLogger().Debugw("release NATS subscription succeeded", "key", key)
+	}
 	return nil
 }
 
@@ -164,13 +241,29 @@ func (n *Nats) GetInvalidSubscriptions() *[]types.NamespacedName {
 	for k, v := range n.subscriptions {
 		if !v.IsValid() {
 			n.namedLogger().Debugw("invalid NATS subscription", "key", k, "subject", v.Subject)
-			nsn = append(nsn, createKymaSubscriptionNamespacedName(k, v))
+			nsn = append(nsn, n.subscriptionNames[k])
 		}
 	}
 	return &nsn
 }
 
-func (n *Nats) getCallback(sink string) nats.MsgHandler {
+// storeSubscription keeps the NATS subscription together with the name of the Kyma subscription it belongs to.
+// The name can't be derived from the NATS subscription, in JetStream mode its subject is the deliver inbox.
+func (n *Nats) storeSubscription(key string, sub *eventingv1alpha1.Subscription, natsSub *nats.Subscription) {
+	if n.subscriptionNames == nil {
+		n.subscriptionNames = make(map[string]types.NamespacedName)
+	}
+	n.subscriptions[key] = natsSub
+	n.subscriptionNames[key] = types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+}
+
+func (n *Nats) removeSubscription(key string) {
+	delete(n.subscriptions, key)
+	delete(n.subscriptionNames, key)
+	delete(n.dispatchConfigs, key)
+}
+
+func (n *Nats) getCallback(config dispatchConfig, pool *workerPool) nats.MsgHandler {
 	return func(msg *nats.Msg) {
 		ce, err := convertMsgToCE(msg)
 		if err != nil {
@@ -178,23 +271,52 @@ func (n *Nats) getCallback(sink string) nats.MsgHandler {
 			return
 		}
 
-		// Creating a context with cancellable
-		ctxWithCancel, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		// the NATS subject covers the event type only, the other filters are applied before dispatch
+		if !config.filter.match(ce) {
+			n.namedLogger().Debugw("event filtered out", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink)
+			return
+		}
 
-		// Creating a context with retries
-		ctxWithRetries := cev2.ContextWithRetriesExponentialBackoff(ctxWithCancel, period, maxTries)
+		n.dispatch(ce, config, pool, 1)
+	}
+}
 
-		// Creating a context with target
-		ctxWithCE := cev2.ContextWithTarget(ctxWithRetries, sink)
+// dispatch makes the given dispatch attempt. A failed attempt is retried after the backoff of the retry policy,
+// the retry is resubmitted to the worker pool from a timer so that the workers and the NATS delivery are not
+// blocked meanwhile. Core NATS does not redeliver, hence the retries which are pending once the pool stops are
+// abandoned, they are counted and forwarded to the dead-letter sink.
+func (n *Nats) dispatch(ce *cev2event.Event, config dispatchConfig, pool *workerPool, attempt int) {
+	result := n.send(ce, config.sink, attempt)
+	if cev2.IsACK(result) {
+		n.namedLogger().Infow("event dispatched", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink, "attempts", attempt)
+		return
+	}
 
-		if result := n.client.Send(ctxWithCE, *ce); !cev2.IsACK(result) {
-			n.namedLogger().Errorw("event dispatch failed", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", sink, "error", result)
-			return
+	abandon := func() {
+		metrics.RecordAbandonedRetry()
+		n.namedLogger().Errorw("event retry abandoned, subscription stopped", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink, "attempts", attempt, "error", result)
+		n.sendToDeadLetterSink(ce, config, attempt, result)
+	}
+	if attempt < config.retryPolicy.maxAttempts {
+		retry := dispatchTask{handler: func(*nats.Msg) { n.dispatch(ce, config, pool, attempt+1) }}
+		if !pool.retry(config.retryPolicy.backoff(attempt), retry, abandon) {
+			abandon()
 		}
-
-		n.namedLogger().Infow("event dispatched", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", sink)
+		return
 	}
+
+	n.namedLogger().Errorw("event dispatch failed", "id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", config.sink, "attempts", attempt, "error", result)
+	n.sendToDeadLetterSink(ce, config, attempt, result)
+}
+
+// send sends the given event to the given sink within a span which continues the trace carried by the event.
+func (n *Nats) send(ce *cev2event.Event, sink string, attempt int) cev2protocol.Result {
+	ctx, span := tracing.StartDispatchSpan(context.Background(), tracing.DispatchSpanName, ce, sink, attempt)
+	defer span.End()
+
+	result := n.client.Send(cev2.ContextWithTarget(ctx, sink), *ce)
+	tracing.SetSpanStatus(span, result)
+	return result
 }
 
 func (n *Nats) namedLogger() *zap.SugaredLogger {
@@ -221,24 +343,12 @@ func createKeyPrefix(sub *eventingv1alpha1.Subscription) string {
 	return fmt.Sprintf("%s", namespacedName.String())
 }
 
-func createKey(sub *eventingv1alpha1.Subscription, subject string) string {
-	return fmt.Sprintf("%s.%s", createKeyPrefix(sub), subject)
-}
-
-func createSubject(filter *eventingv1alpha1.BebFilter, cleaner eventtype.Cleaner) (string, error) {
-	eventType := strings.TrimSpace(filter.EventType.Value)
-	if len(eventType) == 0 {
-		return "", nats.ErrBadSubject
-	}
-	// clean the application name segment in the event-type from none-alphanumeric characters
-	// return it as a NATS subject
-	return cleaner.Clean(eventType)
+// createQueueGroup returns the NATS queue group of the given Kyma subscription, all the replicas dispatching
+// the events of a Kyma subscription join the same queue group so that each event is dispatched once.
+func createQueueGroup(sub *eventingv1alpha1.Subscription) string {
+	return createKeyPrefix(sub)
 }
 
-func createKymaSubscriptionNamespacedName(key string, sub *nats.Subscription) types.NamespacedName {
-	nsn := types.NamespacedName{}
-	nnvalues := strings.Split(strings.TrimSuffix(strings.TrimSuffix(key, sub.Subject), "."), string(types.Separator))
-	nsn.Namespace = nnvalues[0]
-	nsn.Name = nnvalues[1]
-	return nsn
+func createKey(sub *eventingv1alpha1.Subscription, subject string) string {
+	return fmt.Sprintf("%s.%s", createKeyPrefix(sub), subject)
 }
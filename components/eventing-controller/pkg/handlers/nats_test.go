@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -181,6 +184,61 @@ func TestSubscription(t *testing.T) {
 	}
 }
 
+func TestSubscriptionWithQueueGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5227
+
+	natsServer := eventingtesting.RunNatsServerOnPort(natsPort)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	// the sink counts the events it receives
+	var received int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer sink.Close()
+
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	idFunc := func(et string) (string, error) { return et, nil }
+
+	// every replica dispatches the same subscription
+	config := env.NatsConfig{
+		Url:           natsServer.ClientURL(),
+		MaxReconnects: 2,
+		ReconnectWait: time.Second,
+	}
+	var replicas []*Nats
+	for i := 0; i < 2; i++ {
+		replica := NewNats(config, defaultLogger)
+		g.Expect(replica.Initialize(env.Config{})).Should(Succeed())
+		_, err := replica.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		replicas = append(replicas, replica)
+	}
+
+	// each event is dispatched by one replica only
+	events := 10
+	for i := 0; i < events; i++ {
+		g.Expect(SendEventToNATS(replicas[0], "sampledata")).Should(Succeed())
+	}
+	g.Eventually(func() int32 { return atomic.LoadInt32(&received) }, 5*time.Second).Should(BeEquivalentTo(events))
+	g.Consistently(func() int32 { return atomic.LoadInt32(&received) }, time.Second).Should(BeEquivalentTo(events))
+
+	// the remaining replica dispatches all the events once the other one released the subscription
+	g.Expect(replicas[1].ReleaseSubscription(sub)).Should(Succeed())
+	g.Expect(replicas[1].subscriptions).To(BeEmpty())
+	for i := 0; i < events; i++ {
+		g.Expect(SendEventToNATS(replicas[1], "sampledata")).Should(Succeed())
+	}
+	g.Eventually(func() int32 { return atomic.LoadInt32(&received) }, 5*time.Second).Should(BeEquivalentTo(2 * events))
+}
+
 func TestSubscriptionWithDuplicateFilters(t *testing.T) {
 	g := NewWithT(t)
 
@@ -315,7 +373,7 @@ func TestIsValidSubscription(t *testing.T) {
 	g.Expect(natsSub).To(Not(BeNil()))
 
 	// check the mapping of Kyma subscription and Nats subscription
-	nsn := createKymaSubscriptionNamespacedName(key, natsSub)
+	nsn := natsClient.subscriptionNames[key]
 	g.Expect(nsn.Namespace).To(BeIdenticalTo(sub.Namespace))
 	g.Expect(nsn.Name).To(BeIdenticalTo(sub.Name))
 
@@ -85,6 +85,15 @@ func getInternalView4Ev2(subscription *eventingv1alpha1.Subscription, apiRule *a
 	// Name
 	emsSubscription.Name = subscription.Name
 
+	// BEB has no configurable retries, an event is either retried by BEB (AT_LEAST_ONCE) or not (AT_MOST_ONCE).
+	// The backoff and the dead-letter sink do not have an equivalent in BEB.
+	if subscription.Spec.RetryPolicy != nil && subscription.Spec.RetryPolicy.MaxAttempts != nil {
+		emsSubscription.Qos = types.QosAtLeastOnce
+		if *subscription.Spec.RetryPolicy.MaxAttempts <= 1 {
+			emsSubscription.Qos = types.QosAtMostOnce
+		}
+	}
+
 	// Applying protocol settings if provided in subscription CR
 	if subscription.Spec.ProtocolSettings != nil {
 		if subscription.Spec.ProtocolSettings.ContentMode != nil {
@@ -145,6 +145,32 @@ func TestGetInternalView4Ev2(t *testing.T) {
 		g.Expect(err).To(BeNil())
 		g.Expect(expectedBEBSubscription).To(Equal(*gotBEBSubscription))
 	})
+
+	t.Run("subscription with retry policy", func(t *testing.T) {
+		// given
+		subscription := reconcilertesting.NewSubscription("name", "namespace", eventingtesting.WithEventTypeFilter)
+		eventingtesting.WithValidSink("ns", svcName, subscription)
+		maxAttempts := 1
+		subscription.Spec.RetryPolicy = &eventingv1alpha1.RetryPolicy{MaxAttempts: &maxAttempts}
+
+		apiRule := reconcilertesting.NewAPIRule(subscription, reconcilertesting.WithPath)
+		reconcilertesting.WithService(host, svcName, apiRule)
+
+		// then
+		gotBEBSubscription, err := getInternalView4Ev2(subscription, apiRule, defaultWebhookAuth, defaultProtocolSettings, defaultNamespace)
+
+		// when
+		g.Expect(err).To(BeNil())
+		// a single attempt means BEB must not retry the event
+		g.Expect(gotBEBSubscription.Qos).To(Equal(types.QosAtMostOnce))
+
+		// the qos given in the protocol settings wins over the retry policy
+		qos := string(types.QosAtLeastOnce)
+		subscription.Spec.ProtocolSettings = &eventingv1alpha1.ProtocolSettings{Qos: &qos}
+		gotBEBSubscription, err = getInternalView4Ev2(subscription, apiRule, defaultWebhookAuth, defaultProtocolSettings, defaultNamespace)
+		g.Expect(err).To(BeNil())
+		g.Expect(gotBEBSubscription.Qos).To(Equal(types.QosAtLeastOnce))
+	})
 }
 
 func TestGetInternalView4Ems(t *testing.T) {
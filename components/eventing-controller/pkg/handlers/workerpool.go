@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+)
+
+const (
+	// drainPollInterval is the period of checking whether the NATS subscriptions of a stopped pool are drained
+	drainPollInterval = 100 * time.Millisecond
+)
+
+// DispatchStats is the dispatch state of a Kyma subscription on this replica.
+type DispatchStats struct {
+	// PendingMessages is the number of events received from NATS which were not dispatched yet
+	PendingMessages int64
+	// SlowConsumer is true if NATS dropped events because the dispatch could not keep up with them
+	SlowConsumer bool
+}
+
+// dispatchTask is a NATS message waiting for a worker with the handler which dispatches it.
+type dispatchTask struct {
+	msg     *nats.Msg
+	handler nats.MsgHandler
+}
+
+// workerPool dispatches the events of a Kyma subscription with a bounded number of workers.
+// The NATS message handlers block while all the workers are busy and the queue is full, hence NATS buffers the
+// messages of the subscription and flags it as a slow consumer once its pending limits are exceeded.
+type workerPool struct {
+	maxInFlight      int
+	maxRatePerSecond int
+	limiter          *rate.Limiter
+	tasks            chan dispatchTask
+	done             chan struct{}
+	// pending is the number of tasks which are waiting for the queue, queued or being dispatched
+	pending int64
+	// waiting is the number of tasks which are waiting for the queue, their messages are still counted as
+	// pending by NATS since the message handlers did not return yet
+	waiting int64
+
+	// mutex guards the NATS subscriptions which feed the pool and the retries
+	mutex         sync.Mutex
+	subscriptions []*nats.Subscription
+	// retries are the timers of the failed dispatch attempts which wait for their backoff, each with the function
+	// called if the retry is abandoned since the pool stopped
+	retries map[*time.Timer]func()
+	stopped bool
+}
+
+func newWorkerPool(maxInFlight, maxRatePerSecond int) *workerPool {
+	p := &workerPool{
+		maxInFlight:      maxInFlight,
+		maxRatePerSecond: maxRatePerSecond,
+		tasks:            make(chan dispatchTask, maxInFlight),
+		done:             make(chan struct{}),
+		retries:          make(map[*time.Timer]func()),
+	}
+	if maxRatePerSecond > 0 {
+		p.limiter = rate.NewLimiter(rate.Limit(maxRatePerSecond), 1)
+	}
+	for i := 0; i < maxInFlight; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// wrap returns a NATS message handler which hands the messages over to the pool workers.
+func (p *workerPool) wrap(handler nats.MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		p.submit(dispatchTask{msg: msg, handler: handler})
+	}
+}
+
+// submit blocks until a worker is available, the task is dropped if the pool is stopped.
+func (p *workerPool) submit(task dispatchTask) bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+	}
+	atomic.AddInt64(&p.pending, 1)
+	atomic.AddInt64(&p.waiting, 1)
+	defer atomic.AddInt64(&p.waiting, -1)
+	select {
+	case p.tasks <- task:
+		return true
+	case <-p.done:
+		atomic.AddInt64(&p.pending, -1)
+		return false
+	}
+}
+
+// retry submits the given task once the given delay elapsed, the given abandon function is called instead if the pool
+// stops meanwhile. It returns false if the pool is stopped already.
+func (p *workerPool) retry(delay time.Duration, task dispatchTask, abandon func()) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.stopped {
+		return false
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		p.mutex.Lock()
+		delete(p.retries, timer)
+		p.mutex.Unlock()
+		if !p.submit(task) {
+			abandon()
+		}
+	})
+	p.retries[timer] = abandon
+	return true
+}
+
+func (p *workerPool) work() {
+	for {
+		select {
+		case task := <-p.tasks:
+			p.run(task)
+		case <-p.done:
+			// finish the queued tasks, the NATS subscriptions do not feed the pool anymore
+			for {
+				select {
+				case task := <-p.tasks:
+					p.run(task)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *workerPool) run(task dispatchTask) {
+	defer atomic.AddInt64(&p.pending, -1)
+	if p.limiter != nil {
+		// waiting never fails since the context is not cancelled and the burst is positive
+		_ = p.limiter.Wait(context.Background())
+	}
+	task.handler(task.msg)
+}
+
+// track adds a NATS subscription to the ones which are accounted in the pool stats.
+func (p *workerPool) track(sub *nats.Subscription) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.subscriptions = append(p.subscriptions, sub)
+}
+
+// stats returns the pending messages of the pool and of its NATS subscriptions which are still valid.
+func (p *workerPool) stats() DispatchStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats := DispatchStats{PendingMessages: atomic.LoadInt64(&p.pending)}
+	valid := p.subscriptions[:0]
+	for _, sub := range p.subscriptions {
+		if !sub.IsValid() {
+			continue
+		}
+		valid = append(valid, sub)
+		if msgs, _, err := sub.Pending(); err == nil {
+			stats.PendingMessages += int64(msgs)
+		}
+		if dropped, err := sub.Dropped(); err == nil && dropped > 0 {
+			stats.SlowConsumer = true
+		}
+	}
+	if len(valid) > 0 {
+		// the waiting tasks are already counted as pending by the NATS subscriptions
+		stats.PendingMessages -= atomic.LoadInt64(&p.waiting)
+	}
+	p.subscriptions = valid
+	return stats
+}
+
+// stop stops feeding the pool, the queued tasks are still dispatched. The pool keeps accepting tasks until its NATS
+// subscriptions are drained or the NATS drain timeout elapsed, core NATS does not redeliver the messages which
+// are dropped by a stopped pool. The pending retries are abandoned right away, since their backoff may be longer than
+// the drain.
+func (p *workerPool) stop() {
+	p.abandonRetries()
+	if !p.feeding() {
+		close(p.done)
+		return
+	}
+	go func() {
+		deadline := time.Now().Add(nats.DefaultDrainTimeout)
+		for p.feeding() && time.Now().Before(deadline) {
+			time.Sleep(drainPollInterval)
+		}
+		close(p.done)
+	}()
+}
+
+// abandonRetries stops the timers of the pending retries and calls their abandon functions, the retries whose timers
+// fired already are submitted or abandoned by the timers.
+func (p *workerPool) abandonRetries() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.stopped = true
+	for timer, abandon := range p.retries {
+		if timer.Stop() {
+			// the abandon functions may block, e.g. by forwarding the event to the dead-letter sink
+			go abandon()
+		}
+		delete(p.retries, timer)
+	}
+}
+
+// feeding returns true if any of the NATS subscriptions of the pool is still delivering messages.
+func (p *workerPool) feeding() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, sub := range p.subscriptions {
+		if sub.IsValid() {
+			return true
+		}
+	}
+	return false
+}
+
+// getWorkerPool returns the worker pool of the given Kyma subscription, the pool is replaced if the
+// dispatch limits of the given config changed.
+func (n *Nats) getWorkerPool(sub *eventingv1alpha1.Subscription, config dispatchConfig) *workerPool {
+	key := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+	n.poolsMutex.Lock()
+	defer n.poolsMutex.Unlock()
+	if n.pools == nil {
+		n.pools = make(map[types.NamespacedName]*workerPool)
+	}
+	if p, ok := n.pools[key]; ok {
+		if p.maxInFlight == config.maxInFlight && p.maxRatePerSecond == config.maxRatePerSecond {
+			return p
+		}
+		p.stop()
+	}
+	p := newWorkerPool(config.maxInFlight, config.maxRatePerSecond)
+	n.pools[key] = p
+	return p
+}
+
+// removeWorkerPools stops and removes the worker pools of the Kyma subscriptions which match.
+func (n *Nats) removeWorkerPools(match func(key types.NamespacedName) bool) {
+	n.poolsMutex.Lock()
+	defer n.poolsMutex.Unlock()
+	for key, p := range n.pools {
+		if match(key) {
+			p.stop()
+			delete(n.pools, key)
+		}
+	}
+}
+
+// GetDispatchStats returns the dispatch state of the Kyma subscriptions dispatched on this replica.
+// It is safe to call it concurrently with the other methods.
+func (n *Nats) GetDispatchStats() map[types.NamespacedName]DispatchStats {
+	n.poolsMutex.Lock()
+	defer n.poolsMutex.Unlock()
+	stats := make(map[types.NamespacedName]DispatchStats, len(n.pools))
+	for key, p := range n.pools {
+		stats[key] = p.stats()
+	}
+	return stats
+}
+
+// matchSubscription returns a matcher for the worker pool of the given Kyma subscription.
+func matchSubscription(sub *eventingv1alpha1.Subscription) func(key types.NamespacedName) bool {
+	return func(key types.NamespacedName) bool {
+		return key.Namespace == sub.Namespace && key.Name == sub.Name
+	}
+}
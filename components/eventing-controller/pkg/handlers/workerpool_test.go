@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestWorkerPoolMaxInFlight(t *testing.T) {
+	g := NewWithT(t)
+
+	maxInFlight := 3
+	pool := newWorkerPool(maxInFlight, 0)
+	defer pool.stop()
+
+	// the handler blocks until it is released and records the highest concurrency
+	var inFlight, maxObserved int32
+	release := make(chan struct{})
+	handler := func(*nats.Msg) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			observed := atomic.LoadInt32(&maxObserved)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxObserved, observed, current) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+	}
+
+	// the submissions block once the workers are busy and the queue is full
+	tasks := 10
+	var submitted int32
+	go func() {
+		for i := 0; i < tasks; i++ {
+			pool.submit(dispatchTask{msg: &nats.Msg{}, handler: handler})
+			atomic.AddInt32(&submitted, 1)
+		}
+	}()
+	g.Eventually(func() int32 { return atomic.LoadInt32(&inFlight) }).Should(BeEquivalentTo(maxInFlight))
+	g.Consistently(func() int32 { return atomic.LoadInt32(&submitted) }).Should(BeEquivalentTo(2 * maxInFlight))
+	g.Expect(pool.stats().PendingMessages).To(BeEquivalentTo(2*maxInFlight + 1))
+
+	close(release)
+	g.Eventually(func() int64 { return pool.stats().PendingMessages }).Should(BeZero())
+	g.Expect(atomic.LoadInt32(&submitted)).To(BeEquivalentTo(tasks))
+	g.Expect(atomic.LoadInt32(&maxObserved)).To(BeEquivalentTo(maxInFlight))
+}
+
+func TestWorkerPoolMaxRatePerSecond(t *testing.T) {
+	g := NewWithT(t)
+
+	maxRatePerSecond := 20
+	pool := newWorkerPool(5, maxRatePerSecond)
+	defer pool.stop()
+
+	var wg sync.WaitGroup
+	tasks := 10
+	wg.Add(tasks)
+	start := time.Now()
+	for i := 0; i < tasks; i++ {
+		pool.submit(dispatchTask{msg: &nats.Msg{}, handler: func(*nats.Msg) { wg.Done() }})
+	}
+	wg.Wait()
+
+	// the first task is not delayed, the following ones are spread evenly
+	minDuration := time.Duration(tasks-1) * time.Second / time.Duration(maxRatePerSecond)
+	g.Expect(time.Since(start)).To(BeNumerically(">=", minDuration))
+}
+
+func TestWorkerPoolStop(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := newWorkerPool(1, 0)
+
+	// the queued tasks are dispatched after the pool is stopped, the new ones are rejected
+	var dispatched int32
+	release := make(chan struct{})
+	handler := func(*nats.Msg) {
+		<-release
+		atomic.AddInt32(&dispatched, 1)
+	}
+	g.Expect(pool.submit(dispatchTask{msg: &nats.Msg{}, handler: handler})).To(BeTrue())
+	g.Expect(pool.submit(dispatchTask{msg: &nats.Msg{}, handler: handler})).To(BeTrue())
+	pool.stop()
+	g.Expect(pool.submit(dispatchTask{msg: &nats.Msg{}, handler: handler})).To(BeFalse())
+
+	close(release)
+	g.Eventually(func() int32 { return atomic.LoadInt32(&dispatched) }).Should(BeEquivalentTo(2))
+}
+
+func TestWorkerPoolStopAbandonsRetries(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := newWorkerPool(1, 0)
+
+	// the pending retries are abandoned once the pool is stopped, the new ones are rejected
+	var dispatched, abandoned int32
+	retry := dispatchTask{msg: &nats.Msg{}, handler: func(*nats.Msg) { atomic.AddInt32(&dispatched, 1) }}
+	abandon := func() { atomic.AddInt32(&abandoned, 1) }
+	g.Expect(pool.retry(time.Millisecond, retry, abandon)).To(BeTrue())
+	g.Eventually(func() int32 { return atomic.LoadInt32(&dispatched) }).Should(BeEquivalentTo(1))
+
+	g.Expect(pool.retry(time.Hour, retry, abandon)).To(BeTrue())
+	g.Expect(pool.retry(time.Hour, retry, abandon)).To(BeTrue())
+	pool.stop()
+	g.Expect(pool.retry(time.Millisecond, retry, abandon)).To(BeFalse())
+
+	g.Eventually(func() int32 { return atomic.LoadInt32(&abandoned) }).Should(BeEquivalentTo(2))
+	g.Consistently(func() int32 { return atomic.LoadInt32(&dispatched) }).Should(BeEquivalentTo(1))
+}
+
+func TestSubscriptionWithMaxInFlight(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5229
+
+	natsServer := eventingtesting.RunNatsServerOnPort(natsPort)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	natsClient := NewNats(env.NatsConfig{
+		Url:           natsServer.ClientURL(),
+		MaxReconnects: 2,
+		ReconnectWait: time.Second,
+	}, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the sink blocks until it is released and records the highest concurrency
+	var inFlight, maxObserved, received int32
+	release := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			observed := atomic.LoadInt32(&maxObserved)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxObserved, observed, current) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer sink.Close()
+	var releaseOnce sync.Once
+	releaseSink := func() { releaseOnce.Do(func() { close(release) }) }
+	defer releaseSink()
+
+	maxInFlight := 2
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	sub.Spec.MaxInFlight = &maxInFlight
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	events := 10
+	for i := 0; i < events; i++ {
+		g.Expect(SendEventToNATS(natsClient, "sampledata")).Should(Succeed())
+	}
+
+	// the events which are not dispatched yet are reported as pending
+	key := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+	g.Eventually(func() int32 { return atomic.LoadInt32(&inFlight) }, 5*time.Second).Should(BeEquivalentTo(maxInFlight))
+	g.Eventually(func() int64 { return natsClient.GetDispatchStats()[key].PendingMessages }).Should(BeEquivalentTo(events))
+	g.Expect(natsClient.GetDispatchStats()[key].SlowConsumer).To(BeFalse())
+
+	releaseSink()
+	g.Eventually(func() int32 { return atomic.LoadInt32(&received) }, 5*time.Second).Should(BeEquivalentTo(events))
+	g.Eventually(func() int64 { return natsClient.GetDispatchStats()[key].PendingMessages }).Should(BeZero())
+	g.Expect(atomic.LoadInt32(&maxObserved)).To(BeEquivalentTo(maxInFlight))
+
+	// the worker pool is removed with the subscription
+	g.Expect(natsClient.DeleteSubscription(sub)).Should(Succeed())
+	g.Expect(natsClient.GetDispatchStats()).To(BeEmpty())
+}
+
+func TestReleaseSubscriptionDrainsWorkerPool(t *testing.T) {
+	g := NewWithT(t)
+
+	natsPort := 5231
+
+	natsServer := eventingtesting.RunNatsServerOnPort(natsPort)
+	defer eventingtesting.ShutDownNATSServer(natsServer)
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	natsClient := NewNats(env.NatsConfig{
+		Url:           natsServer.ClientURL(),
+		MaxReconnects: 2,
+		ReconnectWait: time.Second,
+	}, defaultLogger)
+	g.Expect(natsClient.Initialize(env.Config{})).Should(Succeed())
+
+	// the sink blocks until it is released
+	var inFlight, received int32
+	release := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&inFlight, 1)
+		<-release
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer sink.Close()
+
+	sub := eventingtesting.NewSubscription("sub", "foo", eventingtesting.WithEventTypeFilter)
+	sub.Spec.Sink = sink.URL
+	idFunc := func(et string) (string, error) { return et, nil }
+	_, err = natsClient.SyncSubscription(sub, eventtype.CleanerFunc(idFunc))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	events := 5
+	for i := 0; i < events; i++ {
+		g.Expect(SendEventToNATS(natsClient, "sampledata")).Should(Succeed())
+	}
+	g.Eventually(func() int32 { return atomic.LoadInt32(&inFlight) }, 5*time.Second).Should(BeEquivalentTo(1))
+
+	// the events received before the release are still dispatched
+	g.Expect(natsClient.ReleaseSubscription(sub)).Should(Succeed())
+	close(release)
+	g.Eventually(func() int32 { return atomic.LoadInt32(&received) }, 5*time.Second).Should(BeEquivalentTo(events))
+}
@@ -0,0 +1,73 @@
+// Package metrics provides the Prometheus metrics of the eventing controller.
+// The metrics are registered in the controller-runtime registry, hence they are served by the manager metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// PendingMessages name of the pending messages metric
+	PendingMessages = "eventing_nats_subscription_pending_messages"
+	// SlowConsumer name of the slow consumer metric
+	SlowConsumer = "eventing_nats_subscription_slow_consumer"
+	// pendingMessagesHelp help for the pending messages metric
+	pendingMessagesHelp = "The number of events received from NATS which were not dispatched to the subscription sink yet"
+	// AbandonedRetries name of the abandoned retries metric
+	AbandonedRetries = "eventing_nats_abandoned_retries_total"
+	// slowConsumerHelp help for the slow consumer metric
+	slowConsumerHelp = "Whether NATS dropped events because the dispatch to the subscription sink could not keep up"
+	// abandonedRetriesHelp help for the abandoned retries metric
+	abandonedRetriesHelp = "The total number of pending dispatch retries of core NATS events abandoned since their subscription stopped"
+)
+
+var (
+	pendingMessages = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: PendingMessages,
+			Help: pendingMessagesHelp,
+		},
+		[]string{"namespace", "name"},
+	)
+	slowConsumer = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: SlowConsumer,
+			Help: slowConsumerHelp,
+		},
+		[]string{"namespace", "name"},
+	)
+	// abandonedRetries is not labeled with the subscription, since the retries are abandoned once it's deleted
+	abandonedRetries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: AbandonedRetries,
+			Help: abandonedRetriesHelp,
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(pendingMessages, slowConsumer, abandonedRetries)
+}
+
+// RecordDispatchStats records the dispatch metrics of the given subscription.
+func RecordDispatchStats(key types.NamespacedName, pending int64, isSlowConsumer bool) {
+	pendingMessages.WithLabelValues(key.Namespace, key.Name).Set(float64(pending))
+	value := 0.0
+	if isSlowConsumer {
+		value = 1
+	}
+	slowConsumer.WithLabelValues(key.Namespace, key.Name).Set(value)
+}
+
+// DeleteDispatchStats deletes the dispatch metrics of the given subscription.
+func DeleteDispatchStats(key types.NamespacedName) {
+	pendingMessages.DeleteLabelValues(key.Namespace, key.Name)
+	slowConsumer.DeleteLabelValues(key.Namespace, key.Name)
+}
+
+// RecordAbandonedRetry records a dispatch retry abandoned since its subscription stopped.
+func RecordAbandonedRetry() {
+	abandonedRetries.Inc()
+}
@@ -0,0 +1,185 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const (
+	exporterName = "otlp-exporter"
+
+	// tracesPath is the path of the OTLP/HTTP traces endpoint of the collector
+	tracesPath = "/v1/traces"
+	// shutdownTimeout is the timeout for exporting the remaining spans once the exporter is stopped
+	shutdownTimeout = 10 * time.Second
+
+	// instrumentationName is the name of the instrumentation library of the exported spans
+	instrumentationName = "github.com/kyma-project/kyma/components/eventing-controller"
+)
+
+// compile time check
+var _ trace.Exporter = &Exporter{}
+
+// Exporter is an OpenCensus trace exporter which records the spans again with an OpenTelemetry tracer, whose batch span
+// processor sends them to an OpenTelemetry collector using the OTLP/HTTP protocol. It runs as a manager runnable
+// on all the replicas.
+type Exporter struct {
+	provider *sdktrace.TracerProvider
+	tracer   oteltrace.Tracer
+}
+
+// spanContextKey is the context key of the OpenCensus span context, whose IDs are kept by the recorded span
+type spanContextKey struct{}
+
+// spanIDs is the IDGenerator which returns the IDs of the OpenCensus span which is recorded
+type spanIDs struct{}
+
+// NewIDs implements the IDGenerator interface for the spans without a parent.
+func (spanIDs) NewIDs(ctx context.Context) (oteltrace.TraceID, oteltrace.SpanID) {
+	sc, _ := ctx.Value(spanContextKey{}).(oteltrace.SpanContext)
+	return sc.TraceID(), sc.SpanID()
+}
+
+// NewSpanID implements the IDGenerator interface for the spans with a parent.
+func (spanIDs) NewSpanID(ctx context.Context, _ oteltrace.TraceID) oteltrace.SpanID {
+	sc, _ := ctx.Value(spanContextKey{}).(oteltrace.SpanContext)
+	return sc.SpanID()
+}
+
+// NewExporter returns a new Exporter instance which sends the spans to the OTLP/HTTP collector at the given endpoint.
+// The export failures are logged with the given logger.
+func NewExporter(endpoint, serviceName string, logger *logger.Logger) (*Exporter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %s: %v", endpoint, err)
+	}
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpointURL.Host),
+		otlptracehttp.WithURLPath(path.Join("/", endpointURL.Path, tracesPath)),
+	}
+	if endpointURL.Scheme != "https" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	// the client connects lazily, hence creating the exporter does not fail while the collector is not available
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the batch span processor reports the export failures to the global OpenTelemetry error handler
+	namedLogger := logger.WithContext().Named(exporterName)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		namedLogger.Errorw("export spans failed", "endpoint", endpoint, "error", err)
+	}))
+
+	// the OpenCensus spans are sampled already, so all of them are recorded with their IDs
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithIDGenerator(spanIDs{}),
+	)
+	return &Exporter{provider: provider, tracer: provider.Tracer(instrumentationName)}, nil
+}
+
+// ExportSpan implements the trace.Exporter interface, the span is queued until the next batch is exported.
+func (e *Exporter) ExportSpan(span *trace.SpanData) {
+	e.record(span)
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the events are dispatched by all the replicas.
+func (e *Exporter) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements the Runnable interface, it exports the queued spans once the context is done.
+func (e *Exporter) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	// export the remaining spans with a fresh context since the given one is done
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return e.provider.Shutdown(shutdownCtx)
+}
+
+// record records the given OpenCensus span as the OpenTelemetry span which is exported.
+func (e *Exporter) record(span *trace.SpanData) {
+	spanContext := toSpanContext(span.SpanContext)
+	ctx := context.WithValue(context.Background(), spanContextKey{}, spanContext)
+	if span.ParentSpanID != (trace.SpanID{}) {
+		parent := spanContext.WithSpanID(oteltrace.SpanID(span.ParentSpanID))
+		if span.HasRemoteParent {
+			ctx = oteltrace.ContextWithRemoteSpanContext(ctx, parent)
+		} else {
+			ctx = oteltrace.ContextWithSpanContext(ctx, parent)
+		}
+	}
+
+	_, recorded := e.tracer.Start(ctx, span.Name,
+		oteltrace.WithTimestamp(span.StartTime),
+		oteltrace.WithSpanKind(toSpanKind(span.SpanKind)),
+		oteltrace.WithAttributes(toAttributes(span.Attributes)...),
+	)
+	for _, annotation := range span.Annotations {
+		recorded.AddEvent(annotation.Message,
+			oteltrace.WithTimestamp(annotation.Time),
+			oteltrace.WithAttributes(toAttributes(annotation.Attributes)...),
+		)
+	}
+	if span.Code != trace.StatusCodeOK {
+		recorded.SetStatus(codes.Error, span.Message)
+	}
+	recorded.End(oteltrace.WithTimestamp(span.EndTime))
+}
+
+func toSpanContext(sc trace.SpanContext) oteltrace.SpanContext {
+	return oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID(sc.TraceID),
+		SpanID:     oteltrace.SpanID(sc.SpanID),
+		TraceFlags: oteltrace.TraceFlags(sc.TraceOptions),
+	})
+}
+
+func toSpanKind(kind int) oteltrace.SpanKind {
+	switch kind {
+	case trace.SpanKindServer:
+		return oteltrace.SpanKindServer
+	case trace.SpanKindClient:
+		return oteltrace.SpanKindClient
+	}
+	return oteltrace.SpanKindInternal
+}
+
+// toAttributes converts the OpenCensus attributes which are either bool, int64, float64 or string values.
+func toAttributes(attributes map[string]interface{}) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		switch v := value.(type) {
+		case bool:
+			result = append(result, attribute.Bool(key, v))
+		case int64:
+			result = append(result, attribute.Int64(key, v))
+		case float64:
+			result = append(result, attribute.Float64(key, v))
+		default:
+			result = append(result, attribute.String(key, fmt.Sprint(v)))
+		}
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/trace"
+	collectortracev1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+func TestExporterExportSpan(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := make(chan *collectortracev1.ExportTraceServiceRequest, 1)
+	collector := newCollector(g, requests)
+	defer collector.Close()
+
+	exporter, err := NewExporter(collector.URL+"/", "eventing-controller", newLogger(g))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- exporter.Start(ctx) }()
+
+	parent, _ := b3.ParseSpanID(spanID)
+	sc := trace.SpanContext{TraceOptions: trace.TraceOptions(1)}
+	sc.TraceID, _ = b3.ParseTraceID(traceID)
+	sc.SpanID, _ = b3.ParseSpanID("e457b5a2e4d86bd1")
+	start := time.Unix(1, 0)
+	exporter.ExportSpan(&trace.SpanData{
+		SpanContext:     sc,
+		ParentSpanID:    parent,
+		HasRemoteParent: true,
+		SpanKind:        trace.SpanKindClient,
+		Name:            DispatchSpanName,
+		StartTime:       start,
+		EndTime:         start.Add(time.Second),
+		Attributes:      map[string]interface{}{AttemptAttribute: int64(2)},
+		Status:          trace.Status{Code: trace.StatusCodeUnknown, Message: "sink unavailable"},
+	})
+	cancel()
+	g.Eventually(done).Should(Receive(BeNil()))
+
+	var request *collectortracev1.ExportTraceServiceRequest
+	g.Eventually(requests).Should(Receive(&request))
+	g.Expect(request.ResourceSpans).To(HaveLen(1))
+	g.Expect(request.ResourceSpans[0].Resource.Attributes).To(HaveLen(1))
+	g.Expect(request.ResourceSpans[0].Resource.Attributes[0].Key).To(Equal("service.name"))
+	g.Expect(request.ResourceSpans[0].Resource.Attributes[0].Value.GetStringValue()).To(Equal("eventing-controller"))
+	g.Expect(request.ResourceSpans[0].InstrumentationLibrarySpans).To(HaveLen(1))
+	g.Expect(request.ResourceSpans[0].InstrumentationLibrarySpans[0].InstrumentationLibrary.Name).To(Equal(instrumentationName))
+	g.Expect(request.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans).To(HaveLen(1))
+
+	exported := request.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans[0]
+	g.Expect(hex.EncodeToString(exported.TraceId)).To(Equal(traceID))
+	g.Expect(hex.EncodeToString(exported.SpanId)).To(Equal("e457b5a2e4d86bd1"))
+	g.Expect(hex.EncodeToString(exported.ParentSpanId)).To(Equal(spanID))
+	g.Expect(exported.Name).To(Equal(DispatchSpanName))
+	g.Expect(exported.Kind).To(Equal(tracev1.Span_SPAN_KIND_CLIENT))
+	g.Expect(exported.StartTimeUnixNano).To(BeEquivalentTo(1000000000))
+	g.Expect(exported.EndTimeUnixNano).To(BeEquivalentTo(2000000000))
+	g.Expect(exported.Status.Code).To(Equal(tracev1.Status_STATUS_CODE_ERROR))
+	g.Expect(exported.Status.Message).To(Equal("sink unavailable"))
+	g.Expect(exported.Attributes).To(HaveLen(1))
+	g.Expect(exported.Attributes[0].Key).To(Equal(AttemptAttribute))
+	g.Expect(exported.Attributes[0].Value.GetIntValue()).To(BeEquivalentTo(2))
+}
+
+func TestExporterStart(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := make(chan *collectortracev1.ExportTraceServiceRequest, 1)
+	collector := newCollector(g, requests)
+	defer collector.Close()
+
+	exporter, err := NewExporter(collector.URL, "eventing-controller", newLogger(g))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- exporter.Start(ctx) }()
+
+	event := newEvent(map[string]string{"traceparent": "00-" + traceID + "-" + spanID + "-01"})
+	_, span := StartDispatchSpan(context.Background(), DispatchSpanName, event, "http://sink", 1)
+	span.End()
+
+	// the queued spans are exported once the exporter is stopped
+	cancel()
+	g.Eventually(done).Should(Receive(BeNil()))
+
+	var request *collectortracev1.ExportTraceServiceRequest
+	g.Eventually(requests).Should(Receive(&request))
+	spans := request.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans
+	g.Expect(spans).To(HaveLen(1))
+	g.Expect(hex.EncodeToString(spans[0].TraceId)).To(Equal(traceID))
+	g.Expect(hex.EncodeToString(spans[0].ParentSpanId)).To(Equal(spanID))
+}
+
+// newCollector returns an OTLP/HTTP collector which passes the received requests to the given channel.
+func newCollector(g *WithT, requests chan<- *collectortracev1.ExportTraceServiceRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(Equal(tracesPath))
+		g.Expect(r.Header.Get("Content-Type")).To(Equal("application/x-protobuf"))
+		body, err := ioutil.ReadAll(r.Body)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		request := &collectortracev1.ExportTraceServiceRequest{}
+		g.Expect(proto.Unmarshal(body, request)).Should(Succeed())
+		requests <- request
+	}))
+}
+
+func newLogger(g *WithT) *logger.Logger {
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	return l
+}
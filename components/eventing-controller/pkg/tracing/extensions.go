@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2extensions "github.com/cloudevents/sdk-go/v2/extensions"
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
+	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// B3Extension is the CE extension attribute which carries the trace context in the B3 single header format:
+	// {TraceId}-{SpanId}-{SamplingState}
+	B3Extension = "b3"
+
+	// DispatchSpanName is the name of the spans of the delivery attempts of an event to the subscription sink
+	DispatchSpanName = "eventing.dispatch"
+	// DeadLetterSpanName is the name of the spans of the events forwarded to the subscription dead-letter sink
+	DeadLetterSpanName = "eventing.deadletter"
+)
+
+// Attributes of the dispatch spans
+const (
+	EventIDAttribute     = "cloudevents.event_id"
+	EventSourceAttribute = "cloudevents.event_source"
+	EventTypeAttribute   = "cloudevents.event_type"
+	SinkAttribute        = "eventing.sink"
+	AttemptAttribute     = "eventing.attempt"
+)
+
+// SpanContextFromEvent returns the span context carried by the distributed tracing extension attributes of the
+// given event, the W3C traceparent is preferred over B3.
+func SpanContextFromEvent(event *cev2event.Event) (trace.SpanContext, bool) {
+	if dt, ok := cev2extensions.GetDistributedTracingExtension(*event); ok {
+		if sc, err := dt.ToSpanContext(); err == nil {
+			return sc, true
+		}
+	}
+	if value, ok := event.Extensions()[B3Extension]; ok {
+		if b3Value, ok := value.(string); ok {
+			return parseB3(b3Value)
+		}
+	}
+	return trace.SpanContext{}, false
+}
+
+// StartDispatchSpan starts a span for sending the given event to the given sink. The span is a child of the span
+// context carried by the event if any, otherwise it starts a new trace.
+func StartDispatchSpan(ctx context.Context, name string, event *cev2event.Event, sink string, attempt int) (context.Context, *trace.Span) {
+	var span *trace.Span
+	if parent, ok := SpanContextFromEvent(event); ok {
+		ctx, span = trace.StartSpanWithRemoteParent(ctx, name, parent, trace.WithSpanKind(trace.SpanKindClient))
+	} else {
+		ctx, span = trace.StartSpan(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	}
+	if span.IsRecordingEvents() {
+		span.AddAttributes(
+			trace.StringAttribute(EventIDAttribute, event.ID()),
+			trace.StringAttribute(EventSourceAttribute, event.Source()),
+			trace.StringAttribute(EventTypeAttribute, event.Type()),
+			trace.StringAttribute(SinkAttribute, sink),
+			trace.Int64Attribute(AttemptAttribute, int64(attempt)),
+		)
+	}
+	return ctx, span
+}
+
+// SetSpanStatus sets the status of the given span from the result of sending an event.
+func SetSpanStatus(span *trace.Span, result cev2protocol.Result) {
+	if cev2protocol.IsACK(result) {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeOK})
+		return
+	}
+	message := ""
+	if result != nil {
+		message = result.Error()
+	}
+	span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: message})
+}
+
+// parseB3 parses the span context of the given B3 single header value in the form of:
+// {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId} where the sampling state and parent span id are optional.
+func parseB3(value string) (trace.SpanContext, bool) {
+	parts := strings.Split(value, "-")
+	if len(parts) < 2 {
+		return trace.SpanContext{}, false
+	}
+	traceID, ok := b3.ParseTraceID(parts[0])
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	spanID, ok := b3.ParseSpanID(parts[1])
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	sc := trace.SpanContext{TraceID: traceID, SpanID: spanID}
+	if len(parts) > 2 {
+		// "d" is the debug flag which implies that the trace is sampled
+		if _, sampled := b3.ParseSampled(parts[2]); sampled || parts[2] == "d" {
+			sc.TraceOptions = trace.TraceOptions(1)
+		}
+	}
+	return sc, true
+}
@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
+	. "github.com/onsi/gomega"
+	"go.opencensus.io/trace"
+)
+
+const (
+	traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID  = "00f067aa0ba902b7"
+)
+
+func TestSpanContextFromEvent(t *testing.T) {
+	g := NewWithT(t)
+
+	testCases := []struct {
+		name        string
+		extensions  map[string]string
+		wantOK      bool
+		wantSampled bool
+	}{
+		{
+			name:        "W3C traceparent",
+			extensions:  map[string]string{"traceparent": "00-" + traceID + "-" + spanID + "-01"},
+			wantOK:      true,
+			wantSampled: true,
+		},
+		{
+			name: "W3C traceparent is preferred over B3",
+			extensions: map[string]string{
+				"traceparent": "00-" + traceID + "-" + spanID + "-00",
+				B3Extension:   "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			},
+			wantOK: true,
+		},
+		{
+			name:        "B3 single header",
+			extensions:  map[string]string{B3Extension: traceID + "-" + spanID + "-1"},
+			wantOK:      true,
+			wantSampled: true,
+		},
+		{
+			name:        "B3 single header with debug flag and parent span id",
+			extensions:  map[string]string{B3Extension: traceID + "-" + spanID + "-d-05e3ac9a4f6e3b90"},
+			wantOK:      true,
+			wantSampled: true,
+		},
+		{
+			name:       "B3 single header without sampling state",
+			extensions: map[string]string{B3Extension: traceID + "-" + spanID},
+			wantOK:     true,
+		},
+		{
+			name:       "invalid traceparent falls back to B3",
+			extensions: map[string]string{"traceparent": "invalid", B3Extension: traceID + "-" + spanID + "-0"},
+			wantOK:     true,
+		},
+		{
+			name:       "invalid B3",
+			extensions: map[string]string{B3Extension: "invalid"},
+			wantOK:     false,
+		},
+		{
+			name:   "no tracing extensions",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		event := newEvent(tc.extensions)
+		sc, ok := SpanContextFromEvent(event)
+		g.Expect(ok).To(Equal(tc.wantOK), tc.name)
+		if !tc.wantOK {
+			continue
+		}
+		g.Expect(sc.TraceID.String()).To(Equal(traceID), tc.name)
+		g.Expect(sc.SpanID.String()).To(Equal(spanID), tc.name)
+		g.Expect(sc.IsSampled()).To(Equal(tc.wantSampled), tc.name)
+	}
+}
+
+func TestStartDispatchSpan(t *testing.T) {
+	g := NewWithT(t)
+
+	// the span continues the trace carried by the event
+	event := newEvent(map[string]string{"traceparent": "00-" + traceID + "-" + spanID + "-01"})
+	_, span := StartDispatchSpan(context.Background(), DispatchSpanName, event, "http://sink", 2)
+	span.End()
+	g.Expect(span.SpanContext().TraceID.String()).To(Equal(traceID))
+	g.Expect(span.SpanContext().SpanID.String()).NotTo(Equal(spanID))
+	g.Expect(span.SpanContext().IsSampled()).To(BeTrue())
+
+	// the span starts a new trace if the event does not carry one
+	_, span = StartDispatchSpan(context.Background(), DispatchSpanName, newEvent(nil), "http://sink", 1)
+	span.End()
+	g.Expect(span.SpanContext().TraceID.String()).NotTo(Equal(traceID))
+}
+
+func TestSetSpanStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter := &spanRecorder{}
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+
+	event := newEvent(map[string]string{"traceparent": "00-" + traceID + "-" + spanID + "-01"})
+	for _, result := range []cev2protocol.Result{cev2protocol.ResultACK, errors.New("sink unavailable")} {
+		_, span := StartDispatchSpan(context.Background(), DispatchSpanName, event, "http://sink", 1)
+		SetSpanStatus(span, result)
+		span.End()
+	}
+
+	g.Expect(exporter.spans).To(HaveLen(2))
+	g.Expect(exporter.spans[0].Code).To(BeEquivalentTo(trace.StatusCodeOK))
+	g.Expect(exporter.spans[0].Attributes).To(HaveKeyWithValue(SinkAttribute, "http://sink"))
+	g.Expect(exporter.spans[0].Attributes).To(HaveKeyWithValue(AttemptAttribute, int64(1)))
+	g.Expect(exporter.spans[1].Code).To(BeEquivalentTo(trace.StatusCodeUnknown))
+	g.Expect(exporter.spans[1].Message).To(Equal("sink unavailable"))
+}
+
+// spanRecorder records the exported spans.
+type spanRecorder struct {
+	spans []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(span *trace.SpanData) {
+	r.spans = append(r.spans, span)
+}
+
+func newEvent(extensions map[string]string) *cev2event.Event {
+	event := cev2event.New()
+	event.SetID("id")
+	event.SetSource("source")
+	event.SetType("sap.kyma.custom.commerce.order.created.v1")
+	for name, value := range extensions {
+		event.SetExtension(name, value)
+	}
+	return &event
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+)
+
+// compile time check
+var _ propagation.HTTPFormat = &HTTPFormat{}
+
+// HTTPFormat is a propagation.HTTPFormat which reads the W3C TraceContext and B3 headers, preferring TraceContext,
+// and writes both of them so that the sinks can continue the trace whichever format they support.
+type HTTPFormat struct {
+	traceContext tracecontext.HTTPFormat
+	b3           b3.HTTPFormat
+}
+
+// SpanContextFromRequest implements the propagation.HTTPFormat interface.
+func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (trace.SpanContext, bool) {
+	if sc, ok := f.traceContext.SpanContextFromRequest(req); ok {
+		return sc, true
+	}
+	return f.b3.SpanContextFromRequest(req)
+}
+
+// SpanContextToRequest implements the propagation.HTTPFormat interface.
+func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
+	f.traceContext.SpanContextToRequest(sc, req)
+	f.b3.SpanContextToRequest(sc, req)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
 	"github.com/pkg/errors"
@@ -49,6 +50,9 @@ const (
 	BEBPublishEndpointForPublisher  = "/sap/ems/v1/events"
 
 	reconcilerName = "backend-reconciler"
+
+	// streamCheckInterval is the interval to check the NATS stream again if it is not ready
+	streamCheckInterval = 30 * time.Second
 )
 
 type Reconciler struct {
@@ -187,8 +191,24 @@ func (r *Reconciler) reconcileNATSBackend(ctx context.Context) (ctrl.Result, err
 
 	// CreateOrUpdate status of the CR
 	// Get publisher proxy ready status
-	err = r.UpdateBackendStatus(ctx, r.backendType, newBackend, publisher, nil)
-	return ctrl.Result{}, err
+	if err := r.UpdateBackendStatus(ctx, r.backendType, newBackend, publisher, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The stream health is not watched, hence check it again later until it gets ready
+	if streamReady := r.natsStreamReady(); streamReady != nil && !*streamReady {
+		return ctrl.Result{RequeueAfter: streamCheckInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// natsStreamReady returns the health of the stream used by the NATS backend, or nil if it does not use one.
+func (r *Reconciler) natsStreamReady() *bool {
+	reporter, ok := r.natsCommander.(commander.StreamStatusReporter)
+	if !ok || !r.natsCommanderStarted {
+		return nil
+	}
+	return reporter.StreamReady()
 }
 
 func (r *Reconciler) reconcileBEBBackend(ctx context.Context, bebSecret *v1.Secret) (ctrl.Result, error) {
@@ -367,8 +387,12 @@ func (r *Reconciler) UpdateBackendStatus(ctx context.Context, backendType eventi
 		desiredStatus.BebSecretName = ""
 		desiredStatus.BebSecretNamespace = ""
 		subscriptionControllerReady = r.natsCommanderStarted
+		desiredStatus.NATSStreamReady = r.natsStreamReady()
 	}
 	eventingReady := subscriptionControllerReady && publisherReady
+	if desiredStatus.NATSStreamReady != nil {
+		eventingReady = eventingReady && *desiredStatus.NATSStreamReady
+	}
 
 	desiredStatus.Backend = backendType
 	desiredStatus.SubscriptionControllerReady = utils.BoolPtr(subscriptionControllerReady)
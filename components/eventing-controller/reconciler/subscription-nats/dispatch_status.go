@@ -0,0 +1,109 @@
+package subscription_nats
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/metrics"
+)
+
+const (
+	// dispatchStatusSyncPeriod is the period of the dispatch status and metrics updates
+	dispatchStatusSyncPeriod = 10 * time.Second
+)
+
+// dispatchStatsGetter returns the dispatch state of the subscriptions dispatched by a backend.
+type dispatchStatsGetter interface {
+	GetDispatchStats() map[types.NamespacedName]handlers.DispatchStats
+}
+
+// dispatchStatsRecorder records the dispatch metrics and deletes the ones of the subscriptions which are gone.
+type dispatchStatsRecorder struct {
+	recorded map[types.NamespacedName]struct{}
+}
+
+func (d *dispatchStatsRecorder) record(stats map[types.NamespacedName]handlers.DispatchStats) {
+	for key := range d.recorded {
+		if _, ok := stats[key]; !ok {
+			metrics.DeleteDispatchStats(key)
+			delete(d.recorded, key)
+		}
+	}
+	if d.recorded == nil {
+		d.recorded = make(map[types.NamespacedName]struct{}, len(stats))
+	}
+	for key, s := range stats {
+		metrics.RecordDispatchStats(key, s.PendingMessages, s.SlowConsumer)
+		d.recorded[key] = struct{}{}
+	}
+}
+
+// syncDispatchStatus updates the dispatch status and metrics of the subscriptions dispatched by this replica.
+// It runs on the leader only, hence the dispatch status does not cover the events dispatched by the other replicas.
+func (r *Reconciler) syncDispatchStatus(ctx context.Context) {
+	getter, ok := r.Backend.(dispatchStatsGetter)
+	if !ok {
+		return
+	}
+	stats := getter.GetDispatchStats()
+	r.statsRecorder.record(stats)
+
+	for key, s := range stats {
+		sub := &eventingv1alpha1.Subscription{}
+		if err := r.Client.Get(ctx, key, sub); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				r.namedLogger().Errorw("get subscription failed", "namespace", key.Namespace, "name", key.Name, "error", err)
+			}
+			continue
+		}
+		desiredStatus := &eventingv1alpha1.DispatchStatus{PendingMessages: s.PendingMessages, SlowConsumer: s.SlowConsumer}
+		if reflect.DeepEqual(sub.Status.DispatchStatus, desiredStatus) {
+			continue
+		}
+		desiredSubscription := sub.DeepCopy()
+		desiredSubscription.Status.DispatchStatus = desiredStatus
+		if err := r.Client.Status().Update(ctx, desiredSubscription, &client.UpdateOptions{}); err != nil {
+			r.namedLogger().Errorw("update subscription dispatch status failed", "namespace", key.Namespace, "name", key.Name, "error", err)
+		}
+	}
+}
+
+// ignoreDispatchStatusUpdates filters out the subscription updates which only change the dispatch status,
+// reconciling them would recreate the NATS subscriptions each time the dispatch status is synced.
+func ignoreDispatchStatusUpdates() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSub, ok := e.ObjectOld.(*eventingv1alpha1.Subscription)
+			if !ok {
+				return true
+			}
+			newSub, ok := e.ObjectNew.(*eventingv1alpha1.Subscription)
+			if !ok {
+				return true
+			}
+			return !isDispatchStatusUpdateOnly(oldSub, newSub)
+		},
+	}
+}
+
+func isDispatchStatusUpdateOnly(oldSub, newSub *eventingv1alpha1.Subscription) bool {
+	if reflect.DeepEqual(oldSub.Status.DispatchStatus, newSub.Status.DispatchStatus) {
+		return false
+	}
+	oldCopy, newCopy := oldSub.DeepCopy(), newSub.DeepCopy()
+	for _, sub := range []*eventingv1alpha1.Subscription{oldCopy, newCopy} {
+		sub.ResourceVersion = ""
+		sub.ManagedFields = nil
+		sub.Status.DispatchStatus = nil
+	}
+	return reflect.DeepEqual(oldCopy, newCopy)
+}
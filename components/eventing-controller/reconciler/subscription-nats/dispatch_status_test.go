@@ -0,0 +1,67 @@
+package subscription_nats
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestIsDispatchStatusUpdateOnly(t *testing.T) {
+	g := NewWithT(t)
+
+	maxInFlight := 5
+	testCases := []struct {
+		name   string
+		update func(sub *eventingv1alpha1.Subscription)
+		want   bool
+	}{
+		{
+			name: "dispatch status changed",
+			update: func(sub *eventingv1alpha1.Subscription) {
+				sub.ResourceVersion = "2"
+				sub.Status.DispatchStatus = &eventingv1alpha1.DispatchStatus{PendingMessages: 10, SlowConsumer: true}
+			},
+			want: true,
+		},
+		{
+			name: "dispatch status and spec changed",
+			update: func(sub *eventingv1alpha1.Subscription) {
+				sub.Status.DispatchStatus = &eventingv1alpha1.DispatchStatus{PendingMessages: 10}
+				sub.Spec.MaxInFlight = &maxInFlight
+			},
+			want: false,
+		},
+		{
+			name: "dispatch status and conditions changed",
+			update: func(sub *eventingv1alpha1.Subscription) {
+				sub.Status.DispatchStatus = &eventingv1alpha1.DispatchStatus{PendingMessages: 10}
+				sub.Status.Conditions = []eventingv1alpha1.Condition{{Type: eventingv1alpha1.ConditionSubscriptionActive}}
+			},
+			want: false,
+		},
+		{
+			name: "spec changed",
+			update: func(sub *eventingv1alpha1.Subscription) {
+				sub.Spec.MaxInFlight = &maxInFlight
+			},
+			want: false,
+		},
+		{
+			name:   "nothing changed",
+			update: func(sub *eventingv1alpha1.Subscription) {},
+			want:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		oldSub := eventingtesting.NewSubscription("sub", "foo")
+		oldSub.ResourceVersion = "1"
+		oldSub.Status.DispatchStatus = &eventingv1alpha1.DispatchStatus{PendingMessages: 1}
+		newSub := oldSub.DeepCopy()
+		tc.update(newSub)
+		g.Expect(isDispatchStatusUpdateOnly(oldSub, newSub)).To(Equal(tc.want), tc.name)
+	}
+}
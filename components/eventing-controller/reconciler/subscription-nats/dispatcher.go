@@ -0,0 +1,246 @@
+package subscription_nats
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/application"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers/eventtype"
+	"github.com/kyma-project/kyma/components/eventing-controller/utils"
+)
+
+const (
+	dispatcherName = "nats-subscription-dispatcher"
+)
+
+// compile time check
+var _ manager.Runnable = &Dispatcher{}
+var _ manager.LeaderElectionRunnable = &Dispatcher{}
+
+// Dispatcher dispatches the events of the NATS subscriptions on the replicas which are not the leader.
+// The leader dispatches through the subscription Reconciler, all the replicas join the same NATS queue group
+// per Kyma subscription so that each event is dispatched by one replica only.
+// The Dispatcher does not modify the subscriptions, it only follows the ones the leader marked as active.
+type Dispatcher struct {
+	client.Client
+	mgr     manager.Manager
+	restCfg *rest.Config
+	cfg     env.NatsConfig
+	logger  *logger.Logger
+
+	// mutex guards the backend which is used by the reconcile loop and released once elected
+	mutex   sync.Mutex
+	backend *handlers.Nats
+	cleaner eventtype.Cleaner
+	elected bool
+	// statsRecorder records the dispatch metrics of the subscriptions
+	statsRecorder dispatchStatsRecorder
+}
+
+// NewDispatcher returns a new Dispatcher instance which dispatches using the given NATS config.
+func NewDispatcher(mgr manager.Manager, restCfg *rest.Config, cfg env.NatsConfig, logger *logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		Client:  mgr.GetClient(),
+		mgr:     mgr,
+		restCfg: restCfg,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the Dispatcher runs on all the replicas.
+func (d *Dispatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements the Runnable interface and starts the Dispatcher controller until the given context is done.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	applicationLister := application.NewLister(ctx, dynamic.NewForConfigOrDie(d.restCfg))
+	d.cleaner = eventtype.NewCleaner(d.cfg.EventTypePrefix, applicationLister, d.logger)
+
+	ctru, err := controller.NewUnmanaged(dispatcherName, d.mgr, controller.Options{Reconciler: d})
+	if err != nil {
+		d.namedLogger().Errorw("create unmanaged controller failed", "name", dispatcherName, "error", err)
+		return err
+	}
+	if err := ctru.Watch(&source.Kind{Type: &eventingv1alpha1.Subscription{}}, &handler.EnqueueRequestForObject{}, ignoreDispatchStatusUpdates()); err != nil {
+		d.namedLogger().Errorw("watch subscriptions failed", "error", err)
+		return err
+	}
+	p := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == NATSFirstInstanceName && o.GetNamespace() == NATSNamespace
+	})
+	if err := ctru.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForObject{}, p); err != nil {
+		d.namedLogger().Errorw("watch nats server failed", "pod", NATSFirstInstanceName, "error", err)
+		return err
+	}
+
+	go wait.UntilWithContext(ctx, d.recordDispatchStats, dispatchStatusSyncPeriod)
+
+	// the leader dispatches through the subscription reconciler, hence the dispatcher steps back once elected
+	go func() {
+		select {
+		case <-d.mgr.Elected():
+			d.release()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctru.Start(ctx)
+}
+
+// Reconcile keeps the NATS subscriptions of this replica in sync with the active Kyma subscriptions.
+func (d *Dispatcher) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.elected {
+		return ctrl.Result{}, nil
+	}
+
+	if req.Name == NATSFirstInstanceName && req.Namespace == NATSNamespace {
+		d.namedLogger().Debugw("received watch request", "namespace", req.Namespace, "name", req.Name)
+		return ctrl.Result{}, d.syncInvalidSubscriptions(ctx)
+	}
+
+	sub := &eventingv1alpha1.Subscription{}
+	if err := d.Client.Get(ctx, req.NamespacedName, sub); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		sub.ObjectMeta = metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name}
+	}
+	return ctrl.Result{}, d.sync(sub)
+}
+
+// sync dispatches the events of the given subscription if the leader marked it as an active NATS subscription,
+// otherwise the subscription is released.
+func (d *Dispatcher) sync(sub *eventingv1alpha1.Subscription) error {
+	log := utils.LoggerWithSubscription(d.namedLogger(), sub)
+
+	if !isActiveNATSSubscription(sub) {
+		if d.backend == nil {
+			return nil
+		}
+		if err := d.backend.ReleaseSubscription(sub); err != nil {
+			log.Errorw("release subscription failed", "error", err)
+			return err
+		}
+		return nil
+	}
+
+	// connect to NATS lazily, the NATS server does not exist if another backend is in use
+	if d.backend == nil {
+		backend := handlers.NewNats(d.cfg, d.logger)
+		if err := backend.Initialize(env.Config{}); err != nil {
+			log.Errorw("start dispatcher failed", "error", err)
+			return err
+		}
+		d.backend = backend
+	}
+
+	// the JetStream subscriptions are synced in place, the core NATS ones are recreated
+	if !d.cfg.JetStreamEnabled {
+		if err := d.backend.ReleaseSubscription(sub); err != nil {
+			log.Errorw("release subscription failed", "error", err)
+			return err
+		}
+	}
+	if _, err := d.backend.SyncSubscription(sub, d.cleaner); err != nil {
+		log.Errorw("sync subscription failed", "error", err)
+		return err
+	}
+	log.Debug("dispatch NATS subscriptions succeeded")
+	return nil
+}
+
+// syncInvalidSubscriptions recreates the NATS subscriptions of this replica which were invalidated by NATS.
+func (d *Dispatcher) syncInvalidSubscriptions(ctx context.Context) error {
+	if d.backend == nil {
+		return nil
+	}
+	for _, v := range *d.backend.GetInvalidSubscriptions() {
+		d.namedLogger().Debugw("found invalid subscription", "namespace", v.Namespace, "name", v.Name)
+		sub := &eventingv1alpha1.Subscription{}
+		if err := d.Client.Get(ctx, v, sub); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				d.namedLogger().Errorw("get invalid subscription failed", "namespace", v.Namespace, "name", v.Name, "error", err)
+				continue
+			}
+			sub.ObjectMeta = metav1.ObjectMeta{Namespace: v.Namespace, Name: v.Name}
+		}
+		if err := d.backend.ReleaseSubscription(sub); err != nil {
+			return err
+		}
+		if err := d.sync(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// release stops dispatching on this replica for good.
+func (d *Dispatcher) release() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.elected = true
+	if d.backend == nil {
+		return
+	}
+	if err := d.backend.ReleaseAllSubscriptions(); err != nil {
+		d.namedLogger().Errorw("release subscriptions failed", "error", err)
+	}
+	d.namedLogger().Info("dispatcher released the subscriptions to the leader")
+}
+
+// recordDispatchStats records the dispatch metrics of the subscriptions dispatched by this replica,
+// the subscription status is synced by the leader only.
+func (d *Dispatcher) recordDispatchStats(context.Context) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	stats := map[types.NamespacedName]handlers.DispatchStats{}
+	if d.backend != nil && !d.elected {
+		stats = d.backend.GetDispatchStats()
+	}
+	d.statsRecorder.record(stats)
+}
+
+// isActiveNATSSubscription returns true if the given subscription was accepted by the NATS subscription reconciler.
+func isActiveNATSSubscription(sub *eventingv1alpha1.Subscription) bool {
+	if !sub.DeletionTimestamp.IsZero() || !utils.ContainsString(sub.Finalizers, Finalizer) {
+		return false
+	}
+	for _, c := range sub.Status.Conditions {
+		if c.Type == eventingv1alpha1.ConditionSubscriptionActive && c.Reason == eventingv1alpha1.ConditionReasonNATSSubscriptionActive {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (d *Dispatcher) namedLogger() *zap.SugaredLogger {
+	return d.logger.WithContext().Named(dispatcherName)
+}
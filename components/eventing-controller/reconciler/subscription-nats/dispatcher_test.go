@@ -0,0 +1,47 @@
+package subscription_nats
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestIsActiveNATSSubscription(t *testing.T) {
+	g := NewWithT(t)
+
+	natsActive := eventingv1alpha1.MakeCondition(eventingv1alpha1.ConditionSubscriptionActive,
+		eventingv1alpha1.ConditionReasonNATSSubscriptionActive, corev1.ConditionTrue, "")
+	natsNotActive := eventingv1alpha1.MakeCondition(eventingv1alpha1.ConditionSubscriptionActive,
+		eventingv1alpha1.ConditionReasonNATSSubscriptionActive, corev1.ConditionFalse, "invalid sink")
+	bebActive := eventingv1alpha1.MakeCondition(eventingv1alpha1.ConditionSubscriptionActive,
+		eventingv1alpha1.ConditionReasonSubscriptionActive, corev1.ConditionTrue, "")
+	now := metav1.Now()
+
+	testCases := []struct {
+		name              string
+		finalizers        []string
+		deletionTimestamp *metav1.Time
+		conditions        []eventingv1alpha1.Condition
+		want              bool
+	}{
+		{name: "active NATS subscription", finalizers: []string{Finalizer}, conditions: []eventingv1alpha1.Condition{natsActive}, want: true},
+		{name: "not active NATS subscription", finalizers: []string{Finalizer}, conditions: []eventingv1alpha1.Condition{natsNotActive}, want: false},
+		{name: "active BEB subscription", finalizers: []string{Finalizer}, conditions: []eventingv1alpha1.Condition{bebActive}, want: false},
+		{name: "subscription without finalizer", conditions: []eventingv1alpha1.Condition{natsActive}, want: false},
+		{name: "subscription being deleted", finalizers: []string{Finalizer}, deletionTimestamp: &now, conditions: []eventingv1alpha1.Condition{natsActive}, want: false},
+		{name: "subscription without status", finalizers: []string{Finalizer}, want: false},
+	}
+
+	for _, tc := range testCases {
+		sub := eventingtesting.NewSubscription("sub", "foo")
+		sub.Finalizers = tc.finalizers
+		sub.DeletionTimestamp = tc.deletionTimestamp
+		sub.Status.Conditions = tc.conditions
+		g.Expect(isActiveNATSSubscription(sub)).To(Equal(tc.want), tc.name)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -39,6 +40,10 @@ type Reconciler struct {
 	logger           *logger.Logger
 	recorder         record.EventRecorder
 	eventTypeCleaner eventtype.Cleaner
+	// jetStreamEnabled is true if the backend keeps durable consumers which must survive a reconciliation
+	jetStreamEnabled bool
+	// statsRecorder records the dispatch metrics of the subscriptions
+	statsRecorder dispatchStatsRecorder
 }
 
 var (
@@ -70,6 +75,7 @@ func NewReconciler(ctx context.Context, client client.Client, applicationLister
 		logger:           logger,
 		recorder:         recorder,
 		eventTypeCleaner: eventtype.NewCleaner(cfg.EventTypePrefix, applicationLister, logger),
+		jetStreamEnabled: cfg.JetStreamEnabled,
 	}
 }
 
@@ -87,7 +93,7 @@ func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
 		return err
 	}
 
-	if err := ctru.Watch(&source.Kind{Type: &eventingv1alpha1.Subscription{}}, &handler.EnqueueRequestForObject{}); err != nil {
+	if err := ctru.Watch(&source.Kind{Type: &eventingv1alpha1.Subscription{}}, &handler.EnqueueRequestForObject{}, ignoreDispatchStatusUpdates()); err != nil {
 		r.namedLogger().Errorw("watch subscriptions failed", "error", err)
 		return err
 	}
@@ -124,6 +130,8 @@ func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
 		}
 	}(r, ctru)
 
+	go wait.UntilWithContext(r.ctx, r.syncDispatchStatus, dispatchStatusSyncPeriod)
+
 	return nil
 }
 
@@ -179,14 +187,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// No point in reconciling as the sink is invalid
 		return ctrl.Result{}, nil
 	}
+	if len(actualSubscription.Spec.DeadLetterSink) > 0 {
+		if err := r.assertSinkValidity(actualSubscription.Spec.DeadLetterSink); err != nil {
+			log.Errorw("parse dead-letter sink URL failed", "error", err)
+			if err := r.syncSubscriptionStatus(ctx, actualSubscription, false, err.Error()); err != nil {
+				return ctrl.Result{}, err
+			}
+			// No point in reconciling as the dead-letter sink is invalid
+			return ctrl.Result{}, nil
+		}
+	}
 
-	// Clean up the old subscriptions
-	if err := r.Backend.DeleteSubscription(desiredSubscription); err != nil {
-		log.Errorw("delete subscription failed", "error", err)
-		if err := r.syncSubscriptionStatus(ctx, actualSubscription, false, err.Error()); err != nil {
+	// Clean up the old subscriptions, in JetStream mode the backend syncs the durable consumers itself
+	if !r.jetStreamEnabled {
+		if err := r.Backend.DeleteSubscription(desiredSubscription); err != nil {
+			log.Errorw("delete subscription failed", "error", err)
+			if err := r.syncSubscriptionStatus(ctx, actualSubscription, false, err.Error()); err != nil {
+				return ctrl.Result{}, err
+			}
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, err
 	}
 
 	// The object is not being deleted, so if it does not have our finalizer,
@@ -212,6 +232,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if err := r.syncSubscriptionStatus(ctx, actualSubscription, false, err.Error()); err != nil {
 			return ctrl.Result{}, err
 		}
+		// No point in reconciling as the filters cannot be applied until the subscription is changed
+		if errors.Is(err, handlers.ErrUnsupportedFilter) {
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{}, err
 	}
 	log.Debug("create NATS subscriptions succeeded")
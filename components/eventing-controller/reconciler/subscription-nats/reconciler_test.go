@@ -35,6 +35,7 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/application/applicationtest"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/application/fake"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/handlers"
 	reconcilertesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
 )
 
@@ -182,6 +183,30 @@ var _ = Describe("NATS Subscription Reconciliation Tests", func() {
 			))
 		})
 	})
+
+	When("Creating a Subscription with an unsupported source filter type", func() {
+		It("Should mark the subscription as not ready", func() {
+			ctx := context.Background()
+			subscriptionName := fmt.Sprintf("sub-%d", testId)
+
+			// Create subscription
+			givenSubscription := reconcilertesting.NewSubscription(subscriptionName, namespaceName,
+				reconcilertesting.WithEventTypeFilter, reconcilertesting.WithWebhookForNats)
+			givenSubscription.Spec.Filter.Filters[0].EventSource.Type = eventingv1alpha1.FilterTypeWildcard
+			reconcilertesting.WithValidSink("foo", "bar", givenSubscription)
+			ensureSubscriptionCreated(givenSubscription, ctx)
+
+			wantMessage := fmt.Sprintf("filter type [%s] is not supported for property [source]: %s",
+				eventingv1alpha1.FilterTypeWildcard, handlers.ErrUnsupportedFilter)
+			getSubscription(givenSubscription, ctx).Should(And(
+				reconcilertesting.HaveSubscriptionName(subscriptionName),
+				reconcilertesting.HaveCondition(eventingv1alpha1.MakeCondition(
+					eventingv1alpha1.ConditionSubscriptionActive,
+					eventingv1alpha1.ConditionReasonNATSSubscriptionActive,
+					v1.ConditionFalse, wantMessage)),
+			))
+		})
+	})
 })
 
 func ensureSubscriptionCreated(subscription *eventingv1alpha1.Subscription, ctx context.Context) {
@@ -17,3 +17,13 @@ func ShutDownNATSServer(natsServer *server.Server) {
 	natsServer.Shutdown()
 	natsServer.WaitForShutdown()
 }
+
+// RunJetStreamNatsServerOnPort will run a server with JetStream enabled on the given port,
+// storing the streams in the given directory.
+func RunJetStreamNatsServerOnPort(port int, storeDir string) *server.Server {
+	opts := natstestserver.DefaultTestOptions
+	opts.Port = port
+	opts.JetStream = true
+	opts.StoreDir = storeDir
+	return natstestserver.RunServer(&opts)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+
+	"github.com/vrischmann/envconfig"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/kyma-project/kyma/components/function-controller/internal/activator"
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+var setupLog = ctrl.Log.WithName("setup")
+
+func main() {
+	ctrl.SetLogger(ctrlzap.New())
+
+	cfg := activator.Config{}
+	if err := envconfig.InitWithPrefix(&cfg, "APP"); err != nil {
+		setupLog.Error(err, "unable to load config")
+		os.Exit(1)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		setupLog.Error(err, "unable to create scheme")
+		os.Exit(1)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create Kubernetes client")
+		os.Exit(1)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create Kubernetes clientset")
+		os.Exit(1)
+	}
+
+	// only the Endpoints of the Functions are cached instead of all the Endpoints of the cluster
+	selector := labels.SelectorFromSet(labels.Set{serverlessv1alpha1.FunctionManagedByLabel: serverlessv1alpha1.FunctionControllerValue}).String()
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = selector
+	}))
+	endpointsInformer := factory.Core().V1().Endpoints().Informer()
+	if err := endpointsInformer.AddIndexers(activator.EndpointsIndexers()); err != nil {
+		setupLog.Error(err, "unable to create Endpoints indexers")
+		os.Exit(1)
+	}
+
+	stop := ctrl.SetupSignalHandler()
+	factory.Start(stop)
+	if !cache.WaitForCacheSync(stop, endpointsInformer.HasSynced) {
+		setupLog.Error(nil, "unable to sync Endpoints cache")
+		os.Exit(1)
+	}
+
+	scaler := activator.NewKubernetesScaler(k8sClient, endpointsInformer.GetIndexer())
+	if err := activator.New(scaler, cfg, ctrl.Log).Start(stop); err != nil {
+		setupLog.Error(err, "unable to run the activator")
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	_ "time/tzdata" // the images don't ship the time zone database, it's needed by the schedule time zones
 
 	"go.uber.org/zap/zapcore"
 
@@ -20,6 +21,7 @@ import (
 
 	k8s "github.com/kyma-project/kyma/components/function-controller/internal/controllers/kubernetes"
 	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless"
+	"github.com/kyma-project/kyma/components/function-controller/internal/gitwebhook"
 	internalresource "github.com/kyma-project/kyma/components/function-controller/internal/resource"
 	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 	// +kubebuilder:scaffold:imports
@@ -42,6 +44,7 @@ type config struct {
 	LeaderElectionEnabled     bool   `envconfig:"default=false"`
 	LeaderElectionID          string `envconfig:"default=serverless-controller-leader-election-helper"`
 	SecretMutatingWebhookPort int    `envconfig:"default=8443"`
+	GitWebhookAddress         string `envconfig:"default=:8090"`
 	LogLevel                  string `envconfig:"default=info"`
 	Kubernetes                k8s.Config
 	Function                  serverless.FunctionConfig
@@ -96,12 +99,17 @@ func main() {
 		},
 	)
 
-	if err := serverless.NewFunction(resourceClient, ctrl.Log, config.Function, mgr.GetEventRecorderFor(serverlessv1alpha1.FunctionControllerValue)).
-		SetupWithManager(mgr); err != nil {
+	functionReconciler := serverless.NewFunction(resourceClient, ctrl.Log, config.Function, mgr.GetEventRecorderFor(serverlessv1alpha1.FunctionControllerValue))
+	if err := functionReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create Function controller")
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(gitwebhook.NewReceiver(mgr.GetClient(), ctrl.Log, config.GitWebhookAddress)); err != nil {
+		setupLog.Error(err, "unable to create git webhook receiver")
+		os.Exit(1)
+	}
+
 	if err := k8s.NewConfigMap(mgr.GetClient(), ctrl.Log, config.Kubernetes, configMapSvc).
 		SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create ConfigMap controller")
@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	_ "time/tzdata" // the images don't ship the time zone database, it's needed by the schedule time zones
 
 	"github.com/pkg/errors"
 
 	"github.com/vrischmann/envconfig"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -18,6 +20,7 @@ import (
 	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	serverlessv1alhpa1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 )
@@ -55,11 +58,20 @@ func main() {
 
 	restConfig := ctrl.GetConfigOrDie()
 
+	scheme := runtime.NewScheme()
+	if err := serverlessv1alhpa1.AddToScheme(scheme); err != nil {
+		panic(errors.Wrap(err, "while creating scheme"))
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		panic(errors.Wrap(err, "while creating kubernetes client"))
+	}
+
 	sharedmain.WebhookMainWithConfig(ctx, "serverless-webhook",
 		restConfig,
 		certificates.NewController,
 		NewDefaultingAdmissionController(defaultingCfg),
-		NewValidationAdmissionController(validationCfg),
+		NewValidationAdmissionController(validationCfg, &runtimeResolver{client: k8sClient}),
 	)
 }
 
@@ -87,7 +99,7 @@ func NewDefaultingAdmissionController(cfg *serverlessv1alhpa1.DefaultingConfig)
 	}
 }
 
-func NewValidationAdmissionController(cfg *serverlessv1alhpa1.ValidationConfig) func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+func NewValidationAdmissionController(cfg *serverlessv1alhpa1.ValidationConfig, resolver serverlessv1alhpa1.RuntimeResolver) func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		return validation.NewAdmissionController(ctx,
 
@@ -102,6 +114,7 @@ func NewValidationAdmissionController(cfg *serverlessv1alhpa1.ValidationConfig)
 
 			// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
 			func(ctx context.Context) context.Context {
+				ctx = context.WithValue(ctx, serverlessv1alhpa1.RuntimeResolverKey, resolver)
 				return context.WithValue(ctx, serverlessv1alhpa1.ValidationConfigKey, *cfg)
 			},
 
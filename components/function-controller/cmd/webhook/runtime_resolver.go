@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alhpa1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+// runtimeResolver reads the FunctionRuntimes directly from the API server, so the Functions can be created right after
+// their FunctionRuntime
+type runtimeResolver struct {
+	client client.Client
+}
+
+var _ serverlessv1alhpa1.RuntimeResolver = &runtimeResolver{}
+
+func (r *runtimeResolver) GetFunctionRuntime(ctx context.Context, runtime serverlessv1alhpa1.Runtime) (*serverlessv1alhpa1.FunctionRuntime, error) {
+	functionRuntime := &serverlessv1alhpa1.FunctionRuntime{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: string(runtime)}, functionRuntime); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return functionRuntime, nil
+}
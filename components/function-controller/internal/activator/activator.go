@@ -0,0 +1,194 @@
+package activator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Scaler scales the Functions up from zero and finds their Pods which are ready to receive requests
+type Scaler interface {
+	// Resolve returns the Function which is routed to the activator under the given host
+	Resolve(host string) (types.NamespacedName, bool)
+	ScaleUp(ctx context.Context, function types.NamespacedName) error
+	// ReadyPodIP returns the IP of a ready Pod of the Function, it's empty if there is no such Pod
+	ReadyPodIP(ctx context.Context, function types.NamespacedName) (string, error)
+}
+
+type Config struct {
+	Address             string        `envconfig:"default=:8080"`
+	HealthAddress       string        `envconfig:"default=:8090"`
+	FunctionPort        int           `envconfig:"default=8080"`
+	ActivationTimeout   time.Duration `envconfig:"default=2m"`
+	PollInterval        time.Duration `envconfig:"default=500ms"`
+	MaxBufferedRequests int64         `envconfig:"default=1000"`
+}
+
+// Activator receives the requests of the Functions scaled to zero, their Service points to the activator until they're
+// scaled up. It holds the requests, scales the Function up, and proxies the requests to the Function Pod as soon as
+// it's ready. The Function is resolved from the hosts which the Function Controller sets on the Endpoints pointing to
+// the activator, so only the Functions scaled to zero can be activated.
+type Activator struct {
+	scaler Scaler
+	config Config
+	log    logr.Logger
+
+	// buffered is the number of the requests waiting for their Functions
+	buffered int64
+
+	mu          sync.Mutex
+	activations map[types.NamespacedName]*activation
+}
+
+// activation is the scale up of one Function shared by all its requests which arrived in the meantime
+type activation struct {
+	done chan struct{}
+	ip   string
+	err  error
+}
+
+func New(scaler Scaler, config Config, log logr.Logger) *Activator {
+	return &Activator{
+		scaler:      scaler,
+		config:      config,
+		log:         log.WithName("activator"),
+		activations: make(map[types.NamespacedName]*activation),
+	}
+}
+
+// Start serves the requests until the stop channel is closed
+func (a *Activator) Start(stop <-chan struct{}) error {
+	health := http.NewServeMux()
+	health.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	servers := []*http.Server{
+		{Addr: a.config.Address, Handler: a},
+		{Addr: a.config.HealthAddress, Handler: health},
+	}
+
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server *http.Server) {
+			a.log.Info("Starting server", "address", server.Addr)
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errs <- err
+			}
+		}(server)
+	}
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-stop:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, server := range servers {
+		if shutdownErr := server.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
+
+func (a *Activator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	function, ok := a.scaler.Resolve(req.Host)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Cannot find Function for host %s", req.Host), http.StatusNotFound)
+		return
+	}
+	log := a.log.WithValues("namespace", function.Namespace, "name", function.Name)
+
+	if atomic.AddInt64(&a.buffered, 1) > a.config.MaxBufferedRequests {
+		atomic.AddInt64(&a.buffered, -1)
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Too many requests waiting for activation", http.StatusServiceUnavailable)
+		return
+	}
+	ip, err := a.activate(req.Context(), function)
+	atomic.AddInt64(&a.buffered, -1)
+	if err != nil {
+		log.Error(err, "Cannot activate Function")
+		http.Error(w, fmt.Sprintf("Cannot activate Function %s", function), http.StatusServiceUnavailable)
+		return
+	}
+
+	a.proxy(ip).ServeHTTP(w, req)
+}
+
+// activate waits until the Function has a ready Pod and returns its IP, the Function is scaled up once for all the
+// requests which wait for it
+func (a *Activator) activate(ctx context.Context, function types.NamespacedName) (string, error) {
+	a.mu.Lock()
+	current, ok := a.activations[function]
+	if !ok {
+		current = &activation{done: make(chan struct{})}
+		a.activations[function] = current
+		go a.run(function, current)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-current.done:
+		return current.ip, current.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (a *Activator) run(function types.NamespacedName, current *activation) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.ActivationTimeout)
+	defer cancel()
+
+	current.ip, current.err = a.waitForPod(ctx, function)
+
+	// the next requests start a new activation, e.g. if the Function was scaled to zero again in the meantime
+	a.mu.Lock()
+	delete(a.activations, function)
+	a.mu.Unlock()
+	close(current.done)
+}
+
+func (a *Activator) waitForPod(ctx context.Context, function types.NamespacedName) (string, error) {
+	ip, err := a.scaler.ReadyPodIP(ctx, function)
+	if err != nil || ip != "" {
+		return ip, err
+	}
+
+	a.log.Info("Scaling Function up", "namespace", function.Namespace, "name", function.Name)
+	if err := a.scaler.ScaleUp(ctx, function); err != nil {
+		return "", fmt.Errorf("while scaling up: %w", err)
+	}
+
+	err = wait.PollImmediateUntil(a.config.PollInterval, func() (bool, error) {
+		ip, err = a.scaler.ReadyPodIP(ctx, function)
+		return ip != "", err
+	}, ctx.Done())
+	if err != nil {
+		return "", fmt.Errorf("while waiting for ready Pod: %w", err)
+	}
+	return ip, nil
+}
+
+func (a *Activator) proxy(ip string) *httputil.ReverseProxy {
+	host := net.JoinHostPort(ip, fmt.Sprint(a.config.FunctionPort))
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = host
+		},
+	}
+}
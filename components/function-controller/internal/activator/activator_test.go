@@ -0,0 +1,160 @@
+package activator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type fakeScaler struct {
+	hosts    map[string]types.NamespacedName
+	ip       string
+	scaleUps int32
+	scaleErr error
+
+	mu    sync.Mutex
+	ready bool
+}
+
+func (s *fakeScaler) Resolve(host string) (types.NamespacedName, bool) {
+	function, ok := s.hosts[host]
+	return function, ok
+}
+
+func (s *fakeScaler) ScaleUp(_ context.Context, _ types.NamespacedName) error {
+	atomic.AddInt32(&s.scaleUps, 1)
+	if s.scaleErr != nil {
+		return s.scaleErr
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		s.mu.Lock()
+		s.ready = true
+		s.mu.Unlock()
+	}()
+	return nil
+}
+
+func (s *fakeScaler) ReadyPodIP(_ context.Context, _ types.NamespacedName) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ready {
+		return "", nil
+	}
+	return s.ip, nil
+}
+
+func TestActivator_ServeHTTP(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	function := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.Host, body)
+	}))
+	defer function.Close()
+	ip, port := splitHostPort(g, function.Listener.Addr().String())
+
+	scaler := &fakeScaler{ip: ip, hosts: fixHosts()}
+	activator := New(scaler, Config{
+		FunctionPort:        port,
+		ActivationTimeout:   time.Second,
+		PollInterval:        5 * time.Millisecond,
+		MaxBufferedRequests: 10,
+	}, zap.New())
+
+	// when
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 5)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "http://fn.test.svc.cluster.local/", strings.NewReader(fmt.Sprint(i)))
+			responses[i] = httptest.NewRecorder()
+			activator.ServeHTTP(responses[i], req)
+		}(i)
+	}
+	wg.Wait()
+
+	// then
+	for i, response := range responses {
+		g.Expect(response.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(response.Body.String()).To(gomega.Equal(fmt.Sprintf("fn.test.svc.cluster.local %d", i)))
+	}
+	g.Expect(atomic.LoadInt32(&scaler.scaleUps)).To(gomega.BeNumerically("<=", 1))
+}
+
+func TestActivator_ServeHTTP_errors(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		host                string
+		scaler              *fakeScaler
+		maxBufferedRequests int64
+
+		expectedStatus int
+	}{
+		"should reject unknown host": {
+			host:                "10.0.0.1",
+			scaler:              &fakeScaler{hosts: fixHosts()},
+			maxBufferedRequests: 10,
+			expectedStatus:      http.StatusNotFound,
+		},
+		"should reject requests over the buffer limit": {
+			host:           "fn.test",
+			scaler:         &fakeScaler{hosts: fixHosts()},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		"should fail if the Function cannot be scaled": {
+			host:                "fn.test",
+			scaler:              &fakeScaler{hosts: fixHosts(), scaleErr: fmt.Errorf("forbidden")},
+			maxBufferedRequests: 10,
+			expectedStatus:      http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			activator := New(testData.scaler, Config{
+				ActivationTimeout:   time.Second,
+				PollInterval:        5 * time.Millisecond,
+				MaxBufferedRequests: testData.maxBufferedRequests,
+			}, zap.New())
+			req := httptest.NewRequest(http.MethodGet, "http://"+testData.host+"/", nil)
+			response := httptest.NewRecorder()
+
+			// when
+			activator.ServeHTTP(response, req)
+
+			// then
+			g.Expect(response.Code).To(gomega.Equal(testData.expectedStatus))
+		})
+	}
+}
+
+func fixHosts() map[string]types.NamespacedName {
+	function := types.NamespacedName{Namespace: "test", Name: "fn"}
+	return map[string]types.NamespacedName{
+		"fn.test":                   function,
+		"fn.test.svc.cluster.local": function,
+	}
+}
+
+func splitHostPort(g *gomega.WithT, address string) (string, int) {
+	host, port, err := net.SplitHostPort(address)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	portNumber, err := strconv.Atoi(port)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return host, portNumber
+}
@@ -0,0 +1,142 @@
+package activator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+// hostIndex indexes the Function Endpoints by the hosts of the Function which is routed to the activator
+const hostIndex = "host"
+
+var _ Scaler = &KubernetesScaler{}
+
+// KubernetesScaler scales the Deployment of the Function to one replica, the Function Controller scales it further
+// according to the request concurrency. The Functions and their ready Pods are read from the cached Endpoints managed
+// by the Function Controller, which point to the activator while the Function has no ready Pods.
+type KubernetesScaler struct {
+	client    client.Client
+	endpoints cache.Indexer
+}
+
+// NewKubernetesScaler returns the scaler which reads the Function Endpoints from the given indexer, it has to be
+// created with the EndpointsIndexers.
+func NewKubernetesScaler(client client.Client, endpoints cache.Indexer) *KubernetesScaler {
+	return &KubernetesScaler{client: client, endpoints: endpoints}
+}
+
+// EndpointsIndexers returns the indexers of the Endpoints informer used by the KubernetesScaler
+func EndpointsIndexers() cache.Indexers {
+	return cache.Indexers{hostIndex: indexActivatorHosts}
+}
+
+func indexActivatorHosts(obj interface{}) ([]string, error) {
+	endpoints, ok := obj.(*corev1.Endpoints)
+	if !ok {
+		return nil, nil
+	}
+	hosts, ok := endpoints.GetAnnotations()[serverlessv1alpha1.FunctionActivatorHostsAnnotation]
+	if !ok || hosts == "" {
+		return nil, nil
+	}
+	return strings.Split(hosts, ","), nil
+}
+
+// Resolve returns the Function routed to the activator under the given host, the host of an APIRule without the domain
+// matches the first label of the given host. Ambiguous hosts aren't resolved.
+func (s *KubernetesScaler) Resolve(host string) (types.NamespacedName, bool) {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	host = strings.ToLower(host)
+
+	objects, err := s.endpoints.ByIndex(hostIndex, host)
+	if err == nil && len(objects) == 0 {
+		objects, err = s.endpoints.ByIndex(hostIndex, strings.SplitN(host, ".", 2)[0])
+	}
+	if err != nil || len(objects) != 1 {
+		return types.NamespacedName{}, false
+	}
+	endpoints := objects[0].(*corev1.Endpoints)
+	return types.NamespacedName{Namespace: endpoints.GetNamespace(), Name: endpoints.GetName()}, true
+}
+
+func (s *KubernetesScaler) ScaleUp(ctx context.Context, function types.NamespacedName) error {
+	deployment, err := s.getDeployment(ctx, function)
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas > 0 {
+		return nil
+	}
+
+	patch := client.MergeFrom(deployment.DeepCopy())
+	replicas := int32(1)
+	deployment.Spec.Replicas = &replicas
+	if deployment.Annotations == nil {
+		deployment.Annotations = make(map[string]string)
+	}
+	deployment.Annotations[serverlessv1alpha1.FunctionActivationTimeAnnotation] = time.Now().UTC().Format(time.RFC3339)
+
+	return s.client.Patch(ctx, deployment, patch)
+}
+
+// ReadyPodIP returns the IP of a Function Pod from the Function Endpoints, the Function Controller points them to the
+// ready Function Pods as soon as there are any
+func (s *KubernetesScaler) ReadyPodIP(_ context.Context, function types.NamespacedName) (string, error) {
+	obj, exists, err := s.endpoints.GetByKey(function.String())
+	if err != nil || !exists {
+		return "", err
+	}
+
+	for _, subset := range obj.(*corev1.Endpoints).Subsets {
+		for _, address := range subset.Addresses {
+			if isFunctionPod(address.TargetRef, function) {
+				return address.IP, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// isFunctionPod checks if the address references a Pod of the Function, the Pods of its Deployments are named after it
+func isFunctionPod(ref *corev1.ObjectReference, function types.NamespacedName) bool {
+	return ref != nil && ref.Kind == "Pod" && ref.Namespace == function.Namespace && strings.HasPrefix(ref.Name, function.Name+"-")
+}
+
+// getDeployment returns the stable Deployment of the Function, the canary Deployment of the rollout is scaled by the
+// Function Controller
+func (s *KubernetesScaler) getDeployment(ctx context.Context, function types.NamespacedName) (*appsv1.Deployment, error) {
+	var deployments appsv1.DeploymentList
+	if err := s.client.List(ctx, &deployments, client.InNamespace(function.Namespace), client.MatchingLabels(functionLabels(function))); err != nil {
+		return nil, err
+	}
+
+	var stable []appsv1.Deployment
+	for _, deployment := range deployments.Items {
+		if deployment.GetLabels()[serverlessv1alpha1.FunctionRolloutRoleLabel] != serverlessv1alpha1.FunctionRolloutRoleCanaryValue {
+			stable = append(stable, deployment)
+		}
+	}
+	if len(stable) != 1 {
+		return nil, fmt.Errorf("expected one stable Deployment, found %d", len(stable))
+	}
+	return &stable[0], nil
+}
+
+func functionLabels(function types.NamespacedName) map[string]string {
+	return map[string]string{
+		serverlessv1alpha1.FunctionNameLabel:      function.Name,
+		serverlessv1alpha1.FunctionManagedByLabel: serverlessv1alpha1.FunctionControllerValue,
+	}
+}
@@ -0,0 +1,134 @@
+package activator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+func TestKubernetesScaler_ScaleUp(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	zero := int32(0)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn-abcde", Namespace: "test", Labels: fixFunctionLabels("fn")},
+		Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+	}
+	canaryLabels := fixFunctionLabels("fn")
+	canaryLabels[serverlessv1alpha1.FunctionRolloutRoleLabel] = serverlessv1alpha1.FunctionRolloutRoleCanaryValue
+	canary := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn-canary-fghij", Namespace: "test", Labels: canaryLabels},
+		Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+	}
+	client := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, deployment, canary)
+	scaler := NewKubernetesScaler(client, fixEndpointsIndexer(g))
+
+	// when
+	err := scaler.ScaleUp(context.TODO(), types.NamespacedName{Namespace: "test", Name: "fn"})
+
+	// then
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	var scaled appsv1.Deployment
+	g.Expect(client.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: "fn-abcde"}, &scaled)).To(gomega.Succeed())
+	g.Expect(*scaled.Spec.Replicas).To(gomega.Equal(int32(1)))
+	g.Expect(scaled.Annotations).To(gomega.HaveKey(serverlessv1alpha1.FunctionActivationTimeAnnotation))
+
+	g.Expect(client.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: "fn-canary-fghij"}, &scaled)).To(gomega.Succeed())
+	g.Expect(*scaled.Spec.Replicas).To(gomega.BeZero())
+}
+
+func TestKubernetesScaler_ReadyPodIP(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	scaler := NewKubernetesScaler(fake.NewFakeClientWithScheme(clientgoscheme.Scheme), fixEndpointsIndexer(g,
+		fixEndpoints("fn", "", corev1.EndpointAddress{IP: "10.0.0.4", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "test", Name: "fn-abcde-1"}}),
+		fixEndpoints("scaled-to-zero", "scaled-to-zero.test", corev1.EndpointAddress{IP: "10.1.0.1"}),
+		// the Endpoints copied from the activator before the activator references were dropped
+		fixEndpoints("activator-refs", "activator-refs.test", corev1.EndpointAddress{IP: "10.1.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "kyma-system", Name: "function-activator-abcde-1"}}),
+		fixEndpoints("foreign-pod", "", corev1.EndpointAddress{IP: "10.0.0.5", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "test", Name: "fn-abcde-2"}}),
+	))
+
+	for name, expected := range map[string]string{
+		"fn":             "10.0.0.4",
+		"scaled-to-zero": "",
+		"activator-refs": "",
+		"foreign-pod":    "",
+		"unknown":        "",
+	} {
+		// when
+		ip, err := scaler.ReadyPodIP(context.TODO(), types.NamespacedName{Namespace: "test", Name: name})
+
+		// then
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(ip).To(gomega.Equal(expected), name)
+	}
+}
+
+func TestKubernetesScaler_Resolve(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	scaler := NewKubernetesScaler(fake.NewFakeClientWithScheme(clientgoscheme.Scheme), fixEndpointsIndexer(g,
+		fixEndpoints("fn", "fn.test,fn.test.svc,fn.test.svc.cluster.local,fn.kyma.example.com", corev1.EndpointAddress{IP: "10.1.0.1"}),
+		fixEndpoints("short", "short.test,short.test.svc,short.test.svc.cluster.local,short", corev1.EndpointAddress{IP: "10.1.0.1"}),
+		fixEndpoints("ambiguous-1", "ambiguous-1.test,shared.kyma.example.com", corev1.EndpointAddress{IP: "10.1.0.1"}),
+		fixEndpoints("ambiguous-2", "ambiguous-2.test,shared.kyma.example.com", corev1.EndpointAddress{IP: "10.1.0.1"}),
+		fixEndpoints("running", "", corev1.EndpointAddress{IP: "10.0.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "running-1"}}),
+	))
+
+	for host, expected := range map[string]*types.NamespacedName{
+		"fn.test":                        {Namespace: "test", Name: "fn"},
+		"fn.test.svc.cluster.local:8080": {Namespace: "test", Name: "fn"},
+		"FN.Kyma.Example.com":            {Namespace: "test", Name: "fn"},
+		"short.kyma.example.com":         {Namespace: "test", Name: "short"},
+		"shared.kyma.example.com":        nil,
+		"running.test":                   nil,
+		"other.test":                     nil,
+		"10.0.0.1:8080":                  nil,
+	} {
+		// when
+		function, ok := scaler.Resolve(host)
+
+		// then
+		g.Expect(ok).To(gomega.Equal(expected != nil), host)
+		if expected != nil {
+			g.Expect(function).To(gomega.Equal(*expected), host)
+		}
+	}
+}
+
+func fixEndpointsIndexer(g *gomega.WithT, endpoints ...*corev1.Endpoints) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, EndpointsIndexers())
+	for _, e := range endpoints {
+		g.Expect(indexer.Add(e)).To(gomega.Succeed())
+	}
+	return indexer
+}
+
+func fixEndpoints(name, hosts string, address corev1.EndpointAddress) *corev1.Endpoints {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: fixFunctionLabels(name)},
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{address}}},
+	}
+	if hosts != "" {
+		endpoints.Annotations = map[string]string{serverlessv1alpha1.FunctionActivatorHostsAnnotation: hosts}
+	}
+	return endpoints
+}
+
+func fixFunctionLabels(name string) map[string]string {
+	return map[string]string{
+		serverlessv1alpha1.FunctionNameLabel:      name,
+		serverlessv1alpha1.FunctionManagedByLabel: serverlessv1alpha1.FunctionControllerValue,
+	}
+}
@@ -14,6 +14,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
@@ -22,6 +23,8 @@ import (
 
 const (
 	destinationArg        = "--destination"
+	cacheRepoArg          = "--cache-repo"
+	dockerfileKey         = "Dockerfile"
 	functionContainerName = "function"
 	baseDir               = "/workspace/src/"
 	workspaceMountPath    = "/workspace"
@@ -60,9 +63,7 @@ func (r *FunctionReconciler) buildJob(instance *serverlessv1alpha1.Function, rtm
 	rootUser := int64(0)
 	optional := true
 
-	imageName := r.buildImageAddress(instance, dockerConfig.PushAddress)
-	args := r.config.Build.ExecutorArgs
-	args = append(args, fmt.Sprintf("%s=%s", destinationArg, imageName), fmt.Sprintf("--context=dir://%s", workspaceMountPath))
+	args := r.buildExecutorArgs(instance, dockerConfig)
 
 	return batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -156,7 +157,7 @@ func (r *FunctionReconciler) getBuildJobVolumeMounts(rtmConfig runtime.Config) [
 		{Name: "credentials", ReadOnly: true, MountPath: "/docker"},
 	}
 	// add package registry config volume mount depending on the used runtime
-	volumeMounts = append(volumeMounts, r.getPackageConfigVolumeMountsForRuntime(rtmConfig.Runtime)...)
+	volumeMounts = append(volumeMounts, r.getPackageConfigVolumeMountsForRuntime(rtmConfig)...)
 	return volumeMounts
 }
 
@@ -167,8 +168,9 @@ func buildRepoFetcherEnvVars(instance *serverlessv1alpha1.Function, gitOptions g
 			Value: gitOptions.URL,
 		},
 		{
+			// the resolved commit is used since tags and semver ranges can point to another commit at build time
 			Name:  "APP_REPOSITORY_COMMIT",
-			Value: instance.Status.Repository.Reference,
+			Value: instance.Status.Commit,
 		},
 		{
 			Name:  "APP_MOUNT_PATH",
@@ -242,9 +244,7 @@ func buildRepoFetcherEnvVars(instance *serverlessv1alpha1.Function, gitOptions g
 }
 
 func (r *FunctionReconciler) buildGitJob(instance *serverlessv1alpha1.Function, gitOptions git.Options, rtmConfig runtime.Config, dockerConfig DockerConfig) batchv1.Job {
-	imageName := r.buildImageAddress(instance, dockerConfig.PushAddress)
-	args := r.config.Build.ExecutorArgs
-	args = append(args, fmt.Sprintf("%s=%s", destinationArg, imageName), fmt.Sprintf("--context=dir://%s", workspaceMountPath))
+	args := r.buildExecutorArgs(instance, dockerConfig)
 
 	one := int32(1)
 	zero := int32(0)
@@ -352,13 +352,13 @@ func (r *FunctionReconciler) getGitBuildJobVolumeMounts(instance *serverlessv1al
 		{Name: "runtime", ReadOnly: true, MountPath: path.Join(workspaceMountPath, "Dockerfile"), SubPath: "Dockerfile"},
 	}
 	// add package registry config volume mount depending on the used runtime
-	volumeMounts = append(volumeMounts, r.getPackageConfigVolumeMountsForRuntime(rtmConfig.Runtime)...)
+	volumeMounts = append(volumeMounts, r.getPackageConfigVolumeMountsForRuntime(rtmConfig)...)
 	return volumeMounts
 
 }
 
-func (r *FunctionReconciler) buildDeployment(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, dockerConfig DockerConfig) appsv1.Deployment {
-	imageName := r.buildImageAddress(instance, dockerConfig.PullAddress)
+func (r *FunctionReconciler) buildDeployment(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, dockerConfig DockerConfig, mountsChecksum string) appsv1.Deployment {
+	imageName := r.buildDeploymentImageAddress(instance, dockerConfig)
 	deploymentLabels := r.functionLabels(instance)
 	podLabels := r.podLabels(instance)
 
@@ -368,6 +368,37 @@ func (r *FunctionReconciler) buildDeployment(instance *serverlessv1alpha1.Functi
 
 	envs := append(instance.Spec.Env, rtmConfig.RuntimeEnvs...)
 	envs = append(envs, envVarsForDeployment...)
+	envs = append(envs, r.serviceBindingEnvs(instance)...)
+
+	podAnnotations := map[string]string{
+		"proxy.istio.io/config": "{ \"holdApplicationUntilProxyStarts\": true }",
+	}
+	if mountsChecksum != "" {
+		podAnnotations[serverlessv1alpha1.FunctionMountsChecksumAnnotation] = mountsChecksum
+	}
+
+	volumes := []corev1.Volume{{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium:    corev1.StorageMediumDefault,
+				SizeLimit: &emptyDirVolumeSize,
+			},
+		},
+	}}
+	volumeMounts := []corev1.VolumeMount{{
+		Name: volumeName,
+		/* needed in order to have python functions working:
+		python functions need writable /tmp dir, but we disable writing to root filesystem via
+		security context below. That's why we override this whole /tmp directory with emptyDir volume.
+		We've decided to add this directory to be writable by all functions, as it may come in handy
+		*/
+		MountPath: "/tmp",
+		ReadOnly:  false,
+	}}
+	mountVolumes, mounts := r.buildMounts(instance)
+	volumes = append(volumes, mountVolumes...)
+	volumeMounts = append(volumeMounts, mounts...)
 
 	return appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -383,37 +414,18 @@ func (r *FunctionReconciler) buildDeployment(instance *serverlessv1alpha1.Functi
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: podLabels, // podLabels contains InternalFnLabels, so it's ok
-					Annotations: map[string]string{
-						"proxy.istio.io/config": "{ \"holdApplicationUntilProxyStarts\": true }",
-					},
+					Labels:      podLabels, // podLabels contains InternalFnLabels, so it's ok
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					Volumes: []corev1.Volume{{
-						Name: volumeName,
-						VolumeSource: corev1.VolumeSource{
-							EmptyDir: &corev1.EmptyDirVolumeSource{
-								Medium:    corev1.StorageMediumDefault,
-								SizeLimit: &emptyDirVolumeSize,
-							},
-						},
-					}},
+					Volumes: volumes,
 					Containers: []corev1.Container{
 						{
-							Name:      functionContainerName,
-							Image:     imageName,
-							Env:       envs,
-							Resources: instance.Spec.Resources,
-							VolumeMounts: []corev1.VolumeMount{{
-								Name: volumeName,
-								/* needed in order to have python functions working:
-								python functions need writable /tmp dir, but we disable writing to root filesystem via
-								security context below. That's why we override this whole /tmp directory with emptyDir volume.
-								We've decided to add this directory to be writable by all functions, as it may come in handy
-								*/
-								MountPath: "/tmp",
-								ReadOnly:  false,
-							}},
+							Name:         functionContainerName,
+							Image:        imageName,
+							Env:          envs,
+							Resources:    instance.Spec.Resources,
+							VolumeMounts: volumeMounts,
 							/*
 								In order to mark pod as ready we need to ensure the function is actually running and ready to serve traffic.
 								We do this but first ensuring that sidecar is raedy by using "proxy.istio.io/config": "{ \"holdApplicationUntilProxyStarts\": true }", annotation
@@ -464,6 +476,81 @@ func (r *FunctionReconciler) buildDeployment(instance *serverlessv1alpha1.Functi
 	}
 }
 
+// buildCanaryDeployment returns the Deployment of the new revision, which receives a part of the Function traffic
+// during the rollout. Its Pods have their own selector labels, so they are reached only through the canary Service.
+func (r *FunctionReconciler) buildCanaryDeployment(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, dockerConfig DockerConfig, mountsChecksum string, image string, replicas int32) appsv1.Deployment {
+	deployment := r.buildDeployment(instance, rtmConfig, dockerConfig, mountsChecksum)
+
+	deployment.GenerateName = fmt.Sprintf("%s-canary-", instance.GetName())
+	deployment.Labels[serverlessv1alpha1.FunctionRolloutRoleLabel] = serverlessv1alpha1.FunctionRolloutRoleCanaryValue
+	deployment.Spec.Replicas = &replicas
+	deployment.Spec.Selector.MatchLabels = r.canarySelectorLabels(instance)
+	deployment.Spec.Template.Labels = r.mergeLabels(instance.Spec.Labels, r.canarySelectorLabels(instance))
+	deployment.Spec.Template.Spec.Containers[0].Image = image
+	return deployment
+}
+
+// buildCanaryService returns the Service of the Pods running the new revision, the VirtualService routes the canary
+// share of the Function traffic to it
+func (r *FunctionReconciler) buildCanaryService(instance *serverlessv1alpha1.Function) corev1.Service {
+	service := r.buildService(instance)
+
+	service.Name = ""
+	service.GenerateName = fmt.Sprintf("%s-canary-", instance.GetName())
+	service.Labels[serverlessv1alpha1.FunctionRolloutRoleLabel] = serverlessv1alpha1.FunctionRolloutRoleCanaryValue
+	service.Spec.Selector = r.canarySelectorLabels(instance)
+	return service
+}
+
+// buildVirtualService returns the VirtualService which splits the traffic of the Function between the Function Service
+// and the canary Service. It's bound to the mesh and to the gateways of the APIRules exposing the Function, so the
+// split applies to the requests from outside of the cluster as well.
+func (r *FunctionReconciler) buildVirtualService(instance *serverlessv1alpha1.Function, apiRuleHosts, apiRuleGateways []string, canaryService string, stableWeight, canaryWeight int32) unstructured.Unstructured {
+	var route []interface{}
+	for _, destination := range []struct {
+		host   string
+		weight int32
+	}{{r.serviceHost(instance), stableWeight}, {fmt.Sprintf("%s.%s.svc.cluster.local", canaryService, instance.GetNamespace()), canaryWeight}} {
+		if destination.weight == 0 {
+			continue
+		}
+		route = append(route, map[string]interface{}{
+			"destination": map[string]interface{}{
+				"host": destination.host,
+			},
+			"weight": int64(destination.weight),
+		})
+	}
+
+	hosts := []interface{}{r.serviceHost(instance)}
+	for _, host := range apiRuleHosts {
+		hosts = append(hosts, host)
+	}
+	gateways := []interface{}{meshGateway}
+	for _, gateway := range apiRuleGateways {
+		gateways = append(gateways, gateway)
+	}
+
+	virtualService := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"hosts":    hosts,
+			"gateways": gateways,
+			"http": []interface{}{
+				map[string]interface{}{"route": route},
+			},
+		},
+	}}
+	virtualService.SetGroupVersionKind(virtualServiceGVK)
+	virtualService.SetName(instance.GetName())
+	virtualService.SetNamespace(instance.GetNamespace())
+	virtualService.SetLabels(r.functionLabels(instance))
+	return virtualService
+}
+
+func (r *FunctionReconciler) serviceHost(instance *serverlessv1alpha1.Function) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", instance.GetName(), instance.GetNamespace())
+}
+
 func (r *FunctionReconciler) buildService(instance *serverlessv1alpha1.Function) corev1.Service {
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -478,11 +565,20 @@ func (r *FunctionReconciler) buildService(instance *serverlessv1alpha1.Function)
 				Port:       80,
 				Protocol:   corev1.ProtocolTCP,
 			}},
-			Selector: r.deploymentSelectorLabels(instance),
+			Selector: r.serviceSelectorLabels(instance),
 		},
 	}
 }
 
+// serviceSelectorLabels are nil for the Function scaled to zero, since its Endpoints are managed by the Function
+// Controller
+func (r *FunctionReconciler) serviceSelectorLabels(instance *serverlessv1alpha1.Function) map[string]string {
+	if isScaleToZeroEnabled(instance) {
+		return nil
+	}
+	return r.deploymentSelectorLabels(instance)
+}
+
 func (r *FunctionReconciler) buildHorizontalPodAutoscaler(instance *serverlessv1alpha1.Function, deploymentName string) autoscalingv1.HorizontalPodAutoscaler {
 	minReplicas, maxReplicas := r.defaultReplicas(instance.Spec)
 	return autoscalingv1.HorizontalPodAutoscaler{
@@ -518,7 +614,48 @@ func (r *FunctionReconciler) defaultReplicas(spec serverlessv1alpha1.FunctionSpe
 	return min, max
 }
 
-func (r *FunctionReconciler) buildImageAddress(instance *serverlessv1alpha1.Function, registryAddress string) string {
+func (r *FunctionReconciler) buildExecutorArgs(instance *serverlessv1alpha1.Function, dockerConfig DockerConfig) []string {
+	imageName := r.buildImageAddress(instance, dockerConfig.PushAddress, dockerConfig.BuildCacheKey)
+
+	args := make([]string, 0, len(r.config.Build.ExecutorArgs)+3)
+	args = append(args, r.config.Build.ExecutorArgs...)
+	args = append(args, fmt.Sprintf("%s=%s", destinationArg, imageName), fmt.Sprintf("--context=dir://%s", workspaceMountPath))
+	if dockerConfig.BuildCacheKey != "" {
+		// the layers are shared by all Functions, so the dependencies layer is reused when only the source changes
+		args = append(args, fmt.Sprintf("%s=%s/%s/layers", cacheRepoArg, dockerConfig.PushAddress, r.config.Build.Cache.Repository))
+	}
+	return args
+}
+
+// buildPullImageAddress returns the address of the image which is run by the Function, the image found in the build
+// cache is pinned to its digest.
+func (r *FunctionReconciler) buildPullImageAddress(instance *serverlessv1alpha1.Function, dockerConfig DockerConfig) string {
+	if r.isBuildCacheHit(instance, dockerConfig) {
+		return instance.Status.Image
+	}
+	return r.buildImageAddress(instance, dockerConfig.PullAddress, dockerConfig.BuildCacheKey)
+}
+
+// buildDeploymentImageAddress returns the image of the Function Deployment, which keeps running the previous revision
+// until the rollout of the new one is promoted.
+func (r *FunctionReconciler) buildDeploymentImageAddress(instance *serverlessv1alpha1.Function, dockerConfig DockerConfig) string {
+	image := r.buildPullImageAddress(instance, dockerConfig)
+	if rollout := instance.Status.Rollout; instance.Spec.Rollout != nil && rollout != nil && rollout.Image == image {
+		switch rollout.Phase {
+		case serverlessv1alpha1.RolloutPhaseProgressing, serverlessv1alpha1.RolloutPhaseAborted, serverlessv1alpha1.RolloutPhaseRolledBack:
+			return rollout.StableImage
+		}
+	}
+	return image
+}
+
+// buildImageAddress returns the address of the Function image in the registry. With the build cache, the image is tagged
+// with the build cache key in the repository shared by all Functions.
+func (r *FunctionReconciler) buildImageAddress(instance *serverlessv1alpha1.Function, registryAddress, buildCacheKey string) string {
+	if buildCacheKey != "" {
+		return r.buildCacheImageAddress(registryAddress, buildCacheKey)
+	}
+
 	var imageTag string
 	if instance.Spec.Type == serverlessv1alpha1.SourceTypeGit {
 		imageTag = r.calculateGitImageTag(instance)
@@ -528,6 +665,10 @@ func (r *FunctionReconciler) buildImageAddress(instance *serverlessv1alpha1.Func
 	return fmt.Sprintf("%s/%s-%s:%s", registryAddress, instance.Namespace, instance.Name, imageTag)
 }
 
+func (r *FunctionReconciler) buildCacheImageAddress(registryAddress, buildCacheKey string) string {
+	return fmt.Sprintf("%s/%s:%s", registryAddress, r.config.Build.Cache.Repository, buildCacheKey)
+}
+
 func (r *FunctionReconciler) functionLabels(instance *serverlessv1alpha1.Function) map[string]string {
 	return r.mergeLabels(instance.GetLabels(), r.internalFunctionLabels(instance))
 }
@@ -546,6 +687,10 @@ func (r *FunctionReconciler) deploymentSelectorLabels(instance *serverlessv1alph
 	return r.mergeLabels(map[string]string{serverlessv1alpha1.FunctionResourceLabel: serverlessv1alpha1.FunctionResourceLabelDeploymentValue}, r.internalFunctionLabels(instance))
 }
 
+func (r *FunctionReconciler) canarySelectorLabels(instance *serverlessv1alpha1.Function) map[string]string {
+	return r.mergeLabels(map[string]string{serverlessv1alpha1.FunctionResourceLabel: serverlessv1alpha1.FunctionResourceLabelCanaryDeploymentValue}, r.internalFunctionLabels(instance))
+}
+
 func (r *FunctionReconciler) podLabels(instance *serverlessv1alpha1.Function) map[string]string {
 	return r.mergeLabels(instance.Spec.Labels, r.deploymentSelectorLabels(instance))
 }
@@ -560,12 +705,14 @@ func (r *FunctionReconciler) mergeLabels(labelsCollection ...map[string]string)
 	return result
 }
 
-func (r *FunctionReconciler) getPackageConfigVolumeMountsForRuntime(rtm serverlessv1alpha1.Runtime) []corev1.VolumeMount {
-	switch rtm {
-	case serverlessv1alpha1.Nodejs12, serverlessv1alpha1.Nodejs14:
-		return []corev1.VolumeMount{{Name: "registry-config", ReadOnly: true, MountPath: path.Join(workspaceMountPath, "registry-config/.npmrc"), SubPath: ".npmrc"}}
-	case serverlessv1alpha1.Python38, serverlessv1alpha1.Python39:
-		return []corev1.VolumeMount{{Name: "registry-config", ReadOnly: true, MountPath: path.Join(workspaceMountPath, "registry-config/pip.conf"), SubPath: "pip.conf"}}
+func (r *FunctionReconciler) getPackageConfigVolumeMountsForRuntime(rtmConfig runtime.Config) []corev1.VolumeMount {
+	if rtmConfig.PackageRegistryConfigFile == "" {
+		return nil
 	}
-	return nil
+	return []corev1.VolumeMount{{
+		Name:      "registry-config",
+		ReadOnly:  true,
+		MountPath: path.Join(workspaceMountPath, "registry-config", rtmConfig.PackageRegistryConfigFile),
+		SubPath:   rtmConfig.PackageRegistryConfigFile,
+	}}
 }
@@ -75,7 +75,7 @@ func TestFunctionReconciler_buildDeployment(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := gomega.NewGomegaWithT(t)
 			r := &FunctionReconciler{}
-			got := r.buildDeployment(tt.args.instance, rtmCfg, DockerConfig{})
+			got := r.buildDeployment(tt.args.instance, rtmCfg, DockerConfig{}, "")
 
 			for key, value := range got.Spec.Selector.MatchLabels {
 				g.Expect(got.Spec.Template.Labels[key]).To(gomega.Equal(value))
@@ -420,17 +420,19 @@ func TestFunctionReconciler_buildJob(t *testing.T) {
 	}
 
 	testCases := []struct {
-		Name                 string
-		Runtime              serverlessv1alpha1.Runtime
-		ExpectedVolumesLen   int
-		ExpectedVolumes      []expectedVolume
-		ExpectedMountsLen    int
-		ExpectedVolumeMounts []corev1.VolumeMount
+		Name                      string
+		Runtime                   serverlessv1alpha1.Runtime
+		PackageRegistryConfigFile string
+		ExpectedVolumesLen        int
+		ExpectedVolumes           []expectedVolume
+		ExpectedMountsLen         int
+		ExpectedVolumeMounts      []corev1.VolumeMount
 	}{
 		{
-			Name:               "Success Node12",
-			Runtime:            serverlessv1alpha1.Nodejs12,
-			ExpectedVolumesLen: 4,
+			Name:                      "Success Node12",
+			Runtime:                   serverlessv1alpha1.Nodejs12,
+			PackageRegistryConfigFile: ".npmrc",
+			ExpectedVolumesLen:        4,
 			ExpectedVolumes: []expectedVolume{
 				{name: "sources", localObjectReference: cmName},
 				{name: "runtime", localObjectReference: rtmCfg.DockerfileConfigMapName},
@@ -447,9 +449,10 @@ func TestFunctionReconciler_buildJob(t *testing.T) {
 			},
 		},
 		{
-			Name:               "Success Node14",
-			Runtime:            serverlessv1alpha1.Nodejs14,
-			ExpectedVolumesLen: 4,
+			Name:                      "Success Node14",
+			Runtime:                   serverlessv1alpha1.Nodejs14,
+			PackageRegistryConfigFile: ".npmrc",
+			ExpectedVolumesLen:        4,
 			ExpectedVolumes: []expectedVolume{
 				{name: "sources", localObjectReference: cmName},
 				{name: "runtime", localObjectReference: rtmCfg.DockerfileConfigMapName},
@@ -466,9 +469,10 @@ func TestFunctionReconciler_buildJob(t *testing.T) {
 			},
 		},
 		{
-			Name:               "Success Python38",
-			Runtime:            serverlessv1alpha1.Python38,
-			ExpectedVolumesLen: 4,
+			Name:                      "Success Python38",
+			Runtime:                   serverlessv1alpha1.Python38,
+			PackageRegistryConfigFile: "pip.conf",
+			ExpectedVolumesLen:        4,
 			ExpectedVolumes: []expectedVolume{
 				{name: "sources", localObjectReference: cmName},
 				{name: "runtime", localObjectReference: rtmCfg.DockerfileConfigMapName},
@@ -485,9 +489,10 @@ func TestFunctionReconciler_buildJob(t *testing.T) {
 			},
 		},
 		{
-			Name:               "Success Python39",
-			Runtime:            serverlessv1alpha1.Python39,
-			ExpectedVolumesLen: 4,
+			Name:                      "Success Python39",
+			Runtime:                   serverlessv1alpha1.Python39,
+			PackageRegistryConfigFile: "pip.conf",
+			ExpectedVolumesLen:        4,
 			ExpectedVolumes: []expectedVolume{
 				{name: "sources", localObjectReference: cmName},
 				{name: "runtime", localObjectReference: rtmCfg.DockerfileConfigMapName},
@@ -503,11 +508,30 @@ func TestFunctionReconciler_buildJob(t *testing.T) {
 				{Name: "registry-config", MountPath: "/workspace/registry-config/pip.conf", SubPath: "pip.conf", ReadOnly: true},
 			},
 		},
+		{
+			Name:               "Success runtime without package registry configuration",
+			Runtime:            "go116",
+			ExpectedVolumesLen: 4,
+			ExpectedVolumes: []expectedVolume{
+				{name: "sources", localObjectReference: cmName},
+				{name: "runtime", localObjectReference: rtmCfg.DockerfileConfigMapName},
+				{name: "credentials", localObjectReference: dockerCfg.ActiveRegistryConfigSecretName},
+				{name: "registry-config", localObjectReference: r.config.PackageRegistryConfigSecretName},
+			},
+			ExpectedMountsLen: 4,
+			ExpectedVolumeMounts: []corev1.VolumeMount{
+				{Name: "sources", MountPath: "/workspace/src/deps.txt", SubPath: FunctionDepsKey, ReadOnly: true},
+				{Name: "sources", MountPath: "/workspace/src/function.abap", SubPath: FunctionSourceKey, ReadOnly: true},
+				{Name: "runtime", MountPath: "/workspace/Dockerfile", SubPath: "Dockerfile", ReadOnly: true},
+				{Name: "credentials", MountPath: "/docker", ReadOnly: true},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(t *testing.T) {
 			rtmCfg.Runtime = testCase.Runtime
+			rtmCfg.PackageRegistryConfigFile = testCase.PackageRegistryConfigFile
 
 			// when
 			job := r.buildJob(&instance, rtmCfg, cmName, dockerCfg)
@@ -524,6 +548,103 @@ func TestFunctionReconciler_buildJob(t *testing.T) {
 	}
 }
 
+func TestFunctionReconciler_buildExecutorArgs(t *testing.T) {
+	instance := &serverlessv1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "function", Namespace: "test"}}
+	r := FunctionReconciler{
+		config: FunctionConfig{
+			Build: BuildConfig{
+				ExecutorArgs: []string{"--insecure", "--cache=true"},
+				Cache:        BuildCacheConfig{Repository: "function-cache"},
+			},
+		},
+	}
+
+	t.Run("should push the Function image", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		args := r.buildExecutorArgs(instance, DockerConfig{PushAddress: "registry.kyma.local"})
+
+		g.Expect(args).To(gomega.Equal([]string{
+			"--insecure",
+			"--cache=true",
+			"--destination=" + r.buildImageAddress(instance, "registry.kyma.local", ""),
+			"--context=dir:///workspace",
+		}))
+		g.Expect(args[2]).To(gomega.HavePrefix("--destination=registry.kyma.local/test-function:"))
+	})
+
+	t.Run("should push the image to the build cache and share the layers", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		args := r.buildExecutorArgs(instance, DockerConfig{PushAddress: "registry.kyma.local", BuildCacheKey: "abc"})
+
+		g.Expect(args).To(gomega.Equal([]string{
+			"--insecure",
+			"--cache=true",
+			"--destination=registry.kyma.local/function-cache:abc",
+			"--context=dir:///workspace",
+			"--cache-repo=registry.kyma.local/function-cache/layers",
+		}))
+		g.Expect(r.config.Build.ExecutorArgs).To(gomega.HaveLen(2))
+	})
+}
+
+func TestFunctionReconciler_buildPullImageAddress(t *testing.T) {
+	r := FunctionReconciler{
+		config: FunctionConfig{
+			Build: BuildConfig{Cache: BuildCacheConfig{Repository: "function-cache"}},
+		},
+	}
+	digest := "@sha256:4bc453b53cb3d914b45f4b250294236adba2c0e09ff6f03793949e7e39fd4cc1"
+
+	for testName, testData := range map[string]struct {
+		image         string
+		buildCacheKey string
+
+		expected string
+	}{
+		"should return the tagged image from the build cache": {
+			buildCacheKey: "abc",
+
+			expected: "registry.kyma.local/function-cache:abc",
+		},
+		"should return the image found in the build cache": {
+			image:         "registry.kyma.local/function-cache:abc" + digest,
+			buildCacheKey: "abc",
+
+			expected: "registry.kyma.local/function-cache:abc" + digest,
+		},
+		"should ignore the image found for other content": {
+			image:         "registry.kyma.local/function-cache:old" + digest,
+			buildCacheKey: "abc",
+
+			expected: "registry.kyma.local/function-cache:abc",
+		},
+		"should ignore the image found in the build cache when it's disabled": {
+			image: "registry.kyma.local/function-cache:abc" + digest,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			instance := &serverlessv1alpha1.Function{
+				ObjectMeta: metav1.ObjectMeta{Name: "function", Namespace: "test"},
+				Status:     serverlessv1alpha1.FunctionStatus{Image: testData.image},
+			}
+			expected := testData.expected
+			if expected == "" {
+				expected = r.buildImageAddress(instance, "registry.kyma.local", "")
+			}
+
+			// when
+			image := r.buildPullImageAddress(instance, DockerConfig{PullAddress: "registry.kyma.local", BuildCacheKey: testData.buildCacheKey})
+
+			// then
+			g.Expect(image).To(gomega.Equal(expected))
+		})
+	}
+}
+
 type expectedVolume struct {
 	name                 string
 	localObjectReference string
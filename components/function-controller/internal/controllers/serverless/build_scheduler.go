@@ -0,0 +1,276 @@
+package serverless
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// buildReservationTimeout is the time after which a queued or granted but not started build is dropped, the
+	// Functions waiting for a build are requeued much more often so only the builds which are no longer needed expire
+	buildReservationTimeout = time.Minute
+	// buildResyncPeriod is the period of comparing the started builds with the active build Jobs, so the slots of the
+	// builds whose Jobs or Functions were deleted are freed. A build started more recently is kept, since its Job may
+	// not be listed yet.
+	buildResyncPeriod = time.Minute
+)
+
+// BuildScheduler limits the number of build Jobs running in the cluster and in a single Namespace. The Functions
+// waiting for a build are queued per Namespace and the Namespaces are served in a round-robin fashion, so a Namespace
+// with many Functions can't starve the others. It's safe for concurrent use by many reconciles.
+type BuildScheduler struct {
+	maxJobs             int
+	maxJobsPerNamespace int
+	listActiveBuilds    func(ctx context.Context) ([]types.NamespacedName, error)
+	notify              func(key types.NamespacedName)
+	now                 func() time.Time
+
+	mutex    sync.Mutex
+	syncedAt time.Time
+	builds   map[types.NamespacedName]*build
+	running  map[string]int
+	queues   map[string][]types.NamespacedName
+	// namespaces is the round-robin order of the Namespaces with queued builds, next is the index of the next one
+	namespaces []string
+	next       int
+}
+
+type build struct {
+	granted   bool
+	started   bool
+	lastSeen  time.Time
+	startedAt time.Time
+}
+
+// NewBuildScheduler returns a scheduler which allows maxJobs builds in the cluster and maxJobsPerNamespace builds in a
+// Namespace, the Namespace limit is disabled if it's not positive. The started builds are synced with the active build
+// Jobs listed by listActiveBuilds, and notify is called when a queued build is granted.
+func NewBuildScheduler(maxJobs, maxJobsPerNamespace int, listActiveBuilds func(ctx context.Context) ([]types.NamespacedName, error), notify func(key types.NamespacedName)) *BuildScheduler {
+	return &BuildScheduler{
+		maxJobs:             maxJobs,
+		maxJobsPerNamespace: maxJobsPerNamespace,
+		listActiveBuilds:    listActiveBuilds,
+		notify:              notify,
+		now:                 time.Now,
+		builds:              map[types.NamespacedName]*build{},
+		running:             map[string]int{},
+		queues:              map[string][]types.NamespacedName{},
+	}
+}
+
+// Acquire requests a build slot for the Function. It returns true if the Function can create its build Job, otherwise
+// the Function is queued and its 1-based position in the queue is returned.
+func (s *BuildScheduler) Acquire(ctx context.Context, key types.NamespacedName) (bool, int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.sync(ctx); err != nil {
+		return false, 0, err
+	}
+
+	now := s.now()
+	b, ok := s.builds[key]
+	if !ok {
+		b = &build{}
+		s.builds[key] = b
+		s.enqueue(key)
+	}
+	b.lastSeen = now
+
+	for _, grantedKey := range s.dispatch() {
+		if grantedKey != key {
+			s.notify(grantedKey)
+		}
+	}
+	if b.granted {
+		return true, 0, nil
+	}
+	return false, s.position(key), nil
+}
+
+// Started marks the build of the Function as running, it holds the build slot until it's released or its Job isn't
+// active anymore.
+func (s *BuildScheduler) Started(key types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if b, ok := s.builds[key]; ok && b.granted {
+		b.started = true
+		b.startedAt = s.now()
+	}
+}
+
+// Release frees the build slot or removes the Function from the queue, e.g. when the build Job finished or the
+// Function was deleted.
+func (s *BuildScheduler) Release(key types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.remove(key) {
+		return
+	}
+	for _, grantedKey := range s.dispatch() {
+		s.notify(grantedKey)
+	}
+}
+
+// sync takes over the builds of the active Jobs which the scheduler doesn't know, e.g. after the controller restart,
+// and frees the slots of the started builds whose Jobs aren't active anymore.
+func (s *BuildScheduler) sync(ctx context.Context) error {
+	now := s.now()
+	if !s.syncedAt.IsZero() && now.Sub(s.syncedAt) < buildResyncPeriod {
+		return nil
+	}
+
+	active, err := s.listActiveBuilds(ctx)
+	if err != nil {
+		return err
+	}
+	activeKeys := make(map[types.NamespacedName]bool, len(active))
+	for _, key := range active {
+		activeKeys[key] = true
+		if _, ok := s.builds[key]; ok {
+			continue
+		}
+		s.builds[key] = &build{granted: true, started: true, lastSeen: now, startedAt: now}
+		s.running[key.Namespace]++
+	}
+	for key, b := range s.builds {
+		if b.started && !activeKeys[key] && now.Sub(b.startedAt) >= buildResyncPeriod {
+			s.remove(key)
+		}
+	}
+	s.syncedAt = now
+	return nil
+}
+
+func (s *BuildScheduler) enqueue(key types.NamespacedName) {
+	if len(s.queues[key.Namespace]) == 0 {
+		s.namespaces = append(s.namespaces, key.Namespace)
+	}
+	s.queues[key.Namespace] = append(s.queues[key.Namespace], key)
+}
+
+// remove removes the build of the Function and returns true if it held a slot or was queued.
+func (s *BuildScheduler) remove(key types.NamespacedName) bool {
+	b, ok := s.builds[key]
+	if !ok {
+		return false
+	}
+	delete(s.builds, key)
+
+	if b.granted {
+		s.running[key.Namespace]--
+		if s.running[key.Namespace] <= 0 {
+			delete(s.running, key.Namespace)
+		}
+		return true
+	}
+
+	queue := s.queues[key.Namespace]
+	for i := range queue {
+		if queue[i] == key {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	s.queues[key.Namespace] = queue
+	if len(queue) == 0 {
+		s.removeNamespace(key.Namespace)
+	}
+	return true
+}
+
+func (s *BuildScheduler) removeNamespace(namespace string) {
+	delete(s.queues, namespace)
+	for i := range s.namespaces {
+		if s.namespaces[i] != namespace {
+			continue
+		}
+		s.namespaces = append(s.namespaces[:i], s.namespaces[i+1:]...)
+		if i < s.next {
+			s.next--
+		}
+		break
+	}
+	if s.next >= len(s.namespaces) {
+		s.next = 0
+	}
+}
+
+// dispatch drops the expired builds and grants the free slots to the queued builds, it returns the granted builds.
+func (s *BuildScheduler) dispatch() []types.NamespacedName {
+	expiry := s.now().Add(-buildReservationTimeout)
+	for key, b := range s.builds {
+		if !b.started && b.lastSeen.Before(expiry) {
+			s.remove(key)
+		}
+	}
+
+	var granted []types.NamespacedName
+	for s.total() < s.maxJobs && len(s.namespaces) > 0 {
+		namespace, ok := s.nextNamespace()
+		if !ok {
+			break
+		}
+
+		key := s.queues[namespace][0]
+		s.queues[namespace] = s.queues[namespace][1:]
+		s.builds[key].granted = true
+		s.running[namespace]++
+		granted = append(granted, key)
+
+		if len(s.queues[namespace]) == 0 {
+			s.removeNamespace(namespace)
+		} else {
+			s.next = (s.next + 1) % len(s.namespaces)
+		}
+	}
+	return granted
+}
+
+// nextNamespace moves to the next Namespace in the round-robin order which didn't reach its limit.
+func (s *BuildScheduler) nextNamespace() (string, bool) {
+	for i := 0; i < len(s.namespaces); i++ {
+		namespace := s.namespaces[s.next]
+		if s.maxJobsPerNamespace <= 0 || s.running[namespace] < s.maxJobsPerNamespace {
+			return namespace, true
+		}
+		s.next = (s.next + 1) % len(s.namespaces)
+	}
+	return "", false
+}
+
+func (s *BuildScheduler) total() int {
+	total := 0
+	for _, running := range s.running {
+		total += running
+	}
+	return total
+}
+
+// position returns the position of the queued build in the round-robin order, assuming that the Namespace limits
+// don't change the order.
+func (s *BuildScheduler) position(key types.NamespacedName) int {
+	position := 0
+	for round := 0; ; round++ {
+		served := false
+		for i := 0; i < len(s.namespaces); i++ {
+			queue := s.queues[s.namespaces[(s.next+i)%len(s.namespaces)]]
+			if round >= len(queue) {
+				continue
+			}
+			served = true
+			position++
+			if queue[round] == key {
+				return position
+			}
+		}
+		if !served {
+			return position + 1
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package serverless
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBuildScheduler_ClusterLimit(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	scheduler, notified := newTestBuildScheduler(2, 0, nil)
+
+	// when
+	acquire(g, scheduler, "ns", "fn-1", true, 0)
+	acquire(g, scheduler, "ns", "fn-2", true, 0)
+	acquire(g, scheduler, "ns", "fn-3", false, 1)
+	acquire(g, scheduler, "ns", "fn-4", false, 2)
+
+	// then
+	g.Expect(*notified).To(gomega.BeEmpty())
+
+	scheduler.Release(buildKey("ns", "fn-1"))
+	g.Expect(*notified).To(gomega.Equal([]types.NamespacedName{buildKey("ns", "fn-3")}))
+	acquire(g, scheduler, "ns", "fn-3", true, 0)
+	acquire(g, scheduler, "ns", "fn-4", false, 1)
+
+	// the released build doesn't hold the slot when it's requested again
+	acquire(g, scheduler, "ns", "fn-1", false, 2)
+}
+
+func TestBuildScheduler_NamespaceFairness(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	scheduler, notified := newTestBuildScheduler(1, 0, nil)
+
+	acquire(g, scheduler, "busy", "fn-0", true, 0)
+	acquire(g, scheduler, "busy", "fn-1", false, 1)
+	acquire(g, scheduler, "busy", "fn-2", false, 2)
+	acquire(g, scheduler, "busy", "fn-3", false, 3)
+
+	// when
+	acquire(g, scheduler, "quiet", "fn-1", false, 2)
+
+	// then
+	acquire(g, scheduler, "busy", "fn-2", false, 3)
+
+	scheduler.Release(buildKey("busy", "fn-0"))
+	scheduler.Release(buildKey("busy", "fn-1"))
+	scheduler.Release(buildKey("quiet", "fn-1"))
+	g.Expect(*notified).To(gomega.Equal([]types.NamespacedName{
+		buildKey("busy", "fn-1"),
+		buildKey("quiet", "fn-1"),
+		buildKey("busy", "fn-2"),
+	}))
+}
+
+func TestBuildScheduler_NamespaceLimit(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	scheduler, notified := newTestBuildScheduler(5, 1, nil)
+
+	// when
+	acquire(g, scheduler, "ns-1", "fn-1", true, 0)
+	acquire(g, scheduler, "ns-1", "fn-2", false, 1)
+	acquire(g, scheduler, "ns-2", "fn-1", true, 0)
+
+	// then
+	scheduler.Release(buildKey("ns-2", "fn-1"))
+	g.Expect(*notified).To(gomega.BeEmpty())
+
+	scheduler.Release(buildKey("ns-1", "fn-1"))
+	g.Expect(*notified).To(gomega.Equal([]types.NamespacedName{buildKey("ns-1", "fn-2")}))
+}
+
+func TestBuildScheduler_ActiveBuilds(t *testing.T) {
+	t.Run("should take over active builds", func(t *testing.T) {
+		// given
+		g := gomega.NewWithT(t)
+		scheduler, _ := newTestBuildScheduler(2, 0, func(ctx context.Context) ([]types.NamespacedName, error) {
+			return []types.NamespacedName{buildKey("ns", "fn-1"), buildKey("ns", "fn-2")}, nil
+		})
+
+		// when
+		acquire(g, scheduler, "ns", "fn-1", true, 0)
+		acquire(g, scheduler, "ns", "fn-3", false, 1)
+		scheduler.Release(buildKey("ns", "fn-2"))
+
+		// then
+		acquire(g, scheduler, "ns", "fn-3", true, 0)
+	})
+
+	t.Run("should return error when active builds can't be listed", func(t *testing.T) {
+		// given
+		g := gomega.NewWithT(t)
+		calls := 0
+		scheduler, _ := newTestBuildScheduler(2, 0, func(ctx context.Context) ([]types.NamespacedName, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("test error")
+			}
+			return nil, nil
+		})
+
+		// when
+		_, _, err := scheduler.Acquire(context.TODO(), buildKey("ns", "fn-1"))
+
+		// then
+		g.Expect(err).To(gomega.HaveOccurred())
+		acquire(g, scheduler, "ns", "fn-1", true, 0)
+		g.Expect(calls).To(gomega.Equal(2))
+	})
+
+	t.Run("should free slots of started builds without active Jobs", func(t *testing.T) {
+		// given
+		g := gomega.NewWithT(t)
+		var active []types.NamespacedName
+		scheduler, _ := newTestBuildScheduler(1, 0, func(ctx context.Context) ([]types.NamespacedName, error) {
+			return active, nil
+		})
+		now := time.Now()
+		scheduler.now = func() time.Time { return now }
+
+		acquire(g, scheduler, "ns", "fn-1", true, 0)
+		scheduler.Started(buildKey("ns", "fn-1"))
+
+		// when
+		now = now.Add(buildResyncPeriod / 2)
+		acquire(g, scheduler, "ns", "fn-2", false, 1)
+		now = now.Add(buildResyncPeriod)
+		active = []types.NamespacedName{buildKey("ns", "fn-1")}
+		acquire(g, scheduler, "ns", "fn-2", false, 1)
+		now = now.Add(buildResyncPeriod)
+		active = nil
+
+		// then
+		acquire(g, scheduler, "ns", "fn-2", true, 0)
+	})
+}
+
+func TestBuildScheduler_Expiry(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	var active []types.NamespacedName
+	scheduler, notified := newTestBuildScheduler(1, 0, func(ctx context.Context) ([]types.NamespacedName, error) {
+		return active, nil
+	})
+	now := time.Now()
+	scheduler.now = func() time.Time { return now }
+
+	acquire(g, scheduler, "ns", "fn-1", true, 0)
+	scheduler.Started(buildKey("ns", "fn-1"))
+	active = []types.NamespacedName{buildKey("ns", "fn-1")}
+	acquire(g, scheduler, "ns", "fn-2", false, 1)
+	acquire(g, scheduler, "ns", "fn-3", false, 2)
+
+	// when
+	now = now.Add(buildReservationTimeout / 2)
+	acquire(g, scheduler, "ns", "fn-3", false, 2)
+	now = now.Add(buildReservationTimeout/2 + time.Second)
+
+	// then
+	acquire(g, scheduler, "ns", "fn-3", false, 1)
+
+	// the granted build which was not started expires as well
+	scheduler.Release(buildKey("ns", "fn-1"))
+	active = nil
+	g.Expect(*notified).To(gomega.Equal([]types.NamespacedName{buildKey("ns", "fn-3")}))
+	now = now.Add(buildReservationTimeout + time.Second)
+	acquire(g, scheduler, "ns", "fn-4", true, 0)
+
+	// the started build with an active Job doesn't expire
+	scheduler.Started(buildKey("ns", "fn-4"))
+	active = []types.NamespacedName{buildKey("ns", "fn-4")}
+	now = now.Add(buildReservationTimeout + time.Second)
+	acquire(g, scheduler, "ns", "fn-5", false, 1)
+}
+
+func newTestBuildScheduler(maxJobs, maxJobsPerNamespace int, listActiveBuilds func(ctx context.Context) ([]types.NamespacedName, error)) (*BuildScheduler, *[]types.NamespacedName) {
+	if listActiveBuilds == nil {
+		listActiveBuilds = func(ctx context.Context) ([]types.NamespacedName, error) {
+			return nil, nil
+		}
+	}
+	notified := &[]types.NamespacedName{}
+	scheduler := NewBuildScheduler(maxJobs, maxJobsPerNamespace, listActiveBuilds, func(key types.NamespacedName) {
+		*notified = append(*notified, key)
+	})
+	return scheduler, notified
+}
+
+func acquire(g *gomega.WithT, scheduler *BuildScheduler, namespace, name string, expectedAcquired bool, expectedPosition int) {
+	acquired, position, err := scheduler.Acquire(context.TODO(), buildKey(namespace, name))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(acquired).To(gomega.Equal(expectedAcquired), "acquired %s/%s", namespace, name)
+	g.Expect(position).To(gomega.Equal(expectedPosition), "position of %s/%s", namespace, name)
+}
+
+func buildKey(namespace, name string) types.NamespacedName {
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
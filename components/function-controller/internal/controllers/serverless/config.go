@@ -14,18 +14,60 @@ type FunctionConfig struct {
 	RequeueDuration                             time.Duration `envconfig:"default=1m"`
 	FunctionReadyRequeueDuration                time.Duration `envconfig:"default=5m"`
 	GitFetchRequeueDuration                     time.Duration `envconfig:"default=30s"`
+	MaxConcurrentReconciles                     int           `envconfig:"default=10"`
 	Build                                       BuildConfig
+	Metrics                                     MetricsConfig
+	ScaleToZero                                 ScaleToZeroConfig
+	Schedule                                    ScheduleConfig
+}
+
+// MetricsConfig configures the access to the Istio metrics in Prometheus, which provide the error rate of the new
+// revision during the rollout and the request concurrency of the Functions scaled to zero
+type MetricsConfig struct {
+	PrometheusAddress string        `envconfig:"default=http://monitoring-prometheus.kyma-system.svc.cluster.local:9090"`
+	Timeout           time.Duration `envconfig:"default=10s"`
+}
+
+// ScaleToZeroConfig configures the Functions with minReplicas set to 0, they are scaled to zero after IdleTimeout
+// without requests and the activator receives their requests until they are scaled up again
+type ScaleToZeroConfig struct {
+	IdleTimeout               time.Duration `envconfig:"default=15m"`
+	TargetConcurrency         int32         `envconfig:"default=10"`
+	MetricsWindow             time.Duration `envconfig:"default=1m"`
+	SyncPeriod                time.Duration `envconfig:"default=30s"`
+	ActivatorServiceName      string        `envconfig:"default=serverless-activator"`
+	ActivatorServiceNamespace string        `envconfig:"default=kyma-system"`
+}
+
+// ScheduleConfig configures the scheduled invocations of the Functions, an invocation is cancelled and failed if the
+// Function doesn't respond within InvocationTimeout
+type ScheduleConfig struct {
+	InvocationTimeout time.Duration `envconfig:"default=5m"`
 }
 
 type BuildConfig struct {
-	ExecutorArgs        []string `envconfig:"default=--insecure;--skip-tls-verify;--skip-unused-stages;--log-format=text;--cache=true"`
-	ExecutorImage       string   `envconfig:"default=gcr.io/kaniko-project/executor:v0.22.0"`
-	RepoFetcherImage    string   `envconfig:"default=eu.gcr.io/kyma-project/function-build-init:305bee60"`
-	MaxSimultaneousJobs int      `envconfig:"default=5"`
+	ExecutorArgs                    []string `envconfig:"default=--insecure;--skip-tls-verify;--skip-unused-stages;--log-format=text;--cache=true"`
+	ExecutorImage                   string   `envconfig:"default=gcr.io/kaniko-project/executor:v0.22.0"`
+	RepoFetcherImage                string   `envconfig:"default=eu.gcr.io/kyma-project/function-build-init:305bee60"`
+	MaxSimultaneousJobs             int      `envconfig:"default=5"`
+	MaxSimultaneousJobsPerNamespace int      `envconfig:"default=0"`
+	Cache                           BuildCacheConfig
+}
+
+// BuildCacheConfig configures the build cache, which keeps the Function images in one repository tagged with the hash
+// of the build content, so that the Functions with the same runtime, source and dependencies share the image
+type BuildCacheConfig struct {
+	Enabled       bool          `envconfig:"default=false"`
+	Repository    string        `envconfig:"default=function-cache"`
+	LookupTimeout time.Duration `envconfig:"default=10s"`
 }
 
 type DockerConfig struct {
 	ActiveRegistryConfigSecretName string
 	PushAddress                    string
 	PullAddress                    string
+	Username                       string
+	Password                       string
+	// BuildCacheKey is the hash of the build content, it's empty if the build cache is disabled
+	BuildCacheKey string
 }
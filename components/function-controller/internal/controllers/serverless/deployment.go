@@ -11,6 +11,7 @@ import (
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,15 +31,15 @@ const (
 	MinimumReplicasAvailable = "MinimumReplicasAvailable"
 )
 
-func (r *FunctionReconciler) isOnDeploymentChange(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, deployments []appsv1.Deployment, dockerConfig DockerConfig) bool {
-	expectedDeployment := r.buildDeployment(instance, rtmConfig, dockerConfig)
+func (r *FunctionReconciler) isOnDeploymentChange(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, deployments []appsv1.Deployment, dockerConfig DockerConfig, mountsChecksum string) bool {
+	expectedDeployment := r.buildDeployment(instance, rtmConfig, dockerConfig, mountsChecksum)
 	resourceOk := len(deployments) == 1 && r.equalDeployments(deployments[0], expectedDeployment, isScalingEnabled(instance))
 
 	return !resourceOk
 }
 
-func (r *FunctionReconciler) onDeploymentChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, deployments []appsv1.Deployment, dockerConfig DockerConfig) (ctrl.Result, error) {
-	newDeployment := r.buildDeployment(instance, rtmConfig, dockerConfig)
+func (r *FunctionReconciler) onDeploymentChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, deployments []appsv1.Deployment, dockerConfig DockerConfig, mountsChecksum string) (ctrl.Result, error) {
+	newDeployment := r.buildDeployment(instance, rtmConfig, dockerConfig, mountsChecksum)
 
 	switch {
 	case len(deployments) == 0:
@@ -73,6 +74,10 @@ func (r *FunctionReconciler) updateDeployment(ctx context.Context, log logr.Logg
 	deploy := oldDeployment.DeepCopy()
 	deploy.Spec = newDeployment.Spec
 	deploy.ObjectMeta.Labels = newDeployment.GetLabels()
+	if isScaleToZeroEnabled(instance) {
+		// the replicas are scaled according to the request concurrency
+		deploy.Spec.Replicas = oldDeployment.Spec.Replicas
+	}
 
 	log.Info(fmt.Sprintf("Updating Deployment %s", deploy.GetName()))
 	if err := r.client.Update(ctx, deploy); err != nil {
@@ -98,6 +103,9 @@ func (r *FunctionReconciler) equalDeployments(existing appsv1.Deployment, expect
 		r.mapsEqual(existing.GetLabels(), expected.GetLabels()) &&
 		r.mapsEqual(existing.Spec.Template.GetLabels(), expected.Spec.Template.GetLabels()) &&
 		equalResources(existing.Spec.Template.Spec.Containers[0].Resources, expected.Spec.Template.Spec.Containers[0].Resources) &&
+		existing.Spec.Template.GetAnnotations()[serverlessv1alpha1.FunctionMountsChecksumAnnotation] == expected.Spec.Template.GetAnnotations()[serverlessv1alpha1.FunctionMountsChecksumAnnotation] &&
+		apiequality.Semantic.DeepEqual(existing.Spec.Template.Spec.Volumes, expected.Spec.Template.Spec.Volumes) &&
+		apiequality.Semantic.DeepEqual(existing.Spec.Template.Spec.Containers[0].VolumeMounts, expected.Spec.Template.Spec.Containers[0].VolumeMounts) &&
 		(scalingEnabled || equalInt32Pointer(existing.Spec.Replicas, expected.Spec.Replicas))
 }
 
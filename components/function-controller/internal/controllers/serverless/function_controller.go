@@ -4,12 +4,14 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
 	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 )
 
@@ -82,8 +84,48 @@ func (r *FunctionReconciler) calculateGitImageTag(instance *serverlessv1alpha1.F
 	return fmt.Sprintf("%x", hash)
 }
 
+// calculateBuildCacheKey returns the hash of everything the Function image is built from, so the Functions which would
+// produce the same image share it regardless of their name and Namespace. The commit identifies the content of the git
+// repository, and the package registry configuration is included as it decides where the dependencies come from.
+func (r *FunctionReconciler) calculateBuildCacheKey(instance *serverlessv1alpha1.Function, rtm runtime.Runtime, dockerfile string, packageRegistryConfig map[string][]byte) string {
+	hash := sha256.New()
+	write := func(value string) {
+		// the length prefix keeps the boundaries of the values, so moving content between them changes the hash
+		fmt.Fprintf(hash, "%d:%s;", len(value), value)
+	}
+
+	write(string(instance.Spec.Runtime))
+	write(dockerfile)
+
+	keys := make([]string, 0, len(packageRegistryConfig))
+	for key := range packageRegistryConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		write(key)
+		write(string(packageRegistryConfig[key]))
+	}
+
+	if instance.Spec.Type == serverlessv1alpha1.SourceTypeGit {
+		write(instance.Status.Commit)
+		write(instance.Status.Repository.BaseDir)
+	} else {
+		write(rtm.SanitizeDependencies(instance.Spec.Deps))
+		write(instance.Spec.Source)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
 func (r *FunctionReconciler) updateStatus(ctx context.Context, result ctrl.Result, instance *serverlessv1alpha1.Function, condition serverlessv1alpha1.Condition, repository *serverlessv1alpha1.Repository, commit string) (ctrl.Result, error) {
 	condition.LastTransitionTime = metav1.Now()
+	// the transition time is kept while only the message changes, e.g. the position of the Function in the build queue
+	for _, existing := range instance.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status && existing.Reason == condition.Reason {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
 
 	service := instance.DeepCopy()
 	service.Status.Conditions = r.updateCondition(service.Status.Conditions, condition)
@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
 	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 )
 
@@ -406,3 +407,93 @@ func TestFunctionReconciler_getConditionReason(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionReconciler_calculateBuildCacheKey(t *testing.T) {
+	r := &FunctionReconciler{}
+	rtm := runtime.GetRuntime(serverlessv1alpha1.Nodejs12)
+	dockerfile := "FROM node:12"
+	base := &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "function", Namespace: "test", UID: "uid-1"},
+		Spec: serverlessv1alpha1.FunctionSpec{
+			Source:  "module.exports = {main: function(event, context) {return 'Hello World.'}}",
+			Deps:    "   ",
+			Runtime: serverlessv1alpha1.Nodejs12,
+		},
+	}
+	baseKey := r.calculateBuildCacheKey(base, rtm, dockerfile, nil)
+
+	t.Run("should share the key between Functions with the same content", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		other := base.DeepCopy()
+		other.Name = "other"
+		other.Namespace = "other"
+		other.UID = "uid-2"
+		other.Spec.Deps = ""
+
+		g.Expect(r.calculateBuildCacheKey(other, rtm, dockerfile, map[string][]byte{})).To(gomega.Equal(baseKey))
+	})
+
+	for testName, testData := range map[string]struct {
+		modify                func(function *serverlessv1alpha1.Function)
+		dockerfile            string
+		packageRegistryConfig map[string][]byte
+	}{
+		"should change the key on source change": {
+			modify: func(function *serverlessv1alpha1.Function) {
+				function.Spec.Source = "module.exports = {}"
+			},
+		},
+		"should change the key on dependencies change": {
+			modify: func(function *serverlessv1alpha1.Function) {
+				function.Spec.Deps = `{"name": "function", "dependencies": {"lodash": "^4.17.20"}}`
+			},
+		},
+		"should change the key on runtime change": {
+			modify: func(function *serverlessv1alpha1.Function) {
+				function.Spec.Runtime = serverlessv1alpha1.Nodejs14
+			},
+		},
+		"should change the key on Dockerfile change": {
+			dockerfile: "FROM node:14",
+		},
+		"should change the key on package registry change": {
+			packageRegistryConfig: map[string][]byte{".npmrc": []byte("registry=https://registry.kyma.local")},
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			function := base.DeepCopy()
+			if testData.modify != nil {
+				testData.modify(function)
+			}
+			functionDockerfile := dockerfile
+			if testData.dockerfile != "" {
+				functionDockerfile = testData.dockerfile
+			}
+
+			// when
+			key := r.calculateBuildCacheKey(function, rtm, functionDockerfile, testData.packageRegistryConfig)
+
+			// then
+			g.Expect(key).NotTo(gomega.Equal(baseKey))
+		})
+	}
+
+	t.Run("should use the commit of git Functions", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		function := base.DeepCopy()
+		function.Spec.Type = serverlessv1alpha1.SourceTypeGit
+		function.Spec.Source = "repository"
+		function.Status.Commit = "commit-1"
+		function.Status.Repository.BaseDir = "/function"
+		key := r.calculateBuildCacheKey(function, rtm, dockerfile, nil)
+
+		other := function.DeepCopy()
+		other.Spec.Source = "fork"
+		g.Expect(r.calculateBuildCacheKey(other, rtm, dockerfile, nil)).To(gomega.Equal(key))
+
+		other.Status.Commit = "commit-2"
+		g.Expect(r.calculateBuildCacheKey(other, rtm, dockerfile, nil)).NotTo(gomega.Equal(key))
+	})
+}
@@ -0,0 +1,187 @@
+package serverless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+// FunctionInvoker sends the scheduled CloudEvents to the Functions. The invocations run in the background, they're
+// tracked per Function so the concurrency policy can be applied and the result of the last one is kept until the
+// Function's status is updated. It's safe for concurrent use by many reconciles.
+type FunctionInvoker struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	notify     func(key types.NamespacedName)
+	now        func() time.Time
+
+	mutex     sync.Mutex
+	functions map[types.NamespacedName]*functionInvocations
+}
+
+type functionInvocations struct {
+	nextID int
+	active map[int]context.CancelFunc
+	result *InvocationResult
+}
+
+// Invocation is a single scheduled call of the Function
+type Invocation struct {
+	URL          string
+	EventID      string
+	EventSource  string
+	Payload      string
+	ScheduleHash string
+	ScheduleTime time.Time
+}
+
+// InvocationResult is the outcome of the last completed Invocation of the Function
+type InvocationResult struct {
+	ScheduleHash   string
+	ScheduleTime   time.Time
+	CompletionTime time.Time
+	Err            error
+}
+
+// NewFunctionInvoker returns an invoker which cancels the invocations running longer than timeout, notify is called
+// when an invocation completes.
+func NewFunctionInvoker(timeout time.Duration, notify func(key types.NamespacedName)) *FunctionInvoker {
+	return &FunctionInvoker{
+		httpClient: &http.Client{},
+		timeout:    timeout,
+		notify:     notify,
+		now:        time.Now,
+		functions:  map[types.NamespacedName]*functionInvocations{},
+	}
+}
+
+// Invoke starts the invocation of the Function in the background, the running invocations of the Function are
+// cancelled first if replace is true.
+func (i *FunctionInvoker) Invoke(key types.NamespacedName, invocation Invocation, replace bool) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	invocations, ok := i.functions[key]
+	if !ok {
+		invocations = &functionInvocations{active: map[int]context.CancelFunc{}}
+		i.functions[key] = invocations
+	}
+	if replace {
+		for id, cancel := range invocations.active {
+			cancel()
+			delete(invocations.active, id)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), i.timeout)
+	id := invocations.nextID
+	invocations.nextID++
+	invocations.active[id] = cancel
+
+	go func() {
+		err := i.send(ctx, invocation)
+		cancel()
+		i.complete(key, id, invocation, err)
+	}()
+}
+
+// Active returns the number of the running invocations of the Function
+func (i *FunctionInvoker) Active(key types.NamespacedName) int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if invocations, ok := i.functions[key]; ok {
+		return len(invocations.active)
+	}
+	return 0
+}
+
+// LastResult returns the result of the last completed invocation of the Function, it's nil if none completed yet
+func (i *FunctionInvoker) LastResult(key types.NamespacedName) *InvocationResult {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if invocations, ok := i.functions[key]; ok {
+		return invocations.result
+	}
+	return nil
+}
+
+// Release cancels the running invocations of the Function and forgets its results, it's called when the Function is
+// deleted.
+func (i *FunctionInvoker) Release(key types.NamespacedName) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if invocations, ok := i.functions[key]; ok {
+		for _, cancel := range invocations.active {
+			cancel()
+		}
+		delete(i.functions, key)
+	}
+}
+
+// complete records the result of the invocation unless it was replaced or released in the meantime
+func (i *FunctionInvoker) complete(key types.NamespacedName, id int, invocation Invocation, err error) {
+	i.mutex.Lock()
+	invocations, ok := i.functions[key]
+	if ok {
+		_, ok = invocations.active[id]
+	}
+	if ok {
+		delete(invocations.active, id)
+		if invocations.result == nil || !invocation.ScheduleTime.Before(invocations.result.ScheduleTime) {
+			invocations.result = &InvocationResult{
+				ScheduleHash:   invocation.ScheduleHash,
+				ScheduleTime:   invocation.ScheduleTime,
+				CompletionTime: i.now().Truncate(time.Second),
+				Err:            err,
+			}
+		}
+	}
+	i.mutex.Unlock()
+
+	if ok {
+		i.notify(key)
+	}
+}
+
+// send posts the CloudEvent in the binary content mode, the invocation succeeds if the Function responds with 2xx
+func (i *FunctionInvoker) send(ctx context.Context, invocation Invocation) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, invocation.URL, bytes.NewBufferString(invocation.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-type", serverlessv1alpha1.ScheduledEventType)
+	req.Header.Set("ce-source", invocation.EventSource)
+	req.Header.Set("ce-id", invocation.EventID)
+	req.Header.Set("ce-time", invocation.ScheduleTime.UTC().Format(time.RFC3339))
+	if json.Valid([]byte(invocation.Payload)) {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+
+	resp, err := i.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Function responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package serverless
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+func TestFunctionInvoker_Invoke(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		requests <- req
+		bodies <- string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	key := types.NamespacedName{Namespace: "test", Name: "fn"}
+	notified := make(chan types.NamespacedName, 1)
+	invoker := NewFunctionInvoker(time.Minute, func(key types.NamespacedName) { notified <- key })
+	scheduleTime := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	// when
+	invoker.Invoke(key, Invocation{
+		URL:          server.URL,
+		EventID:      "uid-1622541600",
+		EventSource:  "/namespaces/test/functions/fn",
+		Payload:      `{"key":"value"}`,
+		ScheduleHash: "hash",
+		ScheduleTime: scheduleTime,
+	}, false)
+
+	// then
+	g.Eventually(notified).Should(gomega.Receive(gomega.Equal(key)))
+	req := <-requests
+	g.Expect(req.Method).To(gomega.Equal(http.MethodPost))
+	g.Expect(req.Header.Get("ce-specversion")).To(gomega.Equal("1.0"))
+	g.Expect(req.Header.Get("ce-type")).To(gomega.Equal(serverlessv1alpha1.ScheduledEventType))
+	g.Expect(req.Header.Get("ce-source")).To(gomega.Equal("/namespaces/test/functions/fn"))
+	g.Expect(req.Header.Get("ce-id")).To(gomega.Equal("uid-1622541600"))
+	g.Expect(req.Header.Get("ce-time")).To(gomega.Equal("2021-06-01T10:00:00Z"))
+	g.Expect(req.Header.Get("Content-Type")).To(gomega.Equal("application/json"))
+	g.Expect(<-bodies).To(gomega.Equal(`{"key":"value"}`))
+
+	g.Expect(invoker.Active(key)).To(gomega.Equal(0))
+	result := invoker.LastResult(key)
+	g.Expect(result).NotTo(gomega.BeNil())
+	g.Expect(result.ScheduleHash).To(gomega.Equal("hash"))
+	g.Expect(result.ScheduleTime).To(gomega.Equal(scheduleTime))
+	g.Expect(result.Err).NotTo(gomega.HaveOccurred())
+}
+
+func TestFunctionInvoker_Failure(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	key := types.NamespacedName{Namespace: "test", Name: "fn"}
+	notified := make(chan types.NamespacedName, 1)
+	invoker := NewFunctionInvoker(time.Minute, func(key types.NamespacedName) { notified <- key })
+
+	// when
+	invoker.Invoke(key, Invocation{URL: server.URL, Payload: "plain text"}, false)
+
+	// then
+	g.Eventually(notified).Should(gomega.Receive())
+	g.Expect(invoker.LastResult(key).Err).To(gomega.MatchError("Function responded with 500 Internal Server Error"))
+}
+
+func TestFunctionInvoker_ReplaceAndRelease(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	key := types.NamespacedName{Namespace: "test", Name: "fn"}
+	invoker := NewFunctionInvoker(time.Minute, func(key types.NamespacedName) {})
+
+	// when
+	invoker.Invoke(key, Invocation{URL: server.URL}, false)
+	invoker.Invoke(key, Invocation{URL: server.URL}, false)
+	concurrent := invoker.Active(key)
+	invoker.Invoke(key, Invocation{URL: server.URL}, true)
+	replaced := invoker.Active(key)
+	invoker.Release(key)
+
+	// then
+	g.Expect(concurrent).To(gomega.Equal(2))
+	g.Expect(replaced).To(gomega.Equal(1))
+	g.Expect(invoker.Active(key)).To(gomega.Equal(0))
+	g.Consistently(func() *InvocationResult { return invoker.LastResult(key) }, 100*time.Millisecond).Should(gomega.BeNil())
+}
@@ -3,6 +3,8 @@ package serverless
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -10,16 +12,23 @@ import (
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/kubernetes"
 	fnRuntime "github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
+	"github.com/kyma-project/kyma/components/function-controller/internal/docker"
 	"github.com/kyma-project/kyma/components/function-controller/internal/git"
+	"github.com/kyma-project/kyma/components/function-controller/internal/metrics"
 	"github.com/kyma-project/kyma/components/function-controller/internal/resource"
 	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 )
@@ -30,22 +39,47 @@ type GitOperator interface {
 	Clone(path string, options git.Options) (string, error)
 }
 
+type ImageRegistry interface {
+	ImageDigest(ctx context.Context, options docker.RegistryOptions, repository, tag string) (string, error)
+}
+
+type MetricsReader interface {
+	ErrorRate(ctx context.Context, namespace, workload string, window time.Duration) (float64, error)
+	RequestConcurrency(ctx context.Context, namespace, workload string, window time.Duration) (float64, error)
+	RequestCount(ctx context.Context, namespace, workload string, window time.Duration) (float64, error)
+}
+
+// sourceUpdatesBufferSize is the number of Functions which can wait for reconciliation after a source update
+const sourceUpdatesBufferSize = 100
+
 type FunctionReconciler struct {
-	Log         logr.Logger
-	client      resource.Client
-	recorder    record.EventRecorder
-	config      FunctionConfig
-	scheme      *runtime.Scheme
-	gitOperator GitOperator
+	Log           logr.Logger
+	client        resource.Client
+	recorder      record.EventRecorder
+	config        FunctionConfig
+	scheme        *runtime.Scheme
+	gitOperator   GitOperator
+	imageRegistry ImageRegistry
+	metrics       MetricsReader
+	sourceUpdates chan event.GenericEvent
+
+	buildSchedulerOnce sync.Once
+	buildScheduler     *BuildScheduler
+
+	functionInvokerOnce sync.Once
+	functionInvoker     *FunctionInvoker
 }
 
 func NewFunction(client resource.Client, log logr.Logger, config FunctionConfig, recorder record.EventRecorder) *FunctionReconciler {
 	return &FunctionReconciler{
-		client:      client,
-		Log:         log.WithName("controllers").WithName("function"),
-		config:      config,
-		recorder:    recorder,
-		gitOperator: git.New(),
+		client:        client,
+		Log:           log.WithName("controllers").WithName("function"),
+		config:        config,
+		recorder:      recorder,
+		gitOperator:   git.New(),
+		imageRegistry: docker.NewRegistryClient(config.Build.Cache.LookupTimeout),
+		metrics:       metrics.NewPrometheusClient(config.Metrics.PrometheusAddress, config.Metrics.Timeout),
+		sourceUpdates: make(chan event.GenericEvent, sourceUpdatesBufferSize),
 	}
 }
 
@@ -57,25 +91,61 @@ func (r *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&batchv1.Job{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.Endpoints{}).
 		Owns(&autoscalingv1.HorizontalPodAutoscaler{}).
+		Watches(&source.Channel{Source: r.sourceUpdates}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &serverlessv1alpha1.FunctionRuntime{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.functionsOfRuntime),
+		}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.functionsOfMount),
+		}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.functionsOfMount),
+		}).
 		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 1, // Build job scheduling mechanism requires this parameter to be set to 1. The mechanism is based on getting active and stateless jobs, concurrent reconciles makes it non deterministic . Value 1 removes data races while fetching list of jobs. https://github.com/kyma-project/kyma/issues/10037
+			MaxConcurrentReconciles: r.config.MaxConcurrentReconciles, // The build Jobs are limited by the BuildScheduler, so the reconciles don't race for them. https://github.com/kyma-project/kyma/issues/10037
 		}).
 		Complete(r)
 }
 
+// functionsOfRuntime returns the Functions which use the changed FunctionRuntime, so they are rebuilt with it
+func (r *FunctionReconciler) functionsOfRuntime(object handler.MapObject) []reconcile.Request {
+	var functions serverlessv1alpha1.FunctionList
+	if err := r.client.ListByLabel(context.Background(), "", nil, &functions); err != nil {
+		r.Log.Error(err, "Cannot list Functions", "runtime", object.Meta.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, function := range functions.Items {
+		if string(function.Spec.Runtime) == object.Meta.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: function.Namespace, Name: function.Name},
+			})
+		}
+	}
+	return requests
+}
+
 // Reconcile reads that state of the cluster for a Function object and makes changes based on the state read and what is in the Function.Spec
 // +kubebuilder:rbac:groups="serverless.kyma-project.io",resources=functions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="serverless.kyma-project.io",resources=functions/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="serverless.kyma-project.io",resources=gitrepositories,verbs=get
+// +kubebuilder:rbac:groups="serverless.kyma-project.io",resources=functionruntimes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups="apps",resources=deployments/status,verbs=get
 // +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;update;patch;delete;deletecollection
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="batch",resources=jobs/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;deletecollection
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="autoscaling",resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;deletecollection
+// +kubebuilder:rbac:groups="networking.istio.io",resources=virtualservices,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups="gateway.kyma-project.io",resources=apirules,verbs=list
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
@@ -85,6 +155,10 @@ func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error
 	instance := &serverlessv1alpha1.Function{}
 	err := r.client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.getBuildScheduler().Release(request.NamespacedName)
+			r.getFunctionInvoker().Release(request.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -94,6 +168,8 @@ func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error
 		"version", instance.GetGeneration())
 
 	if !instance.DeletionTimestamp.IsZero() {
+		r.getBuildScheduler().Release(request.NamespacedName)
+		r.getFunctionInvoker().Release(request.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
@@ -109,32 +185,34 @@ func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error
 		return ctrl.Result{}, err
 	}
 
-	var runtimeConfigMap corev1.ConfigMapList
-	labels := map[string]string{
-		kubernetes.ConfigLabel:  kubernetes.RuntimeLabelValue,
-		kubernetes.RuntimeLabel: string(instance.Spec.Runtime),
-	}
-	if err := r.client.ListByLabel(ctx, instance.GetNamespace(), labels, &runtimeConfigMap); err != nil {
-		log.Error(err, "Cannot list runtime configmap")
-		return ctrl.Result{}, err
-	}
-
-	if len(runtimeConfigMap.Items) != 1 {
-		return ctrl.Result{}, fmt.Errorf("Expected one config map, found %d, with labels: %+v", len(runtimeConfigMap.Items), labels)
-	}
-
 	var deployments appsv1.DeploymentList
 	if err := r.client.ListByLabel(ctx, instance.GetNamespace(), r.internalFunctionLabels(instance), &deployments); err != nil {
 		log.Error(err, "Cannot list Deployments")
 		return ctrl.Result{}, err
 	}
 
+	stableDeployments, canaryDeployments := splitCanaryDeployments(deployments.Items)
+
 	var services corev1.ServiceList
 	if err := r.client.ListByLabel(ctx, instance.GetNamespace(), r.internalFunctionLabels(instance), &services); err != nil {
 		log.Error(err, "Cannot list Services")
 		return ctrl.Result{}, err
 	}
 
+	stableServices, canaryServices := splitCanaryServices(services.Items)
+
+	routing, err := r.readTrafficRouting(ctx, instance, canaryServices)
+	if err != nil {
+		log.Error(err, "Cannot read traffic routing")
+		return ctrl.Result{}, err
+	}
+
+	scaling, err := r.readScaleToZero(ctx, log, instance, stableDeployments)
+	if err != nil {
+		log.Error(err, "Cannot read scale to zero state")
+		return ctrl.Result{}, err
+	}
+
 	var hpas autoscalingv1.HorizontalPodAutoscalerList
 	if err := r.client.ListByLabel(ctx, instance.GetNamespace(), r.internalFunctionLabels(instance), &hpas); err != nil {
 		log.Error(err, "Cannot list HorizontalPodAutoscalers")
@@ -147,6 +225,12 @@ func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error
 		return ctrl.Result{}, err
 	}
 
+	mountsChecksum, err := r.readMountsChecksum(ctx, instance)
+	if err != nil {
+		log.Error(err, "Cannot read mounted Secrets and ConfigMaps")
+		return ctrl.Result{}, err
+	}
+
 	gitOptions, err := r.readGITOptions(ctx, instance)
 	if err != nil {
 		return r.updateStatusWithoutRepository(ctx, ctrl.Result{}, instance, serverlessv1alpha1.Condition{
@@ -171,8 +255,26 @@ func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error
 		})
 	}
 
-	rtmCfg := fnRuntime.GetRuntimeConfig(instance.Spec.Runtime)
-	rtm := fnRuntime.GetRuntime(instance.Spec.Runtime)
+	rtmCfg, err := r.readRuntimeConfig(ctx, instance)
+	if err != nil {
+		log.Error(err, "Cannot read runtime configuration")
+		return ctrl.Result{}, err
+	}
+	rtm := fnRuntime.NewRuntime(rtmCfg)
+
+	if r.config.Build.Cache.Enabled {
+		packageRegistryConfig, err := r.readPackageRegistryConfig(ctx, instance)
+		if err != nil {
+			log.Error(err, "Cannot read package registry configuration")
+			return ctrl.Result{}, err
+		}
+		dockerfile, err := r.readDockerfile(ctx, instance, rtmCfg)
+		if err != nil {
+			log.Error(err, "Cannot read runtime Dockerfile")
+			return ctrl.Result{}, err
+		}
+		dockerConfig.BuildCacheKey = r.calculateBuildCacheKey(instance, rtm, dockerfile, packageRegistryConfig)
+	}
 
 	switch {
 	case instance.Spec.Type == serverlessv1alpha1.SourceTypeGit && r.isOnSourceChange(instance, revision):
@@ -182,18 +284,27 @@ func (r *FunctionReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error
 		}, revision)
 	case instance.Spec.Type != serverlessv1alpha1.SourceTypeGit && r.isOnConfigMapChange(instance, rtm, configMaps.Items, deployments.Items, dockerConfig):
 		return r.onConfigMapChange(ctx, log, instance, rtm, configMaps.Items)
-	case instance.Spec.Type == serverlessv1alpha1.SourceTypeGit && r.isOnJobChange(instance, rtmCfg, jobs.Items, deployments.Items, gitOptions, dockerConfig):
+	case instance.Spec.Type == serverlessv1alpha1.SourceTypeGit && r.isOnJobChange(instance, rtmCfg, jobs.Items, stableDeployments, gitOptions, dockerConfig):
 		return r.onGitJobChange(ctx, log, instance, rtmCfg, jobs.Items, gitOptions, dockerConfig)
-	case instance.Spec.Type != serverlessv1alpha1.SourceTypeGit && r.isOnJobChange(instance, rtmCfg, jobs.Items, deployments.Items, git.Options{}, dockerConfig):
+	case instance.Spec.Type != serverlessv1alpha1.SourceTypeGit && r.isOnJobChange(instance, rtmCfg, jobs.Items, stableDeployments, git.Options{}, dockerConfig):
 		return r.onJobChange(ctx, log, instance, rtmCfg, configMaps.Items[0].GetName(), jobs.Items, dockerConfig)
-	case r.isOnDeploymentChange(instance, rtmCfg, deployments.Items, dockerConfig):
-		return r.onDeploymentChange(ctx, log, instance, rtmCfg, deployments.Items, dockerConfig)
-	case r.isOnServiceChange(instance, services.Items):
-		return r.onServiceChange(ctx, log, instance, services.Items)
-	case r.isOnHorizontalPodAutoscalerChange(instance, hpas.Items, deployments.Items):
-		return r.onHorizontalPodAutoscalerChange(ctx, log, instance, hpas.Items, deployments.Items[0].GetName())
+	case r.isOnRolloutChange(instance, rtmCfg, stableDeployments, canaryDeployments, routing, dockerConfig, mountsChecksum):
+		return r.onRolloutChange(ctx, log, instance, rtmCfg, stableDeployments, canaryDeployments, routing, dockerConfig, mountsChecksum)
+	case r.isOnDeploymentChange(instance, rtmCfg, stableDeployments, dockerConfig, mountsChecksum):
+		return r.onDeploymentChange(ctx, log, instance, rtmCfg, stableDeployments, dockerConfig, mountsChecksum)
+	case r.isOnServiceChange(instance, stableServices):
+		return r.onServiceChange(ctx, log, instance, stableServices)
+	case r.isOnEndpointsChange(instance, scaling):
+		return r.onEndpointsChange(ctx, log, instance, scaling)
+	case r.isOnScaleChange(scaling):
+		return r.onScaleChange(ctx, log, instance, stableDeployments[0], *scaling.replicas)
+	case r.isOnHorizontalPodAutoscalerChange(instance, hpas.Items, stableDeployments):
+		return r.onHorizontalPodAutoscalerChange(ctx, log, instance, hpas.Items, stableDeployments[0].GetName())
+	case r.isOnScheduleChange(instance):
+		return r.onScheduleChange(ctx, log, instance)
 	default:
-		return r.updateDeploymentStatus(ctx, log, instance, deployments.Items, corev1.ConditionTrue)
+		result, err := r.updateDeploymentStatus(ctx, log, instance, stableDeployments, corev1.ConditionTrue)
+		return r.requeueSchedule(instance, r.requeueScaleToZero(instance, r.requeueRolloutStep(instance, result))), err
 	}
 }
 
@@ -266,6 +377,8 @@ func (r *FunctionReconciler) readDockerConfig(ctx context.Context, instance *ser
 			ActiveRegistryConfigSecretName: r.config.ImageRegistryExternalDockerConfigSecretName,
 			PushAddress:                    data["registryAddress"],
 			PullAddress:                    data["registryAddress"],
+			Username:                       data["username"],
+			Password:                       data["password"],
 		}, nil
 	}
 
@@ -277,12 +390,16 @@ func (r *FunctionReconciler) readDockerConfig(ctx context.Context, instance *ser
 				ActiveRegistryConfigSecretName: r.config.ImageRegistryDefaultDockerConfigSecretName,
 				PushAddress:                    data["registryAddress"],
 				PullAddress:                    data["serverAddress"],
+				Username:                       data["username"],
+				Password:                       data["password"],
 			}, nil
 		} else {
 			return DockerConfig{
 				ActiveRegistryConfigSecretName: r.config.ImageRegistryDefaultDockerConfigSecretName,
 				PushAddress:                    data["registryAddress"],
 				PullAddress:                    data["registryAddress"],
+				Username:                       data["username"],
+				Password:                       data["password"],
 			}, nil
 		}
 	}
@@ -290,6 +407,41 @@ func (r *FunctionReconciler) readDockerConfig(ctx context.Context, instance *ser
 	return DockerConfig{}, errors.Errorf("Docker registry configuration not found, none of configuration secrets (%s, %s) found in function namespace", r.config.ImageRegistryDefaultDockerConfigSecretName, r.config.ImageRegistryExternalDockerConfigSecretName)
 }
 
+// readRuntimeConfig returns the configuration of the FunctionRuntime with the name of the Function runtime or, if there
+// is no such FunctionRuntime, the configuration of the runtime shipped with the Function Controller
+func (r *FunctionReconciler) readRuntimeConfig(ctx context.Context, instance *serverlessv1alpha1.Function) (fnRuntime.Config, error) {
+	var functionRuntime serverlessv1alpha1.FunctionRuntime
+	err := r.client.Get(ctx, client.ObjectKey{Name: string(instance.Spec.Runtime)}, &functionRuntime)
+	switch {
+	case err == nil:
+		return fnRuntime.NewConfig(&functionRuntime), nil
+	case !apierrors.IsNotFound(err):
+		return fnRuntime.Config{}, err
+	case !fnRuntime.IsBuiltin(instance.Spec.Runtime):
+		return fnRuntime.Config{}, fmt.Errorf("cannot find runtime: %s", instance.Spec.Runtime)
+	default:
+		return fnRuntime.GetRuntimeConfig(instance.Spec.Runtime), nil
+	}
+}
+
+// readDockerfile returns the Dockerfile of the runtime from the ConfigMap which the build Job mounts.
+func (r *FunctionReconciler) readDockerfile(ctx context.Context, instance *serverlessv1alpha1.Function, rtmCfg fnRuntime.Config) (string, error) {
+	var configMap corev1.ConfigMap
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: rtmCfg.DockerfileConfigMapName}, &configMap); err != nil {
+		return "", errors.Wrapf(err, "while reading Dockerfile ConfigMap %s", rtmCfg.DockerfileConfigMapName)
+	}
+	return configMap.Data[dockerfileKey], nil
+}
+
+// readPackageRegistryConfig returns the data of the optional package registry configuration Secret of the Function.
+func (r *FunctionReconciler) readPackageRegistryConfig(ctx context.Context, instance *serverlessv1alpha1.Function) (map[string][]byte, error) {
+	var secret corev1.Secret
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: r.config.PackageRegistryConfigSecretName}, &secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return secret.Data, nil
+}
+
 func (r *FunctionReconciler) readSecretData(data map[string][]byte) map[string]string {
 	output := make(map[string]string)
 	for k, v := range data {
@@ -363,7 +363,7 @@ var _ = ginkgo.Describe("Function", func() {
 				gomega.Expect(len(deployments.Items)).To(gomega.Equal(1))
 
 				deployment := &deployments.Items[0]
-				expectedImage := reconciler.buildImageAddress(function, "registry.kyma.local")
+				expectedImage := reconciler.buildImageAddress(function, "registry.kyma.local", "")
 				gomega.Expect(deployment).To(gomega.Not(gomega.BeNil()))
 				gomega.Expect(deployment).To(haveSpecificContainer0Image(expectedImage))
 				gomega.Expect(deployment).To(haveLabelLen(7))
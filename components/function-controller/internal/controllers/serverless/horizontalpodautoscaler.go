@@ -21,7 +21,7 @@ func (r *FunctionReconciler) isOnHorizontalPodAutoscalerChange(instance *serverl
 	}
 
 	newHpa := r.buildHorizontalPodAutoscaler(instance, deployments[0].GetName())
-	scalingEnabled := isScalingEnabled(instance)
+	scalingEnabled := isHorizontalPodAutoscalerEnabled(instance)
 	numHpa := len(hpas)
 
 	return (scalingEnabled && numHpa != 1) ||
@@ -36,7 +36,7 @@ func (r *FunctionReconciler) onHorizontalPodAutoscalerChange(ctx context.Context
 	switch {
 	case hpasNum == 0:
 		{
-			if !equalInt32Pointer(instance.Spec.MinReplicas, instance.Spec.MaxReplicas) {
+			if isHorizontalPodAutoscalerEnabled(instance) {
 				return r.createHorizontalPodAutoscaler(ctx, log, instance, newHpa)
 			}
 			return ctrl.Result{}, nil
@@ -47,6 +47,9 @@ func (r *FunctionReconciler) onHorizontalPodAutoscalerChange(ctx context.Context
 		// this case is when we previously created HPA with maxReplicas > minReplicas, but now user changed
 		// function spec and NOW maxReplicas == minReplicas, so hpa is not needed anymore
 		return r.deleteAllHorizontalPodAutoscalers(ctx, instance, log)
+	case hpasNum == 1 && isScaleToZeroEnabled(instance):
+		// the Function scaled to zero is scaled by the Function Controller according to the request concurrency
+		return r.deleteAllHorizontalPodAutoscalers(ctx, instance, log)
 	case !r.equalHorizontalPodAutoscalers(hpas[0], newHpa):
 		return r.updateHorizontalPodAutoscaler(ctx, log, instance, hpas[0], newHpa)
 	default:
@@ -73,6 +76,10 @@ func isScalingEnabled(instance *serverlessv1alpha1.Function) bool {
 	return !equalInt32Pointer(instance.Spec.MinReplicas, instance.Spec.MaxReplicas)
 }
 
+func isHorizontalPodAutoscalerEnabled(instance *serverlessv1alpha1.Function) bool {
+	return isScalingEnabled(instance) && !isScaleToZeroEnabled(instance)
+}
+
 func (r *FunctionReconciler) equalHorizontalPodAutoscalers(existing, expected autoscalingv1.HorizontalPodAutoscaler) bool {
 	return equalInt32Pointer(existing.Spec.TargetCPUUtilizationPercentage, expected.Spec.TargetCPUUtilizationPercentage) &&
 		equalInt32Pointer(existing.Spec.MinReplicas, expected.Spec.MinReplicas) &&
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
+	"github.com/kyma-project/kyma/components/function-controller/internal/docker"
 	"github.com/kyma-project/kyma/components/function-controller/internal/git"
 
 	"github.com/go-logr/logr"
@@ -16,7 +17,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 )
@@ -24,9 +27,13 @@ import (
 var fcManagedByLabel = map[string]string{serverlessv1alpha1.FunctionManagedByLabel: serverlessv1alpha1.FunctionControllerValue}
 
 func (r *FunctionReconciler) isOnJobChange(instance *serverlessv1alpha1.Function, rtmCfg runtime.Config, jobs []batchv1.Job, deployments []appsv1.Deployment, gitOptions git.Options, dockerConfig DockerConfig) bool {
-	image := r.buildImageAddress(instance, dockerConfig.PullAddress)
+	image := r.buildPullImageAddress(instance, dockerConfig)
 	buildStatus := r.getConditionStatus(instance.Status.Conditions, serverlessv1alpha1.ConditionBuildReady)
 
+	if r.isBuildCacheHit(instance, dockerConfig) && buildStatus == corev1.ConditionTrue {
+		return false
+	}
+
 	var expectedJob batchv1.Job
 	if instance.Spec.Type != serverlessv1alpha1.SourceTypeGit {
 		expectedJob = r.buildJob(instance, rtmCfg, "", dockerConfig)
@@ -51,20 +58,39 @@ func (r *FunctionReconciler) isOnJobChange(instance *serverlessv1alpha1.Function
 		buildStatus == corev1.ConditionFalse
 }
 
-func (r *FunctionReconciler) changeJob(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, newJob batchv1.Job, jobs []batchv1.Job) (ctrl.Result, error) {
+func (r *FunctionReconciler) changeJob(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, newJob batchv1.Job, jobs []batchv1.Job, dockerConfig DockerConfig) (ctrl.Result, error) {
 	jobsLen := len(jobs)
 
 	switch {
 	case jobsLen == 0:
-		activeJobs, err := r.getActiveAndStatelessJobs(ctx)
+		if dockerConfig.BuildCacheKey != "" {
+			digest, err := r.lookupBuildCache(ctx, dockerConfig)
+			switch {
+			case err != nil:
+				// the image is built as if it wasn't in the cache, so the registry outage doesn't stop the builds
+				log.Error(err, "Cannot look up image in the build cache")
+			case digest != "":
+				r.getBuildScheduler().Release(functionKey(instance))
+				return r.useCachedImage(ctx, log, instance, fmt.Sprintf("%s@%s", r.buildCacheImageAddress(dockerConfig.PullAddress, dockerConfig.BuildCacheKey), digest))
+			}
+		}
+
+		acquired, position, err := r.getBuildScheduler().Acquire(ctx, functionKey(instance))
 		if err != nil {
+			log.Error(err, "Cannot schedule Job")
 			return ctrl.Result{}, err
 		}
 
-		if activeJobs >= r.config.Build.MaxSimultaneousJobs {
-			return ctrl.Result{
+		if !acquired {
+			return r.updateStatusWithoutRepository(ctx, ctrl.Result{
 				RequeueAfter: time.Second * 5,
-			}, nil
+			}, instance, serverlessv1alpha1.Condition{
+				Type:               serverlessv1alpha1.ConditionBuildReady,
+				Status:             corev1.ConditionUnknown,
+				LastTransitionTime: metav1.Now(),
+				Reason:             serverlessv1alpha1.ConditionReasonJobQueued,
+				Message:            fmt.Sprintf("Job is waiting for a free build slot at position %d in the build queue", position),
+			})
 		}
 
 		return r.createJob(ctx, log, instance, newJob)
@@ -79,11 +105,11 @@ func (r *FunctionReconciler) changeJob(ctx context.Context, log logr.Logger, ins
 
 func (r *FunctionReconciler) onGitJobChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, rtmCfg runtime.Config, jobs []batchv1.Job, gitOptions git.Options, dockerConfig DockerConfig) (ctrl.Result, error) {
 	newJob := r.buildGitJob(instance, gitOptions, rtmCfg, dockerConfig)
-	return r.changeJob(ctx, log, instance, newJob, jobs)
+	return r.changeJob(ctx, log, instance, newJob, jobs, dockerConfig)
 }
 func (r *FunctionReconciler) onJobChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, rtmCfg runtime.Config, configMapName string, jobs []batchv1.Job, dockerConfig DockerConfig) (ctrl.Result, error) {
 	newJob := r.buildJob(instance, rtmCfg, configMapName, dockerConfig)
-	return r.changeJob(ctx, log, instance, newJob, jobs)
+	return r.changeJob(ctx, log, instance, newJob, jobs, dockerConfig)
 }
 
 func (r *FunctionReconciler) equalJobs(existing batchv1.Job, expected batchv1.Job) bool {
@@ -113,6 +139,13 @@ func (r *FunctionReconciler) createJob(ctx context.Context, log logr.Logger, ins
 		return ctrl.Result{}, err
 	}
 	log.Info(fmt.Sprintf("Job %s created", job.GetName()))
+	r.getBuildScheduler().Started(functionKey(instance))
+
+	if instance.Status.Image != "" {
+		// the Function is built, so the image from the build cache is no longer used
+		instance = instance.DeepCopy()
+		instance.Status.Image = ""
+	}
 
 	return r.updateStatusWithoutRepository(ctx, ctrl.Result{}, instance, serverlessv1alpha1.Condition{
 		Type:               serverlessv1alpha1.ConditionBuildReady,
@@ -145,6 +178,7 @@ func (r *FunctionReconciler) updateBuildStatus(ctx context.Context, log logr.Log
 	switch {
 	case job.Status.CompletionTime != nil:
 		log.Info(fmt.Sprintf("Job %s finished", job.GetName()))
+		r.getBuildScheduler().Release(functionKey(instance))
 		return r.updateStatusWithoutRepository(ctx, ctrl.Result{}, instance, serverlessv1alpha1.Condition{
 			Type:               serverlessv1alpha1.ConditionBuildReady,
 			Status:             corev1.ConditionTrue,
@@ -163,6 +197,7 @@ func (r *FunctionReconciler) updateBuildStatus(ctx context.Context, log logr.Log
 		})
 	default:
 		log.Info(fmt.Sprintf("Job %s failed", job.GetName()))
+		r.getBuildScheduler().Release(functionKey(instance))
 		return r.updateStatusWithoutRepository(ctx, ctrl.Result{RequeueAfter: r.config.RequeueDuration}, instance, serverlessv1alpha1.Condition{
 			Type:               serverlessv1alpha1.ConditionBuildReady,
 			Status:             corev1.ConditionFalse,
@@ -193,18 +228,84 @@ func (r *FunctionReconciler) updateJobLabels(ctx context.Context, log logr.Logge
 	})
 }
 
-func (r *FunctionReconciler) getActiveAndStatelessJobs(ctx context.Context) (int, error) {
+// isBuildCacheHit returns true if the Function uses the image found in the build cache for its current build content.
+func (r *FunctionReconciler) isBuildCacheHit(instance *serverlessv1alpha1.Function, dockerConfig DockerConfig) bool {
+	if dockerConfig.BuildCacheKey == "" {
+		return false
+	}
+	image := r.buildCacheImageAddress(dockerConfig.PullAddress, dockerConfig.BuildCacheKey)
+	return strings.HasPrefix(instance.Status.Image, image+"@")
+}
+
+// lookupBuildCache returns the digest of the image built from the same content, it's empty if there is no such image.
+// The registry is reached the same way as by the build Job, so the executor arguments decide about the TLS.
+func (r *FunctionReconciler) lookupBuildCache(ctx context.Context, dockerConfig DockerConfig) (string, error) {
+	options := docker.RegistryOptions{
+		Address:  dockerConfig.PushAddress,
+		Username: dockerConfig.Username,
+		Password: dockerConfig.Password,
+	}
+	for _, arg := range r.config.Build.ExecutorArgs {
+		switch arg {
+		case "--insecure", "--insecure=true":
+			options.Insecure = true
+		case "--skip-tls-verify", "--skip-tls-verify=true":
+			options.SkipTLSVerify = true
+		}
+	}
+	return r.imageRegistry.ImageDigest(ctx, options, r.config.Build.Cache.Repository, dockerConfig.BuildCacheKey)
+}
+
+func (r *FunctionReconciler) useCachedImage(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, image string) (ctrl.Result, error) {
+	log.Info(fmt.Sprintf("Image %s found in the build cache", image))
+
+	cached := instance.DeepCopy()
+	cached.Status.Image = image
+	return r.updateStatusWithoutRepository(ctx, ctrl.Result{}, cached, serverlessv1alpha1.Condition{
+		Type:               serverlessv1alpha1.ConditionBuildReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             serverlessv1alpha1.ConditionReasonJobSkipped,
+		Message:            fmt.Sprintf("Job skipped, image %s found in the build cache", image),
+	})
+}
+
+// getBuildScheduler returns the scheduler of the build Jobs, it's created on the first use to respect the build
+// configuration of the reconciler.
+func (r *FunctionReconciler) getBuildScheduler() *BuildScheduler {
+	r.buildSchedulerOnce.Do(func() {
+		r.buildScheduler = NewBuildScheduler(r.config.Build.MaxSimultaneousJobs, r.config.Build.MaxSimultaneousJobsPerNamespace, r.listActiveBuilds, r.triggerReconcile)
+	})
+	return r.buildScheduler
+}
+
+// listActiveBuilds returns the Functions with active or stateless build Jobs.
+func (r *FunctionReconciler) listActiveBuilds(ctx context.Context) ([]types.NamespacedName, error) {
 	var allJobs batchv1.JobList
 	if err := r.client.ListByLabel(ctx, "", fcManagedByLabel, &allJobs); err != nil {
 		r.Log.Error(err, "Cannot list Jobs")
-		return 0, err
+		return nil, err
 	}
 
-	out := 0
+	var out []types.NamespacedName
 	for _, j := range allJobs.Items {
 		if j.Status.Succeeded == 0 && j.Status.Failed == 0 {
-			out++
+			out = append(out, types.NamespacedName{Namespace: j.GetNamespace(), Name: j.GetLabels()[serverlessv1alpha1.FunctionNameLabel]})
 		}
 	}
 	return out, nil
 }
+
+// triggerReconcile requeues the Function, it doesn't block if the Function can't be requeued immediately since the
+// Functions waiting for a build are requeued periodically anyway.
+func (r *FunctionReconciler) triggerReconcile(key types.NamespacedName) {
+	function := &serverlessv1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	select {
+	case r.sourceUpdates <- event.GenericEvent{Meta: function, Object: function}:
+	default:
+	}
+}
+
+func functionKey(instance *serverlessv1alpha1.Function) types.NamespacedName {
+	return types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetName()}
+}
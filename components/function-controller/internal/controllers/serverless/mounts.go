@@ -0,0 +1,179 @@
+package serverless
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+// defaultMountMode is the mode of the mounted files set by the API server, it's set explicitly so that the Deployment
+// doesn't differ from the expected one
+var defaultMountMode = int32(0644)
+
+// readMountsChecksum returns the checksum of the Secrets and ConfigMaps mounted in the Function, it's empty if there
+// are none. The missing ones are part of the checksum as well, so the Pods are restarted once they're created.
+func (r *FunctionReconciler) readMountsChecksum(ctx context.Context, instance *serverlessv1alpha1.Function) (string, error) {
+	if len(instance.Spec.SecretMounts) == 0 && len(instance.Spec.ConfigMapMounts) == 0 {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	for _, mount := range instance.Spec.SecretMounts {
+		var secret corev1.Secret
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: instance.GetNamespace(), Name: mount.SecretName}, &secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "secret:%s:%t\n", mount.SecretName, err == nil)
+		writeBinaryData(hash, secret.Data)
+	}
+	for _, mount := range instance.Spec.ConfigMapMounts {
+		var configMap corev1.ConfigMap
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: instance.GetNamespace(), Name: mount.ConfigMapName}, &configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "configmap:%s:%t\n", mount.ConfigMapName, err == nil)
+		for _, key := range sortedKeys(configMap.Data) {
+			fmt.Fprintf(hash, "%s=%x\n", key, configMap.Data[key])
+		}
+		writeBinaryData(hash, configMap.BinaryData)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func writeBinaryData(w io.Writer, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s=%x\n", key, data[key])
+	}
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildMounts returns the volumes of the Secrets, ConfigMaps and writable directories declared in the Function
+func (r *FunctionReconciler) buildMounts(instance *serverlessv1alpha1.Function) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+
+	serviceBindingRoot := instance.Spec.ServiceBindingRoot()
+	for i, mount := range instance.Spec.SecretMounts {
+		name := fmt.Sprintf("secret-mount-%d", i)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  mount.SecretName,
+					Items:       mount.Items,
+					DefaultMode: &defaultMountMode,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: mount.ResolveMountPath(serviceBindingRoot), ReadOnly: true})
+	}
+	for i, mount := range instance.Spec.ConfigMapMounts {
+		name := fmt.Sprintf("configmap-mount-%d", i)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: mount.ConfigMapName},
+					Items:                mount.Items,
+					DefaultMode:          &defaultMountMode,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: mount.MountPath, ReadOnly: true})
+	}
+	for i, volume := range instance.Spec.Volumes {
+		name := fmt.Sprintf("volume-%d", i)
+		volumes = append(volumes, corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: volume.EmptyDir},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: volume.MountPath})
+	}
+	return volumes, mounts
+}
+
+// serviceBindingEnvs sets SERVICE_BINDING_ROOT if the Function has service bindings and doesn't set it itself
+func (r *FunctionReconciler) serviceBindingEnvs(instance *serverlessv1alpha1.Function) []corev1.EnvVar {
+	hasBindings := false
+	for _, mount := range instance.Spec.SecretMounts {
+		hasBindings = hasBindings || mount.MountPath == ""
+	}
+	for _, env := range instance.Spec.Env {
+		if env.Name == serverlessv1alpha1.ServiceBindingRootEnv {
+			return nil
+		}
+	}
+	if !hasBindings {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: serverlessv1alpha1.ServiceBindingRootEnv, Value: serverlessv1alpha1.DefaultServiceBindingRoot}}
+}
+
+// functionsOfMount returns the Functions which mount the changed Secret or ConfigMap, so their Pods are restarted
+func (r *FunctionReconciler) functionsOfMount(object handler.MapObject) []reconcile.Request {
+	_, isSecret := object.Object.(*corev1.Secret)
+	_, isConfigMap := object.Object.(*corev1.ConfigMap)
+	if !isSecret && !isConfigMap {
+		return nil
+	}
+
+	var functions serverlessv1alpha1.FunctionList
+	if err := r.client.ListByLabel(context.Background(), object.Meta.GetNamespace(), nil, &functions); err != nil {
+		r.Log.Error(err, "Cannot list Functions", "namespace", object.Meta.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, function := range functions.Items {
+		if (isSecret && mountsSecret(function, object.Meta.GetName())) || (isConfigMap && mountsConfigMap(function, object.Meta.GetName())) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: function.GetNamespace(), Name: function.GetName()},
+			})
+		}
+	}
+	return requests
+}
+
+func mountsSecret(function serverlessv1alpha1.Function, name string) bool {
+	for _, mount := range function.Spec.SecretMounts {
+		if mount.SecretName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func mountsConfigMap(function serverlessv1alpha1.Function, name string) bool {
+	for _, mount := range function.Spec.ConfigMapMounts {
+		if mount.ConfigMapName == name {
+			return true
+		}
+	}
+	return false
+}
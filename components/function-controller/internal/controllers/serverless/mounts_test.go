@@ -0,0 +1,125 @@
+package serverless
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fnRuntime "github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
+	"github.com/kyma-project/kyma/components/function-controller/internal/resource"
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+func TestFunctionReconciler_buildDeployment_mounts(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	r := &FunctionReconciler{}
+	instance := fixMountsFunction()
+
+	// when
+	deployment := r.buildDeployment(instance, fnRuntime.GetRuntimeConfig(serverlessv1alpha1.Nodejs14), DockerConfig{}, "checksum")
+
+	// then
+	template := deployment.Spec.Template
+	g.Expect(template.Annotations).To(gomega.HaveKeyWithValue(serverlessv1alpha1.FunctionMountsChecksumAnnotation, "checksum"))
+	g.Expect(template.Spec.Volumes).To(gomega.HaveLen(4))
+	g.Expect(template.Spec.Volumes[1].Secret.SecretName).To(gomega.Equal("tls"))
+	g.Expect(template.Spec.Volumes[2].ConfigMap.Name).To(gomega.Equal("config"))
+	g.Expect(template.Spec.Volumes[3].EmptyDir.Medium).To(gomega.Equal(corev1.StorageMediumMemory))
+
+	container := template.Spec.Containers[0]
+	var mountPaths []string
+	for _, mount := range container.VolumeMounts {
+		mountPaths = append(mountPaths, mount.MountPath)
+	}
+	g.Expect(mountPaths).To(gomega.Equal([]string{"/tmp", "/bindings/tls", "/etc/config", "/cache"}))
+	g.Expect(container.Env).To(gomega.ContainElement(corev1.EnvVar{Name: serverlessv1alpha1.ServiceBindingRootEnv, Value: "/bindings"}))
+
+	g.Expect(r.equalDeployments(deployment, r.buildDeployment(instance, fnRuntime.GetRuntimeConfig(serverlessv1alpha1.Nodejs14), DockerConfig{}, "checksum"), false)).To(gomega.BeTrue())
+	g.Expect(r.equalDeployments(deployment, r.buildDeployment(instance, fnRuntime.GetRuntimeConfig(serverlessv1alpha1.Nodejs14), DockerConfig{}, "changed"), false)).To(gomega.BeFalse())
+}
+
+func TestFunctionReconciler_readMountsChecksum(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "test"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert")},
+	}
+	k8sClient := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, secret)
+	r := &FunctionReconciler{client: resource.New(k8sClient, clientgoscheme.Scheme)}
+	instance := fixMountsFunction()
+
+	// when
+	withoutConfigMap, err := r.readMountsChecksum(context.TODO(), instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(k8sClient.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "test"},
+		Data:       map[string]string{"config.yaml": "key: value"},
+	})).To(gomega.Succeed())
+	withConfigMap, err := r.readMountsChecksum(context.TODO(), instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	secret.Data["tls.crt"] = []byte("rotated")
+	g.Expect(k8sClient.Update(context.TODO(), secret)).To(gomega.Succeed())
+	withRotatedSecret, err := r.readMountsChecksum(context.TODO(), instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	unchanged, err := r.readMountsChecksum(context.TODO(), instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// then
+	g.Expect(withoutConfigMap).NotTo(gomega.BeEmpty())
+	g.Expect(withConfigMap).NotTo(gomega.Equal(withoutConfigMap))
+	g.Expect(withRotatedSecret).NotTo(gomega.Equal(withConfigMap))
+	g.Expect(unchanged).To(gomega.Equal(withRotatedSecret))
+}
+
+func TestFunctionReconciler_functionsOfMount(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(serverlessv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	other := &serverlessv1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}
+	r := &FunctionReconciler{
+		Log:    zap.New(),
+		client: resource.New(fake.NewFakeClientWithScheme(scheme, fixMountsFunction(), other), scheme),
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "test"}}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "test"}}
+
+	// when
+	secretRequests := r.functionsOfMount(handler.MapObject{Meta: secret, Object: secret})
+	configMapRequests := r.functionsOfMount(handler.MapObject{Meta: configMap, Object: configMap})
+
+	// then
+	g.Expect(secretRequests).To(gomega.Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "test", Name: "fn"}}}))
+	g.Expect(configMapRequests).To(gomega.BeEmpty())
+}
+
+func fixMountsFunction() *serverlessv1alpha1.Function {
+	return &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test"},
+		Spec: serverlessv1alpha1.FunctionSpec{
+			SecretMounts:    []serverlessv1alpha1.SecretMount{{SecretName: "tls"}},
+			ConfigMapMounts: []serverlessv1alpha1.ConfigMapMount{{ConfigMapName: "config", MountPath: "/etc/config"}},
+			Volumes: []serverlessv1alpha1.Volume{{
+				MountPath: "/cache",
+				EmptyDir:  &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			}},
+		},
+	}
+}
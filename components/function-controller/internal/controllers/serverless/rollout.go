@@ -0,0 +1,528 @@
+package serverless
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+// meshGateway binds the VirtualService to the sidecars, so it applies to the requests sent from inside the mesh
+const meshGateway = "mesh"
+
+var virtualServiceGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"}
+
+// trafficRouting holds the resources which split the Function traffic between its revisions, the VirtualService is nil
+// if it doesn't exist
+type trafficRouting struct {
+	virtualService *unstructured.Unstructured
+	canaryServices []corev1.Service
+	// apiRuleHosts and apiRuleGateways expose the Function outside of the cluster, the VirtualService is bound to them
+	apiRuleHosts    []string
+	apiRuleGateways []string
+}
+
+// rolloutPlan is the desired state of the Function revisions
+type rolloutPlan struct {
+	status       *serverlessv1alpha1.RolloutStatus
+	canary       *appsv1.Deployment
+	routing      bool
+	stableWeight int32
+	canaryWeight int32
+	// stepElapsed means that the current step is over, the rollout moves to the next step if the new revision doesn't
+	// exceed the error rate
+	stepElapsed bool
+}
+
+// splitCanaryDeployments separates the Deployments of the new revisions from the Deployments of the stable revision
+func splitCanaryDeployments(deployments []appsv1.Deployment) ([]appsv1.Deployment, []appsv1.Deployment) {
+	var stable, canaries []appsv1.Deployment
+	for _, deployment := range deployments {
+		if deployment.GetLabels()[serverlessv1alpha1.FunctionRolloutRoleLabel] == serverlessv1alpha1.FunctionRolloutRoleCanaryValue {
+			canaries = append(canaries, deployment)
+		} else {
+			stable = append(stable, deployment)
+		}
+	}
+	return stable, canaries
+}
+
+// splitCanaryServices separates the Services of the new revisions from the Function Service
+func splitCanaryServices(services []corev1.Service) ([]corev1.Service, []corev1.Service) {
+	var stable, canaries []corev1.Service
+	for _, service := range services {
+		if service.GetLabels()[serverlessv1alpha1.FunctionRolloutRoleLabel] == serverlessv1alpha1.FunctionRolloutRoleCanaryValue {
+			canaries = append(canaries, service)
+		} else {
+			stable = append(stable, service)
+		}
+	}
+	return stable, canaries
+}
+
+// readTrafficRouting reads the VirtualService of the Function and the APIRules exposing it, they are read only if the
+// Function has the rollout defined now or had it before, so Istio is not required for Functions without the rollout
+func (r *FunctionReconciler) readTrafficRouting(ctx context.Context, instance *serverlessv1alpha1.Function, canaryServices []corev1.Service) (trafficRouting, error) {
+	routing := trafficRouting{canaryServices: canaryServices}
+	if instance.Spec.Rollout == nil && instance.Status.Rollout == nil {
+		return routing, nil
+	}
+
+	virtualService, err := r.getUnstructured(ctx, instance, virtualServiceGVK)
+	if err != nil {
+		return trafficRouting{}, err
+	}
+	routing.virtualService = virtualService
+
+	apiRules, err := r.readAPIRules(ctx, instance)
+	if err != nil {
+		return trafficRouting{}, err
+	}
+	hosts, gateways := map[string]bool{}, map[string]bool{}
+	for _, apiRule := range apiRules {
+		host, _, _ := unstructured.NestedString(apiRule.Object, "spec", "service", "host")
+		gateway, _, _ := unstructured.NestedString(apiRule.Object, "spec", "gateway")
+		if host == "" || gateway == "" {
+			continue
+		}
+		host = strings.ToLower(host)
+		if !hosts[host] {
+			hosts[host] = true
+			routing.apiRuleHosts = append(routing.apiRuleHosts, host)
+		}
+		if !gateways[gateway] {
+			gateways[gateway] = true
+			routing.apiRuleGateways = append(routing.apiRuleGateways, gateway)
+		}
+	}
+	sort.Strings(routing.apiRuleHosts)
+	sort.Strings(routing.apiRuleGateways)
+	return routing, nil
+}
+
+func (r *FunctionReconciler) getUnstructured(ctx context.Context, instance *serverlessv1alpha1.Function, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: instance.GetNamespace(), Name: instance.GetName()}, object); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+func (r *FunctionReconciler) isOnRolloutChange(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, stable, canaries []appsv1.Deployment, routing trafficRouting, dockerConfig DockerConfig, mountsChecksum string) bool {
+	plan := r.planRollout(instance, rtmConfig, stable, canaries, dockerConfig, mountsChecksum, time.Now())
+
+	return plan.stepElapsed ||
+		!equality.Semantic.DeepEqual(instance.Status.Rollout, plan.status) ||
+		!r.equalCanaries(canaries, plan.canary) ||
+		!r.equalTrafficRouting(instance, routing, plan)
+}
+
+func (r *FunctionReconciler) onRolloutChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, stable, canaries []appsv1.Deployment, routing trafficRouting, dockerConfig DockerConfig, mountsChecksum string) (ctrl.Result, error) {
+	now := time.Now()
+	plan := r.planRollout(instance, rtmConfig, stable, canaries, dockerConfig, mountsChecksum, now)
+
+	if plan.stepElapsed {
+		exceeded, errorRate, err := r.exceedsErrorRate(ctx, instance, canaries[0])
+		if err != nil {
+			log.Error(err, "Cannot read the error rate of the new revision")
+			return ctrl.Result{RequeueAfter: r.config.RequeueDuration}, nil
+		}
+
+		if exceeded {
+			plan.status.Phase = serverlessv1alpha1.RolloutPhaseAborted
+			plan.status.Message = fmt.Sprintf("Rollout aborted, error rate of the new revision %.2f%% exceeded %d%%", errorRate, *instance.Spec.Rollout.MaxErrorRate)
+		} else {
+			plan.status.Step++
+			plan.status.StepStartTime = &metav1.Time{Time: now}
+			if int(plan.status.Step) >= len(instance.Spec.Rollout.Steps) {
+				plan.status.Step = int32(len(instance.Spec.Rollout.Steps) - 1)
+				plan.status.Phase = serverlessv1alpha1.RolloutPhasePromoting
+				plan.status.StepStartTime = nil
+			}
+		}
+		r.completeRolloutPlan(&plan, instance, rtmConfig, stable[0], canaries, dockerConfig, mountsChecksum)
+	}
+
+	// the traffic is moved away from the new revision before its Deployment is deleted
+	if plan.routing {
+		if err := r.applyTrafficRouting(ctx, log, instance, routing, plan); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.applyCanary(ctx, log, instance, canaries, plan.canary); err != nil {
+		return ctrl.Result{}, err
+	}
+	if !plan.routing {
+		if err := r.deleteTrafficRouting(ctx, log, routing); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.updateRolloutStatus(ctx, instance, plan.status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.requeueRolloutStep(instance, ctrl.Result{}), nil
+}
+
+// planRollout decides on the phase of the rollout and the traffic split between the revisions. The rollout starts when
+// the image of the Function changes, the previous image stays in the stable Deployment until the new one, run by the
+// canary Deployment, is promoted.
+func (r *FunctionReconciler) planRollout(instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, stable, canaries []appsv1.Deployment, dockerConfig DockerConfig, mountsChecksum string, now time.Time) rolloutPlan {
+	spec := instance.Spec.Rollout
+	if spec == nil || len(stable) != 1 {
+		return rolloutPlan{}
+	}
+
+	image := r.buildPullImageAddress(instance, dockerConfig)
+	stableImage := deploymentImage(stable[0])
+	status := instance.Status.Rollout.DeepCopy()
+
+	switch {
+	case status == nil || status.Image != image:
+		status = &serverlessv1alpha1.RolloutStatus{
+			Phase:       serverlessv1alpha1.RolloutPhaseSucceeded,
+			Image:       image,
+			StableImage: stableImage,
+		}
+		if stableImage != image {
+			status.Phase = serverlessv1alpha1.RolloutPhaseProgressing
+		}
+	case spec.Rollback && (status.Phase == serverlessv1alpha1.RolloutPhaseProgressing || status.Phase == serverlessv1alpha1.RolloutPhaseAborted):
+		status.Phase = serverlessv1alpha1.RolloutPhaseRolledBack
+		status.StepStartTime = nil
+	case !spec.Rollback && status.Phase == serverlessv1alpha1.RolloutPhaseRolledBack:
+		status.Phase = serverlessv1alpha1.RolloutPhaseProgressing
+		status.Step = 0
+	case status.Phase == serverlessv1alpha1.RolloutPhasePromoting && stableImage == image && r.isDeploymentUpToDate(stable[0]):
+		status.Phase = serverlessv1alpha1.RolloutPhaseSucceeded
+		status.StableImage = stableImage
+		status.Step, status.StepStartTime = 0, nil
+	}
+
+	plan := rolloutPlan{status: status, routing: true}
+	r.completeRolloutPlan(&plan, instance, rtmConfig, stable[0], canaries, dockerConfig, mountsChecksum)
+
+	if status.Phase != serverlessv1alpha1.RolloutPhaseProgressing {
+		return plan
+	}
+
+	switch {
+	case !r.isCanaryReady(canaries, image):
+		status.StepStartTime = nil
+	case spec.Steps[status.Step] == 100:
+		status.Phase = serverlessv1alpha1.RolloutPhasePromoting
+		status.StepStartTime = nil
+		r.completeRolloutPlan(&plan, instance, rtmConfig, stable[0], canaries, dockerConfig, mountsChecksum)
+	case status.StepStartTime == nil:
+		status.StepStartTime = &metav1.Time{Time: now}
+	case spec.StepDuration != nil && !now.Before(status.StepStartTime.Add(spec.StepDuration.Duration)):
+		plan.stepElapsed = true
+	}
+	return plan
+}
+
+// completeRolloutPlan sets the canary Deployment, the weights, and the revisions according to the phase of the rollout
+func (r *FunctionReconciler) completeRolloutPlan(plan *rolloutPlan, instance *serverlessv1alpha1.Function, rtmConfig runtime.Config, stable appsv1.Deployment, canaries []appsv1.Deployment, dockerConfig DockerConfig, mountsChecksum string) {
+	status, steps := plan.status, instance.Spec.Rollout.Steps
+	if int(status.Step) >= len(steps) {
+		status.Step = int32(len(steps) - 1)
+	}
+
+	plan.canary, plan.canaryWeight = nil, 0
+	if status.Phase == serverlessv1alpha1.RolloutPhaseProgressing || status.Phase == serverlessv1alpha1.RolloutPhasePromoting {
+		weight := int32(steps[status.Step])
+		if status.Phase == serverlessv1alpha1.RolloutPhasePromoting {
+			weight = 100
+		}
+		canary := r.buildCanaryDeployment(instance, rtmConfig, dockerConfig, mountsChecksum, status.Image, canaryReplicas(stable, weight))
+		plan.canary = &canary
+
+		if r.isCanaryReady(canaries, status.Image) {
+			plan.canaryWeight = int32(steps[status.Step])
+			if status.Phase == serverlessv1alpha1.RolloutPhasePromoting {
+				plan.canaryWeight = 100
+			}
+		}
+	}
+	plan.stableWeight = 100 - plan.canaryWeight
+
+	status.Revisions = []serverlessv1alpha1.RevisionStatus{{
+		Deployment: stable.GetName(),
+		Image:      deploymentImage(stable),
+		Weight:     plan.stableWeight,
+		Ready:      r.isDeploymentReady(stable),
+	}}
+	if plan.canary != nil && len(canaries) == 1 {
+		status.Revisions = append(status.Revisions, serverlessv1alpha1.RevisionStatus{
+			Deployment: canaries[0].GetName(),
+			Image:      deploymentImage(canaries[0]),
+			Weight:     plan.canaryWeight,
+			Ready:      r.isCanaryReady(canaries, status.Image),
+		})
+	}
+
+	switch status.Phase {
+	case serverlessv1alpha1.RolloutPhaseProgressing:
+		status.Message = fmt.Sprintf("Step %d of %d, %d%% of traffic sent to the new revision", status.Step+1, len(steps), plan.canaryWeight)
+		if !r.isCanaryReady(canaries, status.Image) {
+			status.Message = "Waiting for the new revision to be ready"
+		}
+	case serverlessv1alpha1.RolloutPhasePromoting:
+		status.Message = "Replacing the previous revision with the new one"
+	case serverlessv1alpha1.RolloutPhaseSucceeded:
+		status.Message = "The newest revision serves all traffic"
+	case serverlessv1alpha1.RolloutPhaseRolledBack:
+		status.Message = "Rolled back to the previous revision"
+	}
+}
+
+// exceedsErrorRate checks the error rate of the new revision in the last step, the rollout isn't gated if the maximum
+// error rate isn't set
+func (r *FunctionReconciler) exceedsErrorRate(ctx context.Context, instance *serverlessv1alpha1.Function, canary appsv1.Deployment) (bool, float64, error) {
+	spec := instance.Spec.Rollout
+	if spec.MaxErrorRate == nil {
+		return false, 0, nil
+	}
+
+	errorRate, err := r.metrics.ErrorRate(ctx, instance.GetNamespace(), canary.GetName(), spec.StepDuration.Duration)
+	if err != nil {
+		return false, 0, err
+	}
+	return errorRate > float64(*spec.MaxErrorRate), errorRate, nil
+}
+
+// requeueRolloutStep makes sure the Function is reconciled at the end of the current rollout step
+func (r *FunctionReconciler) requeueRolloutStep(instance *serverlessv1alpha1.Function, result ctrl.Result) ctrl.Result {
+	spec, status := instance.Spec.Rollout, instance.Status.Rollout
+	if spec == nil || spec.StepDuration == nil || status == nil || status.StepStartTime == nil ||
+		status.Phase != serverlessv1alpha1.RolloutPhaseProgressing {
+		return result
+	}
+
+	remaining := time.Until(status.StepStartTime.Add(spec.StepDuration.Duration))
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
+
+// canaryReplicas scales the new revision to its share of the stable replicas at the current step, it doesn't depend on
+// the readiness of the new revision, so the new revision doesn't flap between the sizes
+func canaryReplicas(stable appsv1.Deployment, weight int32) int32 {
+	replicas := int32(1)
+	if stable.Spec.Replicas != nil {
+		replicas = *stable.Spec.Replicas
+	}
+	replicas = (replicas*weight + 99) / 100
+	if replicas < 1 {
+		replicas = 1
+	}
+	return replicas
+}
+
+func (r *FunctionReconciler) isCanaryReady(canaries []appsv1.Deployment, image string) bool {
+	return len(canaries) == 1 && deploymentImage(canaries[0]) == image && r.isDeploymentUpToDate(canaries[0])
+}
+
+// isDeploymentUpToDate returns true if the Deployment is ready after its last change
+func (r *FunctionReconciler) isDeploymentUpToDate(deployment appsv1.Deployment) bool {
+	return deployment.Status.ObservedGeneration >= deployment.GetGeneration() && r.isDeploymentReady(deployment)
+}
+
+func deploymentImage(deployment appsv1.Deployment) string {
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	return deployment.Spec.Template.Spec.Containers[0].Image
+}
+
+func (r *FunctionReconciler) equalCanaries(existing []appsv1.Deployment, expected *appsv1.Deployment) bool {
+	if expected == nil {
+		return len(existing) == 0
+	}
+	return len(existing) == 1 && r.equalCanarySelectors(existing[0], *expected) && r.equalDeployments(existing[0], *expected, false)
+}
+
+func (r *FunctionReconciler) equalCanarySelectors(existing appsv1.Deployment, expected appsv1.Deployment) bool {
+	return existing.Spec.Selector != nil && r.mapsEqual(existing.Spec.Selector.MatchLabels, expected.Spec.Selector.MatchLabels)
+}
+
+func (r *FunctionReconciler) applyCanary(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, canaries []appsv1.Deployment, expected *appsv1.Deployment) error {
+	switch {
+	// the selector is immutable, so the canary Deployment selecting other Pods is recreated
+	case expected == nil || len(canaries) > 1 || !r.equalCanarySelectors(canaries[0], *expected):
+		for i := range canaries {
+			log.Info(fmt.Sprintf("Deleting canary Deployment %s", canaries[i].GetName()))
+			if err := r.client.Delete(ctx, &canaries[i]); client.IgnoreNotFound(err) != nil {
+				log.Error(err, fmt.Sprintf("Cannot delete canary Deployment %s", canaries[i].GetName()))
+				return err
+			}
+		}
+	case len(canaries) == 0:
+		log.Info("Creating canary Deployment")
+		if err := r.client.CreateWithReference(ctx, instance, expected); err != nil {
+			log.Error(err, "Cannot create canary Deployment")
+			return err
+		}
+	case !r.equalDeployments(canaries[0], *expected, false):
+		deployment := canaries[0].DeepCopy()
+		deployment.Spec = expected.Spec
+		deployment.ObjectMeta.Labels = expected.GetLabels()
+
+		log.Info(fmt.Sprintf("Updating canary Deployment %s", deployment.GetName()))
+		if err := r.client.Update(ctx, deployment); err != nil {
+			log.Error(err, fmt.Sprintf("Cannot update canary Deployment %s", deployment.GetName()))
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *FunctionReconciler) equalTrafficRouting(instance *serverlessv1alpha1.Function, routing trafficRouting, plan rolloutPlan) bool {
+	if !plan.routing {
+		return routing.virtualService == nil && len(routing.canaryServices) == 0
+	}
+
+	return len(routing.canaryServices) == 1 &&
+		r.equalServices(routing.canaryServices[0], r.buildCanaryService(instance)) &&
+		r.equalUnstructured(routing.virtualService, r.buildVirtualService(instance, routing.apiRuleHosts, routing.apiRuleGateways, routing.canaryServices[0].GetName(), plan.stableWeight, plan.canaryWeight))
+}
+
+func (r *FunctionReconciler) equalUnstructured(existing *unstructured.Unstructured, expected unstructured.Unstructured) bool {
+	return existing != nil &&
+		r.mapsEqual(existing.GetLabels(), expected.GetLabels()) &&
+		reflect.DeepEqual(existing.Object["spec"], expected.Object["spec"])
+}
+
+func (r *FunctionReconciler) applyTrafficRouting(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, routing trafficRouting, plan rolloutPlan) error {
+	// the canary Service exists before it's used by the routes
+	canaryService, err := r.applyCanaryService(ctx, log, instance, routing.canaryServices)
+	if err != nil {
+		return err
+	}
+	return r.applyUnstructured(ctx, log, instance, routing.virtualService, r.buildVirtualService(instance, routing.apiRuleHosts, routing.apiRuleGateways, canaryService.GetName(), plan.stableWeight, plan.canaryWeight))
+}
+
+func (r *FunctionReconciler) applyCanaryService(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, services []corev1.Service) (*corev1.Service, error) {
+	expected := r.buildCanaryService(instance)
+	if len(services) == 0 {
+		log.Info("Creating canary Service")
+		if err := r.client.CreateWithReference(ctx, instance, &expected); err != nil {
+			log.Error(err, "Cannot create canary Service")
+			return nil, err
+		}
+		return &expected, nil
+	}
+
+	for i := range services[1:] {
+		service := &services[i+1]
+		log.Info(fmt.Sprintf("Deleting excess canary Service %s", service.GetName()))
+		if err := r.client.Delete(ctx, service); client.IgnoreNotFound(err) != nil {
+			log.Error(err, fmt.Sprintf("Cannot delete excess canary Service %s", service.GetName()))
+			return nil, err
+		}
+	}
+
+	service := services[0].DeepCopy()
+	if r.equalServices(*service, expected) {
+		return service, nil
+	}
+	service.Spec.Ports = expected.Spec.Ports
+	service.Spec.Selector = expected.Spec.Selector
+	service.ObjectMeta.Labels = expected.GetLabels()
+
+	log.Info(fmt.Sprintf("Updating canary Service %s", service.GetName()))
+	if err := r.client.Update(ctx, service); err != nil {
+		log.Error(err, fmt.Sprintf("Cannot update canary Service %s", service.GetName()))
+		return nil, err
+	}
+	return service, nil
+}
+
+func (r *FunctionReconciler) applyUnstructured(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, existing *unstructured.Unstructured, expected unstructured.Unstructured) error {
+	kind := expected.GetKind()
+	switch {
+	case existing == nil:
+		log.Info(fmt.Sprintf("Creating %s %s", kind, expected.GetName()))
+		if err := r.client.CreateWithReference(ctx, instance, &expected); err != nil {
+			log.Error(err, fmt.Sprintf("Cannot create %s %s", kind, expected.GetName()))
+			return err
+		}
+	case !r.equalUnstructured(existing, expected):
+		updated := existing.DeepCopy()
+		updated.Object["spec"] = expected.Object["spec"]
+		updated.SetLabels(expected.GetLabels())
+
+		log.Info(fmt.Sprintf("Updating %s %s", kind, updated.GetName()))
+		if err := r.client.Update(ctx, updated); err != nil {
+			log.Error(err, fmt.Sprintf("Cannot update %s %s", kind, updated.GetName()))
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteTrafficRouting deletes the VirtualService before the canary Services, so that no route points to a missing
+// Service
+func (r *FunctionReconciler) deleteTrafficRouting(ctx context.Context, log logr.Logger, routing trafficRouting) error {
+	if object := routing.virtualService; object != nil {
+		log.Info(fmt.Sprintf("Deleting %s %s", object.GetKind(), object.GetName()))
+		if err := r.client.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+			log.Error(err, fmt.Sprintf("Cannot delete %s %s", object.GetKind(), object.GetName()))
+			return err
+		}
+	}
+	for i := range routing.canaryServices {
+		service := &routing.canaryServices[i]
+		log.Info(fmt.Sprintf("Deleting canary Service %s", service.GetName()))
+		if err := r.client.Delete(ctx, service); client.IgnoreNotFound(err) != nil {
+			log.Error(err, fmt.Sprintf("Cannot delete canary Service %s", service.GetName()))
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *FunctionReconciler) updateRolloutStatus(ctx context.Context, instance *serverlessv1alpha1.Function, rollout *serverlessv1alpha1.RolloutStatus) error {
+	if equality.Semantic.DeepEqual(instance.Status.Rollout, rollout) {
+		return nil
+	}
+
+	updated := instance.DeepCopy()
+	updated.Status.Rollout = rollout
+	if err := r.client.Status().Update(ctx, updated); err != nil {
+		return err
+	}
+
+	if rollout != nil && (instance.Status.Rollout == nil || instance.Status.Rollout.Phase != rollout.Phase || instance.Status.Rollout.Step != rollout.Step) {
+		eventType := "Normal"
+		if rollout.Phase == serverlessv1alpha1.RolloutPhaseAborted {
+			eventType = "Warning"
+		}
+		r.recorder.Event(instance, eventType, "Rollout"+string(rollout.Phase), rollout.Message)
+	}
+	return nil
+}
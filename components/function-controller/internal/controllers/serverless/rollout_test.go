@@ -0,0 +1,320 @@
+package serverless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kyma-project/kyma/components/function-controller/internal/controllers/serverless/runtime"
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+func TestFunctionReconciler_planRollout(t *testing.T) {
+	r := &FunctionReconciler{}
+	rtmConfig := runtime.GetRuntimeConfig(serverlessv1alpha1.Nodejs14)
+	dockerConfig := DockerConfig{PullAddress: "registry.kyma.local"}
+	now := time.Now()
+	stepStart := metav1.NewTime(now.Add(-2 * time.Minute))
+
+	newFunction := func(rollout *serverlessv1alpha1.Rollout, status *serverlessv1alpha1.RolloutStatus) *serverlessv1alpha1.Function {
+		return &serverlessv1alpha1.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test", UID: "fn-uid"},
+			Spec:       serverlessv1alpha1.FunctionSpec{Source: "new-source", Rollout: rollout},
+			Status:     serverlessv1alpha1.FunctionStatus{Rollout: status},
+		}
+	}
+	image := r.buildPullImageAddress(newFunction(nil, nil), dockerConfig)
+	steps := &serverlessv1alpha1.Rollout{
+		Steps:        []serverlessv1alpha1.TrafficWeight{10, 50},
+		StepDuration: &metav1.Duration{Duration: time.Minute},
+	}
+
+	for testName, testData := range map[string]struct {
+		function *serverlessv1alpha1.Function
+		canaries []appsv1.Deployment
+
+		expectedPhase          serverlessv1alpha1.RolloutPhase
+		expectedStep           int32
+		expectedCanary         bool
+		expectedCanaryWeight   int32
+		expectedCanaryReplicas int32
+		expectedStepElapsed    bool
+	}{
+		"should not plan without rollout": {
+			function: newFunction(nil, nil),
+		},
+		"should succeed when stable runs the new image": {
+			function:      newFunction(steps, nil),
+			expectedPhase: serverlessv1alpha1.RolloutPhaseSucceeded,
+		},
+		"should start rollout of the new image": {
+			function:               newFunction(steps, &serverlessv1alpha1.RolloutStatus{Phase: serverlessv1alpha1.RolloutPhaseSucceeded, Image: "old-image", StableImage: "old-image"}),
+			expectedPhase:          serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 1,
+		},
+		"should send traffic to the ready new revision": {
+			function:               newFunction(steps, fixRolloutStatus(serverlessv1alpha1.RolloutPhaseProgressing, image, 0, nil)),
+			canaries:               []appsv1.Deployment{fixReadyDeployment("fn-canary-abcde", image)},
+			expectedPhase:          serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 1,
+			expectedCanaryWeight:   10,
+		},
+		"should keep traffic away from the new revision until it's ready": {
+			function:               newFunction(steps, fixRolloutStatus(serverlessv1alpha1.RolloutPhaseProgressing, image, 1, &stepStart)),
+			canaries:               []appsv1.Deployment{fixDeployment("fn-canary-abcde", image)},
+			expectedPhase:          serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedStep:           1,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 2,
+		},
+		"should finish the elapsed step": {
+			function:               newFunction(steps, fixRolloutStatus(serverlessv1alpha1.RolloutPhaseProgressing, image, 0, &stepStart)),
+			canaries:               []appsv1.Deployment{fixReadyDeployment("fn-canary-abcde", image)},
+			expectedPhase:          serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 1,
+			expectedCanaryWeight:   10,
+			expectedStepElapsed:    true,
+		},
+		"should stay at the last step without step duration": {
+			function: newFunction(&serverlessv1alpha1.Rollout{Steps: []serverlessv1alpha1.TrafficWeight{30}},
+				fixRolloutStatus(serverlessv1alpha1.RolloutPhaseProgressing, image, 0, &stepStart)),
+			canaries:               []appsv1.Deployment{fixReadyDeployment("fn-canary-abcde", image)},
+			expectedPhase:          serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 2,
+			expectedCanaryWeight:   30,
+		},
+		"should promote at the step with all traffic": {
+			function: newFunction(&serverlessv1alpha1.Rollout{Steps: []serverlessv1alpha1.TrafficWeight{100}},
+				fixRolloutStatus(serverlessv1alpha1.RolloutPhaseProgressing, image, 0, &stepStart)),
+			canaries:               []appsv1.Deployment{fixReadyDeployment("fn-canary-abcde", image)},
+			expectedPhase:          serverlessv1alpha1.RolloutPhasePromoting,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 4,
+			expectedCanaryWeight:   100,
+		},
+		"should roll back": {
+			function: newFunction(&serverlessv1alpha1.Rollout{Steps: steps.Steps, Rollback: true},
+				fixRolloutStatus(serverlessv1alpha1.RolloutPhaseProgressing, image, 1, &stepStart)),
+			canaries:      []appsv1.Deployment{fixReadyDeployment("fn-canary-abcde", image)},
+			expectedPhase: serverlessv1alpha1.RolloutPhaseRolledBack,
+			expectedStep:  1,
+		},
+		"should restart rollout after rollback": {
+			function:               newFunction(steps, fixRolloutStatus(serverlessv1alpha1.RolloutPhaseRolledBack, image, 1, nil)),
+			expectedPhase:          serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedCanary:         true,
+			expectedCanaryReplicas: 1,
+		},
+		"should keep the aborted rollout": {
+			function:      newFunction(steps, fixRolloutStatus(serverlessv1alpha1.RolloutPhaseAborted, image, 1, nil)),
+			expectedPhase: serverlessv1alpha1.RolloutPhaseAborted,
+			expectedStep:  1,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			stable := fixReadyDeployment("fn-abcde", image)
+			if testData.function.Status.Rollout != nil {
+				stable = fixReadyDeployment("fn-abcde", testData.function.Status.Rollout.StableImage)
+			}
+			stableReplicas := int32(4)
+			stable.Spec.Replicas = &stableReplicas
+
+			// when
+			plan := r.planRollout(testData.function, rtmConfig, []appsv1.Deployment{stable}, testData.canaries, dockerConfig, "", now)
+
+			// then
+			if testData.expectedPhase == "" {
+				g.Expect(plan.status).To(gomega.BeNil())
+				g.Expect(plan.routing).To(gomega.BeFalse())
+				return
+			}
+			g.Expect(plan.routing).To(gomega.BeTrue())
+			g.Expect(plan.status.Phase).To(gomega.Equal(testData.expectedPhase))
+			g.Expect(plan.status.Step).To(gomega.Equal(testData.expectedStep))
+			g.Expect(plan.canary != nil).To(gomega.Equal(testData.expectedCanary))
+			if plan.canary != nil {
+				g.Expect(*plan.canary.Spec.Replicas).To(gomega.Equal(testData.expectedCanaryReplicas))
+			}
+			g.Expect(plan.canaryWeight).To(gomega.Equal(testData.expectedCanaryWeight))
+			g.Expect(plan.stableWeight).To(gomega.Equal(100 - testData.expectedCanaryWeight))
+			g.Expect(plan.stepElapsed).To(gomega.Equal(testData.expectedStepElapsed))
+			g.Expect(plan.status.Revisions[0].Weight).To(gomega.Equal(plan.stableWeight))
+		})
+	}
+}
+
+func TestFunctionReconciler_buildCanaryDeployment(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	r := &FunctionReconciler{}
+	instance := &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test", UID: "fn-uid"},
+		Spec: serverlessv1alpha1.FunctionSpec{
+			Rollout: &serverlessv1alpha1.Rollout{Steps: []serverlessv1alpha1.TrafficWeight{10}},
+		},
+	}
+
+	// when
+	stable := r.buildDeployment(instance, runtime.GetRuntimeConfig(serverlessv1alpha1.Nodejs14), DockerConfig{}, "")
+	canary := r.buildCanaryDeployment(instance, runtime.GetRuntimeConfig(serverlessv1alpha1.Nodejs14), DockerConfig{}, "", "new-image", 2)
+	service := r.buildService(instance)
+	canaryService := r.buildCanaryService(instance)
+
+	// then
+	g.Expect(canary.GenerateName).To(gomega.Equal("fn-canary-"))
+	g.Expect(canary.Labels).To(gomega.HaveKeyWithValue(serverlessv1alpha1.FunctionRolloutRoleLabel, serverlessv1alpha1.FunctionRolloutRoleCanaryValue))
+	g.Expect(canary.Spec.Template.Labels).To(gomega.HaveKeyWithValue(serverlessv1alpha1.FunctionResourceLabel, serverlessv1alpha1.FunctionResourceLabelCanaryDeploymentValue))
+	g.Expect(canary.Spec.Template.Spec.Containers[0].Image).To(gomega.Equal("new-image"))
+	g.Expect(*canary.Spec.Replicas).To(gomega.Equal(int32(2)))
+
+	stableSelector := labels.SelectorFromSet(stable.Spec.Selector.MatchLabels)
+	canarySelector := labels.SelectorFromSet(canary.Spec.Selector.MatchLabels)
+	g.Expect(stableSelector.Matches(labels.Set(stable.Spec.Template.Labels))).To(gomega.BeTrue())
+	g.Expect(stableSelector.Matches(labels.Set(canary.Spec.Template.Labels))).To(gomega.BeFalse())
+	g.Expect(canarySelector.Matches(labels.Set(canary.Spec.Template.Labels))).To(gomega.BeTrue())
+	g.Expect(canarySelector.Matches(labels.Set(stable.Spec.Template.Labels))).To(gomega.BeFalse())
+
+	g.Expect(labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(canary.Spec.Template.Labels))).To(gomega.BeFalse())
+	g.Expect(canaryService.GenerateName).To(gomega.Equal("fn-canary-"))
+	g.Expect(canaryService.Spec.Selector).To(gomega.Equal(canary.Spec.Selector.MatchLabels))
+
+	stables, canaries := splitCanaryDeployments([]appsv1.Deployment{canary, stable})
+	g.Expect(stables).To(gomega.HaveLen(1))
+	g.Expect(canaries).To(gomega.HaveLen(1))
+	g.Expect(canaries[0].GenerateName).To(gomega.Equal("fn-canary-"))
+
+	stableServices, canaryServices := splitCanaryServices([]corev1.Service{canaryService, service})
+	g.Expect(stableServices).To(gomega.HaveLen(1))
+	g.Expect(stableServices[0].Name).To(gomega.Equal("fn"))
+	g.Expect(canaryServices).To(gomega.HaveLen(1))
+}
+
+func Test_canaryReplicas(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		stableReplicas *int32
+		weight         int32
+		expected       int32
+	}{
+		"should round up the share of the stable replicas": {stableReplicas: int32Ptr(5), weight: 30, expected: 2},
+		"should run the full replicas on promotion":        {stableReplicas: int32Ptr(5), weight: 100, expected: 5},
+		"should run one replica at least":                  {stableReplicas: int32Ptr(0), weight: 10, expected: 1},
+		"should run one replica without stable replicas":   {weight: 50, expected: 1},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			stable := fixDeployment("fn-abcde", "image")
+			stable.Spec.Replicas = testData.stableReplicas
+
+			g.Expect(canaryReplicas(stable, testData.weight)).To(gomega.Equal(testData.expected))
+		})
+	}
+}
+
+func TestFunctionReconciler_buildVirtualService(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	r := &FunctionReconciler{}
+	instance := &serverlessv1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test"}}
+
+	// when
+	split := r.buildVirtualService(instance, []string{"fn.kyma.local"}, []string{"kyma-gateway.kyma-system.svc.cluster.local"}, "fn-canary-abcde", 90, 10)
+	stableOnly := r.buildVirtualService(instance, nil, nil, "fn-canary-abcde", 100, 0)
+
+	// then
+	g.Expect(split.GetKind()).To(gomega.Equal("VirtualService"))
+	g.Expect(split.Object["spec"]).To(gomega.Equal(map[string]interface{}{
+		"hosts":    []interface{}{"fn.test.svc.cluster.local", "fn.kyma.local"},
+		"gateways": []interface{}{"mesh", "kyma-gateway.kyma-system.svc.cluster.local"},
+		"http": []interface{}{map[string]interface{}{"route": []interface{}{
+			map[string]interface{}{
+				"destination": map[string]interface{}{"host": "fn.test.svc.cluster.local"},
+				"weight":      int64(90),
+			},
+			map[string]interface{}{
+				"destination": map[string]interface{}{"host": "fn-canary-abcde.test.svc.cluster.local"},
+				"weight":      int64(10),
+			},
+		}}},
+	}))
+	g.Expect(stableOnly.Object["spec"].(map[string]interface{})["gateways"]).To(gomega.Equal([]interface{}{"mesh"}))
+	g.Expect(r.equalUnstructured(&split, stableOnly)).To(gomega.BeFalse())
+	g.Expect(r.equalUnstructured(&stableOnly, r.buildVirtualService(instance, nil, nil, "fn-canary-abcde", 100, 0))).To(gomega.BeTrue())
+}
+
+func TestFunctionReconciler_buildDeploymentImageAddress(t *testing.T) {
+	r := &FunctionReconciler{}
+	instance := &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test", UID: "fn-uid"},
+		Spec: serverlessv1alpha1.FunctionSpec{
+			Rollout: &serverlessv1alpha1.Rollout{Steps: []serverlessv1alpha1.TrafficWeight{10}},
+		},
+	}
+	image := r.buildPullImageAddress(instance, DockerConfig{})
+
+	for testName, testData := range map[string]struct {
+		phase         serverlessv1alpha1.RolloutPhase
+		expectedImage string
+	}{
+		"should keep the stable image during rollout": {
+			phase:         serverlessv1alpha1.RolloutPhaseProgressing,
+			expectedImage: "stable-image",
+		},
+		"should keep the stable image after rollback": {
+			phase:         serverlessv1alpha1.RolloutPhaseRolledBack,
+			expectedImage: "stable-image",
+		},
+		"should use the new image on promotion": {
+			phase:         serverlessv1alpha1.RolloutPhasePromoting,
+			expectedImage: image,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			function := instance.DeepCopy()
+			function.Status.Rollout = &serverlessv1alpha1.RolloutStatus{Phase: testData.phase, Image: image, StableImage: "stable-image"}
+
+			g.Expect(r.buildDeploymentImageAddress(function, DockerConfig{})).To(gomega.Equal(testData.expectedImage))
+		})
+	}
+}
+
+func fixRolloutStatus(phase serverlessv1alpha1.RolloutPhase, image string, step int32, stepStartTime *metav1.Time) *serverlessv1alpha1.RolloutStatus {
+	return &serverlessv1alpha1.RolloutStatus{
+		Phase:         phase,
+		Image:         image,
+		StableImage:   "stable-image",
+		Step:          step,
+		StepStartTime: stepStartTime,
+	}
+}
+
+func fixDeployment(name, image string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: image}}},
+			},
+		},
+	}
+}
+
+func fixReadyDeployment(name, image string) appsv1.Deployment {
+	deployment := fixDeployment(name, image)
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: MinimumReplicasAvailable},
+		{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: NewRSAvailableReason},
+	}
+	return deployment
+}
@@ -0,0 +1,12 @@
+package runtime
+
+// plain prepares the dependencies of the runtimes without a dependency sanitizer, they are built as they are
+type plain struct {
+	Config
+}
+
+func (p plain) SanitizeDependencies(dependencies string) string {
+	return dependencies
+}
+
+var _ Runtime = plain{}
@@ -10,73 +10,107 @@ type Runtime interface {
 }
 
 type Config struct {
-	Runtime                 serverlessv1alpha1.Runtime
-	DependencyFile          string
-	FunctionFile            string
-	DockerfileConfigMapName string
-	RuntimeEnvs             []corev1.EnvVar
+	Runtime                   serverlessv1alpha1.Runtime
+	DependencyFile            string
+	FunctionFile              string
+	DockerfileConfigMapName   string
+	RuntimeEnvs               []corev1.EnvVar
+	DependencySanitizer       serverlessv1alpha1.DependencySanitizer
+	PackageRegistryConfigFile string
 }
 
+// builtinConfigs are the runtimes shipped with the Function Controller, they can be replaced by the FunctionRuntimes
+// with the same names
+var builtinConfigs = map[serverlessv1alpha1.Runtime]Config{
+	serverlessv1alpha1.Nodejs12: {
+		Runtime:                 serverlessv1alpha1.Nodejs12,
+		DependencyFile:          "package.json",
+		FunctionFile:            "handler.js",
+		DockerfileConfigMapName: "dockerfile-nodejs-12",
+		RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
+			{Name: "FUNC_RUNTIME", Value: "nodejs12"},
+		},
+		DependencySanitizer:       serverlessv1alpha1.DependencySanitizerJSONObject,
+		PackageRegistryConfigFile: ".npmrc",
+	},
+	serverlessv1alpha1.Nodejs14: {
+		Runtime:                 serverlessv1alpha1.Nodejs14,
+		DependencyFile:          "package.json",
+		FunctionFile:            "handler.js",
+		DockerfileConfigMapName: "dockerfile-nodejs-14",
+		RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
+			{Name: "FUNC_RUNTIME", Value: "nodejs14"},
+		},
+		DependencySanitizer:       serverlessv1alpha1.DependencySanitizerJSONObject,
+		PackageRegistryConfigFile: ".npmrc",
+	},
+	serverlessv1alpha1.Python38: {
+		Runtime:                 serverlessv1alpha1.Python38,
+		DependencyFile:          "requirements.txt",
+		FunctionFile:            "handler.py",
+		DockerfileConfigMapName: "dockerfile-python-38",
+		RuntimeEnvs: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "$(KUBELESS_INSTALL_VOLUME)/lib.python3.8/site-packages:$(KUBELESS_INSTALL_VOLUME)"}, // https://github.com/kubeless/runtimes/blob/master/stable/python/python.jsonnet#L45
+			{Name: "FUNC_RUNTIME", Value: "python38"}},
+		DependencySanitizer:       serverlessv1alpha1.DependencySanitizerNone,
+		PackageRegistryConfigFile: "pip.conf",
+	},
+	serverlessv1alpha1.Python39: {
+		Runtime:                 serverlessv1alpha1.Python39,
+		DependencyFile:          "requirements.txt",
+		FunctionFile:            "handler.py",
+		DockerfileConfigMapName: "dockerfile-python-39",
+		RuntimeEnvs: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "$(KUBELESS_INSTALL_VOLUME)/lib.python3.9/site-packages:$(KUBELESS_INSTALL_VOLUME)"}, // https://github.com/kubeless/runtimes/blob/master/stable/python/python.jsonnet#L45
+			{Name: "FUNC_RUNTIME", Value: "python39"}},
+		DependencySanitizer:       serverlessv1alpha1.DependencySanitizerNone,
+		PackageRegistryConfigFile: "pip.conf",
+	},
+}
+
+// IsBuiltin returns true if the runtime is shipped with the Function Controller
+func IsBuiltin(r serverlessv1alpha1.Runtime) bool {
+	_, ok := builtinConfigs[r]
+	return ok
+}
+
+// GetRuntimeConfig returns the configuration of the runtime shipped with the Function Controller, nodejs12 is returned
+// for unknown runtimes
 func GetRuntimeConfig(r serverlessv1alpha1.Runtime) Config {
-	switch r {
-	case serverlessv1alpha1.Nodejs12:
-		return Config{
-			Runtime:                 serverlessv1alpha1.Nodejs12,
-			DependencyFile:          "package.json",
-			FunctionFile:            "handler.js",
-			DockerfileConfigMapName: "dockerfile-nodejs-12",
-			RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
-				{Name: "FUNC_RUNTIME", Value: "nodejs12"},
-			},
-		}
-	case serverlessv1alpha1.Nodejs14:
-		return Config{
-			Runtime:                 serverlessv1alpha1.Nodejs14,
-			DependencyFile:          "package.json",
-			FunctionFile:            "handler.js",
-			DockerfileConfigMapName: "dockerfile-nodejs-14",
-			RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
-				{Name: "FUNC_RUNTIME", Value: "nodejs14"},
-			},
-		}
-	case serverlessv1alpha1.Python38:
-		return Config{
-			Runtime:                 serverlessv1alpha1.Python38,
-			DependencyFile:          "requirements.txt",
-			FunctionFile:            "handler.py",
-			DockerfileConfigMapName: "dockerfile-python-38",
-			RuntimeEnvs: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "$(KUBELESS_INSTALL_VOLUME)/lib.python3.8/site-packages:$(KUBELESS_INSTALL_VOLUME)"}, // https://github.com/kubeless/runtimes/blob/master/stable/python/python.jsonnet#L45
-				{Name: "FUNC_RUNTIME", Value: "python38"}},
-		}
-	case serverlessv1alpha1.Python39:
-		return Config{
-			Runtime:                 serverlessv1alpha1.Python39,
-			DependencyFile:          "requirements.txt",
-			FunctionFile:            "handler.py",
-			DockerfileConfigMapName: "dockerfile-python-39",
-			RuntimeEnvs: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "$(KUBELESS_INSTALL_VOLUME)/lib.python3.9/site-packages:$(KUBELESS_INSTALL_VOLUME)"}, // https://github.com/kubeless/runtimes/blob/master/stable/python/python.jsonnet#L45
-				{Name: "FUNC_RUNTIME", Value: "python39"}},
-		}
-	default:
-		return Config{
-			Runtime:                 serverlessv1alpha1.Nodejs12,
-			DependencyFile:          "package.json",
-			FunctionFile:            "handler.js",
-			DockerfileConfigMapName: "dockerfile-nodejs-12",
-			RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
-				{Name: "FUNC_RUNTIME", Value: "nodejs12"},
-			},
-		}
+	config, ok := builtinConfigs[r]
+	if !ok {
+		config = builtinConfigs[serverlessv1alpha1.Nodejs12]
 	}
+	config.RuntimeEnvs = append([]corev1.EnvVar(nil), config.RuntimeEnvs...)
+	return config
 }
 
+// NewConfig returns the configuration of the runtime defined by the FunctionRuntime
+func NewConfig(functionRuntime *serverlessv1alpha1.FunctionRuntime) Config {
+	sanitizer := functionRuntime.Spec.DependencySanitizer
+	if sanitizer == "" {
+		sanitizer = serverlessv1alpha1.DependencySanitizerNone
+	}
+	return Config{
+		Runtime:                   serverlessv1alpha1.Runtime(functionRuntime.Name),
+		DependencyFile:            functionRuntime.Spec.DependencyFile,
+		FunctionFile:              functionRuntime.Spec.FunctionFile,
+		DockerfileConfigMapName:   functionRuntime.Spec.DockerfileConfigMapName,
+		RuntimeEnvs:               append([]corev1.EnvVar(nil), functionRuntime.Spec.Env...),
+		DependencySanitizer:       sanitizer,
+		PackageRegistryConfigFile: functionRuntime.Spec.PackageRegistryConfigFile,
+	}
+}
+
+// GetRuntime returns the runtime shipped with the Function Controller, nodejs12 is returned for unknown runtimes
 func GetRuntime(r serverlessv1alpha1.Runtime) Runtime {
-	switch r {
-	case serverlessv1alpha1.Nodejs12, serverlessv1alpha1.Nodejs14:
-		return nodejs{}
-	case serverlessv1alpha1.Python38, serverlessv1alpha1.Python39:
-		return python{}
+	return NewRuntime(GetRuntimeConfig(r))
+}
+
+// NewRuntime returns the runtime which prepares the Function according to the configuration
+func NewRuntime(config Config) Runtime {
+	switch config.DependencySanitizer {
+	case serverlessv1alpha1.DependencySanitizerJSONObject:
+		return nodejs{Config: config}
 	default:
-		return nodejs{}
+		return plain{Config: config}
 	}
 }
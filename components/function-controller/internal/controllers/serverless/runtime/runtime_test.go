@@ -7,6 +7,7 @@ import (
 	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
 	"github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGetRuntimeConfig(t *testing.T) {
@@ -25,6 +26,8 @@ func TestGetRuntimeConfig(t *testing.T) {
 				DockerfileConfigMapName: "dockerfile-nodejs-12",
 				RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
 					{Name: "FUNC_RUNTIME", Value: "nodejs12"}},
+				DependencySanitizer:       serverlessv1alpha1.DependencySanitizerJSONObject,
+				PackageRegistryConfigFile: ".npmrc",
 			},
 		},
 		"python38": {
@@ -37,6 +40,8 @@ func TestGetRuntimeConfig(t *testing.T) {
 				DockerfileConfigMapName: "dockerfile-python-38",
 				RuntimeEnvs: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "$(KUBELESS_INSTALL_VOLUME)/lib.python3.8/site-packages:$(KUBELESS_INSTALL_VOLUME)"},
 					{Name: "FUNC_RUNTIME", Value: "python38"}},
+				DependencySanitizer:       serverlessv1alpha1.DependencySanitizerNone,
+				PackageRegistryConfigFile: "pip.conf",
 			},
 		},
 		"python39": {
@@ -49,6 +54,8 @@ func TestGetRuntimeConfig(t *testing.T) {
 				DockerfileConfigMapName: "dockerfile-python-39",
 				RuntimeEnvs: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "$(KUBELESS_INSTALL_VOLUME)/lib.python3.9/site-packages:$(KUBELESS_INSTALL_VOLUME)"},
 					{Name: "FUNC_RUNTIME", Value: "python39"}},
+				DependencySanitizer:       serverlessv1alpha1.DependencySanitizerNone,
+				PackageRegistryConfigFile: "pip.conf",
 			},
 		},
 		"nodej14": {
@@ -61,6 +68,8 @@ func TestGetRuntimeConfig(t *testing.T) {
 				DockerfileConfigMapName: "dockerfile-nodejs-14",
 				RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
 					{Name: "FUNC_RUNTIME", Value: "nodejs14"}},
+				DependencySanitizer:       serverlessv1alpha1.DependencySanitizerJSONObject,
+				PackageRegistryConfigFile: ".npmrc",
 			},
 		},
 		"default": {
@@ -73,6 +82,8 @@ func TestGetRuntimeConfig(t *testing.T) {
 				DockerfileConfigMapName: "dockerfile-nodejs-14",
 				RuntimeEnvs: []corev1.EnvVar{{Name: "NODE_PATH", Value: "$(KUBELESS_INSTALL_VOLUME)/node_modules"},
 					{Name: "FUNC_RUNTIME", Value: "nodejs14"}},
+				DependencySanitizer:       serverlessv1alpha1.DependencySanitizerJSONObject,
+				PackageRegistryConfigFile: ".npmrc",
 			},
 		}} {
 		t.Run(testName, func(t *testing.T) {
@@ -87,3 +98,34 @@ func TestGetRuntimeConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig(t *testing.T) {
+	//given
+	g := gomega.NewWithT(t)
+	functionRuntime := &serverlessv1alpha1.FunctionRuntime{
+		ObjectMeta: metav1.ObjectMeta{Name: "go116"},
+		Spec: serverlessv1alpha1.FunctionRuntimeSpec{
+			DependencyFile:          "go.mod",
+			FunctionFile:            "handler.go",
+			DockerfileConfigMapName: "dockerfile-go-116",
+			Env:                     []corev1.EnvVar{{Name: "FUNC_RUNTIME", Value: "go116"}},
+		},
+	}
+
+	// when
+	config := runtime.NewConfig(functionRuntime)
+
+	// then
+	g.Expect(config).To(gomega.Equal(runtime.Config{
+		Runtime:                 "go116",
+		DependencyFile:          "go.mod",
+		FunctionFile:            "handler.go",
+		DockerfileConfigMapName: "dockerfile-go-116",
+		RuntimeEnvs:             []corev1.EnvVar{{Name: "FUNC_RUNTIME", Value: "go116"}},
+		DependencySanitizer:     serverlessv1alpha1.DependencySanitizerNone,
+	}))
+	g.Expect(runtime.NewRuntime(config).SanitizeDependencies("")).To(gomega.Equal(""))
+
+	functionRuntime.Spec.DependencySanitizer = serverlessv1alpha1.DependencySanitizerJSONObject
+	g.Expect(runtime.NewRuntime(runtime.NewConfig(functionRuntime)).SanitizeDependencies("")).To(gomega.Equal("{}"))
+}
@@ -0,0 +1,361 @@
+package serverless
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+var apiRuleListGVK = schema.GroupVersionKind{Group: "gateway.kyma-project.io", Version: "v1alpha1", Kind: "APIRuleList"}
+
+// scaleToZero is the state of the Function with minReplicas set to 0. Its Service has no selector, and its Endpoints,
+// managed by the Function Controller, point to the ready Function Pods or, if there are none, to the activator.
+type scaleToZero struct {
+	enabled bool
+	// endpoints are the Endpoints of the Function Service, nil if they don't exist
+	endpoints *corev1.Endpoints
+	pods      []corev1.Pod
+	// activator are the Endpoints of the activator Service, nil if they don't exist
+	activator *corev1.Endpoints
+	// hosts are the hosts of the Function Service and of its APIRules, the activator resolves the Function from them
+	hosts []string
+	// replicas is the new number of the Deployment replicas, nil if it doesn't change
+	replicas *int32
+}
+
+func isScaleToZeroEnabled(instance *serverlessv1alpha1.Function) bool {
+	return instance.Spec.MinReplicas != nil && *instance.Spec.MinReplicas == 0
+}
+
+func (r *FunctionReconciler) readScaleToZero(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, deployments []appsv1.Deployment) (scaleToZero, error) {
+	if !isScaleToZeroEnabled(instance) {
+		return scaleToZero{}, nil
+	}
+	state := scaleToZero{enabled: true}
+
+	var err error
+	if state.endpoints, err = r.getEndpoints(ctx, client.ObjectKey{Namespace: instance.GetNamespace(), Name: instance.GetName()}); err != nil {
+		return scaleToZero{}, err
+	}
+	if state.activator, err = r.getEndpoints(ctx, client.ObjectKey{Namespace: r.config.ScaleToZero.ActivatorServiceNamespace, Name: r.config.ScaleToZero.ActivatorServiceName}); err != nil {
+		return scaleToZero{}, err
+	}
+	if state.hosts, err = r.readFunctionHosts(ctx, instance); err != nil {
+		return scaleToZero{}, err
+	}
+
+	var pods corev1.PodList
+	if err := r.client.ListByLabel(ctx, instance.GetNamespace(), r.deploymentSelectorLabels(instance), &pods); err != nil {
+		return scaleToZero{}, err
+	}
+	state.pods = pods.Items
+
+	if len(deployments) == 1 {
+		state.replicas = r.calculateReplicas(ctx, log, instance, deployments[0], time.Now())
+	}
+	return state, nil
+}
+
+func (r *FunctionReconciler) getEndpoints(ctx context.Context, key client.ObjectKey) (*corev1.Endpoints, error) {
+	var endpoints corev1.Endpoints
+	if err := r.client.Get(ctx, key, &endpoints); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &endpoints, nil
+}
+
+// readFunctionHosts returns the hosts of the Function Service and the hosts of the APIRules which expose it
+func (r *FunctionReconciler) readFunctionHosts(ctx context.Context, instance *serverlessv1alpha1.Function) ([]string, error) {
+	hosts := []string{
+		fmt.Sprintf("%s.%s", instance.GetName(), instance.GetNamespace()),
+		fmt.Sprintf("%s.%s.svc", instance.GetName(), instance.GetNamespace()),
+		r.serviceHost(instance),
+	}
+
+	apiRules, err := r.readAPIRules(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	var apiRuleHosts []string
+	for _, apiRule := range apiRules {
+		host, _, _ := unstructured.NestedString(apiRule.Object, "spec", "service", "host")
+		if host != "" {
+			apiRuleHosts = append(apiRuleHosts, strings.ToLower(host))
+		}
+	}
+	sort.Strings(apiRuleHosts)
+	return append(hosts, apiRuleHosts...), nil
+}
+
+// readAPIRules returns the APIRules which expose the Function Service, there are none if the APIRule CRD isn't
+// installed
+func (r *FunctionReconciler) readAPIRules(ctx context.Context, instance *serverlessv1alpha1.Function) ([]unstructured.Unstructured, error) {
+	apiRules := &unstructured.UnstructuredList{}
+	apiRules.SetGroupVersionKind(apiRuleListGVK)
+	if err := r.client.ListByLabel(ctx, instance.GetNamespace(), nil, apiRules); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var exposing []unstructured.Unstructured
+	for _, apiRule := range apiRules.Items {
+		service, _, _ := unstructured.NestedString(apiRule.Object, "spec", "service", "name")
+		if service == instance.GetName() {
+			exposing = append(exposing, apiRule)
+		}
+	}
+	return exposing, nil
+}
+
+// calculateReplicas scales the Function to zero if it received no requests during the idle timeout, otherwise the
+// number of replicas is the request concurrency divided by the target concurrency. The Function scaled to zero is
+// scaled up by the activator.
+func (r *FunctionReconciler) calculateReplicas(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, deployment appsv1.Deployment, now time.Time) *int32 {
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas == 0 {
+		return nil
+	}
+	current := *deployment.Spec.Replicas
+	config := r.config.ScaleToZero
+
+	count, err := r.metrics.RequestCount(ctx, instance.GetNamespace(), deployment.GetName(), config.IdleTimeout)
+	if err != nil {
+		log.Error(err, "Cannot read request count")
+		return nil
+	}
+	if count == 0 && !r.isRecentlyActivated(deployment, now) {
+		replicas := int32(0)
+		return &replicas
+	}
+
+	concurrency, err := r.metrics.RequestConcurrency(ctx, instance.GetNamespace(), deployment.GetName(), config.MetricsWindow)
+	if err != nil {
+		log.Error(err, "Cannot read request concurrency")
+		return nil
+	}
+	_, maxReplicas := r.defaultReplicas(instance.Spec)
+	replicas := int32(math.Ceil(concurrency / float64(config.TargetConcurrency)))
+	switch {
+	case replicas < 1:
+		replicas = 1
+	case replicas > maxReplicas:
+		replicas = maxReplicas
+	}
+
+	if replicas == current {
+		return nil
+	}
+	return &replicas
+}
+
+// isRecentlyActivated prevents scaling the Function to zero before its first requests show up in the metrics
+func (r *FunctionReconciler) isRecentlyActivated(deployment appsv1.Deployment, now time.Time) bool {
+	activationTime, err := time.Parse(time.RFC3339, deployment.GetAnnotations()[serverlessv1alpha1.FunctionActivationTimeAnnotation])
+	if err != nil {
+		return false
+	}
+	return now.Before(activationTime.Add(r.config.ScaleToZero.IdleTimeout))
+}
+
+func (r *FunctionReconciler) isOnEndpointsChange(instance *serverlessv1alpha1.Function, state scaleToZero) bool {
+	if !state.enabled {
+		return false
+	}
+	expected := r.buildEndpoints(instance, state)
+	return state.endpoints == nil || !r.equalEndpoints(*state.endpoints, expected)
+}
+
+func (r *FunctionReconciler) onEndpointsChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, state scaleToZero) (ctrl.Result, error) {
+	expected := r.buildEndpoints(instance, state)
+
+	if state.endpoints == nil {
+		log.Info(fmt.Sprintf("Creating Endpoints %s", expected.GetName()))
+		if err := r.client.CreateWithReference(ctx, instance, &expected); err != nil {
+			log.Error(err, fmt.Sprintf("Cannot create Endpoints with name %s", expected.GetName()))
+			return ctrl.Result{}, err
+		}
+		log.Info(fmt.Sprintf("Endpoints %s created", expected.GetName()))
+		return ctrl.Result{}, nil
+	}
+
+	endpoints := state.endpoints.DeepCopy()
+	endpoints.Subsets = expected.Subsets
+	endpoints.ObjectMeta.Labels = expected.GetLabels()
+	setActivatorHosts(endpoints, expected)
+
+	log.Info(fmt.Sprintf("Updating Endpoints %s", endpoints.GetName()))
+	if err := r.client.Update(ctx, endpoints); err != nil {
+		log.Error(err, fmt.Sprintf("Cannot update Endpoints with name %s", endpoints.GetName()))
+		return ctrl.Result{}, err
+	}
+	log.Info(fmt.Sprintf("Endpoints %s updated", endpoints.GetName()))
+	return ctrl.Result{}, nil
+}
+
+// buildEndpoints points the Function Service to the ready Function Pods, or to the activator if there are none or the
+// Function is about to be scaled to zero, so that no request is sent to the terminating Pods
+func (r *FunctionReconciler) buildEndpoints(instance *serverlessv1alpha1.Function, state scaleToZero) corev1.Endpoints {
+	endpoints := corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetName(),
+			Namespace: instance.GetNamespace(),
+			Labels:    r.functionLabels(instance),
+		},
+	}
+
+	addresses, port := r.readyPodAddresses(state.pods), svcTargetPort.IntVal
+	if len(addresses) == 0 || (state.replicas != nil && *state.replicas == 0) {
+		addresses, port = r.activatorAddresses(state.activator)
+		if len(addresses) > 0 {
+			endpoints.SetAnnotations(map[string]string{
+				serverlessv1alpha1.FunctionActivatorHostsAnnotation: strings.Join(state.hosts, ","),
+			})
+		}
+	}
+	if len(addresses) == 0 {
+		return endpoints
+	}
+
+	endpoints.Subsets = []corev1.EndpointSubset{{
+		Addresses: addresses,
+		Ports: []corev1.EndpointPort{{
+			Name:     "http", // it has to match the port of the Service
+			Port:     port,
+			Protocol: corev1.ProtocolTCP,
+		}},
+	}}
+	return endpoints
+}
+
+func (r *FunctionReconciler) readyPodAddresses(pods []corev1.Pod) []corev1.EndpointAddress {
+	var addresses []corev1.EndpointAddress
+	for _, pod := range pods {
+		if !isPodReady(pod) {
+			continue
+		}
+		addresses = append(addresses, corev1.EndpointAddress{
+			IP: pod.Status.PodIP,
+			TargetRef: &corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: pod.GetNamespace(),
+				Name:      pod.GetName(),
+				UID:       pod.GetUID(),
+			},
+		})
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].IP < addresses[j].IP
+	})
+	return addresses
+}
+
+func (r *FunctionReconciler) activatorAddresses(activator *corev1.Endpoints) ([]corev1.EndpointAddress, int32) {
+	if activator == nil {
+		return nil, 0
+	}
+
+	var addresses []corev1.EndpointAddress
+	var port int32
+	for _, subset := range activator.Subsets {
+		for _, subsetPort := range subset.Ports {
+			if subsetPort.Name != "http" {
+				continue
+			}
+			for _, address := range subset.Addresses {
+				// the activator Pods aren't referenced, so that the activator doesn't take them for the Function Pods
+				addresses = append(addresses, corev1.EndpointAddress{IP: address.IP})
+			}
+			port = subsetPort.Port
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].IP < addresses[j].IP
+	})
+	return addresses, port
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (r *FunctionReconciler) equalEndpoints(existing, expected corev1.Endpoints) bool {
+	return r.mapsEqual(existing.GetLabels(), expected.GetLabels()) &&
+		existing.GetAnnotations()[serverlessv1alpha1.FunctionActivatorHostsAnnotation] == expected.GetAnnotations()[serverlessv1alpha1.FunctionActivatorHostsAnnotation] &&
+		apiequality.Semantic.DeepEqual(existing.Subsets, expected.Subsets)
+}
+
+// setActivatorHosts copies the activator hosts of the expected Endpoints, the other annotations are kept
+func setActivatorHosts(endpoints *corev1.Endpoints, expected corev1.Endpoints) {
+	hosts, ok := expected.GetAnnotations()[serverlessv1alpha1.FunctionActivatorHostsAnnotation]
+	if !ok {
+		delete(endpoints.Annotations, serverlessv1alpha1.FunctionActivatorHostsAnnotation)
+		return
+	}
+	if endpoints.Annotations == nil {
+		endpoints.Annotations = make(map[string]string)
+	}
+	endpoints.Annotations[serverlessv1alpha1.FunctionActivatorHostsAnnotation] = hosts
+}
+
+func (r *FunctionReconciler) isOnScaleChange(state scaleToZero) bool {
+	return state.enabled && state.replicas != nil
+}
+
+func (r *FunctionReconciler) onScaleChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function, deployment appsv1.Deployment, replicas int32) (ctrl.Result, error) {
+	deploy := deployment.DeepCopy()
+	deploy.Spec.Replicas = &replicas
+
+	log.Info(fmt.Sprintf("Scaling Deployment %s to %d replicas", deploy.GetName(), replicas))
+	if err := r.client.Update(ctx, deploy); err != nil {
+		log.Error(err, fmt.Sprintf("Cannot scale Deployment with name %s", deploy.GetName()))
+		return ctrl.Result{}, err
+	}
+	log.Info(fmt.Sprintf("Deployment %s scaled", deploy.GetName()))
+
+	return r.updateStatusWithoutRepository(ctx, ctrl.Result{RequeueAfter: r.config.ScaleToZero.SyncPeriod}, instance, serverlessv1alpha1.Condition{
+		Type:               serverlessv1alpha1.ConditionRunning,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             serverlessv1alpha1.ConditionReasonDeploymentScaled,
+		Message:            fmt.Sprintf("Deployment %s scaled to %d replicas", deploy.GetName(), replicas),
+	})
+}
+
+// requeueScaleToZero makes sure that the request concurrency of the Function scaled to zero is checked regularly
+func (r *FunctionReconciler) requeueScaleToZero(instance *serverlessv1alpha1.Function, result ctrl.Result) ctrl.Result {
+	if !isScaleToZeroEnabled(instance) {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > r.config.ScaleToZero.SyncPeriod {
+		result.RequeueAfter = r.config.ScaleToZero.SyncPeriod
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package serverless
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+type fakeMetrics struct {
+	count       float64
+	concurrency float64
+	err         error
+}
+
+func (m *fakeMetrics) ErrorRate(_ context.Context, _, _ string, _ time.Duration) (float64, error) {
+	return 0, m.err
+}
+
+func (m *fakeMetrics) RequestConcurrency(_ context.Context, _, _ string, _ time.Duration) (float64, error) {
+	return m.concurrency, m.err
+}
+
+func (m *fakeMetrics) RequestCount(_ context.Context, _, _ string, _ time.Duration) (float64, error) {
+	return m.count, m.err
+}
+
+func TestFunctionReconciler_calculateReplicas(t *testing.T) {
+	zero, five := int32(0), int32(5)
+	now := time.Now()
+	instance := &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test"},
+		Spec:       serverlessv1alpha1.FunctionSpec{MinReplicas: &zero, MaxReplicas: &five},
+	}
+
+	for testName, testData := range map[string]struct {
+		replicas       int32
+		activationTime time.Time
+		metrics        *fakeMetrics
+
+		expectedReplicas *int32
+	}{
+		"should scale idle Function to zero": {
+			replicas:         2,
+			metrics:          &fakeMetrics{},
+			expectedReplicas: &zero,
+		},
+		"should not scale recently activated Function to zero": {
+			replicas:       1,
+			activationTime: now.Add(-time.Minute),
+			metrics:        &fakeMetrics{},
+		},
+		"should scale idle Function to zero after the idle timeout": {
+			replicas:         1,
+			activationTime:   now.Add(-time.Hour),
+			metrics:          &fakeMetrics{},
+			expectedReplicas: &zero,
+		},
+		"should scale up according to concurrency": {
+			replicas:         1,
+			metrics:          &fakeMetrics{count: 100, concurrency: 25},
+			expectedReplicas: int32Ptr(3),
+		},
+		"should not scale over maxReplicas": {
+			replicas:         1,
+			metrics:          &fakeMetrics{count: 100, concurrency: 80},
+			expectedReplicas: &five,
+		},
+		"should keep one replica with low concurrency": {
+			replicas:         3,
+			metrics:          &fakeMetrics{count: 1, concurrency: 0.1},
+			expectedReplicas: int32Ptr(1),
+		},
+		"should not change replicas if concurrency matches": {
+			replicas: 2,
+			metrics:  &fakeMetrics{count: 100, concurrency: 15},
+		},
+		"should leave scaling up from zero to the activator": {
+			replicas: 0,
+			metrics:  &fakeMetrics{count: 100, concurrency: 15},
+		},
+		"should not scale without metrics": {
+			replicas: 2,
+			metrics:  &fakeMetrics{err: fmt.Errorf("connection refused")},
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			r := &FunctionReconciler{
+				metrics: testData.metrics,
+				config: FunctionConfig{ScaleToZero: ScaleToZeroConfig{
+					IdleTimeout:       15 * time.Minute,
+					TargetConcurrency: 10,
+					MetricsWindow:     time.Minute,
+				}},
+			}
+			deployment := appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "fn-abcde", Namespace: "test"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &testData.replicas},
+			}
+			if !testData.activationTime.IsZero() {
+				deployment.Annotations = map[string]string{
+					serverlessv1alpha1.FunctionActivationTimeAnnotation: testData.activationTime.Format(time.RFC3339),
+				}
+			}
+
+			// when
+			replicas := r.calculateReplicas(context.TODO(), zap.New(), instance, deployment, now)
+
+			// then
+			if testData.expectedReplicas == nil {
+				g.Expect(replicas).To(gomega.BeNil())
+				return
+			}
+			g.Expect(replicas).NotTo(gomega.BeNil())
+			g.Expect(*replicas).To(gomega.Equal(*testData.expectedReplicas))
+		})
+	}
+}
+
+func TestFunctionReconciler_buildEndpoints(t *testing.T) {
+	zero := int32(0)
+	instance := &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test", UID: "fn-uid"},
+		Spec:       serverlessv1alpha1.FunctionSpec{MinReplicas: &zero},
+	}
+	activator := &corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{IP: "10.1.0.2", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "kyma-system", Name: "function-activator-abcde-2"}},
+				{IP: "10.1.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "kyma-system", Name: "function-activator-abcde-1"}},
+			},
+			Ports: []corev1.EndpointPort{{Name: "http", Port: 8012}},
+		}},
+	}
+	pods := []corev1.Pod{
+		fixPod("fn-1", "10.0.0.2", corev1.ConditionTrue),
+		fixPod("fn-2", "10.0.0.1", corev1.ConditionTrue),
+		fixPod("fn-3", "10.0.0.3", corev1.ConditionFalse),
+	}
+	hosts := []string{"fn.test", "fn.test.svc", "fn.test.svc.cluster.local", "fn.kyma.example.com"}
+
+	for testName, testData := range map[string]struct {
+		state scaleToZero
+
+		expectedIPs   []string
+		expectedPort  int32
+		expectedHosts string
+	}{
+		"should point to ready Pods": {
+			state:        scaleToZero{enabled: true, pods: pods, activator: activator, hosts: hosts},
+			expectedIPs:  []string{"10.0.0.1", "10.0.0.2"},
+			expectedPort: 8080,
+		},
+		"should point to activator without ready Pods": {
+			state:         scaleToZero{enabled: true, pods: pods[2:], activator: activator, hosts: hosts},
+			expectedIPs:   []string{"10.1.0.1", "10.1.0.2"},
+			expectedPort:  8012,
+			expectedHosts: "fn.test,fn.test.svc,fn.test.svc.cluster.local,fn.kyma.example.com",
+		},
+		"should point to activator before scaling to zero": {
+			state:         scaleToZero{enabled: true, pods: pods, activator: activator, replicas: &zero, hosts: hosts},
+			expectedIPs:   []string{"10.1.0.1", "10.1.0.2"},
+			expectedPort:  8012,
+			expectedHosts: "fn.test,fn.test.svc,fn.test.svc.cluster.local,fn.kyma.example.com",
+		},
+		"should have no addresses without activator": {
+			state: scaleToZero{enabled: true, hosts: hosts},
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			r := &FunctionReconciler{}
+
+			// when
+			endpoints := r.buildEndpoints(instance, testData.state)
+
+			// then
+			g.Expect(endpoints.GetName()).To(gomega.Equal("fn"))
+			g.Expect(endpoints.GetAnnotations()[serverlessv1alpha1.FunctionActivatorHostsAnnotation]).To(gomega.Equal(testData.expectedHosts))
+			if testData.expectedIPs == nil {
+				g.Expect(endpoints.Subsets).To(gomega.BeEmpty())
+				return
+			}
+			g.Expect(endpoints.Subsets).To(gomega.HaveLen(1))
+			var ips []string
+			for _, address := range endpoints.Subsets[0].Addresses {
+				ips = append(ips, address.IP)
+			}
+			g.Expect(ips).To(gomega.Equal(testData.expectedIPs))
+			if testData.expectedHosts != "" {
+				for _, address := range endpoints.Subsets[0].Addresses {
+					g.Expect(address.TargetRef).To(gomega.BeNil(), "the activator Pods mustn't be referenced")
+				}
+			}
+			g.Expect(endpoints.Subsets[0].Ports).To(gomega.Equal([]corev1.EndpointPort{{Name: "http", Port: testData.expectedPort, Protocol: corev1.ProtocolTCP}}))
+			g.Expect(r.isOnEndpointsChange(instance, scaleToZero{enabled: true, endpoints: &endpoints, pods: testData.state.pods, activator: activator, replicas: testData.state.replicas, hosts: hosts})).To(gomega.BeFalse())
+		})
+	}
+}
+
+func TestFunctionReconciler_buildService_scaleToZero(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	r := &FunctionReconciler{}
+	zero, one := int32(0), int32(1)
+	instance := &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "test"},
+		Spec:       serverlessv1alpha1.FunctionSpec{MinReplicas: &zero, MaxReplicas: &one},
+	}
+
+	// when
+	service := r.buildService(instance)
+
+	// then
+	g.Expect(service.Spec.Selector).To(gomega.BeNil())
+	g.Expect(isHorizontalPodAutoscalerEnabled(instance)).To(gomega.BeFalse())
+}
+
+func fixPod(name, ip string, ready corev1.ConditionStatus) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Status: corev1.PodStatus{
+			PodIP:      ip,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+		},
+	}
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
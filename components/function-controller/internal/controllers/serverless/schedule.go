@@ -0,0 +1,185 @@
+package serverless
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+// schedulePlan is the expected schedule status of the Function and whether it has to be invoked now
+type schedulePlan struct {
+	status *serverlessv1alpha1.ScheduleStatus
+	invoke bool
+}
+
+func (r *FunctionReconciler) isOnScheduleChange(instance *serverlessv1alpha1.Function) bool {
+	plan := r.planFunctionSchedule(instance, time.Now())
+
+	return plan.invoke || !equality.Semantic.DeepEqual(instance.Status.Schedule, plan.status)
+}
+
+func (r *FunctionReconciler) onScheduleChange(ctx context.Context, log logr.Logger, instance *serverlessv1alpha1.Function) (ctrl.Result, error) {
+	plan := r.planFunctionSchedule(instance, time.Now())
+
+	// the status is updated first, so the Function isn't invoked twice if the update conflicts
+	updated := instance.DeepCopy()
+	updated.Status.Schedule = plan.status
+	if err := r.client.Status().Update(ctx, updated); err != nil {
+		log.Error(err, "Cannot update schedule status")
+		return ctrl.Result{}, err
+	}
+
+	if plan.invoke {
+		scheduleTime := plan.status.LastScheduleTime.Time
+		r.getFunctionInvoker().Invoke(functionKey(instance), r.buildInvocation(instance, plan.status.ScheduleHash, scheduleTime),
+			instance.Spec.Schedule.ConcurrencyPolicyOrDefault() == serverlessv1alpha1.ScheduleConcurrencyPolicyReplace)
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "ScheduledInvocation", "Function invoked on schedule at %s", scheduleTime.UTC().Format(time.RFC3339))
+		return ctrl.Result{}, nil
+	}
+
+	current, status := instance.Status.Schedule, plan.status
+	if status != nil && status.LastCompletionTime != nil && (current == nil || current.LastCompletionTime == nil || !current.LastCompletionTime.Equal(status.LastCompletionTime)) {
+		eventType := corev1.EventTypeNormal
+		if status.LastResult == serverlessv1alpha1.ScheduleResultFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		r.recorder.Event(instance, eventType, "ScheduledInvocation"+string(status.LastResult), status.Message)
+	}
+	return ctrl.Result{}, nil
+}
+
+// buildInvocation addresses the Function through its Service, so the Functions scaled to zero are activated
+func (r *FunctionReconciler) buildInvocation(instance *serverlessv1alpha1.Function, scheduleHash string, scheduleTime time.Time) Invocation {
+	return Invocation{
+		URL:          fmt.Sprintf("http://%s", r.serviceHost(instance)),
+		EventID:      fmt.Sprintf("%s-%d", instance.GetUID(), scheduleTime.Unix()),
+		EventSource:  fmt.Sprintf("/namespaces/%s/functions/%s", instance.GetNamespace(), instance.GetName()),
+		Payload:      instance.Spec.Schedule.Payload,
+		ScheduleHash: scheduleHash,
+		ScheduleTime: scheduleTime,
+	}
+}
+
+func (r *FunctionReconciler) planFunctionSchedule(instance *serverlessv1alpha1.Function, now time.Time) schedulePlan {
+	invoker := r.getFunctionInvoker()
+	key := functionKey(instance)
+	running := r.getConditionStatus(instance.Status.Conditions, serverlessv1alpha1.ConditionRunning) == corev1.ConditionTrue
+	return planSchedule(instance, running, invoker.Active(key), invoker.LastResult(key), now)
+}
+
+// planSchedule returns the expected schedule status of the Function. The Function is due when its next schedule time
+// has passed, or right away for the one-shot schedule which hasn't run yet, and it's invoked once it's running. The
+// status is reset whenever the schedule changes, so the one-shot schedule runs again after its payload is changed.
+func planSchedule(instance *serverlessv1alpha1.Function, running bool, active int, result *InvocationResult, now time.Time) schedulePlan {
+	spec := instance.Spec.Schedule
+	if spec == nil {
+		return schedulePlan{}
+	}
+
+	hash := scheduleHash(spec)
+	status := &serverlessv1alpha1.ScheduleStatus{ScheduleHash: hash}
+	if current := instance.Status.Schedule; current != nil && current.ScheduleHash == hash {
+		status = current.DeepCopy()
+	}
+	status.Active = int32(active)
+
+	if result != nil && result.ScheduleHash == hash &&
+		(status.LastCompletionTime == nil || status.LastCompletionTime.Time.Before(result.CompletionTime)) {
+		status.LastCompletionTime = &metav1.Time{Time: result.CompletionTime}
+		if result.Err != nil {
+			status.LastResult = serverlessv1alpha1.ScheduleResultFailed
+			status.Message = fmt.Sprintf("Invocation scheduled at %s failed: %v", result.ScheduleTime.UTC().Format(time.RFC3339), result.Err)
+		} else {
+			status.LastResult = serverlessv1alpha1.ScheduleResultSucceeded
+			status.Message = fmt.Sprintf("Invocation scheduled at %s succeeded", result.ScheduleTime.UTC().Format(time.RFC3339))
+		}
+	}
+
+	schedule, err := spec.ParseCron()
+	if err != nil {
+		status.NextScheduleTime = nil
+		status.Message = fmt.Sprintf("Invalid schedule: %v", err)
+		return schedulePlan{status: status}
+	}
+	if spec.Suspend || (schedule == nil && status.LastScheduleTime != nil) {
+		status.NextScheduleTime = nil
+		return schedulePlan{status: status}
+	}
+
+	now = now.Truncate(time.Second)
+	if schedule != nil && status.NextScheduleTime == nil {
+		status.NextScheduleTime = &metav1.Time{Time: schedule.Next(now)}
+	}
+	if schedule != nil && status.NextScheduleTime.After(now) {
+		return schedulePlan{status: status}
+	}
+
+	if !running {
+		status.Message = "Invocation is waiting for the Function to be running"
+		return schedulePlan{status: status}
+	}
+
+	if schedule != nil {
+		status.NextScheduleTime = &metav1.Time{Time: schedule.Next(now)}
+	}
+	if active > 0 && spec.ConcurrencyPolicyOrDefault() == serverlessv1alpha1.ScheduleConcurrencyPolicyForbid {
+		status.Message = fmt.Sprintf("Invocation scheduled at %s skipped, the previous one is still running", now.UTC().Format(time.RFC3339))
+		return schedulePlan{status: status}
+	}
+
+	status.LastScheduleTime = &metav1.Time{Time: now}
+	status.Active = int32(active) + 1
+	if spec.ConcurrencyPolicyOrDefault() == serverlessv1alpha1.ScheduleConcurrencyPolicyReplace {
+		status.Active = 1
+	}
+	status.Message = ""
+	return schedulePlan{status: status, invoke: true}
+}
+
+// scheduleHash identifies the schedule, the concurrency policy and suspension don't reset its status
+func scheduleHash(spec *serverlessv1alpha1.Schedule) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s", spec.Cron, spec.TimeZone, spec.Payload)))
+	return fmt.Sprintf("%x", hash[:8])
+}
+
+// requeueSchedule requeues the Function at its next schedule time, or after RequeueDuration while its invocations are
+// running in case their completion notification is dropped
+func (r *FunctionReconciler) requeueSchedule(instance *serverlessv1alpha1.Function, result ctrl.Result) ctrl.Result {
+	status := instance.Status.Schedule
+	if instance.Spec.Schedule == nil || status == nil {
+		return result
+	}
+
+	var remaining time.Duration
+	if status.NextScheduleTime != nil {
+		remaining = time.Until(status.NextScheduleTime.Time)
+		if remaining < time.Second {
+			remaining = time.Second
+		}
+	}
+	if status.Active > 0 && (remaining == 0 || r.config.RequeueDuration < remaining) {
+		remaining = r.config.RequeueDuration
+	}
+	if remaining > 0 && (result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
+
+// getFunctionInvoker returns the invoker of the scheduled Functions, it's created on the first use to respect the
+// schedule configuration of the reconciler.
+func (r *FunctionReconciler) getFunctionInvoker() *FunctionInvoker {
+	r.functionInvokerOnce.Do(func() {
+		r.functionInvoker = NewFunctionInvoker(r.config.Schedule.InvocationTimeout, r.triggerReconcile)
+	})
+	return r.functionInvoker
+}
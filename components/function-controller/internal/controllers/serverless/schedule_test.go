@@ -0,0 +1,218 @@
+package serverless
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+func TestFunctionReconciler_planSchedule(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 30, 15, 0, time.UTC)
+	hourly := &serverlessv1alpha1.Schedule{Cron: "0 * * * *"}
+	oneShot := &serverlessv1alpha1.Schedule{Payload: `{"key":"value"}`}
+
+	for testName, testData := range map[string]struct {
+		schedule *serverlessv1alpha1.Schedule
+		status   *serverlessv1alpha1.ScheduleStatus
+		running  bool
+		active   int
+		result   *InvocationResult
+
+		expectedInvoke bool
+		expectedStatus *serverlessv1alpha1.ScheduleStatus
+	}{
+		"should not plan without schedule": {
+			status: &serverlessv1alpha1.ScheduleStatus{ScheduleHash: "old"},
+		},
+		"should set next schedule time of new schedule": {
+			schedule: hourly,
+			running:  true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)),
+			},
+		},
+		"should evaluate cron in time zone": {
+			schedule: &serverlessv1alpha1.Schedule{Cron: "0 6 * * *", TimeZone: "Europe/Berlin"},
+			running:  true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(&serverlessv1alpha1.Schedule{Cron: "0 6 * * *", TimeZone: "Europe/Berlin"}),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 2, 4, 0, 0, 0, time.UTC)),
+			},
+		},
+		"should invoke when due": {
+			schedule: hourly,
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			running:        true,
+			expectedInvoke: true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				LastScheduleTime: fixTime(now),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)),
+				Active:           1,
+			},
+		},
+		"should wait for running Function": {
+			schedule: hourly,
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+				Message:          "Invocation is waiting for the Function to be running",
+			},
+		},
+		"should skip when previous invocation is running": {
+			schedule: hourly,
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+				Active:           1,
+			},
+			running: true,
+			active:  1,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)),
+				Active:           1,
+				Message:          "Invocation scheduled at 2021-06-01T10:30:15Z skipped, the previous one is still running",
+			},
+		},
+		"should replace running invocation": {
+			schedule: &serverlessv1alpha1.Schedule{Cron: "0 * * * *", ConcurrencyPolicy: serverlessv1alpha1.ScheduleConcurrencyPolicyReplace},
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+				Active:           2,
+			},
+			running:        true,
+			active:         2,
+			expectedInvoke: true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				LastScheduleTime: fixTime(now),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)),
+				Active:           1,
+			},
+		},
+		"should not invoke suspended schedule": {
+			schedule: &serverlessv1alpha1.Schedule{Cron: "0 * * * *", Suspend: true},
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			running: true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash: scheduleHash(hourly),
+			},
+		},
+		"should invoke one-shot schedule once": {
+			schedule:       oneShot,
+			running:        true,
+			expectedInvoke: true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(oneShot),
+				LastScheduleTime: fixTime(now),
+				Active:           1,
+			},
+		},
+		"should record result of one-shot schedule": {
+			schedule: oneShot,
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(oneShot),
+				LastScheduleTime: fixTime(now.Add(-time.Minute)),
+				Active:           1,
+			},
+			running: true,
+			result: &InvocationResult{
+				ScheduleHash:   scheduleHash(oneShot),
+				ScheduleTime:   now.Add(-time.Minute),
+				CompletionTime: now.Add(-time.Second),
+				Err:            errors.New("Function responded with 500 Internal Server Error"),
+			},
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:       scheduleHash(oneShot),
+				LastScheduleTime:   fixTime(now.Add(-time.Minute)),
+				LastCompletionTime: fixTime(now.Add(-time.Second)),
+				LastResult:         serverlessv1alpha1.ScheduleResultFailed,
+				Message:            "Invocation scheduled at 2021-06-01T10:29:15Z failed: Function responded with 500 Internal Server Error",
+			},
+		},
+		"should reset status of changed schedule": {
+			schedule: oneShot,
+			status: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(hourly),
+				LastScheduleTime: fixTime(now.Add(-time.Minute)),
+				NextScheduleTime: fixTime(time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)),
+			},
+			running: true,
+			result: &InvocationResult{
+				ScheduleHash:   scheduleHash(hourly),
+				ScheduleTime:   now.Add(-time.Minute),
+				CompletionTime: now.Add(-time.Second),
+			},
+			expectedInvoke: true,
+			expectedStatus: &serverlessv1alpha1.ScheduleStatus{
+				ScheduleHash:     scheduleHash(oneShot),
+				LastScheduleTime: fixTime(now),
+				Active:           1,
+			},
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			instance := &serverlessv1alpha1.Function{
+				Spec:   serverlessv1alpha1.FunctionSpec{Schedule: testData.schedule},
+				Status: serverlessv1alpha1.FunctionStatus{Schedule: testData.status},
+			}
+
+			// when
+			plan := planSchedule(instance, testData.running, testData.active, testData.result, now)
+
+			// then
+			g.Expect(plan.invoke).To(gomega.Equal(testData.expectedInvoke))
+			g.Expect(equality.Semantic.DeepEqual(plan.status, testData.expectedStatus)).To(gomega.BeTrue(), "%+v", plan.status)
+		})
+	}
+}
+
+func TestFunctionReconciler_requeueSchedule(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	r := &FunctionReconciler{config: FunctionConfig{RequeueDuration: time.Minute}}
+	instance := &serverlessv1alpha1.Function{
+		Spec: serverlessv1alpha1.FunctionSpec{Schedule: &serverlessv1alpha1.Schedule{Cron: "@daily"}},
+		Status: serverlessv1alpha1.FunctionStatus{Schedule: &serverlessv1alpha1.ScheduleStatus{
+			NextScheduleTime: &metav1.Time{Time: time.Now().Add(time.Hour)},
+		}},
+	}
+
+	// when
+	scheduled := r.requeueSchedule(instance, ctrl.Result{RequeueAfter: 5 * time.Minute})
+	instance.Status.Schedule.Active = 1
+	active := r.requeueSchedule(instance, ctrl.Result{RequeueAfter: 5 * time.Minute})
+	instance.Status.Schedule.NextScheduleTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	due := r.requeueSchedule(instance, ctrl.Result{})
+
+	// then
+	g.Expect(scheduled.RequeueAfter).To(gomega.Equal(5 * time.Minute))
+	g.Expect(active.RequeueAfter).To(gomega.Equal(time.Minute))
+	g.Expect(due.RequeueAfter).To(gomega.Equal(time.Second))
+}
+
+func fixTime(t time.Time) *metav1.Time {
+	return &metav1.Time{Time: t}
+}
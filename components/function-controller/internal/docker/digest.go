@@ -0,0 +1,212 @@
+package docker
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	digestHeader          = "Docker-Content-Digest"
+	authenticateHeader    = "Www-Authenticate"
+	bearerChallengePrefix = "bearer "
+)
+
+// manifestMediaTypes are the manifest types accepted on lookup, the digest of the manifest depends on its type so the
+// types produced by Kaniko have to be accepted to get the digest of the pushed image
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// RegistryOptions defines the registry which is queried and the credentials of its user.
+type RegistryOptions struct {
+	// Address is the registry host with an optional port, e.g. registry.kyma.local:5000
+	Address  string
+	Username string
+	Password string
+	// Insecure allows plain HTTP if the registry can't be reached with HTTPS
+	Insecure bool
+	// SkipTLSVerify skips the verification of the registry certificate
+	SkipTLSVerify bool
+}
+
+// RegistryClient reads image manifests using the Docker Registry HTTP API V2. It supports the basic authentication and
+// the bearer token authentication used by most of the hosted registries.
+type RegistryClient struct {
+	timeout time.Duration
+}
+
+func NewRegistryClient(timeout time.Duration) *RegistryClient {
+	return &RegistryClient{timeout: timeout}
+}
+
+// ImageDigest returns the digest of the image manifest with the given tag, the digest is empty if the image doesn't
+// exist in the repository.
+func (c *RegistryClient) ImageDigest(ctx context.Context, options RegistryOptions, repository, tag string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	httpClient := c.httpClient(options)
+	basicAuth := func(req *http.Request) {
+		if options.Username != "" {
+			req.SetBasicAuth(options.Username, options.Password)
+		}
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", options.Address, repository, tag)
+	resp, err := c.headManifest(ctx, httpClient, manifestURL, basicAuth)
+	if err != nil && options.Insecure {
+		manifestURL = fmt.Sprintf("http://%s/v2/%s/manifests/%s", options.Address, repository, tag)
+		resp, err = c.headManifest(ctx, httpClient, manifestURL, basicAuth)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && isBearerChallenge(resp.Header.Get(authenticateHeader)) {
+		token, err := c.fetchToken(ctx, httpClient, resp.Header.Get(authenticateHeader), options)
+		if err != nil {
+			return "", err
+		}
+		resp, err = c.headManifest(ctx, httpClient, manifestURL, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		digest := resp.Header.Get(digestHeader)
+		if digest == "" {
+			return "", fmt.Errorf("registry didn't return the %s header for %s:%s", digestHeader, repository, tag)
+		}
+		return digest, nil
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unexpected status %d on lookup of %s:%s", resp.StatusCode, repository, tag)
+	}
+}
+
+func (c *RegistryClient) httpClient(options RegistryOptions) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.SkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{Transport: transport}
+}
+
+func (c *RegistryClient) headManifest(ctx context.Context, httpClient *http.Client, manifestURL string, authorize func(req *http.Request)) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	authorize(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// fetchToken requests the bearer token described by the challenge of the registry.
+func (c *RegistryClient) fetchToken(ctx context.Context, httpClient *http.Client, challenge string, options RegistryOptions) (string, error) {
+	params := parseChallenge(challenge)
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("realm not found in the registry challenge %q", challenge)
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid realm %q: %w", realm, err)
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if options.Username != "" {
+		req.SetBasicAuth(options.Username, options.Password)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d on token request", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("cannot decode token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("token not found in the token response")
+}
+
+func isBearerChallenge(challenge string) bool {
+	return strings.HasPrefix(strings.ToLower(challenge), bearerChallengePrefix)
+}
+
+// parseChallenge parses the parameters of the bearer challenge, e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	rest := strings.TrimSpace(challenge[len(bearerChallengePrefix):])
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+		params[key] = value
+		rest = strings.TrimLeft(rest, ", ")
+	}
+	return params
+}
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+const (
+	testDigest   = "sha256:4bc453b53cb3d914b45f4b250294236adba2c0e09ff6f03793949e7e39fd4cc1"
+	testUsername = "user"
+	testPassword = "pass"
+	testToken    = "token"
+)
+
+func TestRegistryClient_ImageDigest(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		tag      string
+		bearer   bool
+		username string
+
+		expectedDigest string
+		expectedErr    bool
+	}{
+		"should return digest with basic auth": {
+			tag:      "existing",
+			username: testUsername,
+
+			expectedDigest: testDigest,
+		},
+		"should return digest with bearer token": {
+			tag:      "existing",
+			bearer:   true,
+			username: testUsername,
+
+			expectedDigest: testDigest,
+		},
+		"should return empty digest when image doesn't exist": {
+			tag:      "missing",
+			bearer:   true,
+			username: testUsername,
+		},
+		"error on invalid credentials": {
+			tag:      "existing",
+			username: "wrong",
+
+			expectedErr: true,
+		},
+		"error on invalid credentials with bearer token": {
+			tag:      "existing",
+			bearer:   true,
+			username: "wrong",
+
+			expectedErr: true,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			server := httptest.NewServer(fixRegistry(g, testData.bearer))
+			defer server.Close()
+
+			client := NewRegistryClient(time.Second)
+
+			// when
+			digest, err := client.ImageDigest(context.TODO(), RegistryOptions{
+				Address:  strings.TrimPrefix(server.URL, "http://"),
+				Username: testData.username,
+				Password: testPassword,
+				Insecure: true,
+			}, "function-cache", testData.tag)
+
+			// then
+			if testData.expectedErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(digest).To(gomega.Equal(testData.expectedDigest))
+		})
+	}
+}
+
+func Test_parseChallenge(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io", scope="repository:function-cache:pull,push",error=invalid_token`)
+
+	g.Expect(params).To(gomega.Equal(map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:function-cache:pull,push",
+		"error":   "invalid_token",
+	}))
+}
+
+// fixRegistry returns a registry with the function-cache:existing image, it authenticates the requests with the basic
+// authentication or with the bearer token issued by its /token endpoint
+func fixRegistry(g *gomega.WithT, bearer bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != testUsername || password != testPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		g.Expect(r.URL.Query().Get("service")).To(gomega.Equal("test-registry"))
+		g.Expect(r.URL.Query().Get("scope")).To(gomega.Equal("repository:function-cache:pull"))
+		fmt.Fprintf(w, `{"token":%q}`, testToken)
+	})
+	mux.HandleFunc("/v2/function-cache/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.Method).To(gomega.Equal(http.MethodHead))
+		g.Expect(r.Header.Get("Accept")).To(gomega.ContainSubstring("application/vnd.docker.distribution.manifest.v2+json"))
+
+		if bearer {
+			if r.Header.Get("Authorization") != "Bearer "+testToken {
+				w.Header().Set(authenticateHeader, fmt.Sprintf(`Bearer realm="http://%s/token",service="test-registry",scope="repository:function-cache:pull"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		} else {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != testUsername || password != testPassword {
+				w.Header().Set(authenticateHeader, `Basic realm="test-registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		if strings.TrimPrefix(r.URL.Path, "/v2/function-cache/manifests/") != "existing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set(digestHeader, testDigest)
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
@@ -2,22 +2,58 @@ package git
 
 import (
 	gogit "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
 	"github.com/go-git/go-git/v5/plumbing/transport"
-	"github.com/go-git/go-git/v5/storage/memory"
+	gitclient "github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/pkg/errors"
 )
 
 type client struct{}
 
-func (o *client) ListRefs(repoUrl string, auth transport.AuthMethod) ([]*plumbing.Reference, error) {
-	r := gogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
-		URLs: []string{repoUrl},
-	})
+// ListRefs returns the references advertised by the remote repository, the annotated tags reference the tagged
+// commit instead of the tag object.
+func (o *client) ListRefs(repoUrl string, auth transport.AuthMethod) (refs []*plumbing.Reference, err error) {
+	endpoint, err := transport.NewEndpoint(repoUrl)
+	if err != nil {
+		return nil, err
+	}
+	c, err := gitclient.NewClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	session, err := c.NewUploadPackSession(endpoint, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := session.Close(); err == nil {
+			err = errors.Wrap(closeErr, "while closing upload pack session")
+		}
+	}()
 
-	return r.List(&gogit.ListOptions{
-		Auth: auth,
-	})
+	advRefs, err := session.AdvertisedReferences()
+	if err != nil {
+		return nil, err
+	}
+	return peeledReferences(advRefs)
+}
+
+// peeledReferences returns the advertised references with the annotated tags peeled to the tagged commits.
+func peeledReferences(advRefs *packp.AdvRefs) ([]*plumbing.Reference, error) {
+	allRefs, err := advRefs.AllReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*plumbing.Reference, 0, len(allRefs))
+	for _, ref := range allRefs {
+		if peeled, ok := advRefs.Peeled[ref.Name().String()]; ok {
+			ref = plumbing.NewHashReference(ref.Name(), peeled)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
 }
 
 func (o *client) PlainClone(path string, isBare bool, options *gogit.CloneOptions) (*gogit.Repository, error) {
@@ -0,0 +1,35 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/onsi/gomega"
+)
+
+func TestPeeledReferences(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	branchHash := plumbing.NewHash("649ed3e95dd9478785120a7572a71bdec2b0d660")
+	tagObjectHash := plumbing.NewHash("0d2e1ea1f0a8cb4a9d6e56f3fe2b21bbcaa9fd8d")
+	tagCommitHash := plumbing.NewHash("e6f9d5b7a0c0c2e1d0b77ba4a32d4b2ff3c0b9f1")
+	lightweightTagHash := plumbing.NewHash("3b18e512dba79e4c8300dd08aeb37f8e728b8dad")
+
+	advRefs := packp.NewAdvRefs()
+	advRefs.References["refs/heads/main"] = branchHash
+	advRefs.References["refs/tags/v1.0.0"] = tagObjectHash
+	advRefs.Peeled["refs/tags/v1.0.0"] = tagCommitHash
+	advRefs.References["refs/tags/v0.9.0"] = lightweightTagHash
+
+	// when
+	refs, err := peeledReferences(advRefs)
+
+	// then
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(refs).To(gomega.ConsistOf(
+		plumbing.NewHashReference("refs/heads/main", branchHash),
+		plumbing.NewHashReference("refs/tags/v1.0.0", tagCommitHash),
+		plumbing.NewHashReference("refs/tags/v0.9.0", lightweightTagHash),
+	))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -63,9 +64,45 @@ func (g *Git) LastCommit(options Options) (string, error) {
 			return elem.Hash().String(), nil
 		}
 	}
+
+	tagPattern := fmt.Sprintf(refsTagsFormat, options.Reference)
+	for _, elem := range refs {
+		if strings.EqualFold(elem.Name().String(), tagPattern) {
+			return elem.Hash().String(), nil
+		}
+	}
+
+	if tag := highestMatchingTag(refs, options.Reference); tag != nil {
+		return tag.Hash().String(), nil
+	}
 	return "", fmt.Errorf("reference not found: %s", options.Reference)
 }
 
+// highestMatchingTag returns the tag with the highest semantic version which satisfies the given reference if it is
+// a semantic version constraint, e.g. "~1.2" or ">= 1.0, < 2.0". Tags which are not semantic versions are ignored.
+func highestMatchingTag(refs []*plumbing.Reference, reference string) *plumbing.Reference {
+	constraint, err := semver.NewConstraint(reference)
+	if err != nil {
+		return nil
+	}
+
+	var highestTag *plumbing.Reference
+	var highestVersion *semver.Version
+	for _, elem := range refs {
+		if !elem.Name().IsTag() {
+			continue
+		}
+		version, err := semver.NewVersion(elem.Name().Short())
+		if err != nil || !constraint.Check(version) {
+			continue
+		}
+		if highestVersion == nil || version.GreaterThan(highestVersion) {
+			highestTag, highestVersion = elem, version
+		}
+	}
+	return highestTag
+}
+
 func (g *Git) Clone(path string, options Options) (string, error) {
 	authMethod, err := options.Auth.ToAuthMethod()
 	if err != nil {
@@ -90,8 +127,18 @@ func (g *Git) Clone(path string, options Options) (string, error) {
 		return "", err
 	}
 
+	// annotated tags reference a tag object which has to be resolved to the tagged commit
+	hash := plumbing.NewHash(commit)
+	if tag, err := repo.TagObject(hash); err == nil {
+		tagCommit, err := tag.Commit()
+		if err != nil {
+			return "", errors.Wrapf(err, "while resolving tag: %s, of repository: %s", tag.Name, options.URL)
+		}
+		hash = tagCommit.Hash
+	}
+
 	err = tree.Checkout(&gogit.CheckoutOptions{
-		Hash: plumbing.NewHash(commit),
+		Hash: hash,
 	})
 	if err != nil {
 		return "", errors.Wrapf(err, "while checkout repository: %s, to commit: %s", options.URL, options.Reference)
@@ -106,7 +106,52 @@ func TestLastCommit(t *testing.T) {
 				plumbing.NewHashReference("refs/tags/1.13.0", exampleHash),
 			},
 
-			expectedCommit: gomega.Equal(""),
+			expectedCommit: gomega.Equal(exampleHash.String()),
+			expectedErr:    gomega.BeNil(),
+		},
+		"ok when branch and tag have the same name": {
+			repoUrl:  "https://github.com/kyma-project/kyma",
+			repoRef:  "release-1.13",
+			repoAuth: nil,
+			mockErr:  nil,
+			mockRefs: []*plumbing.Reference{
+				plumbing.NewHashReference("refs/tags/release-1.13", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/heads/release-1.13", exampleHash),
+			},
+
+			expectedCommit: gomega.Equal(exampleHash.String()),
+			expectedErr:    gomega.BeNil(),
+		},
+		"ok when ref is semver constraint": {
+			repoUrl:  "https://github.com/kyma-project/kyma",
+			repoRef:  "~1.2",
+			repoAuth: nil,
+			mockErr:  nil,
+			mockRefs: []*plumbing.Reference{
+				plumbing.NewHashReference("refs/heads/main", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/tags/1.1.9", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/tags/v1.2.0", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/tags/v1.2.3", exampleHash),
+				plumbing.NewHashReference("refs/tags/v1.2.3^{}", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/tags/1.2.1", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/tags/1.3.0", plumbing.NewHash("")),
+				plumbing.NewHashReference("refs/tags/latest", plumbing.NewHash("")),
+			},
+
+			expectedCommit: gomega.Equal(exampleHash.String()),
+			expectedErr:    gomega.BeNil(),
+		},
+		"error when no tag satisfies semver constraint": {
+			repoUrl:  "https://github.com/kyma-project/kyma",
+			repoRef:  ">= 2.0",
+			repoAuth: nil,
+			mockErr:  nil,
+			mockRefs: []*plumbing.Reference{
+				plumbing.NewHashReference("refs/tags/1.2.3", exampleHash),
+				plumbing.NewHashReference("refs/tags/2.0.0-rc.1", exampleHash),
+			},
+
+			expectedCommit: gomega.HaveLen(0),
 			expectedErr:    gomega.HaveOccurred(),
 		},
 		"error on no permissions to repo": {
@@ -319,6 +364,41 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCloneAnnotatedTag(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	repoUrl := "https://github.com/kyma-project/kyma"
+
+	tmpDir, _ := ioutil.TempDir(os.TempDir(), TmpPrefix)
+	defer os.RemoveAll(tmpDir)
+
+	repository, _, firstCommit := fixTmpRepository(g, tmpDir, 3, false)
+	_, err := repository.CreateTag("v1.0.0", plumbing.NewHash(firstCommit), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "test", Email: "test@test.test", When: time.Now()},
+		Message: "release v1.0.0",
+	})
+	g.Expect(err).To(gomega.BeNil())
+
+	references := []*plumbing.Reference{}
+	remotes, _ := repository.References()
+	remotes.ForEach(func(reference *plumbing.Reference) error {
+		references = append(references, reference)
+		return nil
+	})
+
+	gitMock := new(automock.Client)
+	gitMock.On("PlainClone", tmpDir, false, &git.CloneOptions{URL: repoUrl}).Return(repository, nil)
+	gitMock.On("ListRefs", repoUrl, nil).Return(references, nil).Once()
+	operator := Git{client: gitMock}
+
+	// when
+	commit, err := operator.Clone(tmpDir, Options{URL: repoUrl, Reference: "^1.0"})
+
+	// then
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(commit).To(gomega.Equal(firstCommit))
+}
+
 func fixTmpRepository(g *gomega.WithT, dirPath string, commitsCount int, isBare bool) (*git.Repository, string, string) {
 	repo, initErr := git.PlainInit(dirPath, isBare)
 	g.Expect(initErr).To(gomega.BeNil())
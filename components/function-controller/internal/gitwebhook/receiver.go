@@ -0,0 +1,238 @@
+package gitwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+const (
+	// PathPrefix is the path prefix of the webhook endpoint, the full path is /webhooks/git/<namespace>/<gitrepository>
+	PathPrefix = "/webhooks/git/"
+	// SecretKey is the key of the shared secret in the Secret referenced by the GitRepository webhook
+	SecretKey = "secret"
+
+	githubEventHeader     = "X-GitHub-Event"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	githubSignaturePrefix = "sha256="
+	gitlabEventHeader     = "X-Gitlab-Event"
+	gitlabTokenHeader     = "X-Gitlab-Token"
+	giteaEventHeader      = "X-Gitea-Event"
+	giteaSignatureHeader  = "X-Gitea-Signature"
+
+	maxPayloadSize  = 25 << 20
+	shutdownTimeout = 10 * time.Second
+)
+
+var (
+	_ manager.Runnable               = &Receiver{}
+	_ manager.LeaderElectionRunnable = &Receiver{}
+)
+
+// Receiver receives the push webhooks of GitHub, GitLab and Gitea and triggers the reconciliation of all the Functions
+// which use the pushed GitRepository, so that new commits are picked up without waiting for the next repository poll.
+// The webhooks are authenticated with the shared secret referenced by the GitRepository.
+type Receiver struct {
+	client  client.Client
+	log     logr.Logger
+	address string
+}
+
+func NewReceiver(client client.Client, log logr.Logger, address string) *Receiver {
+	return &Receiver{
+		client:  client,
+		log:     log.WithName("git-webhook"),
+		address: address,
+	}
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface, the receiver runs on all the replicas
+// since the Service routes the webhooks to any of them.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements the manager.Runnable interface, it serves the webhooks until the stop channel is closed.
+func (r *Receiver) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, r)
+	server := &http.Server{Addr: r.address, Handler: mux}
+
+	errs := make(chan error, 1)
+	go func() {
+		r.log.Info("Starting git webhook receiver", "address", r.address)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	segments := strings.Split(strings.TrimPrefix(req.URL.Path, PathPrefix), "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		http.NotFound(w, req)
+		return
+	}
+	key := client.ObjectKey{Namespace: segments[0], Name: segments[1]}
+	log := r.log.WithValues("namespace", key.Namespace, "gitRepository", key.Name)
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "cannot read payload", http.StatusBadRequest)
+		return
+	}
+
+	secret, err := r.readSecret(req.Context(), key)
+	switch {
+	case apierrors.IsNotFound(err):
+		http.NotFound(w, req)
+		return
+	case err != nil:
+		log.Error(err, "Cannot read webhook secret")
+		http.Error(w, "cannot read webhook secret", http.StatusInternalServerError)
+		return
+	}
+
+	push, err := verify(req.Header, payload, secret)
+	if err != nil {
+		log.Info("Rejected webhook", "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if !push {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	count, err := r.triggerFunctions(req.Context(), key)
+	if err != nil {
+		log.Error(err, "Cannot trigger Functions")
+		http.Error(w, "cannot trigger functions", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info("Triggered Functions on push", "functions", count)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// readSecret returns the shared secret of the GitRepository, a NotFound error is returned if the GitRepository does not
+// exist or does not have a webhook configured.
+func (r *Receiver) readSecret(ctx context.Context, key client.ObjectKey) ([]byte, error) {
+	var gitRepository serverlessv1alpha1.GitRepository
+	if err := r.client.Get(ctx, key, &gitRepository); err != nil {
+		return nil, err
+	}
+	if gitRepository.Spec.Webhook == nil {
+		return nil, apierrors.NewNotFound(serverlessv1alpha1.Resource("gitrepositories"), key.Name)
+	}
+
+	var secret corev1.Secret
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: gitRepository.Spec.Webhook.SecretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("secret %s not found", gitRepository.Spec.Webhook.SecretName)
+		}
+		return nil, err
+	}
+	value, ok := secret.Data[SecretKey]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("secret %s does not contain the %s key", secret.Name, SecretKey)
+	}
+	return value, nil
+}
+
+// triggerFunctions annotates the Functions which use the GitRepository with the push time, the Function update
+// triggers their reconciliation on the leader replica.
+func (r *Receiver) triggerFunctions(ctx context.Context, key client.ObjectKey) (int, error) {
+	var functions serverlessv1alpha1.FunctionList
+	if err := r.client.List(ctx, &functions, client.InNamespace(key.Namespace)); err != nil {
+		return 0, err
+	}
+
+	pushTime := time.Now().UTC().Format(time.RFC3339Nano)
+	count := 0
+	for i := range functions.Items {
+		function := &functions.Items[i]
+		if function.Spec.Type != serverlessv1alpha1.SourceTypeGit || function.Spec.Source != key.Name {
+			continue
+		}
+		patch := client.MergeFrom(function.DeepCopy())
+		if function.Annotations == nil {
+			function.Annotations = map[string]string{}
+		}
+		function.Annotations[serverlessv1alpha1.FunctionSourcePushTimeAnnotation] = pushTime
+		if err := r.client.Patch(ctx, function, patch); err != nil && !apierrors.IsNotFound(err) {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// verify authenticates the webhook and returns whether it notifies about a push to the repository.
+func verify(header http.Header, payload, secret []byte) (bool, error) {
+	switch {
+	// Gitea sends the GitHub headers as well, so it has to be checked first
+	case header.Get(giteaEventHeader) != "":
+		if !validSignature(header.Get(giteaSignatureHeader), payload, secret) {
+			return false, fmt.Errorf("invalid %s header", giteaSignatureHeader)
+		}
+		return header.Get(giteaEventHeader) == "push", nil
+	case header.Get(githubEventHeader) != "":
+		signature := header.Get(githubSignatureHeader)
+		if !strings.HasPrefix(signature, githubSignaturePrefix) ||
+			!validSignature(strings.TrimPrefix(signature, githubSignaturePrefix), payload, secret) {
+			return false, fmt.Errorf("invalid %s header", githubSignatureHeader)
+		}
+		return header.Get(githubEventHeader) == "push", nil
+	case header.Get(gitlabEventHeader) != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get(gitlabTokenHeader)), secret) != 1 {
+			return false, fmt.Errorf("invalid %s header", gitlabTokenHeader)
+		}
+		event := header.Get(gitlabEventHeader)
+		return event == "Push Hook" || event == "Tag Push Hook", nil
+	default:
+		return false, fmt.Errorf("unsupported webhook, one of %s, %s or %s headers is required",
+			githubEventHeader, gitlabEventHeader, giteaEventHeader)
+	}
+}
+
+// validSignature checks the hex encoded HMAC-SHA256 signature of the payload.
+func validSignature(signature string, payload, secret []byte) bool {
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
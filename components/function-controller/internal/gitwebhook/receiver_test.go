@@ -0,0 +1,234 @@
+package gitwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	serverlessv1alpha1 "github.com/kyma-project/kyma/components/function-controller/pkg/apis/serverless/v1alpha1"
+)
+
+const (
+	testNamespace = "test"
+	testSecret    = "s3cr3t"
+	testPayload   = `{"ref":"refs/heads/main"}`
+)
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		method  string
+		path    string
+		headers map[string]string
+
+		expectedStatus    int
+		expectedFunctions []string
+	}{
+		"should trigger functions on GitHub push": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				githubEventHeader:     "push",
+				githubSignatureHeader: "sha256=" + sign(testPayload, testSecret),
+			},
+
+			expectedStatus:    http.StatusAccepted,
+			expectedFunctions: []string{"function-1", "function-2"},
+		},
+		"should trigger functions on Gitea push": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				giteaEventHeader:      "push",
+				githubEventHeader:     "push",
+				giteaSignatureHeader:  sign(testPayload, testSecret),
+				githubSignatureHeader: "sha256=invalid",
+			},
+
+			expectedStatus:    http.StatusAccepted,
+			expectedFunctions: []string{"function-1", "function-2"},
+		},
+		"should trigger functions on GitLab tag push": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				gitlabEventHeader: "Tag Push Hook",
+				gitlabTokenHeader: testSecret,
+			},
+
+			expectedStatus:    http.StatusAccepted,
+			expectedFunctions: []string{"function-1", "function-2"},
+		},
+		"should trigger only functions of the repository": {
+			path: "/webhooks/git/test/other-repo",
+			headers: map[string]string{
+				gitlabEventHeader: "Push Hook",
+				gitlabTokenHeader: testSecret,
+			},
+
+			expectedStatus:    http.StatusAccepted,
+			expectedFunctions: []string{"function-3"},
+		},
+		"should ignore GitHub ping": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				githubEventHeader:     "ping",
+				githubSignatureHeader: "sha256=" + sign(testPayload, testSecret),
+			},
+
+			expectedStatus: http.StatusOK,
+		},
+		"error on invalid GitHub signature": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				githubEventHeader:     "push",
+				githubSignatureHeader: "sha256=" + sign(testPayload, "wrong"),
+			},
+
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"error on missing Gitea signature": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				giteaEventHeader: "push",
+			},
+
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"error on invalid GitLab token": {
+			path: "/webhooks/git/test/repo",
+			headers: map[string]string{
+				gitlabEventHeader: "Push Hook",
+				gitlabTokenHeader: "wrong",
+			},
+
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"error on unsupported webhook": {
+			path:    "/webhooks/git/test/repo",
+			headers: map[string]string{},
+
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"error on repository without webhook": {
+			path: "/webhooks/git/test/no-webhook-repo",
+			headers: map[string]string{
+				gitlabEventHeader: "Push Hook",
+				gitlabTokenHeader: testSecret,
+			},
+
+			expectedStatus: http.StatusNotFound,
+		},
+		"error on unknown repository": {
+			path: "/webhooks/git/test/unknown",
+			headers: map[string]string{
+				gitlabEventHeader: "Push Hook",
+				gitlabTokenHeader: testSecret,
+			},
+
+			expectedStatus: http.StatusNotFound,
+		},
+		"error on invalid path": {
+			path: "/webhooks/git/test/repo/function",
+
+			expectedStatus: http.StatusNotFound,
+		},
+		"error on invalid method": {
+			method: http.MethodGet,
+			path:   "/webhooks/git/test/repo",
+
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+
+			k8sClient := fixClient(g)
+			receiver := NewReceiver(k8sClient, zap.New(), ":0")
+
+			method := testData.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			request := httptest.NewRequest(method, testData.path, strings.NewReader(testPayload))
+			for name, value := range testData.headers {
+				request.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+
+			// when
+			receiver.ServeHTTP(recorder, request)
+
+			// then
+			g.Expect(recorder.Code).To(gomega.Equal(testData.expectedStatus))
+			var functionList serverlessv1alpha1.FunctionList
+			g.Expect(k8sClient.List(context.Background(), &functionList)).To(gomega.Succeed())
+			var functions []string
+			for _, function := range functionList.Items {
+				if _, ok := function.Annotations[serverlessv1alpha1.FunctionSourcePushTimeAnnotation]; ok {
+					functions = append(functions, function.Name)
+				}
+			}
+			g.Expect(functions).To(gomega.ConsistOf(testData.expectedFunctions))
+		})
+	}
+}
+
+func fixClient(g *gomega.WithT) client.Client {
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(serverlessv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	return fake.NewFakeClientWithScheme(scheme,
+		fixGitRepository("repo", "webhook-secret"),
+		fixGitRepository("other-repo", "webhook-secret"),
+		fixGitRepository("no-webhook-repo", ""),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-secret", Namespace: testNamespace},
+			Data:       map[string][]byte{SecretKey: []byte(testSecret)},
+		},
+		fixFunction("function-1", testNamespace, "repo", serverlessv1alpha1.SourceTypeGit),
+		fixFunction("function-2", testNamespace, "repo", serverlessv1alpha1.SourceTypeGit),
+		fixFunction("function-3", testNamespace, "other-repo", serverlessv1alpha1.SourceTypeGit),
+		fixFunction("inline-function", testNamespace, "repo", ""),
+		fixFunction("function-4", "other-namespace", "repo", serverlessv1alpha1.SourceTypeGit),
+	)
+}
+
+func fixGitRepository(name, secretName string) *serverlessv1alpha1.GitRepository {
+	repository := &serverlessv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       serverlessv1alpha1.GitRepositorySpec{URL: "https://github.com/kyma-project/" + name},
+	}
+	if secretName != "" {
+		repository.Spec.Webhook = &serverlessv1alpha1.RepositoryWebhook{SecretName: secretName}
+	}
+	return repository
+}
+
+func fixFunction(name, namespace, source string, sourceType serverlessv1alpha1.SourceType) *serverlessv1alpha1.Function {
+	return &serverlessv1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: serverlessv1alpha1.FunctionSpec{
+			Source: source,
+			Type:   sourceType,
+		},
+	}
+}
+
+func sign(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// errorRateQuery returns the ratio of the requests to the workload which failed with 5xx, it's reported by the Istio
+// sidecars of the workload
+const errorRateQuery = `sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace=%q,destination_workload=%q,response_code=~"5.."}[%s]))` +
+	` / sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace=%q,destination_workload=%q}[%s]))`
+
+// requestConcurrencyQuery returns the average number of the requests processed by the workload at the same time, which
+// is the time spent on the requests per second
+const requestConcurrencyQuery = `sum(rate(istio_request_duration_milliseconds_sum{reporter="destination",destination_workload_namespace=%q,destination_workload=%q}[%s])) / 1000`
+
+const requestCountQuery = `sum(increase(istio_requests_total{reporter="destination",destination_workload_namespace=%q,destination_workload=%q}[%s]))`
+
+// PrometheusClient reads the Istio metrics of the Function revisions from Prometheus.
+type PrometheusClient struct {
+	address    string
+	httpClient *http.Client
+}
+
+func NewPrometheusClient(address string, timeout time.Duration) *PrometheusClient {
+	return &PrometheusClient{
+		address:    address,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value [2]interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// ErrorRate returns the percentage of the requests to the workload which failed with 5xx in the window, it's 0 if the
+// workload received no requests.
+func (c *PrometheusClient) ErrorRate(ctx context.Context, namespace, workload string, window time.Duration) (float64, error) {
+	rangeSelector := rangeSelector(window)
+	rate, err := c.query(ctx, fmt.Sprintf(errorRateQuery, namespace, workload, rangeSelector, namespace, workload, rangeSelector))
+	if err != nil {
+		return 0, err
+	}
+	return rate * 100, nil
+}
+
+// RequestConcurrency returns the average number of the requests processed by the workload at the same time in the
+// window.
+func (c *PrometheusClient) RequestConcurrency(ctx context.Context, namespace, workload string, window time.Duration) (float64, error) {
+	return c.query(ctx, fmt.Sprintf(requestConcurrencyQuery, namespace, workload, rangeSelector(window)))
+}
+
+// RequestCount returns the number of the requests to the workload in the window.
+func (c *PrometheusClient) RequestCount(ctx context.Context, namespace, workload string, window time.Duration) (float64, error) {
+	return c.query(ctx, fmt.Sprintf(requestCountQuery, namespace, workload, rangeSelector(window)))
+}
+
+func rangeSelector(window time.Duration) string {
+	return fmt.Sprintf("%ds", int64(window.Seconds()))
+}
+
+// query returns the value of the query which results in at most one sample, it's 0 if there is no sample or the value
+// is NaN
+func (c *PrometheusClient) query(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/query?%s", c.address, url.Values{"query": {query}}.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var body queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("cannot decode query response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Status != "success" {
+		return 0, fmt.Errorf("query failed with status %d: %s", resp.StatusCode, body.Error)
+	}
+	if body.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("unexpected result type %s", body.Data.ResultType)
+	}
+	if len(body.Data.Result) == 0 {
+		return 0, nil
+	}
+
+	value, ok := body.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected value %v", body.Data.Result[0].Value[1])
+	}
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q: %w", value, err)
+	}
+	// e.g. the error rate is NaN when there were no requests
+	if math.IsNaN(result) {
+		return 0, nil
+	}
+	return result, nil
+}
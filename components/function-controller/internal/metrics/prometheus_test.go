@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestPrometheusClient_ErrorRate(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		status   int
+		response string
+
+		expectedRate float64
+		expectedErr  bool
+	}{
+		"should return error rate": {
+			status:       http.StatusOK,
+			response:     `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1617184800,"0.125"]}]}}`,
+			expectedRate: 12.5,
+		},
+		"should return 0 without requests": {
+			status:   http.StatusOK,
+			response: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1617184800,"NaN"]}]}}`,
+		},
+		"should return 0 without metrics": {
+			status:   http.StatusOK,
+			response: `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		},
+		"error on failed query": {
+			status:      http.StatusBadRequest,
+			response:    `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			expectedErr: true,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Path).To(gomega.Equal("/api/v1/query"))
+				g.Expect(r.URL.Query().Get("query")).To(gomega.ContainSubstring(`destination_workload="fn-canary-abcde"`))
+				g.Expect(r.URL.Query().Get("query")).To(gomega.ContainSubstring(`[300s]`))
+				w.WriteHeader(testData.status)
+				fmt.Fprint(w, testData.response)
+			}))
+			defer server.Close()
+
+			client := NewPrometheusClient(server.URL, time.Second)
+
+			// when
+			rate, err := client.ErrorRate(context.TODO(), "test", "fn-canary-abcde", 5*time.Minute)
+
+			// then
+			if testData.expectedErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(rate).To(gomega.Equal(testData.expectedRate))
+		})
+	}
+}
+
+func TestPrometheusClient_RequestConcurrency(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Query().Get("query")).To(gomega.HavePrefix(`sum(rate(istio_request_duration_milliseconds_sum{`))
+		g.Expect(r.URL.Query().Get("query")).To(gomega.ContainSubstring(`destination_workload="fn-abcde"`))
+		g.Expect(r.URL.Query().Get("query")).To(gomega.ContainSubstring(`[60s]`))
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1617184800,"2.5"]}]}}`)
+	}))
+	defer server.Close()
+
+	client := NewPrometheusClient(server.URL, time.Second)
+
+	// when
+	concurrency, err := client.RequestConcurrency(context.TODO(), "test", "fn-abcde", time.Minute)
+
+	// then
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(concurrency).To(gomega.Equal(2.5))
+}
+
+func TestPrometheusClient_RequestCount(t *testing.T) {
+	// given
+	g := gomega.NewWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Query().Get("query")).To(gomega.HavePrefix(`sum(increase(istio_requests_total{`))
+		g.Expect(r.URL.Query().Get("query")).To(gomega.ContainSubstring(`[900s]`))
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer server.Close()
+
+	client := NewPrometheusClient(server.URL, time.Second)
+
+	// when
+	count, err := client.RequestCount(context.TODO(), "test", "fn-abcde", 15*time.Minute)
+
+	// then
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(count).To(gomega.BeZero())
+}
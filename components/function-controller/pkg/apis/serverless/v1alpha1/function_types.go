@@ -1,6 +1,12 @@
 package v1alpha1
 
 import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -11,9 +17,9 @@ const (
 	SourceTypeGit SourceType = "git"
 )
 
-// Runtime enumerates runtimes that are currently supported by Function Controller
-// It is a subset of RuntimeExtended
-// +kubebuilder:validation:Enum=nodejs12;nodejs14;python38;python39
+// Runtime is the name of the runtime, either shipped with the Function Controller or defined by the FunctionRuntime
+// The runtimes shipped with the Function Controller are a subset of RuntimeExtended
+// +kubebuilder:validation:MinLength=1
 type Runtime string
 
 const (
@@ -24,8 +30,8 @@ const (
 )
 
 // RuntimeExtended enumerates runtimes that are either currently supported or
-// no longer supported but there still might be "read-only" Functions using them
-// +kubebuilder:validation:Enum=nodejs12;nodejs14;python38;nodejs10;python39
+// no longer supported but there still might be "read-only" Functions using them,
+// it's any runtime defined by the FunctionRuntime as well
 type RuntimeExtended string
 
 const (
@@ -56,13 +62,29 @@ type FunctionSpec struct {
 	// Env defines an array of key value pairs need to be used as env variable for a function
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// SecretMounts mount the Secrets from the Function Namespace as files, the Function Pods are restarted when the
+	// Secrets change
+	// +optional
+	SecretMounts []SecretMount `json:"secretMounts,omitempty"`
+
+	// ConfigMapMounts mount the ConfigMaps from the Function Namespace as files, the Function Pods are restarted when
+	// the ConfigMaps change
+	// +optional
+	ConfigMapMounts []ConfigMapMount `json:"configMapMounts,omitempty"`
+
+	// Volumes are the writable volumes which live as long as the Function Pod
+	// +optional
+	Volumes []Volume `json:"volumes,omitempty"`
+
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
 	// +optional
 	BuildResources corev1.ResourceRequirements `json:"buildResources,omitempty"`
 
-	// +kubebuilder:validation:Minimum:=1
+	// MinReplicas set to 0 scales the Function to zero when it's idle, its requests are buffered by the activator until
+	// it's scaled up again
+	// +kubebuilder:validation:Minimum:=0
 	MinReplicas *int32 `json:"minReplicas,omitempty"`
 
 	// +kubebuilder:validation:Minimum:=1
@@ -75,6 +97,166 @@ type FunctionSpec struct {
 	Type SourceType `json:"type,omitempty"`
 
 	Repository `json:",inline,omitempty"`
+
+	// Rollout defines how the revision built after the change of the Function replaces the previous one, the previous
+	// revision is replaced at once if it's not set
+	// +optional
+	Rollout *Rollout `json:"rollout,omitempty"`
+
+	// Schedule invokes the Function with the CloudEvent on the cron schedule, or once if the cron isn't set
+	// +optional
+	Schedule *Schedule `json:"schedule,omitempty"`
+}
+
+type ScheduleConcurrencyPolicy string
+
+const (
+	// ScheduleConcurrencyPolicyAllow runs the invocations concurrently
+	ScheduleConcurrencyPolicyAllow ScheduleConcurrencyPolicy = "Allow"
+	// ScheduleConcurrencyPolicyForbid skips the invocation if the previous one is still running
+	ScheduleConcurrencyPolicyForbid ScheduleConcurrencyPolicy = "Forbid"
+	// ScheduleConcurrencyPolicyReplace cancels the running invocations and starts the new one
+	ScheduleConcurrencyPolicyReplace ScheduleConcurrencyPolicy = "Replace"
+)
+
+// Schedule defines when the Function is invoked with the CloudEvent of the ScheduledEventType
+type Schedule struct {
+	// Cron is the standard cron expression, e.g. "0 * * * *" or "@daily", the Function is invoked once when it's ready
+	// if it's not set
+	// +optional
+	Cron string `json:"cron,omitempty"`
+
+	// TimeZone is the IANA time zone of the cron expression, e.g. "Europe/Berlin", it's UTC if it's not set
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// ConcurrencyPolicy specifies what happens when the Function is invoked while the previous invocation is still
+	// running, it's Forbid if it's not set
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy ScheduleConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Payload is the data of the CloudEvent, it's sent as application/json if it's a valid JSON
+	// +optional
+	Payload string `json:"payload,omitempty"`
+
+	// Suspend stops the following invocations, the running ones aren't affected
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ConcurrencyPolicyOrDefault returns the concurrency policy, it's Forbid if it's not set
+func (in *Schedule) ConcurrencyPolicyOrDefault() ScheduleConcurrencyPolicy {
+	if in.ConcurrencyPolicy == "" {
+		return ScheduleConcurrencyPolicyForbid
+	}
+	return in.ConcurrencyPolicy
+}
+
+// ParseCron returns the cron schedule evaluated in the TimeZone, the schedule is nil if the cron isn't set
+func (in *Schedule) ParseCron() (cron.Schedule, error) {
+	if in.Cron == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(in.Cron, "TZ=") || strings.HasPrefix(in.Cron, "CRON_TZ=") {
+		return nil, errors.New("time zone must be set in the timeZone field")
+	}
+	location, err := time.LoadLocation(in.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := cron.ParseStandard(in.Cron)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+	return schedule, nil
+}
+
+// SecretMount mounts the keys of the Secret as files in the directory
+type SecretMount struct {
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// MountPath is the absolute path of the directory, if it's not set the Secret is injected as a service binding and
+	// mounted in $SERVICE_BINDING_ROOT/<secretName>
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Items select the keys of the Secret and their file paths, all keys are mounted if it's not set
+	// +optional
+	Items []corev1.KeyToPath `json:"items,omitempty"`
+}
+
+// ConfigMapMount mounts the keys of the ConfigMap as files in the directory
+type ConfigMapMount struct {
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// Items select the keys of the ConfigMap and their file paths, all keys are mounted if it's not set
+	// +optional
+	Items []corev1.KeyToPath `json:"items,omitempty"`
+}
+
+// Volume is the writable directory of the Function Pod
+type Volume struct {
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// EmptyDir is the empty directory created with the Pod, set its medium to Memory for the tmpfs
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir"`
+}
+
+// ServiceBindingRoot returns the directory of the Secrets mounted as service bindings
+func (spec *FunctionSpec) ServiceBindingRoot() string {
+	for _, env := range spec.Env {
+		if env.Name == ServiceBindingRootEnv && env.Value != "" {
+			return env.Value
+		}
+	}
+	return DefaultServiceBindingRoot
+}
+
+// ResolveMountPath returns the directory of the Secret, which is in the service binding root if it's not set
+func (in *SecretMount) ResolveMountPath(serviceBindingRoot string) string {
+	if in.MountPath != "" {
+		return in.MountPath
+	}
+	return path.Join(serviceBindingRoot, in.SecretName)
+}
+
+// TrafficWeight is the percentage of the Function traffic
+// +kubebuilder:validation:Minimum=0
+// +kubebuilder:validation:Maximum=100
+type TrafficWeight int32
+
+// Rollout defines the traffic split between the previous and the new revision of the Function
+type Rollout struct {
+	// Steps are the weights of the new revision in the following steps of the rollout, e.g. [10, 50]. The new revision
+	// is promoted after the last step or at the step with the weight of 100. Without StepDuration the rollout stays at
+	// the last step, so [0] deploys the new revision without traffic and [20] keeps the traffic split.
+	// +kubebuilder:validation:MinItems=1
+	Steps []TrafficWeight `json:"steps"`
+
+	// StepDuration is the time after which the rollout moves to the next step
+	// +optional
+	StepDuration *metav1.Duration `json:"stepDuration,omitempty"`
+
+	// MaxErrorRate is the highest percentage of the requests to the new revision failed with 5xx during the step, the
+	// rollout is aborted if it's exceeded
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxErrorRate *int32 `json:"maxErrorRate,omitempty"`
+
+	// Rollback sends all traffic to the previous revision and stops the rollout, which starts again when it's unset
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
 }
 
 const (
@@ -85,6 +267,30 @@ const (
 	FunctionResourceLabel                = "serverless.kyma-project.io/resource"
 	FunctionResourceLabelDeploymentValue = "deployment"
 	FunctionResourceLabelUserValue       = "user"
+	// FunctionResourceLabelCanaryDeploymentValue labels the Pods of the new revision during the rollout, so that they
+	// are selected neither by the stable Deployment nor by the Function Service
+	FunctionResourceLabelCanaryDeploymentValue = "canary-deployment"
+	FunctionRolloutRoleLabel                   = "serverless.kyma-project.io/rollout-role"
+	FunctionRolloutRoleCanaryValue             = "canary"
+	// ScheduledEventType is the type of the CloudEvent sent to the Function on its schedule
+	ScheduledEventType = "serverless.kyma-project.io.function.scheduled.v1"
+	// FunctionMountsChecksumAnnotation is set on the Function Pods to the checksum of the mounted Secrets and ConfigMaps,
+	// so their change restarts the Pods
+	FunctionMountsChecksumAnnotation = "serverless.kyma-project.io/mounts-checksum"
+	// ServiceBindingRootEnv is the directory of the Secrets injected as service bindings, see https://servicebinding.io
+	ServiceBindingRootEnv = "SERVICE_BINDING_ROOT"
+	// DefaultServiceBindingRoot is the value of ServiceBindingRootEnv unless it's set in the Function env
+	DefaultServiceBindingRoot = "/bindings"
+	// FunctionActivationTimeAnnotation is set on the Deployment by the activator when it scales the Function up from
+	// zero, the Function isn't scaled to zero again before its idle timeout passes
+	FunctionActivationTimeAnnotation = "serverless.kyma-project.io/activation-time"
+	// FunctionActivatorHostsAnnotation is set by the Function Controller on the Endpoints of the Function while they
+	// point to the activator, it lists the hosts of the Function Service and of the APIRules exposing it, so the
+	// activator only resolves the Functions which are routed to it
+	FunctionActivatorHostsAnnotation = "serverless.kyma-project.io/activator-hosts"
+	// FunctionSourcePushTimeAnnotation is set on the Function by the Git webhook receiver when its repository is pushed
+	// to, the Function update triggers its reconciliation on the leader replica
+	FunctionSourcePushTimeAnnotation = "serverless.kyma-project.io/source-push-time"
 )
 
 // ConditionType defines condition of function.
@@ -105,15 +311,18 @@ const (
 	ConditionReasonSourceUpdateFailed             ConditionReason = "SourceUpdateFailed"
 	ConditionReasonJobFailed                      ConditionReason = "JobFailed"
 	ConditionReasonJobCreated                     ConditionReason = "JobCreated"
+	ConditionReasonJobQueued                      ConditionReason = "JobQueued"
 	ConditionReasonJobUpdated                     ConditionReason = "JobUpdated"
 	ConditionReasonJobRunning                     ConditionReason = "JobRunning"
 	ConditionReasonJobsDeleted                    ConditionReason = "JobsDeleted"
 	ConditionReasonJobFinished                    ConditionReason = "JobFinished"
+	ConditionReasonJobSkipped                     ConditionReason = "JobSkipped"
 	ConditionReasonDeploymentCreated              ConditionReason = "DeploymentCreated"
 	ConditionReasonDeploymentUpdated              ConditionReason = "DeploymentUpdated"
 	ConditionReasonDeploymentFailed               ConditionReason = "DeploymentFailed"
 	ConditionReasonDeploymentWaiting              ConditionReason = "DeploymentWaiting"
 	ConditionReasonDeploymentReady                ConditionReason = "DeploymentReady"
+	ConditionReasonDeploymentScaled               ConditionReason = "DeploymentScaled"
 	ConditionReasonServiceCreated                 ConditionReason = "ServiceCreated"
 	ConditionReasonServiceUpdated                 ConditionReason = "ServiceUpdated"
 	ConditionReasonHorizontalPodAutoscalerCreated ConditionReason = "HorizontalPodAutoscalerCreated"
@@ -135,6 +344,74 @@ type FunctionStatus struct {
 	Commit     string          `json:"commit,omitempty"`
 	Source     string          `json:"source,omitempty"`
 	Runtime    RuntimeExtended `json:"runtime,omitempty"`
+	// Image is the image from the build cache, pinned to its digest, which is used instead of building the Function
+	Image string `json:"image,omitempty"`
+	// Rollout is the state of the rollout of the newest revision, it's set if the Function has the rollout defined
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Schedule is the state of the scheduled invocations, it's set if the Function has the schedule defined
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+}
+
+type ScheduleResult string
+
+const (
+	ScheduleResultSucceeded ScheduleResult = "Succeeded"
+	ScheduleResultFailed    ScheduleResult = "Failed"
+)
+
+type ScheduleStatus struct {
+	// ScheduleHash identifies the schedule of the status, the status is reset when the schedule changes
+	ScheduleHash string `json:"scheduleHash"`
+	// LastScheduleTime is the time of the last invocation
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// NextScheduleTime is the time of the next invocation, it's not set if the Function isn't invoked anymore
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// Active is the number of the running invocations
+	Active int32 `json:"active,omitempty"`
+	// LastCompletionTime is the time when the last finished invocation completed
+	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
+	// LastResult is the result of the last finished invocation
+	LastResult ScheduleResult `json:"lastResult,omitempty"`
+	// Message describes the last result or the skipped invocation
+	Message string `json:"message,omitempty"`
+}
+
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing means that the traffic is split between the previous and the new revision
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePromoting means that the previous revision is being replaced by the new one
+	RolloutPhasePromoting RolloutPhase = "Promoting"
+	// RolloutPhaseSucceeded means that the newest revision serves all traffic
+	RolloutPhaseSucceeded RolloutPhase = "Succeeded"
+	// RolloutPhaseAborted means that the new revision exceeded the error rate and the previous one serves all traffic
+	RolloutPhaseAborted RolloutPhase = "Aborted"
+	// RolloutPhaseRolledBack means that the previous revision serves all traffic on request
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+type RolloutStatus struct {
+	Phase RolloutPhase `json:"phase"`
+	// Image is the image of the new revision
+	Image string `json:"image"`
+	// StableImage is the image of the previous revision, which serves the traffic not sent to the new one
+	StableImage string `json:"stableImage"`
+	// Step is the index of the current step of the rollout
+	Step          int32        `json:"step"`
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	Message       string       `json:"message,omitempty"`
+	// Revisions are the running revisions of the Function with their share of the traffic
+	Revisions []RevisionStatus `json:"revisions,omitempty"`
+}
+
+type RevisionStatus struct {
+	// Deployment is the name of the Deployment which runs the revision
+	Deployment string `json:"deployment"`
+	Image      string `json:"image"`
+	// Weight is the percentage of the Function traffic sent to the revision
+	Weight int32 `json:"weight"`
+	Ready  bool  `json:"ready"`
 }
 
 type Repository struct {
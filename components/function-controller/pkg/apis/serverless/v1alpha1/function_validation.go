@@ -3,7 +3,9 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -19,6 +21,8 @@ const ValidationConfigKey = "validation-config"
 
 type MinFunctionReplicasValues struct {
 	MinValue int32 `envconfig:"default=1"`
+	// ScaleToZeroEnabled allows minReplicas set to 0, it requires the activator
+	ScaleToZeroEnabled bool `envconfig:"default=false"`
 }
 
 type MinFunctionResourcesValues struct {
@@ -54,20 +58,26 @@ func (fn *Function) performBasicValidation(ctx context.Context) *apis.FieldError
 		fn.Spec.validateReplicas(ctx),
 		fn.Spec.validateFunctionResources(ctx),
 		fn.Spec.validateBuildResources(ctx),
+		fn.Spec.validateRollout(),
+		fn.Spec.validateMounts(),
+		fn.Spec.validateSchedule(),
 	)
 }
 
 func (fn *Function) Validate(ctx context.Context) (errors *apis.FieldError) {
 	spec := fn.Spec
 
+	sanitizer, runtimeErr := spec.resolveDependencySanitizer(ctx)
 	if spec.Type == SourceTypeGit {
 		return fn.performBasicValidation(ctx).Also(
+			runtimeErr,
 			spec.validateRepository(),
 		)
 	}
 
 	return fn.performBasicValidation(ctx).Also(
-		spec.validateDeps(),
+		runtimeErr,
+		spec.validateDeps(sanitizer),
 	)
 }
 
@@ -93,8 +103,19 @@ func (spec *FunctionSpec) validateSource() *apis.FieldError {
 	return nil
 }
 
-func (spec *FunctionSpec) validateDeps() *apis.FieldError {
-	if err := ValidateDependencies(spec.Runtime, spec.Deps); err != nil {
+// resolveDependencySanitizer checks that the runtime exists, the FunctionRuntimes are resolved if the resolver is set in
+// the context
+func (spec *FunctionSpec) resolveDependencySanitizer(ctx context.Context) (DependencySanitizer, *apis.FieldError) {
+	resolver, _ := ctx.Value(RuntimeResolverKey).(RuntimeResolver)
+	sanitizer, err := ResolveDependencySanitizer(ctx, resolver, spec.Runtime)
+	if err != nil {
+		return "", apis.ErrInvalidValue(err.Error(), "spec.runtime")
+	}
+	return sanitizer, nil
+}
+
+func (spec *FunctionSpec) validateDeps(sanitizer DependencySanitizer) *apis.FieldError {
+	if err := sanitizer.ValidateDependencies(spec.Deps); err != nil {
 		return apis.ErrInvalidValue(err.Error(), "spec.deps")
 	}
 	return nil
@@ -172,10 +193,22 @@ func validateResources(resources corev1.ResourceRequirements, minMemory, minCpu
 }
 
 func (spec *FunctionSpec) validateReplicas(ctx context.Context) (apisError *apis.FieldError) {
-	minValue := ctx.Value(ValidationConfigKey).(ValidationConfig).Function.Replicas.MinValue
+	replicasConfig := ctx.Value(ValidationConfigKey).(ValidationConfig).Function.Replicas
+	minValue := replicasConfig.MinValue
 	maxReplicas := spec.MaxReplicas
 	minReplicas := spec.MinReplicas
 
+	if minReplicas != nil && *minReplicas == 0 && replicasConfig.ScaleToZeroEnabled {
+		if maxReplicas != nil && *maxReplicas < minValue {
+			apisError = apisError.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("maxReplicas(%d) is less than the smallest allowed value(%d)", *maxReplicas, minValue), "spec.maxReplicas"))
+		}
+		if spec.Rollout != nil {
+			apisError = apisError.Also(apis.ErrGeneric("Function scaled to zero cannot be rolled out", "spec.minReplicas", "spec.rollout"))
+		}
+		return apisError
+	}
+
 	if maxReplicas != nil && minReplicas != nil && *minReplicas > *maxReplicas {
 		apisError = apisError.Also(apis.ErrInvalidValue(
 			fmt.Sprintf("maxReplicas(%d) is less than minReplicas(%d)", *maxReplicas, *minReplicas), "spec.maxReplicas"))
@@ -192,6 +225,136 @@ func (spec *FunctionSpec) validateReplicas(ctx context.Context) (apisError *apis
 	return apisError
 }
 
+func (spec *FunctionSpec) validateRollout() (apisError *apis.FieldError) {
+	rollout := spec.Rollout
+	if rollout == nil {
+		return nil
+	}
+
+	if len(rollout.Steps) == 0 {
+		apisError = apisError.Also(apis.ErrMissingField("spec.rollout.steps"))
+	}
+	for i, step := range rollout.Steps {
+		if step < 0 || step > 100 {
+			apisError = apisError.Also(apis.ErrInvalidArrayValue(
+				fmt.Sprintf("weight(%d) must be between 0 and 100", step), "spec.rollout.steps", i))
+		}
+	}
+	if rollout.StepDuration != nil && rollout.StepDuration.Duration <= 0 {
+		apisError = apisError.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("stepDuration(%s) must be greater than 0", rollout.StepDuration.Duration), "spec.rollout.stepDuration"))
+	}
+	if rollout.MaxErrorRate != nil {
+		if *rollout.MaxErrorRate < 0 || *rollout.MaxErrorRate > 100 {
+			apisError = apisError.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("maxErrorRate(%d) must be between 0 and 100", *rollout.MaxErrorRate), "spec.rollout.maxErrorRate"))
+		}
+		if rollout.StepDuration == nil {
+			apisError = apisError.Also(apis.ErrMissingField("spec.rollout.stepDuration"))
+		}
+	}
+
+	return apisError
+}
+
+// reservedMountPaths are used by the Function Pod itself, /tmp is the writable directory of all Functions
+var reservedMountPaths = []string{"/", "/tmp"}
+
+// runtimeInstallPath is the KUBELESS_INSTALL_VOLUME of the runtime images, it holds the Function code and dependencies
+// which are hidden by the mounts at or under it
+const runtimeInstallPath = "/kubeless"
+
+func (spec *FunctionSpec) validateMounts() (apisError *apis.FieldError) {
+	mountPaths := map[string]string{}
+	validateMountPath := func(mountPath, fieldPath string) *apis.FieldError {
+		if !path.IsAbs(mountPath) || path.Clean(mountPath) != mountPath {
+			return apis.ErrInvalidValue(fmt.Sprintf("mountPath(%s) must be an absolute path", mountPath), fieldPath)
+		}
+		for _, reserved := range reservedMountPaths {
+			if mountPath == reserved {
+				return apis.ErrInvalidValue(fmt.Sprintf("mountPath(%s) is reserved for the serverless domain", mountPath), fieldPath)
+			}
+		}
+		if mountPath == runtimeInstallPath || strings.HasPrefix(mountPath, runtimeInstallPath+"/") {
+			return apis.ErrInvalidValue(fmt.Sprintf("mountPath(%s) would hide the Function code in %s", mountPath, runtimeInstallPath), fieldPath)
+		}
+		if other, ok := mountPaths[mountPath]; ok {
+			return apis.ErrInvalidValue(fmt.Sprintf("mountPath(%s) is already used by %s", mountPath, other), fieldPath)
+		}
+		mountPaths[mountPath] = fieldPath
+		return nil
+	}
+
+	serviceBindingRoot := spec.ServiceBindingRoot()
+	if !path.IsAbs(serviceBindingRoot) {
+		apisError = apisError.Also(apis.ErrInvalidKeyName(ServiceBindingRootEnv, "spec.env", "service binding root must be an absolute path"))
+	}
+	for i, mount := range spec.SecretMounts {
+		fieldPath := fmt.Sprintf("spec.secretMounts[%d]", i)
+		apisError = apisError.Also(
+			validateMountName(mount.SecretName, fieldPath+".secretName"),
+			validateMountPath(mount.ResolveMountPath(serviceBindingRoot), fieldPath+".mountPath"),
+			validateMountItems(mount.Items, fieldPath+".items"),
+		)
+	}
+	for i, mount := range spec.ConfigMapMounts {
+		fieldPath := fmt.Sprintf("spec.configMapMounts[%d]", i)
+		apisError = apisError.Also(
+			validateMountName(mount.ConfigMapName, fieldPath+".configMapName"),
+			validateMountPath(mount.MountPath, fieldPath+".mountPath"),
+			validateMountItems(mount.Items, fieldPath+".items"),
+		)
+	}
+	for i, volume := range spec.Volumes {
+		fieldPath := fmt.Sprintf("spec.volumes[%d]", i)
+		apisError = apisError.Also(validateMountPath(volume.MountPath, fieldPath+".mountPath"))
+		if volume.EmptyDir == nil {
+			apisError = apisError.Also(apis.ErrMissingField(fieldPath + ".emptyDir"))
+		}
+	}
+	return apisError
+}
+
+func (spec *FunctionSpec) validateSchedule() (apisError *apis.FieldError) {
+	schedule := spec.Schedule
+	if schedule == nil {
+		return nil
+	}
+
+	if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+		apisError = apisError.Also(apis.ErrInvalidValue(fmt.Sprintf("timeZone(%s) is unknown", schedule.TimeZone), "spec.schedule.timeZone"))
+	} else if _, err := schedule.ParseCron(); err != nil {
+		apisError = apisError.Also(apis.ErrInvalidValue(fmt.Sprintf("cron(%s) is invalid: %s", schedule.Cron, err), "spec.schedule.cron"))
+	}
+	switch schedule.ConcurrencyPolicy {
+	case "", ScheduleConcurrencyPolicyAllow, ScheduleConcurrencyPolicyForbid, ScheduleConcurrencyPolicyReplace:
+	default:
+		apisError = apisError.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("concurrencyPolicy(%s) must be one of Allow, Forbid, Replace", schedule.ConcurrencyPolicy), "spec.schedule.concurrencyPolicy"))
+	}
+	return apisError
+}
+
+func validateMountName(name, fieldPath string) *apis.FieldError {
+	if errs := utilvalidation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return apis.ErrInvalidValue(fmt.Sprintf("name(%s) is invalid: %s", name, strings.Join(errs, ", ")), fieldPath)
+	}
+	return nil
+}
+
+func validateMountItems(items []corev1.KeyToPath, fieldPath string) (apisError *apis.FieldError) {
+	for i, item := range items {
+		if item.Key == "" {
+			apisError = apisError.Also(apis.ErrMissingField(fmt.Sprintf("%s[%d].key", fieldPath, i)))
+		}
+		if item.Path == "" || path.IsAbs(item.Path) || strings.HasPrefix(path.Clean(item.Path), "..") {
+			apisError = apisError.Also(apis.ErrInvalidArrayValue(
+				fmt.Sprintf("path(%s) must be a relative path inside the mountPath", item.Path), fieldPath, i))
+		}
+	}
+	return apisError
+}
+
 func (spec *FunctionSpec) validateLabels() (apisError *apis.FieldError) {
 	labels := spec.Labels
 	fieldPath := field.NewPath("spec.labels")
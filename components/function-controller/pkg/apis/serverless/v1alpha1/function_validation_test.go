@@ -217,6 +217,26 @@ func TestFunctionSpec_validateResources(t *testing.T) {
 				),
 			),
 		},
+		"Should return error on rollout validation": {
+			givenFunc: Function{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: FunctionSpec{
+					Source:      "test-source",
+					Runtime:     Nodejs12,
+					MinReplicas: &one,
+					MaxReplicas: &one,
+					Rollout: &Rollout{
+						Steps:        []TrafficWeight{10, 120},
+						MaxErrorRate: &one,
+					},
+				},
+			},
+			expectedError: gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.And(
+				gomega.ContainSubstring("spec.rollout.steps[1]"),
+				gomega.ContainSubstring("spec.rollout.stepDuration"),
+			),
+		},
 		"Should return error on function resources validation": {
 			givenFunc: Function{
 				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
@@ -410,3 +430,230 @@ func TestFunctionSpec_validateResources(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionSpec_validateReplicas(t *testing.T) {
+	zero := int32(0)
+	one := int32(1)
+
+	for testName, testData := range map[string]struct {
+		givenSpec          FunctionSpec
+		scaleToZeroEnabled bool
+
+		expectedError          gomega.OmegaMatcher
+		specifiedExpectedError gomega.OmegaMatcher
+	}{
+		"should allow scaling to zero": {
+			givenSpec:          FunctionSpec{MinReplicas: &zero, MaxReplicas: &one},
+			scaleToZeroEnabled: true,
+			expectedError:      gomega.BeNil(),
+		},
+		"should reject scaling to zero if it's disabled": {
+			givenSpec:              FunctionSpec{MinReplicas: &zero, MaxReplicas: &one},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.minReplicas"),
+		},
+		"should reject scaling to zero without replicas": {
+			givenSpec:              FunctionSpec{MinReplicas: &zero, MaxReplicas: &zero},
+			scaleToZeroEnabled:     true,
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.maxReplicas"),
+		},
+		"should reject scaling to zero with rollout": {
+			givenSpec: FunctionSpec{MinReplicas: &zero, MaxReplicas: &one, Rollout: &Rollout{
+				Steps: []TrafficWeight{10},
+			}},
+			scaleToZeroEnabled:     true,
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.rollout"),
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+			config := &ValidationConfig{}
+			err := envconfig.Init(config)
+			g.Expect(err).ShouldNot(gomega.HaveOccurred())
+			config.Function.Replicas.ScaleToZeroEnabled = testData.scaleToZeroEnabled
+
+			ctx := context.WithValue(context.Background(), ValidationConfigKey, *config)
+
+			// when
+			errs := testData.givenSpec.validateReplicas(ctx)
+
+			// then
+			g.Expect(errs).To(testData.expectedError)
+			if testData.specifiedExpectedError != nil {
+				g.Expect(errs.Error()).To(testData.specifiedExpectedError)
+			}
+		})
+	}
+}
+
+func TestFunctionSpec_validateMounts(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		givenSpec FunctionSpec
+
+		expectedError          gomega.OmegaMatcher
+		specifiedExpectedError gomega.OmegaMatcher
+	}{
+		"should allow mounts": {
+			givenSpec: FunctionSpec{
+				SecretMounts: []SecretMount{
+					{SecretName: "tls", MountPath: "/etc/tls", Items: []corev1.KeyToPath{{Key: "tls.crt", Path: "certs/tls.crt"}}},
+					{SecretName: "db"},
+				},
+				ConfigMapMounts: []ConfigMapMount{{ConfigMapName: "config", MountPath: "/etc/config"}},
+				Volumes:         []Volume{{MountPath: "/cache", EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}},
+			},
+			expectedError: gomega.BeNil(),
+		},
+		"should reject relative mountPath": {
+			givenSpec: FunctionSpec{
+				ConfigMapMounts: []ConfigMapMount{{ConfigMapName: "config", MountPath: "etc/config"}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.configMapMounts[0].mountPath"),
+		},
+		"should reject reserved mountPath": {
+			givenSpec: FunctionSpec{
+				Volumes: []Volume{{MountPath: "/tmp", EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.volumes[0].mountPath"),
+		},
+		"should reject mountPath of the Function code": {
+			givenSpec: FunctionSpec{
+				ConfigMapMounts: []ConfigMapMount{{ConfigMapName: "config", MountPath: "/kubeless"}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.configMapMounts[0].mountPath"),
+		},
+		"should reject mountPath nested in the Function code": {
+			givenSpec: FunctionSpec{
+				Volumes: []Volume{{MountPath: "/kubeless/node_modules", EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.volumes[0].mountPath"),
+		},
+		"should accept mountPath with the Function code prefix": {
+			givenSpec: FunctionSpec{
+				Volumes: []Volume{{MountPath: "/kubeless-cache", EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			expectedError: gomega.BeNil(),
+		},
+		"should reject duplicated service binding": {
+			givenSpec: FunctionSpec{
+				SecretMounts: []SecretMount{{SecretName: "db"}, {SecretName: "other", MountPath: "/bindings/db"}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.secretMounts[1].mountPath"),
+		},
+		"should reject relative service binding root": {
+			givenSpec: FunctionSpec{
+				Env:          []corev1.EnvVar{{Name: ServiceBindingRootEnv, Value: "bindings"}},
+				SecretMounts: []SecretMount{{SecretName: "db"}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring(ServiceBindingRootEnv),
+		},
+		"should reject invalid Secret name": {
+			givenSpec: FunctionSpec{
+				SecretMounts: []SecretMount{{SecretName: "Invalid_Name", MountPath: "/etc/secret"}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.secretMounts[0].secretName"),
+		},
+		"should reject item outside of mountPath": {
+			givenSpec: FunctionSpec{
+				ConfigMapMounts: []ConfigMapMount{{ConfigMapName: "config", MountPath: "/etc/config", Items: []corev1.KeyToPath{{Key: "key", Path: "../key"}}}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.configMapMounts[0].items[0]"),
+		},
+		"should reject volume without emptyDir": {
+			givenSpec: FunctionSpec{
+				Volumes: []Volume{{MountPath: "/cache"}},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.volumes[0].emptyDir"),
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+
+			// when
+			errs := testData.givenSpec.validateMounts()
+
+			// then
+			g.Expect(errs).To(testData.expectedError)
+			if testData.specifiedExpectedError != nil {
+				g.Expect(errs.Error()).To(testData.specifiedExpectedError)
+			}
+		})
+	}
+}
+
+func TestFunctionSpec_validateSchedule(t *testing.T) {
+	for testName, testData := range map[string]struct {
+		givenSpec FunctionSpec
+
+		expectedError          gomega.OmegaMatcher
+		specifiedExpectedError gomega.OmegaMatcher
+	}{
+		"should allow cron schedule": {
+			givenSpec: FunctionSpec{
+				Schedule: &Schedule{Cron: "0 6 * * 1-5", TimeZone: "Europe/Berlin", ConcurrencyPolicy: ScheduleConcurrencyPolicyReplace},
+			},
+			expectedError: gomega.BeNil(),
+		},
+		"should allow one-shot schedule": {
+			givenSpec: FunctionSpec{
+				Schedule: &Schedule{Payload: `{"key":"value"}`},
+			},
+			expectedError: gomega.BeNil(),
+		},
+		"should reject invalid cron": {
+			givenSpec: FunctionSpec{
+				Schedule: &Schedule{Cron: "every minute"},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.schedule.cron"),
+		},
+		"should reject time zone in cron": {
+			givenSpec: FunctionSpec{
+				Schedule: &Schedule{Cron: "CRON_TZ=Europe/Berlin 0 6 * * *"},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.schedule.cron"),
+		},
+		"should reject unknown time zone": {
+			givenSpec: FunctionSpec{
+				Schedule: &Schedule{Cron: "@daily", TimeZone: "Mars/Olympus"},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.schedule.timeZone"),
+		},
+		"should reject unknown concurrency policy": {
+			givenSpec: FunctionSpec{
+				Schedule: &Schedule{Cron: "@hourly", ConcurrencyPolicy: "Queue"},
+			},
+			expectedError:          gomega.HaveOccurred(),
+			specifiedExpectedError: gomega.ContainSubstring("spec.schedule.concurrencyPolicy"),
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			// given
+			g := gomega.NewWithT(t)
+
+			// when
+			errs := testData.givenSpec.validateSchedule()
+
+			// then
+			g.Expect(errs).To(testData.expectedError)
+			if testData.specifiedExpectedError != nil {
+				g.Expect(errs.Error()).To(testData.specifiedExpectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DependencySanitizer enumerates the ways of preparing Function dependencies for the build
+// +kubebuilder:validation:Enum=JSONObject;None
+type DependencySanitizer string
+
+const (
+	// DependencySanitizerJSONObject requires the dependencies to be a JSON object and replaces empty dependencies with {}
+	DependencySanitizerJSONObject DependencySanitizer = "JSONObject"
+	// DependencySanitizerNone uses the dependencies as they are
+	DependencySanitizerNone DependencySanitizer = "None"
+)
+
+// FunctionRuntimeSpec defines how the Functions of the runtime are built and run
+type FunctionRuntimeSpec struct {
+	// +kubebuilder:validation:Required
+	// DependencyFile is the name of the file with Function dependencies in the build context, e.g. package.json
+	DependencyFile string `json:"dependencyFile"`
+
+	// +kubebuilder:validation:Required
+	// FunctionFile is the name of the file with Function source in the build context, e.g. handler.js
+	FunctionFile string `json:"functionFile"`
+
+	// +kubebuilder:validation:Required
+	// DockerfileConfigMapName is the name of the ConfigMap with the Dockerfile under the `Dockerfile` key. The ConfigMap
+	// is labeled with serverless.kyma-project.io/config=runtime and serverless.kyma-project.io/runtime=<runtime name>
+	// so that it is propagated to the Function Namespaces.
+	DockerfileConfigMapName string `json:"dockerfileConfigMapName"`
+
+	// Env defines the environment variables of the runtime, they are set in the Function containers
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// DependencySanitizer defines how the dependencies are prepared for the build, it is set to None unless specified
+	// otherwise
+	// +optional
+	DependencySanitizer DependencySanitizer `json:"dependencySanitizer,omitempty"`
+
+	// PackageRegistryConfigFile is the name of the package manager configuration file, e.g. .npmrc, which is mounted in
+	// the build context from the package registry configuration Secret
+	// +optional
+	PackageRegistryConfigFile string `json:"packageRegistryConfigFile,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// FunctionRuntime is the Schema for the functionruntimes API, its name is the runtime set in the Function.
+// The runtimes shipped with the Function Controller can be replaced by the FunctionRuntime with the same name.
+// +kubebuilder:printcolumn:name="Dockerfile",type=string,JSONPath=`.spec.dockerfileConfigMapName`
+// +kubebuilder:printcolumn:name="Dependencies",type=string,JSONPath=`.spec.dependencyFile`
+type FunctionRuntime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FunctionRuntimeSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FunctionRuntimeList contains a list of FunctionRuntime
+type FunctionRuntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FunctionRuntime `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FunctionRuntime{}, &FunctionRuntimeList{})
+}
@@ -15,6 +15,18 @@ type GitRepositorySpec struct {
 	// Auth is the optional definition of authentication that should be used for repository operations
 	// +optional
 	Auth *RepositoryAuth `json:"auth,omitempty"`
+
+	// Webhook is the optional definition of the push webhook which triggers the update of Functions using the repository
+	// +optional
+	Webhook *RepositoryWebhook `json:"webhook,omitempty"`
+}
+
+// RepositoryWebhook defines the shared secret used to authenticate push webhooks of the repository
+type RepositoryWebhook struct {
+	// +kubebuilder:validation:Required
+
+	// SecretName is the name of Kubernetes Secret containing the shared secret under the `secret` key
+	SecretName string `json:"secretName"`
 }
 
 // RepositoryAuth defines authentication method used for repository operations
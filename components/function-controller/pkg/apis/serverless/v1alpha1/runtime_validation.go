@@ -1,20 +1,63 @@
 package v1alpha1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 )
 
+const RuntimeResolverKey = "runtime-resolver"
+
+// RuntimeResolver returns the FunctionRuntime with the given name, it returns nil if there is no such FunctionRuntime
+type RuntimeResolver interface {
+	GetFunctionRuntime(ctx context.Context, runtime Runtime) (*FunctionRuntime, error)
+}
+
+// builtinDependencySanitizers are the dependency sanitizers of the runtimes shipped with the Function Controller
+var builtinDependencySanitizers = map[Runtime]DependencySanitizer{
+	Nodejs12: DependencySanitizerJSONObject,
+	Nodejs14: DependencySanitizerJSONObject,
+	Python38: DependencySanitizerNone,
+	Python39: DependencySanitizerNone,
+}
+
+// ValidateDependencies validates the dependencies of the runtime shipped with the Function Controller
 func ValidateDependencies(runtime Runtime, dependencies string) error {
-	switch runtime {
-	case Nodejs12, Nodejs14:
+	sanitizer, ok := builtinDependencySanitizers[runtime]
+	if !ok {
+		return fmt.Errorf("cannot find runtime: %s", runtime)
+	}
+	return sanitizer.ValidateDependencies(dependencies)
+}
+
+// ResolveDependencySanitizer returns the dependency sanitizer of the runtime, the FunctionRuntime takes precedence over
+// the runtime shipped with the Function Controller. The FunctionRuntime is looked up only if the resolver is given.
+func ResolveDependencySanitizer(ctx context.Context, resolver RuntimeResolver, runtime Runtime) (DependencySanitizer, error) {
+	if resolver != nil {
+		functionRuntime, err := resolver.GetFunctionRuntime(ctx, runtime)
+		if err != nil {
+			return "", fmt.Errorf("cannot get runtime %s: %w", runtime, err)
+		}
+		if functionRuntime != nil {
+			return functionRuntime.Spec.DependencySanitizer, nil
+		}
+	}
+
+	sanitizer, ok := builtinDependencySanitizers[runtime]
+	if !ok {
+		return "", fmt.Errorf("cannot find runtime: %s", runtime)
+	}
+	return sanitizer, nil
+}
+
+func (s DependencySanitizer) ValidateDependencies(dependencies string) error {
+	switch s {
+	case DependencySanitizerJSONObject:
 		return validateNodeJSDependencies(dependencies)
-	case Python38, Python39:
+	default:
 		return nil
 	}
-
-	return fmt.Errorf("cannot find runtime: %s", runtime)
 }
 
 func validateNodeJSDependencies(dependencies string) error {
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeRuntimeResolver map[Runtime]*FunctionRuntime
+
+func (f fakeRuntimeResolver) GetFunctionRuntime(_ context.Context, runtime Runtime) (*FunctionRuntime, error) {
+	if runtime == "broken" {
+		return nil, errors.New("test error")
+	}
+	return f[runtime], nil
+}
+
+func TestResolveDependencySanitizer(t *testing.T) {
+	resolver := fakeRuntimeResolver{
+		"go116": &FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: "go116"},
+			Spec:       FunctionRuntimeSpec{DependencySanitizer: DependencySanitizerNone},
+		},
+		Python39: &FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: string(Python39)},
+			Spec:       FunctionRuntimeSpec{DependencySanitizer: DependencySanitizerJSONObject},
+		},
+	}
+
+	for testName, testData := range map[string]struct {
+		resolver RuntimeResolver
+		runtime  Runtime
+
+		expectedSanitizer DependencySanitizer
+		expectedErr       bool
+	}{
+		"should resolve FunctionRuntime": {
+			resolver:          resolver,
+			runtime:           "go116",
+			expectedSanitizer: DependencySanitizerNone,
+		},
+		"should prefer FunctionRuntime over the builtin runtime": {
+			resolver:          resolver,
+			runtime:           Python39,
+			expectedSanitizer: DependencySanitizerJSONObject,
+		},
+		"should fall back to the builtin runtime": {
+			resolver:          resolver,
+			runtime:           Nodejs14,
+			expectedSanitizer: DependencySanitizerJSONObject,
+		},
+		"should resolve the builtin runtime without resolver": {
+			runtime:           Python38,
+			expectedSanitizer: DependencySanitizerNone,
+		},
+		"error on unknown runtime": {
+			resolver:    resolver,
+			runtime:     "abap",
+			expectedErr: true,
+		},
+		"error on failed lookup": {
+			resolver:    resolver,
+			runtime:     "broken",
+			expectedErr: true,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			sanitizer, err := ResolveDependencySanitizer(context.TODO(), testData.resolver, testData.runtime)
+
+			if testData.expectedErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(sanitizer).To(gomega.Equal(testData.expectedSanitizer))
+		})
+	}
+}
+
+func TestDependencySanitizer_ValidateDependencies(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(DependencySanitizerJSONObject.ValidateDependencies(`{"dependencies": {}}`)).To(gomega.Succeed())
+	g.Expect(DependencySanitizerJSONObject.ValidateDependencies("")).To(gomega.Succeed())
+	g.Expect(DependencySanitizerJSONObject.ValidateDependencies("requests==2.25.1")).NotTo(gomega.Succeed())
+	g.Expect(DependencySanitizerNone.ValidateDependencies("requests==2.25.1")).To(gomega.Succeed())
+}